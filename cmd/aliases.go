@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"devkit/internal/utils"
+)
+
+// aliases maps alias names to the arguments they stand for, as defined
+// under aliases in .devkit.yaml:
+//
+//	aliases:
+//	  jd: dev jwt decode --output json
+var aliases = map[string][]string{}
+
+// loadAliases reads the aliases from the config and adds a command for each
+// of them, so that they are listed in help and offered by completion. The
+// commands only document the alias: expandAlias rewrites the arguments
+// before cobra sees them.
+func loadAliases() {
+	defined := viper.GetStringMapString("aliases")
+	names := make([]string, 0, len(defined))
+	for name := range defined {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		expansion := defined[name]
+		if strings.ContainsAny(name, " \t") || strings.HasPrefix(name, "-") {
+			fmt.Fprintf(os.Stderr, "Warning: ignoring alias %q: invalid name\n", name)
+			continue
+		}
+		if existing, _, err := rootCmd.Find([]string{name}); err == nil && existing != rootCmd {
+			fmt.Fprintf(os.Stderr, "Warning: ignoring alias %q: a command with that name exists\n", name)
+			continue
+		}
+		args, err := utils.SplitArgs(expansion)
+		if err != nil || len(args) == 0 {
+			fmt.Fprintf(os.Stderr, "Warning: ignoring alias %q: invalid expansion %q\n", name, expansion)
+			continue
+		}
+		if target, _, err := rootCmd.Find(args); err != nil || target == rootCmd {
+			fmt.Fprintf(os.Stderr, "Warning: ignoring alias %q: %q is not a devkit command\n", name, expansion)
+			continue
+		}
+
+		aliases[name] = args
+		rootCmd.AddCommand(&cobra.Command{
+			Use:                name,
+			Short:              "Alias for: " + expansion,
+			DisableFlagParsing: true,
+			Run:                func(cmd *cobra.Command, args []string) {},
+		})
+	}
+}
+
+// expandAlias replaces an alias in the command position of args with its
+// expansion. The command position follows the global flags, and "help" and
+// the hidden completion commands, so that devkit help jd and shell
+// completion resolve to the aliased command.
+func expandAlias(args []string) []string {
+	i := commandIndex(args, 0)
+	if i < len(args) && (args[i] == "help" || strings.HasPrefix(args[i], cobra.ShellCompRequestCmd)) {
+		i = commandIndex(args, i+1)
+	}
+	if i >= len(args) {
+		return args
+	}
+	expansion, ok := aliases[args[i]]
+	if !ok {
+		return args
+	}
+
+	expanded := append([]string{}, args[:i]...)
+	expanded = append(expanded, expansion...)
+	return append(expanded, args[i+1:]...)
+}
+
+// commandIndex returns the index of the first argument at or after start
+// that is not a global flag or a global flag's value
+func commandIndex(args []string, start int) int {
+	for i := start; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" || !strings.HasPrefix(arg, "-") {
+			return i
+		}
+		if strings.Contains(arg, "=") {
+			continue
+		}
+
+		var name string
+		if strings.HasPrefix(arg, "--") {
+			name = arg[2:]
+		} else if len(arg) == 2 {
+			if f := rootCmd.PersistentFlags().ShorthandLookup(arg[1:]); f != nil {
+				name = f.Name
+			}
+		}
+		if f := rootCmd.PersistentFlags().Lookup(name); f != nil && f.Value.Type() != "bool" {
+			i++
+		}
+	}
+	return len(args)
+}
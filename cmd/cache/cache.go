@@ -0,0 +1,33 @@
+package cache
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// cacheCmd represents the cache command group
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the results cache",
+	Long: `Manage the results cache in ~/.devkit/cache.
+
+The cache is opt-in: set cache.enabled to true in the config file to keep
+file hashes (file dedupe), DNS answers (net dns lookup, 5 minutes) and whois
+responses (net whois, 24 hours) between runs. File hashes are keyed by path,
+size and modification time, so changed files are always hashed again.
+
+Pass --no-cache to any command to bypass the cache for a single run.
+
+Examples:
+  devkit cache clear
+  devkit cache clear dns whois`,
+}
+
+// GetCacheCmd returns the cache command
+func GetCacheCmd() *cobra.Command {
+	return cacheCmd
+}
+
+func init() {
+	// This will be called when the package is imported
+	// Commands will be added in their respective files
+}
@@ -0,0 +1,64 @@
+package cache
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"devkit/internal/cache"
+	"devkit/internal/output"
+)
+
+// clearCmd represents the clear subcommand
+var clearCmd = &cobra.Command{
+	Use:   "clear [namespace...]",
+	Short: "Remove cached results",
+	Long: `Remove cached results. Without arguments the whole cache is removed;
+otherwise only the given namespaces (file-hash, dns, whois).
+
+Examples:
+  devkit cache clear
+  devkit cache clear file-hash
+  devkit cache clear dns whois --output json`,
+	RunE: runClear,
+}
+
+func init() {
+	cacheCmd.AddCommand(clearCmd)
+
+	clearCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
+}
+
+func runClear(cmd *cobra.Command, args []string) error {
+	outputFormat, _ := cmd.Flags().GetString("output")
+	format := output.OutputFormat(outputFormat)
+
+	count, size, err := cache.Clear(args...)
+	if err != nil {
+		return err
+	}
+
+	if format == output.FormatJSON {
+		output.PrintSuccess(format, map[string]interface{}{
+			"namespaces": args,
+			"entries":    count,
+			"bytes":      size,
+		})
+	} else {
+		fmt.Printf("Removed %d cached entries (%s)\n", count, formatSize(size))
+	}
+
+	return nil
+}
+
+func formatSize(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
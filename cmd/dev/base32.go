@@ -0,0 +1,149 @@
+package dev
+
+import (
+	"encoding/base32"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"devkit/internal/output"
+)
+
+// base32Cmd represents the base32 command group
+var base32Cmd = &cobra.Command{
+	Use:   "base32",
+	Short: "Base32 encode/decode operations (use 'base32 encode' or 'base32 decode')",
+	Long: `Encode or decode base32 (RFC 4648), e.g. TOTP secrets.
+
+Subcommands:
+  encode    Encode input to base32
+  decode    Decode base32 string
+
+Examples:
+  devkit dev base32 encode "hello world"
+  devkit dev base32 decode "NBSWY3DPEB3W64TMMQ======"
+  devkit dev base32 decode "jbsw y3dp ehpk 3pxp"
+  devkit dev base32 encode --file ./secret.bin --raw`,
+}
+
+// base32EncodeCmd represents the encode subcommand
+var base32EncodeCmd = &cobra.Command{
+	Use:   "encode [input]",
+	Short: "Encode input to base32",
+	Long: `Encode a string or file to base32.
+
+Examples:
+  devkit dev base32 encode "hello world"
+  devkit dev base32 encode --file ./secret.bin
+  echo "test" | devkit dev base32 encode --stdin --raw --lower
+  devkit dev base32 encode "hello" --hex`,
+	RunE: runBase32Encode,
+}
+
+// base32DecodeCmd represents the decode subcommand
+var base32DecodeCmd = &cobra.Command{
+	Use:   "decode [input]",
+	Short: "Decode base32 string",
+	Long: `Decode a base32 string.
+
+Lowercase input, missing padding, whitespace and dashes (as in grouped TOTP
+secrets) are accepted.
+
+Examples:
+  devkit dev base32 decode "NBSWY3DPEB3W64TMMQ======"
+  devkit dev base32 decode "jbsw y3dp ehpk 3pxp" --hexdump
+  devkit dev base32 decode --file secret.txt --out secret.bin
+  echo "NBSWY3DP" | devkit dev base32 decode --stdin`,
+	RunE: runBase32Decode,
+}
+
+func init() {
+	devCmd.AddCommand(base32Cmd)
+	base32Cmd.AddCommand(base32EncodeCmd)
+	base32Cmd.AddCommand(base32DecodeCmd)
+
+	// Flag definitions for encode
+	base32EncodeCmd.Flags().StringP("file", "f", "", "Input file path")
+	base32EncodeCmd.Flags().BoolP("stdin", "s", false, "Read from stdin")
+	base32EncodeCmd.Flags().BoolP("raw", "r", false, "Omit padding")
+	base32EncodeCmd.Flags().Bool("hex", false, "Use the extended hex alphabet (0-9A-V)")
+	base32EncodeCmd.Flags().BoolP("lower", "l", false, "Lowercase output")
+	base32EncodeCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
+
+	// Flag definitions for decode
+	base32DecodeCmd.Flags().StringP("file", "f", "", "Input file path")
+	base32DecodeCmd.Flags().BoolP("stdin", "s", false, "Read from stdin")
+	base32DecodeCmd.Flags().Bool("hex", false, "Decode the extended hex alphabet (0-9A-V)")
+	base32DecodeCmd.Flags().String("out", "", "Write the decoded bytes to a file")
+	base32DecodeCmd.Flags().Bool("hexdump", false, "Show a hex dump of the decoded bytes")
+	base32DecodeCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
+}
+
+func runBase32Encode(cmd *cobra.Command, args []string) error {
+	raw, _ := cmd.Flags().GetBool("raw")
+	useHex, _ := cmd.Flags().GetBool("hex")
+	lower, _ := cmd.Flags().GetBool("lower")
+	outputFormat, _ := cmd.Flags().GetString("output")
+	format := output.OutputFormat(outputFormat)
+
+	input, err := getJSONInput(cmd, args)
+	if err != nil {
+		return err
+	}
+
+	encoding, variant := base32Encoding(useHex, raw)
+	encoded := encoding.EncodeToString([]byte(input))
+	if lower {
+		encoded = strings.ToLower(encoded)
+	}
+
+	if format == output.FormatJSON {
+		output.PrintSuccess(format, map[string]interface{}{
+			"encoded":  encoded,
+			"input":    input,
+			"encoding": variant,
+		})
+	} else {
+		output.PrintSuccess(format, encoded)
+	}
+
+	return nil
+}
+
+func runBase32Decode(cmd *cobra.Command, args []string) error {
+	useHex, _ := cmd.Flags().GetBool("hex")
+	outputFormat, _ := cmd.Flags().GetString("output")
+	format := output.OutputFormat(outputFormat)
+
+	input, err := getJSONInput(cmd, args)
+	if err != nil {
+		return err
+	}
+
+	// Normalize grouped or lowercase secrets
+	input = strings.ToUpper(strings.Join(strings.Fields(input), ""))
+	input = strings.ReplaceAll(input, "-", "")
+
+	raw := !strings.HasSuffix(input, "=") && len(input)%8 != 0
+	encoding, variant := base32Encoding(useHex, raw)
+
+	decoded, err := encoding.DecodeString(input)
+	if err != nil {
+		return fmt.Errorf("invalid base32 string (%s): %w", variant, err)
+	}
+
+	return printDecoded(cmd, format, input, variant, decoded)
+}
+
+// base32Encoding returns the encoding for the given alphabet and padding
+// together with its name: std, hex, raw-std or raw-hex
+func base32Encoding(useHex, raw bool) (*base32.Encoding, string) {
+	encoding, variant := base32.StdEncoding, "std"
+	if useHex {
+		encoding, variant = base32.HexEncoding, "hex"
+	}
+	if raw {
+		return encoding.WithPadding(base32.NoPadding), "raw-" + variant
+	}
+	return encoding, variant
+}
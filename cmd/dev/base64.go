@@ -1,10 +1,15 @@
 package dev
 
 import (
+	"bufio"
+	"bytes"
 	"encoding/base64"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"os"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/spf13/cobra"
 	"devkit/internal/output"
@@ -20,11 +25,16 @@ Subcommands:
   encode    Encode input to base64
   decode    Decode base64 string
 
+Use --url for the URL-safe alphabet (- and _ instead of + and /) and --raw
+to omit padding. decode detects the variant automatically when neither flag
+is given.
+
 Examples:
   devkit dev base64 encode "hello world"
   devkit dev base64 decode "aGVsbG8gd29ybGQ="
   devkit dev base64 encode --file ./image.png
-  echo "test" | devkit dev base64 encode --stdin`,
+  echo "test" | devkit dev base64 encode --stdin
+  devkit dev base64 encode '{"alg":"HS256"}' --url --raw`,
 }
 
 // encodeCmd represents the encode subcommand
@@ -33,10 +43,16 @@ var encodeCmd = &cobra.Command{
 	Short: "Encode input to base64",
 	Long: `Encode a string or file to base64.
 
+Files and stdin are encoded as a stream, so large inputs are not loaded
+into memory (except with --output json). Use --wrap 76 for MIME-style
+line breaks.
+
 Examples:
   devkit dev base64 encode "hello world"
   devkit dev base64 encode --file ./image.png
-  echo "test" | devkit dev base64 encode --stdin`,
+  echo "test" | devkit dev base64 encode --stdin
+  devkit dev base64 encode "hello world" --url --raw
+  devkit dev base64 encode --file big.iso --wrap 76 > big.b64`,
 	RunE: runEncode,
 }
 
@@ -46,10 +62,21 @@ var decodeCmd = &cobra.Command{
 	Short: "Decode base64 string",
 	Long: `Decode a base64 string.
 
+Standard, URL-safe, padded and unpadded input is detected automatically;
+--url and --raw force the alphabet or the padding when detection is not
+enough. Whitespace and line breaks are ignored.
+
+Binary data is written unchanged to stdout; use --out to write it to a file
+or --hexdump to preview it.
+
 Examples:
   devkit dev base64 decode "aGVsbG8gd29ybGQ="
+  devkit dev base64 decode "aGVsbG8gd29ybGQ"
   devkit dev base64 decode --file encoded.txt
-  echo "aGVsbG8gd29ybGQ=" | devkit dev base64 decode --stdin`,
+  echo "aGVsbG8gd29ybGQ=" | devkit dev base64 decode --stdin
+  devkit dev base64 decode "eyJhbGciOiJIUzI1NiJ9" --url --raw
+  devkit dev base64 decode --file image.b64 --out image.png
+  devkit dev base64 decode --file blob.b64 --hexdump`,
 	RunE: runDecode,
 }
 
@@ -61,11 +88,20 @@ func init() {
 	// Flag definitions for encode
 	encodeCmd.Flags().StringP("file", "f", "", "Input file path")
 	encodeCmd.Flags().BoolP("stdin", "s", false, "Read from stdin")
+	encodeCmd.Flags().BoolP("url", "u", false, "Use the URL-safe alphabet")
+	encodeCmd.Flags().BoolP("raw", "r", false, "Omit padding")
+	encodeCmd.Flags().Bool("no-padding", false, "Omit padding (same as --raw)")
+	encodeCmd.Flags().IntP("wrap", "w", 0, "Wrap lines after this many characters (76 for MIME, 0 = no wrapping)")
 	encodeCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json, table")
 
 	// Flag definitions for decode
 	decodeCmd.Flags().StringP("file", "f", "", "Input file path")
 	decodeCmd.Flags().BoolP("stdin", "s", false, "Read from stdin")
+	decodeCmd.Flags().BoolP("url", "u", false, "Decode with the URL-safe alphabet")
+	decodeCmd.Flags().BoolP("raw", "r", false, "Decode unpadded input")
+	decodeCmd.Flags().Bool("no-padding", false, "Decode unpadded input (same as --raw)")
+	decodeCmd.Flags().String("out", "", "Write the decoded bytes to a file")
+	decodeCmd.Flags().Bool("hexdump", false, "Show a hex dump of the decoded bytes")
 	decodeCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json, table")
 }
 
@@ -73,54 +109,124 @@ func runEncode(cmd *cobra.Command, args []string) error {
 	// Get input
 	fileFlag, _ := cmd.Flags().GetString("file")
 	stdinFlag, _ := cmd.Flags().GetBool("stdin")
+	wrap, _ := cmd.Flags().GetInt("wrap")
 	outputFormat, _ := cmd.Flags().GetString("output")
 	format := output.OutputFormat(outputFormat)
 
-	var input []byte
-	var err error
+	if wrap < 0 {
+		return fmt.Errorf("--wrap must not be negative")
+	}
+	useURL, raw := base64Flags(cmd)
+	encoding, variant := base64Encoding(useURL, raw)
 
+	var reader io.Reader
 	if stdinFlag {
 		stat, err := os.Stdin.Stat()
 		if err != nil {
 			return fmt.Errorf("stdin error: %w", err)
 		}
-		if (stat.Mode() & os.ModeCharDevice) == 0 {
-			input, err = io.ReadAll(os.Stdin)
-			if err != nil {
-				return fmt.Errorf("read stdin error: %w", err)
-			}
-		} else {
+		if (stat.Mode() & os.ModeCharDevice) != 0 {
 			return fmt.Errorf("no data available from stdin")
 		}
+		reader = os.Stdin
 	} else if fileFlag != "" {
-		input, err = os.ReadFile(fileFlag)
+		file, err := os.Open(fileFlag)
 		if err != nil {
 			return fmt.Errorf("read file error: %w", err)
 		}
+		defer file.Close()
+		reader = file
 	} else if len(args) > 0 {
-		input = []byte(args[0])
+		reader = strings.NewReader(args[0])
 	} else {
 		return fmt.Errorf("input not specified (use --file, --stdin, or provide as argument)")
 	}
 
-	// Encode to base64
-	encoded := base64.StdEncoding.EncodeToString(input)
+	// Plain output is streamed so that large files are never held in memory
+	if format != output.FormatJSON {
+		return streamBase64(encoding, reader, wrap)
+	}
 
-	// Prepare result based on format
-	if format == output.FormatJSON {
-		result := map[string]interface{}{
-			"encoded": encoded,
-			"input":   string(input),
-		}
-		output.PrintSuccess(format, result)
-	} else {
-		// Plain format - just print the encoded string
-		output.PrintSuccess(format, encoded)
+	input, err := io.ReadAll(reader)
+	if err != nil {
+		return fmt.Errorf("read input error: %w", err)
+	}
+	var encoded strings.Builder
+	if err := encodeBase64(encoding, &encoded, bytes.NewReader(input), wrap); err != nil {
+		return err
+	}
+
+	result := map[string]interface{}{
+		"encoded":  encoded.String(),
+		"input":    string(input),
+		"encoding": variant,
 	}
+	if wrap > 0 {
+		result["wrap"] = wrap
+	}
+	output.PrintSuccess(format, result)
+
+	return nil
+}
+
+// streamBase64 encodes r to stdout, ending the output with a newline
+func streamBase64(encoding *base64.Encoding, r io.Reader, wrap int) error {
+	out := bufio.NewWriter(os.Stdout)
+	if err := encodeBase64(encoding, out, r, wrap); err != nil {
+		return err
+	}
+	out.WriteByte('\n')
+	return out.Flush()
+}
 
+// encodeBase64 copies r to w through a base64 encoder, breaking lines every
+// wrap characters (0 = no wrapping)
+func encodeBase64(encoding *base64.Encoding, w io.Writer, r io.Reader, wrap int) error {
+	if wrap > 0 {
+		w = &lineWrapper{w: w, width: wrap}
+	}
+	encoder := base64.NewEncoder(encoding, w)
+	if _, err := io.Copy(encoder, r); err != nil {
+		return fmt.Errorf("read input error: %w", err)
+	}
+	// Close flushes the final partial block and its padding
+	if err := encoder.Close(); err != nil {
+		return fmt.Errorf("write error: %w", err)
+	}
 	return nil
 }
 
+// lineWrapper inserts a newline every width bytes written through it
+type lineWrapper struct {
+	w     io.Writer
+	width int
+	col   int
+}
+
+func (l *lineWrapper) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		if l.col == l.width {
+			if _, err := l.w.Write([]byte{'\n'}); err != nil {
+				return written, err
+			}
+			l.col = 0
+		}
+		chunk := l.width - l.col
+		if chunk > len(p) {
+			chunk = len(p)
+		}
+		n, err := l.w.Write(p[:chunk])
+		written += n
+		l.col += n
+		if err != nil {
+			return written, err
+		}
+		p = p[chunk:]
+	}
+	return written, nil
+}
+
 func runDecode(cmd *cobra.Command, args []string) error {
 	// Get input
 	fileFlag, _ := cmd.Flags().GetString("file")
@@ -157,19 +263,57 @@ func runDecode(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("input not specified (use --file, --stdin, or provide as argument)")
 	}
 
-	// Decode from base64
-	decoded, err := base64.StdEncoding.DecodeString(input)
+	// Decode from base64, ignoring whitespace and line breaks
+	input = strings.Join(strings.Fields(input), "")
+	useURL, raw := base64Flags(cmd)
+	detectedURL, detectedRaw := detectBase64Variant(input)
+	encoding, variant := base64Encoding(useURL || detectedURL, raw || detectedRaw)
+
+	decoded, err := encoding.DecodeString(input)
 	if err != nil {
-		return fmt.Errorf("invalid base64 string: %w", err)
+		return fmt.Errorf("invalid base64 string (%s): %w", variant, err)
+	}
+
+	return printDecoded(cmd, format, input, variant, decoded)
+}
+
+// printDecoded prints the result of a decode subcommand, honouring --out and
+// --hexdump. Binary data is written unchanged in plain mode and as a hex dump
+// in JSON mode.
+func printDecoded(cmd *cobra.Command, format output.OutputFormat, input, variant string, decoded []byte) error {
+	outFile, _ := cmd.Flags().GetString("out")
+	hexdump, _ := cmd.Flags().GetBool("hexdump")
+	binary := !utf8.Valid(decoded)
+
+	if outFile != "" {
+		if err := os.WriteFile(outFile, decoded, 0644); err != nil {
+			return fmt.Errorf("failed to write file %s: %w", outFile, err)
+		}
 	}
 
 	// Prepare result based on format
 	if format == output.FormatJSON {
 		result := map[string]interface{}{
-			"decoded": string(decoded),
-			"input":   input,
+			"input":    input,
+			"encoding": variant,
+			"bytes":    len(decoded),
+			"binary":   binary,
+		}
+		if outFile != "" {
+			result["file"] = outFile
+		} else if hexdump || binary {
+			result["hexdump"] = hex.Dump(decoded)
+		} else {
+			result["decoded"] = string(decoded)
 		}
 		output.PrintSuccess(format, result)
+	} else if outFile != "" {
+		output.PrintSuccess(format, fmt.Sprintf("Wrote %d bytes to %s", len(decoded), outFile))
+	} else if hexdump {
+		fmt.Print(hex.Dump(decoded))
+	} else if binary {
+		// Write binary data unchanged so that it can be redirected to a file
+		os.Stdout.Write(decoded)
 	} else {
 		// Plain format - just print the decoded string
 		output.PrintSuccess(format, string(decoded))
@@ -177,3 +321,33 @@ func runDecode(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+func base64Flags(cmd *cobra.Command) (useURL, raw bool) {
+	useURL, _ = cmd.Flags().GetBool("url")
+	raw, _ = cmd.Flags().GetBool("raw")
+	noPadding, _ := cmd.Flags().GetBool("no-padding")
+	return useURL, raw || noPadding
+}
+
+// base64Encoding returns the encoding for the given alphabet and padding
+// together with its name: std, url, raw-std or raw-url
+func base64Encoding(useURL, raw bool) (*base64.Encoding, string) {
+	switch {
+	case useURL && raw:
+		return base64.RawURLEncoding, "raw-url"
+	case useURL:
+		return base64.URLEncoding, "url"
+	case raw:
+		return base64.RawStdEncoding, "raw-std"
+	default:
+		return base64.StdEncoding, "std"
+	}
+}
+
+// detectBase64Variant guesses the alphabet from the characters used and the
+// padding from the input length
+func detectBase64Variant(input string) (useURL, raw bool) {
+	useURL = strings.ContainsAny(input, "-_")
+	raw = !strings.HasSuffix(input, "=") && len(input)%4 != 0
+	return useURL, raw
+}
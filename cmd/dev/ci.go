@@ -0,0 +1,338 @@
+package dev
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"devkit/internal/output"
+	"devkit/internal/sshexec"
+)
+
+// ciCmd represents the ci command group
+var ciCmd = &cobra.Command{
+	Use:   "ci",
+	Short: "CI environment helpers",
+	Long: `Helpers for build scripts that run on several CI providers.
+
+Examples:
+  devkit dev ci info
+  eval "$(devkit dev ci info --output env)"`,
+}
+
+// ciInfoCmd represents the info subcommand
+var ciInfoCmd = &cobra.Command{
+	Use:   "info",
+	Short: "Detect the CI provider and show normalized build metadata",
+	Long: `Detect the CI provider from its environment variables and show the build
+metadata under the same names on every provider: branch, tag, commit, pull
+request number, run URL and so on.
+
+Providers: GitHub Actions, GitLab CI, Jenkins, CircleCI. Any other system
+that sets CI=true is reported as generic. Outside CI, the provider is local
+and the branch and commit come from the Git clone in the current directory.
+
+--output env prints shell exports (CI_PROVIDER, CI_BRANCH, CI_COMMIT,
+CI_PR_NUMBER, ...) for eval; unknown values are exported empty. --prefix
+changes the CI_ prefix.
+
+Examples:
+  devkit dev ci info
+  devkit dev ci info --output json
+  eval "$(devkit dev ci info --output env)"
+  eval "$(devkit dev ci info --output env --prefix BUILD_)"`,
+	Args: cobra.NoArgs,
+	RunE: runCIInfo,
+}
+
+func init() {
+	devCmd.AddCommand(ciCmd)
+	ciCmd.AddCommand(ciInfoCmd)
+
+	ciInfoCmd.Flags().String("prefix", "CI_", "Variable name prefix for --output env")
+	ciInfoCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json, env")
+}
+
+// ciInfo is the normalized metadata of a CI build
+type ciInfo struct {
+	Provider   string `json:"provider"`
+	Name       string `json:"name"`
+	CI         bool   `json:"ci"`
+	Repo       string `json:"repo,omitempty"`
+	Branch     string `json:"branch,omitempty"`
+	Tag        string `json:"tag,omitempty"`
+	Commit     string `json:"commit,omitempty"`
+	PRNumber   int    `json:"pr_number,omitempty"`
+	BaseBranch string `json:"base_branch,omitempty"`
+	Event      string `json:"event,omitempty"`
+	RunID      string `json:"run_id,omitempty"`
+	RunNumber  string `json:"run_number,omitempty"`
+	RunURL     string `json:"run_url,omitempty"`
+	Job        string `json:"job,omitempty"`
+	Actor      string `json:"actor,omitempty"`
+}
+
+// ciDetectors are tried in order; the first that recognizes the
+// environment wins
+var ciDetectors = []func() *ciInfo{
+	detectGitHubActions,
+	detectGitLabCI,
+	detectJenkins,
+	detectCircleCI,
+}
+
+// ciPRNumber matches the pull request number in refs and URLs such as
+// refs/pull/12/merge and https://github.com/o/r/pull/12
+var ciPRNumber = regexp.MustCompile(`/pulls?/(\d+)`)
+
+func runCIInfo(cmd *cobra.Command, args []string) error {
+	prefix, _ := cmd.Flags().GetString("prefix")
+	outputFormat, _ := cmd.Flags().GetString("output")
+	format := output.OutputFormat(outputFormat)
+
+	if format != output.FormatPlain && format != output.FormatJSON && outputFormat != "env" {
+		return fmt.Errorf("invalid output format: %s (supported: plain, json, env)", outputFormat)
+	}
+	if !regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`).MatchString(prefix + "X") {
+		return fmt.Errorf("invalid prefix: %s (letters, digits and underscores)", prefix)
+	}
+
+	info := detectCI()
+
+	if format == output.FormatJSON {
+		output.PrintSuccess(format, info)
+		return nil
+	}
+
+	fields := info.fields()
+	if outputFormat == "env" {
+		for _, f := range fields {
+			fmt.Printf("export %s%s=%s\n", prefix, strings.ToUpper(f[0]), sshexec.Quote(f[2]))
+		}
+		return nil
+	}
+
+	fmt.Printf("%-13s %s\n", "Provider:", output.Accent(info.Name))
+	for _, f := range fields[1:] {
+		if f[2] != "" {
+			fmt.Printf("%-13s %s\n", f[1]+":", f[2])
+		}
+	}
+	return nil
+}
+
+// fields returns the metadata as name, label and value in a stable order;
+// the names are used for the env exports
+func (info *ciInfo) fields() [][3]string {
+	pr := ""
+	if info.PRNumber > 0 {
+		pr = strconv.Itoa(info.PRNumber)
+	}
+	return [][3]string{
+		{"provider", "Provider", info.Provider},
+		{"repo", "Repository", info.Repo},
+		{"branch", "Branch", info.Branch},
+		{"tag", "Tag", info.Tag},
+		{"commit", "Commit", info.Commit},
+		{"pr_number", "Pull request", pr},
+		{"base_branch", "Base branch", info.BaseBranch},
+		{"event", "Event", info.Event},
+		{"run_id", "Run ID", info.RunID},
+		{"run_number", "Run number", info.RunNumber},
+		{"run_url", "Run URL", info.RunURL},
+		{"job", "Job", info.Job},
+		{"actor", "Actor", info.Actor},
+	}
+}
+
+// detectCI returns the metadata of the current CI build, falling back to
+// the local clone for unknown providers and outside CI
+func detectCI() *ciInfo {
+	for _, detect := range ciDetectors {
+		if info := detect(); info != nil {
+			info.CI = true
+			return info
+		}
+	}
+
+	info := &ciInfo{Provider: "local", Name: "Local"}
+	if ciEnvTrue("CI") {
+		info = &ciInfo{Provider: "generic", Name: "Generic CI", CI: true}
+	}
+	info.Commit = ciGit("rev-parse", "HEAD")
+	if branch := ciGit("rev-parse", "--abbrev-ref", "HEAD"); branch != "HEAD" {
+		info.Branch = branch
+	}
+	info.Tag = ciGit("describe", "--tags", "--exact-match")
+	return info
+}
+
+func detectGitHubActions() *ciInfo {
+	if !ciEnvTrue("GITHUB_ACTIONS") {
+		return nil
+	}
+	info := &ciInfo{
+		Provider:  "github",
+		Name:      "GitHub Actions",
+		Repo:      os.Getenv("GITHUB_REPOSITORY"),
+		Commit:    os.Getenv("GITHUB_SHA"),
+		Event:     os.Getenv("GITHUB_EVENT_NAME"),
+		RunID:     os.Getenv("GITHUB_RUN_ID"),
+		RunNumber: os.Getenv("GITHUB_RUN_NUMBER"),
+		Job:       os.Getenv("GITHUB_JOB"),
+		Actor:     os.Getenv("GITHUB_ACTOR"),
+	}
+	if os.Getenv("GITHUB_REF_TYPE") == "tag" {
+		info.Tag = os.Getenv("GITHUB_REF_NAME")
+	} else {
+		info.Branch = os.Getenv("GITHUB_REF_NAME")
+	}
+	if server := os.Getenv("GITHUB_SERVER_URL"); server != "" && info.Repo != "" && info.RunID != "" {
+		info.RunURL = fmt.Sprintf("%s/%s/actions/runs/%s", server, info.Repo, info.RunID)
+	}
+
+	// Pull request builds check out a merge commit on refs/pull/N/merge;
+	// the head branch and commit are what a script usually wants
+	if head := os.Getenv("GITHUB_HEAD_REF"); head != "" {
+		info.Branch = head
+		info.BaseBranch = os.Getenv("GITHUB_BASE_REF")
+		info.PRNumber = ciParsePRNumber(os.Getenv("GITHUB_REF"))
+		var event struct {
+			Number      int `json:"number"`
+			PullRequest struct {
+				Head struct {
+					SHA string `json:"sha"`
+				} `json:"head"`
+			} `json:"pull_request"`
+		}
+		if data, err := os.ReadFile(os.Getenv("GITHUB_EVENT_PATH")); err == nil && json.Unmarshal(data, &event) == nil {
+			if event.PullRequest.Head.SHA != "" {
+				info.Commit = event.PullRequest.Head.SHA
+			}
+			if event.Number > 0 {
+				info.PRNumber = event.Number
+			}
+		}
+	}
+	return info
+}
+
+func detectGitLabCI() *ciInfo {
+	if !ciEnvTrue("GITLAB_CI") {
+		return nil
+	}
+	info := &ciInfo{
+		Provider:  "gitlab",
+		Name:      "GitLab CI",
+		Repo:      os.Getenv("CI_PROJECT_PATH"),
+		Branch:    os.Getenv("CI_COMMIT_BRANCH"),
+		Tag:       os.Getenv("CI_COMMIT_TAG"),
+		Commit:    os.Getenv("CI_COMMIT_SHA"),
+		Event:     os.Getenv("CI_PIPELINE_SOURCE"),
+		RunID:     os.Getenv("CI_PIPELINE_ID"),
+		RunNumber: os.Getenv("CI_PIPELINE_IID"),
+		RunURL:    os.Getenv("CI_PIPELINE_URL"),
+		Job:       os.Getenv("CI_JOB_NAME"),
+		Actor:     os.Getenv("GITLAB_USER_LOGIN"),
+	}
+	if iid, err := strconv.Atoi(os.Getenv("CI_MERGE_REQUEST_IID")); err == nil {
+		info.PRNumber = iid
+		info.Branch = os.Getenv("CI_MERGE_REQUEST_SOURCE_BRANCH_NAME")
+		info.BaseBranch = os.Getenv("CI_MERGE_REQUEST_TARGET_BRANCH_NAME")
+	}
+	return info
+}
+
+func detectJenkins() *ciInfo {
+	if os.Getenv("JENKINS_URL") == "" {
+		return nil
+	}
+	info := &ciInfo{
+		Provider:  "jenkins",
+		Name:      "Jenkins",
+		Repo:      os.Getenv("GIT_URL"),
+		Tag:       os.Getenv("TAG_NAME"),
+		Commit:    os.Getenv("GIT_COMMIT"),
+		RunID:     os.Getenv("BUILD_TAG"),
+		RunNumber: os.Getenv("BUILD_NUMBER"),
+		RunURL:    os.Getenv("BUILD_URL"),
+		Job:       os.Getenv("JOB_NAME"),
+	}
+	// Multibranch pipelines set BRANCH_NAME (PR-12 for pull requests);
+	// freestyle jobs with the Git plugin set GIT_BRANCH as origin/main
+	info.Branch = os.Getenv("BRANCH_NAME")
+	if info.Branch == "" {
+		info.Branch = strings.TrimPrefix(os.Getenv("GIT_BRANCH"), "origin/")
+	}
+	if id, err := strconv.Atoi(os.Getenv("CHANGE_ID")); err == nil {
+		info.PRNumber = id
+		info.BaseBranch = os.Getenv("CHANGE_TARGET")
+		info.Actor = os.Getenv("CHANGE_AUTHOR")
+		if branch := os.Getenv("CHANGE_BRANCH"); branch != "" {
+			info.Branch = branch
+		}
+	}
+	if info.Tag != "" && info.Branch == info.Tag {
+		info.Branch = ""
+	}
+	return info
+}
+
+func detectCircleCI() *ciInfo {
+	if !ciEnvTrue("CIRCLECI") {
+		return nil
+	}
+	info := &ciInfo{
+		Provider:  "circleci",
+		Name:      "CircleCI",
+		Branch:    os.Getenv("CIRCLE_BRANCH"),
+		Tag:       os.Getenv("CIRCLE_TAG"),
+		Commit:    os.Getenv("CIRCLE_SHA1"),
+		RunID:     os.Getenv("CIRCLE_WORKFLOW_ID"),
+		RunNumber: os.Getenv("CIRCLE_BUILD_NUM"),
+		RunURL:    os.Getenv("CIRCLE_BUILD_URL"),
+		Job:       os.Getenv("CIRCLE_JOB"),
+		Actor:     os.Getenv("CIRCLE_USERNAME"),
+	}
+	if owner, name := os.Getenv("CIRCLE_PROJECT_USERNAME"), os.Getenv("CIRCLE_PROJECT_REPONAME"); owner != "" && name != "" {
+		info.Repo = owner + "/" + name
+	}
+	// CIRCLE_PR_NUMBER is only set for pull requests from forks
+	if n, err := strconv.Atoi(os.Getenv("CIRCLE_PR_NUMBER")); err == nil {
+		info.PRNumber = n
+	} else {
+		info.PRNumber = ciParsePRNumber(os.Getenv("CIRCLE_PULL_REQUEST"))
+	}
+	return info
+}
+
+// ciEnvTrue reports whether an environment variable is set to true
+func ciEnvTrue(name string) bool {
+	v, err := strconv.ParseBool(os.Getenv(name))
+	return err == nil && v
+}
+
+// ciParsePRNumber extracts the pull request number from a ref or URL
+func ciParsePRNumber(s string) int {
+	m := ciPRNumber.FindStringSubmatch(s)
+	if m == nil {
+		return 0
+	}
+	n, _ := strconv.Atoi(m[1])
+	return n
+}
+
+// ciGit runs a git command in the current directory and returns its
+// trimmed output, or "" when it fails
+func ciGit(args ...string) string {
+	out, err := exec.Command("git", args...).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
@@ -0,0 +1,398 @@
+package dev
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronMonthNames and cronDayNames are indexed by the values of the month
+// (1-12) and day-of-week (0-6, Sunday first) fields
+var (
+	cronMonthNames = []string{"", "January", "February", "March", "April", "May", "June",
+		"July", "August", "September", "October", "November", "December"}
+	cronDayNames = []string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"}
+)
+
+// cronItem is one comma-separated part of a cron field: a single value, a
+// range or a stepped range. all marks * and ?.
+type cronItem struct {
+	start, end, step int
+	all              bool
+}
+
+func (it cronItem) single() bool {
+	return !it.all && it.start == it.end
+}
+
+// cronField is a parsed cron field
+type cronField []cronItem
+
+// every reports whether the field is * (or ?), matching every value
+func (f cronField) every() bool {
+	return len(f) == 1 && f[0].all && f[0].step == 1
+}
+
+// values returns the values of a field made only of single values, sorted
+func (f cronField) values() ([]int, bool) {
+	values := make([]int, 0, len(f))
+	for _, it := range f {
+		if !it.single() {
+			return nil, false
+		}
+		values = append(values, it.start)
+	}
+	sort.Ints(values)
+	return values, true
+}
+
+// expand lists every value the field matches
+func (f cronField) expand() []int {
+	seen := map[int]bool{}
+	var values []int
+	for _, it := range f {
+		for v := it.start; v <= it.end; v += it.step {
+			if !seen[v] {
+				seen[v] = true
+				values = append(values, v)
+			}
+		}
+	}
+	sort.Ints(values)
+	return values
+}
+
+// parseCronField parses a field that the cron parser already accepted;
+// names are the accepted value names (JAN, MON), matched on their first
+// three letters
+func parseCronField(field string, min, max int, names []string) (cronField, error) {
+	value := func(s string) (int, error) {
+		if n, err := strconv.Atoi(s); err == nil {
+			return n, nil
+		}
+		for i, name := range names {
+			if name != "" && strings.EqualFold(s, name[:3]) {
+				return i, nil
+			}
+		}
+		return 0, fmt.Errorf("invalid value: %s", s)
+	}
+
+	var f cronField
+	for _, part := range strings.Split(field, ",") {
+		it := cronItem{step: 1}
+		rangePart, step, hasStep := strings.Cut(part, "/")
+		if hasStep {
+			n, err := strconv.Atoi(step)
+			if err != nil {
+				return nil, err
+			}
+			it.step = n
+		}
+		if rangePart == "*" || rangePart == "?" {
+			it.all, it.start, it.end = true, min, max
+		} else {
+			low, high, isRange := strings.Cut(rangePart, "-")
+			var err error
+			if it.start, err = value(low); err != nil {
+				return nil, err
+			}
+			switch {
+			case isRange:
+				if it.end, err = value(high); err != nil {
+					return nil, err
+				}
+			case hasStep:
+				// N/step means N-max/step, and 0/15 is */15
+				it.end = max
+				it.all = it.start == min
+			default:
+				it.end = it.start
+			}
+		}
+		f = append(f, it)
+	}
+	return f, nil
+}
+
+// describeCron turns the fields of a cron expression, five or six with
+// seconds, into a sentence such as "At 09:00 on Monday through Friday"
+func describeCron(fields []string) (string, error) {
+	second := cronField{{start: 0, end: 0, step: 1}}
+	if len(fields) == 6 {
+		var err error
+		if second, err = parseCronField(fields[0], 0, 59, nil); err != nil {
+			return "", err
+		}
+		fields = fields[1:]
+	}
+	if len(fields) != 5 {
+		return "", fmt.Errorf("expected 5 fields, or 6 with seconds, got %d", len(fields))
+	}
+	minute, err := parseCronField(fields[0], 0, 59, nil)
+	if err != nil {
+		return "", err
+	}
+	hour, err := parseCronField(fields[1], 0, 23, nil)
+	if err != nil {
+		return "", err
+	}
+	dom, err := parseCronField(fields[2], 1, 31, nil)
+	if err != nil {
+		return "", err
+	}
+	month, err := parseCronField(fields[3], 1, 12, cronMonthNames)
+	if err != nil {
+		return "", err
+	}
+	dow, err := parseCronField(fields[4], 0, 6, cronDayNames)
+	if err != nil {
+		return "", err
+	}
+
+	var sentence string
+	if seconds, ok := second.values(); ok && len(seconds) == 1 && seconds[0] == 0 {
+		sentence = describeCronTime(minute, hour)
+	} else {
+		sentence = describeCronSeconds(second, minute, hour)
+	}
+	switch {
+	case !dom.every() && !dow.every():
+		// Both day fields set: cron fires when either of them matches
+		sentence += " on " + describeCronDom(dom) + " or on " + describeCronDow(dow)
+	case !dom.every():
+		sentence += " on " + describeCronDom(dom)
+	case !dow.every():
+		sentence += " on " + describeCronDow(dow)
+	}
+	if !month.every() {
+		sentence += ", " + describeCronMonth(month)
+	}
+	return sentence, nil
+}
+
+// describeCronTime describes the minute and hour fields
+func describeCronTime(minute, hour cronField) string {
+	minutes, plainMinutes := minute.values()
+	hours, plainHours := hour.values()
+
+	// A few fixed times of day are listed as clock times
+	if plainMinutes && plainHours && len(minutes)*len(hours) <= 8 {
+		var times []string
+		for _, h := range hours {
+			for _, m := range minutes {
+				times = append(times, fmt.Sprintf("%02d:%02d", h, m))
+			}
+		}
+		return "At " + joinCronList(times)
+	}
+
+	var sentence string
+	switch {
+	case plainMinutes && len(minutes) == 1 && minutes[0] == 0 && len(hour) == 1 && !hour[0].single() && !hour.every():
+		// 0 9-17 * * * and 0 */2 * * *
+		it := hour[0]
+		if it.step == 1 {
+			return fmt.Sprintf("Every hour from %02d:00 through %02d:00", it.start, it.end)
+		}
+		sentence = fmt.Sprintf("Every %d hours", it.step)
+		if !it.all {
+			sentence += fmt.Sprintf(" from %02d:00 through %02d:00", it.start, it.end)
+		}
+		return sentence
+	case plainMinutes && len(minutes) == 1 && minutes[0] == 0:
+		sentence = "Every hour"
+	case plainMinutes:
+		unit := "minutes"
+		if len(minutes) == 1 && minutes[0] == 1 {
+			unit = "minute"
+		}
+		sentence = fmt.Sprintf("At %s %s past the hour", joinCronList(cronStrings(minutes, strconv.Itoa)), unit)
+	case minute.every():
+		sentence = "Every minute"
+	case len(minute) == 1 && minute[0].all:
+		sentence = fmt.Sprintf("Every %d minutes", minute[0].step)
+	case len(minute) == 1 && minute[0].step > 1:
+		sentence = fmt.Sprintf("Every %d minutes, minutes %d through %d past the hour", minute[0].step, minute[0].start, minute[0].end)
+	default:
+		sentence = "At minutes " + describeCronItems(minute, strconv.Itoa, "minutes") + " past the hour"
+	}
+
+	return sentence + describeCronHours(hour)
+}
+
+// describeCronHours describes the hour field as a suffix such as
+// ", between 09:00 and 17:59", empty for *
+func describeCronHours(hour cronField) string {
+	if hour.every() {
+		return ""
+	}
+	if len(hour) == 1 {
+		it := hour[0]
+		switch {
+		case it.step == 1:
+			return fmt.Sprintf(", between %02d:00 and %02d:59", it.start, it.end)
+		case it.all:
+			return fmt.Sprintf(", every %d hours", it.step)
+		default:
+			return fmt.Sprintf(", every %d hours from %02d:00 through %02d:00", it.step, it.start, it.end)
+		}
+	}
+	return ", during hours " + describeCronItems(hour, strconv.Itoa, "hours")
+}
+
+// describeCronSeconds describes the time fields of an expression whose
+// seconds field is not just 0
+func describeCronSeconds(second, minute, hour cronField) string {
+	seconds, plainSeconds := second.values()
+	minutes, plainMinutes := minute.values()
+	hours, plainHours := hour.values()
+
+	if plainSeconds && plainMinutes && plainHours && len(seconds)*len(minutes)*len(hours) <= 8 {
+		var times []string
+		for _, h := range hours {
+			for _, m := range minutes {
+				for _, sec := range seconds {
+					times = append(times, fmt.Sprintf("%02d:%02d:%02d", h, m, sec))
+				}
+			}
+		}
+		return "At " + joinCronList(times)
+	}
+
+	var sentence string
+	switch {
+	case plainSeconds:
+		unit := "seconds"
+		if len(seconds) == 1 && seconds[0] == 1 {
+			unit = "second"
+		}
+		sentence = fmt.Sprintf("At %s %s past the minute", joinCronList(cronStrings(seconds, strconv.Itoa)), unit)
+	case second.every():
+		sentence = "Every second"
+	case len(second) == 1 && second[0].all:
+		sentence = fmt.Sprintf("Every %d seconds", second[0].step)
+	default:
+		sentence = "At seconds " + describeCronItems(second, strconv.Itoa, "seconds") + " past the minute"
+	}
+
+	if minute.every() {
+		return sentence + describeCronHours(hour)
+	}
+	minutePart := describeCronTime(minute, hour)
+	return sentence + ", " + strings.ToLower(minutePart[:1]) + minutePart[1:]
+}
+
+// describeCronDuration formats the interval of an @every schedule
+func describeCronDuration(d time.Duration) string {
+	for _, unit := range []struct {
+		size time.Duration
+		name string
+	}{{time.Hour, "hour"}, {time.Minute, "minute"}, {time.Second, "second"}} {
+		if d%unit.size == 0 {
+			n := int(d / unit.size)
+			if n == 1 {
+				return unit.name
+			}
+			return fmt.Sprintf("%d %ss", n, unit.name)
+		}
+	}
+	return d.String()
+}
+
+// describeCronDom describes the day-of-month field
+func describeCronDom(dom cronField) string {
+	if days, ok := dom.values(); ok {
+		if len(days) == 1 {
+			return fmt.Sprintf("day %d of the month", days[0])
+		}
+		return "days " + joinCronList(cronStrings(days, strconv.Itoa)) + " of the month"
+	}
+	if len(dom) == 1 && dom[0].step > 1 {
+		it := dom[0]
+		if it.all {
+			return fmt.Sprintf("every %s day of the month", cronOrdinal(it.step))
+		}
+		return fmt.Sprintf("every %s day from day %d through %d of the month", cronOrdinal(it.step), it.start, it.end)
+	}
+	return "days " + describeCronItems(dom, strconv.Itoa, "days") + " of the month"
+}
+
+// describeCronDow describes the day-of-week field; steps are spelled out
+// since there are only seven days
+func describeCronDow(dow cronField) string {
+	name := func(v int) string { return cronDayNames[v] }
+	for _, it := range dow {
+		if it.step > 1 {
+			return joinCronList(cronStrings(dow.expand(), name))
+		}
+	}
+	return describeCronItems(dow, name, "days")
+}
+
+// describeCronMonth describes the month field
+func describeCronMonth(month cronField) string {
+	name := func(v int) string { return cronMonthNames[v] }
+	if len(month) == 1 && month[0].all && month[0].step > 1 {
+		return fmt.Sprintf("every %d months", month[0].step)
+	}
+	for _, it := range month {
+		if it.step > 1 {
+			return "in " + joinCronList(cronStrings(month.expand(), name))
+		}
+	}
+	if len(month) == 1 && month[0].single() {
+		return "only in " + name(month[0].start)
+	}
+	return "in " + describeCronItems(month, name, "months")
+}
+
+// describeCronItems lists the items of a field: values, "A through B"
+// ranges and "every N unit from A through B" steps
+func describeCronItems(f cronField, name func(int) string, unit string) string {
+	parts := make([]string, 0, len(f))
+	for _, it := range f {
+		switch {
+		case it.single():
+			parts = append(parts, name(it.start))
+		case it.step == 1:
+			parts = append(parts, name(it.start)+" through "+name(it.end))
+		default:
+			parts = append(parts, fmt.Sprintf("every %d %s from %s through %s", it.step, unit, name(it.start), name(it.end)))
+		}
+	}
+	return joinCronList(parts)
+}
+
+// joinCronList joins items as "a", "a and b" or "a, b and c"
+func joinCronList(items []string) string {
+	if len(items) <= 1 {
+		return strings.Join(items, "")
+	}
+	return strings.Join(items[:len(items)-1], ", ") + " and " + items[len(items)-1]
+}
+
+func cronStrings(values []int, name func(int) string) []string {
+	out := make([]string, len(values))
+	for i, v := range values {
+		out[i] = name(v)
+	}
+	return out
+}
+
+// cronOrdinal formats 2 as 2nd, 3 as 3rd and so on
+func cronOrdinal(n int) string {
+	suffix := "th"
+	switch {
+	case n%100 >= 11 && n%100 <= 13:
+	case n%10 == 1:
+		suffix = "st"
+	case n%10 == 2:
+		suffix = "nd"
+	case n%10 == 3:
+		suffix = "rd"
+	}
+	return strconv.Itoa(n) + suffix
+}
@@ -0,0 +1,622 @@
+package dev
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// cronNumberWords are the spelled-out step counts understood by cron make
+var cronNumberWords = map[string]int{
+	"one": 1, "two": 2, "three": 3, "four": 4, "five": 5, "six": 6, "seven": 7,
+	"eight": 8, "nine": 9, "ten": 10, "eleven": 11, "twelve": 12, "fifteen": 15,
+	"twenty": 20, "thirty": 30,
+}
+
+// cronUnits maps unit words, singular and plural, to the unit they stand for
+var cronUnits = map[string]string{
+	"second": "second", "seconds": "second", "sec": "second", "secs": "second",
+	"minute": "minute", "minutes": "minute", "min": "minute", "mins": "minute",
+	"hour": "hour", "hours": "hour", "hr": "hour", "hrs": "hour",
+	"day": "day", "days": "day",
+	"week": "week", "weeks": "week",
+	"month": "month", "months": "month",
+	"year": "year", "years": "year",
+}
+
+// cronUnitAdverbs are the single words that name a repeating unit
+var cronUnitAdverbs = map[string]string{
+	"secondly": "second", "minutely": "minute", "hourly": "hour", "daily": "day",
+	"nightly": "day", "weekly": "week", "monthly": "month", "yearly": "year", "annually": "year",
+}
+
+// cronFillers are words that carry no meaning for the schedule
+var cronFillers = map[string]bool{
+	"on": true, "the": true, "of": true, "in": true, "and": true, "a": true, "an": true,
+	"run": true, "runs": true, "once": true, "o'clock": true, "oclock": true, "please": true,
+}
+
+// cronClock is a time of day
+type cronClock struct {
+	hour, minute int
+}
+
+// cronPhrase collects what a natural-language schedule says
+type cronPhrase struct {
+	unit      string
+	step      int
+	times     []cronClock
+	minuteAt  int
+	hourFrom  int
+	hourTo    int
+	dows      []string
+	doms      []string
+	months    []string
+	tokens    []string
+	pos       int
+	hasWindow bool
+}
+
+// makeCron turns an English phrase such as "every weekday at 9:30" into cron
+// fields; six fields (seconds first) when the phrase needs seconds or
+// withSeconds is set
+func makeCron(phrase string, withSeconds bool) ([]string, error) {
+	normalized := strings.ToLower(phrase)
+	for _, sep := range []string{",", ";", "&", "+"} {
+		normalized = strings.ReplaceAll(normalized, sep, " ")
+	}
+	p := &cronPhrase{tokens: strings.Fields(normalized), minuteAt: -1, hourFrom: -1, hourTo: -1}
+	if len(p.tokens) == 0 {
+		return nil, fmt.Errorf("empty phrase")
+	}
+	if err := p.parse(); err != nil {
+		return nil, err
+	}
+	return p.fields(withSeconds)
+}
+
+func (p *cronPhrase) peek(offset int) string {
+	if p.pos+offset < len(p.tokens) {
+		return p.tokens[p.pos+offset]
+	}
+	return ""
+}
+
+func (p *cronPhrase) parse() error {
+	for p.pos < len(p.tokens) {
+		token := p.peek(0)
+		switch {
+		case token == "every" || token == "each":
+			p.pos++
+			if err := p.parseEvery(); err != nil {
+				return err
+			}
+		case cronUnitAdverbs[token] != "":
+			if err := p.setUnit(cronUnitAdverbs[token], 1); err != nil {
+				return err
+			}
+			p.pos++
+		case token == "at":
+			p.pos++
+			if err := p.parseAt(); err != nil {
+				return err
+			}
+		case token == "from" || token == "between":
+			p.pos++
+			if err := p.parseWindow(); err != nil {
+				return err
+			}
+		case cronDow(token) != "" || cronDowRange(token) != "" || strings.HasPrefix(token, "weekday") || strings.HasPrefix(token, "weekend"):
+			p.parseDows()
+		case cronMonth(token) > 0:
+			p.parseMonths()
+		case cronDayOfMonth(token) > 0:
+			p.parseDoms()
+		case token == "day" && cronDayOfMonth(p.peek(1)) > 0:
+			p.pos++
+			p.parseDoms()
+		case isCronTime(token, false):
+			if err := p.parseTimes(); err != nil {
+				return err
+			}
+		case token == "noon" || token == "midnight":
+			if err := p.parseTimes(); err != nil {
+				return err
+			}
+		case cronUnits[token] != "" && p.pos > 0 && (p.tokens[p.pos-1] == "a" || p.tokens[p.pos-1] == "an" || p.tokens[p.pos-1] == "per"):
+			// once a day, once per hour
+			if err := p.setUnit(cronUnits[token], 1); err != nil {
+				return err
+			}
+			p.pos++
+		case cronFillers[token] || token == "per":
+			p.pos++
+		default:
+			return fmt.Errorf("don't understand %q", token)
+		}
+	}
+	return nil
+}
+
+func (p *cronPhrase) setUnit(unit string, step int) error {
+	if p.unit != "" && (p.unit != unit || p.step != step) {
+		return fmt.Errorf("the phrase repeats both every %s and every %s", p.unit, unit)
+	}
+	p.unit, p.step = unit, step
+	return nil
+}
+
+// parseEvery handles what follows "every": a count and unit, "other", a
+// unit, or day and month names
+func (p *cronPhrase) parseEvery() error {
+	token := p.peek(0)
+	step := 0
+	if n, err := strconv.Atoi(token); err == nil {
+		step = n
+	} else if n, ok := cronNumberWords[token]; ok {
+		step = n
+	} else if token == "other" {
+		step = 2
+	}
+	if step > 0 {
+		unit, ok := cronUnits[p.peek(1)]
+		if !ok {
+			return fmt.Errorf("expected a unit such as minutes or hours after \"every %s\"", token)
+		}
+		p.pos += 2
+		return p.setUnit(unit, step)
+	}
+	if unit, ok := cronUnits[token]; ok {
+		p.pos++
+		return p.setUnit(unit, 1)
+	}
+	// every monday, every weekday, every january: handled by the main loop
+	if cronDow(token) != "" || cronMonth(token) > 0 || strings.HasPrefix(token, "weekday") || strings.HasPrefix(token, "weekend") {
+		return nil
+	}
+	if token == "" {
+		return fmt.Errorf("expected something after \"every\"")
+	}
+	return fmt.Errorf("don't understand \"every %s\"", token)
+}
+
+// parseAt handles "at 9:30", "at 9 and 17", "at noon", "at minute 15" and
+// "at :15"
+func (p *cronPhrase) parseAt() error {
+	token := p.peek(0)
+	if token == "minute" {
+		n, err := strconv.Atoi(p.peek(1))
+		if err != nil || n < 0 || n > 59 {
+			return fmt.Errorf("expected a minute 0-59 after \"at minute\"")
+		}
+		p.minuteAt, p.pos = n, p.pos+2
+		return nil
+	}
+	if strings.HasPrefix(token, ":") {
+		n, err := strconv.Atoi(token[1:])
+		if err != nil || n < 0 || n > 59 {
+			return fmt.Errorf("invalid minute: %s", token)
+		}
+		p.minuteAt, p.pos = n, p.pos+1
+		return nil
+	}
+	if !isCronTime(token, true) {
+		return fmt.Errorf("expected a time after \"at\", e.g. 9:30, 9am or noon")
+	}
+	return p.parseTimes()
+}
+
+// parseTimes reads a list of times such as "9am and 5pm" or "9 13 17"
+func (p *cronPhrase) parseTimes() error {
+	for {
+		clock, err := p.parseClock()
+		if err != nil {
+			return err
+		}
+		p.times = append(p.times, clock)
+		if p.peek(0) == "and" && isCronTime(p.peek(1), true) {
+			p.pos++
+		} else if !isCronTime(p.peek(0), true) {
+			return nil
+		}
+	}
+}
+
+// parseClock reads one time: 9, 9:30, 09:30, 9am, 9:30pm, 9 pm, noon, midnight
+func (p *cronPhrase) parseClock() (cronClock, error) {
+	token := p.peek(0)
+	p.pos++
+	switch token {
+	case "noon", "midday":
+		return cronClock{12, 0}, nil
+	case "midnight":
+		return cronClock{0, 0}, nil
+	}
+
+	suffix := ""
+	for _, s := range []string{"am", "pm", "a.m.", "p.m."} {
+		if strings.HasSuffix(token, s) {
+			token, suffix = strings.TrimSuffix(token, s), s[:1]
+			break
+		}
+	}
+	if suffix == "" {
+		switch p.peek(0) {
+		case "am", "a.m.":
+			suffix, p.pos = "a", p.pos+1
+		case "pm", "p.m.":
+			suffix, p.pos = "p", p.pos+1
+		}
+	}
+
+	hourText, minuteText, hasMinute := strings.Cut(strings.ReplaceAll(token, ".", ":"), ":")
+	hour, err := strconv.Atoi(hourText)
+	if err != nil {
+		return cronClock{}, fmt.Errorf("invalid time: %s", token)
+	}
+	minute := 0
+	if hasMinute {
+		if minute, err = strconv.Atoi(minuteText); err != nil || len(minuteText) != 2 || minute > 59 {
+			return cronClock{}, fmt.Errorf("invalid time: %s", token)
+		}
+	}
+	switch suffix {
+	case "a", "p":
+		if hour < 1 || hour > 12 {
+			return cronClock{}, fmt.Errorf("invalid time: %s%sm", token, suffix)
+		}
+		hour %= 12
+		if suffix == "p" {
+			hour += 12
+		}
+	default:
+		if hour > 23 || hour < 0 {
+			return cronClock{}, fmt.Errorf("invalid time: %s", token)
+		}
+	}
+	return cronClock{hour, minute}, nil
+}
+
+// isCronTime reports whether token reads as a time of day; bare numbers
+// count only where a time is expected, e.g. after "at"
+func isCronTime(token string, bare bool) bool {
+	if token == "noon" || token == "midnight" || token == "midday" {
+		return true
+	}
+	t := token
+	for _, s := range []string{"am", "pm", "a.m.", "p.m."} {
+		if strings.HasSuffix(t, s) && len(t) > len(s) {
+			t = strings.TrimSuffix(t, s)
+			bare = true
+			break
+		}
+	}
+	hour, minute, hasMinute := strings.Cut(t, ":")
+	if _, err := strconv.Atoi(hour); err != nil || hour == "" {
+		return false
+	}
+	if hasMinute {
+		_, err := strconv.Atoi(minute)
+		return err == nil && len(minute) == 2
+	}
+	return bare
+}
+
+// parseWindow handles "from 9 to 17", "between 9am and 5pm" and
+// "from monday to friday"
+func (p *cronPhrase) parseWindow() error {
+	if cronDow(p.peek(0)) != "" {
+		first := cronDow(p.peek(0))
+		if !isCronRangeWord(p.peek(1)) || cronDow(p.peek(2)) == "" {
+			return fmt.Errorf("expected \"from <day> to <day>\"")
+		}
+		p.dows = append(p.dows, first+"-"+cronDow(p.peek(2)))
+		p.pos += 3
+		return nil
+	}
+	if !isCronTime(p.peek(0), true) {
+		return fmt.Errorf("expected a time or day after %q", p.tokens[p.pos-1])
+	}
+	from, err := p.parseClock()
+	if err != nil {
+		return err
+	}
+	if !isCronRangeWord(p.peek(0)) && p.peek(0) != "and" {
+		return fmt.Errorf("expected \"to\" or \"and\" to end the time window")
+	}
+	p.pos++
+	if !isCronTime(p.peek(0), true) {
+		return fmt.Errorf("expected a time to end the window")
+	}
+	to, err := p.parseClock()
+	if err != nil {
+		return err
+	}
+	if from.minute != 0 || to.minute != 0 {
+		return fmt.Errorf("time windows must start and end on the hour")
+	}
+	if to.hour < from.hour {
+		return fmt.Errorf("time windows cannot cross midnight")
+	}
+	p.hourFrom, p.hourTo, p.hasWindow = from.hour, to.hour, true
+	return nil
+}
+
+func isCronRangeWord(token string) bool {
+	switch token {
+	case "to", "through", "thru", "until", "till", "-":
+		return true
+	}
+	return false
+}
+
+// parseDows reads day names, ranges and weekday/weekend
+func (p *cronPhrase) parseDows() {
+	for {
+		token := p.peek(0)
+		switch {
+		case token == "weekday" || token == "weekdays":
+			p.dows = append(p.dows, "1-5")
+			p.pos++
+		case token == "weekend" || token == "weekends":
+			p.dows = append(p.dows, "0,6")
+			p.pos++
+		case cronDow(token) != "":
+			p.pos++
+			if isCronRangeWord(p.peek(0)) && cronDow(p.peek(1)) != "" {
+				p.dows = append(p.dows, cronDow(token)+"-"+cronDow(p.peek(1)))
+				p.pos += 2
+			} else {
+				p.dows = append(p.dows, cronDow(token))
+			}
+		case cronDowRange(token) != "":
+			p.dows = append(p.dows, cronDowRange(token))
+			p.pos++
+		default:
+			return
+		}
+		if p.peek(0) == "and" || p.peek(0) == "or" {
+			if next := p.peek(1); cronDow(next) == "" && !strings.HasPrefix(next, "week") {
+				return
+			}
+			p.pos++
+		}
+	}
+}
+
+// parseMonths reads month names, each optionally followed by a day, as in
+// "january 1st" or "jan 15"
+func (p *cronPhrase) parseMonths() {
+	for cronMonth(p.peek(0)) > 0 {
+		month := cronMonth(p.peek(0))
+		p.pos++
+		if isCronRangeWord(p.peek(0)) && cronMonth(p.peek(1)) > 0 {
+			p.months = append(p.months, fmt.Sprintf("%d-%d", month, cronMonth(p.peek(1))))
+			p.pos += 2
+		} else {
+			p.months = append(p.months, strconv.Itoa(month))
+		}
+		if day := cronDayOfMonth(p.peek(0)); day > 0 {
+			p.doms = append(p.doms, strconv.Itoa(day))
+			p.pos++
+		} else if n, err := strconv.Atoi(p.peek(0)); err == nil && n >= 1 && n <= 31 && !isCronTime(p.peek(1), false) {
+			p.doms = append(p.doms, strconv.Itoa(n))
+			p.pos++
+		}
+		if p.peek(0) == "and" && cronMonth(p.peek(1)) > 0 {
+			p.pos++
+		}
+	}
+}
+
+// parseDoms reads days of the month: 1st, 15th, "1 and 15" after "day"
+func (p *cronPhrase) parseDoms() {
+	for {
+		day := cronDayOfMonth(p.peek(0))
+		if day == 0 {
+			return
+		}
+		p.doms = append(p.doms, strconv.Itoa(day))
+		p.pos++
+		if p.peek(0) == "and" && cronDayOfMonth(p.peek(1)) > 0 {
+			p.pos++
+		}
+	}
+}
+
+// cronDow returns the day-of-week value of a day name such as mon, tuesday
+// or fridays, or ""
+func cronDow(token string) string {
+	if len(token) < 3 {
+		return ""
+	}
+	token = strings.TrimSuffix(token, ".")
+	for i, name := range cronDayNames {
+		name = strings.ToLower(name)
+		if token == name || token == name+"s" || strings.HasPrefix(name, token) {
+			return strconv.Itoa(i)
+		}
+	}
+	return ""
+}
+
+// cronDowRange parses a range written as one token, e.g. mon-fri
+func cronDowRange(token string) string {
+	from, to, _ := strings.Cut(token, "-")
+	if cronDow(from) == "" || cronDow(to) == "" {
+		return ""
+	}
+	return cronDow(from) + "-" + cronDow(to)
+}
+
+// cronMonth returns the number of a month name such as jan or january, or 0
+func cronMonth(token string) int {
+	token = strings.TrimSuffix(token, ".")
+	if len(token) < 3 {
+		return 0
+	}
+	for i, name := range cronMonthNames[1:] {
+		name = strings.ToLower(name)
+		if token == name || (len(token) == 3 && strings.HasPrefix(name, token)) {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// cronDayOfMonth returns the day of an ordinal such as 1st or 15th, or 0
+func cronDayOfMonth(token string) int {
+	for _, suffix := range []string{"st", "nd", "rd", "th"} {
+		if strings.HasSuffix(token, suffix) {
+			if n, err := strconv.Atoi(strings.TrimSuffix(token, suffix)); err == nil && n >= 1 && n <= 31 {
+				return n
+			}
+		}
+	}
+	return 0
+}
+
+// fields assembles the cron fields from what the phrase said
+func (p *cronPhrase) fields(withSeconds bool) ([]string, error) {
+	second, minute, hour := "0", "", ""
+	dom, month, dow := "*", "*", "*"
+	stepped := func(all string, step int) string {
+		if step > 1 {
+			return "*/" + strconv.Itoa(step)
+		}
+		return all
+	}
+
+	switch p.unit {
+	case "second":
+		if p.step >= 60 {
+			return nil, fmt.Errorf("every %d seconds cannot be written as cron; use \"@every %ds\"", p.step, p.step)
+		}
+		second, minute, hour = stepped("*", p.step), "*", "*"
+		withSeconds = true
+	case "minute":
+		if p.step >= 60 {
+			return nil, fmt.Errorf("every %d minutes cannot be written as cron; use \"@every %dm\"", p.step, p.step)
+		}
+		minute, hour = stepped("*", p.step), "*"
+	case "hour":
+		if p.step >= 24 {
+			return nil, fmt.Errorf("every %d hours cannot be written as cron; use \"@every %dh\"", p.step, p.step)
+		}
+		hour = stepped("*", p.step)
+	case "day":
+		if p.step > 31 {
+			return nil, fmt.Errorf("every %d days cannot be written as cron", p.step)
+		}
+		dom = stepped("*", p.step)
+	case "week":
+		if p.step > 1 {
+			return nil, fmt.Errorf("every %d weeks cannot be written as cron, which has no week numbers", p.step)
+		}
+		if len(p.dows) == 0 {
+			dow = "0"
+		}
+	case "month":
+		if p.step > 12 {
+			return nil, fmt.Errorf("every %d months cannot be written as cron", p.step)
+		}
+		month = stepped("*", p.step)
+		if len(p.doms) == 0 {
+			dom = "1"
+		}
+	case "year":
+		if p.step > 1 {
+			return nil, fmt.Errorf("every %d years cannot be written as cron, which has no year field", p.step)
+		}
+		if len(p.doms) == 0 {
+			dom = "1"
+		}
+		if len(p.months) == 0 {
+			month = "1"
+		}
+	}
+
+	if p.hasWindow {
+		switch p.unit {
+		case "second", "minute":
+			hour = fmt.Sprintf("%d-%d", p.hourFrom, p.hourTo)
+		case "hour":
+			hour = fmt.Sprintf("%d-%d", p.hourFrom, p.hourTo)
+			if p.step > 1 {
+				hour += "/" + strconv.Itoa(p.step)
+			}
+		default:
+			if len(p.times) > 0 {
+				return nil, fmt.Errorf("give either times (at ...) or a window (from ... to ...), not both")
+			}
+			// "between 9 and 17" alone means hourly within the window
+			hour = fmt.Sprintf("%d-%d", p.hourFrom, p.hourTo)
+		}
+	}
+
+	if len(p.times) > 0 {
+		if p.unit == "second" || p.unit == "minute" || p.unit == "hour" {
+			return nil, fmt.Errorf("every %s cannot run at a fixed time; use from/between for a window, e.g. \"every 15 minutes from 9 to 17\"", p.unit)
+		}
+		hours, minutes := map[int]bool{}, map[int]bool{}
+		for _, t := range p.times {
+			hours[t.hour], minutes[t.minute] = true, true
+		}
+		if len(hours) > 1 && len(minutes) > 1 {
+			return nil, fmt.Errorf("times with different hours and minutes cannot be combined in one cron expression")
+		}
+		hour, minute = joinCronSet(hours), joinCronSet(minutes)
+	}
+	if p.minuteAt >= 0 {
+		if minute != "" && minute != "0" && minute != strconv.Itoa(p.minuteAt) {
+			return nil, fmt.Errorf("the phrase gives the minute twice")
+		}
+		minute = strconv.Itoa(p.minuteAt)
+		if hour == "" {
+			hour = "*"
+		}
+	}
+
+	if p.unit == "" && len(p.times) == 0 && !p.hasWindow && p.minuteAt < 0 &&
+		len(p.dows) == 0 && len(p.doms) == 0 && len(p.months) == 0 {
+		return nil, fmt.Errorf("no schedule found; try e.g. \"every weekday at 9:30\" or \"every 15 minutes\"")
+	}
+	if minute == "" {
+		minute = "0"
+	}
+	if hour == "" {
+		hour = "0"
+	}
+	if len(p.dows) > 0 {
+		dow = strings.Join(p.dows, ",")
+	}
+	if len(p.doms) > 0 {
+		if p.unit == "day" && p.step > 1 {
+			return nil, fmt.Errorf("give either days of the month or every N days, not both")
+		}
+		dom = strings.Join(p.doms, ",")
+	}
+	if len(p.months) > 0 {
+		if p.unit == "month" && p.step > 1 {
+			return nil, fmt.Errorf("give either months or every N months, not both")
+		}
+		month = strings.Join(p.months, ",")
+	}
+
+	fields := []string{minute, hour, dom, month, dow}
+	if withSeconds {
+		fields = append([]string{second}, fields...)
+	}
+	return fields, nil
+}
+
+// joinCronSet lists the values of a set in ascending order
+func joinCronSet(set map[int]bool) string {
+	var values []string
+	for v := 0; v < 60; v++ {
+		if set[v] {
+			values = append(values, strconv.Itoa(v))
+		}
+	}
+	return strings.Join(values, ",")
+}
@@ -2,6 +2,7 @@ package dev
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -16,20 +17,35 @@ var cronCmd = &cobra.Command{
 	Short: "Cron expression operations",
 	Long: `Parse and explain cron expressions.
 
+Expressions have five fields (minute hour day-of-month month day-of-week),
+or six with a leading seconds field as used by Quartz and Spring; the
+format is detected from the number of fields, and --with-seconds requires
+six. The macros @yearly (@annually), @monthly, @weekly, @daily (@midnight),
+@hourly and @every <duration>, e.g. @every 5m, are accepted too.
+
+Schedules run in the local time zone unless --tz or a CRON_TZ= prefix, as
+in crontab, names another; times are shown in that zone and in UTC.
+
 Examples:
   devkit dev cron explain "0 9 * * 1-5"
-  devkit dev cron next "0 9 * * 1-5" --count 5`,
+  devkit dev cron next "0 9 * * 1-5" --count 5
+  devkit dev cron prev "0 9 * * 1-5" --count 5
+  devkit dev cron next "0 9 * * 1-5" --tz Europe/Istanbul`,
 }
 
 // cronExplainCmd represents the explain subcommand
 var cronExplainCmd = &cobra.Command{
 	Use:   "explain [expression]",
 	Short: "Explain a cron expression",
-	Long: `Explain what a cron expression means in human-readable format.
+	Long: `Explain what a cron expression means in plain English, e.g. "0 9 * * 1-5"
+as "At 09:00 on Monday through Friday".
 
 Examples:
   devkit dev cron explain "0 9 * * 1-5"
-  devkit dev cron explain "*/5 * * * *"`,
+  devkit dev cron explain "*/5 * * * *"
+  devkit dev cron explain "*/30 * * * * *" --with-seconds
+  devkit dev cron explain @weekly
+  devkit dev cron explain "CRON_TZ=America/New_York 30 16 * * 1-5"`,
 	RunE: runCronExplain,
 }
 
@@ -41,18 +57,183 @@ var cronNextCmd = &cobra.Command{
 
 Examples:
   devkit dev cron next "0 9 * * 1-5"
-  devkit dev cron next "*/5 * * * *" --count 10`,
+  devkit dev cron next "*/5 * * * *" --count 10
+  devkit dev cron next "0 0/15 9-17 ? * MON-FRI"
+  devkit dev cron next "@every 90s"
+  devkit dev cron next "0 3 * * *" --tz Asia/Tokyo`,
 	RunE: runCronNext,
 }
 
+// cronMakeCmd represents the make subcommand
+var cronMakeCmd = &cobra.Command{
+	Use:   "make [phrase]",
+	Short: "Build a cron expression from an English phrase",
+	Long: `Turn a schedule described in plain English into a cron expression, and
+show its explanation and next runs so the result can be checked.
+
+Understood phrases combine:
+  every minute, every 15 minutes, every 2 hours, every other day,
+  hourly, daily, weekly, monthly, yearly, once a day
+  at 9:30, at 9am and 5pm, at noon, at midnight, at minute 15, at :15
+  on monday, on mon and thu, monday to friday, mon-fri, weekdays, weekends
+  on the 1st and 15th, on day 10, in january, jan 1st
+  from 9 to 17, between 9am and 5pm
+
+Seconds (every 30 seconds) produce a six-field expression, as does
+--with-seconds.
+
+Examples:
+  devkit dev cron make "every weekday at 9:30"
+  devkit dev cron make "every 15 minutes from 9am to 5pm on weekdays"
+  devkit dev cron make "on the 1st and 15th of every month at midnight"
+  devkit dev cron make "every sunday at 3am" --tz Europe/Istanbul --output json`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runCronMake,
+}
+
+// cronPrevCmd represents the prev subcommand
+var cronPrevCmd = &cobra.Command{
+	Use:   "prev [expression]",
+	Short: "Show previous execution times",
+	Long: `Show the times a cron expression last fired, most recent first, before
+now or before --from. Useful to check whether a missed job should have run.
+
+--from takes an RFC 3339 date, a date and time in the schedule's time zone
+(2006-01-02 15:04) or Unix seconds.
+
+Examples:
+  devkit dev cron prev "0 9 * * 1-5"
+  devkit dev cron prev "*/15 * * * *" --count 10
+  devkit dev cron prev "0 2 * * 0" --from "2024-06-01 00:00"
+  devkit dev cron prev "0 2 * * 0" --tz UTC`,
+	RunE: runCronPrev,
+}
+
 func init() {
 	devCmd.AddCommand(cronCmd)
 	cronCmd.AddCommand(cronExplainCmd)
 	cronCmd.AddCommand(cronNextCmd)
+	cronCmd.AddCommand(cronPrevCmd)
+	cronCmd.AddCommand(cronMakeCmd)
+
+	cronCmd.PersistentFlags().Bool("with-seconds", false, "Require six fields, the first being seconds (default: detected from the field count)")
+	cronCmd.PersistentFlags().String("tz", "", "Time zone the schedule runs in, e.g. Europe/Istanbul (default: local)")
 
 	cronExplainCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
 	cronNextCmd.Flags().IntP("count", "c", 5, "Number of next executions to show")
 	cronNextCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
+	cronPrevCmd.Flags().IntP("count", "c", 5, "Number of previous executions to show")
+	cronPrevCmd.Flags().String("from", "", "Look back from this time instead of now")
+	cronPrevCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
+	cronMakeCmd.Flags().IntP("count", "c", 3, "Number of next executions to show")
+	cronMakeCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
+}
+
+// cronMacros maps the predefined schedules to their five-field form
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// parseCron parses a five-field expression, a six-field one with leading
+// seconds, a macro such as @daily or @every <duration>, optionally prefixed
+// with CRON_TZ=<zone>. It returns the schedule, the fields it stands for
+// (none for @every) and the time zone the schedule runs in.
+func parseCron(cmd *cobra.Command, expr string) (cron.Schedule, []string, *time.Location, error) {
+	withSeconds, _ := cmd.Flags().GetBool("with-seconds")
+
+	loc, expr, err := cronLocation(cmd, strings.TrimSpace(expr))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if strings.HasPrefix(expr, "@") {
+		if strings.HasPrefix(expr, "@every ") {
+			schedule, err := cron.ParseStandard(expr)
+			return schedule, nil, loc, err
+		}
+		macro, ok := cronMacros[strings.ToLower(expr)]
+		if !ok {
+			return nil, nil, nil, fmt.Errorf("unknown macro: %s (supported: @yearly, @annually, @monthly, @weekly, @daily, @midnight, @hourly, @every <duration>)", expr)
+		}
+		expr = macro
+		if withSeconds {
+			expr = "0 " + expr
+		}
+	}
+
+	fields := strings.Fields(expr)
+	var parser cron.Parser
+	switch {
+	case withSeconds && len(fields) != 6:
+		return nil, nil, nil, fmt.Errorf("expected 6 fields with --with-seconds (seconds first), got %d", len(fields))
+	case len(fields) == 6:
+		parser = cron.NewParser(cron.Second | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow)
+	case len(fields) == 5:
+		parser = cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow)
+	case len(fields) == 7:
+		return nil, nil, nil, fmt.Errorf("the year field of 7-field Quartz expressions is not supported")
+	default:
+		return nil, nil, nil, fmt.Errorf("expected 5 fields, or 6 with seconds, got %d", len(fields))
+	}
+
+	schedule, err := parser.Parse(strings.Join(fields, " "))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	schedule.(*cron.SpecSchedule).Location = loc
+	return schedule, fields, loc, nil
+}
+
+// cronLocation returns the zone of --tz or of a CRON_TZ=/TZ= prefix, and
+// the expression without the prefix
+func cronLocation(cmd *cobra.Command, expr string) (*time.Location, string, error) {
+	tz, _ := cmd.Flags().GetString("tz")
+
+	for _, prefix := range []string{"CRON_TZ=", "TZ="} {
+		if !strings.HasPrefix(expr, prefix) {
+			continue
+		}
+		zone, rest, _ := strings.Cut(expr[len(prefix):], " ")
+		if tz != "" && tz != zone {
+			return nil, "", fmt.Errorf("--tz %s conflicts with %s%s", tz, prefix, zone)
+		}
+		tz, expr = zone, strings.TrimSpace(rest)
+		break
+	}
+
+	if tz == "" {
+		return time.Local, expr, nil
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return nil, "", fmt.Errorf("unknown time zone: %s (use an IANA name such as Europe/Istanbul)", tz)
+	}
+	return loc, expr, nil
+}
+
+// cronZoneName names loc for output; the local zone by its abbreviation
+func cronZoneName(loc *time.Location) string {
+	if loc == time.Local {
+		name, _ := time.Now().Zone()
+		return name
+	}
+	return loc.String()
+}
+
+// formatCronTimes formats times in loc and in UTC, as RFC 3339
+func formatCronTimes(times []time.Time, loc *time.Location) ([]string, []string) {
+	local := make([]string, 0, len(times))
+	utc := make([]string, 0, len(times))
+	for _, t := range times {
+		local = append(local, t.In(loc).Format(time.RFC3339))
+		utc = append(utc, t.UTC().Format(time.RFC3339))
+	}
+	return local, utc
 }
 
 func runCronExplain(cmd *cobra.Command, args []string) error {
@@ -64,22 +245,26 @@ func runCronExplain(cmd *cobra.Command, args []string) error {
 	}
 
 	expr := args[0]
-	parser := cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow)
-
-	schedule, err := parser.Parse(expr)
+	schedule, fields, loc, err := parseCron(cmd, expr)
 	if err != nil {
 		return fmt.Errorf("invalid cron expression: %w", err)
 	}
 
-	// Parse parts
-	parts := parseCronExpression(expr)
-	explanation := explainCronExpression(parts)
+	var explanation string
+	if every, ok := schedule.(cron.ConstantDelaySchedule); ok {
+		explanation = "Every " + describeCronDuration(every.Delay)
+	} else if explanation, err = describeCron(fields); err != nil {
+		return fmt.Errorf("invalid cron expression: %w", err)
+	}
 
+	nextRun := schedule.Next(time.Now().In(loc))
 	result := map[string]interface{}{
-		"expression":  expr,
-		"explanation": explanation,
-		"valid":       true,
-		"next_run":    schedule.Next(time.Now()).Format(time.RFC3339),
+		"expression":   expr,
+		"explanation":  explanation,
+		"valid":        true,
+		"timezone":     cronZoneName(loc),
+		"next_run":     nextRun.In(loc).Format(time.RFC3339),
+		"next_run_utc": nextRun.UTC().Format(time.RFC3339),
 	}
 
 	if format == output.FormatJSON {
@@ -87,7 +272,8 @@ func runCronExplain(cmd *cobra.Command, args []string) error {
 	} else {
 		fmt.Printf("Expression: %s\n", expr)
 		fmt.Printf("Explanation: %s\n", explanation)
-		fmt.Printf("Next run: %s\n", result["next_run"])
+		fmt.Printf("Time zone: %s\n", cronZoneName(loc))
+		fmt.Printf("Next run: %s  %s\n", result["next_run"], output.Muted(result["next_run_utc"].(string)))
 	}
 
 	return nil
@@ -103,83 +289,232 @@ func runCronNext(cmd *cobra.Command, args []string) error {
 	}
 
 	expr := args[0]
-	parser := cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow)
-
-	schedule, err := parser.Parse(expr)
+	schedule, _, loc, err := parseCron(cmd, expr)
 	if err != nil {
 		return fmt.Errorf("invalid cron expression: %w", err)
 	}
 
-	now := time.Now()
-	nextTimes := make([]string, 0, count)
-	currentTime := now
+	times := make([]time.Time, 0, count)
+	currentTime := time.Now().In(loc)
 
 	for i := 0; i < count; i++ {
 		nextTime := schedule.Next(currentTime)
-		nextTimes = append(nextTimes, nextTime.Format(time.RFC3339))
+		times = append(times, nextTime)
 		currentTime = nextTime
 	}
+	nextTimes, nextTimesUTC := formatCronTimes(times, loc)
 
 	result := map[string]interface{}{
-		"expression": expr,
-		"next_times": nextTimes,
-		"count":      count,
+		"expression":     expr,
+		"timezone":       cronZoneName(loc),
+		"next_times":     nextTimes,
+		"next_times_utc": nextTimesUTC,
+		"count":          count,
 	}
 
 	if format == output.FormatJSON {
 		output.PrintSuccess(format, result)
 	} else {
 		fmt.Printf("Expression: %s\n", expr)
-		fmt.Println("Next execution times:")
+		fmt.Printf("Next execution times (%s):\n", cronZoneName(loc))
 		for i, t := range nextTimes {
-			fmt.Printf("  %d. %s\n", i+1, t)
+			fmt.Printf("  %d. %s  %s\n", i+1, t, output.Muted(nextTimesUTC[i]))
 		}
 	}
 
 	return nil
 }
 
-func parseCronExpression(expr string) []string {
-	parts := make([]string, 5)
-	
-	// Simple parsing - split by space
-	values := strings.Fields(expr)
-	for i := 0; i < 5 && i < len(values); i++ {
-		parts[i] = values[i]
+func runCronPrev(cmd *cobra.Command, args []string) error {
+	count, _ := cmd.Flags().GetInt("count")
+	from, _ := cmd.Flags().GetString("from")
+	outputFormat, _ := cmd.Flags().GetString("output")
+	format := output.OutputFormat(outputFormat)
+
+	if len(args) == 0 {
+		return fmt.Errorf("cron expression not specified")
+	}
+	if count < 1 {
+		return fmt.Errorf("count must be at least 1")
+	}
+	if count > 1000 {
+		return fmt.Errorf("count cannot exceed 1000")
+	}
+
+	expr := args[0]
+	schedule, _, loc, err := parseCron(cmd, expr)
+	if err != nil {
+		return fmt.Errorf("invalid cron expression: %w", err)
+	}
+	spec, ok := schedule.(*cron.SpecSchedule)
+	if !ok {
+		return fmt.Errorf("@every schedules have no fixed times; previous runs depend on when the scheduler started")
 	}
-	
-	return parts
+
+	start := time.Now().In(loc)
+	if from != "" {
+		if start, err = parseCronTime(from, loc); err != nil {
+			return err
+		}
+	}
+
+	times := make([]time.Time, 0, count)
+	currentTime := start
+	for i := 0; i < count; i++ {
+		prevTime := cronPrev(spec, currentTime)
+		if prevTime.IsZero() {
+			break
+		}
+		times = append(times, prevTime)
+		currentTime = prevTime
+	}
+	prevTimes, prevTimesUTC := formatCronTimes(times, loc)
+
+	result := map[string]interface{}{
+		"expression":     expr,
+		"timezone":       cronZoneName(loc),
+		"from":           start.In(loc).Format(time.RFC3339),
+		"prev_times":     prevTimes,
+		"prev_times_utc": prevTimesUTC,
+		"count":          len(prevTimes),
+	}
+
+	if format == output.FormatJSON {
+		output.PrintSuccess(format, result)
+	} else {
+		fmt.Printf("Expression: %s\n", expr)
+		fmt.Printf("Previous execution times before %s (%s):\n", result["from"], cronZoneName(loc))
+		if len(prevTimes) == 0 {
+			fmt.Println(output.Muted("  none in the last 5 years"))
+		}
+		for i, t := range times {
+			fmt.Printf("  %d. %s  %s  %s\n", i+1, prevTimes[i], output.Muted(prevTimesUTC[i]), output.Muted(formatAge(t.UnixMilli())))
+		}
+	}
+
+	return nil
 }
 
-func explainCronExpression(parts []string) string {
-	if len(parts) < 5 {
-		return "Invalid cron expression"
+func runCronMake(cmd *cobra.Command, args []string) error {
+	count, _ := cmd.Flags().GetInt("count")
+	withSeconds, _ := cmd.Flags().GetBool("with-seconds")
+	outputFormat, _ := cmd.Flags().GetString("output")
+	format := output.OutputFormat(outputFormat)
+
+	if count < 0 {
+		return fmt.Errorf("count cannot be negative")
+	}
+	if count > 1000 {
+		return fmt.Errorf("count cannot exceed 1000")
 	}
 
-	explanations := []string{
-		fmt.Sprintf("Minute: %s", explainField(parts[0], 0, 59)),
-		fmt.Sprintf("Hour: %s", explainField(parts[1], 0, 23)),
-		fmt.Sprintf("Day of month: %s", explainField(parts[2], 1, 31)),
-		fmt.Sprintf("Month: %s", explainField(parts[3], 1, 12)),
-		fmt.Sprintf("Day of week: %s", explainField(parts[4], 0, 6)),
+	phrase := strings.Join(args, " ")
+	fields, err := makeCron(phrase, withSeconds)
+	if err != nil {
+		return fmt.Errorf("cannot make a cron expression from %q: %w", phrase, err)
+	}
+	expr := strings.Join(fields, " ")
+	schedule, _, loc, err := parseCron(cmd, expr)
+	if err != nil {
+		return fmt.Errorf("invalid cron expression %s: %w", expr, err)
+	}
+	explanation, err := describeCron(fields)
+	if err != nil {
+		return fmt.Errorf("invalid cron expression %s: %w", expr, err)
 	}
 
-	return strings.Join(explanations, ", ")
+	times := make([]time.Time, 0, count)
+	currentTime := time.Now().In(loc)
+	for i := 0; i < count; i++ {
+		currentTime = schedule.Next(currentTime)
+		times = append(times, currentTime)
+	}
+	nextTimes, nextTimesUTC := formatCronTimes(times, loc)
+
+	result := map[string]interface{}{
+		"phrase":         phrase,
+		"expression":     expr,
+		"explanation":    explanation,
+		"timezone":       cronZoneName(loc),
+		"next_times":     nextTimes,
+		"next_times_utc": nextTimesUTC,
+	}
+
+	if format == output.FormatJSON {
+		output.PrintSuccess(format, result)
+		return nil
+	}
+
+	fmt.Printf("Phrase: %s\n", phrase)
+	fmt.Printf("Expression: %s\n", output.Accent(expr))
+	fmt.Printf("Explanation: %s\n", explanation)
+	if count > 0 {
+		fmt.Printf("Next execution times (%s):\n", cronZoneName(loc))
+		for i, t := range nextTimes {
+			fmt.Printf("  %d. %s  %s\n", i+1, t, output.Muted(nextTimesUTC[i]))
+		}
+	}
+	return nil
 }
 
-func explainField(field string, min, max int) string {
-	if field == "*" {
-		return "every value"
+// parseCronTime parses --from: an RFC 3339 date, a date and time in the
+// schedule's zone loc or Unix seconds
+func parseCronTime(s string, loc *time.Location) (time.Time, error) {
+	if sec, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Unix(sec, 0).In(loc), nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t.In(loc), nil
 	}
-	if strings.HasPrefix(field, "*/") {
-		interval := strings.TrimPrefix(field, "*/")
-		return fmt.Sprintf("every %s", interval)
+	for _, layout := range []string{"2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02T15:04", "2006-01-02 15:04", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return t, nil
+		}
 	}
-	if strings.Contains(field, "-") {
-		return fmt.Sprintf("range %s", field)
+	return time.Time{}, fmt.Errorf("invalid time: %s (use an RFC 3339 date, 2006-01-02 15:04 or Unix seconds)", s)
+}
+
+// cronStarBit marks a day field given as * or ?; robfig/cron keeps it in
+// the top bit of the field's bitmask
+const cronStarBit = 1 << 63
+
+// cronPrev returns the latest activation of schedule strictly before t, or
+// the zero time when there is none in the five years before t. It mirrors
+// SpecSchedule.Next, walking backwards from the largest field down.
+func cronPrev(s *cron.SpecSchedule, t time.Time) time.Time {
+	loc := s.Location
+	if loc == time.Local {
+		loc = t.Location()
 	}
-	if strings.Contains(field, ",") {
-		return fmt.Sprintf("values %s", field)
+	t = t.In(loc).Add(-time.Nanosecond).Truncate(time.Second)
+	limit := t.AddDate(-5, 0, 0)
+
+	for !t.Before(limit) {
+		switch {
+		case 1<<uint(t.Month())&s.Month == 0:
+			t = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, loc).Add(-time.Second)
+		case !cronDayMatches(s, t):
+			t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc).Add(-time.Second)
+		case 1<<uint(t.Hour())&s.Hour == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, loc).Add(-time.Second)
+		case 1<<uint(t.Minute())&s.Minute == 0:
+			t = t.Truncate(time.Minute).Add(-time.Second)
+		case 1<<uint(t.Second())&s.Second == 0:
+			t = t.Add(-time.Second)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// cronDayMatches reports whether t satisfies the day-of-month and
+// day-of-week fields: both when either is *, otherwise either of them
+func cronDayMatches(s *cron.SpecSchedule, t time.Time) bool {
+	domMatch := 1<<uint(t.Day())&s.Dom > 0
+	dowMatch := 1<<uint(t.Weekday())&s.Dow > 0
+	if s.Dom&cronStarBit > 0 || s.Dow&cronStarBit > 0 {
+		return domMatch && dowMatch
 	}
-	return field
+	return domMatch || dowMatch
 }
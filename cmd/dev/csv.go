@@ -0,0 +1,531 @@
+package dev
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/spf13/cobra"
+	"devkit/internal/output"
+)
+
+// csvCmd represents the csv command group
+var csvCmd = &cobra.Command{
+	Use:   "csv",
+	Short: "CSV operations (preview, select, to-json, stats)",
+	Long: `CSV inspection and conversion operations.
+
+All subcommands accept a file path or --stdin, a --delimiter (use "tab"
+for TSV) and --no-header for files without a header row.
+
+Examples:
+  devkit dev csv preview users.csv
+  devkit dev csv select users.csv --columns name,email
+  devkit dev csv to-json users.csv
+  devkit dev csv stats users.csv`,
+}
+
+// csvPreviewCmd represents the preview subcommand
+var csvPreviewCmd = &cobra.Command{
+	Use:   "preview [file]",
+	Short: "Show the first rows as a table",
+	Long: `Show the first N rows of a CSV file as an aligned table. Only those
+rows are read, so large files preview as quickly as small ones.
+
+Examples:
+  devkit dev csv preview users.csv
+  devkit dev csv preview users.csv --rows 50
+  devkit dev csv preview data.tsv --delimiter tab`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runCSVPreview,
+}
+
+// csvSelectCmd represents the select subcommand
+var csvSelectCmd = &cobra.Command{
+	Use:   "select [file]",
+	Short: "Select columns from a CSV file",
+	Long: `Output only the given columns, by header name or 1-based index, in the
+given order.
+
+Examples:
+  devkit dev csv select users.csv --columns name,email
+  devkit dev csv select users.csv --columns 1,3 --no-header
+  cat users.csv | devkit dev csv select --stdin --columns email > emails.csv`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runCSVSelect,
+}
+
+// csvToJSONCmd represents the to-json subcommand
+var csvToJSONCmd = &cobra.Command{
+	Use:   "to-json [file]",
+	Short: "Convert CSV to JSON",
+	Long: `Convert CSV rows to JSON objects keyed by the header row (or arrays with
+--no-header). Use --types to convert numbers and booleans; NaN and
+infinities, which JSON cannot hold, stay strings. A header that names a
+column twice is an error, as one value would overwrite the other.
+
+Examples:
+  devkit dev csv to-json users.csv
+  devkit dev csv to-json users.csv --types
+  devkit dev csv to-json users.csv --ndjson`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runCSVToJSON,
+}
+
+// csvStatsCmd represents the stats subcommand
+var csvStatsCmd = &cobra.Command{
+	Use:   "stats [file]",
+	Short: "Show row, column and empty cell counts",
+	Long: `Show row and column counts and, per column, the number of empty cells,
+distinct values and numeric values.
+
+Examples:
+  devkit dev csv stats users.csv
+  devkit dev csv stats users.csv --output json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runCSVStats,
+}
+
+func init() {
+	devCmd.AddCommand(csvCmd)
+	csvCmd.AddCommand(csvPreviewCmd)
+	csvCmd.AddCommand(csvSelectCmd)
+	csvCmd.AddCommand(csvToJSONCmd)
+	csvCmd.AddCommand(csvStatsCmd)
+
+	for _, c := range []*cobra.Command{csvPreviewCmd, csvSelectCmd, csvToJSONCmd, csvStatsCmd} {
+		c.Flags().BoolP("stdin", "s", false, "Read from stdin")
+		c.Flags().StringP("delimiter", "d", ",", "Field delimiter (use \"tab\" for TSV)")
+		c.Flags().Bool("no-header", false, "The first row is data, not a header")
+		c.Flags().StringP("output", "o", "plain", "Output format: plain, json")
+	}
+
+	csvPreviewCmd.Flags().IntP("rows", "n", 10, "Number of rows to show")
+	csvPreviewCmd.Flags().Int("width", 30, "Maximum column width")
+
+	csvSelectCmd.Flags().StringP("columns", "c", "", "Columns to select, by name or 1-based index (comma-separated) (required)")
+	csvSelectCmd.MarkFlagRequired("columns")
+
+	csvToJSONCmd.Flags().Bool("types", false, "Convert numbers and booleans")
+	csvToJSONCmd.Flags().Bool("ndjson", false, "Write one JSON document per line")
+}
+
+// csvTable is a parsed CSV document
+type csvTable struct {
+	header []string
+	rows   [][]string
+	// more is set when readCSV stopped at its limit before the end
+	more bool
+}
+
+// readCSV reads the CSV input of a csv command. With a limit of 0 or more,
+// only the header and that many rows are read.
+func readCSV(cmd *cobra.Command, args []string, limit int) (*csvTable, rune, error) {
+	stdinFlag, _ := cmd.Flags().GetBool("stdin")
+	delimiter, _ := cmd.Flags().GetString("delimiter")
+	noHeader, _ := cmd.Flags().GetBool("no-header")
+
+	comma, err := parseDelimiter(delimiter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var r io.Reader
+	if stdinFlag {
+		r = os.Stdin
+	} else if len(args) > 0 {
+		file, err := os.Open(args[0])
+		if err != nil {
+			return nil, 0, fmt.Errorf("read file error: %w", err)
+		}
+		defer file.Close()
+		r = file
+	} else {
+		return nil, 0, fmt.Errorf("input not specified")
+	}
+
+	reader := csv.NewReader(r)
+	reader.Comma = comma
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	table := &csvTable{}
+	var records [][]string
+	if limit < 0 {
+		if records, err = reader.ReadAll(); err != nil {
+			return nil, 0, fmt.Errorf("invalid CSV: %w", err)
+		}
+	} else {
+		// One record more than needed tells whether the input goes on
+		want := limit + 1
+		if !noHeader {
+			want++
+		}
+		for len(records) < want {
+			record, err := reader.Read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, 0, fmt.Errorf("invalid CSV: %w", err)
+			}
+			records = append(records, record)
+		}
+		if len(records) == want {
+			records = records[:want-1]
+			table.more = true
+		}
+	}
+
+	if len(records) == 0 {
+		return table, comma, nil
+	}
+
+	width := 0
+	for _, record := range records {
+		if len(record) > width {
+			width = len(record)
+		}
+	}
+
+	if noHeader {
+		for i := 0; i < width; i++ {
+			table.header = append(table.header, fmt.Sprintf("column%d", i+1))
+		}
+		table.rows = records
+	} else {
+		table.header = records[0]
+		for i := len(table.header); i < width; i++ {
+			table.header = append(table.header, fmt.Sprintf("column%d", i+1))
+		}
+		table.rows = records[1:]
+	}
+
+	// Pad ragged rows so every row has a value for every column
+	for i, row := range table.rows {
+		for len(row) < len(table.header) {
+			row = append(row, "")
+		}
+		table.rows[i] = row
+	}
+
+	return table, comma, nil
+}
+
+func parseDelimiter(delimiter string) (rune, error) {
+	switch strings.ToLower(delimiter) {
+	case "tab", "\\t", "\t":
+		return '\t', nil
+	case "comma":
+		return ',', nil
+	case "semicolon":
+		return ';', nil
+	case "pipe":
+		return '|', nil
+	}
+	if utf8.RuneCountInString(delimiter) != 1 {
+		return 0, fmt.Errorf("delimiter must be a single character")
+	}
+	r, _ := utf8.DecodeRuneInString(delimiter)
+	return r, nil
+}
+
+func runCSVPreview(cmd *cobra.Command, args []string) error {
+	rows, _ := cmd.Flags().GetInt("rows")
+	maxWidth, _ := cmd.Flags().GetInt("width")
+	outputFormat, _ := cmd.Flags().GetString("output")
+	format := output.OutputFormat(outputFormat)
+
+	table, _, err := readCSV(cmd, args, max(rows, -1))
+	if err != nil {
+		return err
+	}
+	shown := table.rows
+
+	if format == output.FormatJSON {
+		output.PrintSuccess(format, map[string]interface{}{
+			"header": table.header,
+			"rows":   shown,
+			"count":  len(shown),
+			"more":   table.more,
+		})
+		return nil
+	}
+
+	if maxWidth < 3 {
+		maxWidth = 3
+	}
+	widths := make([]int, len(table.header))
+	for i, name := range table.header {
+		widths[i] = utf8.RuneCountInString(name)
+	}
+	for _, row := range shown {
+		for i, cell := range row {
+			if n := utf8.RuneCountInString(cell); n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+	for i := range widths {
+		if widths[i] > maxWidth {
+			widths[i] = maxWidth
+		}
+	}
+
+	printRow := func(row []string) {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			cell = strings.ReplaceAll(cell, "\n", " ")
+			if utf8.RuneCountInString(cell) > widths[i] {
+				cell = string([]rune(cell)[:widths[i]-3]) + "..."
+			}
+			cells[i] = cell + strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell))
+		}
+		fmt.Println(strings.TrimRight(strings.Join(cells, "  "), " "))
+	}
+
+	printRow(table.header)
+	separators := make([]string, len(widths))
+	for i, w := range widths {
+		separators[i] = strings.Repeat("-", w)
+	}
+	fmt.Println(strings.Join(separators, "  "))
+	for _, row := range shown {
+		printRow(row)
+	}
+	if table.more {
+		fmt.Printf("\n%d rows shown, more follow\n", len(shown))
+	} else {
+		fmt.Printf("\n%d rows\n", len(shown))
+	}
+
+	return nil
+}
+
+func runCSVSelect(cmd *cobra.Command, args []string) error {
+	columns, _ := cmd.Flags().GetString("columns")
+	noHeader, _ := cmd.Flags().GetBool("no-header")
+	outputFormat, _ := cmd.Flags().GetString("output")
+	format := output.OutputFormat(outputFormat)
+
+	table, comma, err := readCSV(cmd, args, -1)
+	if err != nil {
+		return err
+	}
+
+	var indexes []int
+	var names []string
+	for _, col := range strings.Split(columns, ",") {
+		col = strings.TrimSpace(col)
+		if col == "" {
+			continue
+		}
+		index := -1
+		for i, name := range table.header {
+			if name == col {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			if n, err := strconv.Atoi(col); err == nil && n >= 1 && n <= len(table.header) {
+				index = n - 1
+			}
+		}
+		if index < 0 {
+			return fmt.Errorf("column not found: %s", col)
+		}
+		indexes = append(indexes, index)
+		names = append(names, table.header[index])
+	}
+	if len(indexes) == 0 {
+		return fmt.Errorf("no columns specified")
+	}
+
+	selected := make([][]string, len(table.rows))
+	for r, row := range table.rows {
+		selected[r] = make([]string, len(indexes))
+		for i, index := range indexes {
+			selected[r][i] = row[index]
+		}
+	}
+
+	if format == output.FormatJSON {
+		output.PrintSuccess(format, map[string]interface{}{
+			"columns": names,
+			"rows":    selected,
+			"count":   len(selected),
+		})
+		return nil
+	}
+
+	writer := csv.NewWriter(os.Stdout)
+	writer.Comma = comma
+	if !noHeader {
+		writer.Write(names)
+	}
+	writer.WriteAll(selected)
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+
+	return nil
+}
+
+func runCSVToJSON(cmd *cobra.Command, args []string) error {
+	noHeader, _ := cmd.Flags().GetBool("no-header")
+	types, _ := cmd.Flags().GetBool("types")
+	ndjson, _ := cmd.Flags().GetBool("ndjson")
+	outputFormat, _ := cmd.Flags().GetString("output")
+	format := output.OutputFormat(outputFormat)
+
+	table, _, err := readCSV(cmd, args, -1)
+	if err != nil {
+		return err
+	}
+
+	// Columns are object keys, so a repeated name would overwrite a value
+	if !noHeader {
+		seen := make(map[string]int, len(table.header))
+		for i, name := range table.header {
+			if first, ok := seen[name]; ok {
+				cmd.SilenceUsage = true
+				return fmt.Errorf("duplicate column name %q (columns %d and %d): rename one or use --no-header", name, first+1, i+1)
+			}
+			seen[name] = i
+		}
+	}
+
+	docs := make([]interface{}, len(table.rows))
+	for r, row := range table.rows {
+		if noHeader {
+			values := make([]interface{}, len(row))
+			for i, cell := range row {
+				values[i] = csvValue(cell, types)
+			}
+			docs[r] = values
+			continue
+		}
+		obj := make(map[string]interface{}, len(table.header))
+		for i, name := range table.header {
+			obj[name] = csvValue(row[i], types)
+		}
+		docs[r] = obj
+	}
+
+	if format == output.FormatJSON {
+		output.PrintSuccess(format, map[string]interface{}{
+			"documents": docs,
+			"count":     len(docs),
+		})
+		return nil
+	}
+
+	if ndjson {
+		for _, doc := range docs {
+			line, err := json.Marshal(doc)
+			if err != nil {
+				return fmt.Errorf("failed to encode JSON: %w", err)
+			}
+			fmt.Println(string(line))
+		}
+		return nil
+	}
+
+	data, err := json.MarshalIndent(docs, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode JSON: %w", err)
+	}
+	output.PrintSuccess(format, string(data))
+
+	return nil
+}
+
+func runCSVStats(cmd *cobra.Command, args []string) error {
+	outputFormat, _ := cmd.Flags().GetString("output")
+	format := output.OutputFormat(outputFormat)
+
+	table, _, err := readCSV(cmd, args, -1)
+	if err != nil {
+		return err
+	}
+
+	var columns []map[string]interface{}
+	totalEmpty := 0
+	for i, name := range table.header {
+		empty, numeric := 0, 0
+		distinct := make(map[string]bool)
+		for _, row := range table.rows {
+			cell := strings.TrimSpace(row[i])
+			if cell == "" {
+				empty++
+				continue
+			}
+			distinct[cell] = true
+			if _, err := strconv.ParseFloat(cell, 64); err == nil {
+				numeric++
+			}
+		}
+		totalEmpty += empty
+		columns = append(columns, map[string]interface{}{
+			"name":     name,
+			"empty":    empty,
+			"distinct": len(distinct),
+			"numeric":  numeric,
+		})
+	}
+
+	if format == output.FormatJSON {
+		output.PrintSuccess(format, map[string]interface{}{
+			"rows":         len(table.rows),
+			"columns":      len(table.header),
+			"empty_cells":  totalEmpty,
+			"column_stats": columns,
+		})
+		return nil
+	}
+
+	fmt.Printf("Rows: %d\n", len(table.rows))
+	fmt.Printf("Columns: %d\n", len(table.header))
+	fmt.Printf("Empty cells: %d\n\n", totalEmpty)
+	fmt.Printf("%-24s %8s %10s %8s\n", "COLUMN", "EMPTY", "DISTINCT", "NUMERIC")
+	for _, col := range columns {
+		name := col["name"].(string)
+		if utf8.RuneCountInString(name) > 24 {
+			name = string([]rune(name)[:21]) + "..."
+		}
+		fmt.Printf("%-24s %8d %10d %8d\n", name, col["empty"], col["distinct"], col["numeric"])
+	}
+
+	return nil
+}
+
+// csvValue converts a cell to a number or boolean when types is set
+func csvValue(cell string, types bool) interface{} {
+	if !types {
+		return cell
+	}
+	if cell == "" {
+		return nil
+	}
+	if n, err := strconv.ParseInt(cell, 10, 64); err == nil {
+		return n
+	}
+	// NaN and infinities have no JSON form and stay strings
+	if f, err := strconv.ParseFloat(cell, 64); err == nil && !math.IsNaN(f) && !math.IsInf(f, 0) {
+		return f
+	}
+	switch strings.ToLower(cell) {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+	return cell
+}
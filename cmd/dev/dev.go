@@ -13,13 +13,13 @@ var devCmd = &cobra.Command{
 This command group includes utilities for:
 - UUID/ULID generation
 - Hash calculation
-- Base64 encoding/decoding
+- Base64, Base32 and hex encoding/decoding
 - JWT operations
 - Regex testing
 - Color conversion
 - URL encoding/decoding
 - HTML entity operations
-- JSON operations
+- JSON, YAML, TOML, XML and CSV operations
 - Random data generation
 - And more...`,
 }
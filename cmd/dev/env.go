@@ -8,6 +8,9 @@ import (
 
 	"github.com/spf13/cobra"
 	"devkit/internal/output"
+	"devkit/internal/safety"
+	"devkit/internal/undo"
+	"devkit/internal/workspace"
 )
 
 // envCmd represents the env command group
@@ -55,7 +58,9 @@ var envUnsetCmd = &cobra.Command{
 
 Examples:
   devkit dev env unset DATABASE_URL --file .env
-  devkit dev env unset API_KEY`,
+  devkit dev env unset API_KEY
+  devkit dev env unset API_KEY --dry-run
+  devkit dev env unset API_KEY --yes`,
 	RunE: runEnvUnset,
 }
 
@@ -85,6 +90,7 @@ func init() {
 	envSetCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
 
 	envUnsetCmd.Flags().StringP("file", "f", ".env", ".env file path")
+	envUnsetCmd.Flags().BoolP("dry-run", "d", false, "Show what would be removed without making changes")
 	envUnsetCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
 
 	envListCmd.Flags().StringP("file", "f", ".env", ".env file path")
@@ -92,6 +98,9 @@ func init() {
 }
 
 func getEnvFilePath(cmd *cobra.Command) string {
+	if ws := workspace.Current(); ws != nil && ws.EnvFile != "" && !cmd.Flags().Changed("file") {
+		return ws.Resolve(ws.EnvFile)
+	}
 	fileFlag, _ := cmd.Flags().GetString("file")
 	if fileFlag == "" {
 		return ".env"
@@ -183,6 +192,27 @@ func runEnvGet(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// journaledWriteEnvFile writes the .env file and records the previous
+// content in the undo journal, returning the operation id
+func journaledWriteEnvFile(cmd *cobra.Command, filePath string, env map[string]string) (string, error) {
+	op, err := undo.Begin(cmd.CommandPath())
+	if err != nil {
+		return "", err
+	}
+	if err := op.BackupFile(filePath); err != nil {
+		op.Discard()
+		return "", err
+	}
+	if err := writeEnvFile(filePath, env); err != nil {
+		op.Discard()
+		return "", err
+	}
+	if err := op.Commit(); err != nil {
+		return "", err
+	}
+	return op.ID, nil
+}
+
 func runEnvSet(cmd *cobra.Command, args []string) error {
 	outputFormat, _ := cmd.Flags().GetString("output")
 	format := output.OutputFormat(outputFormat)
@@ -208,7 +238,8 @@ func runEnvSet(cmd *cobra.Command, args []string) error {
 
 	env[key] = value
 
-	if err := writeEnvFile(filePath, env); err != nil {
+	undoID, err := journaledWriteEnvFile(cmd, filePath, env)
+	if err != nil {
 		return fmt.Errorf("failed to write .env file: %w", err)
 	}
 
@@ -217,15 +248,17 @@ func runEnvSet(cmd *cobra.Command, args []string) error {
 			"key":   key,
 			"value": value,
 			"action": "set",
+			"undo_id": undoID,
 		})
 	} else {
-		output.PrintSuccess(format, fmt.Sprintf("Set %s=%s", key, value))
+		output.PrintSuccess(format, fmt.Sprintf("Set %s=%s (undo with: devkit undo %s)", key, value, undoID))
 	}
 
 	return nil
 }
 
 func runEnvUnset(cmd *cobra.Command, args []string) error {
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
 	outputFormat, _ := cmd.Flags().GetString("output")
 	format := output.OutputFormat(outputFormat)
 
@@ -245,19 +278,35 @@ func runEnvUnset(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("key not found: %s", key)
 	}
 
-	delete(env, key)
+	if err := safety.CheckDryRun(cmd, args, dryRun); err != nil {
+		return err
+	}
 
-	if err := writeEnvFile(filePath, env); err != nil {
-		return fmt.Errorf("failed to write .env file: %w", err)
+	var undoID string
+	if !dryRun {
+		if err := safety.ConfirmChanges(safety.RemoveKeys, 1); err != nil {
+			return err
+		}
+
+		delete(env, key)
+
+		undoID, err = journaledWriteEnvFile(cmd, filePath, env)
+		if err != nil {
+			return fmt.Errorf("failed to write .env file: %w", err)
+		}
 	}
 
 	if format == output.FormatJSON {
 		output.PrintSuccess(format, map[string]interface{}{
-			"key":    key,
-			"action": "unset",
+			"key":     key,
+			"action":  "unset",
+			"dry_run": dryRun,
+			"undo_id": undoID,
 		})
+	} else if dryRun {
+		output.PrintSuccess(format, fmt.Sprintf("DRY RUN - Would unset %s in %s", key, filePath))
 	} else {
-		output.PrintSuccess(format, fmt.Sprintf("Unset %s", key))
+		output.PrintSuccess(format, fmt.Sprintf("Unset %s (undo with: devkit undo %s)", key, undoID))
 	}
 
 	return nil
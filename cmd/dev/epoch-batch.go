@@ -0,0 +1,173 @@
+package dev
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"devkit/internal/output"
+)
+
+// Record formats of epoch --stdin besides plain and json
+const (
+	epochFormatNDJSON = "ndjson"
+	epochFormatCSV    = "csv"
+)
+
+// epochBatch holds the settings that apply to every line of epoch --stdin
+type epochBatch struct {
+	unit    string // input unit of timestamps, or "auto"
+	outUnit string // unit of the printed timestamps; empty for the input unit
+	loc     *time.Location
+	layout  string
+	field   int // 1-based whitespace-separated field holding the value; 0 for the whole line
+}
+
+// epochRecord is the conversion of one line
+type epochRecord struct {
+	Input     string `json:"input"`
+	Timestamp *int64 `json:"timestamp,omitempty"`
+	Unit      string `json:"unit,omitempty"`
+	Date      string `json:"date,omitempty"`
+	UTC       string `json:"utc,omitempty"`
+	Formatted string `json:"formatted,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// runEpochBatch converts one timestamp or date per line of r, streaming
+// the results; lines that cannot be converted are reported in their record
+// and make the command fail once all lines are done
+func runEpochBatch(r io.Reader, b epochBatch, format string) error {
+	var csvWriter *csv.Writer
+	if format == epochFormatCSV {
+		csvWriter = csv.NewWriter(os.Stdout)
+		header := []string{"input", "timestamp", "unit", "date", "utc"}
+		if b.layout != "" {
+			header = append(header, "formatted")
+		}
+		csvWriter.Write(append(header, "error"))
+	}
+
+	var records []epochRecord
+	total, failed := 0, 0
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		value := strings.TrimSpace(scanner.Text())
+		if value == "" {
+			continue
+		}
+		total++
+		rec := b.convert(value)
+		if rec.Error != "" {
+			failed++
+		}
+
+		switch format {
+		case epochFormatNDJSON:
+			data, _ := json.Marshal(rec)
+			fmt.Println(string(data))
+		case epochFormatCSV:
+			row := []string{rec.Input, "", rec.Unit, rec.Date, rec.UTC}
+			if rec.Timestamp != nil {
+				row[1] = strconv.FormatInt(*rec.Timestamp, 10)
+			}
+			if b.layout != "" {
+				row = append(row, rec.Formatted)
+			}
+			csvWriter.Write(append(row, rec.Error))
+			csvWriter.Flush()
+		case string(output.FormatJSON):
+			records = append(records, rec)
+		default:
+			switch {
+			case rec.Error != "":
+				fmt.Printf("%s\t%s\n", rec.Input, output.Failure(rec.Error))
+			case rec.Formatted != "":
+				fmt.Printf("%s\t%s\n", rec.Input, rec.Formatted)
+			default:
+				fmt.Printf("%s\t%s\n", rec.Input, rec.Date)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("read error: %w", err)
+	}
+	if csvWriter != nil {
+		csvWriter.Flush()
+		if err := csvWriter.Error(); err != nil {
+			return err
+		}
+	}
+
+	if format == string(output.FormatJSON) {
+		if records == nil {
+			records = []epochRecord{}
+		}
+		output.PrintSuccess(output.FormatJSON, map[string]interface{}{
+			"count":   total,
+			"failed":  failed,
+			"results": records,
+		})
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d lines could not be converted", failed, total)
+	}
+	return nil
+}
+
+// convert reads the value of a line: a timestamp, a date (in --format or
+// one of the --to-unix formats) or "now"
+func (b epochBatch) convert(line string) epochRecord {
+	value := line
+	if b.field > 0 {
+		fields := strings.Fields(line)
+		if b.field > len(fields) {
+			return epochRecord{Input: line, Error: fmt.Sprintf("line has no field %d", b.field)}
+		}
+		value = fields[b.field-1]
+	}
+	rec := epochRecord{Input: value}
+
+	var t time.Time
+	outUnit := b.outUnit
+	if timestamp, err := strconv.ParseInt(value, 10, 64); err == nil {
+		inUnit := b.unit
+		if inUnit == "auto" {
+			inUnit = detectEpochUnit(value)
+		}
+		if outUnit == "" {
+			outUnit = inUnit
+		}
+		t = epochTime(timestamp, inUnit)
+	} else {
+		if value == "now" {
+			t = time.Now()
+		} else if t, err = parseEpochDate(value, b.layout, b.loc); err != nil {
+			rec.Error = "not a timestamp or date"
+			return rec
+		}
+		if outUnit == "" {
+			outUnit = "s"
+		}
+	}
+
+	if b.loc != nil {
+		t = t.In(b.loc)
+	}
+	timestamp := epochTimestamp(t, outUnit)
+	rec.Timestamp = &timestamp
+	rec.Unit = outUnit
+	rec.Date = t.Format(time.RFC3339Nano)
+	rec.UTC = t.UTC().Format(time.RFC3339Nano)
+	if b.layout != "" {
+		rec.Formatted = t.Format(b.layout)
+	}
+	return rec
+}
@@ -0,0 +1,260 @@
+package dev
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"devkit/internal/output"
+)
+
+// epochDiffCmd represents the epoch diff subcommand
+var epochDiffCmd = &cobra.Command{
+	Use:   "diff <from> <to>",
+	Short: "Print the duration between two timestamps or dates",
+	Long: `Print the time from one timestamp or date to another, in words and as an
+ISO 8601 duration (P1Y2M3DT4H5M6S).
+
+Each value is a Unix timestamp (its unit detected as in "dev epoch", or set
+with --unit), a date in one of the formats of --to-unix, or "now". Years,
+months and days are counted on the calendar in UTC, so 2024-01-31 to
+2024-02-29 is 29 days and 2024-01-15 to 2024-03-15 is 2 months. When <to>
+is before <from> the duration is negative.
+
+Examples:
+  devkit dev epoch diff 1699876543 1700000000
+  devkit dev epoch diff 2024-01-15 now
+  devkit dev epoch diff "2024-01-15 10:30:00" "2024-03-01 08:00:00" --tz Europe/Istanbul
+  devkit dev epoch diff 1699876543123 now --output json`,
+	Args: cobra.ExactArgs(2),
+	RunE: runEpochDiff,
+}
+
+func init() {
+	epochCmd.AddCommand(epochDiffCmd)
+
+	epochDiffCmd.Flags().String("unit", "auto", "Unit of timestamp arguments: auto, s, ms, us, ns")
+	epochDiffCmd.Flags().String("tz", "", "Time zone of dates without an offset, e.g. Europe/Istanbul (default: UTC)")
+	epochDiffCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
+}
+
+func runEpochDiff(cmd *cobra.Command, args []string) error {
+	unit, _ := cmd.Flags().GetString("unit")
+	tz, _ := cmd.Flags().GetString("tz")
+	outputFormat, _ := cmd.Flags().GetString("output")
+	format := output.OutputFormat(outputFormat)
+
+	if _, ok := epochUnits[unit]; !ok && unit != "auto" {
+		return fmt.Errorf("invalid unit: %s (use auto, s, ms, us or ns)", unit)
+	}
+	var loc *time.Location
+	if tz != "" {
+		var err error
+		if loc, err = time.LoadLocation(tz); err != nil {
+			return fmt.Errorf("unknown time zone: %s (use an IANA name such as Europe/Istanbul)", tz)
+		}
+	}
+
+	now := time.Now()
+	times := make([]time.Time, 2)
+	for i, arg := range args {
+		var err error
+		if times[i], err = parseEpochValue(arg, unit, loc, now); err != nil {
+			return err
+		}
+	}
+	from, to := times[0], times[1]
+	span := newEpochSpan(from, to)
+
+	if format == output.FormatJSON {
+		output.PrintSuccess(format, map[string]interface{}{
+			"from":    from.Format(time.RFC3339Nano),
+			"to":      to.Format(time.RFC3339Nano),
+			"seconds": to.Sub(from).Seconds(),
+			"human":   span.human(0),
+			"iso8601": span.iso8601(),
+		})
+		return nil
+	}
+
+	fmt.Printf("From: %s\n", from.Format(time.RFC3339Nano))
+	fmt.Printf("To: %s\n", to.Format(time.RFC3339Nano))
+	fmt.Printf("Duration: %s\n", output.Accent(span.human(0)))
+	fmt.Printf("ISO 8601: %s\n", span.iso8601())
+	fmt.Printf("Seconds: %s\n", strconv.FormatFloat(to.Sub(from).Seconds(), 'f', -1, 64))
+	return nil
+}
+
+// parseEpochValue reads "now", a Unix timestamp in unit (or of the detected
+// unit with "auto") or a date
+func parseEpochValue(value, unit string, loc *time.Location, now time.Time) (time.Time, error) {
+	if value == "now" {
+		return now, nil
+	}
+	if timestamp, err := strconv.ParseInt(value, 10, 64); err == nil {
+		if unit == "auto" {
+			unit = detectEpochUnit(value)
+		}
+		return epochTime(timestamp, unit), nil
+	}
+	return parseEpochDate(value, "", loc)
+}
+
+// epochSpan is the calendar distance between two times
+type epochSpan struct {
+	negative                            bool
+	years, months, days, hours, minutes int
+	seconds                             int
+	nanos                               int
+}
+
+// newEpochSpan counts the years, months, days and clock units from a to b
+// on the UTC calendar, borrowing from the larger unit like a subtraction
+func newEpochSpan(a, b time.Time) epochSpan {
+	var s epochSpan
+	a, b = a.UTC(), b.UTC()
+	if b.Before(a) {
+		a, b = b, a
+		s.negative = true
+	}
+
+	s.years = b.Year() - a.Year()
+	s.months = int(b.Month()) - int(a.Month())
+	s.days = b.Day() - a.Day()
+	s.hours = b.Hour() - a.Hour()
+	s.minutes = b.Minute() - a.Minute()
+	s.seconds = b.Second() - a.Second()
+	s.nanos = b.Nanosecond() - a.Nanosecond()
+
+	if s.nanos < 0 {
+		s.nanos += int(time.Second)
+		s.seconds--
+	}
+	if s.seconds < 0 {
+		s.seconds += 60
+		s.minutes--
+	}
+	if s.minutes < 0 {
+		s.minutes += 60
+		s.hours--
+	}
+	if s.hours < 0 {
+		s.hours += 24
+		s.days--
+	}
+	// Borrow the lengths of the months before b's month; a short month may
+	// not be enough, as from January 31 to March 1
+	for month := b.Month(); s.days < 0; month-- {
+		s.days += time.Date(b.Year(), month, 0, 0, 0, 0, 0, time.UTC).Day()
+		s.months--
+	}
+	for s.months < 0 {
+		s.months += 12
+		s.years--
+	}
+	return s
+}
+
+// parts returns the non-zero units, largest first; fractions of a second
+// are added to the seconds
+func (s epochSpan) parts() []string {
+	var parts []string
+	add := func(n int, name string) {
+		switch {
+		case n == 1:
+			parts = append(parts, "1 "+name)
+		case n > 1:
+			parts = append(parts, fmt.Sprintf("%d %ss", n, name))
+		}
+	}
+	add(s.years, "year")
+	add(s.months, "month")
+	add(s.days, "day")
+	add(s.hours, "hour")
+	add(s.minutes, "minute")
+	if s.nanos > 0 {
+		parts = append(parts, s.fractionalSeconds()+" seconds")
+	} else {
+		add(s.seconds, "second")
+	}
+	return parts
+}
+
+// human describes the span in words, keeping only the largest max units
+// when max > 0
+func (s epochSpan) human(max int) string {
+	parts := s.parts()
+	if len(parts) == 0 {
+		return "0 seconds"
+	}
+	if max > 0 && len(parts) > max {
+		parts = parts[:max]
+	}
+	text := strings.Join(parts, " ")
+	if s.negative {
+		text = "-" + text
+	}
+	return text
+}
+
+// iso8601 formats the span as an ISO 8601 duration, with a leading minus
+// sign when negative
+func (s epochSpan) iso8601() string {
+	var date, clock string
+	for _, u := range []struct {
+		n    int
+		unit string
+	}{{s.years, "Y"}, {s.months, "M"}, {s.days, "D"}} {
+		if u.n > 0 {
+			date += strconv.Itoa(u.n) + u.unit
+		}
+	}
+	if s.hours > 0 {
+		clock += strconv.Itoa(s.hours) + "H"
+	}
+	if s.minutes > 0 {
+		clock += strconv.Itoa(s.minutes) + "M"
+	}
+	if s.seconds > 0 || s.nanos > 0 {
+		clock += s.fractionalSeconds() + "S"
+	}
+
+	if date == "" && clock == "" {
+		return "PT0S"
+	}
+	duration := "P" + date
+	if clock != "" {
+		duration += "T" + clock
+	}
+	if s.negative {
+		duration = "-" + duration
+	}
+	return duration
+}
+
+// fractionalSeconds formats the seconds with their fraction, without
+// trailing zeros
+func (s epochSpan) fractionalSeconds() string {
+	if s.nanos == 0 {
+		return strconv.Itoa(s.seconds)
+	}
+	fraction := strings.TrimRight(fmt.Sprintf("%09d", s.nanos), "0")
+	return fmt.Sprintf("%d.%s", s.seconds, fraction)
+}
+
+// epochRelative describes t relative to now, e.g. "3 days 4 hours ago" or
+// "2 months 1 day from now"
+func epochRelative(t, now time.Time) string {
+	span := newEpochSpan(now, t)
+	span.nanos = 0
+	text := span.human(2)
+	switch {
+	case text == "0 seconds":
+		return "now"
+	case span.negative:
+		return strings.TrimPrefix(text, "-") + " ago"
+	}
+	return text + " from now"
+}
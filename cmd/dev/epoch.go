@@ -2,7 +2,10 @@ package dev
 
 import (
 	"fmt"
+	"os"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -15,10 +18,40 @@ var epochCmd = &cobra.Command{
 	Short: "Convert between Unix timestamp and date",
 	Long: `Convert between Unix timestamp and human-readable date.
 
+The precision of a timestamp is detected from its number of digits: up to
+10 digits are seconds, 11-13 milliseconds (as in JavaScript's Date.now()),
+14-16 microseconds and 17-19 nanoseconds. --unit overrides the detection,
+and --out-unit sets the unit of the printed timestamp (default: the input
+unit, or seconds for dates and "now").
+
+--tz renders the date in an IANA time zone and reads --to-unix dates
+without an offset in it. --format prints the date in a custom layout: a Go
+reference layout such as "2006-01-02 15:04 MST", or one of ansic, date,
+datetime, kitchen, rfc822, rfc822z, rfc850, rfc1123, rfc1123z, rfc3339,
+rfc3339nano, rubydate, stamp, time and unixdate.
+
+The output also tells how long ago or from now the time is, e.g. "3 days
+4 hours ago"; "epoch diff" prints the duration between two times.
+
+--stdin converts one value per line, streaming the results as they are
+read: plain prints the input and its date separated by a tab, ndjson one
+JSON object per line and csv a header and one row per line; json collects
+all results. --field picks a whitespace-separated field of each line, e.g.
+the timestamp column of a log. Lines that cannot be converted are reported
+in their result, and the command fails once all lines are done.
+
 Examples:
   devkit dev epoch 1699876543                    # Convert timestamp to date
+  devkit dev epoch 1699876543123                 # Milliseconds are detected
+  devkit dev epoch 1699876543 --unit ms          # Force the input unit
   devkit dev epoch --to-unix "2024-01-15 10:30"  # Convert date to timestamp
-  devkit dev epoch now                          # Current timestamp`,
+  devkit dev epoch now --out-unit ms             # Current timestamp in milliseconds
+  devkit dev epoch 1699876543 --tz Asia/Tokyo    # Render in another time zone
+  devkit dev epoch now --tz America/New_York --format rfc1123
+  devkit dev epoch 1699876543 --format "Mon 02 Jan 2006 15:04 MST"
+  devkit dev epoch diff 2024-01-15 now           # Duration between two times
+  cut -d' ' -f1 app.log | devkit dev epoch --stdin --output ndjson
+  devkit dev epoch --stdin --field 2 --output csv < events.log > events.csv`,
 	RunE: runEpoch,
 }
 
@@ -26,47 +59,94 @@ func init() {
 	devCmd.AddCommand(epochCmd)
 
 	epochCmd.Flags().String("to-unix", "", "Convert date string to Unix timestamp")
-	epochCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
+	epochCmd.Flags().String("unit", "auto", "Unit of the input timestamp: auto, s, ms, us, ns")
+	epochCmd.Flags().String("out-unit", "", "Unit of the printed timestamp: s, ms, us, ns (default: the input unit)")
+	epochCmd.Flags().String("tz", "", "Time zone to render the date in, e.g. Europe/Istanbul, UTC (default: local)")
+	epochCmd.Flags().String("format", "", "Date layout: a Go layout such as \"2006-01-02 15:04 MST\" or a name like rfc1123, kitchen")
+	epochCmd.Flags().Bool("stdin", false, "Convert one timestamp or date per line of stdin")
+	epochCmd.Flags().Int("field", 0, "With --stdin, convert only this whitespace-separated field of each line (1-based)")
+	epochCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json; with --stdin also ndjson, csv")
 }
 
 func runEpoch(cmd *cobra.Command, args []string) error {
 	toUnix, _ := cmd.Flags().GetString("to-unix")
+	unit, _ := cmd.Flags().GetString("unit")
+	outUnit, _ := cmd.Flags().GetString("out-unit")
+	tz, _ := cmd.Flags().GetString("tz")
+	layout, _ := cmd.Flags().GetString("format")
+	stdinFlag, _ := cmd.Flags().GetBool("stdin")
+	field, _ := cmd.Flags().GetInt("field")
 	outputFormat, _ := cmd.Flags().GetString("output")
 	format := output.OutputFormat(outputFormat)
 
-	var result map[string]interface{}
-
-	if toUnix != "" {
-		// Convert date to Unix timestamp
-		layouts := []string{
-			time.RFC3339,
-			"2006-01-02 15:04:05",
-			"2006-01-02T15:04:05",
-			"2006-01-02",
-			time.RFC822,
-			time.RFC1123,
-		}
+	if _, ok := epochUnits[unit]; !ok && unit != "auto" {
+		return fmt.Errorf("invalid unit: %s (use auto, s, ms, us or ns)", unit)
+	}
+	if _, ok := epochUnits[outUnit]; !ok && outUnit != "" {
+		return fmt.Errorf("invalid output unit: %s (use s, ms, us or ns)", outUnit)
+	}
+	if outUnit == "" {
+		outUnit = "s"
+	}
 
-		var t time.Time
+	var loc *time.Location
+	if tz != "" {
 		var err error
-		parsed := false
+		if loc, err = time.LoadLocation(tz); err != nil {
+			return fmt.Errorf("unknown time zone: %s (use an IANA name such as Europe/Istanbul)", tz)
+		}
+	}
+	if layout != "" {
+		if named, ok := epochLayouts[strings.ToLower(layout)]; ok {
+			layout = named
+		} else if sample := time.Date(2001, 11, 22, 13, 44, 55, 0, time.UTC); sample.Format(layout) == layout {
+			return fmt.Errorf("invalid format: %s (use a Go layout such as \"2006-01-02 15:04\" or one of %s)", layout, strings.Join(epochLayoutNames(), ", "))
+		}
+	}
 
-		for _, layout := range layouts {
-			t, err = time.Parse(layout, toUnix)
-			if err == nil {
-				parsed = true
-				break
-			}
+	if stdinFlag {
+		if len(args) > 0 || toUnix != "" {
+			return fmt.Errorf("--stdin cannot be combined with an argument or --to-unix")
 		}
+		switch outputFormat {
+		case string(output.FormatPlain), string(output.FormatJSON), epochFormatNDJSON, epochFormatCSV:
+		default:
+			return fmt.Errorf("invalid output format: %s (use plain, json, ndjson or csv)", outputFormat)
+		}
+		if field < 0 {
+			return fmt.Errorf("field must be 1 or more")
+		}
+		stat, err := os.Stdin.Stat()
+		if err != nil {
+			return fmt.Errorf("stdin error: %w", err)
+		}
+		if (stat.Mode() & os.ModeCharDevice) != 0 {
+			return fmt.Errorf("no data available from stdin")
+		}
+		if !cmd.Flags().Changed("out-unit") {
+			outUnit = ""
+		}
+		cmd.SilenceUsage = true
+		return runEpochBatch(os.Stdin, epochBatch{unit: unit, outUnit: outUnit, loc: loc, layout: layout, field: field}, outputFormat)
+	}
+	if field != 0 {
+		return fmt.Errorf("--field requires --stdin")
+	}
+
+	var result map[string]interface{}
+	var t time.Time
 
-		if !parsed {
-			return fmt.Errorf("failed to parse date: %s (supported formats: RFC3339, 2006-01-02 15:04:05, 2006-01-02)", toUnix)
+	if toUnix != "" {
+		// Convert date to Unix timestamp
+		var err error
+		if t, err = parseEpochDate(toUnix, layout, loc); err != nil {
+			return err
 		}
 
-		unix := t.Unix()
 		result = map[string]interface{}{
-			"timestamp": unix,
-			"date":      t.Format(time.RFC3339),
+			"timestamp": epochTimestamp(t, outUnit),
+			"unit":      outUnit,
+			"date":      t.Format(time.RFC3339Nano),
 			"input":     toUnix,
 		}
 	} else if len(args) > 0 {
@@ -74,11 +154,12 @@ func runEpoch(cmd *cobra.Command, args []string) error {
 
 		if input == "now" {
 			// Current timestamp
-			now := time.Now()
+			t = time.Now().Truncate(epochUnits[outUnit])
 			result = map[string]interface{}{
-				"timestamp": now.Unix(),
-				"date":      now.Format(time.RFC3339),
-				"utc":       now.UTC().Format(time.RFC3339),
+				"timestamp": epochTimestamp(t, outUnit),
+				"unit":      outUnit,
+				"date":      t.Format(time.RFC3339Nano),
+				"utc":       t.UTC().Format(time.RFC3339Nano),
 			}
 		} else {
 			// Convert timestamp to date
@@ -87,31 +168,187 @@ func runEpoch(cmd *cobra.Command, args []string) error {
 				return fmt.Errorf("invalid timestamp: %s", input)
 			}
 
-			t := time.Unix(timestamp, 0)
+			inUnit := unit
+			if inUnit == "auto" {
+				inUnit = detectEpochUnit(input)
+			}
+			if !cmd.Flags().Changed("out-unit") {
+				outUnit = inUnit
+			}
+
+			t = epochTime(timestamp, inUnit)
 			result = map[string]interface{}{
-				"timestamp": timestamp,
-				"date":      t.Format(time.RFC3339),
-				"utc":       t.UTC().Format(time.RFC3339),
-				"unix":      timestamp,
+				"timestamp":  epochTimestamp(t, outUnit),
+				"unit":       outUnit,
+				"input_unit": inUnit,
+				"date":       t.Format(time.RFC3339Nano),
+				"utc":        t.UTC().Format(time.RFC3339Nano),
+				"unix":       t.Unix(),
 			}
 		}
 	} else {
 		return fmt.Errorf("timestamp or date not specified")
 	}
 
+	// Render the date in --tz and --format
+	if loc != nil {
+		t = t.In(loc)
+		result["date"] = t.Format(time.RFC3339Nano)
+	}
+	offset := t.Format("-07:00")
+	zone, _ := t.Zone()
+	if zone == "" {
+		zone = offset
+	}
+	timezone := cronZoneName(t.Location())
+	if timezone == "" {
+		timezone = zone
+	}
+	result["timezone"] = timezone
+	result["zone"] = zone
+	result["offset"] = offset
+	if layout != "" {
+		result["formatted"] = t.Format(layout)
+	}
+	if len(args) == 0 || args[0] != "now" {
+		result["relative"] = epochRelative(t, time.Now())
+	}
+
 	if format == output.FormatJSON {
 		output.PrintSuccess(format, result)
 	} else {
 		if timestamp, ok := result["timestamp"].(int64); ok {
-			fmt.Printf("Timestamp: %d\n", timestamp)
+			fmt.Printf("Timestamp: %d (%s)\n", timestamp, result["unit"])
 		}
-		if date, ok := result["date"].(string); ok {
-			fmt.Printf("Date: %s\n", date)
+		if inUnit, ok := result["input_unit"].(string); ok && inUnit != result["unit"] {
+			fmt.Printf("Input unit: %s\n", inUnit)
+		}
+		if formatted, ok := result["formatted"].(string); ok {
+			fmt.Printf("Date: %s\n", formatted)
+		} else {
+			fmt.Printf("Date: %s\n", result["date"])
+		}
+		if result["timezone"] == result["zone"] {
+			fmt.Printf("Time zone: %s (UTC%s)\n", result["timezone"], result["offset"])
+		} else {
+			fmt.Printf("Time zone: %s, %s (UTC%s)\n", result["timezone"], result["zone"], result["offset"])
 		}
 		if utc, ok := result["utc"].(string); ok {
 			fmt.Printf("UTC: %s\n", utc)
 		}
+		if relative, ok := result["relative"].(string); ok {
+			fmt.Printf("Relative: %s\n", relative)
+		}
 	}
 
 	return nil
 }
+
+// epochUnits maps the --unit names to their durations
+var epochUnits = map[string]time.Duration{
+	"s":  time.Second,
+	"ms": time.Millisecond,
+	"us": time.Microsecond,
+	"ns": time.Nanosecond,
+}
+
+// epochDateLayouts are the date formats read by --to-unix, epoch diff and
+// the time commands
+var epochDateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04",
+	"2006-01-02T15:04",
+	"2006-01-02",
+	time.RFC822,
+	time.RFC1123,
+}
+
+// parseEpochDate parses a date in layout, or else one of epochDateLayouts;
+// dates without an offset are in loc, or UTC when loc is nil
+func parseEpochDate(value, layout string, loc *time.Location) (time.Time, error) {
+	layouts := epochDateLayouts
+	if layout != "" {
+		layouts = append([]string{layout}, layouts...)
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+	for _, layout := range layouts {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("failed to parse date: %s (supported formats: RFC3339, 2006-01-02 15:04:05, 2006-01-02 15:04, 2006-01-02)", value)
+}
+
+// epochLayouts are the layout names --format accepts besides Go layouts
+var epochLayouts = map[string]string{
+	"ansic":       time.ANSIC,
+	"unixdate":    time.UnixDate,
+	"rubydate":    time.RubyDate,
+	"rfc822":      time.RFC822,
+	"rfc822z":     time.RFC822Z,
+	"rfc850":      time.RFC850,
+	"rfc1123":     time.RFC1123,
+	"rfc1123z":    time.RFC1123Z,
+	"rfc3339":     time.RFC3339,
+	"rfc3339nano": time.RFC3339Nano,
+	"kitchen":     time.Kitchen,
+	"stamp":       time.Stamp,
+	"datetime":    time.DateTime,
+	"date":        time.DateOnly,
+	"time":        time.TimeOnly,
+}
+
+// epochLayoutNames returns the --format layout names, sorted
+func epochLayoutNames() []string {
+	names := make([]string, 0, len(epochLayouts))
+	for name := range epochLayouts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// detectEpochUnit guesses the unit of a timestamp from its number of digits;
+// seconds reach 11 digits only in the year 5138
+func detectEpochUnit(timestamp string) string {
+	digits := len(strings.TrimLeft(strings.TrimPrefix(timestamp, "-"), "0"))
+	switch {
+	case digits <= 10:
+		return "s"
+	case digits <= 13:
+		return "ms"
+	case digits <= 16:
+		return "us"
+	}
+	return "ns"
+}
+
+// epochTime returns the time of a Unix timestamp in unit
+func epochTime(timestamp int64, unit string) time.Time {
+	switch unit {
+	case "ms":
+		return time.UnixMilli(timestamp)
+	case "us":
+		return time.UnixMicro(timestamp)
+	case "ns":
+		return time.Unix(0, timestamp)
+	}
+	return time.Unix(timestamp, 0)
+}
+
+// epochTimestamp returns t as a Unix timestamp in unit
+func epochTimestamp(t time.Time, unit string) int64 {
+	switch unit {
+	case "ms":
+		return t.UnixMilli()
+	case "us":
+		return t.UnixMicro()
+	case "ns":
+		return t.UnixNano()
+	}
+	return t.Unix()
+}
@@ -0,0 +1,671 @@
+package dev
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+	"devkit/internal/output"
+)
+
+// fakeFieldNames are the fields dev fake generates, in the order they are
+// listed in the help
+var fakeFieldNames = []string{
+	"name", "first_name", "last_name", "username", "email", "phone",
+	"company", "url", "street", "city", "region", "postcode", "country",
+	"address", "ip", "ipv6", "date", "datetime", "uuid",
+}
+
+// fakeFieldAliases map alternative field names to the generated ones
+var fakeFieldAliases = map[string]string{
+	"firstname": "first_name",
+	"lastname":  "last_name",
+	"user":      "username",
+	"mail":      "email",
+	"tel":       "phone",
+	"website":   "url",
+	"state":     "region",
+	"zip":       "postcode",
+	"ipv4":      "ip",
+	"timestamp": "datetime",
+}
+
+// fakeEmailDomains are reserved for documentation (RFC 2606), so generated
+// addresses never reach a real mailbox
+var fakeEmailDomains = []string{"example.com", "example.net", "example.org"}
+
+// fakeTemplateField matches a {{field}} placeholder of --template
+var fakeTemplateField = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_]+)\s*\}\}`)
+
+// fakeCity is a city with the region it lies in and the pattern of its
+// postcodes (# digit, A uppercase letter)
+type fakeCity struct {
+	name     string
+	region   string
+	postcode string
+}
+
+// fakeLocale is the data the records of one locale are generated from.
+// Patterns use # for a digit, N for a digit from 2 to 9 and A for an
+// uppercase letter; the formats use {number}, {street}, {city}, {region}
+// and {postcode}.
+type fakeLocale struct {
+	country    string
+	firstNames []string
+	lastNames  []string
+	streets    []string
+	cities     []fakeCity
+	phone      string
+	street     string
+	address    string
+	industries []string
+	suffixes   []string
+}
+
+var fakeLocales = map[string]*fakeLocale{
+	"en_US": {
+		country: "United States",
+		firstNames: []string{
+			"James", "Mary", "Robert", "Patricia", "John", "Jennifer", "Michael", "Linda",
+			"David", "Elizabeth", "William", "Barbara", "Richard", "Susan", "Joseph", "Jessica",
+			"Thomas", "Sarah", "Daniel", "Karen", "Matthew", "Emily", "Anthony", "Ashley",
+		},
+		lastNames: []string{
+			"Smith", "Johnson", "Williams", "Brown", "Jones", "Garcia", "Miller", "Davis",
+			"Rodriguez", "Martinez", "Wilson", "Anderson", "Taylor", "Thomas", "Moore", "Jackson",
+			"Martin", "Lee", "Thompson", "White", "Harris", "Clark", "Lewis", "Walker",
+		},
+		streets: []string{
+			"Main St", "Oak Ave", "Maple Dr", "Cedar Ln", "Pine St", "Elm St", "Washington Ave",
+			"Lake Rd", "Hill St", "Park Ave", "Sunset Blvd", "River Rd", "Church St", "Highland Ave",
+		},
+		cities: []fakeCity{
+			{"Springfield", "IL", "627##"}, {"Austin", "TX", "787##"}, {"Portland", "OR", "972##"},
+			{"Columbus", "OH", "432##"}, {"Denver", "CO", "802##"}, {"Madison", "WI", "537##"},
+			{"Raleigh", "NC", "276##"}, {"Boise", "ID", "837##"}, {"Albany", "NY", "122##"},
+			{"Tucson", "AZ", "857##"}, {"Sacramento", "CA", "958##"}, {"Nashville", "TN", "372##"},
+		},
+		// 555-0100 to 555-0199 are reserved for fictional use
+		phone:      "+1 (N##) 555-01##",
+		street:     "{number} {street}",
+		address:    "{street}, {city}, {region} {postcode}",
+		industries: []string{"Consulting", "Logistics", "Software", "Foods", "Analytics", "Manufacturing", "Health"},
+		suffixes:   []string{"Inc.", "LLC", "Corp.", "Group", "Co."},
+	},
+	"en_GB": {
+		country: "United Kingdom",
+		firstNames: []string{
+			"Oliver", "Olivia", "George", "Amelia", "Harry", "Isla", "Jack", "Ava",
+			"Charlie", "Emily", "Thomas", "Sophie", "Oscar", "Grace", "William", "Lily",
+			"James", "Freya", "Alfie", "Ella", "Henry", "Poppy", "Arthur", "Evie",
+		},
+		lastNames: []string{
+			"Smith", "Jones", "Taylor", "Brown", "Williams", "Wilson", "Johnson", "Davies",
+			"Robinson", "Wright", "Thompson", "Evans", "Walker", "White", "Roberts", "Green",
+			"Hall", "Wood", "Jackson", "Clarke", "Hughes", "Edwards", "Turner", "Cooper",
+		},
+		streets: []string{
+			"High Street", "Station Road", "Church Lane", "Victoria Road", "Green Lane", "Manor Road",
+			"Park Road", "Queens Road", "Mill Lane", "King Street", "The Crescent", "Kings Road",
+		},
+		cities: []fakeCity{
+			{"London", "Greater London", "SW# #AA"}, {"Manchester", "Greater Manchester", "M## #AA"},
+			{"Birmingham", "West Midlands", "B## #AA"}, {"Leeds", "West Yorkshire", "LS# #AA"},
+			{"Bristol", "Bristol", "BS# #AA"}, {"Edinburgh", "Scotland", "EH# #AA"},
+			{"Cardiff", "Wales", "CF## #AA"}, {"Oxford", "Oxfordshire", "OX# #AA"},
+			{"York", "North Yorkshire", "YO## #AA"}, {"Brighton", "East Sussex", "BN# #AA"},
+		},
+		// 07700 900000 to 900999 are reserved for drama
+		phone:      "+44 7700 900###",
+		street:     "{number} {street}",
+		address:    "{street}, {city} {postcode}",
+		industries: []string{"Consulting", "Logistics", "Software", "Trading", "Engineering", "Estates", "Holdings"},
+		suffixes:   []string{"Ltd", "PLC", "LLP", "Group"},
+	},
+	"de_DE": {
+		country: "Deutschland",
+		firstNames: []string{
+			"Lukas", "Anna", "Maximilian", "Lea", "Jonas", "Hannah", "Leon", "Sophie",
+			"Felix", "Marie", "Paul", "Emma", "Finn", "Mia", "Jan", "Laura",
+			"Tobias", "Julia", "Niklas", "Lena", "Stefan", "Katharina", "Jürgen", "Sabine",
+		},
+		lastNames: []string{
+			"Müller", "Schmidt", "Schneider", "Fischer", "Weber", "Meyer", "Wagner", "Becker",
+			"Schulz", "Hoffmann", "Schäfer", "Koch", "Bauer", "Richter", "Klein", "Wolf",
+			"Schröder", "Neumann", "Schwarz", "Zimmermann", "Braun", "Krüger", "Hofmann", "Hartmann",
+		},
+		streets: []string{
+			"Hauptstraße", "Schulstraße", "Gartenstraße", "Bahnhofstraße", "Dorfstraße", "Bergstraße",
+			"Birkenweg", "Lindenstraße", "Kirchstraße", "Waldstraße", "Ringstraße", "Goethestraße",
+		},
+		cities: []fakeCity{
+			{"Berlin", "Berlin", "10###"}, {"Hamburg", "Hamburg", "20###"}, {"München", "Bayern", "80###"},
+			{"Köln", "Nordrhein-Westfalen", "50###"}, {"Frankfurt am Main", "Hessen", "60###"},
+			{"Stuttgart", "Baden-Württemberg", "70###"}, {"Leipzig", "Sachsen", "04###"},
+			{"Dresden", "Sachsen", "01###"}, {"Hannover", "Niedersachsen", "30###"},
+			{"Nürnberg", "Bayern", "90###"}, {"Bremen", "Bremen", "28###"},
+		},
+		phone:      "+49 15# ########",
+		street:     "{street} {number}",
+		address:    "{street}, {postcode} {city}",
+		industries: []string{"Logistik", "Bau", "Technik", "Software", "Beratung", "Handel", "Maschinenbau"},
+		suffixes:   []string{"GmbH", "AG", "GmbH & Co. KG", "KG", "e.K."},
+	},
+	"fr_FR": {
+		country: "France",
+		firstNames: []string{
+			"Gabriel", "Emma", "Louis", "Jade", "Raphaël", "Louise", "Jules", "Alice",
+			"Adam", "Chloé", "Lucas", "Léa", "Hugo", "Manon", "Arthur", "Camille",
+			"Nathan", "Inès", "Théo", "Sarah", "Paul", "Juliette", "Antoine", "Zoé",
+		},
+		lastNames: []string{
+			"Martin", "Bernard", "Thomas", "Petit", "Robert", "Richard", "Durand", "Dubois",
+			"Moreau", "Laurent", "Simon", "Michel", "Lefèvre", "Leroy", "Roux", "David",
+			"Bertrand", "Morel", "Fournier", "Girard", "Bonnet", "Dupont", "Lambert", "Fontaine",
+		},
+		streets: []string{
+			"rue de la Paix", "rue Victor Hugo", "avenue de la République", "rue du Moulin",
+			"boulevard Voltaire", "rue des Écoles", "place de la Mairie", "rue de l'Église",
+			"avenue Jean Jaurès", "rue Pasteur", "chemin des Vignes", "rue Nationale",
+		},
+		cities: []fakeCity{
+			{"Paris", "Île-de-France", "750##"}, {"Lyon", "Auvergne-Rhône-Alpes", "6900#"},
+			{"Marseille", "Provence-Alpes-Côte d'Azur", "130##"}, {"Toulouse", "Occitanie", "310##"},
+			{"Nantes", "Pays de la Loire", "440##"}, {"Bordeaux", "Nouvelle-Aquitaine", "330##"},
+			{"Lille", "Hauts-de-France", "590##"}, {"Strasbourg", "Grand Est", "670##"},
+			{"Rennes", "Bretagne", "350##"}, {"Nice", "Provence-Alpes-Côte d'Azur", "060##"},
+		},
+		phone:      "+33 6 ## ## ## ##",
+		street:     "{number} {street}",
+		address:    "{street}, {postcode} {city}",
+		industries: []string{"Conseil", "Transports", "Informatique", "Bâtiment", "Distribution", "Services"},
+		suffixes:   []string{"SA", "SARL", "SAS", "et Fils"},
+	},
+	"tr_TR": {
+		country: "Türkiye",
+		firstNames: []string{
+			"Mehmet", "Ayşe", "Mustafa", "Fatma", "Ahmet", "Emine", "Ali", "Hatice",
+			"Hüseyin", "Zeynep", "Hasan", "Elif", "İbrahim", "Meryem", "Murat", "Şerife",
+			"Emre", "Büşra", "Burak", "Özlem", "Can", "Gül", "Oğuz", "Derya",
+		},
+		lastNames: []string{
+			"Yılmaz", "Kaya", "Demir", "Şahin", "Çelik", "Yıldız", "Yıldırım", "Öztürk",
+			"Aydın", "Özdemir", "Arslan", "Doğan", "Kılıç", "Aslan", "Çetin", "Kara",
+			"Koç", "Kurt", "Özkan", "Şimşek", "Polat", "Korkmaz", "Karademir", "Erdoğan",
+		},
+		streets: []string{
+			"Atatürk Cad.", "Cumhuriyet Cad.", "İstiklal Cad.", "Gazi Sok.", "Lale Sok.",
+			"Menekşe Sok.", "Bağdat Cad.", "Çiçek Sok.", "Barış Sok.", "Okul Sok.", "Fatih Cad.",
+		},
+		cities: []fakeCity{
+			{"Kadıköy", "İstanbul", "347##"}, {"Beşiktaş", "İstanbul", "343##"},
+			{"Çankaya", "Ankara", "065##"}, {"Konak", "İzmir", "352##"}, {"Nilüfer", "Bursa", "161##"},
+			{"Muratpaşa", "Antalya", "070##"}, {"Seyhan", "Adana", "010##"},
+			{"Selçuklu", "Konya", "420##"}, {"Ortahisar", "Trabzon", "610##"},
+			{"Tepebaşı", "Eskişehir", "264##"},
+		},
+		phone:      "+90 5## ### ## ##",
+		street:     "{street} No:{number}",
+		address:    "{street}, {postcode} {city}/{region}",
+		industries: []string{"Lojistik", "İnşaat", "Teknoloji", "Gıda", "Tekstil", "Danışmanlık", "Yazılım"},
+		suffixes:   []string{"A.Ş.", "Ltd. Şti.", "ve Ortakları"},
+	},
+}
+
+// fakeTransliteration spells the letters of the supported locales in
+// ASCII for user names, email addresses and URLs
+var fakeTransliteration = strings.NewReplacer(
+	"ä", "ae", "ö", "oe", "ü", "ue", "ß", "ss", "Ä", "ae", "Ö", "oe", "Ü", "ue",
+	"é", "e", "è", "e", "ê", "e", "ë", "e", "à", "a", "â", "a", "î", "i", "ï", "i",
+	"ô", "o", "û", "u", "ù", "u", "ç", "c", "œ", "oe", "É", "e",
+	"ş", "s", "Ş", "s", "ğ", "g", "ı", "i", "İ", "i", "Ç", "c",
+)
+
+// fakeCmd represents the fake command
+var fakeCmd = &cobra.Command{
+	Use:   "fake [field...]",
+	Short: "Generate realistic test data (names, emails, addresses, ...)",
+	Long: `Generate realistic fake records for seeding databases and tests.
+
+Fields:
+  name, first_name, last_name, username, email, phone, company, url,
+  street, city, region, postcode, country, address, ip, ipv6, date,
+  datetime, uuid
+
+The fields of one record belong together: the email and user name are
+made from the name, and the city, region and postcode match. Email
+addresses use the reserved example.com domains, and US and UK phone
+numbers are in the ranges reserved for fiction. Without fields, name,
+email and phone are generated.
+
+Locales: en_US (default), en_GB, de_DE, fr_FR, tr_TR
+
+--format selects the output: csv (with a header row unless --no-header),
+json (an array of objects) or ndjson (one object per line).
+
+With --template, every record is printed as the template with each
+{{field}} replaced; values are quoted as CSV fields with --format csv and
+escaped as JSON strings with json or ndjson.
+
+Examples:
+  devkit dev fake
+  devkit dev fake name email company --count 100
+  devkit dev fake name address phone --locale de_DE --format json
+  devkit dev fake --template '{{name}},{{email}}' --count 1000 > users.csv
+  devkit dev fake --template '{"user":"{{username}}","ip":"{{ip}}"}' --format ndjson
+  devkit dev fake first_name,last_name,city --locale tr_TR --seed 42`,
+	RunE: runFake,
+}
+
+func init() {
+	devCmd.AddCommand(fakeCmd)
+
+	fakeCmd.Flags().IntP("count", "c", 1, "Number of records to generate")
+	fakeCmd.Flags().StringP("locale", "l", "en_US", "Locale: en_US, en_GB, de_DE, fr_FR, tr_TR")
+	fakeCmd.Flags().StringP("template", "t", "", "Template with {{field}} placeholders, printed once per record")
+	fakeCmd.Flags().String("format", "csv", "Record format: csv, json, ndjson")
+	fakeCmd.Flags().Bool("no-header", false, "Do not print the CSV header row")
+	fakeCmd.Flags().Int64("seed", 0, "Seed for reproducible records")
+	fakeCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
+}
+
+func runFake(cmd *cobra.Command, args []string) error {
+	count, _ := cmd.Flags().GetInt("count")
+	localeName, _ := cmd.Flags().GetString("locale")
+	template, _ := cmd.Flags().GetString("template")
+	recordFormat, _ := cmd.Flags().GetString("format")
+	noHeader, _ := cmd.Flags().GetBool("no-header")
+	outputFormat, _ := cmd.Flags().GetString("output")
+	format := output.OutputFormat(outputFormat)
+
+	if count < 1 {
+		return fmt.Errorf("count must be at least 1")
+	}
+	if count > 10000 {
+		return fmt.Errorf("count cannot exceed 10000")
+	}
+	switch recordFormat {
+	case "csv", "json", "ndjson":
+	default:
+		return fmt.Errorf("invalid format: %s (supported: csv, json, ndjson)", recordFormat)
+	}
+
+	localeName, locale, err := findFakeLocale(localeName)
+	if err != nil {
+		return err
+	}
+
+	var fields []string
+	if template != "" {
+		if len(args) > 0 {
+			return fmt.Errorf("fields and --template cannot be used together")
+		}
+		for _, m := range fakeTemplateField.FindAllStringSubmatch(template, -1) {
+			fields = append(fields, m[1])
+		}
+		if len(fields) == 0 {
+			return fmt.Errorf("template has no {{field}} placeholders")
+		}
+	} else {
+		for _, arg := range args {
+			for _, field := range strings.Split(arg, ",") {
+				if field = strings.TrimSpace(field); field != "" {
+					fields = append(fields, field)
+				}
+			}
+		}
+		if len(fields) == 0 {
+			fields = []string{"name", "email", "phone"}
+		}
+	}
+	for i, field := range fields {
+		if fields[i], err = fakeFieldName(field); err != nil {
+			return err
+		}
+	}
+
+	seed := time.Now().UnixNano()
+	if cmd.Flags().Changed("seed") {
+		seed, _ = cmd.Flags().GetInt64("seed")
+	}
+	g := &fakeGenerator{r: rand.New(rand.NewSource(seed)), locale: locale}
+
+	records := make([]*fakeRecord, count)
+	for i := range records {
+		records[i] = g.record()
+		// Generate the fields in order so that --seed is reproducible
+		for _, field := range fields {
+			records[i].get(field)
+		}
+	}
+
+	var lines []string
+	if template != "" {
+		for _, rec := range records {
+			lines = append(lines, fakeTemplateField.ReplaceAllStringFunc(template, func(m string) string {
+				field, _ := fakeFieldName(fakeTemplateField.FindStringSubmatch(m)[1])
+				return escapeFakeValue(rec.get(field), recordFormat)
+			}))
+		}
+	}
+
+	if format == output.FormatJSON {
+		result := map[string]interface{}{
+			"locale": localeName,
+			"count":  count,
+			"fields": fields,
+		}
+		if cmd.Flags().Changed("seed") {
+			result["seed"] = seed
+		}
+		if template != "" {
+			result["template"] = template
+			result["lines"] = lines
+		} else {
+			values := make([]map[string]string, count)
+			for i, rec := range records {
+				values[i] = make(map[string]string, len(fields))
+				for _, field := range fields {
+					values[i][field] = rec.get(field)
+				}
+			}
+			result["records"] = values
+		}
+		output.PrintSuccess(format, result)
+		return nil
+	}
+
+	if template != "" {
+		for _, line := range lines {
+			fmt.Println(line)
+		}
+		return nil
+	}
+
+	switch recordFormat {
+	case "csv":
+		w := csv.NewWriter(os.Stdout)
+		if !noHeader {
+			w.Write(fields)
+		}
+		for _, rec := range records {
+			row := make([]string, len(fields))
+			for i, field := range fields {
+				row[i] = rec.get(field)
+			}
+			w.Write(row)
+		}
+		w.Flush()
+		return w.Error()
+
+	case "ndjson":
+		for _, rec := range records {
+			fmt.Println(rec.json(fields))
+		}
+
+	default:
+		var b bytes.Buffer
+		b.WriteString("[")
+		for i, rec := range records {
+			if i > 0 {
+				b.WriteString(",")
+			}
+			b.WriteString(rec.json(fields))
+		}
+		b.WriteString("]")
+		var pretty bytes.Buffer
+		if err := json.Indent(&pretty, b.Bytes(), "", "  "); err != nil {
+			return fmt.Errorf("failed to encode records: %w", err)
+		}
+		fmt.Println(pretty.String())
+	}
+	return nil
+}
+
+// findFakeLocale accepts en_US, en-us or just en
+func findFakeLocale(name string) (string, *fakeLocale, error) {
+	lang, region, _ := strings.Cut(strings.ReplaceAll(name, "-", "_"), "_")
+	key := strings.ToLower(lang) + "_" + strings.ToUpper(region)
+	if locale, ok := fakeLocales[key]; ok {
+		return key, locale, nil
+	}
+
+	names := make([]string, 0, len(fakeLocales))
+	for n := range fakeLocales {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	if region == "" {
+		// A language alone picks its locale; English is en_US
+		if strings.EqualFold(lang, "en") {
+			return "en_US", fakeLocales["en_US"], nil
+		}
+		for _, n := range names {
+			if strings.HasPrefix(n, strings.ToLower(lang)+"_") {
+				return n, fakeLocales[n], nil
+			}
+		}
+	}
+	return "", nil, fmt.Errorf("unsupported locale: %s (supported: %s)", name, strings.Join(names, ", "))
+}
+
+func fakeFieldName(field string) (string, error) {
+	field = strings.ToLower(strings.ReplaceAll(field, "-", "_"))
+	if alias, ok := fakeFieldAliases[field]; ok {
+		return alias, nil
+	}
+	for _, name := range fakeFieldNames {
+		if name == field {
+			return field, nil
+		}
+	}
+	return "", fmt.Errorf("unknown field: %s (supported: %s)", field, strings.Join(fakeFieldNames, ", "))
+}
+
+// escapeFakeValue makes a value safe to put in a template line of the
+// given record format
+func escapeFakeValue(value, recordFormat string) string {
+	switch recordFormat {
+	case "csv":
+		if strings.ContainsAny(value, ",\"\r\n") {
+			return `"` + strings.ReplaceAll(value, `"`, `""`) + `"`
+		}
+		return value
+	default:
+		data, _ := json.Marshal(value)
+		return string(data[1 : len(data)-1])
+	}
+}
+
+// fakeGenerator creates records from a locale using a seeded random source
+type fakeGenerator struct {
+	r      *rand.Rand
+	locale *fakeLocale
+}
+
+// fakeRecord is one generated record. Fields are generated on first use and
+// kept, so that the email of a record is made from its name.
+type fakeRecord struct {
+	g      *fakeGenerator
+	values map[string]string
+	city   *fakeCity
+}
+
+func (g *fakeGenerator) record() *fakeRecord {
+	return &fakeRecord{g: g, values: make(map[string]string)}
+}
+
+func (g *fakeGenerator) pick(list []string) string {
+	return list[g.r.Intn(len(list))]
+}
+
+// fill replaces the placeholders of a phone or postcode pattern
+func (g *fakeGenerator) fill(pattern string) string {
+	var b strings.Builder
+	for _, r := range pattern {
+		switch r {
+		case '#':
+			b.WriteByte(byte('0' + g.r.Intn(10)))
+		case 'N':
+			b.WriteByte(byte('2' + g.r.Intn(8)))
+		case 'A':
+			b.WriteByte(byte('A' + g.r.Intn(26)))
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func (rec *fakeRecord) get(field string) string {
+	if v, ok := rec.values[field]; ok {
+		return v
+	}
+	v := rec.generate(field)
+	rec.values[field] = v
+	return v
+}
+
+func (rec *fakeRecord) generate(field string) string {
+	g, l := rec.g, rec.g.locale
+	switch field {
+	case "first_name":
+		return g.pick(l.firstNames)
+	case "last_name":
+		return g.pick(l.lastNames)
+	case "name":
+		return rec.get("first_name") + " " + rec.get("last_name")
+	case "username":
+		first, last := asciiSlug(rec.get("first_name")), asciiSlug(rec.get("last_name"))
+		switch g.r.Intn(4) {
+		case 0:
+			return first[:1] + last
+		case 1:
+			return first + "." + last
+		case 2:
+			return first + "_" + last + fmt.Sprint(g.r.Intn(100))
+		default:
+			return first + last[:1] + fmt.Sprint(1970+g.r.Intn(40))
+		}
+	case "email":
+		first, last := asciiSlug(rec.get("first_name")), asciiSlug(rec.get("last_name"))
+		local := first + "." + last
+		if g.r.Intn(3) == 0 {
+			local = first[:1] + last + fmt.Sprint(g.r.Intn(100))
+		}
+		return local + "@" + g.pick(fakeEmailDomains)
+	case "phone":
+		return g.fill(l.phone)
+	case "company":
+		switch g.r.Intn(3) {
+		case 0:
+			return g.pick(l.lastNames) + " " + g.pick(l.suffixes)
+		case 1:
+			return g.pick(l.lastNames) + " & " + g.pick(l.lastNames) + " " + g.pick(l.suffixes)
+		default:
+			return g.pick(l.lastNames) + " " + g.pick(l.industries) + " " + g.pick(l.suffixes)
+		}
+	case "url":
+		name := asciiSlug(strings.Fields(rec.get("company"))[0])
+		return "https://www." + name + ".example.com"
+	case "street":
+		return strings.NewReplacer(
+			"{number}", fmt.Sprint(1+g.r.Intn(199)),
+			"{street}", g.pick(l.streets),
+		).Replace(l.street)
+	case "city":
+		return rec.pickCity().name
+	case "region":
+		return rec.pickCity().region
+	case "postcode":
+		return g.fill(rec.pickCity().postcode)
+	case "country":
+		return l.country
+	case "address":
+		return strings.NewReplacer(
+			"{street}", rec.get("street"),
+			"{city}", rec.get("city"),
+			"{region}", rec.get("region"),
+			"{postcode}", rec.get("postcode"),
+		).Replace(l.address)
+	case "ip":
+		return fmt.Sprintf("%d.%d.%d.%d", 1+g.r.Intn(223), g.r.Intn(256), g.r.Intn(256), 1+g.r.Intn(254))
+	case "ipv6":
+		// Global unicast addresses are in 2000::/3
+		return fmt.Sprintf("%x:%x:%x:%x:%x:%x:%x:%x", 0x2000+g.r.Intn(0x2000),
+			g.r.Intn(0x10000), g.r.Intn(0x10000), g.r.Intn(0x10000),
+			g.r.Intn(0x10000), g.r.Intn(0x10000), g.r.Intn(0x10000), 1+g.r.Intn(0xffff))
+	case "date":
+		return rec.time().Format("2006-01-02")
+	case "datetime":
+		return rec.time().Format(time.RFC3339)
+	case "uuid":
+		var b [16]byte
+		g.r.Read(b[:])
+		// Set the version 4 and RFC 4122 variant bits
+		b[6] = (b[6] & 0x0f) | 0x40
+		b[8] = (b[8] & 0x3f) | 0x80
+		id, _ := uuid.FromBytes(b[:])
+		return id.String()
+	}
+	return ""
+}
+
+func (rec *fakeRecord) pickCity() *fakeCity {
+	if rec.city == nil {
+		cities := rec.g.locale.cities
+		rec.city = &cities[rec.g.r.Intn(len(cities))]
+	}
+	return rec.city
+}
+
+// time is shared by date and datetime, so both describe the same moment
+func (rec *fakeRecord) time() time.Time {
+	if v, ok := rec.values["datetime"]; ok {
+		t, _ := time.Parse(time.RFC3339, v)
+		return t
+	}
+	base := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	t := base.Add(time.Duration(rec.g.r.Int63n(int64(5 * 365 * 24 * time.Hour)))).Truncate(time.Second)
+	rec.values["datetime"] = t.Format(time.RFC3339)
+	return t
+}
+
+// json encodes the fields of the record as an object, in field order
+func (rec *fakeRecord) json(fields []string) string {
+	var b strings.Builder
+	b.WriteString("{")
+	for i, field := range fields {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		key, _ := json.Marshal(field)
+		value, _ := json.Marshal(rec.get(field))
+		b.Write(key)
+		b.WriteString(":")
+		b.Write(value)
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+// asciiSlug spells a name in lowercase ASCII letters and digits
+func asciiSlug(s string) string {
+	s = fakeTransliteration.Replace(s)
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,156 @@
+package dev
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"devkit/internal/output"
+)
+
+// gomodBumpCmd represents the gomod bump subcommand
+var gomodBumpCmd = &cobra.Command{
+	Use:   "bump <module>...",
+	Short: "Upgrade modules to their latest minor or patch release",
+	Long: `Upgrade modules to their latest minor release (--minor, the default) or
+their latest patch release of the current minor version (--patch) with go
+get, and report the versions before and after.
+
+A major version is a different module path in Go (example.com/mod/v2), so a
+bump never crosses one. Modules are never downgraded: a module already newer
+than its latest release is left alone. Other modules that go get upgrades
+to satisfy the new requirements are listed as well.
+
+Examples:
+  devkit dev gomod bump github.com/spf13/cobra
+  devkit dev gomod bump --patch golang.org/x/net golang.org/x/crypto
+  devkit dev gomod bump --minor github.com/spf13/viper --dry-run
+  devkit dev gomod bump golang.org/x/sys --tidy --output json`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runGomodBump,
+}
+
+func init() {
+	gomodCmd.AddCommand(gomodBumpCmd)
+
+	gomodBumpCmd.Flags().Bool("minor", false, "Upgrade to the latest minor release (default)")
+	gomodBumpCmd.Flags().Bool("patch", false, "Upgrade to the latest patch release of the current minor version")
+	gomodBumpCmd.Flags().Bool("dry-run", false, "Print the upgrades without changing go.mod")
+	gomodBumpCmd.Flags().Bool("tidy", false, "Run go mod tidy after upgrading")
+	gomodBumpCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
+	gomodBumpCmd.MarkFlagsMutuallyExclusive("minor", "patch")
+}
+
+// gomodUpdate is the version change of one module
+type gomodUpdate struct {
+	Module string `json:"module"`
+	From   string `json:"from"`
+	To     string `json:"to"`
+}
+
+func runGomodBump(cmd *cobra.Command, args []string) error {
+	dir, _ := cmd.Flags().GetString("dir")
+	patch, _ := cmd.Flags().GetBool("patch")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	tidy, _ := cmd.Flags().GetBool("tidy")
+	outputFormat, _ := cmd.Flags().GetString("output")
+	format := output.OutputFormat(outputFormat)
+
+	// "upgrade" is the latest release, unlike "latest" never older than the
+	// current version
+	query, level := "upgrade", "minor"
+	if patch {
+		query, level = "patch", "patch"
+	}
+
+	cmd.SilenceUsage = true
+	if _, err := gomodMain(dir); err != nil {
+		return err
+	}
+	before, err := gomodVersions(dir)
+	if err != nil {
+		return err
+	}
+
+	updates := []gomodUpdate{}
+	var targets []string
+	for _, module := range args {
+		current, ok := before[module]
+		if !ok {
+			return fmt.Errorf("%s is not a dependency of this module", module)
+		}
+		resolved, err := listGoModules(dir, module+"@"+query)
+		if err != nil {
+			return err
+		}
+		if len(resolved) == 0 {
+			return fmt.Errorf("no %s release found for %s", level, module)
+		}
+		update := gomodUpdate{Module: module, From: current, To: resolved[0].Version}
+		updates = append(updates, update)
+		if update.To != update.From {
+			targets = append(targets, module+"@"+update.To)
+		}
+	}
+
+	also := []gomodUpdate{}
+	if !dryRun && len(targets) > 0 {
+		if _, err := runGo(dir, append([]string{"get"}, targets...)...); err != nil {
+			return err
+		}
+		if tidy {
+			if _, err := runGo(dir, "mod", "tidy"); err != nil {
+				return err
+			}
+		}
+		after, err := gomodVersions(dir)
+		if err != nil {
+			return err
+		}
+		requested := make(map[string]bool, len(args))
+		for _, module := range args {
+			requested[module] = true
+		}
+		for module, version := range after {
+			if from := before[module]; from != version && !requested[module] {
+				also = append(also, gomodUpdate{Module: module, From: from, To: version})
+			}
+		}
+		sort.Slice(also, func(i, j int) bool { return also[i].Module < also[j].Module })
+	}
+
+	if format == output.FormatJSON {
+		output.PrintSuccess(format, map[string]interface{}{
+			"level":        level,
+			"dry_run":      dryRun,
+			"updated":      len(targets),
+			"updates":      updates,
+			"also_updated": also,
+		})
+		return nil
+	}
+
+	for _, u := range updates {
+		if u.From == u.To {
+			fmt.Printf("%s %s\n", u.Module, output.Muted(u.From+" (already the latest "+level+" release)"))
+			continue
+		}
+		fmt.Println(output.OK(fmt.Sprintf("%s %s -> %s", u.Module, u.From, output.Accent(u.To))))
+	}
+	if len(also) > 0 {
+		fmt.Println()
+		fmt.Println(output.Accent("Also updated:"))
+		for _, u := range also {
+			from := u.From
+			if from == "" {
+				from = "(new)"
+			}
+			fmt.Printf("  %s %s -> %s\n", u.Module, from, u.To)
+		}
+	}
+	if dryRun && len(targets) > 0 {
+		fmt.Println()
+		fmt.Println(output.Muted("Dry run: go.mod was not changed"))
+	}
+	return nil
+}
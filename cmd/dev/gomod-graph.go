@@ -0,0 +1,204 @@
+package dev
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"devkit/internal/output"
+)
+
+// gomodGraphCmd represents the gomod graph subcommand
+var gomodGraphCmd = &cobra.Command{
+	Use:   "graph",
+	Short: "Print the module requirement graph, or why a module is needed",
+	Long: `Print the module requirement graph (go mod graph) as edges, or with --why
+explain why a module is in the build: the shortest chain of requirements
+from the main module, every module that requires it, and the chain of
+package imports that needs it (go mod why -m).
+
+A module that no package of the main module imports is still in the build
+when some requirement lists it; --why then reports that no package needs it.
+
+Examples:
+  devkit dev gomod graph
+  devkit dev gomod graph --why golang.org/x/text
+  devkit dev gomod graph --why github.com/pkg/errors --output json`,
+	Args: cobra.NoArgs,
+	RunE: runGomodGraph,
+}
+
+func init() {
+	gomodCmd.AddCommand(gomodGraphCmd)
+
+	gomodGraphCmd.Flags().String("why", "", "Explain why this module is needed")
+	gomodGraphCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
+}
+
+// gomodEdge is one requirement of the module graph
+type gomodEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+func runGomodGraph(cmd *cobra.Command, args []string) error {
+	dir, _ := cmd.Flags().GetString("dir")
+	why, _ := cmd.Flags().GetString("why")
+	outputFormat, _ := cmd.Flags().GetString("output")
+	format := output.OutputFormat(outputFormat)
+
+	cmd.SilenceUsage = true
+	mainModule, err := gomodMain(dir)
+	if err != nil {
+		return err
+	}
+	out, err := runGo(dir, "mod", "graph")
+	if err != nil {
+		return err
+	}
+	edges := parseGomodGraph(string(out))
+
+	if why == "" {
+		if format == output.FormatJSON {
+			output.PrintSuccess(format, map[string]interface{}{
+				"module":  mainModule.Path,
+				"modules": len(gomodNodes(edges)),
+				"edges":   edges,
+			})
+			return nil
+		}
+		for _, e := range edges {
+			fmt.Printf("%s %s\n", e.From, e.To)
+		}
+		return nil
+	}
+
+	target, _, _ := strings.Cut(why, "@")
+	chain := gomodChain(edges, mainModule.Path, target)
+	if chain == nil {
+		return fmt.Errorf("%s is not in the module graph of %s", target, mainModule.Path)
+	}
+
+	requiredBy := []string{}
+	seen := make(map[string]bool)
+	for _, e := range edges {
+		if gomodPath(e.To) == target && !seen[e.From] {
+			seen[e.From] = true
+			requiredBy = append(requiredBy, e.From)
+		}
+	}
+	sort.Strings(requiredBy)
+
+	selected := ""
+	if modules, err := listGoModules(dir, target); err == nil && len(modules) > 0 {
+		selected = modules[0].Version
+	}
+
+	whyOut, err := runGo(dir, "mod", "why", "-m", target)
+	if err != nil {
+		return err
+	}
+	packages := []string{}
+	for _, line := range strings.Split(string(whyOut), "\n") {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "#") && !strings.HasPrefix(line, "(") {
+			packages = append(packages, line)
+		}
+	}
+
+	if format == output.FormatJSON {
+		output.PrintSuccess(format, map[string]interface{}{
+			"module":      target,
+			"version":     selected,
+			"needed":      len(packages) > 0,
+			"chain":       chain,
+			"required_by": requiredBy,
+			"packages":    packages,
+		})
+		return nil
+	}
+
+	fmt.Printf("Module: %s", target)
+	if selected != "" {
+		fmt.Printf(" %s", output.Muted(selected))
+	}
+	fmt.Println()
+	fmt.Println()
+	fmt.Println(output.Accent("Requirement chain:"))
+	for i, node := range chain {
+		fmt.Printf("%s%s\n", strings.Repeat("  ", i), node)
+	}
+	fmt.Println()
+	fmt.Println(output.Accent(fmt.Sprintf("Required by (%d):", len(requiredBy))))
+	for _, from := range requiredBy {
+		fmt.Printf("  %s\n", from)
+	}
+	fmt.Println()
+	if len(packages) == 0 {
+		fmt.Println(output.Warn("No package of the main module imports it"))
+		return nil
+	}
+	fmt.Println(output.Accent("Imported through:"))
+	for i, pkg := range packages {
+		fmt.Printf("%s%s\n", strings.Repeat("  ", i), pkg)
+	}
+	return nil
+}
+
+// parseGomodGraph reads the "from to" lines of go mod graph
+func parseGomodGraph(text string) []gomodEdge {
+	edges := []gomodEdge{}
+	for _, line := range strings.Split(text, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 {
+			edges = append(edges, gomodEdge{From: fields[0], To: fields[1]})
+		}
+	}
+	return edges
+}
+
+// gomodNodes returns the distinct modules of the graph
+func gomodNodes(edges []gomodEdge) map[string]bool {
+	nodes := make(map[string]bool)
+	for _, e := range edges {
+		nodes[e.From] = true
+		nodes[e.To] = true
+	}
+	return nodes
+}
+
+// gomodPath strips the version from a module@version node
+func gomodPath(node string) string {
+	path, _, _ := strings.Cut(node, "@")
+	return path
+}
+
+// gomodChain finds the shortest chain of requirements from the main module
+// to any version of target, or nil when the graph does not reach it
+func gomodChain(edges []gomodEdge, main, target string) []string {
+	next := make(map[string][]string)
+	for _, e := range edges {
+		next[e.From] = append(next[e.From], e.To)
+	}
+
+	parent := map[string]string{main: ""}
+	queue := []string{main}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		if gomodPath(node) == target && node != main {
+			chain := []string{}
+			for ; node != ""; node = parent[node] {
+				chain = append([]string{node}, chain...)
+			}
+			return chain
+		}
+		for _, to := range next[node] {
+			if _, ok := parent[to]; !ok {
+				parent[to] = node
+				queue = append(queue, to)
+			}
+		}
+	}
+	return nil
+}
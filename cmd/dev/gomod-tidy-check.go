@@ -0,0 +1,118 @@
+package dev
+
+import (
+	stderrors "errors"
+	"fmt"
+	"os/exec"
+	"path"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"devkit/internal/errors"
+	"devkit/internal/output"
+)
+
+// gomodTidyCheckCmd represents the gomod tidy-check subcommand
+var gomodTidyCheckCmd = &cobra.Command{
+	Use:   "tidy-check",
+	Short: "Fail when go mod tidy would change go.mod or go.sum",
+	Long: `Check that go.mod and go.sum are tidy, without changing them: run go mod
+tidy -diff (Go 1.23 or newer) and report the requirements it would add or
+remove.
+
+Exits with 0 when the files are tidy and 2 when go mod tidy would change
+them, so it can gate CI.
+
+Examples:
+  devkit dev gomod tidy-check
+  devkit dev gomod tidy-check --dir ./services/api
+  devkit dev gomod tidy-check --output json`,
+	Args: cobra.NoArgs,
+	RunE: runGomodTidyCheck,
+}
+
+func init() {
+	gomodCmd.AddCommand(gomodTidyCheckCmd)
+
+	gomodTidyCheckCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
+}
+
+// gomodFileChange is what go mod tidy would change in one file
+type gomodFileChange struct {
+	File    string   `json:"file"`
+	Added   []string `json:"added"`
+	Removed []string `json:"removed"`
+}
+
+func runGomodTidyCheck(cmd *cobra.Command, args []string) error {
+	dir, _ := cmd.Flags().GetString("dir")
+	outputFormat, _ := cmd.Flags().GetString("output")
+	format := output.OutputFormat(outputFormat)
+
+	cmd.SilenceUsage = true
+	if _, err := gomodMain(dir); err != nil {
+		return err
+	}
+
+	// go mod tidy -diff exits with 1 and prints the diff when not tidy
+	out, err := runGo(dir, "mod", "tidy", "-diff")
+	var exitErr *exec.ExitError
+	if err != nil && !(stderrors.As(err, &exitErr) && len(out) > 0) {
+		return err
+	}
+	changes := parseTidyDiff(string(out))
+
+	if format == output.FormatJSON {
+		output.PrintSuccess(format, map[string]interface{}{
+			"tidy":    len(changes) == 0,
+			"changes": changes,
+			"diff":    string(out),
+		})
+	} else if len(changes) == 0 {
+		fmt.Println(output.OK("go.mod and go.sum are tidy"))
+	} else {
+		for _, c := range changes {
+			fmt.Println(output.Fail(fmt.Sprintf("go mod tidy would change %s (%d added, %d removed)", c.File, len(c.Added), len(c.Removed))))
+			// go.sum changes are checksums, only counted
+			if c.File != "go.mod" {
+				continue
+			}
+			for _, line := range c.Removed {
+				fmt.Println(output.Failure("  - " + line))
+			}
+			for _, line := range c.Added {
+				fmt.Println(output.Success("  + " + line))
+			}
+		}
+	}
+
+	if len(changes) > 0 {
+		return &errors.ExitError{Code: errors.ExitInvalid, Err: fmt.Errorf("go.mod or go.sum is not tidy; run go mod tidy")}
+	}
+	return nil
+}
+
+// parseTidyDiff reads the unified diff of go mod tidy -diff into the lines
+// added and removed per file
+func parseTidyDiff(diff string) []gomodFileChange {
+	changes := []gomodFileChange{}
+	var current *gomodFileChange
+	for _, line := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "diff "):
+			fields := strings.Fields(line)
+			changes = append(changes, gomodFileChange{File: path.Base(fields[len(fields)-1]), Added: []string{}, Removed: []string{}})
+			current = &changes[len(changes)-1]
+		case current == nil, strings.HasPrefix(line, "--- "), strings.HasPrefix(line, "+++ "), strings.HasPrefix(line, "@@"):
+		case strings.HasPrefix(line, "+"):
+			if text := strings.TrimSpace(line[1:]); text != "" {
+				current.Added = append(current.Added, text)
+			}
+		case strings.HasPrefix(line, "-"):
+			if text := strings.TrimSpace(line[1:]); text != "" {
+				current.Removed = append(current.Removed, text)
+			}
+		}
+	}
+	return changes
+}
@@ -0,0 +1,119 @@
+package dev
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// gomodCmd represents the gomod command group
+var gomodCmd = &cobra.Command{
+	Use:   "gomod",
+	Short: "Go module helpers",
+	Long: `Inspect and update the dependencies of a Go module, with JSON output for CI.
+
+The commands run the go tool found in PATH in the module of the current
+directory, or of --dir.
+
+Examples:
+  devkit dev gomod graph --why golang.org/x/text
+  devkit dev gomod bump --minor github.com/spf13/cobra
+  devkit dev gomod tidy-check
+  devkit dev gomod tidy-check --dir ./services/api --output json`,
+}
+
+func init() {
+	devCmd.AddCommand(gomodCmd)
+
+	gomodCmd.PersistentFlags().StringP("dir", "C", "", "Directory of the module (default: the current directory)")
+}
+
+// goModule is a module as printed by go list -m -json
+type goModule struct {
+	Path     string
+	Version  string
+	Main     bool
+	Indirect bool
+	Dir      string
+	GoMod    string
+	Update   *goModule
+	Replace  *goModule
+}
+
+// runGo runs the go tool in dir and returns its standard output; on failure
+// the error carries what go printed to standard error
+func runGo(dir string, args ...string) ([]byte, error) {
+	if _, err := exec.LookPath("go"); err != nil {
+		return nil, fmt.Errorf("go is not installed or not in PATH")
+	}
+	c := exec.Command("go", args...)
+	c.Dir = dir
+	var stderr bytes.Buffer
+	c.Stderr = &stderr
+	out, err := c.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return out, fmt.Errorf("go %s: %s", strings.Join(args[:min(2, len(args))], " "), msg)
+		}
+		return out, fmt.Errorf("go %s: %w", strings.Join(args[:min(2, len(args))], " "), err)
+	}
+	return out, nil
+}
+
+// gomodMain returns the main module of dir, failing outside a module
+func gomodMain(dir string) (*goModule, error) {
+	out, err := runGo(dir, "env", "GOMOD")
+	if err != nil {
+		return nil, err
+	}
+	if gomod := strings.TrimSpace(string(out)); gomod == "" || gomod == "/dev/null" || gomod == "NUL" {
+		return nil, fmt.Errorf("not in a Go module (no go.mod found)")
+	}
+	modules, err := listGoModules(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range modules {
+		if m.Main {
+			return m, nil
+		}
+	}
+	return nil, fmt.Errorf("no main module found")
+}
+
+// listGoModules runs go list -m -json with args (the main module when none)
+func listGoModules(dir string, args ...string) ([]*goModule, error) {
+	out, err := runGo(dir, append([]string{"list", "-m", "-json"}, args...)...)
+	if err != nil {
+		return nil, err
+	}
+	var modules []*goModule
+	decoder := json.NewDecoder(bytes.NewReader(out))
+	for decoder.More() {
+		m := &goModule{}
+		if err := decoder.Decode(m); err != nil {
+			return nil, fmt.Errorf("failed to read go list output: %w", err)
+		}
+		modules = append(modules, m)
+	}
+	return modules, nil
+}
+
+// gomodVersions maps each module of the build list to its version
+func gomodVersions(dir string) (map[string]string, error) {
+	modules, err := listGoModules(dir, "all")
+	if err != nil {
+		return nil, err
+	}
+	versions := make(map[string]string, len(modules))
+	for _, m := range modules {
+		if !m.Main {
+			versions[m.Path] = m.Version
+		}
+	}
+	return versions, nil
+}
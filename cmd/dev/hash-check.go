@@ -0,0 +1,185 @@
+package dev
+
+import (
+	"bufio"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"devkit/internal/output"
+)
+
+// checkResult is the outcome of verifying one manifest line
+type checkResult struct {
+	File     string `json:"file"`
+	Expected string `json:"expected"`
+	Actual   string `json:"actual,omitempty"`
+	Status   string `json:"status"` // ok, failed, missing
+	Error    string `json:"error,omitempty"`
+}
+
+// bsdChecksumLine matches the BSD tag format: SHA256 (file) = hash
+var bsdChecksumLine = regexp.MustCompile(`^([A-Za-z0-9-]+) \((.*)\) = ([0-9a-fA-F]+)$`)
+
+// runHashCheck verifies the files listed in a checksum manifest, like
+// sha256sum -c. The manifest holds "<hash>  <file>" lines (GNU format, a
+// "*" before the name marks binary mode) or "SHA256 (file) = <hash>" lines
+// (BSD format). "-" reads the manifest from stdin.
+func runHashCheck(cmd *cobra.Command, algorithm, manifest string) error {
+	ignoreMissing, _ := cmd.Flags().GetBool("ignore-missing")
+	outputFormat, _ := cmd.Flags().GetString("output")
+	format := output.OutputFormat(outputFormat)
+
+	newHash, ok := hashAlgorithms[algorithm]
+	if !ok {
+		return fmt.Errorf("unsupported algorithm: %s (supported: %s)", algorithm, strings.Join(hashAlgorithmNames, ", "))
+	}
+	if key, _ := cmd.Flags().GetString("hmac-key"); key != "" {
+		return fmt.Errorf("--check cannot be combined with --hmac-key")
+	}
+	if keyFile, _ := cmd.Flags().GetString("hmac-key-file"); keyFile != "" {
+		return fmt.Errorf("--check cannot be combined with --hmac-key-file")
+	}
+
+	var r io.Reader = os.Stdin
+	if manifest != "-" {
+		file, err := os.Open(manifest)
+		if err != nil {
+			return fmt.Errorf("failed to open manifest: %w", err)
+		}
+		defer file.Close()
+		r = file
+	}
+
+	var results []checkResult
+	malformed := 0
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		expected, name, ok := parseChecksumLine(line)
+		if !ok {
+			malformed++
+			continue
+		}
+
+		result := checkResult{File: name, Expected: strings.ToLower(expected)}
+		actual, err := hashFile(name, newHash)
+		switch {
+		case os.IsNotExist(err) && ignoreMissing:
+			continue
+		case err != nil:
+			result.Status = "missing"
+			result.Error = err.Error()
+		case actual == result.Expected:
+			result.Status = "ok"
+			result.Actual = actual
+		default:
+			result.Status = "failed"
+			result.Actual = actual
+		}
+		results = append(results, result)
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read manifest: %w", err)
+	}
+
+	counts := map[string]int{"ok": 0, "failed": 0, "missing": 0}
+	for _, result := range results {
+		counts[result.Status]++
+	}
+
+	if format == output.FormatJSON {
+		output.PrintSuccess(format, map[string]interface{}{
+			"algorithm": algorithm,
+			"manifest":  manifest,
+			"results":   results,
+			"ok":        counts["ok"],
+			"failed":    counts["failed"],
+			"missing":   counts["missing"],
+			"malformed": malformed,
+		})
+	} else {
+		for _, result := range results {
+			switch result.Status {
+			case "ok":
+				fmt.Printf("%s: OK\n", result.File)
+			case "failed":
+				fmt.Printf("%s: FAILED\n", result.File)
+			default:
+				fmt.Printf("%s: FAILED open or read\n", result.File)
+			}
+		}
+		if malformed > 0 {
+			fmt.Fprintf(os.Stderr, "WARNING: %d line(s) are improperly formatted\n", malformed)
+		}
+		if counts["missing"] > 0 {
+			fmt.Fprintf(os.Stderr, "WARNING: %d listed file(s) could not be read\n", counts["missing"])
+		}
+		if counts["failed"] > 0 {
+			fmt.Fprintf(os.Stderr, "WARNING: %d computed checksum(s) did NOT match\n", counts["failed"])
+		}
+	}
+
+	if len(results) == 0 {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("no properly formatted checksum lines found")
+	}
+	if counts["failed"] > 0 || counts["missing"] > 0 {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("%d of %d files failed verification", counts["failed"]+counts["missing"], len(results))
+	}
+	return nil
+}
+
+// parseChecksumLine splits a GNU or BSD style manifest line into the
+// expected hash and the file name
+func parseChecksumLine(line string) (string, string, bool) {
+	if m := bsdChecksumLine.FindStringSubmatch(line); m != nil {
+		return m[3], m[2], true
+	}
+
+	// GNU format: the hash, a space, then a space (text) or * (binary). A
+	// leading backslash marks a file name with escaped \\ and \n.
+	escaped := strings.HasPrefix(line, "\\")
+	if escaped {
+		line = line[1:]
+	}
+	i := strings.IndexByte(line, ' ')
+	if i <= 0 || i+2 > len(line) {
+		return "", "", false
+	}
+	expected, name := line[:i], line[i+2:]
+	if line[i+1] != ' ' && line[i+1] != '*' {
+		return "", "", false
+	}
+	if _, err := hex.DecodeString(expected); err != nil || name == "" {
+		return "", "", false
+	}
+	if escaped {
+		name = strings.NewReplacer("\\\\", "\\", "\\n", "\n").Replace(name)
+	}
+	return expected, name, true
+}
+
+// hashFile streams a file through a new hash and returns the hex digest
+func hashFile(path string, newHash func() hash.Hash) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	h := newHash()
+	if _, err := io.Copy(h, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
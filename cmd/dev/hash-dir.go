@@ -0,0 +1,146 @@
+package dev
+
+import (
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"devkit/internal/matcher"
+	"devkit/internal/output"
+)
+
+// hashDirCmd represents the hash dir subcommand
+var hashDirCmd = &cobra.Command{
+	Use:   "dir [path]",
+	Short: "Compute a deterministic hash of a directory tree",
+	Long: `Compute one hash over the files in a directory tree, so that build outputs
+can be compared across machines.
+
+Every file is hashed, then the tree hash is the hash of the sorted manifest
+of "<file hash>  <relative path>" lines (paths use forward slashes), the
+same lines sha256sum prints, so it can be reproduced without devkit. The
+result depends only on relative paths and file contents: modification
+times, permissions, empty directories and symbolic links are ignored. Use
+--list to print the manifest, e.g. to find which file differs.
+--ignore-profile leaves out the files a toolchain generates, such as
+node_modules or __pycache__.
+
+Examples:
+  devkit dev hash dir ./build
+  devkit dev hash dir ./dist --algorithm blake2b-256
+  devkit dev hash dir . --include "src/**" --exclude "*.log" --exclude node_modules
+  devkit dev hash dir ./build --list
+  devkit dev hash dir . --ignore-profile node,build-artifacts`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runHashDir,
+}
+
+func init() {
+	hashCmd.AddCommand(hashDirCmd)
+
+	hashDirCmd.Flags().StringP("algorithm", "a", "sha256", "Hash algorithm: "+strings.Join(hashAlgorithmNames, ", "))
+	hashDirCmd.Flags().StringArray("include", nil, "Only hash files matching a glob pattern (repeatable)")
+	hashDirCmd.Flags().StringArray("exclude", nil, "Skip files and directories matching a glob pattern (repeatable)")
+	hashDirCmd.Flags().StringSlice("ignore-profile", nil, "Skip the files generated by a toolchain: "+strings.Join(matcher.ProfileNames(), ", ")+" (comma-separated or repeated)")
+	hashDirCmd.Flags().BoolP("list", "l", false, "Also print the per-file manifest")
+	hashDirCmd.Flags().IntP("jobs", "j", runtime.NumCPU(), "Number of files hashed in parallel")
+	hashDirCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
+}
+
+func runHashDir(cmd *cobra.Command, args []string) error {
+	root := "."
+	if len(args) > 0 {
+		root = args[0]
+	}
+	algorithm, _ := cmd.Flags().GetString("algorithm")
+	include, _ := cmd.Flags().GetStringArray("include")
+	exclude, _ := cmd.Flags().GetStringArray("exclude")
+	profiles, _ := cmd.Flags().GetStringSlice("ignore-profile")
+	list, _ := cmd.Flags().GetBool("list")
+	jobs, _ := cmd.Flags().GetInt("jobs")
+	outputFormat, _ := cmd.Flags().GetString("output")
+	format := output.OutputFormat(outputFormat)
+
+	newHash, ok := hashAlgorithms[algorithm]
+	if !ok {
+		return fmt.Errorf("unsupported algorithm: %s (supported: %s)", algorithm, strings.Join(hashAlgorithmNames, ", "))
+	}
+	if info, err := os.Stat(root); err != nil {
+		return fmt.Errorf("cannot access %s: %w", root, err)
+	} else if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", root)
+	}
+
+	m, err := matcher.New(matcher.Options{Include: include, Exclude: exclude, IgnoreProfiles: profiles, Recursive: true})
+	if err != nil {
+		return err
+	}
+	var files []string
+	err = m.Walk([]string{root}, func(path string, info os.FileInfo) error {
+		files = append(files, path)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Sort by the slash-separated relative path so that the order is the
+	// same on every platform
+	rels := make(map[string]string, len(files))
+	for _, path := range files {
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		rels[path] = filepath.ToSlash(rel)
+	}
+	sort.Slice(files, func(i, j int) bool { return rels[files[i]] < rels[files[j]] })
+
+	digests := hashFilesParallel(files, newHash, jobs)
+
+	tree := newHash()
+	var size int64
+	for i := range digests {
+		d := &digests[i]
+		if d.Error != "" {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("failed to hash %s: %s", d.File, d.Error)
+		}
+		d.File = rels[d.File]
+		size += d.Size
+		fmt.Fprintln(tree, formatChecksumLine(d.Hash, d.File))
+	}
+	digest := hex.EncodeToString(tree.Sum(nil))
+
+	if format == output.FormatJSON {
+		result := map[string]interface{}{
+			"algorithm": algorithm,
+			"path":      root,
+			"hash":      digest,
+			"files":     len(digests),
+			"size":      size,
+		}
+		if list {
+			result["manifest"] = digests
+		}
+		output.PrintSuccess(format, result)
+		return nil
+	}
+
+	if list {
+		for _, d := range digests {
+			fmt.Println(formatChecksumLine(d.Hash, d.File))
+		}
+		fmt.Println()
+	}
+	fmt.Printf("%s  %s\n", digest, root)
+	if list {
+		fmt.Printf("%d files, %d bytes\n", len(digests), size)
+	}
+	return nil
+}
@@ -0,0 +1,201 @@
+package dev
+
+import (
+	"crypto/hmac"
+	"fmt"
+	"hash"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cobra"
+	"devkit/internal/matcher"
+	"devkit/internal/output"
+)
+
+// fileDigest is the hash of one file in a --files run
+type fileDigest struct {
+	File  string `json:"file"`
+	Hash  string `json:"hash,omitempty"`
+	Size  int64  `json:"size"`
+	Error string `json:"error,omitempty"`
+}
+
+// runHashFiles hashes every file matched by the --files globs and
+// directories concurrently and prints a manifest. With -o sum the manifest
+// uses the sha256sum format and can be verified later with --check.
+func runHashFiles(cmd *cobra.Command, algorithm string, patterns []string) error {
+	jobs, _ := cmd.Flags().GetInt("jobs")
+	outputFormat, _ := cmd.Flags().GetString("output")
+	format := output.OutputFormat(outputFormat)
+
+	newHash, ok := hashAlgorithms[algorithm]
+	if !ok {
+		return fmt.Errorf("unsupported algorithm: %s (supported: %s)", algorithm, strings.Join(hashAlgorithmNames, ", "))
+	}
+	key, err := hmacKey(cmd)
+	if err != nil {
+		return err
+	}
+	if key != nil {
+		if checksumAlgorithms[algorithm] {
+			return fmt.Errorf("%s is a checksum, not a cryptographic hash; HMAC is not supported", algorithm)
+		}
+		base := newHash
+		newHash = func() hash.Hash { return hmac.New(base, key) }
+	}
+
+	files, err := collectHashFiles(patterns)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no files match %s", strings.Join(patterns, ", "))
+	}
+
+	digests := hashFilesParallel(files, newHash, jobs)
+
+	failed := 0
+	for _, d := range digests {
+		if d.Error != "" {
+			failed++
+		}
+	}
+
+	switch format {
+	case output.FormatJSON:
+		result := map[string]interface{}{
+			"algorithm": algorithm,
+			"files":     digests,
+			"count":     len(digests),
+			"failed":    failed,
+		}
+		if key != nil {
+			result["hmac"] = true
+		}
+		output.PrintSuccess(format, result)
+	case "sum":
+		for _, d := range digests {
+			if d.Error != "" {
+				fmt.Fprintf(os.Stderr, "%s: %s\n", d.File, d.Error)
+				continue
+			}
+			fmt.Println(formatChecksumLine(d.Hash, filepath.ToSlash(d.File)))
+		}
+	default:
+		width := 0
+		for _, d := range digests {
+			if len(d.File) > width {
+				width = len(d.File)
+			}
+		}
+		for _, d := range digests {
+			if d.Error != "" {
+				fmt.Printf("%-*s  %s\n", width, d.File, output.Failure(d.Error))
+				continue
+			}
+			fmt.Printf("%-*s  %s\n", width, d.File, d.Hash)
+		}
+	}
+
+	if failed > 0 {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("%d of %d files could not be hashed", failed, len(digests))
+	}
+	return nil
+}
+
+// hashFilesParallel hashes files with up to jobs workers and returns the
+// digests in the order of files
+func hashFilesParallel(files []string, newHash func() hash.Hash, jobs int) []fileDigest {
+	if jobs < 1 {
+		jobs = 1
+	}
+
+	digests := make([]fileDigest, len(files))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < jobs && w < len(files); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				d := fileDigest{File: files[i]}
+				if info, err := os.Stat(files[i]); err == nil {
+					d.Size = info.Size()
+				}
+				digest, err := hashFile(files[i], newHash)
+				if err != nil {
+					d.Error = err.Error()
+				}
+				d.Hash = digest
+				digests[i] = d
+			}
+		}()
+	}
+	for i := range files {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+	return digests
+}
+
+// collectHashFiles expands globs and walks directories into a sorted list
+// of regular files without duplicates
+func collectHashFiles(patterns []string) ([]string, error) {
+	paths, err := matcher.ExpandPaths(patterns)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var files []string
+	add := func(path string) {
+		path = filepath.Clean(path)
+		if !seen[path] {
+			seen[path] = true
+			files = append(files, path)
+		}
+	}
+
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		if !info.IsDir() {
+			add(path)
+			continue
+		}
+		err = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.Type().IsRegular() {
+				add(p)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to walk %s: %w", path, err)
+		}
+	}
+
+	sort.Strings(files)
+	return files, nil
+}
+
+// formatChecksumLine writes a GNU manifest line. Like sha256sum, names
+// containing a backslash or newline are escaped and the line is prefixed
+// with a backslash.
+func formatChecksumLine(digest, name string) string {
+	if strings.ContainsAny(name, "\\\n") {
+		name = strings.NewReplacer("\\", "\\\\", "\n", "\\n").Replace(name)
+		return "\\" + digest + "  " + name
+	}
+	return digest + "  " + name
+}
@@ -0,0 +1,290 @@
+package dev
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+	"devkit/internal/errors"
+	"devkit/internal/matcher"
+	"devkit/internal/output"
+)
+
+// bcryptCmd represents the bcrypt subcommand
+var bcryptCmd = &cobra.Command{
+	Use:   "bcrypt [password]",
+	Short: "Hash or verify a password with bcrypt",
+	Long: `Hash a password with bcrypt, or check it against an existing hash with
+--verify. A trailing newline in stdin or file input is ignored.
+
+Examples:
+  devkit dev hash bcrypt "secret"
+  devkit dev hash bcrypt "secret" --cost 12
+  devkit dev hash bcrypt "secret" --verify '$2a$12$...'
+  echo "secret" | devkit dev hash bcrypt --stdin`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runBcrypt,
+}
+
+// argon2Cmd represents the argon2 subcommand
+var argon2Cmd = &cobra.Command{
+	Use:   "argon2 [password]",
+	Short: "Hash or verify a password with Argon2id",
+	Long: `Hash a password with Argon2id, or check it against an existing hash with
+--verify. Hashes use the standard encoded form
+($argon2id$v=19$m=65536,t=3,p=4$salt$hash); --verify also accepts argon2i.
+
+Memory is limited to 4GB, time to 64 iterations and threads to 64, both
+when hashing and for the parameters of a --verify hash; a hash beyond them
+is rejected with exit status 2 instead of being computed.
+
+Examples:
+  devkit dev hash argon2 "secret"
+  devkit dev hash argon2 "secret" --time 4 --memory 128MB --threads 2
+  devkit dev hash argon2 "secret" --verify '$argon2id$v=19$m=65536,t=3,p=4$...'`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runArgon2,
+}
+
+func init() {
+	hashCmd.AddCommand(bcryptCmd)
+	hashCmd.AddCommand(argon2Cmd)
+
+	for _, c := range []*cobra.Command{bcryptCmd, argon2Cmd} {
+		c.Flags().StringP("file", "f", "", "Read the password from a file")
+		c.Flags().BoolP("stdin", "s", false, "Read the password from stdin")
+		c.Flags().String("verify", "", "Check the password against this hash instead of hashing it")
+		c.Flags().StringP("output", "o", "plain", "Output format: plain, json")
+	}
+
+	bcryptCmd.Flags().IntP("cost", "c", bcrypt.DefaultCost, fmt.Sprintf("Cost factor (%d-%d)", bcrypt.MinCost, bcrypt.MaxCost))
+
+	argon2Cmd.Flags().Uint32P("time", "t", 3, "Number of iterations")
+	argon2Cmd.Flags().StringP("memory", "m", "64MB", "Memory to use (e.g. 64MB)")
+	argon2Cmd.Flags().Uint8P("threads", "p", 4, "Degree of parallelism")
+	argon2Cmd.Flags().Uint32("salt-length", 16, "Salt length in bytes")
+	argon2Cmd.Flags().Uint32("key-length", 32, "Hash length in bytes")
+}
+
+// getPasswordInput reads the password like getJSONInput, dropping the
+// trailing newline that echo and editors add
+func getPasswordInput(cmd *cobra.Command, args []string) (string, error) {
+	input, err := getJSONInput(cmd, args)
+	if err != nil {
+		return "", fmt.Errorf("password not specified (use --file, --stdin, or provide as argument)")
+	}
+	return strings.TrimRight(input, "\r\n"), nil
+}
+
+func runBcrypt(cmd *cobra.Command, args []string) error {
+	cost, _ := cmd.Flags().GetInt("cost")
+	verify, _ := cmd.Flags().GetString("verify")
+	outputFormat, _ := cmd.Flags().GetString("output")
+	format := output.OutputFormat(outputFormat)
+
+	password, err := getPasswordInput(cmd, args)
+	if err != nil {
+		return err
+	}
+
+	if verify != "" {
+		err := bcrypt.CompareHashAndPassword([]byte(verify), []byte(password))
+		if err != nil && err != bcrypt.ErrMismatchedHashAndPassword {
+			return fmt.Errorf("invalid bcrypt hash: %w", err)
+		}
+		return printPasswordMatch(cmd, format, "bcrypt", err == nil)
+	}
+
+	if cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+		return fmt.Errorf("cost must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost)
+	}
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), cost)
+	if err != nil {
+		return fmt.Errorf("bcrypt failed: %w", err)
+	}
+
+	if format == output.FormatJSON {
+		output.PrintSuccess(format, map[string]interface{}{
+			"algorithm": "bcrypt",
+			"hash":      string(hashed),
+			"cost":      cost,
+		})
+	} else {
+		output.PrintSuccess(format, string(hashed))
+	}
+
+	return nil
+}
+
+// Limits of the Argon2 parameters. A --verify hash comes from elsewhere and
+// could otherwise make devkit allocate up to 4TB or run for days.
+const (
+	argon2MaxMemory  = 4 << 20 // KiB
+	argon2MaxTime    = 64
+	argon2MaxThreads = 64
+)
+
+// argon2Params are the parameters encoded in an Argon2 hash
+type argon2Params struct {
+	variant string
+	memory  uint32 // KiB
+	time    uint32
+	threads uint8
+	salt    []byte
+	key     []byte
+}
+
+func runArgon2(cmd *cobra.Command, args []string) error {
+	verify, _ := cmd.Flags().GetString("verify")
+	outputFormat, _ := cmd.Flags().GetString("output")
+	format := output.OutputFormat(outputFormat)
+
+	password, err := getPasswordInput(cmd, args)
+	if err != nil {
+		return err
+	}
+
+	if verify != "" {
+		p, err := parseArgon2Hash(verify)
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+		key := p.derive(password, uint32(len(p.key)))
+		match := subtle.ConstantTimeCompare(key, p.key) == 1
+		return printPasswordMatch(cmd, format, p.variant, match)
+	}
+
+	p := argon2Params{variant: "argon2id"}
+	p.time, _ = cmd.Flags().GetUint32("time")
+	p.threads, _ = cmd.Flags().GetUint8("threads")
+	saltLength, _ := cmd.Flags().GetUint32("salt-length")
+	keyLength, _ := cmd.Flags().GetUint32("key-length")
+	memory, _ := cmd.Flags().GetString("memory")
+
+	size, err := matcher.ParseSize(memory)
+	if err != nil {
+		return err
+	}
+	if size/1024 > argon2MaxMemory {
+		return fmt.Errorf("memory must be at most 4GB")
+	}
+	p.memory = uint32(size / 1024)
+	if p.time < 1 || p.threads < 1 || p.memory < 8*uint32(p.threads) {
+		return fmt.Errorf("invalid parameters: time and threads must be at least 1 and memory at least 8KB per thread")
+	}
+	if err := p.checkLimits(); err != nil {
+		return err
+	}
+	if saltLength < 8 || keyLength < 4 {
+		return fmt.Errorf("salt length must be at least 8 bytes and key length at least 4 bytes")
+	}
+
+	p.salt = make([]byte, saltLength)
+	if _, err := rand.Read(p.salt); err != nil {
+		return fmt.Errorf("failed to generate salt: %w", err)
+	}
+	p.key = p.derive(password, keyLength)
+	encoded := p.encode()
+
+	if format == output.FormatJSON {
+		output.PrintSuccess(format, map[string]interface{}{
+			"algorithm": p.variant,
+			"hash":      encoded,
+			"memory":    p.memory,
+			"time":      p.time,
+			"threads":   p.threads,
+		})
+	} else {
+		output.PrintSuccess(format, encoded)
+	}
+
+	return nil
+}
+
+// checkLimits reports parameters beyond the limits
+func (p argon2Params) checkLimits() error {
+	switch {
+	case p.memory > argon2MaxMemory:
+		return fmt.Errorf("memory of %d KiB exceeds the limit of %d KiB (4GB)", p.memory, argon2MaxMemory)
+	case p.time > argon2MaxTime:
+		return fmt.Errorf("time %d exceeds the limit of %d", p.time, argon2MaxTime)
+	case p.threads > argon2MaxThreads:
+		return fmt.Errorf("threads %d exceeds the limit of %d", p.threads, argon2MaxThreads)
+	}
+	return nil
+}
+
+func (p argon2Params) derive(password string, keyLength uint32) []byte {
+	if p.variant == "argon2i" {
+		return argon2.Key([]byte(password), p.salt, p.time, p.memory, p.threads, keyLength)
+	}
+	return argon2.IDKey([]byte(password), p.salt, p.time, p.memory, p.threads, keyLength)
+}
+
+func (p argon2Params) encode() string {
+	return fmt.Sprintf("$%s$v=%d$m=%d,t=%d,p=%d$%s$%s", p.variant, argon2.Version,
+		p.memory, p.time, p.threads,
+		base64.RawStdEncoding.EncodeToString(p.salt),
+		base64.RawStdEncoding.EncodeToString(p.key))
+}
+
+// parseArgon2Hash parses $argon2id$v=19$m=65536,t=3,p=4$salt$hash
+func parseArgon2Hash(encoded string) (argon2Params, error) {
+	var p argon2Params
+	parts := strings.Split(strings.TrimSpace(encoded), "$")
+	if len(parts) != 6 || parts[0] != "" {
+		return p, fmt.Errorf("invalid argon2 hash: expected $argon2id$v=19$m=...,t=...,p=...$salt$hash")
+	}
+
+	p.variant = parts[1]
+	if p.variant != "argon2id" && p.variant != "argon2i" {
+		return p, fmt.Errorf("unsupported argon2 variant: %s (supported: argon2id, argon2i)", p.variant)
+	}
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return p, fmt.Errorf("unsupported argon2 version: %s", parts[2])
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &p.memory, &p.time, &p.threads); err != nil || p.time < 1 || p.threads < 1 {
+		return p, fmt.Errorf("invalid argon2 parameters: %s", parts[3])
+	}
+	if err := p.checkLimits(); err != nil {
+		return p, &errors.ExitError{Code: errors.ExitInvalid, Err: fmt.Errorf("argon2 hash rejected: %w", err)}
+	}
+
+	var err error
+	if p.salt, err = base64.RawStdEncoding.DecodeString(parts[4]); err != nil {
+		return p, fmt.Errorf("invalid argon2 salt: %w", err)
+	}
+	if p.key, err = base64.RawStdEncoding.DecodeString(parts[5]); err != nil {
+		return p, fmt.Errorf("invalid argon2 hash value: %w", err)
+	}
+	if len(p.key) < 4 {
+		return p, fmt.Errorf("invalid argon2 hash value: too short")
+	}
+	return p, nil
+}
+
+// printPasswordMatch reports a --verify result; a mismatch is an error so
+// that scripts can rely on the exit code
+func printPasswordMatch(cmd *cobra.Command, format output.OutputFormat, algorithm string, match bool) error {
+	if format == output.FormatJSON {
+		output.PrintSuccess(format, map[string]interface{}{
+			"algorithm": algorithm,
+			"match":     match,
+		})
+	} else if match {
+		fmt.Println(output.OK("Password matches the hash"))
+	}
+
+	if !match {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("password does not match the hash")
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package dev
+
+import (
+	"testing"
+
+	"devkit/internal/errors"
+)
+
+func TestParseArgon2HashLimits(t *testing.T) {
+	tests := []struct {
+		name   string
+		params string
+		ok     bool
+	}{
+		{name: "default", params: "m=65536,t=3,p=4", ok: true},
+		{name: "at the limits", params: "m=4194304,t=64,p=64", ok: true},
+		{name: "memory", params: "m=4294967295,t=3,p=4"},
+		{name: "time", params: "m=65536,t=65,p=4"},
+		{name: "threads", params: "m=65536,t=3,p=65"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseArgon2Hash("$argon2id$v=19$" + tt.params + "$c2FsdHNhbHQ$aGFzaGhhc2g")
+			if tt.ok {
+				if err != nil {
+					t.Fatalf("parseArgon2Hash: %v", err)
+				}
+				return
+			}
+			if exitErr, ok := err.(*errors.ExitError); !ok || exitErr.Code != errors.ExitInvalid {
+				t.Fatalf("parseArgon2Hash error = %v, want an exit status %d error", err, errors.ExitInvalid)
+			}
+		})
+	}
+}
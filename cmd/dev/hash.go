@@ -1,17 +1,30 @@
 package dev
 
 import (
+	"bytes"
+	"crypto/hmac"
 	"crypto/md5"
 	"crypto/sha1"
 	"crypto/sha256"
 	"crypto/sha512"
 	"encoding/hex"
 	"fmt"
+	"hash"
+	"hash/crc32"
+	"hash/crc64"
 	"io"
 	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
 
+	"github.com/cespare/xxhash/v2"
 	"github.com/spf13/cobra"
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/sha3"
+	"devkit/internal/i18n"
 	"devkit/internal/output"
+	"devkit/internal/picker"
 	"devkit/internal/utils"
 )
 
@@ -21,14 +34,39 @@ var hashCmd = &cobra.Command{
 	Short: "Calculate hash of input",
 	Long: `Calculate cryptographic hash of a string or file.
 
-Supported algorithms: md5, sha1, sha256, sha512
+Supported algorithms:
+  Cryptographic:     md5, sha1, sha256, sha512, sha3-256, sha3-512,
+                     blake2b (512-bit), blake2b-256
+  Non-cryptographic: crc32 (IEEE), crc64 (ECMA), xxh64
+
+With --hmac-key or --hmac-key-file the keyed HMAC of the input is computed
+instead, for example to check a webhook signature. A trailing newline in
+the key file is ignored.
+
+Run in a terminal without an input to pick the file to hash interactively.
+
+With --files, every file matched by a glob or found in a directory is
+hashed concurrently and a manifest is printed. Use -o sum for the sha256sum
+format, which --check can verify later.
+
+With --check, the files listed in a manifest ("<hash>  <file>" lines as
+written by sha256sum, or BSD "SHA256 (file) = <hash>" lines) are verified
+and reported as OK or FAILED. The command fails if any file does not match
+or cannot be read.
 
 Examples:
   devkit dev hash sha256 "hello world"
   devkit dev hash md5 --file /path/to/file
-  echo "hello" | devkit dev hash sha256 --stdin`,
+  devkit dev hash xxh64 --file ./large.bin
+  echo "hello" | devkit dev hash sha256 --stdin
+  devkit dev hash sha256 --file payload.json --hmac-key "{{env.WEBHOOK_SECRET}}"
+  devkit dev hash sha1 "message" --hmac-key-file secret.key
+  devkit dev hash sha256 --files "dist/*"
+  devkit dev hash sha256 --files dist -o sum > SHA256SUMS
+  devkit dev hash sha256 --check SHA256SUMS
+  devkit dev hash sha256 --check SHA256SUMS --ignore-missing`,
 	Args: cobra.MinimumNArgs(1),
-	ValidArgs: []string{"md5", "sha1", "sha256", "sha512"},
+	ValidArgs: hashAlgorithmNames,
 	RunE: runHash,
 }
 
@@ -38,63 +76,91 @@ func init() {
 	// Flag definitions
 	hashCmd.Flags().StringP("file", "f", "", "Input file path")
 	hashCmd.Flags().BoolP("stdin", "s", false, "Read from stdin")
-	hashCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json, table")
+	hashCmd.Flags().String("hmac-key", "", "Compute an HMAC with this key")
+	hashCmd.Flags().String("hmac-key-file", "", "Compute an HMAC with the key read from a file")
+	hashCmd.Flags().StringP("check", "c", "", "Verify the files listed in a checksum manifest (- for stdin)")
+	hashCmd.Flags().Bool("ignore-missing", false, "With --check, skip files that do not exist")
+	hashCmd.Flags().StringArray("files", nil, "Hash every file matching a glob or in a directory (repeatable)")
+	hashCmd.Flags().IntP("jobs", "j", runtime.NumCPU(), "With --files, number of files hashed in parallel")
+	hashCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json, table; sum (sha256sum format) with --files")
 }
 
 func runHash(cmd *cobra.Command, args []string) error {
 	algorithm := args[0]
+
+	if manifest, _ := cmd.Flags().GetString("check"); manifest != "" {
+		return runHashCheck(cmd, algorithm, manifest)
+	}
+	if files, _ := cmd.Flags().GetStringArray("files"); len(files) > 0 {
+		return runHashFiles(cmd, algorithm, files)
+	}
 	
-	// Get input
 	fileFlag, _ := cmd.Flags().GetString("file")
 	stdinFlag, _ := cmd.Flags().GetBool("stdin")
-	
+
+	newHash, ok := hashAlgorithms[algorithm]
+	if !ok {
+		return fmt.Errorf("unsupported algorithm: %s (supported: %s)", algorithm, strings.Join(hashAlgorithmNames, ", "))
+	}
+	key, err := hmacKey(cmd)
+	if err != nil {
+		return err
+	}
+	if key != nil && checksumAlgorithms[algorithm] {
+		return fmt.Errorf("%s is a checksum, not a cryptographic hash; HMAC is not supported", algorithm)
+	}
+
+	var h hash.Hash
+	if key != nil {
+		h = hmac.New(newHash, key)
+	} else {
+		h = newHash()
+	}
+
+	if !stdinFlag && fileFlag == "" && len(args) < 2 && picker.Available() {
+		cmd.SilenceUsage = true
+		if fileFlag, err = picker.File(i18n.T("picker.select_file")); err != nil {
+			return err
+		}
+	}
+
+	// Files and stdin are streamed through the hash so that inputs larger
+	// than memory work; JSON output reports the file name, or "-" for stdin,
+	// as the input
 	var input string
-	var err error
-	
 	if stdinFlag {
 		stat, err := os.Stdin.Stat()
 		if err != nil {
 			return fmt.Errorf("stdin error: %w", err)
 		}
-		if (stat.Mode() & os.ModeCharDevice) == 0 {
-			bytes, err := io.ReadAll(os.Stdin)
-			if err != nil {
-				return fmt.Errorf("read stdin error: %w", err)
-			}
-			input = string(bytes)
-		} else {
+		if (stat.Mode() & os.ModeCharDevice) != 0 {
 			return fmt.Errorf("no data available from stdin")
 		}
+		if err := copyWithProgress(h, os.Stdin, "Hashing stdin", 0); err != nil {
+			return fmt.Errorf("read stdin error: %w", err)
+		}
+		input = "-"
 	} else if fileFlag != "" {
-		bytes, err := os.ReadFile(fileFlag)
+		file, err := os.Open(fileFlag)
 		if err != nil {
 			return fmt.Errorf("read file error: %w", err)
 		}
-		input = string(bytes)
+		defer file.Close()
+		var size int64
+		if info, err := file.Stat(); err == nil {
+			size = info.Size()
+		}
+		if err := copyWithProgress(h, file, "Hashing "+filepath.Base(fileFlag), size); err != nil {
+			return fmt.Errorf("read file error: %w", err)
+		}
+		input = fileFlag
 	} else if len(args) > 1 {
 		input = args[1]
+		h.Write([]byte(input))
 	} else {
 		return fmt.Errorf("input not specified (use --file, --stdin, or provide as argument)")
 	}
-
-	// Calculate hash
-	var hash string
-	switch algorithm {
-	case "md5":
-		hash, err = calculateMD5(input)
-	case "sha1":
-		hash, err = calculateSHA1(input)
-	case "sha256":
-		hash, err = calculateSHA256(input)
-	case "sha512":
-		hash, err = calculateSHA512(input)
-	default:
-		return fmt.Errorf("unsupported algorithm: %s (supported: md5, sha1, sha256, sha512)", algorithm)
-	}
-
-	if err != nil {
-		return err
-	}
+	digest := hex.EncodeToString(h.Sum(nil))
 
 	// Get output format
 	outputFormat, _ := cmd.Flags().GetString("output")
@@ -104,34 +170,73 @@ func runHash(cmd *cobra.Command, args []string) error {
 	if format == output.FormatJSON {
 		result := map[string]interface{}{
 			"algorithm": algorithm,
-			"hash":      hash,
+			"hash":      digest,
 			"input":     utils.TrimSpace(input),
 		}
+		if key != nil {
+			result["hmac"] = true
+		}
 		output.PrintSuccess(format, result)
 	} else {
 		// Plain format - just print the hash
-		output.PrintSuccess(format, hash)
+		output.PrintSuccess(format, digest)
 	}
 
 	return nil
 }
 
-func calculateMD5(input string) (string, error) {
-	hash := md5.Sum([]byte(input))
-	return hex.EncodeToString(hash[:]), nil
+// copyWithProgress copies r into w, showing progress for long copies
+func copyWithProgress(w io.Writer, r io.Reader, label string, size int64) error {
+	progress := output.NewProgress(label, size)
+	defer progress.Done()
+	_, err := io.Copy(io.MultiWriter(w, progress), r)
+	return err
 }
 
-func calculateSHA1(input string) (string, error) {
-	hash := sha1.Sum([]byte(input))
-	return hex.EncodeToString(hash[:]), nil
+// hashAlgorithms maps the supported algorithm names to their constructors
+var hashAlgorithms = map[string]func() hash.Hash{
+	"md5":         md5.New,
+	"sha1":        sha1.New,
+	"sha256":      sha256.New,
+	"sha512":      sha512.New,
+	"sha3-256":    sha3.New256,
+	"sha3-512":    sha3.New512,
+	"blake2b":     func() hash.Hash { h, _ := blake2b.New512(nil); return h },
+	"blake2b-256": func() hash.Hash { h, _ := blake2b.New256(nil); return h },
+	"crc32":       func() hash.Hash { return crc32.NewIEEE() },
+	"crc64":       func() hash.Hash { return crc64.New(crc64.MakeTable(crc64.ECMA)) },
+	"xxh64":       func() hash.Hash { return xxhash.New() },
 }
 
-func calculateSHA256(input string) (string, error) {
-	hash := sha256.Sum256([]byte(input))
-	return hex.EncodeToString(hash[:]), nil
+// hashAlgorithmNames lists the algorithms in the order they are documented
+var hashAlgorithmNames = []string{
+	"md5", "sha1", "sha256", "sha512", "sha3-256", "sha3-512",
+	"blake2b", "blake2b-256", "crc32", "crc64", "xxh64",
 }
 
-func calculateSHA512(input string) (string, error) {
-	hash := sha512.Sum512([]byte(input))
-	return hex.EncodeToString(hash[:]), nil
+// checksumAlgorithms are not cryptographic and cannot be used for an HMAC
+var checksumAlgorithms = map[string]bool{"crc32": true, "crc64": true, "xxh64": true}
+
+// hmacKey returns the key given with --hmac-key or --hmac-key-file, or nil
+// when no HMAC was requested
+func hmacKey(cmd *cobra.Command) ([]byte, error) {
+	key, _ := cmd.Flags().GetString("hmac-key")
+	keyFile, _ := cmd.Flags().GetString("hmac-key-file")
+
+	if key != "" && keyFile != "" {
+		return nil, fmt.Errorf("use either --hmac-key or --hmac-key-file, not both")
+	}
+	if keyFile != "" {
+		data, err := os.ReadFile(keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("read key file error: %w", err)
+		}
+		// Editors usually end the file with a newline that is not part of
+		// the key
+		return bytes.TrimRight(data, "\r\n"), nil
+	}
+	if key != "" {
+		return []byte(key), nil
+	}
+	return nil, nil
 }
@@ -0,0 +1,144 @@
+package dev
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"devkit/internal/output"
+)
+
+// hexCmd represents the hex command group
+var hexCmd = &cobra.Command{
+	Use:   "hex",
+	Short: "Hex encode/decode operations (use 'hex encode' or 'hex decode')",
+	Long: `Encode data to hexadecimal or decode hexadecimal to data.
+
+Subcommands:
+  encode    Encode input to hex
+  decode    Decode hex string
+
+Examples:
+  devkit dev hex encode "hello"
+  devkit dev hex encode --file ./blob.bin --upper --separator ":"
+  devkit dev hex decode "68656c6c6f"
+  devkit dev hex decode "0x68 0x65 0x6c 0x6c 0x6f"`,
+}
+
+// hexEncodeCmd represents the encode subcommand
+var hexEncodeCmd = &cobra.Command{
+	Use:   "encode [input]",
+	Short: "Encode input to hex",
+	Long: `Encode a string or file to hexadecimal.
+
+Examples:
+  devkit dev hex encode "hello"
+  devkit dev hex encode "hello" --upper
+  devkit dev hex encode --file ./cert.der --separator ":"
+  echo -n "test" | devkit dev hex encode --stdin`,
+	RunE: runHexEncode,
+}
+
+// hexDecodeCmd represents the decode subcommand
+var hexDecodeCmd = &cobra.Command{
+	Use:   "decode [input]",
+	Short: "Decode hex string",
+	Long: `Decode a hexadecimal string.
+
+Upper and lower case are accepted, as are 0x prefixes, whitespace and the
+separators ":" "-" and ",".
+
+Examples:
+  devkit dev hex decode "68656c6c6f"
+  devkit dev hex decode "68:65:6C:6C:6F"
+  devkit dev hex decode --file dump.hex --out blob.bin
+  devkit dev hex decode --file dump.hex --hexdump`,
+	RunE: runHexDecode,
+}
+
+func init() {
+	devCmd.AddCommand(hexCmd)
+	hexCmd.AddCommand(hexEncodeCmd)
+	hexCmd.AddCommand(hexDecodeCmd)
+
+	// Flag definitions for encode
+	hexEncodeCmd.Flags().StringP("file", "f", "", "Input file path")
+	hexEncodeCmd.Flags().BoolP("stdin", "s", false, "Read from stdin")
+	hexEncodeCmd.Flags().BoolP("upper", "u", false, "Uppercase output")
+	hexEncodeCmd.Flags().String("separator", "", "Separator between bytes (e.g. \":\" or \" \")")
+	hexEncodeCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
+
+	// Flag definitions for decode
+	hexDecodeCmd.Flags().StringP("file", "f", "", "Input file path")
+	hexDecodeCmd.Flags().BoolP("stdin", "s", false, "Read from stdin")
+	hexDecodeCmd.Flags().String("out", "", "Write the decoded bytes to a file")
+	hexDecodeCmd.Flags().Bool("hexdump", false, "Show a hex dump of the decoded bytes")
+	hexDecodeCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
+}
+
+func runHexEncode(cmd *cobra.Command, args []string) error {
+	upper, _ := cmd.Flags().GetBool("upper")
+	separator, _ := cmd.Flags().GetString("separator")
+	outputFormat, _ := cmd.Flags().GetString("output")
+	format := output.OutputFormat(outputFormat)
+
+	input, err := getJSONInput(cmd, args)
+	if err != nil {
+		return err
+	}
+
+	encoded := hex.EncodeToString([]byte(input))
+	if separator != "" {
+		pairs := make([]string, 0, len(encoded)/2)
+		for i := 0; i < len(encoded); i += 2 {
+			pairs = append(pairs, encoded[i:i+2])
+		}
+		encoded = strings.Join(pairs, separator)
+	}
+	if upper {
+		encoded = strings.ToUpper(encoded)
+	}
+
+	if format == output.FormatJSON {
+		output.PrintSuccess(format, map[string]interface{}{
+			"encoded": encoded,
+			"input":   input,
+			"bytes":   len(input),
+		})
+	} else {
+		output.PrintSuccess(format, encoded)
+	}
+
+	return nil
+}
+
+func runHexDecode(cmd *cobra.Command, args []string) error {
+	outputFormat, _ := cmd.Flags().GetString("output")
+	format := output.OutputFormat(outputFormat)
+
+	input, err := getJSONInput(cmd, args)
+	if err != nil {
+		return err
+	}
+
+	normalized := normalizeHex(input)
+	decoded, err := hex.DecodeString(normalized)
+	if err != nil {
+		return fmt.Errorf("invalid hex string: %w", err)
+	}
+
+	return printDecoded(cmd, format, normalized, "hex", decoded)
+}
+
+// normalizeHex strips 0x prefixes, whitespace and common byte separators
+func normalizeHex(input string) string {
+	var b strings.Builder
+	for _, field := range strings.FieldsFunc(input, func(r rune) bool {
+		return r == ' ' || r == '\t' || r == '\n' || r == '\r' || r == ':' || r == '-' || r == ','
+	}) {
+		field = strings.TrimPrefix(strings.TrimPrefix(field, "0x"), "0X")
+		b.WriteString(field)
+	}
+	return strings.ToLower(b.String())
+}
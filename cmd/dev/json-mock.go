@@ -0,0 +1,409 @@
+package dev
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+	"devkit/internal/output"
+)
+
+// jsonMockCmd represents the mock subcommand
+var jsonMockCmd = &cobra.Command{
+	Use:   "mock",
+	Short: "Generate fake JSON documents from a schema or template",
+	Long: `Generate fake but valid JSON documents from a JSON Schema or an example
+template document.
+
+Supported schema keywords: type, properties, items, enum, const, minimum,
+maximum, exclusiveMinimum, exclusiveMaximum, minLength, maxLength,
+minItems, maxItems, format (email, uuid, date, date-time, uri, ipv4,
+hostname), examples, oneOf, anyOf.
+
+With --template, the example document is used as a shape: every value is
+replaced by a random value of the same type.
+
+Examples:
+  devkit dev json mock --schema schema.json
+  devkit dev json mock --schema schema.json --count 10
+  devkit dev json mock --template user.json --count 5 --ndjson
+  devkit dev json mock --schema schema.json --seed 42`,
+	RunE: runJSONMock,
+}
+
+// jsonMocker generates values using a seeded random source
+type jsonMocker struct {
+	r *rand.Rand
+}
+
+var mockWords = []string{
+	"alpha", "bravo", "charlie", "delta", "echo", "foxtrot", "golf", "hotel",
+	"india", "juliet", "kilo", "lima", "mike", "november", "oscar", "papa",
+}
+
+func init() {
+	jsonCmd.AddCommand(jsonMockCmd)
+
+	jsonMockCmd.Flags().String("schema", "", "JSON Schema file")
+	jsonMockCmd.Flags().String("template", "", "Example JSON document used as a template")
+	jsonMockCmd.Flags().IntP("count", "c", 1, "Number of documents to generate")
+	jsonMockCmd.Flags().Int64("seed", 0, "Random seed for reproducible output (0 = random)")
+	jsonMockCmd.Flags().Bool("ndjson", false, "Emit one minified document per line")
+	jsonMockCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
+}
+
+func runJSONMock(cmd *cobra.Command, args []string) error {
+	schemaFile, _ := cmd.Flags().GetString("schema")
+	templateFile, _ := cmd.Flags().GetString("template")
+	count, _ := cmd.Flags().GetInt("count")
+	seed, _ := cmd.Flags().GetInt64("seed")
+	ndjson, _ := cmd.Flags().GetBool("ndjson")
+	outputFormat, _ := cmd.Flags().GetString("output")
+	format := output.OutputFormat(outputFormat)
+
+	if (schemaFile == "") == (templateFile == "") {
+		return fmt.Errorf("specify exactly one of --schema or --template")
+	}
+	if count < 1 {
+		return fmt.Errorf("count must be at least 1")
+	}
+	if count > 10000 {
+		return fmt.Errorf("count cannot exceed 10000")
+	}
+
+	source := schemaFile
+	if source == "" {
+		source = templateFile
+	}
+	data, err := os.ReadFile(source)
+	if err != nil {
+		return fmt.Errorf("read file error: %w", err)
+	}
+
+	var def interface{}
+	if err := json.Unmarshal(data, &def); err != nil {
+		return fmt.Errorf("invalid JSON in %s: %w", source, err)
+	}
+
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	m := &jsonMocker{r: rand.New(rand.NewSource(seed))}
+
+	docs := make([]interface{}, count)
+	for i := range docs {
+		if schemaFile != "" {
+			schema, ok := def.(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("schema must be a JSON object")
+			}
+			docs[i] = m.fromSchema(schema, 0)
+		} else {
+			docs[i] = m.fromTemplate(def)
+		}
+	}
+
+	if format == output.FormatJSON {
+		output.PrintSuccess(format, map[string]interface{}{
+			"count":     count,
+			"documents": docs,
+		})
+		return nil
+	}
+
+	if ndjson {
+		for _, doc := range docs {
+			line, err := json.Marshal(doc)
+			if err != nil {
+				return fmt.Errorf("failed to encode document: %w", err)
+			}
+			fmt.Println(string(line))
+		}
+		return nil
+	}
+
+	var result interface{} = docs
+	if count == 1 {
+		result = docs[0]
+	}
+	pretty, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode document: %w", err)
+	}
+	output.PrintSuccess(format, string(pretty))
+
+	return nil
+}
+
+// fromSchema generates a value satisfying a (subset of) JSON Schema
+func (m *jsonMocker) fromSchema(schema map[string]interface{}, depth int) interface{} {
+	if v, ok := schema["const"]; ok {
+		return v
+	}
+	if enum, ok := schema["enum"].([]interface{}); ok && len(enum) > 0 {
+		return enum[m.r.Intn(len(enum))]
+	}
+	if examples, ok := schema["examples"].([]interface{}); ok && len(examples) > 0 {
+		return examples[m.r.Intn(len(examples))]
+	}
+	if options, ok := schema["oneOf"].([]interface{}); ok && len(options) > 0 {
+		if sub, ok := options[m.r.Intn(len(options))].(map[string]interface{}); ok {
+			return m.fromSchema(sub, depth)
+		}
+	}
+	if options, ok := schema["anyOf"].([]interface{}); ok && len(options) > 0 {
+		if sub, ok := options[m.r.Intn(len(options))].(map[string]interface{}); ok {
+			return m.fromSchema(sub, depth)
+		}
+	}
+
+	schemaType := ""
+	switch t := schema["type"].(type) {
+	case string:
+		schemaType = t
+	case []interface{}:
+		if len(t) > 0 {
+			schemaType, _ = t[m.r.Intn(len(t))].(string)
+		}
+	}
+	if schemaType == "" {
+		switch {
+		case schema["properties"] != nil:
+			schemaType = "object"
+		case schema["items"] != nil:
+			schemaType = "array"
+		default:
+			schemaType = "string"
+		}
+	}
+
+	switch schemaType {
+	case "object":
+		obj := make(map[string]interface{})
+		if depth > 10 {
+			return obj
+		}
+		props, _ := schema["properties"].(map[string]interface{})
+		keys := make([]string, 0, len(props))
+		for key := range props {
+			keys = append(keys, key)
+		}
+		// Iterate in a stable order so --seed is reproducible
+		sort.Strings(keys)
+		for _, key := range keys {
+			if sub, ok := props[key].(map[string]interface{}); ok {
+				obj[key] = m.fromSchema(sub, depth+1)
+			}
+		}
+		return obj
+
+	case "array":
+		minItems := intKeyword(schema, "minItems", 1)
+		maxItems := intKeyword(schema, "maxItems", minItems+4)
+		minItems = min(minItems, maxItems)
+		n := minItems
+		if maxItems > minItems {
+			n += m.r.Intn(maxItems - minItems + 1)
+		}
+		items, _ := schema["items"].(map[string]interface{})
+		arr := make([]interface{}, n)
+		for i := range arr {
+			if items == nil || depth > 10 {
+				arr[i] = m.word()
+			} else {
+				arr[i] = m.fromSchema(items, depth+1)
+			}
+		}
+		return arr
+
+	case "integer":
+		lo, hi := numberRange(schema)
+		min, max := int(math.Floor(lo))+1, int(math.Ceil(hi))-1
+		if _, exclusive := exclusiveBound(schema, "minimum"); !exclusive {
+			min = int(math.Ceil(lo))
+		}
+		if _, exclusive := exclusiveBound(schema, "maximum"); !exclusive {
+			max = int(math.Floor(hi))
+		}
+		if max <= min {
+			return min
+		}
+		return min + m.r.Intn(max-min+1)
+
+	case "number":
+		lo, hi := numberRange(schema)
+		if hi <= lo {
+			return lo
+		}
+		v := math.Round((lo+m.r.Float64()*(hi-lo))*100) / 100
+		_, exclusiveMin := exclusiveBound(schema, "minimum")
+		_, exclusiveMax := exclusiveBound(schema, "maximum")
+		// Rounding can land on or past a bound
+		if v < lo || v > hi || exclusiveMin && v == lo || exclusiveMax && v == hi {
+			v = lo + (hi-lo)/2
+		}
+		return v
+
+	case "boolean":
+		return m.r.Intn(2) == 1
+
+	case "null":
+		return nil
+
+	default:
+		return m.stringFor(schema)
+	}
+}
+
+// stringFor generates a string honoring format and length keywords
+func (m *jsonMocker) stringFor(schema map[string]interface{}) string {
+	format, _ := schema["format"].(string)
+	switch format {
+	case "email":
+		return fmt.Sprintf("%s.%s@example.com", m.word(), m.word())
+	case "uuid":
+		var b [16]byte
+		m.r.Read(b[:])
+		// Set the version 4 and RFC 4122 variant bits
+		b[6] = (b[6] & 0x0f) | 0x40
+		b[8] = (b[8] & 0x3f) | 0x80
+		id, _ := uuid.FromBytes(b[:])
+		return id.String()
+	case "date":
+		return m.time().Format("2006-01-02")
+	case "date-time":
+		return m.time().Format(time.RFC3339)
+	case "uri", "url":
+		return fmt.Sprintf("https://%s.example.com/%s", m.word(), m.word())
+	case "ipv4":
+		return fmt.Sprintf("%d.%d.%d.%d", 1+m.r.Intn(223), m.r.Intn(256), m.r.Intn(256), 1+m.r.Intn(254))
+	case "hostname":
+		return m.word() + ".example.com"
+	}
+
+	minLength := intKeyword(schema, "minLength", 0)
+	maxLength := intKeyword(schema, "maxLength", 0)
+
+	var words []string
+	for i := 0; i < 1+m.r.Intn(3); i++ {
+		words = append(words, m.word())
+	}
+	s := strings.Join(words, " ")
+	for len(s) < minLength {
+		s += " " + m.word()
+	}
+	if maxLength > 0 && len(s) > maxLength {
+		s = strings.TrimRight(s[:maxLength], " ")
+	}
+	// Cutting at a space can leave the string short of minLength again
+	for len(s) < minLength {
+		s += string(rune('a' + m.r.Intn(26)))
+	}
+	return s
+}
+
+// fromTemplate replaces every leaf value with a random value of the same type
+func (m *jsonMocker) fromTemplate(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(t))
+		for key := range t {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		obj := make(map[string]interface{}, len(t))
+		for _, key := range keys {
+			obj[key] = m.fromTemplate(t[key])
+		}
+		return obj
+	case []interface{}:
+		arr := make([]interface{}, len(t))
+		for i, item := range t {
+			arr[i] = m.fromTemplate(item)
+		}
+		return arr
+	case string:
+		switch {
+		case strings.Contains(t, "@"):
+			return m.stringFor(map[string]interface{}{"format": "email"})
+		case strings.HasPrefix(t, "http://") || strings.HasPrefix(t, "https://"):
+			return m.stringFor(map[string]interface{}{"format": "uri"})
+		}
+		if _, err := uuid.Parse(t); err == nil {
+			return m.stringFor(map[string]interface{}{"format": "uuid"})
+		}
+		if _, err := time.Parse(time.RFC3339, t); err == nil {
+			return m.stringFor(map[string]interface{}{"format": "date-time"})
+		}
+		return m.stringFor(map[string]interface{}{"maxLength": float64(len(t) + 8)})
+	case float64:
+		if t == math.Trunc(t) {
+			return float64(m.r.Intn(1000))
+		}
+		return math.Round(m.r.Float64()*1000*100) / 100
+	case bool:
+		return m.r.Intn(2) == 1
+	default:
+		return v
+	}
+}
+
+func (m *jsonMocker) word() string {
+	return mockWords[m.r.Intn(len(mockWords))]
+}
+
+func (m *jsonMocker) time() time.Time {
+	base := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	return base.Add(time.Duration(m.r.Int63n(int64(5 * 365 * 24 * time.Hour)))).Truncate(time.Second)
+}
+
+func intKeyword(schema map[string]interface{}, key string, def int) int {
+	if v, ok := schema[key].(float64); ok {
+		return int(v)
+	}
+	return def
+}
+
+// numberRange returns the bounds of a number schema: minimum or
+// exclusiveMinimum and maximum or exclusiveMaximum. A missing bound is
+// 1000 away from the other one, or the range is 0 to 1000.
+func numberRange(schema map[string]interface{}) (lo, hi float64) {
+	lo, hasLo := exclusiveBound(schema, "minimum")
+	if !hasLo {
+		lo, hasLo = schema["minimum"].(float64)
+	}
+	hi, hasHi := exclusiveBound(schema, "maximum")
+	if !hasHi {
+		hi, hasHi = schema["maximum"].(float64)
+	}
+	switch {
+	case !hasLo && !hasHi:
+		return 0, 1000
+	case !hasLo:
+		return hi - 1000, hi
+	case !hasHi:
+		return lo, lo + 1000
+	}
+	return lo, hi
+}
+
+// exclusiveBound returns the exclusive bound for "minimum" or "maximum":
+// exclusiveMinimum as a number, or as true next to minimum (draft 4)
+func exclusiveBound(schema map[string]interface{}, bound string) (float64, bool) {
+	key := "exclusive" + strings.ToUpper(bound[:1]) + bound[1:]
+	switch v := schema[key].(type) {
+	case float64:
+		return v, true
+	case bool:
+		n, ok := schema[bound].(float64)
+		return n, v && ok
+	}
+	return 0, false
+}
@@ -0,0 +1,53 @@
+package dev
+
+import (
+	"encoding/json"
+	"math/rand"
+	"testing"
+
+	"devkit/internal/schema"
+)
+
+func TestJSONMockMatchesSchema(t *testing.T) {
+	schemas := map[string]string{
+		"negative maximum":  `{"type": "integer", "maximum": -5}`,
+		"positive minimum":  `{"type": "integer", "minimum": 2000}`,
+		"exclusive integer": `{"type": "integer", "exclusiveMinimum": 1, "exclusiveMaximum": 3}`,
+		"draft 4 exclusive": `{"type": "integer", "minimum": 0, "maximum": 2, "exclusiveMinimum": true, "exclusiveMaximum": true}`,
+		"exclusive number":  `{"type": "number", "exclusiveMinimum": 0, "exclusiveMaximum": 0.01}`,
+		"number maximum":    `{"type": "number", "maximum": -1.5}`,
+		"fixed length":      `{"type": "string", "minLength": 6, "maxLength": 6}`,
+		"short string":      `{"type": "string", "maxLength": 3}`,
+		"long string":       `{"type": "string", "minLength": 40}`,
+		"no items":          `{"type": "array", "maxItems": 0, "items": {"type": "string"}}`,
+		"item range":        `{"type": "array", "minItems": 2, "maxItems": 3, "items": {"type": "boolean"}}`,
+		"nested object": `{"type": "object", "required": ["id", "tags", "score"], "properties": {
+			"id": {"type": "string", "format": "uuid"},
+			"tags": {"type": "array", "maxItems": 2, "items": {"type": "string", "minLength": 5, "maxLength": 5}},
+			"score": {"type": "number", "minimum": -1, "maximum": 1},
+			"kind": {"enum": ["a", "b"]}}}`,
+	}
+
+	for name, src := range schemas {
+		t.Run(name, func(t *testing.T) {
+			var s map[string]interface{}
+			if err := json.Unmarshal([]byte(src), &s); err != nil {
+				t.Fatal(err)
+			}
+			for seed := int64(1); seed <= 200; seed++ {
+				m := &jsonMocker{r: rand.New(rand.NewSource(seed))}
+				data, err := json.Marshal(m.fromSchema(s, 0))
+				if err != nil {
+					t.Fatal(err)
+				}
+				var doc interface{}
+				if err := json.Unmarshal(data, &doc); err != nil {
+					t.Fatal(err)
+				}
+				if errs := schema.Validate(s, doc); len(errs) > 0 {
+					t.Fatalf("seed %d: %s does not match the schema: %v", seed, data, errs)
+				}
+			}
+		})
+	}
+}
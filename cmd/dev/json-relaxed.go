@@ -0,0 +1,118 @@
+package dev
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// useRelaxedJSON reports whether comments and trailing commas should be accepted
+func useRelaxedJSON(cmd *cobra.Command) bool {
+	relaxed, _ := cmd.Flags().GetBool("relaxed")
+	if relaxed {
+		return true
+	}
+
+	// tsconfig-style files are always relaxed. JSON5 is not detected: it
+	// also allows unquoted keys, hex numbers and Infinity, which are not
+	// accepted.
+	fileFlag, _ := cmd.Flags().GetString("file")
+	return strings.ToLower(filepath.Ext(fileFlag)) == ".jsonc"
+}
+
+// jsoncError is a comment error in a JSONC document, at a byte offset
+type jsoncError struct {
+	msg    string
+	Offset int64
+}
+
+func (e *jsoncError) Error() string { return e.msg }
+
+// stripJSONC removes // and /* */ comments and trailing commas so that
+// JSONC documents (tsconfig.json, VS Code settings) become strict JSON.
+// Removed text is replaced with spaces and newlines are kept, so that
+// parse errors point at the same line and column as in the input.
+func stripJSONC(input string) (string, error) {
+	b := []byte(input)
+
+	inString := false
+	for i := 0; i < len(b); i++ {
+		c := b[i]
+
+		if inString {
+			if c == '\\' && i+1 < len(b) {
+				i++
+			} else if c == '"' {
+				inString = false
+			}
+			continue
+		}
+
+		switch {
+		case c == '"':
+			inString = true
+		case c == '/' && i+1 < len(b) && b[i+1] == '/':
+			for ; i < len(b) && b[i] != '\n'; i++ {
+				blankJSONC(b, i)
+			}
+		case c == '/' && i+1 < len(b) && b[i+1] == '*':
+			end := strings.Index(input[i+2:], "*/")
+			if end < 0 {
+				return "", &jsoncError{msg: "unterminated /* comment", Offset: int64(i)}
+			}
+			for stop := i + 2 + end + 2; i < stop; i++ {
+				blankJSONC(b, i)
+			}
+			i--
+		}
+	}
+
+	return stripTrailingCommas(string(b)), nil
+}
+
+// blankJSONC replaces the byte at i with a space unless it ends a line
+func blankJSONC(b []byte, i int) {
+	if b[i] != '\n' && b[i] != '\r' {
+		b[i] = ' '
+	}
+}
+
+// stripTrailingCommas replaces commas that directly precede a closing
+// bracket with a space
+func stripTrailingCommas(input string) string {
+	var b strings.Builder
+	b.Grow(len(input))
+
+	inString := false
+	for i := 0; i < len(input); i++ {
+		c := input[i]
+
+		if inString {
+			b.WriteByte(c)
+			if c == '\\' && i+1 < len(input) {
+				i++
+				b.WriteByte(input[i])
+			} else if c == '"' {
+				inString = false
+			}
+			continue
+		}
+
+		if c == '"' {
+			inString = true
+		} else if c == ',' {
+			j := i + 1
+			for j < len(input) && strings.IndexByte(" \t\r\n", input[j]) >= 0 {
+				j++
+			}
+			if j < len(input) && (input[j] == '}' || input[j] == ']') {
+				b.WriteByte(' ')
+				continue
+			}
+		}
+		b.WriteByte(c)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,42 @@
+package dev
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestStripJSONCKeepsPositions(t *testing.T) {
+	input := "{\n  /* a\n     b */ \"a\": [1, 2,], // note\n  \"b\": \"/* kept */\",\n}"
+	got, err := stripJSONC(input)
+	if err != nil {
+		t.Fatalf("stripJSONC: %v", err)
+	}
+	if len(got) != len(input) {
+		t.Fatalf("length changed from %d to %d: %q", len(input), len(got), got)
+	}
+	for i := range input {
+		if (input[i] == '\n') != (got[i] == '\n') {
+			t.Fatalf("line breaks moved: %q", got)
+		}
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal([]byte(got), &doc); err != nil {
+		t.Fatalf("stripped document is not JSON: %v\n%s", err, got)
+	}
+	if doc["b"] != "/* kept */" {
+		t.Errorf("comment inside a string was removed: %v", doc["b"])
+	}
+}
+
+func TestStripJSONCUnterminatedComment(t *testing.T) {
+	input := "{\n  \"a\": 1 /* open\n}"
+	_, err := stripJSONC(input)
+	if err == nil {
+		t.Fatal("stripJSONC accepted an unterminated comment")
+	}
+	line, column := jsonErrorPosition(input, err)
+	if line != 2 || column != strings.Index("  \"a\": 1 /* open", "/*")+1 {
+		t.Errorf("error at %d:%d, want the comment start", line, column)
+	}
+}
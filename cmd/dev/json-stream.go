@@ -0,0 +1,198 @@
+package dev
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// jsonStreamFrame tracks the state of an open object or array while streaming
+type jsonStreamFrame struct {
+	object bool
+	count  int
+}
+
+// useJSONStream reports whether the streaming path should be used for the input
+func useJSONStream(cmd *cobra.Command) bool {
+	stream, _ := cmd.Flags().GetBool("stream")
+	if stream {
+		return true
+	}
+
+	// NDJSON / JSON Lines files always hold several documents
+	fileFlag, _ := cmd.Flags().GetString("file")
+	switch strings.ToLower(filepath.Ext(fileFlag)) {
+	case ".ndjson", ".jsonl":
+		return true
+	}
+	return false
+}
+
+// getJSONReader returns a reader for the JSON input without loading it into memory
+func getJSONReader(cmd *cobra.Command, args []string) (io.ReadCloser, error) {
+	fileFlag, _ := cmd.Flags().GetString("file")
+	stdinFlag, _ := cmd.Flags().GetBool("stdin")
+
+	if stdinFlag {
+		stat, err := os.Stdin.Stat()
+		if err != nil {
+			return nil, fmt.Errorf("stdin error: %w", err)
+		}
+		if (stat.Mode() & os.ModeCharDevice) != 0 {
+			return nil, fmt.Errorf("no data available from stdin")
+		}
+		return io.NopCloser(os.Stdin), nil
+	} else if fileFlag != "" {
+		file, err := os.Open(fileFlag)
+		if err != nil {
+			return nil, fmt.Errorf("read file error: %w", err)
+		}
+		return file, nil
+	} else if len(args) > 0 {
+		return io.NopCloser(strings.NewReader(args[0])), nil
+	}
+	return nil, fmt.Errorf("input not specified")
+}
+
+// runJSONStream reformats the input token by token and writes it to stdout.
+// An empty indent produces minified output with one document per line.
+func runJSONStream(cmd *cobra.Command, args []string, indent string) error {
+	outputFormat, _ := cmd.Flags().GetString("output")
+	if outputFormat == "json" {
+		return fmt.Errorf("--output json is not supported in streaming mode")
+	}
+
+	reader, err := getJSONReader(cmd, args)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	cmd.SilenceUsage = true
+	return reformatJSONStream(reader, os.Stdout, indent)
+}
+
+// reformatJSONStream re-indents or compacts a stream of JSON documents without
+// building them in memory. Several top-level documents (NDJSON) are supported.
+// Output is not held back until a document is complete, so a syntax error
+// leaves what came before it, including part of the broken document, in w,
+// ended with a newline.
+func reformatJSONStream(r io.Reader, w io.Writer, indent string) error {
+	dec := json.NewDecoder(bufio.NewReaderSize(r, 64*1024))
+	dec.UseNumber()
+
+	bw := bufio.NewWriterSize(w, 64*1024)
+	defer bw.Flush()
+
+	newline := func(depth int) {
+		if indent == "" {
+			return
+		}
+		bw.WriteByte('\n')
+		for i := 0; i < depth; i++ {
+			bw.WriteString(indent)
+		}
+	}
+
+	var stack []jsonStreamFrame
+	docs := 0
+
+	// fail ends the last line written before err
+	fail := func(err error) error {
+		if len(stack) > 0 || docs > 0 {
+			bw.WriteByte('\n')
+		}
+		return err
+	}
+
+	// writePrefix emits the separator that belongs before the next token
+	writePrefix := func() {
+		if len(stack) == 0 {
+			if docs > 0 {
+				bw.WriteByte('\n')
+			}
+			return
+		}
+		top := &stack[len(stack)-1]
+		if top.object && top.count%2 == 1 {
+			// Value following a key
+			bw.WriteByte(':')
+			if indent != "" {
+				bw.WriteByte(' ')
+			}
+			top.count++
+			return
+		}
+		if top.count > 0 {
+			bw.WriteByte(',')
+		}
+		newline(len(stack))
+		top.count++
+	}
+
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			if len(stack) != 0 {
+				return fail(fmt.Errorf("invalid JSON: unexpected end of input"))
+			}
+			break
+		}
+		if err != nil {
+			return fail(fmt.Errorf("invalid JSON: %w", err))
+		}
+
+		switch v := tok.(type) {
+		case json.Delim:
+			switch v {
+			case '{', '[':
+				writePrefix()
+				bw.WriteByte(byte(v))
+				stack = append(stack, jsonStreamFrame{object: v == '{'})
+			case '}', ']':
+				frame := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				if frame.count > 0 {
+					newline(len(stack))
+				}
+				bw.WriteByte(byte(v))
+				if len(stack) == 0 {
+					docs++
+				}
+			}
+		default:
+			writePrefix()
+			encoded, err := encodeJSONScalar(v)
+			if err != nil {
+				return fail(err)
+			}
+			bw.Write(encoded)
+			if len(stack) == 0 {
+				docs++
+			}
+		}
+	}
+
+	if docs > 0 {
+		bw.WriteByte('\n')
+	}
+	return nil
+}
+
+// encodeJSONScalar encodes a string, number, bool or null token
+func encodeJSONScalar(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(v); err != nil {
+		return nil, fmt.Errorf("failed to encode value: %w", err)
+	}
+	return bytes.TrimRight(buf.Bytes(), "\n"), nil
+}
@@ -0,0 +1,30 @@
+package dev
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestReformatJSONStream(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+		err   bool
+	}{
+		{input: `{"a": [1, 2]} 3`, want: "{\"a\":[1,2]}\n3\n"},
+		{input: "{\"a\": 1}\n{\"b\":", want: "{\"a\":1}\n{\"b\"\n", err: true},
+		{input: "{\"a\": 1}\n]", want: "{\"a\":1}\n", err: true},
+		{input: "]", want: "", err: true},
+	}
+	for _, tt := range tests {
+		var out bytes.Buffer
+		err := reformatJSONStream(strings.NewReader(tt.input), &out, "")
+		if (err != nil) != tt.err {
+			t.Errorf("%q: error = %v, want error %v", tt.input, err, tt.err)
+		}
+		if out.String() != tt.want {
+			t.Errorf("%q: wrote %q, want %q", tt.input, out.String(), tt.want)
+		}
+	}
+}
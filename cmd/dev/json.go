@@ -2,13 +2,18 @@ package dev
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
+	"regexp"
+	"strconv"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/tidwall/gjson"
 	"github.com/tidwall/pretty"
+	"devkit/internal/check"
 	"devkit/internal/output"
 )
 
@@ -31,9 +36,20 @@ var jsonPrettifyCmd = &cobra.Command{
 	Short: "Prettify JSON string",
 	Long: `Format JSON string with indentation.
 
+Use --stream to reformat huge files token by token without loading them
+into memory. Files ending in .ndjson or .jsonl are streamed automatically,
+one document per line. Streamed output is written as the input is read, so
+on a syntax error the part before it has already been printed.
+
+Use --relaxed (automatic for .jsonc files) to accept comments and trailing
+commas; the output is always strict JSON. Other JSON5 syntax is not
+supported.
+
 Examples:
   devkit dev json prettify '{"a":1,"b":2}'
-  devkit dev json prettify --file data.json`,
+  devkit dev json prettify --file data.json
+  devkit dev json prettify --file huge.json --stream
+  devkit dev json prettify --file tsconfig.json --relaxed`,
 	RunE: runJSONPrettify,
 }
 
@@ -43,9 +59,20 @@ var jsonMinifyCmd = &cobra.Command{
 	Short: "Minify JSON string",
 	Long: `Remove whitespace from JSON string.
 
+Use --stream to reformat huge files token by token without loading them
+into memory. Multiple documents are written one per line (NDJSON). Streamed
+output is written as the input is read, so on a syntax error the part before
+it has already been printed.
+
+Use --relaxed (automatic for .jsonc files) to accept comments and trailing
+commas; the output is always strict JSON. Other JSON5 syntax is not
+supported.
+
 Examples:
   devkit dev json minify '{"a": 1, "b": 2}'
-  devkit dev json minify --file data.json`,
+  devkit dev json minify --file data.json
+  devkit dev json minify --file events.ndjson
+  devkit dev json minify --file settings.jsonc > settings.json`,
 	RunE: runJSONMinify,
 }
 
@@ -55,9 +82,20 @@ var jsonValidateCmd = &cobra.Command{
 	Short: "Validate JSON string",
 	Long: `Check if a string is valid JSON.
 
+With --relaxed (automatic for .jsonc files), // and /* */ comments and
+trailing commas are accepted; a /* comment that is never closed is an
+error. Other JSON5 syntax, such as unquoted keys, single-quoted strings,
+hex numbers or Infinity, is not.
+
+Exits with status 2 when the input is invalid, or 0 with --fail-on none.
+--vimgrep prints the error as path:line:column: message for editors, and
+nothing for valid input.
+
 Examples:
   devkit dev json validate '{"a":1}'
-  devkit dev json validate --file data.json`,
+  devkit dev json validate --file data.json
+  devkit dev json validate --file tsconfig.json --relaxed
+  devkit dev json validate --file data.json --vimgrep`,
 	RunE: runJSONValidate,
 }
 
@@ -73,29 +111,74 @@ Examples:
 	RunE: runJSONPath,
 }
 
+// jsonEscapeCmd represents the escape subcommand
+var jsonEscapeCmd = &cobra.Command{
+	Use:   "escape [text]",
+	Short: "Escape text into a JSON string literal",
+	Long: `Escape arbitrary text into a valid JSON string literal.
+
+Examples:
+  devkit dev json escape 'say "hi"'
+  devkit dev json escape --file payload.json
+  devkit dev json escape --file payload.json --no-quotes`,
+	RunE: runJSONEscape,
+}
+
+// jsonUnescapeCmd represents the unescape subcommand
+var jsonUnescapeCmd = &cobra.Command{
+	Use:   "unescape [literal]",
+	Short: "Unescape a JSON string literal",
+	Long: `Unescape a JSON string literal (including \uXXXX sequences) back to text.
+The surrounding quotes are optional.
+
+Examples:
+  devkit dev json unescape '"say \"hi\""'
+  devkit dev json unescape 'caf\u00e9'
+  echo '"line1\nline2"' | devkit dev json unescape --stdin`,
+	RunE: runJSONUnescape,
+}
+
 func init() {
 	devCmd.AddCommand(jsonCmd)
 	jsonCmd.AddCommand(jsonPrettifyCmd)
 	jsonCmd.AddCommand(jsonMinifyCmd)
 	jsonCmd.AddCommand(jsonValidateCmd)
 	jsonCmd.AddCommand(jsonPathCmd)
+	jsonCmd.AddCommand(jsonEscapeCmd)
+	jsonCmd.AddCommand(jsonUnescapeCmd)
 
 	// Flag definitions
 	jsonPrettifyCmd.Flags().StringP("file", "f", "", "Input file path")
 	jsonPrettifyCmd.Flags().BoolP("stdin", "s", false, "Read from stdin")
 	jsonPrettifyCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
+	jsonPrettifyCmd.Flags().Bool("relaxed", false, "Accept comments and trailing commas (JSONC)")
+	jsonPrettifyCmd.Flags().Bool("stream", false, "Stream the input instead of loading it into memory")
 
 	jsonMinifyCmd.Flags().StringP("file", "f", "", "Input file path")
 	jsonMinifyCmd.Flags().BoolP("stdin", "s", false, "Read from stdin")
 	jsonMinifyCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
+	jsonMinifyCmd.Flags().Bool("relaxed", false, "Accept comments and trailing commas (JSONC)")
+	jsonMinifyCmd.Flags().Bool("stream", false, "Stream the input instead of loading it into memory")
 
 	jsonValidateCmd.Flags().StringP("file", "f", "", "Input file path")
 	jsonValidateCmd.Flags().BoolP("stdin", "s", false, "Read from stdin")
 	jsonValidateCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
+	check.AddFailOnFlag(jsonValidateCmd, check.Invalid)
+	jsonValidateCmd.Flags().Bool("relaxed", false, "Accept comments and trailing commas (JSONC)")
+	jsonValidateCmd.Flags().Bool("vimgrep", false, "Print errors as path:line:column: message for editors")
 
 	jsonPathCmd.Flags().StringP("file", "f", "", "Input file path")
 	jsonPathCmd.Flags().BoolP("stdin", "s", false, "Read from stdin")
 	jsonPathCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
+
+	jsonEscapeCmd.Flags().StringP("file", "f", "", "Input file path")
+	jsonEscapeCmd.Flags().BoolP("stdin", "s", false, "Read from stdin")
+	jsonEscapeCmd.Flags().Bool("no-quotes", false, "Omit the surrounding quotes")
+	jsonEscapeCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
+
+	jsonUnescapeCmd.Flags().StringP("file", "f", "", "Input file path")
+	jsonUnescapeCmd.Flags().BoolP("stdin", "s", false, "Read from stdin")
+	jsonUnescapeCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
 }
 
 func getJSONInput(cmd *cobra.Command, args []string) (string, error) {
@@ -128,7 +211,57 @@ func getJSONInput(cmd *cobra.Command, args []string) (string, error) {
 	return "", fmt.Errorf("input not specified")
 }
 
+// errorLinePattern finds the line, and the column when given, mentioned in
+// a parser error such as "yaml: line 3: ..." or "XML syntax error on line 7"
+var errorLinePattern = regexp.MustCompile(`line (\d+)(?:, column (\d+))?`)
+
+// inputLocation places a validation error for --vimgrep: in the --file
+// path, or "-" for other input, at line and column, which are read from the
+// error message when line is 0
+func inputLocation(cmd *cobra.Command, line, column int, err error) output.Location {
+	file, _ := cmd.Flags().GetString("file")
+	if stdin, _ := cmd.Flags().GetBool("stdin"); stdin || file == "" {
+		file = "-"
+	}
+	if line == 0 {
+		if m := errorLinePattern.FindStringSubmatch(err.Error()); m != nil {
+			line, _ = strconv.Atoi(m[1])
+			column, _ = strconv.Atoi(m[2])
+		}
+	}
+	return output.Location{File: file, Line: line, Column: column, Message: err.Error()}
+}
+
+// jsonErrorPosition returns the line and column of a JSON syntax or type
+// error in input, or 0, 0
+func jsonErrorPosition(input string, err error) (int, int) {
+	var offset int64
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	var commentErr *jsoncError
+	switch {
+	case errors.As(err, &syntaxErr):
+		offset = syntaxErr.Offset
+	case errors.As(err, &commentErr):
+		offset = commentErr.Offset + 1
+	case errors.As(err, &typeErr):
+		offset = typeErr.Offset
+	default:
+		return 0, 0
+	}
+	offset = min(offset, int64(len(input)))
+	before := input[:offset]
+	return strings.Count(before, "\n") + 1, int(offset) - strings.LastIndex(before, "\n") - 1
+}
+
 func runJSONPrettify(cmd *cobra.Command, args []string) error {
+	if useJSONStream(cmd) {
+		if useRelaxedJSON(cmd) {
+			return fmt.Errorf("--relaxed cannot be combined with streaming mode")
+		}
+		return runJSONStream(cmd, args, "  ")
+	}
+
 	outputFormat, _ := cmd.Flags().GetString("output")
 	format := output.OutputFormat(outputFormat)
 
@@ -136,6 +269,11 @@ func runJSONPrettify(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	if useRelaxedJSON(cmd) {
+		if jsonInput, err = stripJSONC(jsonInput); err != nil {
+			return fmt.Errorf("invalid JSON: %w", err)
+		}
+	}
 
 	// Parse and prettify
 	var data interface{}
@@ -162,6 +300,13 @@ func runJSONPrettify(cmd *cobra.Command, args []string) error {
 }
 
 func runJSONMinify(cmd *cobra.Command, args []string) error {
+	if useJSONStream(cmd) {
+		if useRelaxedJSON(cmd) {
+			return fmt.Errorf("--relaxed cannot be combined with streaming mode")
+		}
+		return runJSONStream(cmd, args, "")
+	}
+
 	outputFormat, _ := cmd.Flags().GetString("output")
 	format := output.OutputFormat(outputFormat)
 
@@ -169,6 +314,11 @@ func runJSONMinify(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	if useRelaxedJSON(cmd) {
+		if jsonInput, err = stripJSONC(jsonInput); err != nil {
+			return fmt.Errorf("invalid JSON: %w", err)
+		}
+	}
 
 	// Parse and minify
 	var data interface{}
@@ -202,10 +352,32 @@ func runJSONValidate(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	// A comment error is reported like a parse error, at its position in
+	// the input
+	var stripErr error
+	if useRelaxedJSON(cmd) {
+		var stripped string
+		if stripped, stripErr = stripJSONC(jsonInput); stripErr == nil {
+			jsonInput = stripped
+		}
+	}
 
 	// Validate JSON
 	var data interface{}
-	isValid := json.Unmarshal([]byte(jsonInput), &data) == nil
+	parseErr := stripErr
+	if parseErr == nil {
+		parseErr = json.Unmarshal([]byte(jsonInput), &data)
+	}
+	isValid := parseErr == nil
+
+	if vimgrep, _ := cmd.Flags().GetBool("vimgrep"); vimgrep {
+		if !isValid {
+			line, column := jsonErrorPosition(jsonInput, parseErr)
+			output.PrintLocations([]output.Location{inputLocation(cmd, line, column, parseErr)})
+			return check.Result(cmd, check.Invalid, "invalid JSON")
+		}
+		return nil
+	}
 
 	if format == output.FormatJSON {
 		result := map[string]interface{}{
@@ -217,12 +389,15 @@ func runJSONValidate(cmd *cobra.Command, args []string) error {
 		output.PrintSuccess(format, result)
 	} else {
 		if isValid {
-			output.PrintSuccess(format, "✓ Valid JSON")
+			output.PrintSuccess(format, output.OK("Valid JSON"))
 		} else {
-			output.PrintError(format, fmt.Errorf("✗ Invalid JSON"))
+			output.PrintError(format, fmt.Errorf("%s", output.Fail("Invalid JSON")))
 		}
 	}
 
+	if !isValid {
+		return check.Result(cmd, check.Invalid, "invalid JSON")
+	}
 	return nil
 }
 
@@ -249,7 +424,7 @@ func runJSONPath(cmd *cobra.Command, args []string) error {
 
 	if format == output.FormatJSON {
 		output.PrintSuccess(format, map[string]interface{}{
-			"query": query,
+			"query":  query,
 			"result": result.Value(),
 		})
 	} else {
@@ -264,3 +439,66 @@ func runJSONPath(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+func runJSONEscape(cmd *cobra.Command, args []string) error {
+	noQuotes, _ := cmd.Flags().GetBool("no-quotes")
+	outputFormat, _ := cmd.Flags().GetString("output")
+	format := output.OutputFormat(outputFormat)
+
+	input, err := getJSONInput(cmd, args)
+	if err != nil {
+		return err
+	}
+
+	encoded, err := encodeJSONScalar(input)
+	if err != nil {
+		return err
+	}
+
+	result := string(encoded)
+	if noQuotes {
+		result = result[1 : len(result)-1]
+	}
+
+	if format == output.FormatJSON {
+		output.PrintSuccess(format, map[string]interface{}{
+			"escaped": result,
+			"input":   input,
+		})
+	} else {
+		output.PrintSuccess(format, result)
+	}
+
+	return nil
+}
+
+func runJSONUnescape(cmd *cobra.Command, args []string) error {
+	outputFormat, _ := cmd.Flags().GetString("output")
+	format := output.OutputFormat(outputFormat)
+
+	input, err := getJSONInput(cmd, args)
+	if err != nil {
+		return err
+	}
+
+	literal := strings.TrimSpace(input)
+	if len(literal) < 2 || !strings.HasPrefix(literal, `"`) || !strings.HasSuffix(literal, `"`) {
+		literal = `"` + literal + `"`
+	}
+
+	var unescaped string
+	if err := json.Unmarshal([]byte(literal), &unescaped); err != nil {
+		return fmt.Errorf("invalid JSON string literal: %w", err)
+	}
+
+	if format == output.FormatJSON {
+		output.PrintSuccess(format, map[string]interface{}{
+			"unescaped": unescaped,
+			"input":     input,
+		})
+	} else {
+		output.PrintSuccess(format, unescaped)
+	}
+
+	return nil
+}
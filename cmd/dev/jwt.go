@@ -11,6 +11,7 @@ import (
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/spf13/cobra"
+	"devkit/internal/check"
 	"devkit/internal/output"
 )
 
@@ -45,11 +46,19 @@ Examples:
 var jwtVerifyCmd = &cobra.Command{
 	Use:   "verify [token]",
 	Short: "Verify JWT token signature",
-	Long: `Verify a JWT token's signature using a secret key.
+	Long: `Verify a JWT token's signature using a secret key, and check its exp and
+nbf claims.
+
+Exit status: 2 when the signature or a claim is invalid, 3 when the token
+has expired, 4 when it expires within --warn-within. --fail-on sets the
+lowest level that fails the command (default expired); with --fail-on
+warning a token close to expiry also fails, with none the command only
+reports.
 
 Examples:
   devkit dev jwt verify "eyJ..." --secret "my-secret-key"
-  devkit dev jwt verify --file token.txt --secret "my-secret-key"`,
+  devkit dev jwt verify --file token.txt --secret "my-secret-key"
+  devkit dev jwt verify --file token.txt -k "$SECRET" --warn-within 24h --fail-on warning`,
 	RunE: runJWTVerify,
 }
 
@@ -68,6 +77,8 @@ func init() {
 	jwtVerifyCmd.Flags().BoolP("stdin", "s", false, "Read from stdin")
 	jwtVerifyCmd.Flags().StringP("secret", "k", "", "Secret key for verification (required)")
 	jwtVerifyCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json, table")
+	jwtVerifyCmd.Flags().Duration("warn-within", 0, "Warn when the token expires within this duration, e.g. 24h")
+	check.AddFailOnFlag(jwtVerifyCmd, check.Expired)
 	jwtVerifyCmd.MarkFlagRequired("secret")
 }
 
@@ -178,7 +189,7 @@ func runJWTDecode(cmd *cobra.Command, args []string) error {
 		fmt.Println("\nClaims:")
 		fmt.Println(string(claimsJSON))
 		if isExpired(claims) {
-			fmt.Println("\n⚠ Token is expired")
+			fmt.Println("\n" + output.Warn("Token is expired"))
 		}
 	}
 
@@ -226,17 +237,27 @@ func runJWTVerify(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("secret key is required (use --secret)")
 	}
 
-	// Parse and verify token
+	// Parse and verify the signature. Time-based claims are checked
+	// separately so that an expired token with a good signature is reported
+	// as expired rather than as a parse error.
 	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
 		// Check signing method
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
 		return []byte(secret), nil
-	})
+	}, jwt.WithoutClaimsValidation())
 
 	if err != nil {
-		return fmt.Errorf("verification failed: %w", err)
+		if format == output.FormatJSON {
+			output.PrintSuccess(format, map[string]interface{}{
+				"valid": false,
+				"error": err.Error(),
+			})
+		} else {
+			fmt.Println(output.Fail(fmt.Sprintf("Token is invalid: %v", err)))
+		}
+		return check.Result(cmd, check.Invalid, "token is invalid")
 	}
 
 	// Extract claims
@@ -245,27 +266,67 @@ func runJWTVerify(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to extract claims")
 	}
 
+	warnWithin, _ := cmd.Flags().GetDuration("warn-within")
+	level, message := jwtClaimsLevel(claims, warnWithin)
+
 	// Prepare result
 	result := map[string]interface{}{
-		"valid":   token.Valid,
-		"expired": isExpired(claims),
+		"valid":   level != check.Invalid,
+		"expired": level == check.Expired,
 		"claims":  claims,
 	}
+	if level != check.None {
+		result["level"] = level.String()
+		result["message"] = message
+	}
 
 	if format == output.FormatJSON {
 		output.PrintSuccess(format, result)
 	} else {
-		if token.Valid {
-			fmt.Println("✓ Token is valid")
-			if isExpired(claims) {
-				fmt.Println("⚠ Token is expired")
-			}
-		} else {
-			fmt.Println("✗ Token is invalid")
+		switch level {
+		case check.None:
+			fmt.Println(output.OK("Token is valid"))
+		case check.Warning:
+			fmt.Println(output.OK("Signature is valid"))
+			fmt.Println(output.Warn(message))
+		case check.Expired:
+			fmt.Println(output.OK("Signature is valid"))
+			fmt.Println(output.Fail(message))
+		default:
+			fmt.Println(output.Fail(message))
 		}
 	}
 
-	return nil
+	if level == check.None {
+		return nil
+	}
+	return check.Result(cmd, level, strings.ToLower(message[:1])+message[1:])
+}
+
+// jwtClaimsLevel checks the exp and nbf claims of a token whose signature
+// is valid. A token that expires within warnWithin is a warning.
+func jwtClaimsLevel(claims jwt.MapClaims, warnWithin time.Duration) (check.Level, string) {
+	now := time.Now()
+	if nbf, err := claims.GetNotBefore(); err != nil {
+		return check.Invalid, fmt.Sprintf("Token has an invalid nbf claim: %v", err)
+	} else if nbf != nil && now.Before(nbf.Time) {
+		return check.Invalid, fmt.Sprintf("Token is not valid before %s", nbf.Format(time.RFC3339))
+	}
+
+	exp, err := claims.GetExpirationTime()
+	if err != nil {
+		return check.Invalid, fmt.Sprintf("Token has an invalid exp claim: %v", err)
+	}
+	if exp == nil {
+		return check.None, ""
+	}
+	if now.After(exp.Time) {
+		return check.Expired, fmt.Sprintf("Token expired at %s", exp.Format(time.RFC3339))
+	}
+	if warnWithin > 0 && exp.Sub(now) < warnWithin {
+		return check.Warning, fmt.Sprintf("Token expires at %s (in %s)", exp.Format(time.RFC3339), exp.Sub(now).Round(time.Second))
+	}
+	return check.None, ""
 }
 
 func isExpired(claims jwt.MapClaims) bool {
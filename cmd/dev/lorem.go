@@ -2,9 +2,13 @@ package dev
 
 import (
 	"fmt"
+	"html"
 	"math/rand"
+	"os"
 	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/spf13/cobra"
 	"devkit/internal/output"
@@ -18,10 +22,20 @@ var loremCmd = &cobra.Command{
 
 Types: word, sentence, paragraph
 
+Formats: text (default), html (paragraphs wrapped in <p> tags) and
+markdown (each paragraph under a "##" heading).
+
+--seed makes the text reproducible, e.g. for snapshot tests;
+--start-with-lorem begins with the classic "Lorem ipsum dolor sit amet";
+--corpus takes the words from a file instead of the Latin word list.
+
 Examples:
   devkit dev lorem word --count 5
   devkit dev lorem sentence --count 3
-  devkit dev lorem paragraph --count 2`,
+  devkit dev lorem paragraph --count 2
+  devkit dev lorem paragraph --count 3 --format html --seed 42
+  devkit dev lorem paragraph --format markdown --start-with-lorem
+  devkit dev lorem sentence --count 5 --corpus words.txt`,
 	RunE: runLorem,
 }
 
@@ -37,15 +51,31 @@ var loremWords = []string{
 	"anim", "id", "est", "laborum",
 }
 
+// loremOpening is the classic first sentence used with --start-with-lorem
+const loremOpening = "Lorem ipsum dolor sit amet, consectetur adipiscing elit."
+
+// loremGenerator produces words, sentences and paragraphs from a word list
+type loremGenerator struct {
+	r     *rand.Rand
+	words []string
+}
+
 func init() {
 	devCmd.AddCommand(loremCmd)
 
 	loremCmd.Flags().IntP("count", "c", 1, "Number of items to generate")
+	loremCmd.Flags().String("format", "text", "Text format: text, html, markdown")
+	loremCmd.Flags().Int64("seed", 0, "Seed for reproducible text")
+	loremCmd.Flags().Bool("start-with-lorem", false, "Start with \"Lorem ipsum dolor sit amet\"")
+	loremCmd.Flags().String("corpus", "", "File whose words are used instead of the Latin word list")
 	loremCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
 }
 
 func runLorem(cmd *cobra.Command, args []string) error {
 	count, _ := cmd.Flags().GetInt("count")
+	textFormat, _ := cmd.Flags().GetString("format")
+	startWithLorem, _ := cmd.Flags().GetBool("start-with-lorem")
+	corpus, _ := cmd.Flags().GetString("corpus")
 	outputFormat, _ := cmd.Flags().GetString("output")
 	format := output.OutputFormat(outputFormat)
 
@@ -60,33 +90,64 @@ func runLorem(cmd *cobra.Command, args []string) error {
 		loremType = "word"
 	}
 
-	var result string
-	var results []string
+	switch textFormat {
+	case "text", "html", "markdown":
+	default:
+		return fmt.Errorf("invalid format: %s (supported: text, html, markdown)", textFormat)
+	}
+
+	seed := time.Now().UnixNano()
+	if cmd.Flags().Changed("seed") {
+		seed, _ = cmd.Flags().GetInt64("seed")
+	}
+	gen := &loremGenerator{r: rand.New(rand.NewSource(seed)), words: loremWords}
+	if corpus != "" {
+		words, err := readLoremCorpus(corpus)
+		if err != nil {
+			return err
+		}
+		gen.words = words
+	}
 
+	var results []string
 	switch loremType {
 	case "word":
-		words := generateWords(count)
-		result = strings.Join(words, " ")
-		results = words
+		results = gen.generateWords(count)
+		if startWithLorem {
+			opening := strings.Fields(strings.ToLower(strings.Trim(loremOpening, ".")))
+			for i := 0; i < len(results) && i < len(opening); i++ {
+				results[i] = strings.Trim(opening[i], ",")
+			}
+		}
 	case "sentence":
-		sentences := generateSentences(count)
-		result = strings.Join(sentences, " ")
-		results = sentences
+		results = gen.generateSentences(count)
+		if startWithLorem {
+			results[0] = loremOpening
+		}
 	case "paragraph":
-		paragraphs := generateParagraphs(count)
-		result = strings.Join(paragraphs, "\n\n")
-		results = paragraphs
+		results = gen.generateParagraphs(count)
+		if startWithLorem {
+			sentences := gen.generateSentences(gen.r.Intn(5) + 2)
+			results[0] = loremOpening + " " + strings.Join(sentences, " ")
+		}
 	default:
 		return fmt.Errorf("invalid type: %s (supported: word, sentence, paragraph)", loremType)
 	}
 
+	result := formatLorem(gen, loremType, textFormat, results)
+
 	if format == output.FormatJSON {
-		output.PrintSuccess(format, map[string]interface{}{
+		data := map[string]interface{}{
 			"type":   loremType,
 			"count":  count,
+			"format": textFormat,
 			"text":   result,
 			"items":  results,
-		})
+		}
+		if cmd.Flags().Changed("seed") {
+			data["seed"] = seed
+		}
+		output.PrintSuccess(format, data)
 	} else {
 		output.PrintSuccess(format, result)
 	}
@@ -94,35 +155,89 @@ func runLorem(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func generateWords(count int) []string {
-	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+// formatLorem joins the generated items as plain text, HTML paragraphs or
+// Markdown sections
+func formatLorem(gen *loremGenerator, loremType, textFormat string, items []string) string {
+	switch {
+	case textFormat == "html" && loremType == "paragraph":
+		blocks := make([]string, len(items))
+		for i, item := range items {
+			blocks[i] = "<p>" + html.EscapeString(item) + "</p>"
+		}
+		return strings.Join(blocks, "\n")
+	case textFormat == "html":
+		return "<p>" + html.EscapeString(strings.Join(items, " ")) + "</p>"
+	case textFormat == "markdown" && loremType == "paragraph":
+		blocks := make([]string, len(items))
+		for i, item := range items {
+			blocks[i] = "## " + gen.generateHeading() + "\n\n" + item
+		}
+		return strings.Join(blocks, "\n\n")
+	case loremType == "paragraph":
+		return strings.Join(items, "\n\n")
+	}
+	return strings.Join(items, " ")
+}
+
+// readLoremCorpus reads the words of a file, without surrounding
+// punctuation
+func readLoremCorpus(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read corpus: %w", err)
+	}
+	var words []string
+	for _, field := range strings.Fields(string(data)) {
+		if word := strings.Trim(field, ".,;:!?\"'()[]{}"); word != "" {
+			words = append(words, word)
+		}
+	}
+	if len(words) == 0 {
+		return nil, fmt.Errorf("corpus %s contains no words", path)
+	}
+	return words, nil
+}
+
+func (g *loremGenerator) generateWords(count int) []string {
 	words := make([]string, count)
 	for i := 0; i < count; i++ {
-		words[i] = loremWords[r.Intn(len(loremWords))]
+		words[i] = g.words[g.r.Intn(len(g.words))]
 	}
 	return words
 }
 
-func generateSentences(count int) []string {
-	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+func (g *loremGenerator) generateSentences(count int) []string {
 	sentences := make([]string, count)
 	for i := 0; i < count; i++ {
-		wordCount := r.Intn(10) + 5 // 5-15 words per sentence
-		words := generateWords(wordCount)
+		wordCount := g.r.Intn(10) + 5 // 5-15 words per sentence
+		words := g.generateWords(wordCount)
 		sentence := strings.Join(words, " ")
-		sentence = strings.ToUpper(string(sentence[0])) + sentence[1:] + "."
-		sentences[i] = sentence
+		sentences[i] = capitalize(sentence) + "."
 	}
 	return sentences
 }
 
-func generateParagraphs(count int) []string {
-	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+func (g *loremGenerator) generateParagraphs(count int) []string {
 	paragraphs := make([]string, count)
 	for i := 0; i < count; i++ {
-		sentenceCount := r.Intn(5) + 3 // 3-8 sentences per paragraph
-		sentences := generateSentences(sentenceCount)
+		sentenceCount := g.r.Intn(5) + 3 // 3-8 sentences per paragraph
+		sentences := g.generateSentences(sentenceCount)
 		paragraphs[i] = strings.Join(sentences, " ")
 	}
 	return paragraphs
 }
+
+// generateHeading returns two to four capitalized words
+func (g *loremGenerator) generateHeading() string {
+	words := g.generateWords(g.r.Intn(3) + 2)
+	return capitalize(strings.Join(words, " "))
+}
+
+// capitalize upper-cases the first letter of s
+func capitalize(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	if r == utf8.RuneError {
+		return s
+	}
+	return string(unicode.ToUpper(r)) + s[size:]
+}
@@ -0,0 +1,135 @@
+package dev
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math"
+	"math/bits"
+
+	"github.com/spf13/cobra"
+	"devkit/internal/output"
+)
+
+// nanoidAlphabet is the URL-safe alphabet of the reference implementation
+const nanoidAlphabet = "useandom-26T198340PX75pxJACKVERYMINDBUSHWOLF_GQZbfghjklqvwyzrict"
+
+// nanoidCmd represents the nanoid command
+var nanoidCmd = &cobra.Command{
+	Use:   "nanoid",
+	Short: "Generate NanoID values",
+	Long: `Generate NanoID values: short, URL-safe, random IDs.
+
+By default an ID has 21 characters from A-Za-z0-9_- (126 bits of entropy,
+comparable to UUID v4). --size and --alphabet produce IDs compatible with
+services that use a custom generator; every character of the alphabet is
+equally likely, as in the reference implementation.
+
+Examples:
+  devkit dev nanoid                          # Generate a single NanoID
+  devkit dev nanoid --count 5                # Generate 5 NanoIDs
+  devkit dev nanoid --size 10
+  devkit dev nanoid --alphabet 0123456789abcdef --size 12
+  devkit dev nanoid --count 3 --output json`,
+	RunE: runNanoID,
+}
+
+func init() {
+	devCmd.AddCommand(nanoidCmd)
+
+	// Flag definitions
+	nanoidCmd.Flags().IntP("size", "s", 21, "Number of characters per ID")
+	nanoidCmd.Flags().StringP("alphabet", "a", nanoidAlphabet, "Characters to build IDs from (2-256 distinct characters)")
+	nanoidCmd.Flags().IntP("count", "c", 1, "Number of NanoIDs to generate")
+	nanoidCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json, table")
+}
+
+func runNanoID(cmd *cobra.Command, args []string) error {
+	size, _ := cmd.Flags().GetInt("size")
+	alphabet, _ := cmd.Flags().GetString("alphabet")
+	count, _ := cmd.Flags().GetInt("count")
+	outputFormat, _ := cmd.Flags().GetString("output")
+	format := output.OutputFormat(outputFormat)
+
+	if count < 1 {
+		return fmt.Errorf("count must be at least 1")
+	}
+
+	if count > 1000 {
+		return fmt.Errorf("count cannot exceed 1000")
+	}
+
+	if size < 1 || size > 1024 {
+		return fmt.Errorf("size must be between 1 and 1024")
+	}
+
+	chars := []rune(alphabet)
+	if len(chars) < 2 || len(chars) > 256 {
+		return fmt.Errorf("alphabet must have between 2 and 256 characters")
+	}
+	seen := make(map[rune]bool, len(chars))
+	for _, c := range chars {
+		if seen[c] {
+			return fmt.Errorf("alphabet contains %q more than once", c)
+		}
+		seen[c] = true
+	}
+
+	ids, err := generateNanoID(chars, size, count)
+	if err != nil {
+		return fmt.Errorf("failed to generate NanoID: %w", err)
+	}
+
+	// Prepare result based on format
+	if format == output.FormatJSON {
+		result := map[string]interface{}{
+			"count":        count,
+			"size":         size,
+			"entropy_bits": math.Round(float64(size)*math.Log2(float64(len(chars)))*10) / 10,
+			"nanoids":      ids,
+		}
+		if alphabet != nanoidAlphabet {
+			result["alphabet"] = alphabet
+		}
+		output.PrintSuccess(format, result)
+	} else {
+		// Plain format - print each NanoID on a new line
+		if count == 1 {
+			output.PrintSuccess(format, ids[0])
+		} else {
+			output.PrintSuccess(format, ids)
+		}
+	}
+
+	return nil
+}
+
+// generateNanoID builds count IDs of size characters. Random bytes are
+// masked to the smallest power of two covering the alphabet and values
+// outside it are discarded, so that no character is favored.
+func generateNanoID(alphabet []rune, size, count int) ([]string, error) {
+	mask := 1<<bits.Len(uint(len(alphabet)-1)|1) - 1
+	// Read enough bytes per round to usually finish an ID in one round
+	step := int(math.Ceil(1.6 * float64(mask*size) / float64(len(alphabet))))
+	buf := make([]byte, step)
+
+	ids := make([]string, count)
+	for i := range ids {
+		id := make([]rune, 0, size)
+		for len(id) < size {
+			if _, err := rand.Read(buf); err != nil {
+				return nil, err
+			}
+			for _, b := range buf {
+				if idx := int(b) & mask; idx < len(alphabet) {
+					id = append(id, alphabet[idx])
+					if len(id) == size {
+						break
+					}
+				}
+			}
+		}
+		ids[i] = string(id)
+	}
+
+	return ids, nil
+}
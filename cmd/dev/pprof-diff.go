@@ -0,0 +1,195 @@
+package dev
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"devkit/internal/output"
+)
+
+// pprofDiffCmd represents the pprof diff subcommand
+var pprofDiffCmd = &cobra.Command{
+	Use:   "diff <old> <new>",
+	Short: "Compare two profiles function by function",
+	Long: `Compare two profiles of the same kind and list the functions whose flat
+(or, with --cum, cumulative) value changed the most, in either direction.
+
+Changes are given as a percentage of the old total, like go tool pprof
+-diff_base. CPU profiles of different lengths compare better with
+--normalize, which scales the old profile to the total of the new one.
+
+Examples:
+  devkit dev pprof diff before.pb.gz after.pb.gz
+  devkit dev pprof diff old-heap.pb.gz heap.pb.gz --sample inuse_space
+  devkit dev pprof diff base.pb.gz cpu.pb.gz --cum --normalize --top 20
+  devkit dev pprof diff base.pb.gz cpu.pb.gz --output json`,
+	Args: cobra.ExactArgs(2),
+	RunE: runPprofDiff,
+}
+
+func init() {
+	pprofCmd.AddCommand(pprofDiffCmd)
+
+	pprofDiffCmd.Flags().IntP("top", "n", 10, "Number of functions to list (0 for all)")
+	pprofDiffCmd.Flags().String("sample", "", "Sample type to compare, e.g. cpu, alloc_space (default: the profile's default)")
+	pprofDiffCmd.Flags().Bool("cum", false, "Compare cumulative values instead of flat")
+	pprofDiffCmd.Flags().Bool("normalize", false, "Scale the old profile to the total of the new one")
+	pprofDiffCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
+}
+
+// pprofChange is the value of one function in both profiles
+type pprofChange struct {
+	name     string
+	old, new int64
+}
+
+func runPprofDiff(cmd *cobra.Command, args []string) error {
+	top, _ := cmd.Flags().GetInt("top")
+	sampleType, _ := cmd.Flags().GetString("sample")
+	byCum, _ := cmd.Flags().GetBool("cum")
+	normalize, _ := cmd.Flags().GetBool("normalize")
+	outputFormat, _ := cmd.Flags().GetString("output")
+	format := output.OutputFormat(outputFormat)
+
+	if top < 0 {
+		return fmt.Errorf("--top must not be negative")
+	}
+
+	cmd.SilenceUsage = true
+	summaries := make([]*pprofSummary, 2)
+	for i, path := range args {
+		p, err := loadPprof(path)
+		if err != nil {
+			return err
+		}
+		// The new profile is read by the sample type picked for the old one
+		if i == 1 {
+			sampleType = summaries[0].sampleType
+		}
+		index, err := pprofSampleIndex(p, sampleType)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		summaries[i] = summarizePprof(p, index)
+	}
+	base, current := summaries[0], summaries[1]
+	if base.unit != current.unit {
+		return fmt.Errorf("the profiles measure %s in different units (%s and %s)", base.sampleType, base.unit, current.unit)
+	}
+
+	scale := 1.0
+	if normalize && base.total != 0 {
+		scale = float64(current.total) / float64(base.total)
+	}
+	value := func(fn *pprofFunction) int64 {
+		if fn == nil {
+			return 0
+		}
+		if byCum {
+			return fn.Cum
+		}
+		return fn.Flat
+	}
+
+	var changes []pprofChange
+	names := make(map[string]bool)
+	for name := range base.functions {
+		names[name] = true
+	}
+	for name := range current.functions {
+		names[name] = true
+	}
+	for name := range names {
+		c := pprofChange{
+			name: name,
+			old:  int64(float64(value(base.functions[name])) * scale),
+			new:  value(current.functions[name]),
+		}
+		if c.old != c.new {
+			changes = append(changes, c)
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		a, b := abs64(changes[i].new-changes[i].old), abs64(changes[j].new-changes[j].old)
+		if a != b {
+			return a > b
+		}
+		return changes[i].name < changes[j].name
+	})
+	shown := changes
+	if top > 0 && len(shown) > top {
+		shown = shown[:top]
+	}
+
+	baseTotal := int64(float64(base.total) * scale)
+	unit := current.unit
+	if format == output.FormatJSON {
+		rows := make([]map[string]interface{}, 0, len(shown))
+		for _, c := range shown {
+			rows = append(rows, map[string]interface{}{
+				"name":          c.name,
+				"old":           c.old,
+				"new":           c.new,
+				"delta":         c.new - c.old,
+				"delta_percent": pprofPercent(c.new-c.old, baseTotal),
+			})
+		}
+		output.PrintSuccess(format, map[string]interface{}{
+			"old":         args[0],
+			"new":         args[1],
+			"sample_type": current.sampleType,
+			"unit":        unit,
+			"old_total":   baseTotal,
+			"new_total":   current.total,
+			"delta":       current.total - baseTotal,
+			"count":       len(changes),
+			"functions":   rows,
+		})
+		return nil
+	}
+
+	fmt.Printf("Old: %s\n", filepath.Base(args[0]))
+	fmt.Printf("New: %s\n", filepath.Base(args[1]))
+	fmt.Printf("Type: %s (%s)\n", current.sampleType, unit)
+	totalDelta := current.total - baseTotal
+	fmt.Printf("Total: %s -> %s (%s, %+.2f%%)\n", formatPprofValue(baseTotal, unit),
+		formatPprofValue(current.total, unit), signedPprofValue(totalDelta, unit), pprofPercent(totalDelta, baseTotal))
+	fmt.Printf("Showing %d of %d changed functions\n\n", len(shown), len(changes))
+
+	column := "FLAT"
+	if byCum {
+		column = "CUM"
+	}
+	fmt.Println(output.Accent(fmt.Sprintf("%10s %10s %11s %8s  %s", "OLD "+column, "NEW "+column, "DELTA", "DELTA%", "FUNCTION")))
+	fmt.Println(output.Rule(70))
+	for _, c := range shown {
+		delta := c.new - c.old
+		deltaText := fmt.Sprintf("%11s", signedPprofValue(delta, unit))
+		if delta > 0 {
+			deltaText = output.Failure(deltaText)
+		} else {
+			deltaText = output.Success(deltaText)
+		}
+		fmt.Printf("%10s %10s %s %+7.2f%%  %s\n", formatPprofValue(c.old, unit), formatPprofValue(c.new, unit),
+			deltaText, pprofPercent(delta, baseTotal), c.name)
+	}
+	return nil
+}
+
+// signedPprofValue formats a change with its sign
+func signedPprofValue(delta int64, unit string) string {
+	if delta > 0 {
+		return "+" + formatPprofValue(delta, unit)
+	}
+	return formatPprofValue(delta, unit)
+}
+
+// abs64 returns the absolute value of n
+func abs64(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
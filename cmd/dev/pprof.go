@@ -0,0 +1,324 @@
+package dev
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/pprof/profile"
+	"github.com/spf13/cobra"
+	"devkit/internal/output"
+)
+
+// pprofCmd represents the pprof command group
+var pprofCmd = &cobra.Command{
+	Use:   "pprof",
+	Short: "Summarize Go pprof profiles",
+	Long: `Summarize Go pprof profiles (CPU, heap, allocs, block, mutex, goroutine) in
+the terminal, without go tool pprof or its web UI.
+
+Profiles are read as written by runtime/pprof, net/http/pprof or go test
+-cpuprofile/-memprofile, gzipped or not.
+
+Examples:
+  devkit dev pprof top cpu.pb.gz
+  devkit dev pprof top heap.pb.gz --sample alloc_space --top 20
+  devkit dev pprof diff before.pb.gz after.pb.gz`,
+}
+
+// pprofTopCmd represents the pprof top subcommand
+var pprofTopCmd = &cobra.Command{
+	Use:   "top <profile>",
+	Short: "List the functions with the highest flat or cumulative values",
+	Long: `List the functions of a profile with the highest values, like "top" in
+go tool pprof.
+
+Flat is the value of the samples whose innermost frame is the function; cum
+also counts the samples where the function is anywhere on the stack. Sum is
+the running total of flat. Functions are sorted by flat, or by cum with
+--cum.
+
+Profiles with several sample types (a heap profile has alloc_objects,
+alloc_space, inuse_objects and inuse_space) are summarized by their default
+type; --sample picks another.
+
+Examples:
+  devkit dev pprof top cpu.pb.gz
+  devkit dev pprof top cpu.pb.gz --cum --top 25
+  devkit dev pprof top heap.pb.gz --sample alloc_space
+  devkit dev pprof top cpu.pb.gz --output json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPprofTop,
+}
+
+func init() {
+	devCmd.AddCommand(pprofCmd)
+	pprofCmd.AddCommand(pprofTopCmd)
+
+	pprofTopCmd.Flags().IntP("top", "n", 10, "Number of functions to list (0 for all)")
+	pprofTopCmd.Flags().String("sample", "", "Sample type to summarize, e.g. cpu, alloc_space (default: the profile's default)")
+	pprofTopCmd.Flags().Bool("cum", false, "Sort by cumulative value instead of flat")
+	pprofTopCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
+}
+
+// pprofFunction is the flat and cumulative value of one function
+type pprofFunction struct {
+	Name string
+	File string
+	Flat int64
+	Cum  int64
+}
+
+// pprofSummary is a profile reduced to per-function values of one sample type
+type pprofSummary struct {
+	sampleType string
+	unit       string
+	total      int64
+	duration   time.Duration
+	functions  map[string]*pprofFunction
+}
+
+func runPprofTop(cmd *cobra.Command, args []string) error {
+	top, _ := cmd.Flags().GetInt("top")
+	sampleType, _ := cmd.Flags().GetString("sample")
+	byCum, _ := cmd.Flags().GetBool("cum")
+	outputFormat, _ := cmd.Flags().GetString("output")
+	format := output.OutputFormat(outputFormat)
+
+	if top < 0 {
+		return fmt.Errorf("--top must not be negative")
+	}
+
+	cmd.SilenceUsage = true
+	p, err := loadPprof(args[0])
+	if err != nil {
+		return err
+	}
+	index, err := pprofSampleIndex(p, sampleType)
+	if err != nil {
+		return err
+	}
+	summary := summarizePprof(p, index)
+	functions := summary.sorted(byCum)
+	shown := functions
+	if top > 0 && len(shown) > top {
+		shown = shown[:top]
+	}
+
+	if format == output.FormatJSON {
+		rows := make([]map[string]interface{}, 0, len(shown))
+		for _, fn := range shown {
+			rows = append(rows, map[string]interface{}{
+				"name":         fn.Name,
+				"file":         fn.File,
+				"flat":         fn.Flat,
+				"flat_percent": pprofPercent(fn.Flat, summary.total),
+				"cum":          fn.Cum,
+				"cum_percent":  pprofPercent(fn.Cum, summary.total),
+			})
+		}
+		output.PrintSuccess(format, map[string]interface{}{
+			"file":        args[0],
+			"sample_type": summary.sampleType,
+			"unit":        summary.unit,
+			"duration_ms": summary.duration.Milliseconds(),
+			"total_value": summary.total,
+			"count":       len(functions),
+			"functions":   rows,
+		})
+		return nil
+	}
+
+	fmt.Printf("File: %s\n", filepath.Base(args[0]))
+	fmt.Printf("Type: %s (%s)\n", summary.sampleType, summary.unit)
+	if summary.duration > 0 {
+		fmt.Printf("Duration: %s\n", summary.duration.Round(time.Millisecond))
+	}
+	fmt.Printf("Total: %s\n", formatPprofValue(summary.total, summary.unit))
+	fmt.Printf("Showing %d of %d functions\n\n", len(shown), len(functions))
+
+	fmt.Println(output.Accent(fmt.Sprintf("%10s %7s %7s %10s %7s  %s", "FLAT", "FLAT%", "SUM%", "CUM", "CUM%", "FUNCTION")))
+	fmt.Println(output.Rule(70))
+	var sum int64
+	for _, fn := range shown {
+		sum += fn.Flat
+		fmt.Printf("%10s %6.2f%% %6.2f%% %10s %6.2f%%  %s\n",
+			formatPprofValue(fn.Flat, summary.unit), pprofPercent(fn.Flat, summary.total),
+			pprofPercent(sum, summary.total), formatPprofValue(fn.Cum, summary.unit),
+			pprofPercent(fn.Cum, summary.total), fn.Name)
+	}
+	return nil
+}
+
+// loadPprof reads a profile in the protobuf format, gzipped or not
+func loadPprof(path string) (*profile.Profile, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open profile: %w", err)
+	}
+	defer file.Close()
+
+	p, err := profile.Parse(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s is not a pprof profile: %w", path, err)
+	}
+	if len(p.SampleType) == 0 {
+		return nil, fmt.Errorf("%s has no sample types", path)
+	}
+	return p, nil
+}
+
+// pprofSampleIndex returns the index of the named sample type in the
+// sample values; with no name, the profile's default type or else the last
+// one, as go tool pprof does
+func pprofSampleIndex(p *profile.Profile, name string) (int, error) {
+	if name == "" {
+		name = p.DefaultSampleType
+		if name == "" {
+			return len(p.SampleType) - 1, nil
+		}
+	}
+	names := make([]string, len(p.SampleType))
+	for i, st := range p.SampleType {
+		if st.Type == name {
+			return i, nil
+		}
+		names[i] = st.Type
+	}
+	return 0, fmt.Errorf("unknown sample type: %s (available: %s)", name, strings.Join(names, ", "))
+}
+
+// summarizePprof adds up the values at index by function. The flat value
+// goes to the innermost frame, inlined functions included; the cumulative
+// value goes once to every function on the stack.
+func summarizePprof(p *profile.Profile, index int) *pprofSummary {
+	s := &pprofSummary{
+		sampleType: p.SampleType[index].Type,
+		unit:       p.SampleType[index].Unit,
+		duration:   time.Duration(p.DurationNanos),
+		functions:  make(map[string]*pprofFunction),
+	}
+
+	get := func(name, file string) *pprofFunction {
+		fn, ok := s.functions[name]
+		if !ok {
+			fn = &pprofFunction{Name: name, File: file}
+			s.functions[name] = fn
+		}
+		return fn
+	}
+
+	for _, sample := range p.Sample {
+		value := sample.Value[index]
+		if value == 0 {
+			continue
+		}
+		s.total += value
+
+		seen := make(map[string]bool)
+		for i, loc := range sample.Location {
+			frames := pprofFrames(loc)
+			for j, frame := range frames {
+				fn := get(frame[0], frame[1])
+				if i == 0 && j == 0 {
+					fn.Flat += value
+				}
+				if !seen[frame[0]] {
+					seen[frame[0]] = true
+					fn.Cum += value
+				}
+			}
+		}
+	}
+	return s
+}
+
+// pprofFrames returns the function name and file of each frame of a
+// location, innermost first; unsymbolized locations are named by address
+func pprofFrames(loc *profile.Location) [][2]string {
+	var frames [][2]string
+	for _, line := range loc.Line {
+		if line.Function == nil || line.Function.Name == "" {
+			continue
+		}
+		frames = append(frames, [2]string{line.Function.Name, line.Function.Filename})
+	}
+	if len(frames) == 0 {
+		frames = append(frames, [2]string{fmt.Sprintf("0x%x", loc.Address), ""})
+	}
+	return frames
+}
+
+// sorted returns the functions with the highest flat (or cumulative) value
+// first, by name on ties
+func (s *pprofSummary) sorted(byCum bool) []*pprofFunction {
+	functions := make([]*pprofFunction, 0, len(s.functions))
+	for _, fn := range s.functions {
+		functions = append(functions, fn)
+	}
+	sort.Slice(functions, func(i, j int) bool {
+		a, b := functions[i], functions[j]
+		if byCum && a.Cum != b.Cum {
+			return a.Cum > b.Cum
+		}
+		if a.Flat != b.Flat {
+			return a.Flat > b.Flat
+		}
+		if !byCum && a.Cum != b.Cum {
+			return a.Cum > b.Cum
+		}
+		return a.Name < b.Name
+	})
+	return functions
+}
+
+// pprofPercent returns value as a percentage of total, rounded to two decimals
+func pprofPercent(value, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(value)*10000/float64(total)) / 100
+}
+
+// formatPprofValue prints a value in the unit of its sample type:
+// nanoseconds as a duration, bytes in binary units and anything else as a
+// plain number
+func formatPprofValue(value int64, unit string) string {
+	switch unit {
+	case "nanoseconds":
+		d := time.Duration(value)
+		abs := d
+		if abs < 0 {
+			abs = -abs
+		}
+		switch {
+		case abs >= time.Second:
+			d = d.Round(10 * time.Millisecond)
+		case abs >= time.Millisecond:
+			d = d.Round(10 * time.Microsecond)
+		case abs >= time.Microsecond:
+			d = d.Round(10 * time.Nanosecond)
+		}
+		return d.String()
+	case "bytes":
+		abs := value
+		if abs < 0 {
+			abs = -abs
+		}
+		if abs < 1024 {
+			return fmt.Sprintf("%dB", value)
+		}
+		div, exp := int64(1024), 0
+		for n := abs / 1024; n >= 1024; n /= 1024 {
+			div *= 1024
+			exp++
+		}
+		return fmt.Sprintf("%.2f%cB", float64(value)/float64(div), "KMGTPE"[exp])
+	}
+	return strconv.FormatInt(value, 10)
+}
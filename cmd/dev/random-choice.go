@@ -0,0 +1,161 @@
+package dev
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"devkit/internal/output"
+)
+
+// randomChoiceCmd represents the choice subcommand
+var randomChoiceCmd = &cobra.Command{
+	Use:   "choice [item...]",
+	Short: "Pick random items from a list",
+	Long: `Pick --count random items from the arguments, or from the lines of
+--file or stdin (blank lines are skipped). Items are picked without
+replacement, so each appears at most once, unless --replace is given.
+
+--seed makes the pick reproducible; without it the cryptographically
+secure random generator is used.
+
+Examples:
+  devkit dev random choice alice bob carol dave
+  devkit dev random choice --count 2 alice bob carol dave
+  cat reviewers.txt | devkit dev random choice
+  devkit dev random choice --file tests.txt --count 10 --replace
+  devkit dev random choice --seed 42 red green blue`,
+	RunE: runRandomChoice,
+}
+
+func init() {
+	randomCmd.AddCommand(randomChoiceCmd)
+
+	randomChoiceCmd.Flags().IntP("count", "c", 1, "Number of items to pick")
+	randomChoiceCmd.Flags().BoolP("replace", "r", false, "Pick with replacement (items may repeat)")
+	randomChoiceCmd.Flags().StringP("file", "f", "", "Read items from a file, one per line")
+	randomChoiceCmd.Flags().Int64("seed", 0, "Seed for a reproducible pick")
+	randomChoiceCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
+}
+
+func runRandomChoice(cmd *cobra.Command, args []string) error {
+	count, _ := cmd.Flags().GetInt("count")
+	replace, _ := cmd.Flags().GetBool("replace")
+	outputFormat, _ := cmd.Flags().GetString("output")
+	format := output.OutputFormat(outputFormat)
+
+	if count < 1 {
+		return fmt.Errorf("count must be at least 1")
+	}
+
+	items, err := randomListItems(cmd, args)
+	if err != nil {
+		return err
+	}
+	if !replace && count > len(items) {
+		return fmt.Errorf("cannot pick %d of %d items without replacement (use --replace)", count, len(items))
+	}
+
+	intn := randomIntn(cmd)
+	var picked []string
+	if replace {
+		picked = make([]string, count)
+		for i := range picked {
+			picked[i] = items[intn(len(items))]
+		}
+	} else {
+		picked = shuffleStrings(items, intn)[:count]
+	}
+
+	if format == output.FormatJSON {
+		result := map[string]interface{}{
+			"count":      count,
+			"item_count": len(items),
+			"replace":    replace,
+		}
+		if cmd.Flags().Changed("seed") {
+			result["seed"], _ = cmd.Flags().GetInt64("seed")
+		}
+		if count == 1 {
+			result["choice"] = picked[0]
+		} else {
+			result["choices"] = picked
+		}
+		output.PrintSuccess(format, result)
+	} else if count == 1 {
+		output.PrintSuccess(format, picked[0])
+	} else {
+		output.PrintSuccess(format, picked)
+	}
+
+	return nil
+}
+
+// randomListItems returns the items of a choice or shuffle: the arguments,
+// or the non-blank lines of --file or stdin
+func randomListItems(cmd *cobra.Command, args []string) ([]string, error) {
+	if len(args) > 0 {
+		return args, nil
+	}
+
+	var r io.Reader
+	if file, _ := cmd.Flags().GetString("file"); file != "" {
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, fmt.Errorf("read file error: %w", err)
+		}
+		defer f.Close()
+		r = f
+	} else {
+		stat, err := os.Stdin.Stat()
+		if err != nil {
+			return nil, fmt.Errorf("stdin error: %w", err)
+		}
+		if (stat.Mode() & os.ModeCharDevice) != 0 {
+			return nil, fmt.Errorf("no items given (pass them as arguments, with --file, or on stdin)")
+		}
+		r = os.Stdin
+	}
+
+	var items []string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if line := strings.TrimRight(scanner.Text(), "\r"); strings.TrimSpace(line) != "" {
+			items = append(items, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read error: %w", err)
+	}
+	if len(items) == 0 {
+		return nil, fmt.Errorf("no items to choose from")
+	}
+	return items, nil
+}
+
+// randomIntn returns a function picking a number in [0, n): seeded and
+// reproducible with --seed, cryptographically secure otherwise
+func randomIntn(cmd *cobra.Command) func(n int) int {
+	if cmd.Flags().Changed("seed") {
+		seed, _ := cmd.Flags().GetInt64("seed")
+		return rand.New(rand.NewSource(seed)).Intn
+	}
+	return func(n int) int {
+		return generateRandomNumber(0, n-1)
+	}
+}
+
+// shuffleStrings returns a shuffled copy of items (Fisher-Yates)
+func shuffleStrings(items []string, intn func(int) int) []string {
+	shuffled := append([]string(nil), items...)
+	for i := len(shuffled) - 1; i > 0; i-- {
+		j := intn(i + 1)
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	}
+	return shuffled
+}
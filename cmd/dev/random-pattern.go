@@ -0,0 +1,260 @@
+package dev
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"devkit/internal/output"
+)
+
+// randomPatternClasses map the placeholder characters of a pattern to the
+// characters they are replaced with
+var randomPatternClasses = map[rune]string{
+	'A': "ABCDEFGHIJKLMNOPQRSTUVWXYZ",
+	'a': "abcdefghijklmnopqrstuvwxyz",
+	'9': "0123456789",
+	'#': "0123456789",
+	'X': "ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789",
+	'x': "abcdefghijklmnopqrstuvwxyz0123456789",
+	'H': "0123456789ABCDEF",
+	'h': "0123456789abcdef",
+	'?': "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789",
+}
+
+// maxPatternRepeat bounds {n} so that a typo cannot ask for gigabytes
+const maxPatternRepeat = 4096
+
+// patternPart is one character position of a parsed pattern: either a
+// literal or a pool of characters to pick from
+type patternPart struct {
+	literal rune
+	pool    []rune
+}
+
+// randomPatternCmd represents the pattern subcommand
+var randomPatternCmd = &cobra.Command{
+	Use:   "pattern [pattern]",
+	Short: "Generate values from a pattern like AAA-999-aaa",
+	Long: `Generate values such as license keys, SKUs or order numbers from a
+pattern. Each placeholder is replaced with a random character from its
+class; any other character is kept as is.
+
+Placeholders:
+  A  uppercase letter        a  lowercase letter
+  9  digit (also #)          ?  letter or digit
+  X  uppercase letter/digit  x  lowercase letter/digit
+  H  uppercase hex digit     h  lowercase hex digit
+  [...]  a character from the set, with ranges, e.g. [A-F0-9] or [xyz]
+  {n}    repeats the preceding placeholder or literal n times
+  \c     the character c itself, e.g. \A or \9
+
+With --unique, no value is repeated within one run.
+
+Examples:
+  devkit dev random pattern "AAA-999-aaa"
+  devkit dev random pattern "XXXXX-XXXXX-XXXXX-XXXXX" --count 10
+  devkit dev random pattern "SKU-[A-C]9{6}" --count 1000 --unique
+  devkit dev random pattern "ORD\A-h{8}" --output json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRandomPattern,
+}
+
+func init() {
+	randomCmd.AddCommand(randomPatternCmd)
+
+	randomPatternCmd.Flags().IntP("count", "c", 1, "Number of values to generate")
+	randomPatternCmd.Flags().BoolP("unique", "u", false, "Do not repeat a value")
+	randomPatternCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
+}
+
+func runRandomPattern(cmd *cobra.Command, args []string) error {
+	pattern := args[0]
+	count, _ := cmd.Flags().GetInt("count")
+	unique, _ := cmd.Flags().GetBool("unique")
+	outputFormat, _ := cmd.Flags().GetString("output")
+	format := output.OutputFormat(outputFormat)
+
+	if count < 1 {
+		return fmt.Errorf("count must be at least 1")
+	}
+
+	if count > 1000 {
+		return fmt.Errorf("count cannot exceed 1000")
+	}
+
+	parts, err := parseRandomPattern(pattern)
+	if err != nil {
+		return err
+	}
+
+	// The number of distinct values is the product of the pool sizes;
+	// log2 of it is the entropy of one value
+	var bits float64
+	for _, part := range parts {
+		if part.pool != nil {
+			bits += math.Log2(float64(len(part.pool)))
+		}
+	}
+	if unique && bits < math.Log2(float64(count)) {
+		return fmt.Errorf("pattern %q has only %.0f possible values, fewer than --count %d", pattern, math.Exp2(bits), count)
+	}
+
+	values := make([]string, 0, count)
+	seen := make(map[string]bool)
+	for len(values) < count {
+		value, err := generateFromPattern(parts)
+		if err != nil {
+			return err
+		}
+		if unique {
+			if seen[value] {
+				continue
+			}
+			seen[value] = true
+		}
+		values = append(values, value)
+	}
+
+	if format == output.FormatJSON {
+		result := map[string]interface{}{
+			"pattern":      pattern,
+			"entropy_bits": math.Round(bits*100) / 100,
+		}
+		if count == 1 {
+			result["value"] = values[0]
+		} else {
+			result["values"] = values
+		}
+		output.PrintSuccess(format, result)
+	} else if count == 1 {
+		output.PrintSuccess(format, values[0])
+	} else {
+		output.PrintSuccess(format, values)
+	}
+
+	return nil
+}
+
+// parseRandomPattern turns a pattern into one part per output character
+func parseRandomPattern(pattern string) ([]patternPart, error) {
+	runes := []rune(pattern)
+	var parts []patternPart
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == '\\':
+			if i+1 == len(runes) {
+				return nil, fmt.Errorf("pattern ends with an escape character")
+			}
+			i++
+			parts = append(parts, patternPart{literal: runes[i]})
+		case r == '[':
+			end := i + 1
+			for end < len(runes) && runes[end] != ']' {
+				if runes[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(runes) {
+				return nil, fmt.Errorf("unterminated character set at position %d", i+1)
+			}
+			pool, err := parsePatternSet(runes[i+1 : end])
+			if err != nil {
+				return nil, err
+			}
+			parts = append(parts, patternPart{pool: pool})
+			i = end
+		case r == '{':
+			end := i + 1
+			for end < len(runes) && runes[end] != '}' {
+				end++
+			}
+			if end >= len(runes) {
+				return nil, fmt.Errorf("unterminated repeat at position %d", i+1)
+			}
+			if len(parts) == 0 {
+				return nil, fmt.Errorf("repeat at position %d has nothing to repeat", i+1)
+			}
+			n, err := strconv.Atoi(string(runes[i+1 : end]))
+			if err != nil || n < 1 || n > maxPatternRepeat {
+				return nil, fmt.Errorf("invalid repeat {%s}: must be a number between 1 and %d", string(runes[i+1:end]), maxPatternRepeat)
+			}
+			last := parts[len(parts)-1]
+			for j := 1; j < n; j++ {
+				parts = append(parts, last)
+			}
+			i = end
+		default:
+			if class, ok := randomPatternClasses[r]; ok {
+				parts = append(parts, patternPart{pool: []rune(class)})
+			} else {
+				parts = append(parts, patternPart{literal: r})
+			}
+		}
+	}
+
+	if len(parts) == 0 {
+		return nil, fmt.Errorf("pattern cannot be empty")
+	}
+	return parts, nil
+}
+
+// parsePatternSet expands the contents of a [...] set, e.g. "A-F0-9"
+func parsePatternSet(set []rune) ([]rune, error) {
+	var pool []rune
+	seen := make(map[rune]bool)
+	add := func(r rune) {
+		if !seen[r] {
+			seen[r] = true
+			pool = append(pool, r)
+		}
+	}
+
+	for i := 0; i < len(set); i++ {
+		r := set[i]
+		if r == '\\' && i+1 < len(set) {
+			i++
+			add(set[i])
+			continue
+		}
+		if i+2 < len(set) && set[i+1] == '-' {
+			hi := set[i+2]
+			if hi < r {
+				return nil, fmt.Errorf("invalid range %c-%c in character set", r, hi)
+			}
+			for c := r; c <= hi; c++ {
+				add(c)
+			}
+			i += 2
+			continue
+		}
+		add(r)
+	}
+
+	if len(pool) == 0 {
+		return nil, fmt.Errorf("character set cannot be empty")
+	}
+	return pool, nil
+}
+
+func generateFromPattern(parts []patternPart) (string, error) {
+	var b strings.Builder
+	for _, part := range parts {
+		if part.pool == nil {
+			b.WriteRune(part.literal)
+			continue
+		}
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(part.pool))))
+		if err != nil {
+			return "", fmt.Errorf("failed to generate random value: %w", err)
+		}
+		b.WriteRune(part.pool[n.Int64()])
+	}
+	return b.String(), nil
+}
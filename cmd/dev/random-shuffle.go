@@ -0,0 +1,57 @@
+package dev
+
+import (
+	"github.com/spf13/cobra"
+	"devkit/internal/output"
+)
+
+// randomShuffleCmd represents the shuffle subcommand
+var randomShuffleCmd = &cobra.Command{
+	Use:   "shuffle [item...]",
+	Short: "Shuffle lines or items into a random order",
+	Long: `Print the arguments, or the lines of --file or stdin (blank lines are
+skipped), in a random order, e.g. to randomize test order or a rota.
+
+--seed makes the order reproducible, so a failing order can be replayed;
+without it the cryptographically secure random generator is used.
+
+Examples:
+  devkit dev random shuffle alice bob carol dave
+  go test -list . | devkit dev random shuffle
+  devkit dev random shuffle --file tests.txt --seed 1234`,
+	RunE: runRandomShuffle,
+}
+
+func init() {
+	randomCmd.AddCommand(randomShuffleCmd)
+
+	randomShuffleCmd.Flags().StringP("file", "f", "", "Read items from a file, one per line")
+	randomShuffleCmd.Flags().Int64("seed", 0, "Seed for a reproducible order")
+	randomShuffleCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
+}
+
+func runRandomShuffle(cmd *cobra.Command, args []string) error {
+	outputFormat, _ := cmd.Flags().GetString("output")
+	format := output.OutputFormat(outputFormat)
+
+	items, err := randomListItems(cmd, args)
+	if err != nil {
+		return err
+	}
+	shuffled := shuffleStrings(items, randomIntn(cmd))
+
+	if format == output.FormatJSON {
+		result := map[string]interface{}{
+			"count": len(shuffled),
+			"items": shuffled,
+		}
+		if cmd.Flags().Changed("seed") {
+			result["seed"], _ = cmd.Flags().GetInt64("seed")
+		}
+		output.PrintSuccess(format, result)
+	} else {
+		output.PrintSuccess(format, shuffled)
+	}
+
+	return nil
+}
@@ -3,22 +3,33 @@ package dev
 import (
 	"crypto/rand"
 	"fmt"
+	"math"
 	"math/big"
+	"os"
+	"slices"
+	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 	"devkit/internal/output"
 )
 
 // randomCmd represents the random command group
 var randomCmd = &cobra.Command{
 	Use:   "random",
-	Short: "Generate random data (string, number, password)",
-	Long: `Generate random strings, numbers, and passwords.
+	Short: "Generate random data (string, number, password, passphrase, bytes, pattern, choice)",
+	Long: `Generate random strings, numbers, passwords, passphrases, bytes and
+values from a pattern, or pick and shuffle items from a list.
 
 Examples:
   devkit dev random string --length 32
   devkit dev random number --min 1 --max 100
-  devkit dev random password --length 16`,
+  devkit dev random password --length 16
+  devkit dev random passphrase --words 6
+  devkit dev random bytes --length 32 --encoding base64url
+  devkit dev random pattern "AAA-999-aaa" --count 10
+  devkit dev random choice alice bob carol
+  devkit dev random shuffle --file tests.txt`,
 }
 
 // randomStringCmd represents the string subcommand
@@ -51,9 +62,20 @@ var randomPasswordCmd = &cobra.Command{
 	Short: "Generate random password",
 	Long: `Generate a secure random password.
 
+Policy controls:
+  --no-ambiguous   leave out characters that are easy to misread (0 O o 1 l I |)
+  --require        guarantee at least one character of each listed class:
+                   upper, lower, digit, symbol (symbol implies --symbols)
+  --min-entropy    fail unless the password has at least this many bits
+
+The entropy estimate (length × log2 of the character set size) and a
+strength rating are printed to stderr and included in the JSON output.
+
 Examples:
   devkit dev random password --length 16
-  devkit dev random password --length 20 --symbols`,
+  devkit dev random password --length 20 --symbols
+  devkit dev random password --no-ambiguous --require upper,lower,digit
+  devkit dev random password --length 24 --require upper,lower,digit,symbol --min-entropy 128`,
 	RunE: runRandomPassword,
 }
 
@@ -74,6 +96,9 @@ func init() {
 
 	randomPasswordCmd.Flags().IntP("length", "l", 16, "Length of the password")
 	randomPasswordCmd.Flags().BoolP("symbols", "s", false, "Include symbols")
+	randomPasswordCmd.Flags().Bool("no-ambiguous", false, "Exclude easily confused characters (0 O o 1 l I |)")
+	randomPasswordCmd.Flags().String("require", "", "Character classes that must appear: upper, lower, digit, symbol (comma-separated)")
+	randomPasswordCmd.Flags().Float64("min-entropy", 0, "Fail unless the password has at least this many bits of entropy")
 	randomPasswordCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
 }
 
@@ -134,6 +159,9 @@ func runRandomNumber(cmd *cobra.Command, args []string) error {
 func runRandomPassword(cmd *cobra.Command, args []string) error {
 	length, _ := cmd.Flags().GetInt("length")
 	symbols, _ := cmd.Flags().GetBool("symbols")
+	noAmbiguous, _ := cmd.Flags().GetBool("no-ambiguous")
+	require, _ := cmd.Flags().GetString("require")
+	minEntropy, _ := cmd.Flags().GetFloat64("min-entropy")
 	outputFormat, _ := cmd.Flags().GetString("output")
 	format := output.OutputFormat(outputFormat)
 
@@ -141,26 +169,113 @@ func runRandomPassword(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("length must be at least 1")
 	}
 
-	charset := "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	var required []string
+	for _, class := range strings.Split(require, ",") {
+		class = strings.ToLower(strings.TrimSpace(class))
+		if class == "" || slices.Contains(required, class) {
+			continue
+		}
+		if _, ok := passwordClasses[class]; !ok {
+			return fmt.Errorf("unknown character class: %s (supported: upper, lower, digit, symbol)", class)
+		}
+		required = append(required, class)
+	}
+	if slices.Contains(required, "symbol") {
+		symbols = true
+	}
+	if len(required) > length {
+		return fmt.Errorf("length %d is too short for %d required character classes", length, len(required))
+	}
+
+	classes := []string{"upper", "lower", "digit"}
 	if symbols {
-		charset += "!@#$%^&*()_+-=[]{}|;:,.<>?"
+		classes = append(classes, "symbol")
+	}
+	pools := make(map[string]string)
+	var charset string
+	for _, class := range classes {
+		pools[class] = passwordClasses[class]
+		if noAmbiguous {
+			pools[class] = strings.Map(func(r rune) rune {
+				if strings.ContainsRune(ambiguousChars, r) {
+					return -1
+				}
+				return r
+			}, pools[class])
+		}
+		charset += pools[class]
 	}
 
-	result := generateRandomString(length, charset)
+	entropy := math.Round(float64(length)*math.Log2(float64(len(charset)))*10) / 10
+	if entropy < minEntropy {
+		return fmt.Errorf("password entropy %.1f bits is below --min-entropy %g (increase --length or add --symbols)", entropy, minEntropy)
+	}
+	strength := passwordStrength(entropy)
+
+	// One character of each required class, the rest from the whole set,
+	// then shuffled so that the required characters are not always first
+	b := []byte(generateRandomString(length-len(required), charset))
+	for _, class := range required {
+		b = append(b, generateRandomString(1, pools[class])...)
+	}
+	for i := len(b) - 1; i > 0; i-- {
+		j := generateRandomNumber(0, i)
+		b[i], b[j] = b[j], b[i]
+	}
+	result := string(b)
 
 	if format == output.FormatJSON {
-		output.PrintSuccess(format, map[string]interface{}{
-			"password": result,
-			"length":  length,
-			"symbols": symbols,
-		})
-	} else {
-		output.PrintSuccess(format, result)
+		data := map[string]interface{}{
+			"password":     result,
+			"length":       length,
+			"symbols":      symbols,
+			"no_ambiguous": noAmbiguous,
+			"charset_size": len(charset),
+			"entropy_bits": entropy,
+			"strength":     strength,
+		}
+		if len(required) > 0 {
+			data["require"] = required
+		}
+		output.PrintSuccess(format, data)
+		return nil
+	}
+
+	output.PrintSuccess(format, result)
+	if !viper.GetBool("quiet") {
+		fmt.Fprintln(os.Stderr, output.Muted(fmt.Sprintf("%d characters from a %d-character set: %.1f bits of entropy (%s)", length, len(charset), entropy, strength)))
 	}
 
 	return nil
 }
 
+// passwordClasses are the character classes passwords are built from
+var passwordClasses = map[string]string{
+	"upper":  "ABCDEFGHIJKLMNOPQRSTUVWXYZ",
+	"lower":  "abcdefghijklmnopqrstuvwxyz",
+	"digit":  "0123456789",
+	"symbol": "!@#$%^&*()_+-=[]{}|;:,.<>?",
+}
+
+// ambiguousChars are left out with --no-ambiguous
+const ambiguousChars = "0Oo1lI|"
+
+// passwordStrength rates an entropy estimate, using the usual bands of
+// password managers
+func passwordStrength(bits float64) string {
+	switch {
+	case bits < 28:
+		return "very weak"
+	case bits < 36:
+		return "weak"
+	case bits < 60:
+		return "fair"
+	case bits < 128:
+		return "strong"
+	}
+	return "very strong"
+}
+
 func generateRandomString(length int, charset string) string {
 	b := make([]byte, length)
 	for i := range b {
@@ -0,0 +1,194 @@
+package dev
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/spf13/cobra"
+	"devkit/internal/check"
+	"devkit/internal/output"
+)
+
+// semverCheckCmd represents the semver check subcommand
+var semverCheckCmd = &cobra.Command{
+	Use:     "check [version...] [constraint]",
+	Aliases: []string{"satisfies"},
+	Short:   "Check whether versions satisfy a constraint",
+	Long: `Check versions against a constraint such as "^1.3.0", "~2.1", ">= 1.2, < 2.0"
+or "1.x || 2.x". The constraint is the last argument; versions are the
+arguments before it, or are read from a file or stdin (one per line, blank
+lines skipped).
+
+Prerelease versions only satisfy constraints that mention a prerelease of
+the same major.minor.patch, e.g. ">= 1.4.0-0". --only prints just the
+matching or non-matching versions, one per line, for use in pipelines.
+
+Exits with status 2 if any version does not satisfy the constraint or is
+not a valid version (see --fail-on).
+
+Examples:
+  devkit dev semver check "1.4.2" "^1.3.0"
+  devkit dev semver check 1.2.0 1.9.9 2.0.0 ">= 1.2, < 2.0"
+  git tag | devkit dev semver check --stdin "~1.4" --only satisfied
+  devkit dev semver check --file versions.txt "^2" --output json --fail-on none`,
+	RunE: runSemverCheck,
+}
+
+func init() {
+	semverCmd.AddCommand(semverCheckCmd)
+
+	semverCheckCmd.Flags().StringP("file", "f", "", "Read versions from a file, one per line")
+	semverCheckCmd.Flags().Bool("stdin", false, "Read versions from stdin, one per line")
+	semverCheckCmd.Flags().String("only", "", "Print only matching or non-matching versions: satisfied, unsatisfied")
+	semverCheckCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
+	check.AddFailOnFlag(semverCheckCmd, check.Invalid)
+}
+
+// semverCheckResult is the result for one input version
+type semverCheckResult struct {
+	Input     string   `json:"input"`
+	Line      int      `json:"line,omitempty"`
+	Version   string   `json:"version,omitempty"`
+	Satisfied bool     `json:"satisfied"`
+	Reasons   []string `json:"reasons,omitempty"`
+	Error     string   `json:"error,omitempty"`
+}
+
+func runSemverCheck(cmd *cobra.Command, args []string) error {
+	fileFlag, _ := cmd.Flags().GetString("file")
+	stdinFlag, _ := cmd.Flags().GetBool("stdin")
+	only, _ := cmd.Flags().GetString("only")
+	outputFormat, _ := cmd.Flags().GetString("output")
+	format := output.OutputFormat(outputFormat)
+
+	if only != "" && only != "satisfied" && only != "unsatisfied" {
+		return fmt.Errorf("invalid --only value: %s (use satisfied or unsatisfied)", only)
+	}
+	if len(args) == 0 {
+		return fmt.Errorf("constraint required")
+	}
+
+	constraintStr := args[len(args)-1]
+	constraint, err := semver.NewConstraint(constraintStr)
+	if err != nil {
+		return fmt.Errorf("invalid constraint: %w", err)
+	}
+
+	var results []semverCheckResult
+	for _, arg := range args[:len(args)-1] {
+		results = append(results, checkSemver(arg, constraint))
+	}
+
+	var r io.Reader
+	if stdinFlag {
+		stat, err := os.Stdin.Stat()
+		if err != nil {
+			return fmt.Errorf("stdin error: %w", err)
+		}
+		if (stat.Mode() & os.ModeCharDevice) != 0 {
+			return fmt.Errorf("no data available from stdin")
+		}
+		r = os.Stdin
+	} else if fileFlag != "" {
+		file, err := os.Open(fileFlag)
+		if err != nil {
+			return fmt.Errorf("read file error: %w", err)
+		}
+		defer file.Close()
+		r = file
+	}
+	if r != nil {
+		scanner := bufio.NewScanner(r)
+		line := 0
+		for scanner.Scan() {
+			line++
+			value := strings.TrimSpace(scanner.Text())
+			if value == "" {
+				continue
+			}
+			result := checkSemver(value, constraint)
+			result.Line = line
+			results = append(results, result)
+		}
+		if err := scanner.Err(); err != nil {
+			return fmt.Errorf("read error: %w", err)
+		}
+	}
+
+	if len(results) == 0 {
+		if r == nil {
+			return fmt.Errorf("version not specified (provide versions before the constraint, --file or --stdin)")
+		}
+		return fmt.Errorf("no versions found in input")
+	}
+
+	unsatisfied := 0
+	for _, result := range results {
+		if !result.Satisfied {
+			unsatisfied++
+		}
+	}
+
+	switch {
+	case format == output.FormatJSON:
+		output.PrintSuccess(format, map[string]interface{}{
+			"constraint":  constraint.String(),
+			"count":       len(results),
+			"satisfied":   len(results) - unsatisfied,
+			"unsatisfied": unsatisfied,
+			"results":     results,
+		})
+	case only != "":
+		for _, result := range results {
+			if result.Satisfied == (only == "satisfied") {
+				fmt.Println(result.Input)
+			}
+		}
+	default:
+		width := 0
+		for _, result := range results {
+			width = max(width, len(result.Input))
+		}
+		for _, result := range results {
+			switch {
+			case result.Satisfied:
+				fmt.Println(output.OK(fmt.Sprintf("%-*s  %s", width, result.Input, output.Muted("satisfies "+constraint.String()))))
+			case result.Error != "":
+				fmt.Println(output.Fail(fmt.Sprintf("%-*s  %s", width, result.Input, output.Failure(result.Error))))
+			default:
+				fmt.Println(output.Fail(fmt.Sprintf("%-*s  %s", width, result.Input, output.Failure(strings.Join(result.Reasons, "; ")))))
+			}
+		}
+		if len(results) > 1 {
+			fmt.Printf("\n%d satisfied, %d unsatisfied\n", len(results)-unsatisfied, unsatisfied)
+		}
+	}
+
+	if unsatisfied > 0 {
+		return check.Result(cmd, check.Invalid, fmt.Sprintf("%d of %d versions do not satisfy %s", unsatisfied, len(results), constraint))
+	}
+	return nil
+}
+
+// checkSemver parses value and validates it against constraint
+func checkSemver(value string, constraint *semver.Constraints) semverCheckResult {
+	result := semverCheckResult{Input: value}
+
+	v, err := semver.NewVersion(value)
+	if err != nil {
+		result.Error = fmt.Sprintf("invalid version: %v", err)
+		return result
+	}
+	result.Version = v.String()
+
+	ok, errs := constraint.Validate(v)
+	result.Satisfied = ok
+	for _, e := range errs {
+		result.Reasons = append(result.Reasons, e.Error())
+	}
+	return result
+}
@@ -0,0 +1,115 @@
+package dev
+
+import (
+	"fmt"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/spf13/cobra"
+	"devkit/internal/output"
+)
+
+// semverDiffCmd represents the semver diff subcommand
+var semverDiffCmd = &cobra.Command{
+	Use:   "diff [from] [to]",
+	Short: "Classify the change between two versions",
+	Long: `Print the kind of change from one version to another: the most significant
+part that differs.
+
+Changes:
+  major       the major versions differ (1.4.2 -> 2.0.0)
+  minor       the minor versions differ (1.4.2 -> 1.5.0)
+  patch       the patch versions differ (1.4.2 -> 1.4.3)
+  prerelease  only the prerelease differs (1.5.0-rc.1 -> 1.5.0-rc.2, 1.5.0-rc.2 -> 1.5.0)
+  build       only the build metadata differs (1.4.2+a -> 1.4.2+b)
+  none        the versions are identical
+
+The JSON output also gives the direction (upgrade, downgrade or none) and
+whether the change is breaking under semver rules: a major change, or a
+minor change before 1.0.0 (a patch change before 0.1.0).
+
+Examples:
+  devkit dev semver diff 1.4.2 1.5.0
+  devkit dev semver diff v2.0.0-rc.1 v2.0.0
+  devkit dev semver diff "$(git describe --tags --abbrev=0)" 2.0.0 --output json`,
+	RunE: runSemverDiff,
+}
+
+func init() {
+	semverCmd.AddCommand(semverDiffCmd)
+
+	semverDiffCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
+}
+
+func runSemverDiff(cmd *cobra.Command, args []string) error {
+	outputFormat, _ := cmd.Flags().GetString("output")
+	format := output.OutputFormat(outputFormat)
+
+	if len(args) < 2 {
+		return fmt.Errorf("two versions required")
+	}
+
+	from, err := semver.NewVersion(args[0])
+	if err != nil {
+		return fmt.Errorf("invalid version 1: %w", err)
+	}
+	to, err := semver.NewVersion(args[1])
+	if err != nil {
+		return fmt.Errorf("invalid version 2: %w", err)
+	}
+
+	change := semverChange(from, to)
+
+	direction := "none"
+	switch comparison := from.Compare(to); {
+	case comparison < 0:
+		direction = "upgrade"
+	case comparison > 0:
+		direction = "downgrade"
+	}
+
+	if format == output.FormatJSON {
+		output.PrintSuccess(format, map[string]interface{}{
+			"from":      from.String(),
+			"to":        to.String(),
+			"change":    change,
+			"direction": direction,
+			"breaking":  semverBreaking(from, to, change),
+		})
+	} else {
+		output.PrintSuccess(format, change)
+	}
+
+	return nil
+}
+
+// semverChange returns the most significant part that differs between a and b
+func semverChange(a, b *semver.Version) string {
+	switch {
+	case a.Major() != b.Major():
+		return "major"
+	case a.Minor() != b.Minor():
+		return "minor"
+	case a.Patch() != b.Patch():
+		return "patch"
+	case a.Prerelease() != b.Prerelease():
+		return "prerelease"
+	case a.Metadata() != b.Metadata():
+		return "build"
+	}
+	return "none"
+}
+
+// semverBreaking reports whether a change may break compatibility: while the
+// major version is 0 the minor version acts as major, and while the minor
+// version is also 0 the patch version does
+func semverBreaking(a, b *semver.Version, change string) bool {
+	switch change {
+	case "major":
+		return true
+	case "minor":
+		return a.Major() == 0
+	case "patch":
+		return a.Major() == 0 && a.Minor() == 0
+	}
+	return false
+}
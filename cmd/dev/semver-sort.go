@@ -0,0 +1,194 @@
+package dev
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/spf13/cobra"
+	"devkit/internal/output"
+)
+
+// semverSortCmd represents the semver sort subcommand
+var semverSortCmd = &cobra.Command{
+	Use:   "sort [version...]",
+	Short: "Sort and filter a list of versions",
+	Long: `Sort versions given as arguments, in a file or on stdin (one per line)
+by semantic version precedence, oldest first unless --desc is given.
+Values are printed as given, so tags keep their "v" prefix; equal versions
+keep their input order.
+
+Values that are not semantic versions, such as unrelated git tags, are
+skipped (with a note on stderr) unless --strict is given. --constraint
+keeps only the versions that satisfy it, --stable drops prereleases, and
+--latest prints only the newest remaining version, failing when none is
+left.
+
+Examples:
+  git tag | devkit dev semver sort --stdin
+  git tag | devkit dev semver sort --stdin --latest --constraint "^1.4"
+  devkit dev semver sort 1.10.0 1.2.0 1.2.0-rc.1 --desc
+  devkit dev semver sort --file versions.txt --stable --output json`,
+	RunE: runSemverSort,
+}
+
+func init() {
+	semverCmd.AddCommand(semverSortCmd)
+
+	semverSortCmd.Flags().StringP("file", "f", "", "Read versions from a file, one per line")
+	semverSortCmd.Flags().Bool("stdin", false, "Read versions from stdin, one per line")
+	semverSortCmd.Flags().BoolP("desc", "r", false, "Sort newest first")
+	semverSortCmd.Flags().StringP("constraint", "c", "", "Keep only versions satisfying this constraint, e.g. \"^1.4\"")
+	semverSortCmd.Flags().Bool("stable", false, "Drop prerelease versions")
+	semverSortCmd.Flags().Bool("latest", false, "Print only the newest matching version")
+	semverSortCmd.Flags().Bool("strict", false, "Fail on values that are not semantic versions")
+	semverSortCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
+}
+
+// semverSortItem is an input value and its parsed version
+type semverSortItem struct {
+	input   string
+	version *semver.Version
+}
+
+func runSemverSort(cmd *cobra.Command, args []string) error {
+	fileFlag, _ := cmd.Flags().GetString("file")
+	stdinFlag, _ := cmd.Flags().GetBool("stdin")
+	desc, _ := cmd.Flags().GetBool("desc")
+	constraintStr, _ := cmd.Flags().GetString("constraint")
+	stable, _ := cmd.Flags().GetBool("stable")
+	latest, _ := cmd.Flags().GetBool("latest")
+	strict, _ := cmd.Flags().GetBool("strict")
+	outputFormat, _ := cmd.Flags().GetString("output")
+	format := output.OutputFormat(outputFormat)
+
+	var constraint *semver.Constraints
+	if constraintStr != "" {
+		var err error
+		constraint, err = semver.NewConstraint(constraintStr)
+		if err != nil {
+			return fmt.Errorf("invalid constraint: %w", err)
+		}
+	}
+
+	inputs := append([]string{}, args...)
+
+	var r io.Reader
+	if stdinFlag {
+		stat, err := os.Stdin.Stat()
+		if err != nil {
+			return fmt.Errorf("stdin error: %w", err)
+		}
+		if (stat.Mode() & os.ModeCharDevice) != 0 {
+			return fmt.Errorf("no data available from stdin")
+		}
+		r = os.Stdin
+	} else if fileFlag != "" {
+		file, err := os.Open(fileFlag)
+		if err != nil {
+			return fmt.Errorf("read file error: %w", err)
+		}
+		defer file.Close()
+		r = file
+	}
+	if r != nil {
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			if value := strings.TrimSpace(scanner.Text()); value != "" {
+				inputs = append(inputs, value)
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return fmt.Errorf("read error: %w", err)
+		}
+	}
+
+	if len(inputs) == 0 {
+		if r == nil {
+			return fmt.Errorf("input not specified (provide versions as arguments, --file or --stdin)")
+		}
+		return fmt.Errorf("no versions found in input")
+	}
+
+	var items []semverSortItem
+	var skipped []string
+	for _, input := range inputs {
+		v, err := semver.NewVersion(input)
+		if err != nil {
+			if strict {
+				cmd.SilenceUsage = true
+				return fmt.Errorf("invalid version %q: %w", input, err)
+			}
+			skipped = append(skipped, input)
+			continue
+		}
+		if stable && v.Prerelease() != "" {
+			continue
+		}
+		if constraint != nil && !constraint.Check(v) {
+			continue
+		}
+		items = append(items, semverSortItem{input: input, version: v})
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		if desc {
+			return items[i].version.GreaterThan(items[j].version)
+		}
+		return items[i].version.LessThan(items[j].version)
+	})
+
+	if len(skipped) > 0 && format != output.FormatJSON {
+		fmt.Fprintf(os.Stderr, "%s\n", output.Muted("skipped values that are not semantic versions: "+strings.Join(skipped, ", ")))
+	}
+
+	var newest *semverSortItem
+	for i := range items {
+		if newest == nil || items[i].version.GreaterThan(newest.version) {
+			newest = &items[i]
+		}
+	}
+	if latest && newest == nil {
+		cmd.SilenceUsage = true
+		if constraint != nil {
+			return fmt.Errorf("no version satisfies %s", constraint)
+		}
+		return fmt.Errorf("no versions left after filtering")
+	}
+
+	versions := make([]string, 0, len(items))
+	for _, item := range items {
+		versions = append(versions, item.input)
+	}
+
+	if format == output.FormatJSON {
+		result := map[string]interface{}{
+			"count":    len(versions),
+			"versions": versions,
+		}
+		if newest != nil {
+			result["latest"] = newest.input
+		}
+		if constraint != nil {
+			result["constraint"] = constraint.String()
+		}
+		if len(skipped) > 0 {
+			result["skipped"] = skipped
+		}
+		output.PrintSuccess(format, result)
+		return nil
+	}
+
+	if latest {
+		fmt.Println(newest.input)
+		return nil
+	}
+	for _, v := range versions {
+		fmt.Println(v)
+	}
+	return nil
+}
@@ -2,6 +2,8 @@ package dev
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/Masterminds/semver/v3"
 	"github.com/spf13/cobra"
@@ -12,13 +14,17 @@ import (
 var semverCmd = &cobra.Command{
 	Use:   "semver",
 	Short: "Semantic version operations",
-	Long: `Compare and manipulate semantic versions.
+	Long: `Compare, manipulate, check, sort and diff semantic versions.
 
 Examples:
   devkit dev semver compare "1.2.3" "1.2.4"
   devkit dev semver bump major "1.2.3"
   devkit dev semver bump minor "1.2.3"
-  devkit dev semver bump patch "1.2.3"`,
+  devkit dev semver bump patch "1.2.3"
+  devkit dev semver bump prerelease "1.2.4-rc.1"
+  devkit dev semver check "1.4.2" "^1.3.0"
+  git tag | devkit dev semver sort --stdin --latest
+  devkit dev semver diff "1.4.2" "1.5.0"`,
 }
 
 // semverCompareCmd represents the compare subcommand
@@ -37,14 +43,25 @@ Examples:
 var semverBumpCmd = &cobra.Command{
 	Use:   "bump [type] [version]",
 	Short: "Bump a semantic version",
-	Long: `Bump a semantic version by major, minor, or patch.
+	Long: `Bump a semantic version by major, minor, patch or prerelease, or turn a
+prerelease into its release.
 
-Types: major, minor, patch
+Types:
+  major, minor, patch  increment that part; with --pre-id the result is the
+                       first prerelease of it (1.2.3 -> 2.0.0-rc.1)
+  prerelease           next prerelease: 1.2.3 -> 1.2.4-rc.1, 1.2.4-rc.1 -> 1.2.4-rc.2;
+                       a different --pre-id starts over (1.2.4-beta.3 -> 1.2.4-rc.1)
+  release              drop the prerelease: 1.2.4-rc.2 -> 1.2.4
+
+Build metadata is dropped unless --metadata sets it.
 
 Examples:
   devkit dev semver bump major "1.2.3"
   devkit dev semver bump minor "1.2.3"
-  devkit dev semver bump patch "1.2.3"`,
+  devkit dev semver bump patch "1.2.3"
+  devkit dev semver bump prerelease "1.2.4-rc.1"
+  devkit dev semver bump minor "1.2.3" --pre-id beta
+  devkit dev semver bump release "1.2.4-rc.2" --metadata "build.42"`,
 	RunE: runSemverBump,
 }
 
@@ -55,6 +72,8 @@ func init() {
 
 	semverCompareCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
 	semverBumpCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
+	semverBumpCmd.Flags().String("pre-id", "", "Prerelease identifier, e.g. rc, beta (default: keep the current one, or rc)")
+	semverBumpCmd.Flags().String("metadata", "", "Build metadata to set on the result, e.g. build.42")
 }
 
 func runSemverCompare(cmd *cobra.Command, args []string) error {
@@ -87,10 +106,10 @@ func runSemverCompare(cmd *cobra.Command, args []string) error {
 	}
 
 	result := map[string]interface{}{
-		"version1":  v1.String(),
-		"version2":  v2.String(),
+		"version1":   v1.String(),
+		"version2":   v2.String(),
 		"comparison": comparison,
-		"result":    resultStr,
+		"result":     resultStr,
 	}
 
 	if format == output.FormatJSON {
@@ -105,6 +124,8 @@ func runSemverCompare(cmd *cobra.Command, args []string) error {
 func runSemverBump(cmd *cobra.Command, args []string) error {
 	outputFormat, _ := cmd.Flags().GetString("output")
 	format := output.OutputFormat(outputFormat)
+	preID, _ := cmd.Flags().GetString("pre-id")
+	metadata, _ := cmd.Flags().GetString("metadata")
 
 	if len(args) < 2 {
 		return fmt.Errorf("bump type and version required")
@@ -118,16 +139,51 @@ func runSemverBump(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("invalid version: %w", err)
 	}
 
+	release, _ := v.SetPrerelease("")
+	release, _ = release.SetMetadata("")
+
+	// With --pre-id, major, minor and patch bump the release version so that
+	// a prerelease of 1.2.4 moves on to 1.2.5-rc.1 rather than 1.2.4-rc.1
+	base := *v
+	if preID != "" {
+		base = release
+	}
+
 	var bumped semver.Version
 	switch bumpType {
 	case "major":
-		bumped = v.IncMajor()
+		bumped = base.IncMajor()
 	case "minor":
-		bumped = v.IncMinor()
+		bumped = base.IncMinor()
 	case "patch":
-		bumped = v.IncPatch()
+		bumped = base.IncPatch()
+	case "prerelease":
+		if v.Prerelease() == "" {
+			bumped = release.IncPatch()
+		} else {
+			bumped = release
+		}
+		bumped, err = bumped.SetPrerelease(nextPrerelease(v.Prerelease(), preID))
+		if err != nil {
+			return fmt.Errorf("invalid --pre-id: %w", err)
+		}
+	case "release":
+		bumped = release
 	default:
-		return fmt.Errorf("invalid bump type: %s (supported: major, minor, patch)", bumpType)
+		return fmt.Errorf("invalid bump type: %s (supported: major, minor, patch, prerelease, release)", bumpType)
+	}
+
+	if preID != "" && (bumpType == "major" || bumpType == "minor" || bumpType == "patch") {
+		bumped, err = bumped.SetPrerelease(preID + ".1")
+		if err != nil {
+			return fmt.Errorf("invalid --pre-id: %w", err)
+		}
+	}
+	if metadata != "" {
+		bumped, err = bumped.SetMetadata(metadata)
+		if err != nil {
+			return fmt.Errorf("invalid --metadata: %w", err)
+		}
 	}
 
 	result := map[string]interface{}{
@@ -144,3 +200,24 @@ func runSemverBump(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// nextPrerelease returns the prerelease that follows current: the last
+// numeric identifier is incremented when current starts with preID (or
+// preID is empty), otherwise preID starts over at 1
+func nextPrerelease(current, preID string) string {
+	if current == "" || preID != "" && current != preID && !strings.HasPrefix(current, preID+".") {
+		if preID == "" {
+			preID = "rc"
+		}
+		return preID + ".1"
+	}
+
+	parts := strings.Split(current, ".")
+	for i := len(parts) - 1; i >= 0; i-- {
+		if n, err := strconv.ParseUint(parts[i], 10, 64); err == nil {
+			parts[i] = strconv.FormatUint(n+1, 10)
+			return strings.Join(parts, ".")
+		}
+	}
+	return current + ".1"
+}
@@ -0,0 +1,195 @@
+package dev
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/text/unicode/norm"
+	"devkit/internal/output"
+)
+
+// slugCmd represents the slug command
+var slugCmd = &cobra.Command{
+	Use:   "slug [text...]",
+	Short: "Convert text to a URL-safe slug",
+	Long: `Convert text to an ASCII slug for URLs, file names or CMS content, e.g.
+"Çılgın Öğrenci: İlk Gün!" becomes cilgin-ogrenci-ilk-gun.
+
+Accented letters are transliterated (ç → c, ö → o, ß → ss, æ → ae),
+apostrophes are dropped, and any other character that is not an ASCII
+letter or digit separates words. --max-length cuts the slug at a word
+boundary when the first word fits. With --stdin or --file every line
+becomes its own slug.
+
+Examples:
+  devkit dev slug "Hello, World!"
+  devkit dev slug "Crème Brûlée: The Recipe" --separator _
+  devkit dev slug "Çılgın Öğrenci: İlk Gün" --max-length 14
+  devkit dev slug "Release Notes 2.0" --lowercase=false
+  cat titles.txt | devkit dev slug --stdin --output json`,
+	RunE: runSlug,
+}
+
+func init() {
+	devCmd.AddCommand(slugCmd)
+
+	slugCmd.Flags().String("separator", "-", "Separator between words: -, _, . or ~, or empty")
+	slugCmd.Flags().IntP("max-length", "m", 0, "Maximum slug length (0 = no limit)")
+	slugCmd.Flags().Bool("lowercase", true, "Convert the slug to lower case")
+	slugCmd.Flags().StringP("file", "f", "", "Input file path, one text per line")
+	slugCmd.Flags().BoolP("stdin", "s", false, "Read from stdin, one text per line")
+	slugCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
+}
+
+// slugReplacements transliterates the letters that do not decompose into
+// an ASCII letter and combining marks
+var slugReplacements = map[rune]string{
+	'ß': "ss", 'ẞ': "SS",
+	'æ': "ae", 'Æ': "AE",
+	'œ': "oe", 'Œ': "OE",
+	'ø': "o", 'Ø': "O",
+	'đ': "d", 'Đ': "D",
+	'ð': "d", 'Ð': "D",
+	'þ': "th", 'Þ': "TH",
+	'ł': "l", 'Ł': "L",
+	'ħ': "h", 'Ħ': "H",
+	'ı': "i",
+}
+
+// slugResult is the slug of one input text
+type slugResult struct {
+	Input string `json:"input"`
+	Slug  string `json:"slug"`
+}
+
+func runSlug(cmd *cobra.Command, args []string) error {
+	separator, _ := cmd.Flags().GetString("separator")
+	maxLength, _ := cmd.Flags().GetInt("max-length")
+	lowercase, _ := cmd.Flags().GetBool("lowercase")
+	fileFlag, _ := cmd.Flags().GetString("file")
+	stdinFlag, _ := cmd.Flags().GetBool("stdin")
+	outputFormat, _ := cmd.Flags().GetString("output")
+	format := output.OutputFormat(outputFormat)
+
+	if len(separator) > 1 || (separator != "" && !strings.Contains("-_.~", separator)) {
+		return fmt.Errorf("invalid separator: %q (use -, _, . or ~, or an empty string)", separator)
+	}
+	if maxLength < 0 {
+		return fmt.Errorf("max-length cannot be negative")
+	}
+
+	var inputs []string
+	if stdinFlag {
+		stat, err := os.Stdin.Stat()
+		if err != nil {
+			return fmt.Errorf("stdin error: %w", err)
+		}
+		if (stat.Mode() & os.ModeCharDevice) != 0 {
+			return fmt.Errorf("no data available from stdin")
+		}
+		if inputs, err = readSlugLines(os.Stdin); err != nil {
+			return fmt.Errorf("read stdin error: %w", err)
+		}
+	} else if fileFlag != "" {
+		f, err := os.Open(fileFlag)
+		if err != nil {
+			return fmt.Errorf("read file error: %w", err)
+		}
+		defer f.Close()
+		if inputs, err = readSlugLines(f); err != nil {
+			return fmt.Errorf("read file error: %w", err)
+		}
+	} else if len(args) > 0 {
+		inputs = []string{strings.Join(args, " ")}
+	} else {
+		return fmt.Errorf("input not specified")
+	}
+	cmd.SilenceUsage = true
+
+	results := make([]slugResult, 0, len(inputs))
+	for _, input := range inputs {
+		slug := slugify(input, separator, maxLength, lowercase)
+		if slug == "" {
+			return fmt.Errorf("no letters or digits to build a slug from: %q", input)
+		}
+		results = append(results, slugResult{Input: input, Slug: slug})
+	}
+
+	if format == output.FormatJSON {
+		output.PrintSuccess(format, map[string]interface{}{
+			"results": results,
+			"count":   len(results),
+		})
+		return nil
+	}
+
+	for _, r := range results {
+		fmt.Println(r.Slug)
+	}
+	return nil
+}
+
+// readSlugLines returns the non-empty lines of r
+func readSlugLines(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, scanner.Err()
+}
+
+// slugify transliterates text to ASCII words joined by separator. With
+// maxLength, trailing words are dropped to fit; a first word that is too
+// long is cut.
+func slugify(text, separator string, maxLength int, lowercase bool) string {
+	var words []string
+	var word strings.Builder
+	flush := func() {
+		if word.Len() > 0 {
+			words = append(words, word.String())
+			word.Reset()
+		}
+	}
+
+	// NFD splits accented letters into the base letter and combining marks,
+	// which are dropped
+	for _, r := range norm.NFD.String(text) {
+		switch {
+		case unicode.Is(unicode.Mn, r), r == '\'', r == '’':
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			word.WriteRune(r)
+		case slugReplacements[r] != "":
+			word.WriteString(slugReplacements[r])
+		default:
+			flush()
+		}
+	}
+	flush()
+
+	slug := ""
+	for i, w := range words {
+		if lowercase {
+			w = strings.ToLower(w)
+		}
+		next := w
+		if i > 0 {
+			next = slug + separator + w
+		}
+		if maxLength > 0 && len(next) > maxLength {
+			if i == 0 {
+				return w[:maxLength]
+			}
+			break
+		}
+		slug = next
+	}
+	return slug
+}
@@ -0,0 +1,375 @@
+package dev
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"devkit/internal/output"
+)
+
+// Snowflake layout: 41 bits of milliseconds since the epoch, 5 bits of
+// datacenter (Discord: worker), 5 bits of worker (Discord: process) and a
+// 12-bit sequence
+const (
+	snowflakeSequenceBits = 12
+	snowflakeWorkerBits   = 5
+	snowflakeMaxSequence  = 1<<snowflakeSequenceBits - 1
+	snowflakeMaxWorker    = 1<<snowflakeWorkerBits - 1
+	snowflakeTimeShift    = snowflakeSequenceBits + 2*snowflakeWorkerBits
+)
+
+// snowflakeEpochs are the epochs known by name, in Unix milliseconds
+var snowflakeEpochs = map[string]int64{
+	"twitter": 1288834974657,
+	"discord": 1420070400000,
+	"unix":    0,
+}
+
+// snowflakeCmd represents the snowflake command
+var snowflakeCmd = &cobra.Command{
+	Use:   "snowflake",
+	Short: "Generate and decode snowflake IDs",
+	Long: `Generate Twitter/Discord-style snowflake IDs.
+
+A snowflake is a 64-bit integer made of 41 bits of milliseconds since an
+epoch, a 5-bit datacenter ID, a 5-bit worker ID and a 12-bit sequence that
+counts IDs within the same millisecond. (Discord calls the two 5-bit fields
+worker and process.) The epoch is twitter (default), discord, unix, a Unix
+time in milliseconds, or an RFC 3339 date.
+
+Use "devkit dev snowflake decode" to take an existing ID apart.
+
+Examples:
+  devkit dev snowflake                          # Generate a single snowflake
+  devkit dev snowflake --count 5 --worker 3
+  devkit dev snowflake --epoch discord --datacenter 1 --worker 2
+  devkit dev snowflake --epoch 2024-01-01T00:00:00Z --output json`,
+	RunE: runSnowflake,
+}
+
+// snowflakeDecodeCmd represents the snowflake decode subcommand
+var snowflakeDecodeCmd = &cobra.Command{
+	Use:   "decode [id...]",
+	Short: "Show the timestamp, datacenter, worker and sequence of snowflake IDs",
+	Long: `Split snowflake IDs into their timestamp, datacenter, worker and sequence.
+
+IDs are read from the arguments, or one per line from --file or --stdin.
+With several IDs a table is printed, and IDs that occur more than once are
+reported as collisions: they were generated by the same datacenter and
+worker in the same millisecond with the same sequence, which usually means
+two generators share a worker ID.
+
+Examples:
+  devkit dev snowflake decode 1541815603606036480
+  devkit dev snowflake decode 175928847299117063 --epoch discord
+  devkit dev snowflake decode 1541815603606036480 1541815603606036481 --output json
+  grep -o 'id=[0-9]*' app.log | cut -d= -f2 | devkit dev snowflake decode --stdin`,
+	RunE: runSnowflakeDecode,
+}
+
+func init() {
+	devCmd.AddCommand(snowflakeCmd)
+	snowflakeCmd.AddCommand(snowflakeDecodeCmd)
+
+	// Flag definitions
+	snowflakeCmd.PersistentFlags().String("epoch", "twitter", "Epoch: twitter, discord, unix, Unix milliseconds or an RFC 3339 date")
+	snowflakeCmd.Flags().Int("datacenter", 0, "Datacenter ID (0-31)")
+	snowflakeCmd.Flags().Int("worker", 0, "Worker ID (0-31)")
+	snowflakeCmd.Flags().IntP("count", "c", 1, "Number of snowflakes to generate")
+	snowflakeCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json, table")
+
+	snowflakeDecodeCmd.Flags().StringP("file", "f", "", "Read IDs from a file, one per line")
+	snowflakeDecodeCmd.Flags().Bool("stdin", false, "Read IDs from stdin, one per line")
+	snowflakeDecodeCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
+}
+
+func runSnowflake(cmd *cobra.Command, args []string) error {
+	epochFlag, _ := cmd.Flags().GetString("epoch")
+	datacenter, _ := cmd.Flags().GetInt("datacenter")
+	worker, _ := cmd.Flags().GetInt("worker")
+	count, _ := cmd.Flags().GetInt("count")
+	outputFormat, _ := cmd.Flags().GetString("output")
+	format := output.OutputFormat(outputFormat)
+
+	if count < 1 {
+		return fmt.Errorf("count must be at least 1")
+	}
+
+	if count > 1000 {
+		return fmt.Errorf("count cannot exceed 1000")
+	}
+
+	if datacenter < 0 || datacenter > snowflakeMaxWorker {
+		return fmt.Errorf("datacenter must be between 0 and %d", snowflakeMaxWorker)
+	}
+	if worker < 0 || worker > snowflakeMaxWorker {
+		return fmt.Errorf("worker must be between 0 and %d", snowflakeMaxWorker)
+	}
+
+	epoch, err := parseSnowflakeEpoch(epochFlag)
+	if err != nil {
+		return err
+	}
+
+	ids, err := generateSnowflakes(epoch, datacenter, worker, count)
+	if err != nil {
+		return err
+	}
+
+	// Prepare result based on format
+	if format == output.FormatJSON {
+		// IDs are strings: they exceed the integers JSON parsers keep exactly
+		result := map[string]interface{}{
+			"count":      count,
+			"epoch":      time.UnixMilli(epoch).UTC().Format(time.RFC3339Nano),
+			"datacenter": datacenter,
+			"worker":     worker,
+			"snowflakes": ids,
+		}
+		output.PrintSuccess(format, result)
+	} else {
+		// Plain format - print each snowflake on a new line
+		if count == 1 {
+			output.PrintSuccess(format, ids[0])
+		} else {
+			output.PrintSuccess(format, ids)
+		}
+	}
+
+	return nil
+}
+
+// generateSnowflakes creates count IDs. The sequence counts up within a
+// millisecond; when it runs out the generator waits for the next one.
+func generateSnowflakes(epoch int64, datacenter, worker, count int) ([]string, error) {
+	ids := make([]string, count)
+	last, sequence := int64(-1), 0
+	for i := range ids {
+		now := time.Now().UnixMilli() - epoch
+		if now < 0 {
+			return nil, fmt.Errorf("epoch is in the future")
+		}
+		if now >= 1<<41 {
+			return nil, fmt.Errorf("epoch is too far in the past for a 41-bit timestamp")
+		}
+		if now == last {
+			sequence++
+			if sequence > snowflakeMaxSequence {
+				for now <= last {
+					time.Sleep(100 * time.Microsecond)
+					now = time.Now().UnixMilli() - epoch
+				}
+				sequence = 0
+			}
+		} else {
+			sequence = 0
+		}
+		last = now
+
+		id := now<<snowflakeTimeShift |
+			int64(datacenter)<<(snowflakeSequenceBits+snowflakeWorkerBits) |
+			int64(worker)<<snowflakeSequenceBits |
+			int64(sequence)
+		ids[i] = strconv.FormatInt(id, 10)
+	}
+	return ids, nil
+}
+
+// snowflakeParts are the fields of a decoded snowflake
+type snowflakeParts struct {
+	ID         string `json:"id"`
+	Timestamp  string `json:"timestamp"`
+	UnixMillis int64  `json:"unix_ms"`
+	Datacenter int    `json:"datacenter"`
+	Worker     int    `json:"worker"`
+	Sequence   int    `json:"sequence"`
+}
+
+func runSnowflakeDecode(cmd *cobra.Command, args []string) error {
+	epochFlag, _ := cmd.Flags().GetString("epoch")
+	outputFormat, _ := cmd.Flags().GetString("output")
+	format := output.OutputFormat(outputFormat)
+
+	epoch, err := parseSnowflakeEpoch(epochFlag)
+	if err != nil {
+		return err
+	}
+
+	values, err := readSnowflakeInput(cmd, args)
+	if err != nil {
+		return err
+	}
+
+	parts := make([]snowflakeParts, len(values))
+	for i, value := range values {
+		id, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || id < 0 {
+			return fmt.Errorf("invalid snowflake: %s (expected a positive 64-bit integer)", value)
+		}
+		parts[i] = decodeSnowflake(id, epoch)
+	}
+	collisions := snowflakeCollisions(parts)
+
+	if format == output.FormatJSON {
+		result := map[string]interface{}{
+			"epoch": time.UnixMilli(epoch).UTC().Format(time.RFC3339Nano),
+		}
+		if len(parts) == 1 {
+			result["snowflake"] = parts[0]
+		} else {
+			result["snowflakes"] = parts
+			result["collisions"] = collisions
+		}
+		output.PrintSuccess(format, result)
+		return nil
+	}
+
+	if len(parts) == 1 {
+		p := parts[0]
+		fmt.Printf("ID: %s\n", p.ID)
+		fmt.Printf("Timestamp: %s (%s)\n", p.Timestamp, formatAge(p.UnixMillis))
+		fmt.Printf("Datacenter: %d\n", p.Datacenter)
+		fmt.Printf("Worker: %d\n", p.Worker)
+		fmt.Printf("Sequence: %d\n", p.Sequence)
+		return nil
+	}
+
+	idWidth := len("ID")
+	for _, p := range parts {
+		idWidth = max(idWidth, len(p.ID))
+	}
+	header := fmt.Sprintf("%-*s  %-24s  %2s  %2s  %4s", idWidth, "ID", "TIMESTAMP", "DC", "WK", "SEQ")
+	fmt.Println(output.Accent(header))
+	fmt.Println(output.Rule(len(header)))
+	for _, p := range parts {
+		fmt.Printf("%-*s  %-24s  %2d  %2d  %4d\n", idWidth, p.ID, p.Timestamp, p.Datacenter, p.Worker, p.Sequence)
+	}
+	if len(collisions) > 0 {
+		fmt.Println()
+		for _, c := range collisions {
+			fmt.Println(output.Warn(fmt.Sprintf("collision: %s occurs %d times", c.ID, c.Count)))
+		}
+	}
+	return nil
+}
+
+// readSnowflakeInput returns the IDs given as arguments, in --file or on
+// --stdin; blank lines are skipped
+func readSnowflakeInput(cmd *cobra.Command, args []string) ([]string, error) {
+	fileFlag, _ := cmd.Flags().GetString("file")
+	stdinFlag, _ := cmd.Flags().GetBool("stdin")
+
+	values := append([]string(nil), args...)
+	var r io.Reader
+	if stdinFlag {
+		stat, err := os.Stdin.Stat()
+		if err != nil {
+			return nil, fmt.Errorf("stdin error: %w", err)
+		}
+		if (stat.Mode() & os.ModeCharDevice) != 0 {
+			return nil, fmt.Errorf("no data available from stdin")
+		}
+		r = os.Stdin
+	} else if fileFlag != "" {
+		file, err := os.Open(fileFlag)
+		if err != nil {
+			return nil, fmt.Errorf("read file error: %w", err)
+		}
+		defer file.Close()
+		r = file
+	}
+	if r != nil {
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			if line := strings.TrimSpace(scanner.Text()); line != "" {
+				values = append(values, line)
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("read error: %w", err)
+		}
+	}
+	if len(values) == 0 {
+		return nil, fmt.Errorf("input not specified (provide IDs as arguments, --file or --stdin)")
+	}
+	return values, nil
+}
+
+// decodeSnowflake splits id into its fields
+func decodeSnowflake(id, epoch int64) snowflakeParts {
+	millis := id>>snowflakeTimeShift + epoch
+	return snowflakeParts{
+		ID:         strconv.FormatInt(id, 10),
+		Timestamp:  time.UnixMilli(millis).UTC().Format("2006-01-02T15:04:05.000Z07:00"),
+		UnixMillis: millis,
+		Datacenter: int(id>>(snowflakeSequenceBits+snowflakeWorkerBits)) & snowflakeMaxWorker,
+		Worker:     int(id>>snowflakeSequenceBits) & snowflakeMaxWorker,
+		Sequence:   int(id & snowflakeMaxSequence),
+	}
+}
+
+// snowflakeCollision is an ID that occurs more than once
+type snowflakeCollision struct {
+	ID    string `json:"id"`
+	Count int    `json:"count"`
+}
+
+// snowflakeCollisions lists the IDs that occur more than once, in the
+// order of their first occurrence. The fields cover all 63 bits, so two
+// generators produced the same fields exactly when the IDs are equal.
+func snowflakeCollisions(parts []snowflakeParts) []snowflakeCollision {
+	counts := make(map[string]int)
+	var order []string
+	for _, p := range parts {
+		if counts[p.ID] == 0 {
+			order = append(order, p.ID)
+		}
+		counts[p.ID]++
+	}
+	collisions := []snowflakeCollision{}
+	for _, id := range order {
+		if counts[id] > 1 {
+			collisions = append(collisions, snowflakeCollision{ID: id, Count: counts[id]})
+		}
+	}
+	return collisions
+}
+
+// parseSnowflakeEpoch resolves --epoch to Unix milliseconds
+func parseSnowflakeEpoch(s string) (int64, error) {
+	if epoch, ok := snowflakeEpochs[strings.ToLower(s)]; ok {
+		return epoch, nil
+	}
+	if ms, err := strconv.ParseInt(s, 10, 64); err == nil && ms >= 0 {
+		return ms, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t.UnixMilli(), nil
+	}
+	return 0, fmt.Errorf("invalid epoch: %s (use twitter, discord, unix, Unix milliseconds or an RFC 3339 date)", s)
+}
+
+// formatAge describes roughly how long ago millis was
+func formatAge(millis int64) string {
+	age := time.Since(time.UnixMilli(millis))
+	format := "%s ago"
+	if age < 0 {
+		age, format = -age, "in %s"
+	}
+	var span string
+	switch {
+	case age >= 48*time.Hour:
+		span = fmt.Sprintf("%d days", int(age.Hours()/24))
+	case age >= time.Hour:
+		span = age.Truncate(time.Minute).String()
+	default:
+		span = age.Round(time.Millisecond).String()
+	}
+	return fmt.Sprintf(format, span)
+}
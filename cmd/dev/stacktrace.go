@@ -0,0 +1,141 @@
+package dev
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"devkit/internal/output"
+	"devkit/internal/stacktrace"
+)
+
+// stacktraceCmd represents the stacktrace command group
+var stacktraceCmd = &cobra.Command{
+	Use:   "stacktrace",
+	Short: "Stack trace and panic log tools",
+	Long: `Read Go panics and goroutine dumps, Java exceptions and thread dumps, and
+Python tracebacks from logs.
+
+Examples:
+  go test ./... 2>&1 | devkit dev stacktrace parse --stdin
+  devkit dev stacktrace parse goroutines.txt --module-path github.com/acme/api`,
+}
+
+// stacktraceParseCmd represents the stacktrace parse subcommand
+var stacktraceParseCmd = &cobra.Command{
+	Use:   "parse [file]",
+	Short: "Summarize stack traces, grouping identical stacks",
+	Long: `Find the stack traces in a log and print a condensed summary: goroutines (or
+threads) with identical stacks and state are grouped and counted, so a
+goroutine dump of thousands of lines shrinks to its distinct stacks, the
+most common first. The goroutine that panicked and exceptions come before
+everything else.
+
+Recognized traces:
+  Go      panics, fatal errors and goroutine dumps (SIGQUIT, debug.Stack,
+          /debug/pprof/goroutine?debug=2)
+  Java    exceptions with their causes, and jstack thread dumps
+  Python  tracebacks
+
+Frames of your own code are highlighted: functions or files starting with a
+--module-path prefix (default: the module of go.mod in the current
+directory). --own-only hides the other frames, keeping the innermost one.
+Log lines around the traces are ignored.
+
+Examples:
+  go test ./... 2>&1 | devkit dev stacktrace parse --stdin
+  curl -s "localhost:6060/debug/pprof/goroutine?debug=2" | devkit dev stacktrace parse --stdin
+  devkit dev stacktrace parse crash.log --module-path github.com/acme/api --own-only
+  kubectl logs api-0 | devkit dev stacktrace parse --stdin --output json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runStacktraceParse,
+}
+
+func init() {
+	devCmd.AddCommand(stacktraceCmd)
+	stacktraceCmd.AddCommand(stacktraceParseCmd)
+
+	stacktraceParseCmd.Flags().BoolP("stdin", "s", false, "Read from stdin")
+	stacktraceParseCmd.Flags().StringSlice("module-path", nil, "Package or module prefixes of your code, highlighted in frames (default: the go.mod module)")
+	stacktraceParseCmd.Flags().Bool("own-only", false, "Show only frames of --module-path, and the innermost frame")
+	stacktraceParseCmd.Flags().IntP("top", "n", 0, "Number of groups to print (0 for all)")
+	stacktraceParseCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
+}
+
+func runStacktraceParse(cmd *cobra.Command, args []string) error {
+	stdinFlag, _ := cmd.Flags().GetBool("stdin")
+	modulePaths, _ := cmd.Flags().GetStringSlice("module-path")
+	ownOnly, _ := cmd.Flags().GetBool("own-only")
+	top, _ := cmd.Flags().GetInt("top")
+	outputFormat, _ := cmd.Flags().GetString("output")
+	format := output.OutputFormat(outputFormat)
+
+	if top < 0 {
+		return fmt.Errorf("--top must not be negative")
+	}
+	var r io.Reader
+	if stdinFlag {
+		r = os.Stdin
+	} else if len(args) > 0 {
+		file, err := os.Open(args[0])
+		if err != nil {
+			return fmt.Errorf("read file error: %w", err)
+		}
+		defer file.Close()
+		r = file
+	} else {
+		return fmt.Errorf("input not specified (give a file or --stdin)")
+	}
+
+	if !cmd.Flags().Changed("module-path") {
+		if path := stacktrace.ModulePath("."); path != "" {
+			modulePaths = []string{path}
+		}
+	}
+
+	cmd.SilenceUsage = true
+	traces, err := stacktrace.Parse(r)
+	if err != nil {
+		return err
+	}
+	stacktrace.MarkOwn(traces, modulePaths)
+	groups := stacktrace.GroupTraces(traces)
+	shown := groups
+	if top > 0 && len(shown) > top {
+		shown = shown[:top]
+	}
+	if ownOnly {
+		for i := range shown {
+			shown[i].Frames = stacktrace.OwnFrames(shown[i].Frames)
+		}
+	}
+
+	if format == output.FormatJSON {
+		if shown == nil {
+			shown = []stacktrace.Group{}
+		}
+		output.PrintSuccess(format, map[string]interface{}{
+			"traces":       len(traces),
+			"groups":       len(groups),
+			"module_paths": modulePaths,
+			"results":      shown,
+		})
+		return nil
+	}
+
+	if len(traces) == 0 {
+		fmt.Println(output.Muted("No stack traces found"))
+		return nil
+	}
+	fmt.Printf("%d traces in %d groups", len(traces), len(groups))
+	if len(shown) < len(groups) {
+		fmt.Printf(", showing %d", len(shown))
+	}
+	fmt.Println()
+	for _, g := range shown {
+		fmt.Println()
+		stacktrace.PrintGroup(g)
+	}
+	return nil
+}
@@ -0,0 +1,220 @@
+package dev
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"devkit/internal/output"
+)
+
+// timeAddCmd represents the time add subcommand
+var timeAddCmd = &cobra.Command{
+	Use:   "add <time>",
+	Short: "Add a duration to a time",
+	Long: `Add a duration to a time and print the result with its weekday.
+
+Durations combine a number and a unit: y (years), mo (months), w (weeks)
+and d (days) move the calendar date and keep the wall-clock time, also
+across daylight saving changes; h, m, s, ms, us and ns add exact time, as
+in Go (72h, 1h30m, 1.5h). They can be mixed: 1y2mo, 1d12h, 2w3d. A
+leading minus subtracts, as does "time sub". Adding a month to January 31
+gives March 2 or 3, as in Go.
+
+--tz is the zone of dates without an offset and of the result (default:
+local).
+
+Examples:
+  devkit dev time add 2024-03-01 --duration 72h
+  devkit dev time add "2024-03-09 12:00" --duration 1d --tz America/New_York
+  devkit dev time add now --duration 2w3d --format rfc1123
+  devkit dev time add 2024-01-31 --duration 1mo --output json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTimeAdd,
+}
+
+// timeSubCmd represents the time sub subcommand
+var timeSubCmd = &cobra.Command{
+	Use:   "sub <time>",
+	Short: "Subtract a duration from a time",
+	Long: `Subtract a duration from a time and print the result with its weekday.
+Durations are those of "time add"; "epoch diff" prints the duration
+between two times.
+
+Examples:
+  devkit dev time sub 2024-03-01 --duration 90d
+  devkit dev time sub now --duration 36h --tz UTC
+  devkit dev time sub "2024-03-31 02:30" --duration 1w --tz Europe/Berlin --output json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTimeAdd,
+}
+
+func init() {
+	for _, c := range []*cobra.Command{timeAddCmd, timeSubCmd} {
+		timeCmd.AddCommand(c)
+
+		c.Flags().StringP("duration", "d", "", "Duration, e.g. 72h, 1h30m, 3d, 2w, 1y2mo (required)")
+		c.Flags().String("tz", "local", "Time zone of dates without an offset and of the result")
+		c.Flags().String("format", "", "Result layout: a Go layout such as \"2006-01-02 15:04\" or a name like rfc1123, kitchen")
+		c.Flags().StringP("output", "o", "plain", "Output format: plain, json")
+		c.MarkFlagRequired("duration")
+	}
+}
+
+// timeDuration is a duration with calendar parts, which depend on the date
+// they are added to, and an exact clock part
+type timeDuration struct {
+	years, months, days int
+	clock               time.Duration
+}
+
+// timeDurationPart matches one number and unit of a duration
+var timeDurationPart = regexp.MustCompile(`^(\d+(?:\.\d+)?)(mo|ms|us|µs|ns|y|w|d|h|m|s)`)
+
+// parseTimeDuration reads a duration such as 72h, 1d12h or -1y2mo
+func parseTimeDuration(s string) (timeDuration, error) {
+	var d timeDuration
+	text := strings.TrimSpace(s)
+	negative := strings.HasPrefix(text, "-")
+	text = strings.TrimLeft(text, "+-")
+	if text == "" {
+		return d, fmt.Errorf("invalid duration: %q (use e.g. 72h, 1h30m, 3d, 2w, 1y2mo)", s)
+	}
+
+	for text != "" {
+		m := timeDurationPart.FindStringSubmatch(text)
+		if m == nil {
+			return d, fmt.Errorf("invalid duration: %q (use e.g. 72h, 1h30m, 3d, 2w, 1y2mo)", s)
+		}
+		text = text[len(m[0]):]
+		switch m[2] {
+		case "y", "mo", "w", "d":
+			n, err := strconv.Atoi(m[1])
+			if err != nil {
+				return d, fmt.Errorf("invalid duration: %q (years, months, weeks and days must be whole numbers)", s)
+			}
+			switch m[2] {
+			case "y":
+				d.years += n
+			case "mo":
+				d.months += n
+			case "w":
+				d.days += 7 * n
+			case "d":
+				d.days += n
+			}
+		default:
+			part, err := time.ParseDuration(m[0])
+			if err != nil {
+				return d, fmt.Errorf("invalid duration: %q", s)
+			}
+			d.clock += part
+		}
+	}
+
+	if negative {
+		d = d.negate()
+	}
+	return d, nil
+}
+
+// negate returns the opposite duration
+func (d timeDuration) negate() timeDuration {
+	return timeDuration{years: -d.years, months: -d.months, days: -d.days, clock: -d.clock}
+}
+
+// apply adds the calendar parts, then the clock part, to t
+func (d timeDuration) apply(t time.Time) time.Time {
+	return t.AddDate(d.years, d.months, d.days).Add(d.clock)
+}
+
+// String describes the duration, e.g. "1 year 2 months 3 days 4h0m0s"
+func (d timeDuration) String() string {
+	var parts []string
+	sign := ""
+	if d.years < 0 || d.months < 0 || d.days < 0 || d.clock < 0 {
+		sign = "-"
+		d = d.negate()
+	}
+	add := func(n int, name string) {
+		switch {
+		case n == 1:
+			parts = append(parts, "1 "+name)
+		case n > 1:
+			parts = append(parts, fmt.Sprintf("%d %ss", n, name))
+		}
+	}
+	add(d.years, "year")
+	add(d.months, "month")
+	add(d.days, "day")
+	if d.clock != 0 {
+		parts = append(parts, d.clock.String())
+	}
+	if len(parts) == 0 {
+		return "0s"
+	}
+	return sign + strings.Join(parts, " ")
+}
+
+func runTimeAdd(cmd *cobra.Command, args []string) error {
+	durationFlag, _ := cmd.Flags().GetString("duration")
+	tz, _ := cmd.Flags().GetString("tz")
+	layout, _ := cmd.Flags().GetString("format")
+	outputFormat, _ := cmd.Flags().GetString("output")
+	format := output.OutputFormat(outputFormat)
+
+	duration, err := parseTimeDuration(durationFlag)
+	if err != nil {
+		return err
+	}
+	if cmd.Name() == "sub" {
+		duration = duration.negate()
+	}
+	loc, err := loadTimeZone(tz)
+	if err != nil {
+		return err
+	}
+	if layout, err = timeLayout(layout, ""); err != nil {
+		return err
+	}
+
+	from, err := parseEpochValue(args[0], "auto", loc, time.Now())
+	if err != nil {
+		return err
+	}
+	from = from.In(loc)
+	result := duration.apply(from)
+
+	formatted := result.Format(time.RFC3339Nano)
+	if layout != "" {
+		formatted = result.Format(layout)
+	}
+
+	if format == output.FormatJSON {
+		data := map[string]interface{}{
+			"input":    args[0],
+			"duration": duration.String(),
+			"from":     from.Format(time.RFC3339Nano),
+			"result":   result.Format(time.RFC3339Nano),
+			"utc":      result.UTC().Format(time.RFC3339Nano),
+			"unix":     result.Unix(),
+			"weekday":  result.Weekday().String(),
+		}
+		if layout != "" {
+			data["formatted"] = formatted
+		}
+		output.PrintSuccess(format, data)
+		return nil
+	}
+
+	fmt.Printf("From: %s (%s)\n", from.Format(time.RFC3339Nano), from.Weekday())
+	fmt.Printf("Duration: %s\n", duration)
+	fmt.Printf("Result: %s (%s)\n", output.Accent(formatted), result.Weekday())
+	if result.Location() != time.UTC {
+		fmt.Printf("UTC: %s\n", result.UTC().Format(time.RFC3339Nano))
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package dev
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+	"devkit/internal/output"
+)
+
+// timeConvertCmd represents the time convert subcommand
+var timeConvertCmd = &cobra.Command{
+	Use:   "convert <time>",
+	Short: "Convert a time from one time zone to others",
+	Long: `Print a time given in one zone (--from) in other zones (--to), with each
+zone's abbreviation and UTC offset; dates that fall on another day than in
+--from are marked (+1d, -1d).
+
+--from applies to dates without an offset, and defaults to the local zone;
+a date with an offset (2024-03-01T14:00:00+03:00) or a Unix timestamp is
+read as is. --to takes a comma-separated list or can be repeated.
+
+Examples:
+  devkit dev time convert "2024-03-01 14:00" --from UTC --to America/New_York
+  devkit dev time convert "2024-03-01 09:30" --from Europe/Istanbul --to PST,IST,JST
+  devkit dev time convert now --to UTC --to Asia/Singapore --format kitchen
+  devkit dev time convert 1709301600 --to Europe/Berlin --output json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTimeConvert,
+}
+
+func init() {
+	timeCmd.AddCommand(timeConvertCmd)
+
+	timeConvertCmd.Flags().String("from", "local", "Time zone of a date without an offset")
+	timeConvertCmd.Flags().StringSlice("to", []string{"local"}, "Time zones to convert to (comma-separated or repeated)")
+	timeConvertCmd.Flags().String("format", "", "Time layout: a Go layout such as \"15:04 Jan 2\" or a name like rfc1123, kitchen")
+	timeConvertCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
+}
+
+func runTimeConvert(cmd *cobra.Command, args []string) error {
+	from, _ := cmd.Flags().GetString("from")
+	to, _ := cmd.Flags().GetStringSlice("to")
+	layout, _ := cmd.Flags().GetString("format")
+	outputFormat, _ := cmd.Flags().GetString("output")
+	format := output.OutputFormat(outputFormat)
+
+	fromLoc, err := loadTimeZone(from)
+	if err != nil {
+		return err
+	}
+	names, locs, err := loadTimeZones(to)
+	if err != nil {
+		return err
+	}
+	if len(names) == 0 {
+		return fmt.Errorf("no time zone to convert to")
+	}
+	if layout, err = timeLayout(layout, timeDefaultLayout); err != nil {
+		return err
+	}
+
+	t, err := parseEpochValue(args[0], "auto", fromLoc, time.Now())
+	if err != nil {
+		return err
+	}
+	// A date keeps the offset it was written with; timestamps and "now" are
+	// shown in --from
+	source, sourceName := t.In(fromLoc), from
+	if _, err := strconv.ParseInt(args[0], 10, 64); err != nil && args[0] != "now" && t.Location() != fromLoc {
+		source, sourceName = t, t.Location().String()
+		if sourceName == "" {
+			sourceName = t.Format("-07:00")
+		}
+	}
+
+	ref := source
+	sourceRow := newTimeInZone(sourceName, source, source.Location(), layout, ref)
+	rows := make([]timeInZone, 0, len(names))
+	for i, name := range names {
+		rows = append(rows, newTimeInZone(name, t, locs[i], layout, ref))
+	}
+
+	if format == output.FormatJSON {
+		output.PrintSuccess(format, map[string]interface{}{
+			"input":   args[0],
+			"from":    sourceRow,
+			"utc":     t.UTC().Format(time.RFC3339Nano),
+			"results": rows,
+		})
+		return nil
+	}
+
+	printTimeZones(append([]timeInZone{sourceRow}, rows...))
+	return nil
+}
@@ -0,0 +1,80 @@
+package dev
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"devkit/internal/output"
+)
+
+// timeNowCmd represents the time now subcommand
+var timeNowCmd = &cobra.Command{
+	Use:   "now",
+	Short: "Print the current time in several time zones",
+	Long: `Print the current time in several zones, a world clock for distributed
+teams; times that fall on another day than the local date are marked
+(+1d, -1d).
+
+Without --zones, the zones of time.zones in the config file are used, or
+else the local zone and UTC.
+
+Examples:
+  devkit dev time now
+  devkit dev time now --zones UTC,IST,PST
+  devkit dev time now --zones Europe/Istanbul,America/New_York --format kitchen
+  devkit dev time now --zones UTC,JST --output json`,
+	Args: cobra.NoArgs,
+	RunE: runTimeNow,
+}
+
+func init() {
+	timeCmd.AddCommand(timeNowCmd)
+
+	timeNowCmd.Flags().StringSlice("zones", nil, "Time zones to show (comma-separated or repeated; default: time.zones, or local and UTC)")
+	timeNowCmd.Flags().String("format", "", "Time layout: a Go layout such as \"15:04 Jan 2\" or a name like rfc1123, kitchen")
+	timeNowCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
+}
+
+func runTimeNow(cmd *cobra.Command, args []string) error {
+	zones, _ := cmd.Flags().GetStringSlice("zones")
+	layout, _ := cmd.Flags().GetString("format")
+	outputFormat, _ := cmd.Flags().GetString("output")
+	format := output.OutputFormat(outputFormat)
+
+	if len(zones) == 0 {
+		zones = viper.GetStringSlice("time.zones")
+	}
+	if len(zones) == 0 {
+		zones = []string{"local", "UTC"}
+	}
+	names, locs, err := loadTimeZones(zones)
+	if err != nil {
+		return err
+	}
+	if len(names) == 0 {
+		return fmt.Errorf("no time zone to show")
+	}
+	if layout, err = timeLayout(layout, timeDefaultLayout); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	rows := make([]timeInZone, 0, len(names))
+	for i, name := range names {
+		rows = append(rows, newTimeInZone(name, now, locs[i], layout, now))
+	}
+
+	if format == output.FormatJSON {
+		output.PrintSuccess(format, map[string]interface{}{
+			"utc":     now.UTC().Format(time.RFC3339Nano),
+			"unix":    now.Unix(),
+			"results": rows,
+		})
+		return nil
+	}
+
+	printTimeZones(rows)
+	return nil
+}
@@ -0,0 +1,216 @@
+package dev
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"devkit/internal/output"
+)
+
+// timeCmd represents the time command group
+var timeCmd = &cobra.Command{
+	Use:   "time",
+	Short: "Time zone conversion, world clock and date arithmetic",
+	Long: `Work with wall-clock times across time zones: convert a time from one zone
+to others, print the current time in several zones, and add or subtract
+durations. "dev epoch" converts Unix timestamps.
+
+Zones are IANA names (Europe/Istanbul), common abbreviations (UTC, PST, IST,
+CET, JST, ...), fixed offsets (+05:30, UTC-8) or "local". An abbreviation
+names a zone rather than an offset, so PST is America/Los_Angeles and shows
+PDT in summer; IST is India Standard Time.
+
+Times are a date in one of the formats of "epoch --to-unix"
+(2024-03-01 14:00, 2024-03-01T14:00:00Z, 2024-03-01), a Unix timestamp or
+"now".
+
+Examples:
+  devkit dev time convert "2024-03-01 14:00" --from UTC --to America/New_York
+  devkit dev time now --zones UTC,IST,PST
+  devkit dev time add 2024-03-01 --duration 72h`,
+}
+
+func init() {
+	devCmd.AddCommand(timeCmd)
+}
+
+// timeZoneAbbreviations maps common zone abbreviations to the IANA zone they
+// usually stand for; ambiguous ones (CST, IST, BST) take the most common
+var timeZoneAbbreviations = map[string]string{
+	"GMT":  "Etc/GMT",
+	"WET":  "Europe/Lisbon",
+	"BST":  "Europe/London",
+	"CET":  "Europe/Paris",
+	"CEST": "Europe/Paris",
+	"EET":  "Europe/Athens",
+	"EEST": "Europe/Athens",
+	"TRT":  "Europe/Istanbul",
+	"MSK":  "Europe/Moscow",
+	"GST":  "Asia/Dubai",
+	"PKT":  "Asia/Karachi",
+	"IST":  "Asia/Kolkata",
+	"ICT":  "Asia/Bangkok",
+	"WIB":  "Asia/Jakarta",
+	"SGT":  "Asia/Singapore",
+	"HKT":  "Asia/Hong_Kong",
+	"JST":  "Asia/Tokyo",
+	"KST":  "Asia/Seoul",
+	"AWST": "Australia/Perth",
+	"ACST": "Australia/Adelaide",
+	"AEST": "Australia/Sydney",
+	"AEDT": "Australia/Sydney",
+	"NZST": "Pacific/Auckland",
+	"NZDT": "Pacific/Auckland",
+	"HST":  "Pacific/Honolulu",
+	"AKST": "America/Anchorage",
+	"AKDT": "America/Anchorage",
+	"PST":  "America/Los_Angeles",
+	"PDT":  "America/Los_Angeles",
+	"MST":  "America/Denver",
+	"MDT":  "America/Denver",
+	"CST":  "America/Chicago",
+	"CDT":  "America/Chicago",
+	"EST":  "America/New_York",
+	"EDT":  "America/New_York",
+	"BRT":  "America/Sao_Paulo",
+	"ART":  "America/Argentina/Buenos_Aires",
+	"WAT":  "Africa/Lagos",
+	"CAT":  "Africa/Maputo",
+	"EAT":  "Africa/Nairobi",
+	"SAST": "Africa/Johannesburg",
+}
+
+// timeFixedZone matches offsets such as +05:30, -0800 and UTC+3
+var timeFixedZone = regexp.MustCompile(`^(?i:UTC|GMT)?([+-])(\d{1,2})(?::?(\d{2}))?$`)
+
+// loadTimeZone reads an IANA zone name, an abbreviation, a fixed offset or
+// "local"
+func loadTimeZone(name string) (*time.Location, error) {
+	switch strings.ToLower(name) {
+	case "", "local":
+		return time.Local, nil
+	case "utc", "z":
+		return time.UTC, nil
+	}
+	if iana, ok := timeZoneAbbreviations[strings.ToUpper(name)]; ok {
+		return time.LoadLocation(iana)
+	}
+	if m := timeFixedZone.FindStringSubmatch(name); m != nil {
+		hours, _ := strconv.Atoi(m[2])
+		minutes, _ := strconv.Atoi(m[3])
+		if hours <= 14 && minutes < 60 {
+			offset := hours*3600 + minutes*60
+			if m[1] == "-" {
+				offset = -offset
+			}
+			return time.FixedZone(fmt.Sprintf("UTC%s%02d:%02d", m[1], hours, minutes), offset), nil
+		}
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown time zone: %s (use an IANA name such as Europe/Istanbul, an abbreviation such as PST or an offset such as +05:30)", name)
+	}
+	return loc, nil
+}
+
+// loadTimeZones reads a comma-separated list of zones
+func loadTimeZones(list []string) ([]string, []*time.Location, error) {
+	var names []string
+	var locs []*time.Location
+	for _, item := range list {
+		for _, name := range strings.Split(item, ",") {
+			if name = strings.TrimSpace(name); name == "" {
+				continue
+			}
+			loc, err := loadTimeZone(name)
+			if err != nil {
+				return nil, nil, err
+			}
+			names = append(names, name)
+			locs = append(locs, loc)
+		}
+	}
+	return names, locs, nil
+}
+
+// timeLayout resolves a --format value: a layout name of epoch --format, a
+// Go layout, or def when empty
+func timeLayout(layout, def string) (string, error) {
+	if layout == "" {
+		return def, nil
+	}
+	if named, ok := epochLayouts[strings.ToLower(layout)]; ok {
+		return named, nil
+	}
+	if sample := time.Date(2001, 11, 22, 13, 44, 55, 0, time.UTC); sample.Format(layout) == layout {
+		return "", fmt.Errorf("invalid format: %s (use a Go layout such as \"2006-01-02 15:04\" or one of %s)", layout, strings.Join(epochLayoutNames(), ", "))
+	}
+	return layout, nil
+}
+
+// timeDefaultLayout is how the time commands print times without --format
+const timeDefaultLayout = "Mon 2006-01-02 15:04:05"
+
+// timeInZone is a time as seen in one zone
+type timeInZone struct {
+	Zone         string `json:"zone"`
+	Location     string `json:"location"`
+	Time         string `json:"time"`
+	Formatted    string `json:"formatted"`
+	Abbreviation string `json:"abbreviation"`
+	Offset       string `json:"offset"`
+	DayOffset    int    `json:"day_offset"`
+}
+
+// newTimeInZone renders t in loc; DayOffset is the number of calendar days
+// the date is ahead of (or behind) the date of ref
+func newTimeInZone(name string, t time.Time, loc *time.Location, layout string, ref time.Time) timeInZone {
+	t = t.In(loc)
+	abbreviation, _ := t.Zone()
+	offset := t.Format("-07:00")
+	if abbreviation == "" || abbreviation[0] == '+' || abbreviation[0] == '-' {
+		abbreviation = "UTC" + offset
+	}
+	location := cronZoneName(loc)
+	if location == "" {
+		location = "UTC" + offset
+	}
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	refDay := time.Date(ref.Year(), ref.Month(), ref.Day(), 0, 0, 0, 0, time.UTC)
+	return timeInZone{
+		Zone:         name,
+		Location:     location,
+		Time:         t.Format(time.RFC3339Nano),
+		Formatted:    t.Format(layout),
+		Abbreviation: abbreviation,
+		Offset:       offset,
+		DayOffset:    int(day.Sub(refDay).Hours() / 24),
+	}
+}
+
+// printTimeZones prints the times as a table, marking dates that differ
+// from the reference date
+func printTimeZones(rows []timeInZone) {
+	zoneWidth, timeWidth := len("ZONE"), len("TIME")
+	for _, r := range rows {
+		zoneWidth = max(zoneWidth, len(r.Zone))
+		timeWidth = max(timeWidth, len(r.Formatted))
+	}
+	fmt.Println(output.Accent(fmt.Sprintf("%-*s  %-*s  %s", zoneWidth, "ZONE", timeWidth, "TIME", "OFFSET")))
+	fmt.Println(output.Rule(zoneWidth + timeWidth + 24))
+	for _, r := range rows {
+		offset := "UTC" + r.Offset
+		if r.Abbreviation != offset && r.Abbreviation != "UTC" {
+			offset = r.Abbreviation + ", " + offset
+		}
+		line := fmt.Sprintf("%-*s  %-*s  %s", zoneWidth, r.Zone, timeWidth, r.Formatted, offset)
+		if r.DayOffset != 0 {
+			line += " " + output.Muted(fmt.Sprintf("(%+dd)", r.DayOffset))
+		}
+		fmt.Println(line)
+	}
+}
@@ -0,0 +1,587 @@
+package dev
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"github.com/pelletier/go-toml/v2/unstable"
+	"github.com/spf13/cobra"
+	"devkit/internal/check"
+	"devkit/internal/output"
+	"devkit/internal/undo"
+)
+
+// tomlCmd represents the toml command group
+var tomlCmd = &cobra.Command{
+	Use:   "toml",
+	Short: "TOML operations (validate, get, set)",
+	Long: `TOML inspection and patching operations for files such as Cargo.toml,
+pyproject.toml or application config.
+
+Key paths are dot-separated. Quote segments that contain dots and use
+numeric segments to index arrays and arrays of tables:
+  package.version
+  tool.poetry.dependencies.python
+  bin.0.name
+  servers."eu.example.com".port
+
+Examples:
+  devkit dev toml validate --file Cargo.toml
+  devkit dev toml get package.version --file Cargo.toml
+  devkit dev toml set package.version 1.2.0 --file Cargo.toml --in-place`,
+}
+
+// tomlValidateCmd represents the validate subcommand
+var tomlValidateCmd = &cobra.Command{
+	Use:   "validate [toml]",
+	Short: "Validate TOML string",
+	Long: `Check if a string or file is valid TOML. Errors include the line and column.
+Exits with status 2 when the input is invalid, or 0 with --fail-on none.
+--vimgrep prints the error as path:line:column: message for editors, and
+nothing for valid input.
+
+Examples:
+  devkit dev toml validate 'a = 1'
+  devkit dev toml validate --file pyproject.toml
+  cat Cargo.toml | devkit dev toml validate --stdin
+  devkit dev toml validate --file Cargo.toml --vimgrep`,
+	RunE: runTOMLValidate,
+}
+
+// tomlGetCmd represents the get subcommand
+var tomlGetCmd = &cobra.Command{
+	Use:   "get [key.path] [toml]",
+	Short: "Read a value by key path",
+	Long: `Read a value by key path. Strings are printed without quotes, tables and
+arrays are printed as JSON.
+
+Examples:
+  devkit dev toml get package.version --file Cargo.toml
+  devkit dev toml get tool.poetry.dependencies --file pyproject.toml
+  devkit dev toml get bin.0.name --file Cargo.toml --output json`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runTOMLGet,
+}
+
+// tomlSetCmd represents the set subcommand
+var tomlSetCmd = &cobra.Command{
+	Use:   "set [key.path] [value]",
+	Short: "Set a value by key path",
+	Long: `Set a value by key path. Comments and formatting of the rest of the document
+are preserved; missing keys are added to their table, and missing tables are
+appended to the end of the document.
+
+The value is parsed as a TOML literal (1, true, [1, 2], { a = 1 }, "text");
+anything that is not valid TOML is stored as a string. Use --string to always
+store a string.
+
+The updated document is printed unless --in-place is given.
+
+Examples:
+  devkit dev toml set package.version 1.2.0 --file Cargo.toml --in-place
+  devkit dev toml set server.port 8080 --file config.toml
+  devkit dev toml set project.version 2.0 --string --file pyproject.toml -i`,
+	Args: cobra.ExactArgs(2),
+	RunE: runTOMLSet,
+}
+
+func init() {
+	devCmd.AddCommand(tomlCmd)
+	tomlCmd.AddCommand(tomlValidateCmd)
+	tomlCmd.AddCommand(tomlGetCmd)
+	tomlCmd.AddCommand(tomlSetCmd)
+
+	// Flag definitions
+	tomlValidateCmd.Flags().StringP("file", "f", "", "Input file path")
+	tomlValidateCmd.Flags().BoolP("stdin", "s", false, "Read from stdin")
+	tomlValidateCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
+	check.AddFailOnFlag(tomlValidateCmd, check.Invalid)
+	tomlValidateCmd.Flags().Bool("vimgrep", false, "Print errors as path:line:column: message for editors")
+
+	tomlGetCmd.Flags().StringP("file", "f", "", "Input file path")
+	tomlGetCmd.Flags().BoolP("stdin", "s", false, "Read from stdin")
+	tomlGetCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
+
+	tomlSetCmd.Flags().StringP("file", "f", "", "Input file path")
+	tomlSetCmd.Flags().BoolP("stdin", "s", false, "Read from stdin")
+	tomlSetCmd.Flags().BoolP("in-place", "i", false, "Write the result back to --file")
+	tomlSetCmd.Flags().Bool("string", false, "Always store the value as a string")
+	tomlSetCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
+}
+
+func runTOMLValidate(cmd *cobra.Command, args []string) error {
+	outputFormat, _ := cmd.Flags().GetString("output")
+	format := output.OutputFormat(outputFormat)
+
+	input, err := getJSONInput(cmd, args)
+	if err != nil {
+		return err
+	}
+
+	var doc map[string]interface{}
+	parseErr := toml.Unmarshal([]byte(input), &doc)
+	line, column := 0, 0
+	var decodeErr *toml.DecodeError
+	if errors.As(parseErr, &decodeErr) {
+		line, column = decodeErr.Position()
+	}
+
+	if vimgrep, _ := cmd.Flags().GetBool("vimgrep"); vimgrep {
+		if parseErr != nil {
+			output.PrintLocations([]output.Location{inputLocation(cmd, line, column, parseErr)})
+			return check.Result(cmd, check.Invalid, "invalid TOML")
+		}
+		return nil
+	}
+
+	if format == output.FormatJSON {
+		result := map[string]interface{}{
+			"valid": parseErr == nil,
+			"keys":  len(doc),
+		}
+		if parseErr != nil {
+			result["error"] = parseErr.Error()
+			result["line"] = line
+			result["column"] = column
+		}
+		output.PrintSuccess(format, result)
+	} else {
+		if parseErr == nil {
+			output.PrintSuccess(format, output.OK(fmt.Sprintf("Valid TOML (%d top-level key(s))", len(doc))))
+		} else if line > 0 {
+			output.PrintError(format, fmt.Errorf("%s", output.Fail(fmt.Sprintf("Invalid TOML: line %d, column %d: %v", line, column, parseErr))))
+		} else {
+			output.PrintError(format, fmt.Errorf("%s", output.Fail(fmt.Sprintf("Invalid TOML: %v", parseErr))))
+		}
+	}
+
+	if parseErr != nil {
+		return check.Result(cmd, check.Invalid, "invalid TOML")
+	}
+	return nil
+}
+
+func runTOMLGet(cmd *cobra.Command, args []string) error {
+	outputFormat, _ := cmd.Flags().GetString("output")
+	format := output.OutputFormat(outputFormat)
+
+	keyPath := args[0]
+	path, err := splitTOMLPath(keyPath)
+	if err != nil {
+		return err
+	}
+
+	input, err := getJSONInput(cmd, args[1:])
+	if err != nil {
+		return err
+	}
+
+	var doc map[string]interface{}
+	if err := toml.Unmarshal([]byte(input), &doc); err != nil {
+		return fmt.Errorf("invalid TOML: %w", err)
+	}
+
+	value, ok := lookupTOMLPath(doc, path)
+	if !ok {
+		return fmt.Errorf("key not found: %s", keyPath)
+	}
+
+	if format == output.FormatJSON {
+		output.PrintSuccess(format, map[string]interface{}{
+			"key":   keyPath,
+			"value": value,
+		})
+		return nil
+	}
+
+	switch v := value.(type) {
+	case string:
+		fmt.Println(v)
+	case map[string]interface{}, []interface{}:
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode value: %w", err)
+		}
+		fmt.Println(string(data))
+	default:
+		fmt.Println(v)
+	}
+
+	return nil
+}
+
+func runTOMLSet(cmd *cobra.Command, args []string) error {
+	file, _ := cmd.Flags().GetString("file")
+	inPlace, _ := cmd.Flags().GetBool("in-place")
+	forceString, _ := cmd.Flags().GetBool("string")
+	outputFormat, _ := cmd.Flags().GetString("output")
+	format := output.OutputFormat(outputFormat)
+
+	if inPlace && file == "" {
+		return fmt.Errorf("--in-place requires --file")
+	}
+
+	keyPath := args[0]
+	path, err := splitTOMLPath(keyPath)
+	if err != nil {
+		return err
+	}
+
+	literal := tomlLiteral(args[1], forceString)
+
+	input, err := getJSONInput(cmd, nil)
+	if err != nil {
+		return err
+	}
+
+	updated, err := setTOMLValue([]byte(input), path, literal)
+	if err != nil {
+		return fmt.Errorf("cannot set %s: %w", keyPath, err)
+	}
+
+	// Make sure the patched document is still valid and holds the new value
+	var doc map[string]interface{}
+	if err := toml.Unmarshal(updated, &doc); err != nil {
+		return fmt.Errorf("cannot set %s: result is not valid TOML: %w", keyPath, err)
+	}
+	value, ok := lookupTOMLPath(doc, path)
+	if !ok {
+		return fmt.Errorf("cannot set %s: key is defined in a way that cannot be patched", keyPath)
+	}
+
+	var undoID string
+	if inPlace {
+		op, err := undo.Begin(cmd.CommandPath())
+		if err != nil {
+			return err
+		}
+		if err := op.BackupFile(file); err != nil {
+			op.Discard()
+			return err
+		}
+		info, err := os.Stat(file)
+		if err != nil {
+			op.Discard()
+			return fmt.Errorf("failed to stat %s: %w", file, err)
+		}
+		if err := os.WriteFile(file, updated, info.Mode().Perm()); err != nil {
+			op.Discard()
+			return fmt.Errorf("failed to write file %s: %w", file, err)
+		}
+		if err := op.Commit(); err != nil {
+			return err
+		}
+		undoID = op.ID
+	}
+
+	if format == output.FormatJSON {
+		result := map[string]interface{}{
+			"key":      keyPath,
+			"value":    value,
+			"in_place": inPlace,
+		}
+		if inPlace {
+			result["file"] = file
+			result["undo_id"] = undoID
+		} else {
+			result["document"] = string(updated)
+		}
+		output.PrintSuccess(format, result)
+	} else if inPlace {
+		output.PrintSuccess(format, fmt.Sprintf("Set %s = %s in %s", keyPath, literal, file))
+	} else {
+		fmt.Print(string(updated))
+	}
+
+	return nil
+}
+
+// splitTOMLPath splits a dotted key path, honouring quoted segments
+func splitTOMLPath(keyPath string) ([]string, error) {
+	var parts []string
+	var current strings.Builder
+	quote := byte(0)
+	quoted := false
+
+	for i := 0; i < len(keyPath); i++ {
+		c := keyPath[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			} else {
+				current.WriteByte(c)
+			}
+		case c == '"' || c == '\'':
+			quote = c
+			quoted = true
+		case c == '.':
+			if current.Len() == 0 && !quoted {
+				return nil, fmt.Errorf("invalid key path: %s", keyPath)
+			}
+			parts = append(parts, current.String())
+			current.Reset()
+			quoted = false
+		default:
+			current.WriteByte(c)
+		}
+	}
+	if quote != 0 || (current.Len() == 0 && !quoted) {
+		return nil, fmt.Errorf("invalid key path: %s", keyPath)
+	}
+	return append(parts, current.String()), nil
+}
+
+// lookupTOMLPath walks a decoded document; numeric segments index arrays
+func lookupTOMLPath(doc map[string]interface{}, path []string) (interface{}, bool) {
+	var current interface{} = doc
+	for _, part := range path {
+		switch node := current.(type) {
+		case map[string]interface{}:
+			value, ok := node[part]
+			if !ok {
+				return nil, false
+			}
+			current = value
+		case []interface{}:
+			index, err := strconv.Atoi(part)
+			if err != nil || index < 0 || index >= len(node) {
+				return nil, false
+			}
+			current = node[index]
+		default:
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+var tomlStringEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+
+// tomlLiteral returns value as TOML source, quoting it when it is not a
+// valid TOML value or when a string is forced
+func tomlLiteral(value string, forceString bool) string {
+	if !forceString {
+		var probe map[string]interface{}
+		if toml.Unmarshal([]byte("v = "+value), &probe) == nil {
+			return strings.TrimSpace(value)
+		}
+	}
+	return `"` + tomlStringEscaper.Replace(value) + `"`
+}
+
+// tomlSection tracks where keys of a table end in the source document
+type tomlSection struct {
+	path []string
+	end  int // offset after the last line belonging to the table
+}
+
+// setTOMLValue replaces the value of an existing key in place, or inserts
+// a new key, leaving the rest of the document untouched
+func setTOMLValue(data []byte, path []string, literal string) ([]byte, error) {
+	var p unstable.Parser
+	p.Reset(data)
+
+	root := &tomlSection{end: 0}
+	var sections []*tomlSection
+	current := root
+	arrayCounts := make(map[string]int)
+
+	for p.NextExpression() {
+		expr := p.Expression()
+		switch expr.Kind {
+		case unstable.Table, unstable.ArrayTable:
+			keyPath, keyEnd := tomlKeyPath(expr)
+			if expr.Kind == unstable.ArrayTable {
+				joined := strings.Join(keyPath, "\x00")
+				keyPath = append(keyPath, strconv.Itoa(arrayCounts[joined]))
+				arrayCounts[joined]++
+			}
+			current = &tomlSection{path: keyPath, end: lineEnd(data, keyEnd)}
+			sections = append(sections, current)
+		case unstable.KeyValue:
+			keyPath, keyEnd := tomlKeyPath(expr)
+			fullPath := append(append([]string{}, current.path...), keyPath...)
+
+			eq := bytes.IndexByte(data[keyEnd:], '=')
+			if eq < 0 {
+				return nil, fmt.Errorf("malformed key/value")
+			}
+			start := keyEnd + eq + 1
+			for start < len(data) && (data[start] == ' ' || data[start] == '\t') {
+				start++
+			}
+			end := tomlValueEnd(data, start)
+
+			if len(fullPath) < len(path) && equalPath(fullPath, path[:len(fullPath)]) {
+				return nil, fmt.Errorf("%s is an inline value; set it as a whole", formatTOMLKey(fullPath))
+			}
+			if equalPath(fullPath, path) {
+				var out bytes.Buffer
+				out.Write(data[:start])
+				out.WriteString(literal)
+				out.Write(data[end:])
+				return out.Bytes(), nil
+			}
+			current.end = lineEnd(data, end)
+		}
+	}
+	if err := p.Error(); err != nil {
+		return nil, fmt.Errorf("invalid TOML: %w", err)
+	}
+
+	// Insert into the table the key belongs to, or create that table
+	parent, key := path[:len(path)-1], path[len(path)-1]
+	line := formatTOMLKey([]string{key}) + " = " + literal + "\n"
+
+	target := root
+	if len(parent) > 0 {
+		target = nil
+		for _, section := range sections {
+			if equalPath(section.path, parent) {
+				target = section
+			}
+		}
+	}
+
+	var out bytes.Buffer
+	if target == nil {
+		out.Write(data)
+		if len(data) > 0 && !bytes.HasSuffix(data, []byte("\n")) {
+			out.WriteByte('\n')
+		}
+		if len(data) > 0 {
+			out.WriteByte('\n')
+		}
+		out.WriteString("[" + formatTOMLKey(parent) + "]\n")
+		out.WriteString(line)
+		return out.Bytes(), nil
+	}
+
+	out.Write(data[:target.end])
+	if target.end > 0 && data[target.end-1] != '\n' {
+		out.WriteByte('\n')
+	}
+	out.WriteString(line)
+	out.Write(data[target.end:])
+	return out.Bytes(), nil
+}
+
+// tomlKeyPath returns the key segments of an expression and the offset
+// just after the key in the source
+func tomlKeyPath(expr *unstable.Node) ([]string, int) {
+	var path []string
+	end := 0
+	it := expr.Key()
+	for it.Next() {
+		node := it.Node()
+		path = append(path, string(node.Data))
+		end = int(node.Raw.Offset + node.Raw.Length)
+	}
+	return path, end
+}
+
+// tomlValueEnd returns the offset at which the value starting at start ends,
+// excluding any trailing comment and whitespace
+func tomlValueEnd(data []byte, start int) int {
+	depth := 0
+	i := start
+	last := start
+
+	for i < len(data) {
+		c := data[i]
+		switch {
+		case c == '"' || c == '\'':
+			delim := []byte{c}
+			if bytes.HasPrefix(data[i:], []byte{c, c, c}) {
+				delim = []byte{c, c, c}
+			}
+			i += len(delim)
+			for i < len(data) && !bytes.HasPrefix(data[i:], delim) {
+				if c == '"' && data[i] == '\\' {
+					i++
+				}
+				i++
+			}
+			i += len(delim)
+			// Multiline strings may end with up to two extra quotes
+			for len(delim) == 3 && i < len(data) && data[i] == c {
+				i++
+			}
+			last = i
+			continue
+		case c == '[' || c == '{':
+			depth++
+		case c == ']' || c == '}':
+			depth--
+		case c == '#':
+			if depth == 0 {
+				return last
+			}
+			for i < len(data) && data[i] != '\n' {
+				i++
+			}
+			continue
+		case c == '\n':
+			if depth == 0 {
+				return last
+			}
+		}
+		if c != ' ' && c != '\t' && c != '\r' && c != '\n' {
+			last = i + 1
+		}
+		i++
+	}
+	if last > len(data) {
+		return len(data)
+	}
+	return last
+}
+
+func lineEnd(data []byte, offset int) int {
+	if offset > len(data) {
+		return len(data)
+	}
+	if idx := bytes.IndexByte(data[offset:], '\n'); idx >= 0 {
+		return offset + idx + 1
+	}
+	return len(data)
+}
+
+func equalPath(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// formatTOMLKey renders key segments, quoting those that are not bare keys
+func formatTOMLKey(path []string) string {
+	parts := make([]string, len(path))
+	for i, part := range path {
+		bare := part != ""
+		for _, r := range part {
+			if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+				bare = false
+				break
+			}
+		}
+		if bare {
+			parts[i] = part
+		} else {
+			parts[i] = `"` + tomlStringEscaper.Replace(part) + `"`
+		}
+	}
+	return strings.Join(parts, ".")
+}
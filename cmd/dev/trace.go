@@ -0,0 +1,398 @@
+package dev
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/exp/trace"
+	"devkit/internal/output"
+)
+
+// traceCmd represents the trace command group
+var traceCmd = &cobra.Command{
+	Use:   "trace",
+	Short: "Summarize Go runtime execution traces",
+	Long: `Summarize Go runtime execution traces in the terminal, without go tool trace
+or its web UI.
+
+Traces are read as written by runtime/trace, net/http/pprof (/debug/pprof/trace)
+or go test -trace, for Go 1.22 to 1.26.
+
+Examples:
+  devkit dev trace summary trace.out`,
+}
+
+// traceSummaryCmd represents the trace summary subcommand
+var traceSummaryCmd = &cobra.Command{
+	Use:   "summary <trace>",
+	Short: "Print the goroutine, GC and scheduler figures of a trace",
+	Long: `Print an overview of an execution trace: its length, the goroutines and the
+time they spent running, runnable (waiting for a P), blocked and in system
+calls, garbage collection cycles and stop-the-world pauses, GOMAXPROCS and
+heap size, user tasks, regions and logs, and the reasons goroutines blocked
+for longest.
+
+Examples:
+  devkit dev trace summary trace.out
+  devkit dev trace summary trace.out --top 10
+  curl -s "localhost:6060/debug/pprof/trace?seconds=5" > trace.out && devkit dev trace summary trace.out
+  devkit dev trace summary trace.out --output json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTraceSummary,
+}
+
+func init() {
+	devCmd.AddCommand(traceCmd)
+	traceCmd.AddCommand(traceSummaryCmd)
+
+	traceSummaryCmd.Flags().IntP("top", "n", 5, "Number of block reasons to list (0 for all)")
+	traceSummaryCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
+}
+
+// Metrics of the runtime/metrics package recorded in traces
+const (
+	traceMetricGomaxprocs = "/sched/gomaxprocs:threads"
+	traceMetricHeapLive   = "/memory/classes/heap/objects:bytes"
+	traceMetricHeapGoal   = "/gc/heap/goal:bytes"
+)
+
+// traceRangeGCMark is the range of a GC cycle from the start of marking to
+// mark termination
+const traceRangeGCMark = "GC concurrent mark phase"
+
+// traceTimedRange reports whether the summary times the range: GC marking
+// and stop-the-world pauses, which are global, unlike sweeping and assists
+func traceTimedRange(name string) bool {
+	return name == traceRangeGCMark || strings.HasPrefix(name, "stop-the-world")
+}
+
+// traceBlock is the time goroutines spent blocked for one reason
+type traceBlock struct {
+	reason string
+	count  int
+	total  time.Duration
+}
+
+// traceGoroutine is the state of a goroutine while the trace is read
+type traceGoroutine struct {
+	state  trace.GoState
+	since  trace.Time
+	reason string
+}
+
+// traceStats is the summary of a trace
+type traceStats struct {
+	events     int
+	start, end trace.Time
+
+	goroutines map[trace.GoID]*traceGoroutine
+	created    int
+	states     map[trace.GoState]time.Duration
+	blocks     map[string]*traceBlock
+
+	gcCycles int
+	gcTime   time.Duration
+	stw      int
+	stwTotal time.Duration
+	stwMax   time.Duration
+	ranges   map[string]trace.Time
+
+	gomaxprocs uint64
+	heapMax    uint64
+	heapGoal   uint64
+
+	tasks, regions, logs int
+}
+
+func runTraceSummary(cmd *cobra.Command, args []string) error {
+	top, _ := cmd.Flags().GetInt("top")
+	outputFormat, _ := cmd.Flags().GetString("output")
+	format := output.OutputFormat(outputFormat)
+
+	if top < 0 {
+		return fmt.Errorf("--top must not be negative")
+	}
+
+	cmd.SilenceUsage = true
+	file, err := os.Open(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to open trace: %w", err)
+	}
+	defer file.Close()
+
+	stats, err := readTrace(file)
+	if err != nil {
+		return fmt.Errorf("%s: %w", args[0], err)
+	}
+
+	duration := stats.end.Sub(stats.start)
+	blocks := stats.sortedBlocks()
+	if top > 0 && len(blocks) > top {
+		blocks = blocks[:top]
+	}
+	stateNames := []trace.GoState{trace.GoRunning, trace.GoRunnable, trace.GoWaiting, trace.GoSyscall}
+
+	if format == output.FormatJSON {
+		states := make(map[string]int64)
+		for _, state := range stateNames {
+			states[strings.ToLower(state.String())] = stats.states[state].Milliseconds()
+		}
+		blockRows := make([]map[string]interface{}, 0, len(blocks))
+		for _, b := range blocks {
+			blockRows = append(blockRows, map[string]interface{}{
+				"reason":   b.reason,
+				"count":    b.count,
+				"total_ms": b.total.Milliseconds(),
+			})
+		}
+		output.PrintSuccess(format, map[string]interface{}{
+			"file":               args[0],
+			"duration_ms":        duration.Milliseconds(),
+			"events":             stats.events,
+			"goroutines":         len(stats.goroutines),
+			"goroutines_created": stats.created,
+			"goroutine_ms":       states,
+			"gc_cycles":          stats.gcCycles,
+			"gc_ms":              stats.gcTime.Milliseconds(),
+			"stw_pauses":         stats.stw,
+			"stw_total_us":       stats.stwTotal.Microseconds(),
+			"stw_max_us":         stats.stwMax.Microseconds(),
+			"gomaxprocs":         stats.gomaxprocs,
+			"heap_max_bytes":     stats.heapMax,
+			"heap_goal_bytes":    stats.heapGoal,
+			"tasks":              stats.tasks,
+			"regions":            stats.regions,
+			"logs":               stats.logs,
+			"blocks":             blockRows,
+		})
+		return nil
+	}
+
+	fmt.Printf("File: %s\n", filepath.Base(args[0]))
+	fmt.Printf("Duration: %s\n", duration.Round(time.Microsecond))
+	fmt.Printf("Events: %d\n", stats.events)
+	if stats.gomaxprocs > 0 {
+		fmt.Printf("GOMAXPROCS: %d\n", stats.gomaxprocs)
+	}
+	fmt.Printf("Goroutines: %d (%d created)\n", len(stats.goroutines), stats.created)
+	if stats.heapMax > 0 {
+		fmt.Printf("Heap: %s max, %s goal\n", formatPprofValue(int64(stats.heapMax), "bytes"), formatPprofValue(int64(stats.heapGoal), "bytes"))
+	}
+	fmt.Printf("GC: %d cycles, %s\n", stats.gcCycles, stats.gcTime.Round(time.Microsecond))
+	if stats.stw > 0 {
+		fmt.Printf("Stop-the-world: %d pauses, %s total, %s max\n", stats.stw,
+			stats.stwTotal.Round(time.Microsecond), stats.stwMax.Round(time.Microsecond))
+	} else {
+		fmt.Println("Stop-the-world: none")
+	}
+	fmt.Printf("Tasks: %d, regions: %d, logs: %d\n", stats.tasks, stats.regions, stats.logs)
+
+	var goroutineTime time.Duration
+	for _, state := range stateNames {
+		goroutineTime += stats.states[state]
+	}
+	fmt.Println()
+	fmt.Println(output.Accent(fmt.Sprintf("%-10s %14s %7s", "STATE", "TIME", "SHARE")))
+	fmt.Println(output.Rule(33))
+	for _, state := range stateNames {
+		d := stats.states[state]
+		fmt.Printf("%-10s %14s %6.2f%%\n", strings.ToLower(state.String()), d.Round(time.Microsecond),
+			pprofPercent(int64(d), int64(goroutineTime)))
+	}
+
+	if len(blocks) > 0 {
+		fmt.Println()
+		fmt.Println(output.Accent(fmt.Sprintf("%-28s %8s %14s", "BLOCKED ON", "COUNT", "TIME")))
+		fmt.Println(output.Rule(52))
+		for _, b := range blocks {
+			fmt.Printf("%-28s %8d %14s\n", b.reason, b.count, b.total.Round(time.Microsecond))
+		}
+	}
+	return nil
+}
+
+// Go versions whose trace format golang.org/x/exp/trace reads; update them
+// when the module is bumped
+const (
+	traceMinVersion = 22
+	traceMaxVersion = 26
+)
+
+// readTrace reads all events of a trace and adds them up
+func readTrace(r io.Reader) (*traceStats, error) {
+	br := bufio.NewReader(r)
+	header, _ := br.Peek(16) // "go 1.26 trace\x00\x00\x00"
+	reader, err := trace.NewReader(br)
+	if err != nil {
+		var minor int
+		if _, scanErr := fmt.Sscanf(string(header), "go 1.%d trace", &minor); scanErr == nil &&
+			(minor < traceMinVersion || minor > traceMaxVersion) {
+			return nil, fmt.Errorf("trace was written by Go 1.%d, only traces of Go 1.%d to 1.%d are supported",
+				minor, traceMinVersion, traceMaxVersion)
+		}
+		return nil, fmt.Errorf("not a supported Go execution trace: %w", err)
+	}
+
+	s := &traceStats{
+		goroutines: make(map[trace.GoID]*traceGoroutine),
+		states:     make(map[trace.GoState]time.Duration),
+		blocks:     make(map[string]*traceBlock),
+		ranges:     make(map[string]trace.Time),
+	}
+	for {
+		event, err := reader.ReadEvent()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read trace: %w", err)
+		}
+		s.add(event)
+	}
+	if s.events == 0 {
+		return nil, fmt.Errorf("trace has no events")
+	}
+
+	// Goroutines still alive are counted up to the end of the trace
+	for _, g := range s.goroutines {
+		s.leave(g, s.end)
+	}
+	return s, nil
+}
+
+// add counts one event
+func (s *traceStats) add(event trace.Event) {
+	now := event.Time()
+	if s.events == 0 {
+		s.start = now
+	}
+	s.events++
+	s.end = now
+
+	switch event.Kind() {
+	case trace.EventStateTransition:
+		st := event.StateTransition()
+		if st.Resource.Kind != trace.ResourceGoroutine {
+			return
+		}
+		from, to := st.Goroutine()
+		id := st.Resource.Goroutine()
+		g, ok := s.goroutines[id]
+		if !ok {
+			g = &traceGoroutine{state: from, since: s.start}
+			s.goroutines[id] = g
+		}
+		if from == trace.GoNotExist && to != trace.GoNotExist {
+			s.created++
+		}
+		s.leave(g, now)
+		g.state, g.since, g.reason = to, now, st.Reason
+
+	case trace.EventRangeBegin, trace.EventRangeActive:
+		r := event.Range()
+		if !traceTimedRange(r.Name) {
+			return
+		}
+		if _, ok := s.ranges[r.Name]; !ok {
+			s.ranges[r.Name] = now
+		}
+		if event.Kind() == trace.EventRangeBegin && r.Name == traceRangeGCMark {
+			s.gcCycles++
+		}
+
+	case trace.EventRangeEnd:
+		r := event.Range()
+		if !traceTimedRange(r.Name) {
+			return
+		}
+		begin, ok := s.ranges[r.Name]
+		if !ok {
+			begin = s.start
+		}
+		delete(s.ranges, r.Name)
+		d := now.Sub(begin)
+		switch {
+		case r.Name == traceRangeGCMark:
+			s.gcTime += d
+		case strings.HasPrefix(r.Name, "stop-the-world"):
+			s.stw++
+			s.stwTotal += d
+			if d > s.stwMax {
+				s.stwMax = d
+			}
+		}
+
+	case trace.EventMetric:
+		m := event.Metric()
+		if m.Value.Kind() != trace.ValueUint64 {
+			return
+		}
+		switch v := m.Value.Uint64(); m.Name {
+		case traceMetricGomaxprocs:
+			s.gomaxprocs = v
+		case traceMetricHeapLive:
+			if v > s.heapMax {
+				s.heapMax = v
+			}
+		case traceMetricHeapGoal:
+			s.heapGoal = v
+		}
+
+	case trace.EventTaskBegin:
+		s.tasks++
+	case trace.EventRegionBegin:
+		s.regions++
+	case trace.EventLog:
+		s.logs++
+	}
+}
+
+// leave adds the time g spent in its current state up to now, and to its
+// block reason when it was waiting
+func (s *traceStats) leave(g *traceGoroutine, now trace.Time) {
+	d := now.Sub(g.since)
+	if d <= 0 {
+		return
+	}
+	switch g.state {
+	case trace.GoRunning, trace.GoRunnable, trace.GoSyscall:
+		s.states[g.state] += d
+	case trace.GoWaiting:
+		s.states[g.state] += d
+		reason := g.reason
+		if reason == "" {
+			reason = "unknown"
+		}
+		b, ok := s.blocks[reason]
+		if !ok {
+			b = &traceBlock{reason: reason}
+			s.blocks[reason] = b
+		}
+		b.count++
+		b.total += d
+	}
+	g.since = now
+}
+
+// sortedBlocks returns the block reasons with the most time first
+func (s *traceStats) sortedBlocks() []*traceBlock {
+	blocks := make([]*traceBlock, 0, len(s.blocks))
+	for _, b := range s.blocks {
+		blocks = append(blocks, b)
+	}
+	sort.Slice(blocks, func(i, j int) bool {
+		if blocks[i].total != blocks[j].total {
+			return blocks[i].total > blocks[j].total
+		}
+		return blocks[i].reason < blocks[j].reason
+	})
+	return blocks
+}
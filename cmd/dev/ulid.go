@@ -1,7 +1,12 @@
 package dev
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
+	"io"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/oklog/ulid/v2"
@@ -21,23 +26,55 @@ ULID is a 26-character string that is:
 - URL-safe
 - Case-insensitive
 
+The first 10 characters encode the time in milliseconds and the remaining
+16 are random, so ULIDs created in the same millisecond sort in random
+order. With --monotonic the random part of each such ULID is the previous
+one plus a random increment, so that they sort in the order they were
+generated. --time uses the given time instead of the current one; it
+accepts an RFC 3339 date or Unix milliseconds.
+
+Use "devkit dev ulid decode" to read the timestamp back.
+
 Examples:
   devkit dev ulid                    # Generate a single ULID
   devkit dev ulid --count 5          # Generate 5 ULIDs
+  devkit dev ulid --count 100 --monotonic
+  devkit dev ulid --time 2024-01-01T00:00:00Z
   devkit dev ulid --count 3 --output json`,
 	RunE: runULID,
 }
 
+// ulidDecodeCmd represents the ulid decode subcommand
+var ulidDecodeCmd = &cobra.Command{
+	Use:   "decode [ulid]",
+	Short: "Show the timestamp embedded in a ULID",
+	Long: `Parse a ULID and show its embedded timestamp and random part. Lower case
+input is accepted.
+
+Examples:
+  devkit dev ulid decode 01HV5Q8Z6W3X5G9K2N4R7T1B0C
+  devkit dev ulid decode 01hv5q8z6w3x5g9k2n4r7t1b0c --output json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runULIDDecode,
+}
+
 func init() {
 	devCmd.AddCommand(ulidCmd)
+	ulidCmd.AddCommand(ulidDecodeCmd)
 
 	// Flag definitions
 	ulidCmd.Flags().IntP("count", "c", 1, "Number of ULIDs to generate")
+	ulidCmd.Flags().Bool("monotonic", false, "Keep ULIDs from the same millisecond in generation order")
+	ulidCmd.Flags().String("time", "", "Timestamp to embed: RFC 3339 date or Unix milliseconds (default: now)")
 	ulidCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json, table")
+
+	ulidDecodeCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
 }
 
 func runULID(cmd *cobra.Command, args []string) error {
 	count, _ := cmd.Flags().GetInt("count")
+	monotonic, _ := cmd.Flags().GetBool("monotonic")
+	timeFlag, _ := cmd.Flags().GetString("time")
 	outputFormat, _ := cmd.Flags().GetString("output")
 	format := output.OutputFormat(outputFormat)
 
@@ -49,7 +86,16 @@ func runULID(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("count cannot exceed 1000")
 	}
 
-	ulids, err := generateULID(count)
+	var at *time.Time
+	if timeFlag != "" {
+		t, err := parseULIDTime(timeFlag)
+		if err != nil {
+			return err
+		}
+		at = &t
+	}
+
+	ulids, err := generateULID(count, monotonic, at)
 	if err != nil {
 		return fmt.Errorf("failed to generate ULID: %w", err)
 	}
@@ -57,8 +103,12 @@ func runULID(cmd *cobra.Command, args []string) error {
 	// Prepare result based on format
 	if format == output.FormatJSON {
 		result := map[string]interface{}{
-			"count": count,
-			"ulids": ulids,
+			"count":     count,
+			"monotonic": monotonic,
+			"ulids":     ulids,
+		}
+		if at != nil {
+			result["timestamp"] = at.UTC().Format("2006-01-02T15:04:05.000Z07:00")
 		}
 		output.PrintSuccess(format, result)
 	} else {
@@ -73,14 +123,78 @@ func runULID(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func generateULID(count int) ([]string, error) {
+// generateULID creates count ULIDs for the time at, or for the current
+// time when at is nil
+func generateULID(count int, monotonic bool, at *time.Time) ([]string, error) {
+	var entropy io.Reader = rand.Reader
+	if monotonic {
+		entropy = ulid.Monotonic(rand.Reader, 0)
+	}
+
 	ulids := make([]string, count)
-	entropy := ulid.DefaultEntropy()
-	
 	for i := 0; i < count; i++ {
-		id := ulid.MustNew(ulid.Timestamp(time.Now()), entropy)
+		ms := ulid.Now()
+		if at != nil {
+			ms = ulid.Timestamp(*at)
+		}
+		id, err := ulid.New(ms, entropy)
+		if err != nil {
+			return nil, err
+		}
 		ulids[i] = id.String()
 	}
-	
+
 	return ulids, nil
 }
+
+// parseULIDTime parses --time: Unix milliseconds or an RFC 3339 date, with
+// or without a time of day
+func parseULIDTime(s string) (time.Time, error) {
+	if ms, err := strconv.ParseInt(s, 10, 64); err == nil {
+		if ms < 0 || uint64(ms) > ulid.MaxTime() {
+			return time.Time{}, fmt.Errorf("time out of range for a ULID: %s", s)
+		}
+		return time.UnixMilli(ms), nil
+	}
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			if t.Before(time.Unix(0, 0)) {
+				return time.Time{}, fmt.Errorf("time out of range for a ULID: %s", s)
+			}
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time: %s (use an RFC 3339 date or Unix milliseconds)", s)
+}
+
+func runULIDDecode(cmd *cobra.Command, args []string) error {
+	outputFormat, _ := cmd.Flags().GetString("output")
+	format := output.OutputFormat(outputFormat)
+
+	input := strings.TrimSpace(args[0])
+	id, err := ulid.ParseStrict(input)
+	if err != nil {
+		return fmt.Errorf("invalid ULID: %s (%w)", input, err)
+	}
+
+	millis := int64(id.Time())
+	timestamp := ulid.Time(id.Time()).UTC().Format("2006-01-02T15:04:05.000Z07:00")
+	entropy := strings.ToUpper(hex.EncodeToString(id.Entropy()))
+
+	if format == output.FormatJSON {
+		output.PrintSuccess(format, map[string]interface{}{
+			"input":     args[0],
+			"ulid":      id.String(),
+			"timestamp": timestamp,
+			"unix_ms":   millis,
+			"entropy":   entropy,
+		})
+		return nil
+	}
+
+	fmt.Printf("ULID: %s\n", id.String())
+	fmt.Printf("Timestamp: %s (%s)\n", timestamp, formatAge(millis))
+	fmt.Printf("Unix ms: %d\n", millis)
+	fmt.Printf("Entropy: %s\n", entropy)
+	return nil
+}
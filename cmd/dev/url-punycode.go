@@ -0,0 +1,199 @@
+package dev
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/net/idna"
+	"devkit/internal/output"
+)
+
+// urlPunycodeCmd represents the url punycode command group
+var urlPunycodeCmd = &cobra.Command{
+	Use:   "punycode",
+	Short: "Convert internationalized domain names to and from punycode",
+	Long: `Convert internationalized domain names (IDNs) such as münchen.de to their
+ASCII "xn--" form used by DNS, certificates and HTTP, and back.
+
+Examples:
+  devkit dev url punycode encode münchen.de
+  devkit dev url punycode decode xn--mnchen-3ya.de
+  devkit dev url punycode encode "https://bücher.example/katalog?q=ä"`,
+}
+
+// urlPunycodeEncodeCmd represents the url punycode encode subcommand
+var urlPunycodeEncodeCmd = &cobra.Command{
+	Use:   "encode <domain|url>...",
+	Short: "Convert domain names to punycode",
+	Long: `Convert domain names to their ASCII punycode form (IDNA 2008, as browsers
+resolve them): labels with non-ASCII characters become "xn--" labels and
+upper case is folded. When a URL is given, only its host is converted and
+the rest is kept as written.
+
+Examples:
+  devkit dev url punycode encode münchen.de
+  devkit dev url punycode encode "https://user@bücher.example:8443/katalog?q=ä"
+  devkit dev url punycode encode 例え.テスト Straße.de --output json
+  cat domains.txt | devkit dev url punycode encode --stdin`,
+	RunE: runURLPunycode,
+}
+
+// urlPunycodeDecodeCmd represents the url punycode decode subcommand
+var urlPunycodeDecodeCmd = &cobra.Command{
+	Use:   "decode <domain|url>...",
+	Short: "Convert punycode domain names to Unicode",
+	Long: `Convert "xn--" labels of domain names back to Unicode, e.g. to show the
+real name of a host found in logs or certificates. When a URL is given,
+only its host is converted and the rest is kept as written.
+
+Examples:
+  devkit dev url punycode decode xn--mnchen-3ya.de
+  devkit dev url punycode decode "https://xn--bcher-kva.example/katalog"
+  devkit dev url punycode decode xn--r8jz45g.xn--zckzah --output json`,
+	RunE: runURLPunycode,
+}
+
+func init() {
+	urlCmd.AddCommand(urlPunycodeCmd)
+	for _, c := range []*cobra.Command{urlPunycodeEncodeCmd, urlPunycodeDecodeCmd} {
+		urlPunycodeCmd.AddCommand(c)
+		c.Flags().BoolP("stdin", "s", false, "Read domains or URLs from stdin, one per line")
+		c.Flags().StringP("output", "o", "plain", "Output format: plain, json")
+	}
+}
+
+// urlPunycodeResult is one converted domain or URL
+type urlPunycodeResult struct {
+	Input   string `json:"input"`
+	Output  string `json:"output"`
+	ASCII   string `json:"ascii"`
+	Unicode string `json:"unicode"`
+}
+
+func runURLPunycode(cmd *cobra.Command, args []string) error {
+	stdinFlag, _ := cmd.Flags().GetBool("stdin")
+	outputFormat, _ := cmd.Flags().GetString("output")
+	format := output.OutputFormat(outputFormat)
+
+	inputs := args
+	if stdinFlag {
+		stat, err := os.Stdin.Stat()
+		if err != nil {
+			return fmt.Errorf("stdin error: %w", err)
+		}
+		if (stat.Mode() & os.ModeCharDevice) != 0 {
+			return fmt.Errorf("no data available from stdin")
+		}
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			if line := strings.TrimSpace(scanner.Text()); line != "" {
+				inputs = append(inputs, line)
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return fmt.Errorf("read stdin error: %w", err)
+		}
+	}
+	if len(inputs) == 0 {
+		return fmt.Errorf("input not specified")
+	}
+	cmd.SilenceUsage = true
+
+	toUnicode := cmd.Name() == "decode"
+	results := make([]urlPunycodeResult, 0, len(inputs))
+	for _, input := range inputs {
+		result, err := convertPunycode(input, toUnicode)
+		if err != nil {
+			return err
+		}
+		results = append(results, result)
+	}
+
+	if format == output.FormatJSON {
+		output.PrintSuccess(format, map[string]interface{}{
+			"results": results,
+			"count":   len(results),
+		})
+		return nil
+	}
+
+	for _, r := range results {
+		fmt.Println(r.Output)
+	}
+	return nil
+}
+
+// convertPunycode converts a domain, or the host of a URL, to its ASCII or
+// Unicode form
+func convertPunycode(input string, toUnicode bool) (urlPunycodeResult, error) {
+	prefix, host, suffix := "", input, ""
+	if strings.Contains(input, "://") {
+		if _, err := url.Parse(input); err != nil {
+			return urlPunycodeResult{}, fmt.Errorf("failed to parse URL: %w", err)
+		}
+		prefix, host, suffix = splitURLHost(input)
+		if host == "" {
+			return urlPunycodeResult{}, fmt.Errorf("URL has no host: %s", input)
+		}
+	}
+
+	ascii, unicode, err := idnHostForms(host)
+	if err != nil {
+		return urlPunycodeResult{}, fmt.Errorf("invalid domain name %q: %w", host, err)
+	}
+	converted := ascii
+	if toUnicode {
+		converted = unicode
+	}
+	return urlPunycodeResult{
+		Input:   input,
+		Output:  prefix + converted + suffix,
+		ASCII:   ascii,
+		Unicode: unicode,
+	}, nil
+}
+
+// splitURLHost cuts a URL around the host name, so that the scheme, user
+// info, port, path, query and fragment can be kept verbatim
+func splitURLHost(rawURL string) (string, string, string) {
+	i := strings.Index(rawURL, "://") + len("://")
+	authority := rawURL[i:]
+	if end := strings.IndexAny(authority, "/?#"); end >= 0 {
+		authority = authority[:end]
+	}
+	if at := strings.LastIndex(authority, "@"); at >= 0 {
+		i += at + 1
+		authority = authority[at+1:]
+	}
+	host := authority
+	if strings.HasPrefix(authority, "[") {
+		if end := strings.Index(authority, "]"); end >= 0 {
+			host = authority[:end+1]
+		}
+	} else if h, _, err := net.SplitHostPort(authority); err == nil {
+		host = h
+	}
+	return rawURL[:i], host, rawURL[i+len(host):]
+}
+
+// idnHostForms returns the ASCII (punycode) and Unicode forms of a host
+// name. IP addresses are returned unchanged.
+func idnHostForms(host string) (string, string, error) {
+	if net.ParseIP(strings.Trim(host, "[]")) != nil {
+		return host, host, nil
+	}
+	ascii, err := idna.Lookup.ToASCII(host)
+	if err != nil {
+		return "", "", err
+	}
+	unicode, err := idna.Display.ToUnicode(ascii)
+	if err != nil {
+		return "", "", err
+	}
+	return ascii, unicode, nil
+}
@@ -0,0 +1,299 @@
+package dev
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"devkit/internal/output"
+)
+
+// urlQueryCmd represents the url query command group
+var urlQueryCmd = &cobra.Command{
+	Use:   "query",
+	Short: "Add, remove, read and sort query parameters",
+	Long: `Edit the query string of a URL without regular expressions. Parameters that
+are not touched keep their order and encoding, and the rest of the URL,
+including the fragment, is left as is.
+
+Examples:
+  devkit dev url query add "https://example.com/?a=1" ref=newsletter
+  devkit dev url query remove "https://example.com/?utm_source=x&id=7" "utm_*"
+  devkit dev url query get "https://example.com/?q=go+cli" q
+  devkit dev url query sort "https://example.com/?b=2&a=1"`,
+}
+
+// urlQueryAddCmd represents the url query add subcommand
+var urlQueryAddCmd = &cobra.Command{
+	Use:   "add <url> <key=value>...",
+	Short: "Add query parameters to a URL",
+	Long: `Append query parameters to a URL, escaping keys and values. A key that is
+already present gets another value, unless --replace is given, which
+replaces its values in place.
+
+Examples:
+  devkit dev url query add "https://example.com/docs?page=2" ref=newsletter
+  devkit dev url query add "https://example.com/?page=2" page=3 --replace
+  devkit dev url query add https://example.com/search "q=a&b c" lang=en --output json`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: runURLQuery,
+}
+
+// urlQueryRemoveCmd represents the url query remove subcommand
+var urlQueryRemoveCmd = &cobra.Command{
+	Use:   "remove <url> <key>...",
+	Short: "Remove query parameters from a URL",
+	Long: `Remove query parameters by key. Keys can be glob patterns, so "utm_*"
+strips every tracking parameter; quote them so the shell does not expand
+them. An empty query string is dropped together with its "?".
+
+Examples:
+  devkit dev url query remove "https://example.com/?utm_source=x&utm_medium=y&id=7" "utm_*"
+  devkit dev url query remove "https://example.com/?a=1&b=2&c=3" a c
+  devkit dev url query remove "https://example.com/?fbclid=x#top" fbclid gclid --output json`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: runURLQuery,
+}
+
+// urlQueryGetCmd represents the url query get subcommand
+var urlQueryGetCmd = &cobra.Command{
+	Use:   "get <url> [key]",
+	Short: "Print query parameter values",
+	Long: `Print the decoded values of a query parameter, one per line, and fail when
+the URL does not have it. Without a key, every parameter is printed as
+key=value.
+
+Examples:
+  devkit dev url query get "https://example.com/?q=go+cli&page=2" q
+  devkit dev url query get "https://example.com/?tag=a&tag=b" tag
+  devkit dev url query get "https://example.com/?q=go+cli&page=2" --output json`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runURLQuery,
+}
+
+// urlQuerySortCmd represents the url query sort subcommand
+var urlQuerySortCmd = &cobra.Command{
+	Use:   "sort <url>",
+	Short: "Sort query parameters by key",
+	Long: `Sort query parameters by key to get a canonical URL, e.g. for cache keys or
+comparing URLs. Values of the same key keep their order.
+
+Examples:
+  devkit dev url query sort "https://example.com/?b=2&a=1&b=1"
+  devkit dev url query sort "https://example.com/?z=1&a=2" --output json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runURLQuery,
+}
+
+func init() {
+	urlCmd.AddCommand(urlQueryCmd)
+	for _, c := range []*cobra.Command{urlQueryAddCmd, urlQueryRemoveCmd, urlQueryGetCmd, urlQuerySortCmd} {
+		urlQueryCmd.AddCommand(c)
+		c.Flags().StringP("output", "o", "plain", "Output format: plain, json")
+	}
+	urlQueryAddCmd.Flags().Bool("replace", false, "Replace the values of keys that are already present")
+}
+
+// urlQueryParam is one key=value pair of a query string. raw is the pair as
+// written in the URL, kept for parameters that are not changed.
+type urlQueryParam struct {
+	Key      string `json:"key"`
+	Value    string `json:"value"`
+	hasValue bool
+	raw      string
+}
+
+// newURLQueryParam reads key=value, or a bare key
+func newURLQueryParam(pair string) urlQueryParam {
+	key, value, hasValue := strings.Cut(pair, "=")
+	return urlQueryParam{Key: key, Value: value, hasValue: hasValue}
+}
+
+// String returns the pair as written in the URL, escaping new pairs
+func (p urlQueryParam) String() string {
+	if p.raw != "" {
+		return p.raw
+	}
+	s := url.QueryEscape(p.Key)
+	if p.hasValue {
+		s += "=" + url.QueryEscape(p.Value)
+	}
+	return s
+}
+
+// parseURLQuery splits a raw query string into its parameters, decoding
+// keys and values
+func parseURLQuery(rawQuery string) ([]urlQueryParam, error) {
+	var params []urlQueryParam
+	for _, pair := range strings.Split(rawQuery, "&") {
+		if pair == "" {
+			continue
+		}
+		rawKey, rawValue, hasValue := strings.Cut(pair, "=")
+		key, err := url.QueryUnescape(rawKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid query parameter %q: %w", pair, err)
+		}
+		value, err := url.QueryUnescape(rawValue)
+		if err != nil {
+			return nil, fmt.Errorf("invalid query parameter %q: %w", pair, err)
+		}
+		params = append(params, urlQueryParam{Key: key, Value: value, hasValue: hasValue, raw: pair})
+	}
+	return params, nil
+}
+
+// splitURLQuery cuts a URL into the part before the query, the raw query
+// and the fragment (with its "#"), so that the other parts stay verbatim
+func splitURLQuery(s string) (string, string, string) {
+	rest, fragment := s, ""
+	if i := strings.Index(s, "#"); i >= 0 {
+		rest, fragment = s[:i], s[i:]
+	}
+	base, query, _ := strings.Cut(rest, "?")
+	return base, query, fragment
+}
+
+func runURLQuery(cmd *cobra.Command, args []string) error {
+	outputFormat, _ := cmd.Flags().GetString("output")
+	format := output.OutputFormat(outputFormat)
+
+	if _, err := url.Parse(args[0]); err != nil {
+		return fmt.Errorf("failed to parse URL: %w", err)
+	}
+	base, rawQuery, fragment := splitURLQuery(args[0])
+	params, err := parseURLQuery(rawQuery)
+	if err != nil {
+		return err
+	}
+	cmd.SilenceUsage = true
+
+	switch cmd.Name() {
+	case "get":
+		key := ""
+		if len(args) > 1 {
+			key = args[1]
+		}
+		return printURLQueryValues(args[0], key, params, format)
+	case "add":
+		replace, _ := cmd.Flags().GetBool("replace")
+		for _, pair := range args[1:] {
+			param := newURLQueryParam(pair)
+			if param.Key == "" {
+				return fmt.Errorf("invalid query parameter: %q (use key=value)", pair)
+			}
+			if replace {
+				params = replaceURLQueryParam(params, param)
+			} else {
+				params = append(params, param)
+			}
+		}
+	case "remove":
+		for _, pattern := range args[1:] {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("invalid pattern: %s", pattern)
+			}
+		}
+		kept := params[:0]
+		for _, p := range params {
+			if !urlQueryKeyMatches(p.Key, args[1:]) {
+				kept = append(kept, p)
+			}
+		}
+		params = kept
+	case "sort":
+		sort.SliceStable(params, func(i, j int) bool { return params[i].Key < params[j].Key })
+	}
+
+	pairs := make([]string, len(params))
+	for i, p := range params {
+		pairs[i] = p.String()
+	}
+	result := base
+	if len(pairs) > 0 {
+		result += "?" + strings.Join(pairs, "&")
+	}
+	result += fragment
+
+	if format == output.FormatJSON {
+		if params == nil {
+			params = []urlQueryParam{}
+		}
+		output.PrintSuccess(format, map[string]interface{}{
+			"input":  args[0],
+			"url":    result,
+			"query":  strings.Join(pairs, "&"),
+			"params": params,
+		})
+		return nil
+	}
+
+	fmt.Println(result)
+	return nil
+}
+
+// replaceURLQueryParam sets the value of the first parameter with the key
+// of param and drops the others, or appends param when there is none
+func replaceURLQueryParam(params []urlQueryParam, param urlQueryParam) []urlQueryParam {
+	replaced := false
+	kept := params[:0]
+	for _, p := range params {
+		if p.Key != param.Key {
+			kept = append(kept, p)
+		} else if !replaced {
+			kept = append(kept, param)
+			replaced = true
+		}
+	}
+	if !replaced {
+		kept = append(kept, param)
+	}
+	return kept
+}
+
+// urlQueryKeyMatches reports whether key matches one of the glob patterns
+func urlQueryKeyMatches(key string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, key); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// printURLQueryValues prints the values of key, or every parameter when
+// key is empty
+func printURLQueryValues(rawURL, key string, params []urlQueryParam, format output.OutputFormat) error {
+	matched := []urlQueryParam{}
+	values := []string{}
+	for _, p := range params {
+		if key == "" || p.Key == key {
+			matched = append(matched, p)
+			values = append(values, p.Value)
+		}
+	}
+	if key != "" && len(matched) == 0 {
+		return fmt.Errorf("query parameter not found: %s", key)
+	}
+
+	if format == output.FormatJSON {
+		output.PrintSuccess(format, map[string]interface{}{
+			"url":    rawURL,
+			"key":    key,
+			"values": values,
+			"params": matched,
+		})
+		return nil
+	}
+	for _, p := range matched {
+		if key == "" {
+			fmt.Printf("%s=%s\n", p.Key, p.Value)
+		} else {
+			fmt.Println(p.Value)
+		}
+	}
+	return nil
+}
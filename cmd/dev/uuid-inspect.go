@@ -0,0 +1,151 @@
+package dev
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+	"devkit/internal/output"
+)
+
+// uuidInspectCmd represents the uuid inspect subcommand
+var uuidInspectCmd = &cobra.Command{
+	Use:   "inspect [uuid]",
+	Short: "Show the version, variant and timestamp of a UUID",
+	Long: `Parse a UUID and report its version and variant. For time-based UUIDs
+(v1, v6, v7) the embedded timestamp is shown, and for v1 and v6 the clock
+sequence and node ID as well. The UUID is also printed in its canonical,
+braced, URN and plain hex forms, any of which is accepted as input.
+
+Examples:
+  devkit dev uuid inspect 0190b8a4-5c3e-7cc4-9f4a-2b1e6d0c8f11
+  devkit dev uuid inspect "{6ba7b810-9dad-11d1-80b4-00c04fd430c8}"
+  devkit dev uuid inspect urn:uuid:6ba7b810-9dad-11d1-80b4-00c04fd430c8 --output json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runUUIDInspect,
+}
+
+func init() {
+	uuidCmd.AddCommand(uuidInspectCmd)
+
+	uuidInspectCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
+}
+
+// uuidVersionNames describes the versions defined by RFC 9562
+var uuidVersionNames = map[uuid.Version]string{
+	1: "time-based",
+	2: "DCE security",
+	3: "name-based (MD5)",
+	4: "random",
+	5: "name-based (SHA-1)",
+	6: "reordered time-based",
+	7: "Unix time-based",
+	8: "custom",
+}
+
+func runUUIDInspect(cmd *cobra.Command, args []string) error {
+	outputFormat, _ := cmd.Flags().GetString("output")
+	format := output.OutputFormat(outputFormat)
+
+	id, err := uuid.Parse(strings.TrimSpace(args[0]))
+	if err != nil {
+		return fmt.Errorf("invalid UUID: %w", err)
+	}
+
+	result := inspectUUID(id)
+	result["input"] = args[0]
+
+	if format == output.FormatJSON {
+		output.PrintSuccess(format, result)
+		return nil
+	}
+
+	fmt.Printf("UUID: %s\n", result["uuid"])
+	fmt.Printf("Version: %s\n", result["version_name"])
+	fmt.Printf("Variant: %s\n", result["variant"])
+	if ts, ok := result["timestamp"]; ok {
+		fmt.Printf("Timestamp: %s\n", ts)
+	}
+	if seq, ok := result["clock_sequence"]; ok {
+		fmt.Printf("Clock Sequence: %d\n", seq)
+		fmt.Printf("Node: %s\n", result["node"])
+	}
+	formats := result["formats"].(map[string]string)
+	fmt.Println("Formats:")
+	for _, name := range []string{"canonical", "braced", "urn", "hex", "uppercase"} {
+		fmt.Printf("  %-10s %s\n", name+":", formats[name])
+	}
+
+	return nil
+}
+
+// inspectUUID decodes the fields of id
+func inspectUUID(id uuid.UUID) map[string]interface{} {
+	canonical := id.String()
+	result := map[string]interface{}{
+		"uuid":    canonical,
+		"version": int(id.Version()),
+		"variant": uuidVariantName(id),
+		"formats": map[string]string{
+			"canonical": canonical,
+			"braced":    "{" + canonical + "}",
+			"urn":       id.URN(),
+			"hex":       hex.EncodeToString(id[:]),
+			"uppercase": strings.ToUpper(canonical),
+		},
+	}
+
+	switch {
+	case id == uuid.Nil:
+		result["version_name"] = "nil UUID"
+		return result
+	case id == uuid.Max:
+		result["version_name"] = "max UUID"
+		return result
+	}
+
+	name, ok := uuidVersionNames[id.Version()]
+	if !ok || id.Variant() != uuid.RFC4122 {
+		name = "unknown"
+	}
+	result["version_name"] = fmt.Sprintf("%d (%s)", id.Version(), name)
+	if id.Variant() != uuid.RFC4122 {
+		return result
+	}
+
+	switch id.Version() {
+	case 1, 6:
+		sec, nsec := id.Time().UnixTime()
+		result["timestamp"] = time.Unix(sec, nsec).UTC().Format(time.RFC3339Nano)
+		result["clock_sequence"] = id.ClockSequence()
+		node := id.NodeID()
+		parts := make([]string, len(node))
+		for i, b := range node {
+			parts[i] = fmt.Sprintf("%02x", b)
+		}
+		result["node"] = strings.Join(parts, ":")
+		// The multicast bit marks a random node ID rather than a MAC address
+		result["node_random"] = node[0]&0x01 != 0
+	case 7:
+		sec, nsec := id.Time().UnixTime()
+		result["timestamp"] = time.Unix(sec, nsec).UTC().Format("2006-01-02T15:04:05.000Z07:00")
+	}
+	return result
+}
+
+// uuidVariantName names the variant field of id
+func uuidVariantName(id uuid.UUID) string {
+	switch id.Variant() {
+	case uuid.RFC4122:
+		return "RFC 9562"
+	case uuid.Microsoft:
+		return "Microsoft (reserved)"
+	case uuid.Future:
+		return "future (reserved)"
+	default:
+		return "NCS (reserved)"
+	}
+}
@@ -0,0 +1,217 @@
+package dev
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+	"devkit/internal/check"
+	"devkit/internal/output"
+)
+
+// uuidValidateCmd represents the uuid validate subcommand
+var uuidValidateCmd = &cobra.Command{
+	Use:   "validate [uuid...]",
+	Short: "Check whether values are valid UUIDs",
+	Long: `Check UUIDs given as arguments, in a file or on stdin (one per line) and
+report each as valid, with its version, or invalid, with the reason. Blank
+lines are skipped.
+
+Braced, URN and plain hex forms are accepted unless --strict is given, which
+only accepts the canonical 8-4-4-4-12 form. --version additionally requires
+an RFC 9562 UUID of that version. --only prints just the valid or just the
+invalid values, one per line, for use in pipelines.
+
+Exits with status 2 if any value is invalid (see --fail-on).
+
+Examples:
+  devkit dev uuid validate 0190b8a4-5c3e-7cc4-9f4a-2b1e6d0c8f11 not-a-uuid
+  devkit dev uuid validate --file ids.txt --version 4
+  cat ids.txt | devkit dev uuid validate --stdin --strict --only invalid
+  devkit dev uuid validate --file ids.txt --output json --fail-on none`,
+	RunE: runUUIDValidate,
+}
+
+func init() {
+	uuidCmd.AddCommand(uuidValidateCmd)
+
+	uuidValidateCmd.Flags().StringP("file", "f", "", "Read UUIDs from a file, one per line")
+	uuidValidateCmd.Flags().Bool("stdin", false, "Read UUIDs from stdin, one per line")
+	uuidValidateCmd.Flags().Bool("strict", false, "Only accept the canonical hyphenated form")
+	uuidValidateCmd.Flags().Int("version", 0, "Require this UUID version (1-8)")
+	uuidValidateCmd.Flags().String("only", "", "Print only valid or invalid values: valid, invalid")
+	uuidValidateCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
+	check.AddFailOnFlag(uuidValidateCmd, check.Invalid)
+}
+
+// uuidValidation is the result for one input value
+type uuidValidation struct {
+	Input   string `json:"input"`
+	Line    int    `json:"line,omitempty"`
+	Valid   bool   `json:"valid"`
+	UUID    string `json:"uuid,omitempty"`
+	Version int    `json:"version"`
+	Variant string `json:"variant,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+func runUUIDValidate(cmd *cobra.Command, args []string) error {
+	fileFlag, _ := cmd.Flags().GetString("file")
+	stdinFlag, _ := cmd.Flags().GetBool("stdin")
+	strict, _ := cmd.Flags().GetBool("strict")
+	version, _ := cmd.Flags().GetInt("version")
+	only, _ := cmd.Flags().GetString("only")
+	outputFormat, _ := cmd.Flags().GetString("output")
+	format := output.OutputFormat(outputFormat)
+
+	if version < 0 || version > 8 {
+		return fmt.Errorf("version must be between 1 and 8")
+	}
+	if only != "" && only != "valid" && only != "invalid" {
+		return fmt.Errorf("invalid --only value: %s (use valid or invalid)", only)
+	}
+
+	var results []uuidValidation
+	for _, arg := range args {
+		results = append(results, validateUUID(arg, strict, version))
+	}
+
+	var r io.Reader
+	if stdinFlag {
+		stat, err := os.Stdin.Stat()
+		if err != nil {
+			return fmt.Errorf("stdin error: %w", err)
+		}
+		if (stat.Mode() & os.ModeCharDevice) != 0 {
+			return fmt.Errorf("no data available from stdin")
+		}
+		r = os.Stdin
+	} else if fileFlag != "" {
+		file, err := os.Open(fileFlag)
+		if err != nil {
+			return fmt.Errorf("read file error: %w", err)
+		}
+		defer file.Close()
+		r = file
+	}
+	if r != nil {
+		scanner := bufio.NewScanner(r)
+		line := 0
+		for scanner.Scan() {
+			line++
+			value := strings.TrimSpace(scanner.Text())
+			if value == "" {
+				continue
+			}
+			v := validateUUID(value, strict, version)
+			v.Line = line
+			results = append(results, v)
+		}
+		if err := scanner.Err(); err != nil {
+			return fmt.Errorf("read error: %w", err)
+		}
+	}
+
+	if len(results) == 0 {
+		if r == nil {
+			return fmt.Errorf("input not specified (provide UUIDs as arguments, --file or --stdin)")
+		}
+		return fmt.Errorf("no UUIDs found in input")
+	}
+
+	invalid := 0
+	for _, v := range results {
+		if !v.Valid {
+			invalid++
+		}
+	}
+
+	switch {
+	case format == output.FormatJSON:
+		output.PrintSuccess(format, map[string]interface{}{
+			"count":   len(results),
+			"valid":   len(results) - invalid,
+			"invalid": invalid,
+			"results": results,
+		})
+	case only != "":
+		for _, v := range results {
+			if v.Valid == (only == "valid") {
+				fmt.Println(v.Input)
+			}
+		}
+	default:
+		width := 0
+		for _, v := range results {
+			width = max(width, len(v.Input))
+		}
+		for _, v := range results {
+			if v.Valid {
+				fmt.Println(output.OK(fmt.Sprintf("%-*s  %s", width, v.Input, output.Muted(uuidValidationDetail(v)))))
+			} else {
+				fmt.Println(output.Fail(fmt.Sprintf("%-*s  %s", width, v.Input, output.Failure(v.Error))))
+			}
+		}
+		if len(results) > 1 {
+			fmt.Printf("\n%d valid, %d invalid\n", len(results)-invalid, invalid)
+		}
+	}
+
+	if invalid > 0 {
+		return check.Result(cmd, check.Invalid, fmt.Sprintf("%d of %d UUIDs are invalid", invalid, len(results)))
+	}
+	return nil
+}
+
+// validateUUID parses value and applies the --strict and --version rules
+func validateUUID(value string, strict bool, version int) uuidValidation {
+	v := uuidValidation{Input: value}
+
+	id, err := uuid.Parse(value)
+	if err != nil {
+		v.Error = err.Error()
+		return v
+	}
+	if strict && (len(value) != 36 || value != strings.ToLower(value) && value != strings.ToUpper(value)) {
+		v.Error = "not in canonical form"
+		return v
+	}
+	v.UUID = id.String()
+	v.Version = int(id.Version())
+	v.Variant = uuidVariantName(id)
+
+	if version != 0 {
+		if id.Variant() != uuid.RFC4122 {
+			v.Error = fmt.Sprintf("variant is %s, not RFC 9562", v.Variant)
+			return v
+		}
+		if v.Version != version {
+			v.Error = fmt.Sprintf("version %d, expected %d", v.Version, version)
+			return v
+		}
+	}
+	v.Valid = true
+	return v
+}
+
+// uuidValidationDetail describes a valid UUID for the plain output
+func uuidValidationDetail(v uuidValidation) string {
+	switch v.UUID {
+	case uuid.Nil.String():
+		return "nil UUID"
+	case uuid.Max.String():
+		return "max UUID"
+	}
+	if v.Variant != "RFC 9562" {
+		return v.Variant + " variant"
+	}
+	name := uuidVersionNames[uuid.Version(v.Version)]
+	if name == "" {
+		return fmt.Sprintf("v%d", v.Version)
+	}
+	return fmt.Sprintf("v%d (%s)", v.Version, name)
+}
@@ -2,6 +2,7 @@ package dev
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/google/uuid"
 	"github.com/spf13/cobra"
@@ -11,18 +12,29 @@ import (
 // uuidCmd represents the uuid command
 var uuidCmd = &cobra.Command{
 	Use:   "uuid",
-	Short: "Generate UUID (v4 or v7)",
+	Short: "Generate UUID (v1, v3, v4, v5 or v7)",
 	Long: `Generate UUID (Universally Unique Identifier) values.
 
 Supported versions:
+  - v1: Time-based UUID (timestamp and node ID)
+  - v3: Name-based UUID (MD5)
   - v4: Random UUID (default)
+  - v5: Name-based UUID (SHA-1)
   - v7: Time-ordered UUID
 
+v3 and v5 UUIDs are deterministic: the same --namespace and --name always
+give the same UUID. The namespace is dns, url, oid, x500 or any UUID; each
+--name gives one UUID.
+
 Examples:
   devkit dev uuid                    # Generate UUID v4
   devkit dev uuid --version 7        # Generate UUID v7
+  devkit dev uuid --version 1        # Generate UUID v1
   devkit dev uuid --count 5          # Generate 5 UUIDs
-  devkit dev uuid --version 7 --count 3 --output json`,
+  devkit dev uuid --version 7 --count 3 --output json
+  devkit dev uuid --version 5 --namespace dns --name example.com
+  devkit dev uuid --version 3 --namespace url --name https://example.com/a --name https://example.com/b
+  devkit dev uuid --version 5 --namespace 6ba7b810-9dad-11d1-80b4-00c04fd430c8 --name users/42`,
 	RunE: runUUID,
 }
 
@@ -30,17 +42,34 @@ func init() {
 	devCmd.AddCommand(uuidCmd)
 
 	// Flag definitions
-	uuidCmd.Flags().Int("version", 4, "UUID version (4 or 7)")
+	uuidCmd.Flags().Int("version", 4, "UUID version (1, 3, 4, 5 or 7)")
 	uuidCmd.Flags().IntP("count", "c", 1, "Number of UUIDs to generate")
+	uuidCmd.Flags().String("namespace", "", "Namespace for v3/v5: dns, url, oid, x500 or a UUID")
+	uuidCmd.Flags().StringArray("name", nil, "Name to hash for v3/v5 (repeatable)")
 	uuidCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json, table")
 }
 
 func runUUID(cmd *cobra.Command, args []string) error {
 	version, _ := cmd.Flags().GetInt("version")
 	count, _ := cmd.Flags().GetInt("count")
+	namespace, _ := cmd.Flags().GetString("namespace")
+	names, _ := cmd.Flags().GetStringArray("name")
 	outputFormat, _ := cmd.Flags().GetString("output")
 	format := output.OutputFormat(outputFormat)
 
+	nameBased := version == 3 || version == 5
+	if nameBased {
+		if namespace == "" || len(names) == 0 {
+			return fmt.Errorf("UUID v%d requires --namespace and --name", version)
+		}
+		if cmd.Flags().Changed("count") {
+			return fmt.Errorf("--count cannot be used with v%d; each --name gives one UUID", version)
+		}
+		count = len(names)
+	} else if namespace != "" || len(names) > 0 {
+		return fmt.Errorf("--namespace and --name are only used with versions 3 and 5")
+	}
+
 	if count < 1 {
 		return fmt.Errorf("count must be at least 1")
 	}
@@ -52,13 +81,22 @@ func runUUID(cmd *cobra.Command, args []string) error {
 	var uuids []string
 	var err error
 
+	var ns uuid.UUID
 	switch version {
+	case 1:
+		uuids, err = generateUUIDv1(count)
+	case 3, 5:
+		ns, err = parseUUIDNamespace(namespace)
+		if err != nil {
+			return err
+		}
+		uuids = generateNameBasedUUIDs(version, ns, names)
 	case 4:
 		uuids, err = generateUUIDv4(count)
 	case 7:
 		uuids, err = generateUUIDv7(count)
 	default:
-		return fmt.Errorf("unsupported UUID version: %d (supported: 4, 7)", version)
+		return fmt.Errorf("unsupported UUID version: %d (supported: 1, 3, 4, 5, 7)", version)
 	}
 
 	if err != nil {
@@ -72,6 +110,10 @@ func runUUID(cmd *cobra.Command, args []string) error {
 			"count":   count,
 			"uuids":   uuids,
 		}
+		if nameBased {
+			result["namespace"] = ns.String()
+			result["names"] = names
+		}
 		output.PrintSuccess(format, result)
 	} else {
 		// Plain format - print each UUID on a new line
@@ -85,6 +127,51 @@ func runUUID(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func generateUUIDv1(count int) ([]string, error) {
+	uuids := make([]string, count)
+	for i := 0; i < count; i++ {
+		id, err := uuid.NewUUID()
+		if err != nil {
+			return nil, err
+		}
+		uuids[i] = id.String()
+	}
+	return uuids, nil
+}
+
+// uuidNamespaces are the namespaces predefined by RFC 9562
+var uuidNamespaces = map[string]uuid.UUID{
+	"dns":  uuid.NameSpaceDNS,
+	"url":  uuid.NameSpaceURL,
+	"oid":  uuid.NameSpaceOID,
+	"x500": uuid.NameSpaceX500,
+}
+
+// parseUUIDNamespace resolves a predefined namespace name or parses a UUID
+func parseUUIDNamespace(s string) (uuid.UUID, error) {
+	if ns, ok := uuidNamespaces[strings.ToLower(s)]; ok {
+		return ns, nil
+	}
+	ns, err := uuid.Parse(s)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("invalid namespace: %s (use dns, url, oid, x500 or a UUID)", s)
+	}
+	return ns, nil
+}
+
+// generateNameBasedUUIDs returns the v3 (MD5) or v5 (SHA-1) UUID of each name
+func generateNameBasedUUIDs(version int, ns uuid.UUID, names []string) []string {
+	uuids := make([]string, len(names))
+	for i, name := range names {
+		if version == 3 {
+			uuids[i] = uuid.NewMD5(ns, []byte(name)).String()
+		} else {
+			uuids[i] = uuid.NewSHA1(ns, []byte(name)).String()
+		}
+	}
+	return uuids
+}
+
 func generateUUIDv4(count int) ([]string, error) {
 	uuids := make([]string, count)
 	for i := 0; i < count; i++ {
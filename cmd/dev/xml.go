@@ -0,0 +1,487 @@
+package dev
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+
+	"github.com/antchfx/xmlquery"
+	"github.com/antchfx/xpath"
+	"github.com/spf13/cobra"
+	"devkit/internal/check"
+	"devkit/internal/output"
+)
+
+// xmlCmd represents the xml command group
+var xmlCmd = &cobra.Command{
+	Use:   "xml",
+	Short: "XML operations (validate, prettify, minify, xpath)",
+	Long: `XML manipulation operations for SOAP envelopes, RSS feeds and other
+XML documents.
+
+Examples:
+  devkit dev xml validate --file feed.xml
+  devkit dev xml prettify --file envelope.xml
+  devkit dev xml minify --file envelope.xml
+  devkit dev xml xpath '//item/title' --file feed.xml`,
+}
+
+// xmlValidateCmd represents the validate subcommand
+var xmlValidateCmd = &cobra.Command{
+	Use:   "validate [xml]",
+	Short: "Validate XML string",
+	Long: `Check if a string or file is well-formed XML. Errors include the line number.
+Exits with status 2 when the input is invalid, or 0 with --fail-on none.
+--vimgrep prints the error as path:line:column: message for editors, and
+nothing for valid input.
+
+Examples:
+  devkit dev xml validate '<a><b>1</b></a>'
+  devkit dev xml validate --file feed.xml
+  curl -s https://example.com/feed | devkit dev xml validate --stdin
+  devkit dev xml validate --file feed.xml --vimgrep`,
+	RunE: runXMLValidate,
+}
+
+// xmlPrettifyCmd represents the prettify subcommand
+var xmlPrettifyCmd = &cobra.Command{
+	Use:   "prettify [xml]",
+	Short: "Prettify XML string",
+	Long: `Format XML with indentation. Namespace prefixes, comments and processing
+instructions are preserved.
+
+Examples:
+  devkit dev xml prettify '<a><b>1</b></a>'
+  devkit dev xml prettify --file envelope.xml --indent 4`,
+	RunE: runXMLPrettify,
+}
+
+// xmlMinifyCmd represents the minify subcommand
+var xmlMinifyCmd = &cobra.Command{
+	Use:   "minify [xml]",
+	Short: "Minify XML string",
+	Long: `Remove insignificant whitespace between XML elements.
+
+Examples:
+  devkit dev xml minify --file envelope.xml
+  devkit dev xml minify --file envelope.xml --strip-comments`,
+	RunE: runXMLMinify,
+}
+
+// xmlXPathCmd represents the xpath subcommand
+var xmlXPathCmd = &cobra.Command{
+	Use:   "xpath [expression]",
+	Short: "Query XML using XPath",
+	Long: `Query XML data using an XPath 1.0 expression. Matching elements are
+printed as XML; attributes and text nodes are printed as text.
+
+Examples:
+  devkit dev xml xpath '//item/title' --file feed.xml --text
+  devkit dev xml xpath '//*[local-name()="Body"]' --file envelope.xml
+  devkit dev xml xpath 'count(//item)' --file feed.xml`,
+	RunE: runXMLXPath,
+}
+
+func init() {
+	devCmd.AddCommand(xmlCmd)
+	xmlCmd.AddCommand(xmlValidateCmd)
+	xmlCmd.AddCommand(xmlPrettifyCmd)
+	xmlCmd.AddCommand(xmlMinifyCmd)
+	xmlCmd.AddCommand(xmlXPathCmd)
+
+	// Flag definitions
+	xmlValidateCmd.Flags().StringP("file", "f", "", "Input file path")
+	xmlValidateCmd.Flags().BoolP("stdin", "s", false, "Read from stdin")
+	xmlValidateCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
+	check.AddFailOnFlag(xmlValidateCmd, check.Invalid)
+	xmlValidateCmd.Flags().Bool("vimgrep", false, "Print errors as path:line:column: message for editors")
+
+	xmlPrettifyCmd.Flags().StringP("file", "f", "", "Input file path")
+	xmlPrettifyCmd.Flags().BoolP("stdin", "s", false, "Read from stdin")
+	xmlPrettifyCmd.Flags().Int("indent", 2, "Number of spaces per indentation level")
+	xmlPrettifyCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
+
+	xmlMinifyCmd.Flags().StringP("file", "f", "", "Input file path")
+	xmlMinifyCmd.Flags().BoolP("stdin", "s", false, "Read from stdin")
+	xmlMinifyCmd.Flags().Bool("strip-comments", false, "Remove comments")
+	xmlMinifyCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
+
+	xmlXPathCmd.Flags().StringP("file", "f", "", "Input file path")
+	xmlXPathCmd.Flags().BoolP("stdin", "s", false, "Read from stdin")
+	xmlXPathCmd.Flags().Bool("text", false, "Print the text content of matching nodes")
+	xmlXPathCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
+}
+
+func runXMLValidate(cmd *cobra.Command, args []string) error {
+	outputFormat, _ := cmd.Flags().GetString("output")
+	format := output.OutputFormat(outputFormat)
+
+	input, err := getJSONInput(cmd, args)
+	if err != nil {
+		return err
+	}
+
+	root, validErr := validateXML(input)
+
+	if vimgrep, _ := cmd.Flags().GetBool("vimgrep"); vimgrep {
+		if validErr != nil {
+			output.PrintLocations([]output.Location{inputLocation(cmd, 0, 0, validErr)})
+			return check.Result(cmd, check.Invalid, "invalid XML")
+		}
+		return nil
+	}
+
+	if format == output.FormatJSON {
+		result := map[string]interface{}{
+			"valid": validErr == nil,
+		}
+		if validErr == nil {
+			result["root"] = root
+		} else {
+			result["error"] = validErr.Error()
+		}
+		output.PrintSuccess(format, result)
+	} else {
+		if validErr == nil {
+			output.PrintSuccess(format, output.OK(fmt.Sprintf("Valid XML (root element <%s>)", root)))
+		} else {
+			output.PrintError(format, fmt.Errorf("%s", output.Fail(fmt.Sprintf("Invalid XML: %v", validErr))))
+		}
+	}
+
+	if validErr != nil {
+		return check.Result(cmd, check.Invalid, "invalid XML")
+	}
+	return nil
+}
+
+func runXMLPrettify(cmd *cobra.Command, args []string) error {
+	indent, _ := cmd.Flags().GetInt("indent")
+	outputFormat, _ := cmd.Flags().GetString("output")
+	format := output.OutputFormat(outputFormat)
+
+	if indent < 1 || indent > 8 {
+		return fmt.Errorf("indent must be between 1 and 8")
+	}
+
+	input, err := getJSONInput(cmd, args)
+	if err != nil {
+		return err
+	}
+
+	result, err := formatXML(input, strings.Repeat(" ", indent), false)
+	if err != nil {
+		return fmt.Errorf("invalid XML: %w", err)
+	}
+
+	if format == output.FormatJSON {
+		output.PrintSuccess(format, map[string]interface{}{
+			"prettified": result,
+		})
+	} else {
+		output.PrintSuccess(format, result)
+	}
+
+	return nil
+}
+
+func runXMLMinify(cmd *cobra.Command, args []string) error {
+	stripComments, _ := cmd.Flags().GetBool("strip-comments")
+	outputFormat, _ := cmd.Flags().GetString("output")
+	format := output.OutputFormat(outputFormat)
+
+	input, err := getJSONInput(cmd, args)
+	if err != nil {
+		return err
+	}
+
+	result, err := formatXML(input, "", stripComments)
+	if err != nil {
+		return fmt.Errorf("invalid XML: %w", err)
+	}
+
+	if format == output.FormatJSON {
+		output.PrintSuccess(format, map[string]interface{}{
+			"minified": result,
+		})
+	} else {
+		output.PrintSuccess(format, result)
+	}
+
+	return nil
+}
+
+func runXMLXPath(cmd *cobra.Command, args []string) error {
+	textOnly, _ := cmd.Flags().GetBool("text")
+	outputFormat, _ := cmd.Flags().GetString("output")
+	format := output.OutputFormat(outputFormat)
+
+	if len(args) == 0 {
+		return fmt.Errorf("XPath expression not specified")
+	}
+
+	expr := args[0]
+	input, err := getJSONInput(cmd, args[1:])
+	if err != nil {
+		return err
+	}
+
+	doc, err := xmlquery.Parse(strings.NewReader(input))
+	if err != nil {
+		return fmt.Errorf("invalid XML: %w", err)
+	}
+
+	compiled, err := xpath.Compile(expr)
+	if err != nil {
+		return fmt.Errorf("invalid XPath expression: %w", err)
+	}
+
+	// Expressions such as count() or string() evaluate to a single value
+	switch value := compiled.Evaluate(xmlquery.CreateXPathNavigator(doc)).(type) {
+	case float64, string, bool:
+		if format == output.FormatJSON {
+			output.PrintSuccess(format, map[string]interface{}{
+				"query":  expr,
+				"result": value,
+			})
+		} else {
+			output.PrintSuccess(format, fmt.Sprint(value))
+		}
+		return nil
+	}
+
+	nodes := xmlquery.QuerySelectorAll(doc, compiled)
+	if len(nodes) == 0 {
+		return fmt.Errorf("no nodes match: %s", expr)
+	}
+
+	results := make([]string, len(nodes))
+	for i, node := range nodes {
+		if textOnly || node.Type == xmlquery.AttributeNode || node.Type == xmlquery.TextNode || node.Type == xmlquery.CharDataNode {
+			results[i] = node.InnerText()
+		} else {
+			results[i] = node.OutputXML(true)
+		}
+	}
+
+	if format == output.FormatJSON {
+		output.PrintSuccess(format, map[string]interface{}{
+			"query":   expr,
+			"results": results,
+			"count":   len(results),
+		})
+	} else {
+		output.PrintSuccess(format, results)
+	}
+
+	return nil
+}
+
+// validateXML checks that input is well-formed and has exactly one root
+// element, returning the root element name
+func validateXML(input string) (string, error) {
+	decoder := xml.NewDecoder(strings.NewReader(input))
+	decoder.Strict = true
+
+	root := ""
+	var stack []string
+	for {
+		tok, err := decoder.RawToken()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if len(stack) == 0 {
+				if root != "" {
+					line, _ := decoder.InputPos()
+					return "", fmt.Errorf("line %d: multiple root elements", line)
+				}
+				root = xmlName(t.Name)
+			}
+			stack = append(stack, xmlName(t.Name))
+		case xml.EndElement:
+			// RawToken does not match end tags, so check nesting here
+			name := xmlName(t.Name)
+			if len(stack) == 0 || stack[len(stack)-1] != name {
+				line, _ := decoder.InputPos()
+				return "", fmt.Errorf("line %d: unexpected end element </%s>", line, name)
+			}
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if len(stack) == 0 && len(bytes.TrimSpace(t)) > 0 {
+				line, _ := decoder.InputPos()
+				return "", fmt.Errorf("line %d: text outside of root element", line)
+			}
+		}
+	}
+
+	if root == "" {
+		return "", fmt.Errorf("no root element")
+	}
+	if len(stack) != 0 {
+		return "", fmt.Errorf("unexpected end of document: <%s> is not closed", stack[len(stack)-1])
+	}
+	return root, nil
+}
+
+// formatXML re-serializes XML token by token. With an empty indent the
+// output is minified; namespace prefixes are kept as written. Text content
+// is kept as is: only the whitespace around text is trimmed when indenting,
+// and CDATA sections are written back as CDATA.
+func formatXML(input, indent string, stripComments bool) (string, error) {
+	if _, err := validateXML(input); err != nil {
+		return "", err
+	}
+
+	decoder := xml.NewDecoder(strings.NewReader(input))
+	var b strings.Builder
+
+	depth := 0
+	pending := ""    // start tag waiting to see whether the element is empty
+	inlined := false // the current element contains text, keep its end tag inline
+	var texts []xmlText
+
+	newline := func(level int) {
+		if indent != "" && b.Len() > 0 {
+			b.WriteString("\n" + strings.Repeat(indent, level))
+		}
+	}
+	flush := func() {
+		if pending != "" {
+			b.WriteString(pending + ">")
+			pending = ""
+		}
+	}
+	// flushText writes the text and CDATA sections read since the last tag.
+	// Whitespace-only text between tags is dropped.
+	flushText := func() {
+		defer func() { texts = texts[:0] }()
+		blank := true
+		for _, t := range texts {
+			if t.cdata || strings.TrimSpace(t.data) != "" {
+				blank = false
+			}
+		}
+		if blank {
+			return
+		}
+		if indent != "" {
+			if first := &texts[0]; !first.cdata {
+				first.data = strings.TrimLeftFunc(first.data, unicode.IsSpace)
+			}
+			if last := &texts[len(texts)-1]; !last.cdata {
+				last.data = strings.TrimRightFunc(last.data, unicode.IsSpace)
+			}
+		}
+		flush()
+		for _, t := range texts {
+			if t.cdata {
+				b.WriteString("<![CDATA[" + t.data + "]]>")
+			} else {
+				xmlTextEscaper.WriteString(&b, t.data)
+			}
+		}
+		inlined = true
+	}
+
+	for {
+		offset := decoder.InputOffset()
+		tok, err := decoder.RawToken()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+
+		if t, ok := tok.(xml.CharData); ok {
+			// CharData does not tell CDATA sections apart, the raw input does
+			texts = append(texts, xmlText{
+				data:  string(t),
+				cdata: strings.HasPrefix(input[offset:], "<![CDATA["),
+			})
+			continue
+		}
+		flushText()
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			flush()
+			newline(depth)
+			var tag strings.Builder
+			tag.WriteString("<" + xmlName(t.Name))
+			for _, attr := range t.Attr {
+				tag.WriteString(" " + xmlName(attr.Name) + `="`)
+				xml.EscapeText(&tag, []byte(attr.Value))
+				tag.WriteString(`"`)
+			}
+			pending = tag.String()
+			inlined = false
+			depth++
+
+		case xml.EndElement:
+			depth--
+			if pending != "" {
+				b.WriteString(pending + "/>")
+				pending = ""
+			} else {
+				if !inlined {
+					newline(depth)
+				}
+				b.WriteString("</" + xmlName(t.Name) + ">")
+			}
+			inlined = false
+
+		case xml.Comment:
+			if stripComments {
+				continue
+			}
+			flush()
+			newline(depth)
+			b.WriteString("<!--" + string(t) + "-->")
+
+		case xml.ProcInst:
+			flush()
+			newline(depth)
+			b.WriteString("<?" + t.Target)
+			if len(t.Inst) > 0 {
+				b.WriteString(" " + string(t.Inst))
+			}
+			b.WriteString("?>")
+
+		case xml.Directive:
+			flush()
+			newline(depth)
+			b.WriteString("<!" + string(t) + ">")
+		}
+	}
+	flushText()
+
+	return b.String(), nil
+}
+
+// xmlText is a run of character data, either text or a CDATA section
+type xmlText struct {
+	data  string
+	cdata bool
+}
+
+// xmlTextEscaper escapes only what text content requires, so that quotes
+// and newlines are written as they were
+var xmlTextEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+func xmlName(name xml.Name) string {
+	if name.Space != "" {
+		return name.Space + ":" + name.Local
+	}
+	return name.Local
+}
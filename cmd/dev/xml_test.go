@@ -0,0 +1,89 @@
+package dev
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/antchfx/xmlquery"
+)
+
+const xmlRoundTripInput = `<?xml version="1.0"?>
+<doc>
+  <poem>Roses are red,
+  violets are "blue" &amp; 'grey'</poem>
+  <code><![CDATA[if (a < b && c > d) {
+  return;
+}]]></code>
+  <mixed>x &lt; y <![CDATA[ <raw> ]]> z</mixed>
+  <empty/>
+</doc>`
+
+func TestFormatXMLKeepsText(t *testing.T) {
+	tests := []struct {
+		name   string
+		indent string
+		want   string
+	}{
+		{
+			name:   "minify",
+			indent: "",
+			want: `<?xml version="1.0"?><doc><poem>Roses are red,
+  violets are "blue" &amp; 'grey'</poem><code><![CDATA[if (a < b && c > d) {
+  return;
+}]]></code><mixed>x &lt; y <![CDATA[ <raw> ]]> z</mixed><empty/></doc>`,
+		},
+		{
+			name:   "prettify",
+			indent: "  ",
+			want: `<?xml version="1.0"?>
+<doc>
+  <poem>Roses are red,
+  violets are "blue" &amp; 'grey'</poem>
+  <code><![CDATA[if (a < b && c > d) {
+  return;
+}]]></code>
+  <mixed>x &lt; y <![CDATA[ <raw> ]]> z</mixed>
+  <empty/>
+</doc>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := formatXML(xmlRoundTripInput, tt.indent, false)
+			if err != nil {
+				t.Fatalf("formatXML: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("formatXML output:\n%s\nwant:\n%s", got, tt.want)
+			}
+
+			again, err := formatXML(got, tt.indent, false)
+			if err != nil {
+				t.Fatalf("formatXML of its own output: %v", err)
+			}
+			if again != got {
+				t.Errorf("formatting is not stable:\n%s\nthen:\n%s", got, again)
+			}
+
+			for _, expr := range []string{"//poem", "//code", "//mixed"} {
+				if want, have := innerXMLText(t, xmlRoundTripInput, expr), innerXMLText(t, got, expr); have != want {
+					t.Errorf("%s text changed: %q, want %q", expr, have, want)
+				}
+			}
+		})
+	}
+}
+
+func innerXMLText(t *testing.T, doc, expr string) string {
+	t.Helper()
+	root, err := xmlquery.Parse(strings.NewReader(doc))
+	if err != nil {
+		t.Fatalf("parse %q: %v", doc, err)
+	}
+	node := xmlquery.FindOne(root, expr)
+	if node == nil {
+		t.Fatalf("%s not found in %q", expr, doc)
+	}
+	return node.InnerText()
+}
@@ -0,0 +1,391 @@
+package dev
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/tidwall/gjson"
+	"gopkg.in/yaml.v3"
+	"devkit/internal/check"
+	"devkit/internal/output"
+)
+
+// yamlCmd represents the yaml command group
+var yamlCmd = &cobra.Command{
+	Use:   "yaml",
+	Short: "YAML operations (validate, prettify, query, convert)",
+	Long: `YAML inspection and querying operations.
+
+Multi-document files (separated by ---) are supported by every subcommand.
+
+Examples:
+  devkit dev yaml validate --file config.yaml
+  devkit dev yaml prettify --file config.yaml
+  devkit dev yaml path '.spec.containers[0].image' --file deploy.yaml
+  devkit dev yaml to-json --file config.yaml`,
+}
+
+// yamlValidateCmd represents the validate subcommand
+var yamlValidateCmd = &cobra.Command{
+	Use:   "validate [yaml]",
+	Short: "Validate YAML string",
+	Long: `Check if a string or file is valid YAML. Errors include the line number.
+Exits with status 2 when the input is invalid, or 0 with --fail-on none.
+--vimgrep prints the error as path:line:column: message for editors, and
+nothing for valid input.
+
+Examples:
+  devkit dev yaml validate 'a: 1'
+  devkit dev yaml validate --file config.yaml
+  cat k8s.yaml | devkit dev yaml validate --stdin
+  devkit dev yaml validate --file config.yaml --vimgrep`,
+	RunE: runYAMLValidate,
+}
+
+// yamlPrettifyCmd represents the prettify subcommand
+var yamlPrettifyCmd = &cobra.Command{
+	Use:   "prettify [yaml]",
+	Short: "Prettify YAML string",
+	Long: `Re-indent YAML consistently. Key order and comments are preserved.
+
+Examples:
+  devkit dev yaml prettify --file config.yaml
+  devkit dev yaml prettify --file config.yaml --indent 4`,
+	RunE: runYAMLPrettify,
+}
+
+// yamlPathCmd represents the path query subcommand
+var yamlPathCmd = &cobra.Command{
+	Use:   "path [query]",
+	Short: "Query YAML using a path expression",
+	Long: `Query YAML data using a yq-style or gjson-style path expression.
+
+yq-style expressions start with a dot:
+  .spec.replicas              nested keys
+  .items[0].name              array index
+  .items[].name               all elements of an array
+
+gjson-style expressions are used as-is (see devkit dev json path):
+  spec.replicas
+  items.0.name
+  items.#.name
+
+Examples:
+  devkit dev yaml path '.spec.containers[0].image' --file deploy.yaml
+  devkit dev yaml path 'services.#.name' --file compose.yaml
+  devkit dev yaml path '.metadata.name' --file all.yaml --doc 1`,
+	RunE: runYAMLPath,
+}
+
+// yamlToJSONCmd represents the to-json subcommand
+var yamlToJSONCmd = &cobra.Command{
+	Use:   "to-json [yaml]",
+	Short: "Convert YAML to JSON",
+	Long: `Convert YAML to JSON. Multi-document input becomes a JSON array,
+or one document per line with --ndjson.
+
+Examples:
+  devkit dev yaml to-json 'a: 1'
+  devkit dev yaml to-json --file config.yaml
+  devkit dev yaml to-json --file all.yaml --ndjson`,
+	RunE: runYAMLToJSON,
+}
+
+var yqIndexPattern = regexp.MustCompile(`\[(\d*)\]`)
+
+func init() {
+	devCmd.AddCommand(yamlCmd)
+	yamlCmd.AddCommand(yamlValidateCmd)
+	yamlCmd.AddCommand(yamlPrettifyCmd)
+	yamlCmd.AddCommand(yamlPathCmd)
+	yamlCmd.AddCommand(yamlToJSONCmd)
+
+	// Flag definitions
+	yamlValidateCmd.Flags().StringP("file", "f", "", "Input file path")
+	yamlValidateCmd.Flags().BoolP("stdin", "s", false, "Read from stdin")
+	yamlValidateCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
+	check.AddFailOnFlag(yamlValidateCmd, check.Invalid)
+	yamlValidateCmd.Flags().Bool("vimgrep", false, "Print errors as path:line:column: message for editors")
+
+	yamlPrettifyCmd.Flags().StringP("file", "f", "", "Input file path")
+	yamlPrettifyCmd.Flags().BoolP("stdin", "s", false, "Read from stdin")
+	yamlPrettifyCmd.Flags().Int("indent", 2, "Number of spaces per indentation level")
+	yamlPrettifyCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
+
+	yamlPathCmd.Flags().StringP("file", "f", "", "Input file path")
+	yamlPathCmd.Flags().BoolP("stdin", "s", false, "Read from stdin")
+	yamlPathCmd.Flags().Int("doc", 0, "Document index for multi-document input")
+	yamlPathCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
+
+	yamlToJSONCmd.Flags().StringP("file", "f", "", "Input file path")
+	yamlToJSONCmd.Flags().BoolP("stdin", "s", false, "Read from stdin")
+	yamlToJSONCmd.Flags().Bool("compact", false, "Minified JSON output")
+	yamlToJSONCmd.Flags().Bool("ndjson", false, "Write one JSON document per line")
+	yamlToJSONCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
+}
+
+func runYAMLValidate(cmd *cobra.Command, args []string) error {
+	outputFormat, _ := cmd.Flags().GetString("output")
+	format := output.OutputFormat(outputFormat)
+
+	input, err := getJSONInput(cmd, args)
+	if err != nil {
+		return err
+	}
+
+	docs, parseErr := decodeYAMLDocuments(input)
+
+	if vimgrep, _ := cmd.Flags().GetBool("vimgrep"); vimgrep {
+		if parseErr != nil {
+			output.PrintLocations([]output.Location{inputLocation(cmd, 0, 0, parseErr)})
+			return check.Result(cmd, check.Invalid, "invalid YAML")
+		}
+		return nil
+	}
+
+	if format == output.FormatJSON {
+		result := map[string]interface{}{
+			"valid":     parseErr == nil,
+			"documents": len(docs),
+		}
+		if parseErr != nil {
+			result["error"] = parseErr.Error()
+		}
+		output.PrintSuccess(format, result)
+	} else {
+		if parseErr == nil {
+			output.PrintSuccess(format, output.OK(fmt.Sprintf("Valid YAML (%d document(s))", len(docs))))
+		} else {
+			output.PrintError(format, fmt.Errorf("%s", output.Fail(fmt.Sprintf("Invalid YAML: %v", parseErr))))
+		}
+	}
+
+	if parseErr != nil {
+		return check.Result(cmd, check.Invalid, "invalid YAML")
+	}
+	return nil
+}
+
+func runYAMLPrettify(cmd *cobra.Command, args []string) error {
+	indent, _ := cmd.Flags().GetInt("indent")
+	outputFormat, _ := cmd.Flags().GetString("output")
+	format := output.OutputFormat(outputFormat)
+
+	if indent < 1 || indent > 8 {
+		return fmt.Errorf("indent must be between 1 and 8")
+	}
+
+	input, err := getJSONInput(cmd, args)
+	if err != nil {
+		return err
+	}
+
+	// Decode into nodes so that comments and key order survive
+	decoder := yaml.NewDecoder(strings.NewReader(input))
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(indent)
+	for {
+		var node yaml.Node
+		if err := decoder.Decode(&node); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return fmt.Errorf("invalid YAML: %w", err)
+		}
+		if err := encoder.Encode(&node); err != nil {
+			return fmt.Errorf("failed to prettify: %w", err)
+		}
+	}
+	encoder.Close()
+
+	result := strings.TrimRight(buf.String(), "\n")
+
+	if format == output.FormatJSON {
+		output.PrintSuccess(format, map[string]interface{}{
+			"prettified": result,
+		})
+	} else {
+		output.PrintSuccess(format, result)
+	}
+
+	return nil
+}
+
+func runYAMLPath(cmd *cobra.Command, args []string) error {
+	docIndex, _ := cmd.Flags().GetInt("doc")
+	outputFormat, _ := cmd.Flags().GetString("output")
+	format := output.OutputFormat(outputFormat)
+
+	if len(args) == 0 {
+		return fmt.Errorf("path query not specified")
+	}
+
+	query := args[0]
+	input, err := getJSONInput(cmd, args[1:])
+	if err != nil {
+		return err
+	}
+
+	docs, err := decodeYAMLDocuments(input)
+	if err != nil {
+		return fmt.Errorf("invalid YAML: %w", err)
+	}
+	if docIndex < 0 || docIndex >= len(docs) {
+		return fmt.Errorf("document index %d out of range (found %d document(s))", docIndex, len(docs))
+	}
+
+	data, err := json.Marshal(docs[docIndex])
+	if err != nil {
+		return fmt.Errorf("failed to convert YAML: %w", err)
+	}
+
+	// Query with gjson, translating yq-style expressions first
+	result := gjson.GetBytes(data, yqToGJSON(query))
+
+	if !result.Exists() {
+		return fmt.Errorf("path not found: %s", query)
+	}
+
+	if format == output.FormatJSON {
+		output.PrintSuccess(format, map[string]interface{}{
+			"query":  query,
+			"result": result.Value(),
+		})
+	} else {
+		if result.IsArray() || result.IsObject() {
+			// Print complex values back as YAML
+			out, _ := yaml.Marshal(result.Value())
+			output.PrintSuccess(format, strings.TrimRight(string(out), "\n"))
+		} else {
+			output.PrintSuccess(format, result.String())
+		}
+	}
+
+	return nil
+}
+
+func runYAMLToJSON(cmd *cobra.Command, args []string) error {
+	compact, _ := cmd.Flags().GetBool("compact")
+	ndjson, _ := cmd.Flags().GetBool("ndjson")
+	outputFormat, _ := cmd.Flags().GetString("output")
+	format := output.OutputFormat(outputFormat)
+
+	input, err := getJSONInput(cmd, args)
+	if err != nil {
+		return err
+	}
+
+	docs, err := decodeYAMLDocuments(input)
+	if err != nil {
+		return fmt.Errorf("invalid YAML: %w", err)
+	}
+
+	if format == output.FormatJSON {
+		output.PrintSuccess(format, map[string]interface{}{
+			"documents": docs,
+			"count":     len(docs),
+		})
+		return nil
+	}
+
+	if ndjson {
+		for _, doc := range docs {
+			line, err := json.Marshal(doc)
+			if err != nil {
+				return fmt.Errorf("failed to encode JSON: %w", err)
+			}
+			fmt.Println(string(line))
+		}
+		return nil
+	}
+
+	var value interface{} = docs
+	if len(docs) == 1 {
+		value = docs[0]
+	}
+
+	var data []byte
+	if compact {
+		data, err = json.Marshal(value)
+	} else {
+		data, err = json.MarshalIndent(value, "", "  ")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to encode JSON: %w", err)
+	}
+	output.PrintSuccess(format, string(data))
+
+	return nil
+}
+
+// decodeYAMLDocuments decodes every document of a (multi-document) YAML
+// stream into JSON-compatible values
+func decodeYAMLDocuments(input string) ([]interface{}, error) {
+	decoder := yaml.NewDecoder(strings.NewReader(input))
+	var docs []interface{}
+	for {
+		var doc interface{}
+		if err := decoder.Decode(&doc); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return docs, err
+		}
+		docs = append(docs, normalizeYAML(doc))
+	}
+	if len(docs) == 0 {
+		docs = append(docs, nil)
+	}
+	return docs, nil
+}
+
+// normalizeYAML converts maps with non-string keys so the value can be
+// encoded as JSON
+func normalizeYAML(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for key, value := range t {
+			t[key] = normalizeYAML(value)
+		}
+		return t
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(t))
+		for key, value := range t {
+			m[fmt.Sprint(key)] = normalizeYAML(value)
+		}
+		return m
+	case []interface{}:
+		for i, value := range t {
+			t[i] = normalizeYAML(value)
+		}
+		return t
+	default:
+		return v
+	}
+}
+
+// yqToGJSON translates a yq-style expression (.a.b[0].c, .items[].name)
+// to gjson syntax (a.b.0.c, items.#.name). Other expressions are returned as-is.
+func yqToGJSON(query string) string {
+	if !strings.HasPrefix(query, ".") {
+		return query
+	}
+	if query == "." {
+		return "@this"
+	}
+	query = yqIndexPattern.ReplaceAllStringFunc(query, func(m string) string {
+		index := strings.Trim(m, "[]")
+		if index == "" {
+			return ".#"
+		}
+		return "." + index
+	})
+	return strings.TrimPrefix(query, ".")
+}
@@ -0,0 +1,345 @@
+package doctor
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/cpu"
+	"github.com/shirou/gopsutil/v3/disk"
+	"github.com/shirou/gopsutil/v3/host"
+	"github.com/shirou/gopsutil/v3/mem"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"devkit/internal/history"
+	"devkit/internal/output"
+	"devkit/pkg/version"
+)
+
+// bundleCmd represents the bundle subcommand
+var bundleCmd = &cobra.Command{
+	Use:   "bundle",
+	Short: "Collect a redacted diagnostics archive for bug reports",
+	Long: `Collect system information, DevKit version, relevant environment variables
+(with secrets masked), configuration, recent command history and network
+diagnostics into a .tar.gz archive that can be attached to an issue.
+
+The archive contains a manifest.json listing exactly what was collected.
+
+Examples:
+  devkit doctor bundle
+  devkit doctor bundle --out report.tar.gz
+  devkit doctor bundle --skip-network`,
+	RunE: runBundle,
+}
+
+// bundleEntry describes one file in the archive
+type bundleEntry struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Size        int    `json:"size"`
+}
+
+// envPrefixes lists the environment variables relevant for troubleshooting
+var envPrefixes = []string{
+	"DEVKIT_", "HTTP_PROXY", "HTTPS_PROXY", "NO_PROXY", "http_proxy", "https_proxy", "no_proxy",
+	"SHELL", "TERM", "LANG", "LC_", "PATH", "GO", "CI",
+}
+
+// secretMarkers flag variable names whose values must never be collected
+var secretMarkers = []string{"TOKEN", "SECRET", "PASSWORD", "PASSWD", "KEY", "CREDENTIAL", "AUTH"}
+
+func init() {
+	doctorCmd.AddCommand(bundleCmd)
+
+	bundleCmd.Flags().String("out", "", "Output archive path (default: devkit-report-<timestamp>.tar.gz)")
+	bundleCmd.Flags().Bool("skip-network", false, "Skip network diagnostics")
+	bundleCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
+}
+
+func runBundle(cmd *cobra.Command, args []string) error {
+	outPath, _ := cmd.Flags().GetString("out")
+	skipNetwork, _ := cmd.Flags().GetBool("skip-network")
+	outputFormat, _ := cmd.Flags().GetString("output")
+	format := output.OutputFormat(outputFormat)
+
+	if outPath == "" {
+		outPath = fmt.Sprintf("devkit-report-%s.tar.gz", time.Now().Format("20060102-150405"))
+	}
+
+	sections := []struct {
+		name        string
+		description string
+		collect     func() interface{}
+	}{
+		{"version.json", "DevKit version, Go runtime and platform", collectVersion},
+		{"sysinfo.json", "CPU, memory, disk and OS information", collectSysinfo},
+		{"env.json", "Relevant environment variables (secrets masked)", collectEnv},
+		{"config.json", "Active configuration file and values (secrets masked)", collectConfig},
+		{"history.json", "Recent devkit commands (names and flags only, no values)", collectHistory},
+	}
+	if !skipNetwork {
+		sections = append(sections, struct {
+			name        string
+			description string
+			collect     func() interface{}
+		}{"network.json", "Interfaces, DNS resolution and connectivity checks", collectNetwork})
+	}
+
+	file, err := os.Create(outPath)
+	if err != nil {
+		return fmt.Errorf("failed to create archive: %w", err)
+	}
+	defer file.Close()
+
+	gz := gzip.NewWriter(file)
+	tw := tar.NewWriter(gz)
+
+	var entries []bundleEntry
+	for _, section := range sections {
+		data, err := json.MarshalIndent(section.collect(), "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode %s: %w", section.name, err)
+		}
+		if err := writeTarFile(tw, section.name, data); err != nil {
+			return err
+		}
+		entries = append(entries, bundleEntry{Name: section.name, Description: section.description, Size: len(data)})
+	}
+
+	manifest := map[string]interface{}{
+		"created_at": time.Now().Format(time.RFC3339),
+		"version":    version.Version,
+		"files":      entries,
+		"redaction":  "values of variables and config keys containing TOKEN, SECRET, PASSWORD, KEY, CREDENTIAL or AUTH are masked; credentials in URLs are removed",
+	}
+	manifestData, _ := json.MarshalIndent(manifest, "", "  ")
+	if err := writeTarFile(tw, "manifest.json", manifestData); err != nil {
+		return err
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to finalize archive: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("failed to finalize archive: %w", err)
+	}
+
+	if format == output.FormatJSON {
+		output.PrintSuccess(format, map[string]interface{}{
+			"archive": outPath,
+			"files":   entries,
+		})
+	} else {
+		fmt.Printf("Diagnostics bundle written to %s\n", outPath)
+		for _, entry := range entries {
+			fmt.Printf("  %-14s %s\n", entry.Name, entry.Description)
+		}
+		fmt.Println("\nPlease review the archive before attaching it to an issue.")
+	}
+
+	return nil
+}
+
+func writeTarFile(tw *tar.Writer, name string, data []byte) error {
+	header := &tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    int64(len(data)),
+		ModTime: time.Now(),
+	}
+	if err := tw.WriteHeader(header); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	if _, err := tw.Write(data); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
+
+func collectVersion() interface{} {
+	return map[string]interface{}{
+		"version":    version.Version,
+		"build_time": version.BuildTime,
+		"go_version": runtime.Version(),
+		"os":         runtime.GOOS,
+		"arch":       runtime.GOARCH,
+	}
+}
+
+func collectSysinfo() interface{} {
+	result := make(map[string]interface{})
+
+	if hostInfo, err := host.Info(); err == nil {
+		result["os"] = map[string]interface{}{
+			"platform":       hostInfo.Platform,
+			"family":         hostInfo.PlatformFamily,
+			"version":        hostInfo.PlatformVersion,
+			"kernel_version": hostInfo.KernelVersion,
+			"virtualization": hostInfo.VirtualizationSystem,
+		}
+	}
+
+	cpuModel := "Unknown"
+	if cpuInfo, err := cpu.Info(); err == nil && len(cpuInfo) > 0 {
+		cpuModel = cpuInfo[0].ModelName
+	}
+	result["cpu"] = map[string]interface{}{
+		"cores": runtime.NumCPU(),
+		"model": cpuModel,
+	}
+
+	if memInfo, err := mem.VirtualMemory(); err == nil {
+		result["memory"] = map[string]interface{}{
+			"total":     memInfo.Total,
+			"available": memInfo.Available,
+			"percent":   memInfo.UsedPercent,
+		}
+	}
+
+	if diskInfo, err := disk.Usage("/"); err == nil {
+		result["disk"] = map[string]interface{}{
+			"total":   diskInfo.Total,
+			"free":    diskInfo.Free,
+			"percent": diskInfo.UsedPercent,
+		}
+	}
+
+	return result
+}
+
+func collectEnv() interface{} {
+	env := make(map[string]string)
+	for _, kv := range os.Environ() {
+		parts := strings.SplitN(kv, "=", 2)
+		if len(parts) != 2 || !isRelevantEnv(parts[0]) {
+			continue
+		}
+		env[parts[0]] = redactValue(parts[0], parts[1])
+	}
+	return env
+}
+
+func collectConfig() interface{} {
+	settings := make(map[string]string)
+	for _, key := range viper.AllKeys() {
+		settings[key] = redactValue(key, fmt.Sprint(viper.Get(key)))
+	}
+	return map[string]interface{}{
+		"config_file": viper.ConfigFileUsed(),
+		"settings":    settings,
+	}
+}
+
+// historyLimit is the number of recent commands included in the bundle
+const historyLimit = 50
+
+func collectHistory() interface{} {
+	entries, err := history.Load(time.Time{})
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}
+	}
+	if len(entries) > historyLimit {
+		entries = entries[len(entries)-historyLimit:]
+	}
+	return map[string]interface{}{
+		"enabled": history.Enabled(),
+		"entries": entries,
+	}
+}
+
+func collectNetwork() interface{} {
+	result := make(map[string]interface{})
+
+	var ifaces []map[string]interface{}
+	if interfaces, err := net.Interfaces(); err == nil {
+		for _, iface := range interfaces {
+			var addrs []string
+			if list, err := iface.Addrs(); err == nil {
+				for _, addr := range list {
+					addrs = append(addrs, addr.String())
+				}
+			}
+			ifaces = append(ifaces, map[string]interface{}{
+				"name":      iface.Name,
+				"flags":     iface.Flags.String(),
+				"mtu":       iface.MTU,
+				"addresses": addrs,
+			})
+		}
+	}
+	result["interfaces"] = ifaces
+
+	var checks []map[string]interface{}
+	for _, name := range []string{"example.com", "proxy.golang.org"} {
+		start := time.Now()
+		ips, err := net.LookupHost(name)
+		check := map[string]interface{}{
+			"type":        "dns",
+			"target":      name,
+			"duration_ms": time.Since(start).Milliseconds(),
+		}
+		if err != nil {
+			check["error"] = err.Error()
+		} else {
+			sort.Strings(ips)
+			check["addresses"] = ips
+		}
+		checks = append(checks, check)
+	}
+
+	for _, address := range []string{"1.1.1.1:443", "example.com:443"} {
+		start := time.Now()
+		conn, err := net.DialTimeout("tcp", address, 3*time.Second)
+		check := map[string]interface{}{
+			"type":        "tcp",
+			"target":      address,
+			"duration_ms": time.Since(start).Milliseconds(),
+		}
+		if err != nil {
+			check["error"] = err.Error()
+		} else {
+			conn.Close()
+		}
+		checks = append(checks, check)
+	}
+	result["checks"] = checks
+
+	return result
+}
+
+func isRelevantEnv(name string) bool {
+	for _, prefix := range envPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactValue masks secret values and strips credentials from URLs
+func redactValue(name, value string) string {
+	upper := strings.ToUpper(name)
+	for _, marker := range secretMarkers {
+		if strings.Contains(upper, marker) {
+			if value == "" {
+				return ""
+			}
+			return "****"
+		}
+	}
+
+	if u, err := url.Parse(value); err == nil && u.User != nil && u.Host != "" {
+		u.User = url.User("redacted")
+		return u.String()
+	}
+	return value
+}
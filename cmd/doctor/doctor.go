@@ -0,0 +1,25 @@
+package doctor
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// doctorCmd represents the doctor command group
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Diagnostics for troubleshooting and bug reports",
+	Long: `Diagnostics for troubleshooting DevKit and preparing bug reports.
+
+This command group includes utilities for:
+- Collecting a redacted environment bundle to attach to issues`,
+}
+
+// GetDoctorCmd returns the doctor command
+func GetDoctorCmd() *cobra.Command {
+	return doctorCmd
+}
+
+func init() {
+	// This will be called when the package is imported
+	// Commands will be added in their respective files
+}
@@ -0,0 +1,431 @@
+package file
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/spf13/cobra"
+	"devkit/internal/check"
+	"devkit/internal/matcher"
+	"devkit/internal/output"
+)
+
+// Audit severities, from most to least severe
+const (
+	auditHigh   = "high"
+	auditMedium = "medium"
+	auditLow    = "low"
+)
+
+// auditRatioMinSize is the uncompressed size below which compression
+// ratios are not reported: small runs of zeros compress extremely well
+// without being a danger
+const auditRatioMinSize = 1 << 20
+
+// auditNestedExtensions are archives inside the archive, which the audit
+// does not open
+var auditNestedExtensions = []string{".zip", ".jar", ".war", ".tar", ".gz", ".tgz", ".bz2", ".xz", ".zst", ".7z", ".rar"}
+
+var windowsDrive = regexp.MustCompile(`^[A-Za-z]:`)
+
+// archiveAuditCmd represents the audit subcommand
+var archiveAuditCmd = &cobra.Command{
+	Use:   "audit [archive]",
+	Short: "Check an archive for path traversal, symlink escapes and zip bombs",
+	Long: `Inspect a zip, tar or tar.gz archive without extracting it and report
+entries that would be unsafe to unpack, e.g. from a user upload:
+
+  high    path traversal (../), absolute paths and drive letters, symlinks
+          and hard links pointing outside the target directory, entries
+          written through a symlink, overlapping zip entries, compression
+          ratios above --max-ratio and totals above --max-size
+  medium  more entries than --max-entries, duplicate names, device files
+          and FIFOs, setuid and setgid bits
+  low     control characters in names and nested archives, which are not
+          audited
+
+The risk is the most severe finding. The command exits with status 2 when
+the archive has high-risk findings; --fail-on warning also fails on medium
+ones, --fail-on none never fails.
+
+Examples:
+  devkit file archive audit upload.zip
+  devkit file archive audit release.tar.gz --max-size 500M
+  devkit file archive audit suspicious.zip --max-ratio 50 --fail-on warning
+  devkit file archive audit backup.tgz --output json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runArchiveAudit,
+}
+
+func init() {
+	archiveCmd.AddCommand(archiveAuditCmd)
+
+	archiveAuditCmd.Flags().Float64("max-ratio", 100, "Highest acceptable compression ratio (uncompressed:compressed)")
+	archiveAuditCmd.Flags().String("max-size", "1G", "Highest acceptable total uncompressed size")
+	archiveAuditCmd.Flags().Int("max-entries", 10000, "Highest acceptable number of entries")
+	archiveAuditCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
+	check.AddFailOnFlag(archiveAuditCmd, check.Invalid)
+}
+
+// auditEntry is one entry of an archive as the audit sees it
+type auditEntry struct {
+	name       string
+	kind       string // file, dir, symlink, hardlink or special
+	size       int64
+	compressed int64 // zip only; 0 when unknown
+	offset     int64 // zip only; start of the compressed data
+	link       string
+	mode       os.FileMode
+}
+
+// auditFinding is one problem found in an archive
+type auditFinding struct {
+	Entry    string `json:"entry,omitempty"`
+	Check    string `json:"check"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+func runArchiveAudit(cmd *cobra.Command, args []string) error {
+	archivePath := args[0]
+	maxRatio, _ := cmd.Flags().GetFloat64("max-ratio")
+	maxSizeValue, _ := cmd.Flags().GetString("max-size")
+	maxEntries, _ := cmd.Flags().GetInt("max-entries")
+	outputFormat, _ := cmd.Flags().GetString("output")
+	format := output.OutputFormat(outputFormat)
+
+	if maxRatio <= 1 {
+		return fmt.Errorf("--max-ratio must be greater than 1")
+	}
+	maxSize, err := matcher.ParseSize(maxSizeValue)
+	if err != nil {
+		return err
+	}
+	if maxEntries < 1 {
+		return fmt.Errorf("--max-entries must be at least 1")
+	}
+
+	info, err := os.Stat(archivePath)
+	if err != nil {
+		return fmt.Errorf("failed to get file info: %w", err)
+	}
+	if info.IsDir() {
+		return fmt.Errorf("%s is a directory", archivePath)
+	}
+
+	archiveType, entries, err := readAuditEntries(archivePath)
+	if err != nil {
+		return err
+	}
+
+	findings := auditArchive(entries, maxRatio, maxSize, maxEntries)
+
+	var total int64
+	counts := map[string]int{}
+	for _, e := range entries {
+		total += e.size
+		counts[e.kind]++
+	}
+	// The overall ratio also catches tar.gz bombs, whose entries have no
+	// compressed size of their own
+	ratio := 0.0
+	if info.Size() > 0 {
+		ratio = float64(total) / float64(info.Size())
+	}
+	if ratio > maxRatio && total >= auditRatioMinSize {
+		findings = append(findings, auditFinding{
+			Check:    "compression-ratio",
+			Severity: auditHigh,
+			Message:  fmt.Sprintf("archive expands %.0f times (%s to %s), above --max-ratio %.0f", ratio, formatSize(info.Size()), formatSize(total), maxRatio),
+		})
+	}
+	if total > maxSize {
+		findings = append(findings, auditFinding{
+			Check:    "total-size",
+			Severity: auditHigh,
+			Message:  fmt.Sprintf("archive expands to %s, above --max-size %s", formatSize(total), formatSize(maxSize)),
+		})
+	}
+
+	summary := map[string]int{auditHigh: 0, auditMedium: 0, auditLow: 0}
+	for _, f := range findings {
+		summary[f.Severity]++
+	}
+	risk := "none"
+	level := check.None
+	switch {
+	case summary[auditHigh] > 0:
+		risk, level = auditHigh, check.Invalid
+	case summary[auditMedium] > 0:
+		risk, level = auditMedium, check.Warning
+	case summary[auditLow] > 0:
+		risk = auditLow
+	}
+	message := fmt.Sprintf("archive audit found %s-risk entries", risk)
+
+	if format == output.FormatJSON {
+		if findings == nil {
+			findings = []auditFinding{}
+		}
+		output.PrintSuccess(format, map[string]interface{}{
+			"path":              archivePath,
+			"archive":           archiveType,
+			"entries":           len(entries),
+			"files":             counts["file"],
+			"dirs":              counts["dir"],
+			"links":             counts["symlink"] + counts["hardlink"],
+			"compressed_size":   info.Size(),
+			"uncompressed_size": total,
+			"ratio":             float64(int(ratio*10)) / 10,
+			"risk":              risk,
+			"summary":           summary,
+			"findings":          findings,
+		})
+		return check.Result(cmd, level, message)
+	}
+
+	fmt.Printf("%s %s\n\n", output.Accent(archivePath), output.Muted(fmt.Sprintf("(%s, %d entries, %s → %s, %.1f:1)",
+		archiveType, len(entries), formatSize(info.Size()), formatSize(total), ratio)))
+	if len(findings) == 0 {
+		fmt.Println(output.OK("No issues found"))
+		return nil
+	}
+
+	for _, f := range findings {
+		severity := fmt.Sprintf("%-6s", strings.ToUpper(f.Severity))
+		switch f.Severity {
+		case auditHigh:
+			severity = output.Failure(severity)
+		case auditMedium:
+			severity = output.Warning(severity)
+		default:
+			severity = output.Muted(severity)
+		}
+		if f.Entry != "" {
+			fmt.Printf("  %s  %s\n          %s\n", severity, f.Entry, output.Muted(f.Message))
+		} else {
+			fmt.Printf("  %s  %s\n", severity, f.Message)
+		}
+	}
+	fmt.Printf("\nRisk: %s (%d high, %d medium, %d low)\n", risk, summary[auditHigh], summary[auditMedium], summary[auditLow])
+	return check.Result(cmd, level, message)
+}
+
+// readAuditEntries lists a zip or (gzip-compressed) tar archive
+func readAuditEntries(archivePath string) (string, []auditEntry, error) {
+	if reader, err := zip.OpenReader(archivePath); err == nil {
+		defer reader.Close()
+		var entries []auditEntry
+		for _, f := range reader.File {
+			e := auditEntry{
+				name:       f.Name,
+				kind:       "file",
+				size:       int64(f.UncompressedSize64),
+				compressed: int64(f.CompressedSize64),
+				mode:       f.Mode(),
+			}
+			if offset, err := f.DataOffset(); err == nil {
+				e.offset = offset
+			}
+			switch {
+			case f.Mode()&os.ModeSymlink != 0:
+				// The target of a zip symlink is its content
+				e.kind = "symlink"
+				if rc, err := f.Open(); err == nil {
+					target, _ := io.ReadAll(io.LimitReader(rc, 4096))
+					rc.Close()
+					e.link = string(target)
+				}
+			case f.FileInfo().IsDir():
+				e.kind = "dir"
+			case f.Mode()&(os.ModeDevice|os.ModeNamedPipe|os.ModeSocket) != 0:
+				e.kind = "special"
+			}
+			entries = append(entries, e)
+		}
+		return "zip", entries, nil
+	}
+
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	defer file.Close()
+
+	archiveType := "tar"
+	var r io.Reader = bufio.NewReader(file)
+	if gz, err := gzip.NewReader(r); err == nil {
+		defer gz.Close()
+		archiveType = "tar.gz"
+		r = gz
+	} else if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return "", nil, fmt.Errorf("failed to read file: %w", err)
+	} else {
+		r = file
+	}
+
+	var entries []auditEntry
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			if len(entries) == 0 {
+				return "", nil, fmt.Errorf("not a zip or tar archive: %w", err)
+			}
+			return "", nil, fmt.Errorf("corrupt %s archive after %d entries: %w", archiveType, len(entries), err)
+		}
+		e := auditEntry{
+			name: header.Name,
+			kind: "file",
+			size: header.Size,
+			link: header.Linkname,
+			mode: header.FileInfo().Mode(),
+		}
+		switch header.Typeflag {
+		case tar.TypeDir:
+			e.kind = "dir"
+		case tar.TypeSymlink:
+			e.kind = "symlink"
+		case tar.TypeLink:
+			e.kind = "hardlink"
+			e.size = 0
+		case tar.TypeChar, tar.TypeBlock, tar.TypeFifo:
+			e.kind = "special"
+		}
+		entries = append(entries, e)
+	}
+	return archiveType, entries, nil
+}
+
+// auditArchive checks the entries one by one and against each other
+func auditArchive(entries []auditEntry, maxRatio float64, maxSize int64, maxEntries int) []auditFinding {
+	var findings []auditFinding
+	add := func(entry, check, severity, format string, args ...interface{}) {
+		findings = append(findings, auditFinding{Entry: entry, Check: check, Severity: severity, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if len(entries) > maxEntries {
+		add("", "entry-count", auditMedium, "%d entries, above --max-entries %d", len(entries), maxEntries)
+	}
+
+	seen := make(map[string]bool)
+	symlinks := make(map[string]bool)
+	for _, e := range entries {
+		// Zip files written on Windows may use backslashes
+		name := strings.ReplaceAll(e.name, "\\", "/")
+		clean := path.Clean(name)
+
+		switch {
+		case strings.HasPrefix(name, "/") || windowsDrive.MatchString(name):
+			add(e.name, "absolute-path", auditHigh, "absolute path: would be extracted outside the target directory")
+		case auditEscapes(clean):
+			add(e.name, "path-traversal", auditHigh, "path traversal: would be extracted to %s outside the target directory", clean)
+		}
+
+		for dir := path.Dir(clean); dir != "." && dir != "/"; dir = path.Dir(dir) {
+			if symlinks[dir] {
+				add(e.name, "symlink-write", auditHigh, "written through the symlink %s, which can point anywhere", dir)
+				break
+			}
+		}
+
+		switch e.kind {
+		case "symlink":
+			symlinks[clean] = true
+			target := strings.ReplaceAll(e.link, "\\", "/")
+			if strings.HasPrefix(target, "/") || windowsDrive.MatchString(target) {
+				add(e.name, "symlink-escape", auditHigh, "symlink to the absolute path %s", e.link)
+			} else if auditEscapes(path.Join(path.Dir(clean), target)) {
+				add(e.name, "symlink-escape", auditHigh, "symlink to %s points outside the target directory", e.link)
+			}
+		case "hardlink":
+			target := path.Clean(strings.ReplaceAll(e.link, "\\", "/"))
+			if strings.HasPrefix(target, "/") || auditEscapes(target) {
+				add(e.name, "hardlink-escape", auditHigh, "hard link to %s outside the target directory", e.link)
+			}
+		case "special":
+			add(e.name, "special-file", auditMedium, "device file or FIFO (%s)", e.mode.Type())
+		}
+
+		if e.mode&(os.ModeSetuid|os.ModeSetgid) != 0 {
+			add(e.name, "setuid", auditMedium, "setuid or setgid bit set (%s)", e.mode)
+		}
+		if e.kind == "file" && e.compressed > 0 && e.size >= auditRatioMinSize {
+			if ratio := float64(e.size) / float64(e.compressed); ratio > maxRatio {
+				add(e.name, "compression-ratio", auditHigh, "expands %.0f times (%s to %s), above --max-ratio %.0f", ratio, formatSize(e.compressed), formatSize(e.size), maxRatio)
+			}
+		}
+		if e.kind == "file" && e.size > maxSize {
+			add(e.name, "entry-size", auditHigh, "expands to %s, above --max-size %s", formatSize(e.size), formatSize(maxSize))
+		}
+
+		key := strings.TrimSuffix(clean, "/")
+		if seen[key] && e.kind != "dir" {
+			add(e.name, "duplicate", auditMedium, "duplicate entry: overwrites an earlier entry of the same name")
+		}
+		seen[key] = true
+
+		if strings.IndexFunc(e.name, unicode.IsControl) >= 0 {
+			add(fmt.Sprintf("%q", e.name), "control-characters", auditLow, "name contains control characters")
+		}
+		if e.kind == "file" {
+			lower := strings.ToLower(name)
+			for _, ext := range auditNestedExtensions {
+				if strings.HasSuffix(lower, ext) {
+					add(e.name, "nested-archive", auditLow, "nested archive: audit it separately before extracting it")
+					break
+				}
+			}
+		}
+	}
+
+	findings = append(findings, auditOverlaps(entries)...)
+	return findings
+}
+
+// auditEscapes reports whether a cleaned relative path leaves the target
+// directory
+func auditEscapes(clean string) bool {
+	return clean == ".." || strings.HasPrefix(clean, "../")
+}
+
+// auditOverlaps finds zip entries that share compressed data, the trick
+// behind non-recursive zip bombs that expand far beyond their size
+func auditOverlaps(entries []auditEntry) []auditFinding {
+	var files []auditEntry
+	for _, e := range entries {
+		if e.offset > 0 && e.compressed > 0 {
+			files = append(files, e)
+		}
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].offset < files[j].offset
+	})
+
+	var findings []auditFinding
+	for i := 1; i < len(files); i++ {
+		prev := files[i-1]
+		if files[i].offset < prev.offset+prev.compressed {
+			findings = append(findings, auditFinding{
+				Entry:    files[i].name,
+				Check:    "overlapping-entries",
+				Severity: auditHigh,
+				Message:  fmt.Sprintf("shares compressed data with %s (zip bomb)", prev.name),
+			})
+		}
+	}
+	return findings
+}
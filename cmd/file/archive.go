@@ -0,0 +1,20 @@
+package file
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// archiveCmd represents the archive command group
+var archiveCmd = &cobra.Command{
+	Use:   "archive",
+	Short: "Archive operations (audit)",
+	Long: `Operations on zip and tar archives.
+
+Examples:
+  devkit file archive audit upload.zip
+  devkit file archive audit release.tar.gz --output json`,
+}
+
+func init() {
+	fileCmd.AddCommand(archiveCmd)
+}
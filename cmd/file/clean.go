@@ -0,0 +1,478 @@
+package file
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"devkit/internal/i18n"
+	"devkit/internal/matcher"
+	"devkit/internal/output"
+	"devkit/internal/picker"
+	"devkit/internal/safety"
+	"devkit/internal/workspace"
+)
+
+// cleanCmd represents the clean command
+var cleanCmd = &cobra.Command{
+	Use:   "clean [path...]",
+	Short: "Delete generated files such as build outputs and caches",
+	Long: `Delete the files and directories a build leaves behind: dependency
+directories, caches, build outputs and logs.
+
+What to delete is given by --artifacts, which names toolchains (node, go,
+python, build-artifacts) whose caches and intermediate files are always
+safe to regenerate, and by --pattern. --artifacts lists less than
+--ignore-profile: vendored code, virtual environments and directories
+such as bin/ or dist/, which projects often commit, are left to --pattern.
+Patterns follow .gitignore rules: a trailing / matches directories only, a
+leading / anchors a pattern to the path, and ! keeps what an earlier
+pattern selected.
+
+Matching directories are deleted as a whole, unless they contain a path
+that is kept; then only the rest of their contents is deleted. Paths
+matching --exclude or --ignore-profile are kept, as are the files git
+tracks, which are listed instead of deleted. .git, .hg and .svn are never
+entered. The size and modification time filters select files; with them,
+matching directories are emptied of the files that pass instead of being
+deleted.
+
+Cleaned files are not recorded for undo, as they can be generated again.
+Review them with --dry-run, or pick them with --interactive, which
+replaces the confirmation prompt.
+
+Examples:
+  devkit file clean --artifacts node --dry-run
+  devkit file clean ./services --artifacts python,build-artifacts --yes
+  devkit file clean --pattern "*.log" --pattern "tmp/" --exclude "logs/keep.log"
+  devkit file clean --artifacts go --exclude "*.prof" --interactive
+  devkit file clean --pattern "dist/" --modified-before 30d
+  devkit file clean --artifacts node --output json --dry-run`,
+	RunE: runClean,
+}
+
+func init() {
+	fileCmd.AddCommand(cleanCmd)
+
+	cleanCmd.Flags().StringSlice("artifacts", nil, "Delete the caches and intermediate files of a toolchain: "+strings.Join(cleanArtifactNames(), ", ")+" (comma-separated or repeated)")
+	cleanCmd.Flags().StringArray("pattern", nil, "Delete files and directories matching a .gitignore pattern (repeatable)")
+	cleanCmd.Flags().StringArray("exclude", nil, "Keep files and directories matching a .gitignore pattern (repeatable)")
+	addIgnoreProfileFlag(cleanCmd)
+	cleanCmd.Flags().BoolP("follow-symlinks", "L", false, "Follow symbolic links (loops are detected and skipped)")
+	addFileFilterFlags(cleanCmd)
+	cleanCmd.Flags().BoolP("dry-run", "d", false, "Show what would be deleted without making changes")
+	cleanCmd.Flags().BoolP("interactive", "i", false, "Choose which of the files and directories to delete")
+	cleanCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
+}
+
+// cleanVCSDirs are never entered or deleted
+var cleanVCSDirs = map[string]bool{".git": true, ".hg": true, ".svn": true}
+
+// cleanArtifacts are the patterns --artifacts deletes. Unlike the ignore
+// profiles, they only name caches and intermediate files that are never
+// source, so that cleaning cannot take committed code with it.
+var cleanArtifacts = map[string][]string{
+	"node": {
+		"node_modules/",
+		".next/",
+		".nuxt/",
+		".svelte-kit/",
+		".parcel-cache/",
+		".turbo/",
+		"npm-debug.log*",
+		"yarn-debug.log*",
+		"yarn-error.log*",
+		"pnpm-debug.log*",
+	},
+	"go": {
+		"*.test",
+		"*.prof",
+	},
+	"python": {
+		"__pycache__/",
+		"*.py[co]",
+		".mypy_cache/",
+		".pytest_cache/",
+		".ruff_cache/",
+		".tox/",
+		".nox/",
+		"*.egg-info/",
+		".eggs/",
+		"htmlcov/",
+		".coverage",
+	},
+	"build-artifacts": {
+		"*.o",
+		"*.obj",
+		"*.class",
+	},
+}
+
+// cleanArtifactNames returns the names --artifacts accepts, sorted
+func cleanArtifactNames() []string {
+	names := make([]string, 0, len(cleanArtifacts))
+	for name := range cleanArtifacts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// cleanEntry is a file or directory selected for deletion
+type cleanEntry struct {
+	Path string `json:"path"`
+	Dir  bool   `json:"dir"`
+	Size int64  `json:"size"`
+}
+
+func runClean(cmd *cobra.Command, args []string) error {
+	artifacts, _ := cmd.Flags().GetStringSlice("artifacts")
+	patterns, _ := cmd.Flags().GetStringArray("pattern")
+	exclude, _ := cmd.Flags().GetStringArray("exclude")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	interactive, _ := cmd.Flags().GetBool("interactive")
+	outputFormat, _ := cmd.Flags().GetString("output")
+	format := output.OutputFormat(outputFormat)
+
+	if len(artifacts) == 0 && len(patterns) == 0 {
+		return fmt.Errorf("nothing to clean: give --artifacts or --pattern")
+	}
+	for _, name := range artifacts {
+		list, ok := cleanArtifacts[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			return fmt.Errorf("unknown artifacts: %s (use %s)", name, strings.Join(cleanArtifactNames(), ", "))
+		}
+		patterns = append(patterns, list...)
+	}
+	if interactive && dryRun {
+		return fmt.Errorf("--interactive and --dry-run cannot be used together")
+	}
+	if interactive && !picker.Available() {
+		return fmt.Errorf("--interactive needs a terminal")
+	}
+
+	// Both sets of rules are exclude rules of a matcher, so that they get
+	// the .gitignore semantics of --exclude everywhere else. The workspace
+	// ignore patterns are not applied: they usually name the very files
+	// clean deletes.
+	targets, err := matcher.New(matcher.Options{Exclude: patterns})
+	if err != nil {
+		return err
+	}
+	keepOpts := matcher.Options{Exclude: exclude}
+	keepOpts.IgnoreProfiles, _ = cmd.Flags().GetStringSlice("ignore-profile")
+	keepOpts.FollowSymlinks, _ = cmd.Flags().GetBool("follow-symlinks")
+	if err := readFileFilterFlags(cmd, &keepOpts); err != nil {
+		return err
+	}
+	keep, err := matcher.New(keepOpts)
+	if err != nil {
+		return err
+	}
+
+	if err := safety.CheckDryRun(cmd, args, dryRun); err != nil {
+		return err
+	}
+
+	paths := args
+	if len(paths) == 0 {
+		paths = []string{"."}
+		if ws := workspace.Current(); ws != nil && ws.Path != "" {
+			paths = []string{ws.Resolve(ws.Path)}
+		}
+	}
+	roots, err := matcher.ExpandPaths(paths)
+	if err != nil {
+		return err
+	}
+
+	entries := []cleanEntry{}
+	tracked := []string{}
+	var total int64
+	for _, root := range roots {
+		c := &cleaner{targets: targets, keep: keep, tracked: gitTrackedPaths(root), filtered: cleanFiltered(keepOpts), follow: keepOpts.FollowSymlinks}
+		if err := c.find(root); err != nil {
+			return err
+		}
+		for _, entry := range c.entries {
+			total += entry.Size
+		}
+		entries = append(entries, c.entries...)
+		tracked = append(tracked, c.kept...)
+	}
+
+	cmd.SilenceUsage = true
+	toDelete := entries
+	skipped := 0
+	if interactive && len(toDelete) > 0 {
+		labels := make([]string, len(toDelete))
+		for i, entry := range toDelete {
+			labels[i] = fmt.Sprintf("%s (%s)", cleanLabel(entry), formatSize(entry.Size))
+		}
+		selected, err := picker.Checklist(i18n.T("picker.cleans"), labels)
+		if err != nil {
+			return err
+		}
+		chosen := make([]cleanEntry, 0, len(selected))
+		for _, i := range selected {
+			chosen = append(chosen, toDelete[i])
+		}
+		skipped = len(toDelete) - len(chosen)
+		toDelete = chosen
+	}
+
+	deleted := []string{}
+	failed := make(map[string]string)
+	var freed int64
+	if len(toDelete) > 0 && !dryRun {
+		if !interactive {
+			if err := safety.ConfirmChanges(safety.DeletePaths, len(toDelete)); err != nil {
+				return err
+			}
+		}
+		for _, entry := range toDelete {
+			if err := os.RemoveAll(entry.Path); err != nil {
+				failed[entry.Path] = err.Error()
+				continue
+			}
+			deleted = append(deleted, entry.Path)
+			freed += entry.Size
+		}
+	}
+
+	if format == output.FormatJSON {
+		result := map[string]interface{}{
+			"path":    roots[0],
+			"paths":   roots,
+			"entries": entries,
+			"count":   len(entries),
+			"size":    total,
+			"tracked": tracked,
+			"dry_run": dryRun,
+		}
+		if !dryRun {
+			result["deleted"] = deleted
+			result["freed"] = freed
+			result["skipped"] = skipped
+			if len(failed) > 0 {
+				result["failed"] = failed
+			}
+		}
+		output.PrintSuccess(format, result)
+		return nil
+	}
+
+	for _, path := range tracked {
+		fmt.Printf("  Kept (tracked by git): %s\n", path)
+	}
+	if len(entries) == 0 {
+		if len(tracked) > 0 {
+			fmt.Println()
+		}
+		fmt.Println("Nothing to clean")
+		return nil
+	}
+	if dryRun {
+		fmt.Println("DRY RUN - Would delete:")
+	}
+	for _, entry := range toDelete {
+		label := fmt.Sprintf("%s (%s)", cleanLabel(entry), formatSize(entry.Size))
+		if dryRun {
+			fmt.Printf("  Would delete: %s\n", label)
+		} else if reason, ok := failed[entry.Path]; ok {
+			fmt.Printf("  Failed to delete %s: %s\n", cleanLabel(entry), reason)
+		} else {
+			fmt.Printf("  Deleted: %s\n", label)
+		}
+	}
+	if skipped > 0 {
+		fmt.Printf("\nKept %d entries that were not selected\n", skipped)
+	}
+	if dryRun {
+		fmt.Printf("\nFound %d entries to clean (%s)\n", len(entries), formatSize(total))
+	} else {
+		fmt.Printf("\nDeleted %d entries, freed %s\n", len(deleted), formatSize(freed))
+	}
+
+	return nil
+}
+
+// cleaner collects the entries to delete under one root
+type cleaner struct {
+	targets  *matcher.Matcher
+	keep     *matcher.Matcher
+	tracked  map[string]bool // absolute paths of the files git tracks and their directories
+	filtered bool            // size or time filters select single files
+	follow   bool
+	entries  []cleanEntry
+	kept     []string        // matching paths left alone because git tracks them
+	seen     map[string]bool // directories entered, by resolved path
+}
+
+// cleanFiltered reports whether the size or modification time filters are
+// set, which select files rather than whole directories
+func cleanFiltered(opts matcher.Options) bool {
+	return opts.MinSize > 0 || opts.MaxSize > 0 || !opts.ModifiedSince.IsZero() || !opts.ModifiedBefore.IsZero()
+}
+
+// find collects the files and directories under root that match the
+// targets and are not kept
+func (c *cleaner) find(root string) error {
+	info, err := os.Stat(root)
+	if err != nil {
+		return fmt.Errorf("cannot access %s: %w", root, err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", root)
+	}
+	c.seen = make(map[string]bool)
+	c.enter(root)
+	c.walk(root, "", false)
+	return nil
+}
+
+// enter reports whether a directory has not been entered yet
+func (c *cleaner) enter(dir string) bool {
+	real, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return false
+	}
+	if c.seen[real] {
+		return false
+	}
+	c.seen[real] = true
+	return true
+}
+
+// walk visits the entries of dir. Inside a matching directory (matched)
+// every entry is deleted unless it is kept.
+func (c *cleaner) walk(dir, relDir string, matched bool) {
+	children, err := os.ReadDir(dir)
+	if err != nil {
+		// Unreadable directories are left alone
+		return
+	}
+	for _, child := range children {
+		path := filepath.Join(dir, child.Name())
+		rel := child.Name()
+		if relDir != "" {
+			rel = relDir + "/" + rel
+		}
+
+		info, err := child.Info()
+		if err != nil {
+			continue
+		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			if !c.follow {
+				continue
+			}
+			// Broken links are skipped
+			if info, err = os.Stat(path); err != nil {
+				continue
+			}
+		}
+		isDir := info.IsDir()
+		if isDir && cleanVCSDirs[child.Name()] || c.keep.Excluded(rel, isDir) {
+			continue
+		}
+
+		target := matched || c.targets.Excluded(rel, isDir)
+		switch {
+		case isDir:
+			// A matching directory is deleted whole when nothing in it is
+			// kept; otherwise its other contents are deleted one by one
+			if target && !c.filtered && child.Type()&os.ModeSymlink == 0 && !c.keeps(path, rel) {
+				c.entries = append(c.entries, cleanEntry{Path: path, Dir: true, Size: cleanSize(path)})
+				continue
+			}
+			if c.enter(path) {
+				c.walk(path, rel, target)
+			}
+		case !target || !info.Mode().IsRegular() && child.Type()&os.ModeSymlink == 0:
+		case c.isTracked(path):
+			c.kept = append(c.kept, path)
+		case c.keep.MatchInfo(info):
+			c.entries = append(c.entries, cleanEntry{Path: path, Size: info.Size()})
+		}
+	}
+}
+
+// keeps reports whether the directory dir contains a path that is excluded
+// or tracked by git
+func (c *cleaner) keeps(dir, relDir string) bool {
+	if c.isTracked(dir) {
+		return true
+	}
+	found := false
+	filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil || path == dir {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return nil
+		}
+		if c.keep.Excluded(relDir+"/"+filepath.ToSlash(rel), d.IsDir()) {
+			found = true
+			return filepath.SkipAll
+		}
+		return nil
+	})
+	return found
+}
+
+func (c *cleaner) isTracked(path string) bool {
+	abs, err := filepath.Abs(path)
+	return err == nil && c.tracked[abs]
+}
+
+// gitTrackedPaths returns the absolute paths of the files git tracks under
+// root and of the directories that contain them. It is empty outside a git
+// work tree or without git.
+func gitTrackedPaths(root string) map[string]bool {
+	tracked := make(map[string]bool)
+	abs, err := filepath.Abs(root)
+	if err != nil {
+		return tracked
+	}
+	out, err := exec.Command("git", "-C", abs, "ls-files", "-z").Output()
+	if err != nil {
+		return tracked
+	}
+	for _, name := range bytes.Split(out, []byte{0}) {
+		if len(name) == 0 {
+			continue
+		}
+		for path := filepath.Join(abs, filepath.FromSlash(string(name))); path != abs && !tracked[path]; path = filepath.Dir(path) {
+			tracked[path] = true
+		}
+	}
+	return tracked
+}
+
+// cleanSize returns the total size of the files in a directory
+func cleanSize(dir string) int64 {
+	var size int64
+	filepath.WalkDir(dir, func(_ string, d os.DirEntry, err error) error {
+		if err == nil && d.Type().IsRegular() {
+			if info, err := d.Info(); err == nil {
+				size += info.Size()
+			}
+		}
+		return nil
+	})
+	return size
+}
+
+func cleanLabel(entry cleanEntry) string {
+	if entry.Dir {
+		return entry.Path + string(filepath.Separator)
+	}
+	return entry.Path
+}
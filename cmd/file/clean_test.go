@@ -0,0 +1,50 @@
+package file
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"devkit/internal/matcher"
+)
+
+func TestCleanKeepsExcludedPathInMatchedDir(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{"build/keep.txt", "build/app.o", "build/sub/gen.o", "src/main.go"} {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	targets, err := matcher.New(matcher.Options{Exclude: []string{"build/"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	keep, err := matcher.New(matcher.Options{Exclude: []string{"build/keep.txt"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &cleaner{targets: targets, keep: keep}
+	if err := c.find(root); err != nil {
+		t.Fatal(err)
+	}
+
+	got := make(map[string]bool)
+	for _, entry := range c.entries {
+		rel, _ := filepath.Rel(root, entry.Path)
+		got[filepath.ToSlash(rel)] = true
+	}
+	want := map[string]bool{"build/app.o": true, "build/sub": true}
+	if len(got) != len(want) {
+		t.Fatalf("entries = %v, want %v", got, want)
+	}
+	for rel := range want {
+		if !got[rel] {
+			t.Errorf("entries = %v, want %v", got, want)
+		}
+	}
+}
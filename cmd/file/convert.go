@@ -10,6 +10,7 @@ import (
 	"github.com/pelletier/go-toml/v2"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
+	"devkit/internal/picker"
 )
 
 // convertCmd represents the convert command
@@ -20,11 +21,18 @@ var convertCmd = &cobra.Command{
 
 Supported formats: json, yaml, toml, xml, csv
 
+Run in a terminal without a file to pick one interactively from the files
+in a supported format.
+
+With --out-dir, the output is written to that directory under the input
+file name with the extension of the target format.
+
 Examples:
   devkit file convert config.json --to yaml
   devkit file convert data.csv --to json
-  devkit file convert config.yaml --to toml`,
-	Args: cobra.ExactArgs(1),
+  devkit file convert config.yaml --to toml
+  devkit file convert data.yaml --to json --out-dir ./processed`,
+	Args: picker.ExactArgs(1),
 	RunE: runConvert,
 }
 
@@ -33,13 +41,30 @@ func init() {
 
 	convertCmd.Flags().StringP("to", "t", "", "Target format (json, yaml, toml, xml, csv) (required)")
 	convertCmd.Flags().StringP("output", "o", "", "Output file path (default: stdout)")
+	convertCmd.Flags().String("out-dir", "", "Directory to write the output to, named after the input file")
 	convertCmd.MarkFlagRequired("to")
 }
 
 func runConvert(cmd *cobra.Command, args []string) error {
+	args, err := picker.Fill(cmd, args, 1, nil, "json", "yaml", "yml", "toml", "xml", "csv")
+	if err != nil {
+		return err
+	}
 	inputFile := args[0]
 	toFormat, _ := cmd.Flags().GetString("to")
 	outputFile, _ := cmd.Flags().GetString("output")
+	outDir, _ := cmd.Flags().GetString("out-dir")
+
+	if outDir != "" {
+		if outputFile != "" {
+			return fmt.Errorf("--output and --out-dir cannot be used together")
+		}
+		if err := os.MkdirAll(outDir, 0755); err != nil {
+			return fmt.Errorf("failed to create output directory: %w", err)
+		}
+		base := strings.TrimSuffix(filepath.Base(inputFile), filepath.Ext(inputFile))
+		outputFile = filepath.Join(outDir, base+"."+strings.ToLower(toFormat))
+	}
 
 	// Detect input format
 	inputExt := strings.ToLower(strings.TrimPrefix(filepath.Ext(inputFile), "."))
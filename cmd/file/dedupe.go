@@ -6,9 +6,15 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"strconv"
 
 	"github.com/spf13/cobra"
+	"devkit/internal/cache"
+	"devkit/internal/i18n"
 	"devkit/internal/output"
+	"devkit/internal/picker"
+	"devkit/internal/safety"
+	"devkit/internal/undo"
 )
 
 // dedupeCmd represents the dedupe command
@@ -17,9 +23,19 @@ var dedupeCmd = &cobra.Command{
 	Short: "Find and remove duplicate files",
 	Long: `Find duplicate files by hash and optionally remove them.
 
+Size and modification time filters are applied before hashing, which keeps
+scans of large trees fast.
+
+With --action delete, --interactive shows the duplicates to be deleted as a
+checklist, so that some can be kept before the rest are deleted; it
+replaces the confirmation prompt.
+
 Examples:
   devkit file dedupe ./downloads --by hash
-  devkit file dedupe ./photos --by name --action delete --dry-run`,
+  devkit file dedupe ./photos --by name --action delete --dry-run
+  devkit file dedupe ./downloads --action delete --yes
+  devkit file dedupe ./photos -r --action delete --interactive
+  devkit file dedupe ~/media -r --min-size 10MB --modified-since 30d`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runDedupe,
 }
@@ -30,40 +46,51 @@ func init() {
 	dedupeCmd.Flags().StringP("by", "b", "hash", "Comparison method: hash, name")
 	dedupeCmd.Flags().StringP("action", "a", "list", "Action: list, delete")
 	dedupeCmd.Flags().BoolP("dry-run", "d", false, "Show what would be deleted without making changes")
+	dedupeCmd.Flags().BoolP("interactive", "i", false, "Choose which of the duplicates to delete")
 	dedupeCmd.Flags().BoolP("recursive", "r", false, "Search recursively")
+	dedupeCmd.Flags().StringArray("exclude", nil, "Skip files and directories matching a glob pattern (repeatable)")
+	addIgnoreProfileFlag(dedupeCmd)
+	addFileFilterFlags(dedupeCmd)
+	dedupeCmd.Flags().BoolP("follow-symlinks", "L", false, "Follow symbolic links (loops are detected and skipped)")
 	dedupeCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
 }
 
 func runDedupe(cmd *cobra.Command, args []string) error {
-	searchPath := "."
-	if len(args) > 0 {
-		searchPath = args[0]
-	}
-
 	by, _ := cmd.Flags().GetString("by")
 	action, _ := cmd.Flags().GetString("action")
 	dryRun, _ := cmd.Flags().GetBool("dry-run")
-	recursive, _ := cmd.Flags().GetBool("recursive")
+	interactive, _ := cmd.Flags().GetBool("interactive")
 	outputFormat, _ := cmd.Flags().GetString("output")
 	format := output.OutputFormat(outputFormat)
 
-	fileMap := make(map[string][]string)
+	if interactive && action != "delete" {
+		return fmt.Errorf("--interactive needs --action delete")
+	}
+	if interactive && dryRun {
+		return fmt.Errorf("--interactive and --dry-run cannot be used together")
+	}
+	if interactive && !picker.Available() {
+		return fmt.Errorf("--interactive needs a terminal")
+	}
 
-	err := filepath.Walk(searchPath, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return nil
+	if action == "delete" {
+		if err := safety.CheckDryRun(cmd, args, dryRun); err != nil {
+			return err
 		}
+	}
 
-		if info.IsDir() {
-			if !recursive && path != searchPath {
-				return filepath.SkipDir
-			}
-			return nil
-		}
+	fileMatcher, roots, err := newFileMatcher(cmd, args, ".")
+	if err != nil {
+		return err
+	}
+	searchPath := roots[0]
+
+	fileMap := make(map[string][]string)
 
+	err = fileMatcher.Walk(roots, func(path string, info os.FileInfo) error {
 		var key string
 		if by == "hash" {
-			hash, err := calculateFileHash(path)
+			hash, err := cachedFileHash(path, info)
 			if err != nil {
 				return nil
 			}
@@ -82,6 +109,7 @@ func runDedupe(cmd *cobra.Command, args []string) error {
 
 	var duplicates []map[string]interface{}
 	var toDelete []string
+	keeps := make(map[string]string)
 
 	for key, files := range fileMap {
 		if len(files) > 1 {
@@ -99,19 +127,83 @@ func runDedupe(cmd *cobra.Command, args []string) error {
 
 			if action == "delete" {
 				toDelete = append(toDelete, dups...)
+				for _, dup := range dups {
+					keeps[dup] = keep
+				}
 			}
 		}
 	}
 
+	skipped := 0
+	if interactive && len(toDelete) > 0 {
+		cmd.SilenceUsage = true
+		labels := make([]string, len(toDelete))
+		for i, file := range toDelete {
+			labels[i] = fmt.Sprintf("%s (duplicate of %s)", file, keeps[file])
+		}
+		selected, err := picker.Checklist(i18n.T("picker.deletes"), labels)
+		if err != nil {
+			return err
+		}
+		chosen := make([]string, 0, len(selected))
+		for _, i := range selected {
+			chosen = append(chosen, toDelete[i])
+		}
+		skipped = len(toDelete) - len(chosen)
+		toDelete = chosen
+	}
+
+	deleted := []string{}
+	failed := make(map[string]string)
+	var undoID string
+	if len(toDelete) > 0 && !dryRun {
+		if !interactive {
+			if err := safety.ConfirmChanges(safety.DeleteFiles, len(toDelete)); err != nil {
+				return err
+			}
+		}
+		op, err := undo.Begin(cmd.CommandPath())
+		if err != nil {
+			return err
+		}
+		for _, file := range toDelete {
+			if err := op.BackupDelete(file); err != nil {
+				failed[file] = err.Error()
+				continue
+			}
+			if err := os.Remove(file); err != nil {
+				op.Entries = op.Entries[:len(op.Entries)-1]
+				failed[file] = err.Error()
+			} else {
+				deleted = append(deleted, file)
+			}
+		}
+		if err := op.Commit(); err != nil {
+			return err
+		}
+		if len(deleted) > 0 {
+			undoID = op.ID
+		}
+	}
+
 	if format == output.FormatJSON {
-		output.PrintSuccess(format, map[string]interface{}{
+		result := map[string]interface{}{
 			"path":      searchPath,
 			"method":    by,
 			"duplicates": duplicates,
 			"count":     len(duplicates),
 			"to_delete": len(toDelete),
 			"dry_run":   dryRun,
-		})
+		}
+		if action == "delete" && !dryRun {
+			result["deleted"] = deleted
+			result["undo_id"] = undoID
+			result["skipped"] = skipped
+			if len(failed) > 0 {
+				result["failed"] = failed
+			}
+		}
+		output.PrintSuccess(format, result)
 	} else {
 		for _, dup := range duplicates {
 			fmt.Printf("\nDuplicate group (key: %s):\n", dup["key"])
@@ -128,16 +220,20 @@ func runDedupe(cmd *cobra.Command, args []string) error {
 				fmt.Println("\nDeleting duplicates:")
 			}
 			for _, file := range toDelete {
-				if !dryRun {
-					if err := os.Remove(file); err != nil {
-						fmt.Printf("  Failed to delete %s: %v\n", file, err)
-					} else {
-						fmt.Printf("  Deleted: %s\n", file)
-					}
-				} else {
+				if dryRun {
 					fmt.Printf("  Would delete: %s\n", file)
+				} else if reason, ok := failed[file]; ok {
+					fmt.Printf("  Failed to delete %s: %s\n", file, reason)
+				} else {
+					fmt.Printf("  Deleted: %s\n", file)
 				}
 			}
+			if undoID != "" {
+				fmt.Printf("Undo with: devkit undo %s\n", undoID)
+			}
+		}
+		if skipped > 0 {
+			fmt.Printf("\nKept %d duplicates that were not selected\n", skipped)
 		}
 
 		fmt.Printf("\nFound %d duplicate groups\n", len(duplicates))
@@ -146,6 +242,27 @@ func runDedupe(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// cachedFileHash returns the SHA-256 of a file, reusing the cached hash
+// while the file's path, size and modification time are unchanged
+func cachedFileHash(path string, info os.FileInfo) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return calculateFileHash(path)
+	}
+	key := cache.Key(abs, strconv.FormatInt(info.Size(), 10), strconv.FormatInt(info.ModTime().UnixNano(), 10))
+
+	var hash string
+	if cache.Get(cache.NamespaceFileHash, key, 0, &hash) {
+		return hash, nil
+	}
+	hash, err = calculateFileHash(path)
+	if err != nil {
+		return "", err
+	}
+	cache.Set(cache.NamespaceFileHash, key, hash)
+	return hash, nil
+}
+
 func calculateFileHash(filename string) (string, error) {
 	file, err := os.Open(filename)
 	if err != nil {
@@ -5,21 +5,23 @@ import (
 	"fmt"
 	"os"
 
-	"github.com/fatih/color"
 	"github.com/spf13/cobra"
+	"devkit/internal/i18n"
 	"devkit/internal/output"
+	"devkit/internal/picker"
 )
 
 // diffCmd represents the diff command
 var diffCmd = &cobra.Command{
 	Use:   "diff [file1] [file2]",
 	Short: "Compare two files or directories",
-	Long: `Compare two files or directories and show differences.
+	Long: `Compare two files or directories and show differences. Run in a
+terminal without the paths to pick the files interactively.
 
 Examples:
   devkit file diff file1.txt file2.txt
   devkit file diff dir1/ dir2/`,
-	Args: cobra.ExactArgs(2),
+	Args: picker.ExactArgs(2),
 	RunE: runDiff,
 }
 
@@ -31,6 +33,10 @@ func init() {
 }
 
 func runDiff(cmd *cobra.Command, args []string) error {
+	args, err := picker.Fill(cmd, args, 2, []string{i18n.T("picker.first_file"), i18n.T("picker.second_file")})
+	if err != nil {
+		return err
+	}
 	file1 := args[0]
 	file2 := args[1]
 	_, _ = cmd.Flags().GetBool("unified") // unified flag for future use
@@ -70,19 +76,15 @@ func runDiff(cmd *cobra.Command, args []string) error {
 			"diffs": diffs,
 		})
 	} else {
-		red := color.New(color.FgRed).SprintFunc()
-		green := color.New(color.FgGreen).SprintFunc()
-		yellow := color.New(color.FgYellow).SprintFunc()
-
-		fmt.Printf("%s %s\n", yellow("---"), file1)
-		fmt.Printf("%s %s\n", yellow("+++"), file2)
+		fmt.Printf("%s %s\n", output.Warning("---"), file1)
+		fmt.Printf("%s %s\n", output.Warning("+++"), file2)
 
 		for _, diff := range diffs {
 			switch diff.Type {
 			case "removed":
-				fmt.Printf("%s %s\n", red("-"), diff.Line)
+				fmt.Printf("%s %s\n", output.Failure("-"), diff.Line)
 			case "added":
-				fmt.Printf("%s %s\n", green("+"), diff.Line)
+				fmt.Printf("%s %s\n", output.Success("+"), diff.Line)
 			case "context":
 				fmt.Printf("%s %s\n", " ", diff.Line)
 			}
@@ -1,7 +1,11 @@
 package file
 
 import (
+	"strings"
+
 	"github.com/spf13/cobra"
+	"devkit/internal/matcher"
+	"devkit/internal/workspace"
 )
 
 // fileCmd represents the file command group
@@ -11,12 +15,14 @@ var fileCmd = &cobra.Command{
 	Long: `File and directory operations for managing files and directories.
 
 This command group includes utilities for:
-- File search and find-replace
+- File search, find (with --exec) and find-replace
 - Bulk rename operations
 - Format conversion
 - File comparison (diff)
 - Duplicate file detection
+- Cleaning of build outputs and caches
 - File watching
+- Archive safety audits
 - Directory tree visualization
 - File statistics
 - And more...`,
@@ -31,3 +37,123 @@ func init() {
 	// This will be called when the package is imported
 	// Commands will be added in their respective files
 }
+
+// newFileMatcher builds the shared include/exclude matcher from the
+// --recursive, --extensions, --ignore, --include/--pattern, --exclude,
+// --ignore-profile, --follow-symlinks and size/time filter flags a command
+// defines, and expands the path arguments (falling back to the workspace
+// path, then defaultPath when none are given). Workspace ignore patterns
+// and profiles are always excluded.
+func newFileMatcher(cmd *cobra.Command, paths []string, defaultPath string) (*matcher.Matcher, []string, error) {
+	opts, defaultPath, err := fileMatcherOptions(cmd, defaultPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	return buildFileMatcher(opts, paths, defaultPath)
+}
+
+// fileMatcherOptions reads the matcher flags a command defines, adds the
+// workspace ignore patterns and returns the default path to use
+func fileMatcherOptions(cmd *cobra.Command, defaultPath string) (matcher.Options, string, error) {
+	var opts matcher.Options
+	if cmd.Flags().Lookup("recursive") != nil {
+		opts.Recursive, _ = cmd.Flags().GetBool("recursive")
+	}
+	if cmd.Flags().Lookup("extensions") != nil {
+		extensions, _ := cmd.Flags().GetString("extensions")
+		opts.Extensions = matcher.SplitList(extensions)
+	}
+	if cmd.Flags().Lookup("ignore") != nil {
+		ignore, _ := cmd.Flags().GetString("ignore")
+		opts.Exclude = matcher.SplitList(ignore)
+	}
+	if cmd.Flags().Lookup("include") != nil {
+		opts.Include, _ = cmd.Flags().GetStringArray("include")
+	}
+	if cmd.Flags().Lookup("pattern") != nil {
+		pattern, _ := cmd.Flags().GetString("pattern")
+		opts.Include = append(opts.Include, pattern)
+	}
+	if cmd.Flags().Lookup("exclude") != nil {
+		exclude, _ := cmd.Flags().GetStringArray("exclude")
+		opts.Exclude = append(opts.Exclude, exclude...)
+	}
+	if cmd.Flags().Lookup("ignore-profile") != nil {
+		opts.IgnoreProfiles, _ = cmd.Flags().GetStringSlice("ignore-profile")
+	}
+
+	if ws := workspace.Current(); ws != nil {
+		opts.Exclude = append(opts.Exclude, ws.Ignore...)
+		opts.IgnoreProfiles = append(opts.IgnoreProfiles, ws.IgnoreProfiles...)
+		if ws.Path != "" && !cmd.Flags().Changed("path") {
+			defaultPath = ws.Resolve(ws.Path)
+		}
+	}
+
+	if cmd.Flags().Lookup("follow-symlinks") != nil {
+		opts.FollowSymlinks, _ = cmd.Flags().GetBool("follow-symlinks")
+	}
+	if cmd.Flags().Lookup("min-size") != nil {
+		if err := readFileFilterFlags(cmd, &opts); err != nil {
+			return opts, "", err
+		}
+	}
+	return opts, defaultPath, nil
+}
+
+// buildFileMatcher creates the matcher and expands the path arguments,
+// falling back to defaultPath when none are given
+func buildFileMatcher(opts matcher.Options, paths []string, defaultPath string) (*matcher.Matcher, []string, error) {
+	m, err := matcher.New(opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(paths) == 0 {
+		paths = []string{defaultPath}
+	}
+	roots, err := matcher.ExpandPaths(paths)
+	if err != nil {
+		return nil, nil, err
+	}
+	return m, roots, nil
+}
+
+// addIgnoreProfileFlag defines --ignore-profile, which excludes the files of
+// the named matcher profiles
+func addIgnoreProfileFlag(cmd *cobra.Command) {
+	cmd.Flags().StringSlice("ignore-profile", nil, "Skip the files generated by a toolchain: "+strings.Join(matcher.ProfileNames(), ", ")+" (comma-separated or repeated)")
+}
+
+// addFileFilterFlags defines the shared size and modification time filters
+func addFileFilterFlags(cmd *cobra.Command) {
+	cmd.Flags().String("min-size", "", "Only include files at least this large (e.g. 10MB)")
+	cmd.Flags().String("max-size", "", "Only include files at most this large (e.g. 1GB)")
+	cmd.Flags().String("modified-since", "", "Only include files modified after a date or age (e.g. 2024-06-01, 7d)")
+	cmd.Flags().String("modified-before", "", "Only include files modified before a date or age (e.g. 2024-06-01, 30d)")
+}
+
+func readFileFilterFlags(cmd *cobra.Command, opts *matcher.Options) error {
+	var err error
+	if value, _ := cmd.Flags().GetString("min-size"); value != "" {
+		if opts.MinSize, err = matcher.ParseSize(value); err != nil {
+			return err
+		}
+	}
+	if value, _ := cmd.Flags().GetString("max-size"); value != "" {
+		if opts.MaxSize, err = matcher.ParseSize(value); err != nil {
+			return err
+		}
+	}
+	if value, _ := cmd.Flags().GetString("modified-since"); value != "" {
+		if opts.ModifiedSince, err = matcher.ParseTime(value); err != nil {
+			return err
+		}
+	}
+	if value, _ := cmd.Flags().GetString("modified-before"); value != "" {
+		if opts.ModifiedBefore, err = matcher.ParseTime(value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
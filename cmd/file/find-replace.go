@@ -4,24 +4,30 @@ import (
 	"bufio"
 	"fmt"
 	"os"
-	"path/filepath"
 	"regexp"
-	"strings"
 
 	"github.com/spf13/cobra"
 	"devkit/internal/output"
+	"devkit/internal/safety"
+	"devkit/internal/undo"
 )
 
 // findReplaceCmd represents the find-replace command
 var findReplaceCmd = &cobra.Command{
-	Use:   "find-replace [find] [replace] [path]",
+	Use:   "find-replace [find] [replace] [path...]",
 	Short: "Find and replace text in multiple files",
 	Long: `Find and replace text patterns in multiple files.
 
+Several paths can be given; paths may be glob patterns. --include and
+--exclude take doublestar patterns (** matches any number of directories)
+and imply --recursive when they contain **.
+
 Examples:
   devkit file find-replace "old" "new" .
   devkit file find-replace "TODO" "DONE" ./src --recursive
-  devkit file find-replace "error" "err" . --extensions "go,js" --dry-run`,
+  devkit file find-replace "error" "err" . --extensions "go,js" --dry-run
+  devkit file find-replace "v1" "v2" ./docs --yes
+  devkit file find-replace "oldpkg" "newpkg" ./cmd ./internal --include "**/*.go"`,
 	Args: cobra.MinimumNArgs(2),
 	RunE: runFindReplace,
 }
@@ -33,6 +39,9 @@ func init() {
 	findReplaceCmd.Flags().BoolP("recursive", "r", false, "Search recursively")
 	findReplaceCmd.Flags().String("extensions", "", "File extensions to search (comma-separated)")
 	findReplaceCmd.Flags().String("ignore", "", "Directories to ignore (comma-separated)")
+	findReplaceCmd.Flags().StringArray("include", nil, "Only modify files matching a glob pattern (repeatable)")
+	findReplaceCmd.Flags().StringArray("exclude", nil, "Skip files and directories matching a glob pattern (repeatable)")
+	addIgnoreProfileFlag(findReplaceCmd)
 	findReplaceCmd.Flags().BoolP("regex", "e", false, "Use regex pattern")
 	findReplaceCmd.Flags().BoolP("dry-run", "d", false, "Show what would be changed without making changes")
 	findReplaceCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
@@ -41,24 +50,18 @@ func init() {
 func runFindReplace(cmd *cobra.Command, args []string) error {
 	find := args[0]
 	replace := args[1]
-	searchPath := "."
-	if len(args) > 2 {
-		searchPath = args[2]
-	}
-
-	recursive, _ := cmd.Flags().GetBool("recursive")
-	extensions, _ := cmd.Flags().GetString("extensions")
-	ignore, _ := cmd.Flags().GetString("ignore")
+	searchPath, _ := cmd.Flags().GetString("path")
 	useRegex, _ := cmd.Flags().GetBool("regex")
 	dryRun, _ := cmd.Flags().GetBool("dry-run")
 	outputFormat, _ := cmd.Flags().GetString("output")
 	format := output.OutputFormat(outputFormat)
 
-	extList := strings.Split(extensions, ",")
-	ignoreList := strings.Split(ignore, ",")
+	fileMatcher, roots, err := newFileMatcher(cmd, args[2:], searchPath)
+	if err != nil {
+		return err
+	}
 
 	var searchPattern *regexp.Regexp
-	var err error
 
 	if useRegex {
 		searchPattern, err = regexp.Compile(find)
@@ -70,40 +73,15 @@ func runFindReplace(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("invalid pattern: %w", err)
 	}
 
+	if err := safety.CheckDryRun(cmd, args, dryRun); err != nil {
+		return err
+	}
+
 	var results []map[string]interface{}
+	pending := make(map[string][]string)
 	totalReplacements := 0
 
-	err = filepath.Walk(searchPath, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return nil
-		}
-
-		if info.IsDir() {
-			if !recursive && path != searchPath {
-				return filepath.SkipDir
-			}
-			for _, ignoreDir := range ignoreList {
-				if strings.Contains(path, strings.TrimSpace(ignoreDir)) {
-					return filepath.SkipDir
-				}
-			}
-			return nil
-		}
-
-		if extensions != "" {
-			ext := filepath.Ext(path)
-			found := false
-			for _, e := range extList {
-				if strings.TrimSpace(e) == strings.TrimPrefix(ext, ".") {
-					found = true
-					break
-				}
-			}
-			if !found {
-				return nil
-			}
-		}
-
+	err = fileMatcher.Walk(roots, func(path string, info os.FileInfo) error {
 		file, err := os.Open(path)
 		if err != nil {
 			return nil
@@ -136,19 +114,7 @@ func runFindReplace(cmd *cobra.Command, args []string) error {
 				"replacements": countReplacements(lines, searchPattern, find, replace),
 			}
 			results = append(results, result)
-
-			if !dryRun {
-				outputFile, err := os.Create(path)
-				if err != nil {
-					return fmt.Errorf("failed to write file %s: %w", path, err)
-				}
-				writer := bufio.NewWriter(outputFile)
-				for _, line := range lines {
-					fmt.Fprintln(writer, line)
-				}
-				writer.Flush()
-				outputFile.Close()
-			}
+			pending[path] = lines
 		}
 
 		return nil
@@ -158,15 +124,43 @@ func runFindReplace(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("find-replace error: %w", err)
 	}
 
+	var undoID string
+	if !dryRun && len(results) > 0 {
+		if err := safety.ConfirmChanges(safety.ModifyFiles, len(results)); err != nil {
+			return err
+		}
+		op, err := undo.Begin(cmd.CommandPath())
+		if err != nil {
+			return err
+		}
+		for _, result := range results {
+			path := result["file"].(string)
+			if err := op.BackupFile(path); err != nil {
+				op.Commit()
+				return err
+			}
+			if err := writeLines(path, pending[path]); err != nil {
+				op.Commit()
+				return err
+			}
+		}
+		if err := op.Commit(); err != nil {
+			return err
+		}
+		undoID = op.ID
+	}
+
 	if format == output.FormatJSON {
 		output.PrintSuccess(format, map[string]interface{}{
-			"find":      find,
-			"replace":   replace,
-			"path":     searchPath,
-			"files":    results,
-			"count":    len(results),
-			"total":    totalReplacements,
-			"dry_run":  dryRun,
+			"find":         find,
+			"replace":      replace,
+			"path":         roots[0],
+			"paths":        roots,
+			"files":        results,
+			"count":        len(results),
+			"replacements": totalReplacements,
+			"dry_run":      dryRun,
+			"undo_id":      undoID,
 		})
 	} else {
 		if dryRun {
@@ -176,11 +170,28 @@ func runFindReplace(cmd *cobra.Command, args []string) error {
 			fmt.Printf("Modified: %s (%d replacements)\n", result["file"], result["replacements"])
 		}
 		fmt.Printf("\nTotal: %d files, %d replacements\n", len(results), totalReplacements)
+		if undoID != "" {
+			fmt.Printf("Undo with: devkit undo %s\n", undoID)
+		}
 	}
 
 	return nil
 }
 
+func writeLines(path string, lines []string) error {
+	outputFile, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to write file %s: %w", path, err)
+	}
+	defer outputFile.Close()
+
+	writer := bufio.NewWriter(outputFile)
+	for _, line := range lines {
+		fmt.Fprintln(writer, line)
+	}
+	return writer.Flush()
+}
+
 func countReplacements(lines []string, pattern *regexp.Regexp, find, replace string) int {
 	count := 0
 	for _, line := range lines {
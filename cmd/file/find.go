@@ -0,0 +1,340 @@
+package file
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"devkit/internal/errors"
+	"devkit/internal/matcher"
+	"devkit/internal/output"
+	"devkit/internal/utils"
+)
+
+// findPlaceholders are replaced in --exec arguments, longest first so that
+// {/.} is not read as {/} followed by "."
+var findPlaceholders = []string{"{//}", "{/.}", "{/}", "{.}", "{}"}
+
+// findCmd represents the find command
+var findCmd = &cobra.Command{
+	Use:   "find [path...]",
+	Short: "Find files by name, type, size and age, and run a command on each",
+	Long: `Find files and directories below the given paths (default: the current
+directory or the workspace path) and print one path per line, like fd or
+find, on every platform.
+
+Predicates:
+  --name       glob the name (or, with a slash, the relative path) must match;
+               repeatable, ** matches any number of directories
+  --type       f (files) or d (directories); both by default
+  --size       +N at least / -N at most N bytes (512, 10K, 1.5M, 2G), files only
+  --modified   -AGE modified within AGE / +AGE modified more than AGE ago
+               (90m, 36h, 7d, 2w), or -DATE / +DATE (after / before 2024-06-01)
+Repeat --size or --modified to give both bounds. Hidden files and
+directories (names starting with a dot, such as .git) are skipped unless
+--hidden is given.
+
+With --exec, the command is run for every match, --jobs at a time. Its
+arguments may contain placeholders; without any, the path is appended:
+  {}    the path                 {/}   the file name
+  {//}  the parent directory     {.}   the path without extension
+  {/.}  the file name without extension
+The command is run directly, not through a shell; "devkit" runs this
+binary. The output of each run is printed in one piece.
+
+Examples:
+  devkit file find --name '*.go'
+  devkit file find src docs --type d --name '*test*'
+  devkit file find --size +1M --modified -7d
+  devkit file find --name '*.log' --modified +30d --output json
+  devkit file find --name '*.go' --type f --exec 'devkit dev hash sha256 --file {}'
+  devkit file find --name '*.json' --exec 'devkit file convert {} --to yaml --output {.}.yaml'`,
+	RunE: runFind,
+}
+
+func init() {
+	fileCmd.AddCommand(findCmd)
+
+	findCmd.Flags().StringArrayP("name", "n", nil, "Glob the name or relative path must match (repeatable)")
+	findCmd.Flags().StringP("type", "t", "", "Entry type: f (file), d (directory)")
+	findCmd.Flags().StringArray("size", nil, "Size bound: +N (at least) or -N (at most), e.g. +1M (repeatable)")
+	findCmd.Flags().StringArray("modified", nil, "Age bound: -7d (within) or +7d (older than), or a date (repeatable)")
+	findCmd.Flags().String("extensions", "", "File extensions to find (comma-separated)")
+	findCmd.Flags().StringArray("exclude", nil, "Skip files and directories matching a glob pattern (repeatable)")
+	addIgnoreProfileFlag(findCmd)
+	findCmd.Flags().BoolP("hidden", "H", false, "Include hidden files and directories")
+	findCmd.Flags().BoolP("follow-symlinks", "L", false, "Follow symbolic links (loops are detected and skipped)")
+	findCmd.Flags().StringP("exec", "x", "", "Command to run for every match, e.g. 'devkit dev hash sha256 --file {}'")
+	findCmd.Flags().IntP("jobs", "j", runtime.NumCPU(), "With --exec, number of commands run in parallel")
+	findCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
+}
+
+// findMatch is one entry found, with the result of --exec if given
+type findMatch struct {
+	Path     string `json:"path"`
+	Type     string `json:"type"`
+	Size     int64  `json:"size"`
+	Modified string `json:"modified"`
+	ExitCode *int   `json:"exit_code,omitempty"`
+	Output   string `json:"output,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+func runFind(cmd *cobra.Command, args []string) error {
+	names, _ := cmd.Flags().GetStringArray("name")
+	entryType, _ := cmd.Flags().GetString("type")
+	sizes, _ := cmd.Flags().GetStringArray("size")
+	ages, _ := cmd.Flags().GetStringArray("modified")
+	hidden, _ := cmd.Flags().GetBool("hidden")
+	execLine, _ := cmd.Flags().GetString("exec")
+	jobs, _ := cmd.Flags().GetInt("jobs")
+	outputFormat, _ := cmd.Flags().GetString("output")
+	format := output.OutputFormat(outputFormat)
+
+	opts, defaultPath, err := fileMatcherOptions(cmd, ".")
+	if err != nil {
+		return err
+	}
+	opts.Recursive = true
+	opts.Include = append(opts.Include, names...)
+	if !hidden {
+		opts.Exclude = append(opts.Exclude, ".*")
+	}
+
+	switch entryType {
+	case "":
+		opts.Dirs = true
+	case "f", "file":
+	case "d", "dir", "directory":
+		opts.Dirs = true
+	default:
+		return fmt.Errorf("invalid type: %s (supported: f, d)", entryType)
+	}
+	if err := parseFindBounds(sizes, ages, &opts); err != nil {
+		return err
+	}
+
+	var command []string
+	if execLine != "" {
+		if command, err = utils.SplitArgs(execLine); err != nil {
+			return fmt.Errorf("invalid --exec command: %w", err)
+		}
+		if len(command) == 0 {
+			return fmt.Errorf("--exec command cannot be empty")
+		}
+	}
+
+	m, roots, err := buildFileMatcher(opts, args, defaultPath)
+	if err != nil {
+		return err
+	}
+
+	var matches []findMatch
+	err = m.Walk(roots, func(path string, info os.FileInfo) error {
+		if entryType != "" && info.IsDir() != (entryType != "f" && entryType != "file") {
+			return nil
+		}
+		match := findMatch{
+			Path:     path,
+			Type:     "file",
+			Size:     info.Size(),
+			Modified: info.ModTime().Format(time.RFC3339),
+		}
+		if info.IsDir() {
+			match.Type = "dir"
+		}
+		matches = append(matches, match)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("find error: %w", err)
+	}
+
+	if command == nil {
+		if format == output.FormatJSON {
+			output.PrintSuccess(format, map[string]interface{}{
+				"paths":   roots,
+				"count":   len(matches),
+				"matches": matches,
+			})
+			return nil
+		}
+		for _, match := range matches {
+			fmt.Println(match.Path)
+		}
+		return nil
+	}
+
+	failed := runFindExec(command, matches, jobs, format != output.FormatJSON)
+
+	if format == output.FormatJSON {
+		output.PrintSuccess(format, map[string]interface{}{
+			"paths":   roots,
+			"count":   len(matches),
+			"exec":    execLine,
+			"failed":  failed,
+			"matches": matches,
+		})
+	}
+
+	if failed == 0 {
+		return nil
+	}
+	cmd.SilenceUsage = true
+	return &errors.ExitError{Code: errors.ExitFailure, Err: fmt.Errorf("command failed for %d of %d matches", failed, len(matches))}
+}
+
+// parseFindBounds reads the signed --size and --modified values into the
+// matcher size and time bounds
+func parseFindBounds(sizes, ages []string, opts *matcher.Options) error {
+	for _, value := range sizes {
+		sign, rest := splitFindSign(value)
+		if sign == 0 {
+			return fmt.Errorf("invalid size: %s (use +N for at least or -N for at most, e.g. +1M)", value)
+		}
+		size, err := matcher.ParseSize(rest)
+		if err != nil {
+			return err
+		}
+		if sign == '+' {
+			opts.MinSize = size
+		} else {
+			opts.MaxSize = size
+		}
+	}
+
+	for _, value := range ages {
+		sign, rest := splitFindSign(value)
+		if sign == 0 {
+			return fmt.Errorf("invalid age: %s (use -7d for within or +7d for older than)", value)
+		}
+		t, err := matcher.ParseTime(rest)
+		if err != nil {
+			return err
+		}
+		if sign == '-' {
+			opts.ModifiedSince = t
+		} else {
+			opts.ModifiedBefore = t
+		}
+	}
+	return nil
+}
+
+func splitFindSign(value string) (byte, string) {
+	value = strings.TrimSpace(value)
+	if len(value) < 2 || (value[0] != '+' && value[0] != '-') {
+		return 0, value
+	}
+	return value[0], value[1:]
+}
+
+// runFindExec runs command for every match with up to jobs at a time and
+// records the results in matches. With print, the output of each run is
+// written as soon as it finishes; otherwise it is kept in the match. It
+// returns the number of failed runs.
+func runFindExec(command []string, matches []findMatch, jobs int, print bool) int {
+	if jobs < 1 {
+		jobs = 1
+	}
+	self, _ := os.Executable()
+
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		failed int
+	)
+	indexes := make(chan int)
+	for w := 0; w < jobs && w < len(matches); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				match := &matches[i]
+				argv := expandFindCommand(command, match.Path)
+				if argv[0] == "devkit" && self != "" {
+					argv[0] = self
+				}
+
+				var stdout, stderr bytes.Buffer
+				proc := exec.Command(argv[0], argv[1:]...)
+				proc.Stdout = &stdout
+				proc.Stderr = &stderr
+				err := proc.Run()
+
+				code := 0
+				if err != nil {
+					code = errors.ExitFailure
+					if exitErr, ok := err.(*exec.ExitError); ok {
+						code = exitErr.ExitCode()
+					} else {
+						match.Error = err.Error()
+					}
+				}
+				match.ExitCode = &code
+				if code != 0 && match.Error == "" {
+					match.Error = strings.TrimSpace(stderr.String())
+				}
+
+				mu.Lock()
+				if code != 0 {
+					failed++
+				}
+				if print {
+					os.Stdout.Write(stdout.Bytes())
+					os.Stderr.Write(stderr.Bytes())
+					if err != nil && stderr.Len() == 0 {
+						fmt.Fprintln(os.Stderr, output.Fail(fmt.Sprintf("%s: %v", match.Path, err)))
+					}
+				} else {
+					match.Output = strings.TrimSpace(stdout.String())
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for i := range matches {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+	return failed
+}
+
+// expandFindCommand substitutes the placeholders in command for path, or
+// appends path when command has none
+func expandFindCommand(command []string, path string) []string {
+	ext := filepath.Ext(path)
+	base := filepath.Base(path)
+	replacer := strings.NewReplacer(
+		"{//}", filepath.Dir(path),
+		"{/.}", strings.TrimSuffix(base, ext),
+		"{/}", base,
+		"{.}", strings.TrimSuffix(path, ext),
+		"{}", path,
+	)
+
+	argv := make([]string, len(command))
+	found := false
+	for i, arg := range command {
+		for _, placeholder := range findPlaceholders {
+			if strings.Contains(arg, placeholder) {
+				found = true
+				break
+			}
+		}
+		argv[i] = replacer.Replace(arg)
+	}
+	if !found {
+		argv = append(argv, path)
+	}
+	return argv
+}
@@ -0,0 +1,659 @@
+package file
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/tidwall/pretty"
+	"golang.org/x/term"
+	"devkit/internal/output"
+	"devkit/internal/picker"
+)
+
+// previewKinds are the renderers --as can select
+var previewKinds = []string{"json", "csv", "markdown", "image", "archive", "text", "hex"}
+
+// previewExtensions map file extensions to renderers; other files are
+// recognized by their content
+var previewExtensions = map[string]string{
+	".json":     "json",
+	".geojson":  "json",
+	".csv":      "csv",
+	".tsv":      "csv",
+	".md":       "markdown",
+	".markdown": "markdown",
+	".png":      "image",
+	".jpg":      "image",
+	".jpeg":     "image",
+	".gif":      "image",
+	".zip":      "archive",
+	".jar":      "archive",
+	".tar":      "archive",
+	".tgz":      "archive",
+	".gz":       "archive",
+}
+
+// previewMaxRead bounds how much of a file the JSON, Markdown and text
+// renderers read
+const previewMaxRead = 16 << 20
+
+// previewCmd represents the preview command
+var previewCmd = &cobra.Command{
+	Use:   "preview [file]",
+	Short: "Show what is inside a file",
+	Long: `Show what is inside a file with the renderer that fits its type:
+
+  json      prettified, with colors on a terminal
+  csv       the first rows as an aligned table (.csv, .tsv)
+  markdown  headings, lists, quotes and code blocks rendered for the terminal
+  image     format and dimensions, and a thumbnail on a color terminal
+            (PNG, JPEG, GIF)
+  archive   the entries of a zip, tar or tar.gz file
+  text      the first lines of any other text file
+  hex       a hex dump of the start of binary files
+
+The renderer is chosen from the file extension, then the content; --as
+forces one. --lines limits the output (0 for everything). Run in a terminal
+without a file to pick one interactively.
+
+Examples:
+  devkit file preview package.json
+  devkit file preview data.csv --lines 10
+  devkit file preview README.md
+  devkit file preview logo.png --width 40
+  devkit file preview release.tar.gz
+  devkit file preview firmware.bin --as hex --lines 8
+  devkit file preview report.csv --output json`,
+	Args: picker.ExactArgs(1),
+	RunE: runPreview,
+}
+
+func init() {
+	fileCmd.AddCommand(previewCmd)
+
+	previewCmd.Flags().String("as", "", "Renderer to use: "+strings.Join(previewKinds, ", ")+" (default: detected)")
+	previewCmd.Flags().IntP("lines", "n", 40, "Maximum lines, rows or entries to show (0 for all)")
+	previewCmd.Flags().IntP("width", "w", 0, "Thumbnail width in columns (default: fit the terminal, at most 80)")
+	previewCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
+}
+
+// preview is the result of a renderer: lines for the terminal and a
+// summary for JSON output
+type preview struct {
+	lines   []string
+	details map[string]interface{}
+}
+
+func runPreview(cmd *cobra.Command, args []string) error {
+	args, err := picker.Fill(cmd, args, 1, nil)
+	if err != nil {
+		return err
+	}
+	path := args[0]
+	kind, _ := cmd.Flags().GetString("as")
+	limit, _ := cmd.Flags().GetInt("lines")
+	width, _ := cmd.Flags().GetInt("width")
+	outputFormat, _ := cmd.Flags().GetString("output")
+	format := output.OutputFormat(outputFormat)
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("failed to get file info: %w", err)
+	}
+	if info.IsDir() {
+		return fmt.Errorf("%s is a directory (use file tree to list it)", path)
+	}
+
+	head, err := readPreviewHead(path, 512)
+	if err != nil {
+		return err
+	}
+	mime := http.DetectContentType(head)
+
+	detected := kind == ""
+	if detected {
+		kind = detectPreviewKind(path, head, mime)
+	} else if !slices.Contains(previewKinds, kind) {
+		return fmt.Errorf("unsupported renderer: %s (supported: %s)", kind, strings.Join(previewKinds, ", "))
+	}
+	if limit < 0 {
+		limit = 0
+	}
+
+	var p *preview
+	switch kind {
+	case "json":
+		p, err = previewJSON(path)
+	case "csv":
+		p, err = previewCSV(path, limit)
+	case "markdown":
+		p, err = previewMarkdown(path)
+	case "image":
+		p, err = previewImage(path, width, format == output.FormatJSON)
+	case "archive":
+		p, err = previewArchive(path)
+	case "text":
+		p, err = previewText(path, limit)
+	default:
+		p, err = previewHex(path, limit)
+	}
+	if err != nil && detected && kind == "json" && filepath.Ext(path) == "" {
+		// Text that merely starts with a bracket, e.g. an INI file
+		kind = "text"
+		p, err = previewText(path, limit)
+	}
+	if err != nil {
+		return err
+	}
+
+	if format == output.FormatJSON {
+		result := map[string]interface{}{
+			"path":      path,
+			"size":      info.Size(),
+			"kind":      kind,
+			"mime_type": mime,
+		}
+		for key, value := range p.details {
+			result[key] = value
+		}
+		output.PrintSuccess(format, result)
+		return nil
+	}
+
+	fmt.Printf("%s %s\n\n", output.Accent(path), output.Muted(fmt.Sprintf("(%s, %s)", kind, formatSize(info.Size()))))
+	lines := p.lines
+	// CSV rows are limited while reading; thumbnails are shown whole
+	if limit > 0 && kind != "csv" && kind != "image" && len(lines) > limit {
+		more := len(lines) - limit
+		lines = append(lines[:limit:limit], output.Muted(fmt.Sprintf("… %d more lines (--lines 0 shows everything)", more)))
+	}
+	for _, line := range lines {
+		fmt.Println(line)
+	}
+	return nil
+}
+
+func readPreviewHead(path string, n int) ([]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	defer file.Close()
+
+	head := make([]byte, n)
+	read, err := io.ReadFull(file, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	return head[:read], nil
+}
+
+func readPreviewFile(path string) ([]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, previewMaxRead))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	return data, nil
+}
+
+// detectPreviewKind picks a renderer from the extension, then from the
+// sniffed content type
+func detectPreviewKind(path string, head []byte, mime string) string {
+	if kind, ok := previewExtensions[strings.ToLower(filepath.Ext(path))]; ok {
+		return kind
+	}
+
+	switch {
+	case strings.HasPrefix(mime, "image/") && mime != "image/svg+xml" && mime != "image/x-icon":
+		return "image"
+	case mime == "application/zip" || mime == "application/x-gzip":
+		return "archive"
+	case len(head) >= 262 && string(head[257:262]) == "ustar":
+		return "archive"
+	case bytes.IndexByte(head, 0) >= 0 || !utf8.Valid(trimPartialRune(head)):
+		return "hex"
+	}
+
+	trimmed := bytes.TrimSpace(head)
+	if len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[') {
+		return "json"
+	}
+	return "text"
+}
+
+// trimPartialRune drops a multi-byte character cut off at the end of a
+// buffer so that it does not make valid UTF-8 look binary
+func trimPartialRune(b []byte) []byte {
+	for i := 0; i < utf8.UTFMax && i < len(b); i++ {
+		if r, _ := utf8.DecodeLastRune(b[:len(b)-i]); r != utf8.RuneError {
+			return b[:len(b)-i]
+		}
+	}
+	return b
+}
+
+func previewJSON(path string) (*preview, error) {
+	data, err := readPreviewFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w (use --as text)", err)
+	}
+
+	details := map[string]interface{}{"type": jsonTypeName(value)}
+	switch v := value.(type) {
+	case map[string]interface{}:
+		details["keys"] = len(v)
+	case []interface{}:
+		details["items"] = len(v)
+	}
+
+	formatted := pretty.Pretty(data)
+	if !color.NoColor {
+		formatted = pretty.Color(formatted, nil)
+	}
+	return &preview{
+		lines:   strings.Split(strings.TrimRight(string(formatted), "\n"), "\n"),
+		details: details,
+	}, nil
+}
+
+func jsonTypeName(value interface{}) string {
+	switch value.(type) {
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	}
+	return "null"
+}
+
+func previewCSV(path string, limit int) (*preview, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(bufio.NewReader(file))
+	if strings.EqualFold(filepath.Ext(path), ".tsv") {
+		reader.Comma = '\t'
+	}
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	var rows [][]string
+	total := 0
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV: %w (use --as text)", err)
+		}
+		total++
+		if limit == 0 || len(rows) <= limit {
+			rows = append(rows, record)
+		}
+	}
+	if len(rows) == 0 {
+		return &preview{details: map[string]interface{}{"columns": []string{}, "rows": 0}}, nil
+	}
+
+	header, body := rows[0], rows[1:]
+	total--
+	if limit > 0 && len(body) > limit {
+		body = body[:limit]
+	}
+
+	columns := len(header)
+	for _, row := range body {
+		columns = max(columns, len(row))
+	}
+	widths := make([]int, columns)
+	for _, row := range append([][]string{header}, body...) {
+		for i, cell := range row {
+			widths[i] = min(max(widths[i], utf8.RuneCountInString(cell)), 40)
+		}
+	}
+
+	format := func(row []string, style func(string) string) string {
+		cells := make([]string, columns)
+		for i := range cells {
+			cell := ""
+			if i < len(row) {
+				cell = strings.ReplaceAll(row[i], "\n", " ")
+			}
+			if utf8.RuneCountInString(cell) > widths[i] {
+				cell = string([]rune(cell)[:widths[i]-1]) + "…"
+			}
+			cells[i] = style(cell) + strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell))
+		}
+		return strings.TrimRight(strings.Join(cells, "  "), " ")
+	}
+
+	lines := []string{format(header, output.Accent)}
+	ruleWidth := 2 * (columns - 1)
+	for _, w := range widths {
+		ruleWidth += w
+	}
+	lines = append(lines, output.Rule(ruleWidth))
+	for _, row := range body {
+		lines = append(lines, format(row, func(s string) string { return s }))
+	}
+	lines = append(lines, "", output.Muted(fmt.Sprintf("%d of %d rows", len(body), total)))
+
+	return &preview{
+		lines: lines,
+		details: map[string]interface{}{
+			"columns": header,
+			"rows":    total,
+			"preview": body,
+		},
+	}, nil
+}
+
+var (
+	markdownBold = regexp.MustCompile(`\*\*([^*]+)\*\*|__([^_]+)__`)
+	markdownCode = regexp.MustCompile("`([^`]+)`")
+	markdownLink = regexp.MustCompile(`!?\[([^\]]*)\]\(([^)\s]+)[^)]*\)`)
+	markdownList = regexp.MustCompile(`^(\s*)([-*+]|\d+[.)])\s+(.*)$`)
+)
+
+func previewMarkdown(path string) (*preview, error) {
+	data, err := readPreviewFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var lines []string
+	headings := 0
+	inCode := false
+	for _, line := range strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inCode = !inCode
+			continue
+		}
+		if inCode {
+			lines = append(lines, "    "+output.Muted(line))
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(trimmed, "#"):
+			level := len(trimmed) - len(strings.TrimLeft(trimmed, "#"))
+			text := markdownInline(strings.TrimSpace(trimmed[level:]))
+			headings++
+			if len(lines) > 0 && lines[len(lines)-1] != "" {
+				lines = append(lines, "")
+			}
+			lines = append(lines, output.Accent(text))
+			if level <= 2 {
+				lines = append(lines, output.Rule(utf8.RuneCountInString(stripANSI(text))))
+			}
+		case trimmed == "---" || trimmed == "***" || trimmed == "___":
+			lines = append(lines, output.Rule(40))
+		case strings.HasPrefix(trimmed, ">"):
+			lines = append(lines, output.Muted("│ ")+markdownInline(strings.TrimSpace(strings.TrimPrefix(trimmed, ">"))))
+		case markdownList.MatchString(line):
+			m := markdownList.FindStringSubmatch(line)
+			bullet := "•"
+			if m[2][0] >= '0' && m[2][0] <= '9' {
+				bullet = m[2]
+			}
+			lines = append(lines, m[1]+"  "+bullet+" "+markdownInline(m[3]))
+		default:
+			lines = append(lines, markdownInline(line))
+		}
+	}
+
+	return &preview{lines: lines, details: map[string]interface{}{
+		"lines":    strings.Count(string(data), "\n") + 1,
+		"headings": headings,
+	}}, nil
+}
+
+// markdownInline renders bold, code spans and links
+func markdownInline(s string) string {
+	s = markdownLink.ReplaceAllStringFunc(s, func(m string) string {
+		parts := markdownLink.FindStringSubmatch(m)
+		if parts[1] == "" {
+			return output.Muted(parts[2])
+		}
+		return parts[1] + " " + output.Muted("("+parts[2]+")")
+	})
+	s = markdownCode.ReplaceAllStringFunc(s, func(m string) string {
+		return output.Accent(strings.Trim(m, "`"))
+	})
+	return markdownBold.ReplaceAllStringFunc(s, func(m string) string {
+		return output.Accent(m[2 : len(m)-2])
+	})
+}
+
+var ansiEscape = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+func stripANSI(s string) string {
+	return ansiEscape.ReplaceAllString(s, "")
+}
+
+func previewImage(path string, width int, noThumbnail bool) (*preview, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	defer file.Close()
+
+	img, imgFormat, err := image.Decode(file)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode image: %w (supported: PNG, JPEG, GIF)", err)
+	}
+	bounds := img.Bounds()
+	details := map[string]interface{}{
+		"format": imgFormat,
+		"width":  bounds.Dx(),
+		"height": bounds.Dy(),
+	}
+
+	lines := []string{fmt.Sprintf("%s image, %d × %d pixels", strings.ToUpper(imgFormat), bounds.Dx(), bounds.Dy())}
+	if noThumbnail || color.NoColor || bounds.Empty() {
+		return &preview{lines: lines, details: details}, nil
+	}
+
+	if width <= 0 {
+		width = 80
+		if w, _, err := term.GetSize(int(os.Stdout.Fd())); err == nil && w > 0 {
+			width = min(w-2, 80)
+		}
+	}
+	width = min(width, bounds.Dx())
+	// Each character shows two pixels stacked with the upper half block,
+	// and terminal cells are about twice as high as they are wide
+	height := max(bounds.Dy()*width/bounds.Dx(), 2)
+	height -= height % 2
+
+	lines = append(lines, "")
+	for y := 0; y < height; y += 2 {
+		var b strings.Builder
+		for x := 0; x < width; x++ {
+			px := bounds.Min.X + x*bounds.Dx()/width
+			top := img.At(px, bounds.Min.Y+y*bounds.Dy()/height)
+			bottom := img.At(px, bounds.Min.Y+(y+1)*bounds.Dy()/height)
+			tr, tg, tb, _ := top.RGBA()
+			br, bg, bb, _ := bottom.RGBA()
+			fmt.Fprintf(&b, "\x1b[38;2;%d;%d;%dm\x1b[48;2;%d;%d;%dm▀", tr>>8, tg>>8, tb>>8, br>>8, bg>>8, bb>>8)
+		}
+		b.WriteString("\x1b[0m")
+		lines = append(lines, b.String())
+	}
+	return &preview{lines: lines, details: details}, nil
+}
+
+// archiveEntry is one file in an archive listing
+type archiveEntry struct {
+	Name     string `json:"name"`
+	Size     int64  `json:"size"`
+	Modified string `json:"modified"`
+	Dir      bool   `json:"dir,omitempty"`
+}
+
+func previewArchive(path string) (*preview, error) {
+	var (
+		entries     []archiveEntry
+		archiveType string
+		err         error
+	)
+	if reader, zipErr := zip.OpenReader(path); zipErr == nil {
+		defer reader.Close()
+		archiveType = "zip"
+		for _, f := range reader.File {
+			entries = append(entries, archiveEntry{
+				Name:     f.Name,
+				Size:     int64(f.UncompressedSize64),
+				Modified: f.Modified.Format(time.RFC3339),
+				Dir:      f.FileInfo().IsDir(),
+			})
+		}
+	} else {
+		archiveType, entries, err = readTarEntries(path)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var total int64
+	lines := []string{output.Accent(fmt.Sprintf("%10s  %-20s  %s", "Size", "Modified", "Name")), output.Rule(60)}
+	for _, e := range entries {
+		total += e.Size
+		size := formatSize(e.Size)
+		if e.Dir {
+			size = "-"
+		}
+		modified, _ := time.Parse(time.RFC3339, e.Modified)
+		lines = append(lines, fmt.Sprintf("%10s  %-20s  %s", size, modified.Local().Format("2006-01-02 15:04"), e.Name))
+	}
+	summary := output.Muted(fmt.Sprintf("%d entries, %s uncompressed", len(entries), formatSize(total)))
+	// The summary goes first so that it is not cut off by --lines
+	lines = append([]string{summary, ""}, lines...)
+
+	return &preview{lines: lines, details: map[string]interface{}{
+		"archive":    archiveType,
+		"entries":    entries,
+		"count":      len(entries),
+		"total_size": total,
+	}}, nil
+}
+
+// readTarEntries lists a tar file, gzip-compressed or not
+func readTarEntries(path string) (string, []archiveEntry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	defer file.Close()
+
+	archiveType := "tar"
+	var r io.Reader = bufio.NewReader(file)
+	if gz, err := gzip.NewReader(r); err == nil {
+		defer gz.Close()
+		archiveType = "tar.gz"
+		r = gz
+	} else if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return "", nil, fmt.Errorf("failed to read file: %w", err)
+	} else {
+		r = file
+	}
+
+	var entries []archiveEntry
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			if archiveType == "tar.gz" && len(entries) == 0 {
+				return "", nil, fmt.Errorf("not a tar archive inside the gzip stream (use --as hex)")
+			}
+			return "", nil, fmt.Errorf("not a zip or tar archive: %w (use --as hex)", err)
+		}
+		entries = append(entries, archiveEntry{
+			Name:     header.Name,
+			Size:     header.Size,
+			Modified: header.ModTime.Format(time.RFC3339),
+			Dir:      header.Typeflag == tar.TypeDir,
+		})
+	}
+	return archiveType, entries, nil
+}
+
+func previewText(path string, limit int) (*preview, error) {
+	data, err := readPreviewFile(path)
+	if err != nil {
+		return nil, err
+	}
+	text := strings.TrimRight(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+	lines := strings.Split(text, "\n")
+	head := lines
+	if limit > 0 && len(head) > limit {
+		head = head[:limit]
+	}
+	return &preview{lines: lines, details: map[string]interface{}{
+		"lines": len(lines),
+		"head":  head,
+	}}, nil
+}
+
+// previewHex dumps the first limit lines of 16 bytes (4 KB when limit is 0)
+func previewHex(path string, limit int) (*preview, error) {
+	n := 4096
+	if limit > 0 {
+		n = limit * 16
+	}
+	head, err := readPreviewHead(path, n)
+	if err != nil {
+		return nil, err
+	}
+	dump := strings.TrimRight(hex.Dump(head), "\n")
+	return &preview{lines: strings.Split(dump, "\n"), details: map[string]interface{}{
+		"bytes": len(head),
+		"hex":   hex.EncodeToString(head),
+	}}, nil
+}
@@ -8,20 +8,38 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+	"devkit/internal/i18n"
 	"devkit/internal/output"
+	"devkit/internal/picker"
+	"devkit/internal/platform"
+	"devkit/internal/safety"
+	"devkit/internal/undo"
 )
 
 // renameCmd represents the rename command
 var renameCmd = &cobra.Command{
-	Use:   "rename [pattern] [path]",
+	Use:   "rename [path...]",
 	Short: "Bulk rename files using patterns",
 	Long: `Bulk rename files using regex, prefix, suffix, or case conversion.
 
+Several paths can be given; paths may be glob patterns. --pattern and
+--exclude take doublestar patterns (** matches any number of directories)
+and imply --recursive when they contain **.
+
+Renames that would overwrite a file, because the new name is taken or two
+files would get the same name, are refused before anything is renamed.
+
+--interactive shows the proposed renames as a checklist, so that some can
+be left out before the rest are applied; it replaces the confirmation
+prompt.
+
 Examples:
   devkit file rename --pattern "*.txt" --prefix "backup_" --path ./docs
   devkit file rename --pattern "IMG_*.jpg" --replace "IMG_" "photo_" --path ./images
-  devkit file rename --pattern "*.txt" --case upper --path ./docs --dry-run`,
-	Args: cobra.MaximumNArgs(1),
+  devkit file rename --pattern "*.txt" --case upper --path ./docs --dry-run
+  devkit file rename --pattern "*.log" --suffix "_old" --yes
+  devkit file rename --pattern "*.md" --replace " " --with "-" -r --interactive
+  devkit file rename --pattern "**/*.JPG" --case lower ./photos ./scans`,
 	RunE: runRename,
 }
 
@@ -30,6 +48,8 @@ func init() {
 
 	renameCmd.Flags().StringP("pattern", "p", "*", "File pattern (glob)")
 	renameCmd.Flags().StringP("path", "P", ".", "Path to search in")
+	renameCmd.Flags().StringArray("exclude", nil, "Skip files and directories matching a glob pattern (repeatable)")
+	addIgnoreProfileFlag(renameCmd)
 	renameCmd.Flags().String("prefix", "", "Add prefix to filename")
 	renameCmd.Flags().String("suffix", "", "Add suffix to filename (before extension)")
 	renameCmd.Flags().String("replace", "", "Replace pattern (use with --with)")
@@ -37,6 +57,7 @@ func init() {
 	renameCmd.Flags().String("case", "", "Case conversion: lower, upper, title")
 	renameCmd.Flags().BoolP("recursive", "r", false, "Search recursively")
 	renameCmd.Flags().BoolP("dry-run", "d", false, "Show what would be renamed without making changes")
+	renameCmd.Flags().BoolP("interactive", "i", false, "Choose which of the proposed renames to apply")
 	renameCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
 }
 
@@ -48,38 +69,30 @@ func runRename(cmd *cobra.Command, args []string) error {
 	replacePattern, _ := cmd.Flags().GetString("replace")
 	replaceWith, _ := cmd.Flags().GetString("with")
 	caseConv, _ := cmd.Flags().GetString("case")
-	recursive, _ := cmd.Flags().GetBool("recursive")
 	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	interactive, _ := cmd.Flags().GetBool("interactive")
 	outputFormat, _ := cmd.Flags().GetString("output")
 	format := output.OutputFormat(outputFormat)
 
-	if len(args) > 0 {
-		searchPath = args[0]
+	if interactive && dryRun {
+		return fmt.Errorf("--interactive and --dry-run cannot be used together")
+	}
+	if interactive && !picker.Available() {
+		return fmt.Errorf("--interactive needs a terminal")
 	}
 
-	var results []map[string]interface{}
-
-	err := filepath.Walk(searchPath, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return nil
-		}
-
-		if info.IsDir() {
-			if !recursive && path != searchPath {
-				return filepath.SkipDir
-			}
-			return nil
-		}
+	fileMatcher, roots, err := newFileMatcher(cmd, args, searchPath)
+	if err != nil {
+		return err
+	}
 
-		matched, err := filepath.Match(pattern, info.Name())
-		if err != nil {
-			return err
-		}
+	if err := safety.CheckDryRun(cmd, args, dryRun); err != nil {
+		return err
+	}
 
-		if !matched {
-			return nil
-		}
+	var results []map[string]interface{}
 
+	err = fileMatcher.Walk(roots, func(path string, info os.FileInfo) error {
 		dir := filepath.Dir(path)
 		oldName := info.Name()
 		ext := filepath.Ext(oldName)
@@ -116,19 +129,17 @@ func runRename(cmd *cobra.Command, args []string) error {
 		}
 
 		if newName != oldName {
+			if err := platform.ValidateFileName(newName); err != nil {
+				return err
+			}
 			newPath := filepath.Join(dir, newName)
 			result := map[string]interface{}{
-				"old": oldName,
-				"new": newName,
-				"path": path,
+				"old":      oldName,
+				"new":      newName,
+				"path":     path,
+				"new_path": newPath,
 			}
 			results = append(results, result)
-
-			if !dryRun {
-				if err := os.Rename(path, newPath); err != nil {
-					return fmt.Errorf("failed to rename %s: %w", path, err)
-				}
-			}
 		}
 
 		return nil
@@ -137,14 +148,69 @@ func runRename(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("rename error: %w", err)
 	}
+	cmd.SilenceUsage = true
+	if err := checkRenameTargets(results); err != nil {
+		return err
+	}
+
+	skipped := 0
+	if interactive && len(results) > 0 {
+		labels := make([]string, len(results))
+		for i, result := range results {
+			labels[i] = fmt.Sprintf("%s -> %s", result["path"], result["new"])
+		}
+		selected, err := picker.Checklist(i18n.T("picker.renames"), labels)
+		if err != nil {
+			return err
+		}
+		chosen := make([]map[string]interface{}, 0, len(selected))
+		for _, i := range selected {
+			chosen = append(chosen, results[i])
+		}
+		skipped = len(results) - len(chosen)
+		results = chosen
+	}
+
+	var undoID string
+	if !dryRun && len(results) > 0 {
+		if !interactive {
+			if err := safety.ConfirmChanges(safety.RenameFiles, len(results)); err != nil {
+				return err
+			}
+		}
+		op, err := undo.Begin(cmd.CommandPath())
+		if err != nil {
+			return err
+		}
+		for _, result := range results {
+			oldPath, newPath := result["path"].(string), result["new_path"].(string)
+			// The target may have appeared since the plan was made
+			if err := checkRenameTarget(oldPath, newPath); err != nil {
+				op.Commit()
+				return err
+			}
+			if err := os.Rename(oldPath, newPath); err != nil {
+				op.Commit()
+				return fmt.Errorf("failed to rename %s: %w", oldPath, err)
+			}
+			op.RecordRename(oldPath, newPath)
+		}
+		if err := op.Commit(); err != nil {
+			return err
+		}
+		undoID = op.ID
+	}
 
 	if format == output.FormatJSON {
 		output.PrintSuccess(format, map[string]interface{}{
 			"pattern": pattern,
-			"path":    searchPath,
+			"path":    roots[0],
+			"paths":   roots,
 			"renames": results,
 			"count":   len(results),
+			"skipped": skipped,
 			"dry_run": dryRun,
+			"undo_id": undoID,
 		})
 	} else {
 		if dryRun {
@@ -154,7 +220,44 @@ func runRename(cmd *cobra.Command, args []string) error {
 			fmt.Printf("Rename: %s -> %s\n", result["old"], result["new"])
 		}
 		fmt.Printf("\nTotal: %d files renamed\n", len(results))
+		if skipped > 0 {
+			fmt.Printf("Skipped: %d files\n", skipped)
+		}
+		if undoID != "" {
+			fmt.Printf("Undo with: devkit undo %s\n", undoID)
+		}
 	}
 
 	return nil
 }
+
+// checkRenameTargets refuses a plan that would overwrite a file: two files
+// renamed to the same name, or a new name that is already taken. The
+// overwritten file would be lost, as the undo journal only records renames.
+func checkRenameTargets(results []map[string]interface{}) error {
+	sources := make(map[string]string, len(results))
+	for _, result := range results {
+		oldPath, newPath := result["path"].(string), result["new_path"].(string)
+		if other, ok := sources[newPath]; ok {
+			return fmt.Errorf("%s and %s would both be renamed to %s", other, oldPath, newPath)
+		}
+		sources[newPath] = oldPath
+		if err := checkRenameTarget(oldPath, newPath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkRenameTarget fails when newPath exists and is not oldPath itself,
+// which it is for a change of case on a case-insensitive file system
+func checkRenameTarget(oldPath, newPath string) error {
+	target, err := os.Lstat(newPath)
+	if err != nil {
+		return nil
+	}
+	if source, err := os.Lstat(oldPath); err == nil && os.SameFile(source, target) {
+		return nil
+	}
+	return fmt.Errorf("cannot rename %s: %s already exists", oldPath, newPath)
+}
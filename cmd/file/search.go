@@ -4,25 +4,34 @@ import (
 	"bufio"
 	"fmt"
 	"os"
-	"path/filepath"
 	"regexp"
-	"strings"
 
-	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 	"devkit/internal/output"
 )
 
 // searchCmd represents the search command
 var searchCmd = &cobra.Command{
-	Use:   "search [pattern] [path]",
+	Use:   "search [pattern] [path...]",
 	Short: "Search for text in files",
 	Long: `Search for text patterns in files with colored output and line numbers.
 
+Several paths can be given; paths may be glob patterns. --include and
+--exclude take doublestar patterns (** matches any number of directories)
+and imply --recursive when they contain **.
+
+--vimgrep prints one path:line:column: text line per match, for Vim's
+quickfix list (vim -q todo.qf), VS Code problem matchers and Emacs
+compilation buffers.
+
 Examples:
   devkit file search "TODO" .
   devkit file search "function" ./src --recursive
-  devkit file search "error" . --extensions "go,js" --ignore "node_modules"`,
+  devkit file search "error" . --extensions "go,js" --ignore "node_modules"
+  devkit file search "TODO" ./cmd ./internal --include "**/*.go" --exclude "*_gen.go"
+  devkit file search "panic" 'pkg/**/*.go'
+  devkit file search "password" . -r --max-size 1MB --modified-since 7d
+  devkit file search "TODO|FIXME" . -r --regex --vimgrep > todo.qf`,
 	Args: cobra.MinimumNArgs(1),
 	RunE: runSearch,
 }
@@ -34,27 +43,32 @@ func init() {
 	searchCmd.Flags().BoolP("recursive", "r", false, "Search recursively")
 	searchCmd.Flags().String("extensions", "", "File extensions to search (comma-separated)")
 	searchCmd.Flags().String("ignore", "", "Directories to ignore (comma-separated)")
+	searchCmd.Flags().StringArray("include", nil, "Only search files matching a glob pattern (repeatable)")
+	searchCmd.Flags().StringArray("exclude", nil, "Skip files and directories matching a glob pattern (repeatable)")
+	addIgnoreProfileFlag(searchCmd)
+	addFileFilterFlags(searchCmd)
+	searchCmd.Flags().BoolP("follow-symlinks", "L", false, "Follow symbolic links (loops are detected and skipped)")
 	searchCmd.Flags().BoolP("case-sensitive", "c", false, "Case-sensitive search")
 	searchCmd.Flags().BoolP("regex", "e", false, "Use regex pattern")
+	searchCmd.Flags().Bool("vimgrep", false, "Print path:line:column: text lines for editors")
 	searchCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
 }
 
 func runSearch(cmd *cobra.Command, args []string) error {
 	pattern := args[0]
 	searchPath, _ := cmd.Flags().GetString("path")
-	recursive, _ := cmd.Flags().GetBool("recursive")
-	extensions, _ := cmd.Flags().GetString("extensions")
-	ignore, _ := cmd.Flags().GetString("ignore")
 	caseSensitive, _ := cmd.Flags().GetBool("case-sensitive")
 	useRegex, _ := cmd.Flags().GetBool("regex")
+	vimgrep, _ := cmd.Flags().GetBool("vimgrep")
 	outputFormat, _ := cmd.Flags().GetString("output")
 	format := output.OutputFormat(outputFormat)
 
-	extList := strings.Split(extensions, ",")
-	ignoreList := strings.Split(ignore, ",")
+	fileMatcher, roots, err := newFileMatcher(cmd, args[1:], searchPath)
+	if err != nil {
+		return err
+	}
 
 	var searchPattern *regexp.Regexp
-	var err error
 
 	if useRegex {
 		if caseSensitive {
@@ -75,40 +89,9 @@ func runSearch(cmd *cobra.Command, args []string) error {
 	}
 
 	var results []map[string]interface{}
+	var locations []output.Location
 
-	err = filepath.Walk(searchPath, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return nil
-		}
-
-		if info.IsDir() {
-			if !recursive && path != searchPath {
-				return filepath.SkipDir
-			}
-			// Check ignore list
-			for _, ignoreDir := range ignoreList {
-				if strings.Contains(path, strings.TrimSpace(ignoreDir)) {
-					return filepath.SkipDir
-				}
-			}
-			return nil
-		}
-
-		// Check extensions
-		if extensions != "" {
-			ext := filepath.Ext(path)
-			found := false
-			for _, e := range extList {
-				if strings.TrimSpace(e) == strings.TrimPrefix(ext, ".") {
-					found = true
-					break
-				}
-			}
-			if !found {
-				return nil
-			}
-		}
-
+	err = fileMatcher.Walk(roots, func(path string, info os.FileInfo) error {
 		file, err := os.Open(path)
 		if err != nil {
 			return nil
@@ -131,6 +114,9 @@ func runSearch(cmd *cobra.Command, args []string) error {
 					"highlighted": highlighted,
 				}
 				results = append(results, result)
+				for _, m := range matches {
+					locations = append(locations, output.Location{File: path, Line: lineNum, Column: m[0] + 1, Message: line})
+				}
 			}
 		}
 
@@ -141,10 +127,16 @@ func runSearch(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("search error: %w", err)
 	}
 
+	if vimgrep {
+		output.PrintLocations(locations)
+		return nil
+	}
+
 	if format == output.FormatJSON {
 		output.PrintSuccess(format, map[string]interface{}{
 			"pattern": pattern,
-			"path":    searchPath,
+			"path":    roots[0],
+			"paths":   roots,
 			"results": results,
 			"count":   len(results),
 		})
@@ -154,10 +146,6 @@ func runSearch(cmd *cobra.Command, args []string) error {
 			return nil
 		}
 
-		yellow := color.New(color.FgYellow).SprintFunc()
-		green := color.New(color.FgGreen).SprintFunc()
-		blue := color.New(color.FgBlue).SprintFunc()
-
 		currentFile := ""
 		for _, result := range results {
 			file := result["file"].(string)
@@ -166,9 +154,9 @@ func runSearch(cmd *cobra.Command, args []string) error {
 
 			if file != currentFile {
 				currentFile = file
-				fmt.Printf("\n%s\n", blue(file))
+				fmt.Printf("\n%s\n", output.Accent(file))
 			}
-			fmt.Printf("  %s:%s %s\n", yellow(fmt.Sprintf("%d", line)), green("│"), content)
+			fmt.Printf("  %s:%s %s\n", output.Warning(fmt.Sprintf("%d", line)), output.Separator(), content)
 		}
 		fmt.Printf("\nFound %d matches\n", len(results))
 	}
@@ -181,13 +169,12 @@ func highlightMatches(line string, matches [][]int) string {
 		return line
 	}
 
-	red := color.New(color.FgRed, color.Bold).SprintFunc()
 	result := ""
 	last := 0
 
 	for _, match := range matches {
 		result += line[last:match[0]]
-		result += red(line[match[0]:match[1]])
+		result += output.Highlight(line[match[0]:match[1]])
 		last = match[1]
 	}
 	result += line[last:]
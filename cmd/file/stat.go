@@ -3,23 +3,34 @@ package file
 import (
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
+	"devkit/internal/i18n"
+	"devkit/internal/matcher"
 	"devkit/internal/output"
+	"devkit/internal/picker"
+	"devkit/internal/platform"
 )
 
 // statCmd represents the stat command
 var statCmd = &cobra.Command{
-	Use:   "stat [file]",
+	Use:   "stat [file...]",
 	Short: "Display detailed file information",
-	Long: `Display detailed information about a file or directory.
+	Long: `Display detailed information about files or directories.
+
+Paths may be glob patterns (** matches any number of directories). With
+more than one path, JSON output lists the entries under "files". Run in a
+terminal without a path to pick a file interactively.
 
 Examples:
   devkit file stat README.md
   devkit file stat /path/to/file
-  devkit file stat .`,
-	Args: cobra.ExactArgs(1),
+  devkit file stat .
+  devkit file stat go.mod go.sum
+  devkit file stat 'cmd/**/*.go' --output json`,
+	Args: picker.MinimumNArgs(1),
 	RunE: runStat,
 }
 
@@ -33,32 +44,69 @@ func runStat(cmd *cobra.Command, args []string) error {
 	outputFormat, _ := cmd.Flags().GetString("output")
 	format := output.OutputFormat(outputFormat)
 
-	path := args[0]
-	info, err := os.Stat(path)
+	args, err := picker.Fill(cmd, args, 1, nil)
 	if err != nil {
-		return fmt.Errorf("failed to get file info: %w", err)
+		return err
 	}
 
-	result := map[string]interface{}{
-		"name":    info.Name(),
-		"size":    info.Size(),
-		"mode":    info.Mode().String(),
-		"mod_time": info.ModTime().Format(time.RFC3339),
-		"is_dir":  info.IsDir(),
+	paths, err := matcher.ExpandPaths(args)
+	if err != nil {
+		return err
 	}
 
-	if !info.IsDir() {
-		result["size_human"] = formatSize(info.Size())
+	var results []map[string]interface{}
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return fmt.Errorf("failed to get file info: %w", err)
+		}
+
+		result := map[string]interface{}{
+			"name":    info.Name(),
+			"size":    info.Size(),
+			"mode":    info.Mode().String(),
+			"mod_time": info.ModTime().Format(time.RFC3339),
+			"is_dir":  info.IsDir(),
+		}
+
+		if !info.IsDir() {
+			result["size_human"] = formatSize(info.Size())
+		}
+		if attrs := platform.Attributes(info); len(attrs) > 0 {
+			result["attributes"] = attrs
+		}
+		if len(paths) > 1 {
+			result["path"] = path
+		}
+		results = append(results, result)
 	}
 
 	if format == output.FormatJSON {
-		output.PrintSuccess(format, result)
+		if len(results) == 1 {
+			output.PrintSuccess(format, results[0])
+		} else {
+			output.PrintSuccess(format, map[string]interface{}{
+				"files": results,
+				"count": len(results),
+			})
+		}
 	} else {
-		fmt.Printf("Name: %s\n", result["name"])
-		fmt.Printf("Size: %d bytes (%s)\n", result["size"], result["size_human"])
-		fmt.Printf("Mode: %s\n", result["mode"])
-		fmt.Printf("Modified: %s\n", result["mod_time"])
-		fmt.Printf("Is Directory: %v\n", result["is_dir"])
+		for i, result := range results {
+			if i > 0 {
+				fmt.Println()
+			}
+			if path, ok := result["path"]; ok {
+				fmt.Printf("%s:\n", path)
+			}
+			fmt.Printf("%s: %s\n", i18n.T("label.name"), result["name"])
+			fmt.Printf("%s: %d %s (%s)\n", i18n.T("label.size"), result["size"], i18n.T("label.bytes"), result["size_human"])
+			fmt.Printf("%s: %s\n", i18n.T("label.mode"), result["mode"])
+			if attrs, ok := result["attributes"].([]string); ok {
+				fmt.Printf("%s: %s\n", i18n.T("label.attributes"), strings.Join(attrs, ", "))
+			}
+			fmt.Printf("%s: %s\n", i18n.T("label.modified"), result["mod_time"])
+			fmt.Printf("%s: %v\n", i18n.T("label.is_directory"), result["is_dir"])
+		}
 	}
 
 	return nil
@@ -3,10 +3,12 @@ package file
 import (
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 
 	"github.com/spf13/cobra"
+	"devkit/internal/matcher"
 	"devkit/internal/output"
 )
 
@@ -16,10 +18,20 @@ var treeCmd = &cobra.Command{
 	Short: "Display directory structure as a tree",
 	Long: `Display directory structure in a tree format.
 
+Symbolic links are shown as "name -> target". With --follow-symlinks,
+linked directories are expanded; a directory that was already shown (for
+example a link pointing to one of its parents) is marked instead of being
+expanded again.
+
+--exclude and --ignore-profile hide files and directories like in the other
+file commands, as do the workspace ignore patterns and profiles.
+
 Examples:
   devkit file tree .
   devkit file tree /path/to/directory
-  devkit file tree . --depth 2`,
+  devkit file tree . --depth 2
+  devkit file tree . --follow-symlinks
+  devkit file tree . --ignore-profile node,build-artifacts --exclude "*.log"`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runTree,
 }
@@ -29,22 +41,42 @@ func init() {
 
 	treeCmd.Flags().IntP("depth", "d", -1, "Maximum depth to traverse (-1 for unlimited)")
 	treeCmd.Flags().BoolP("all", "a", false, "Show hidden files")
+	treeCmd.Flags().BoolP("follow-symlinks", "L", false, "Expand symlinked directories (loops are detected and skipped)")
+	treeCmd.Flags().StringArray("exclude", nil, "Hide files and directories matching a glob pattern (repeatable)")
+	addIgnoreProfileFlag(treeCmd)
 	treeCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
 }
 
 func runTree(cmd *cobra.Command, args []string) error {
 	depth, _ := cmd.Flags().GetInt("depth")
 	showAll, _ := cmd.Flags().GetBool("all")
+	followSymlinks, _ := cmd.Flags().GetBool("follow-symlinks")
 	outputFormat, _ := cmd.Flags().GetString("output")
 	format := output.OutputFormat(outputFormat)
 
-	root := "."
+	opts, root, err := fileMatcherOptions(cmd, ".")
+	if err != nil {
+		return err
+	}
 	if len(args) > 0 {
 		root = args[0]
 	}
+	m, err := matcher.New(opts)
+	if err != nil {
+		return err
+	}
 
 	var tree []string
-	err := buildTree(root, "", 0, depth, showAll, &tree)
+	t := &treeBuilder{
+		maxDepth:       depth,
+		showAll:        showAll,
+		followSymlinks: followSymlinks,
+		matcher:        m,
+		visited:        make(map[string]bool),
+		tree:           &tree,
+	}
+	t.markVisited(root)
+	err = t.build(root, "", "", 0)
 	if err != nil {
 		return fmt.Errorf("failed to build tree: %w", err)
 	}
@@ -63,8 +95,35 @@ func runTree(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func buildTree(root, prefix string, level, maxDepth int, showAll bool, tree *[]string) error {
-	if maxDepth >= 0 && level >= maxDepth {
+// treeBuilder renders a directory tree, remembering the directories already
+// expanded so that symlink loops terminate
+type treeBuilder struct {
+	maxDepth       int
+	showAll        bool
+	followSymlinks bool
+	matcher        *matcher.Matcher
+	visited        map[string]bool
+	tree           *[]string
+}
+
+// markVisited records dir and reports whether it was not expanded before
+func (t *treeBuilder) markVisited(dir string) bool {
+	real, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return false
+	}
+	if real, err = filepath.Abs(real); err != nil {
+		return false
+	}
+	if t.visited[real] {
+		return false
+	}
+	t.visited[real] = true
+	return true
+}
+
+func (t *treeBuilder) build(root, relDir, prefix string, level int) error {
+	if t.maxDepth >= 0 && level >= t.maxDepth {
 		return nil
 	}
 
@@ -73,35 +132,61 @@ func buildTree(root, prefix string, level, maxDepth int, showAll bool, tree *[]s
 		return err
 	}
 
-	// Filter hidden files
+	// Filter hidden and excluded files
 	filtered := []os.DirEntry{}
 	for _, entry := range entries {
-		if showAll || !strings.HasPrefix(entry.Name(), ".") {
-			filtered = append(filtered, entry)
+		if !t.showAll && strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		if t.matcher.Excluded(path.Join(relDir, entry.Name()), entry.IsDir()) {
+			continue
 		}
+		filtered = append(filtered, entry)
 	}
 
 	for i, entry := range filtered {
 		isLast := i == len(filtered)-1
 		name := entry.Name()
+		rel := path.Join(relDir, name)
+		path := filepath.Join(root, name)
+
+		isDir := entry.IsDir()
+		label := name
+		if entry.Type()&os.ModeSymlink != 0 {
+			if target, err := os.Readlink(path); err == nil {
+				label = name + " -> " + target
+			}
+			if t.followSymlinks {
+				if info, err := os.Stat(path); err == nil {
+					isDir = info.IsDir()
+				}
+			} else {
+				isDir = false
+			}
+		}
+
+		expand := isDir
+		if isDir && !t.markVisited(path) {
+			label += " [already shown]"
+			expand = false
+		}
 
 		var connector string
 		if isLast {
 			connector = "└── "
-			*tree = append(*tree, prefix+connector+name)
 		} else {
 			connector = "├── "
-			*tree = append(*tree, prefix+connector+name)
 		}
+		*t.tree = append(*t.tree, prefix+connector+label)
 
-		if entry.IsDir() {
+		if expand {
 			var nextPrefix string
 			if isLast {
 				nextPrefix = prefix + "    "
 			} else {
 				nextPrefix = prefix + "│   "
 			}
-			buildTree(filepath.Join(root, name), nextPrefix, level+1, maxDepth, showAll, tree)
+			t.build(path, rel, nextPrefix, level+1)
 		}
 	}
 
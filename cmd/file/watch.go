@@ -1,14 +1,25 @@
 package file
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/fsnotify/fsnotify"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"devkit/internal/errors"
+	"devkit/internal/i18n"
+	"devkit/internal/matcher"
+	"devkit/internal/output"
+	"devkit/internal/platform"
+	"devkit/internal/utils"
 )
 
 // watchCmd represents the watch command
@@ -17,10 +28,35 @@ var watchCmd = &cobra.Command{
 	Short: "Watch files for changes",
 	Long: `Watch files and directories for changes and execute commands.
 
+The --on-change command runs through sh, or cmd.exe on Windows. Use --shell
+to pick another shell (bash, zsh, cmd, powershell, pwsh), or --shell none
+to run the program directly without a shell.
+
+With --pipeline, the directory becomes a hot folder: every new file that
+matches --pattern is handed to a devkit file operation once it has stopped
+changing for --settle. The file path is appended to the operation, or
+replaces the placeholders {}, {/}, {//}, {.} and {/.} as in file find.
+Files the operation fails on are moved to --quarantine (default: .failed
+in the watched directory); with --done-dir, processed files are moved
+there. Each file produces one JSON log record, appended to --log and
+printed on stdout with --output json.
+
+Output the operation writes inside the watched directory, such as the
+--out-dir or --output of convert, is not processed again. An --out-dir
+that contains the watched files themselves, e.g. --out-dir . or {//},
+would feed the pipeline its own output and is refused.
+
+--exclude and --ignore-profile skip files and whole directories like in the
+other file commands, as do the workspace ignore patterns and profiles.
+
 Examples:
   devkit file watch ./src
   devkit file watch ./src --on-change "go build"
-  devkit file watch . --pattern "*.go" --on-change "go test ./..."`,
+  devkit file watch . --pattern "*.go" --on-change "go test ./..."
+  devkit file watch . --ignore-profile node --exclude "*.tmp" --on-change "npm run build"
+  devkit file watch . --on-change "Get-Date" --shell powershell
+  devkit file watch ./inbox --pipeline 'convert --to json --out-dir ./processed'
+  devkit file watch ./inbox --pattern '*.yaml' --pipeline 'convert --to json --out-dir ./processed' --done-dir ./archive --log watch.jsonl`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runWatch,
 }
@@ -30,18 +66,86 @@ func init() {
 
 	watchCmd.Flags().StringP("pattern", "p", "*", "File pattern to watch")
 	watchCmd.Flags().String("on-change", "", "Command to execute on file change")
+	watchCmd.Flags().String("shell", "", fmt.Sprintf("Shell for --on-change: %s (default %s)", strings.Join(platform.Shells, ", "), platform.DefaultShell()))
 	watchCmd.Flags().BoolP("recursive", "r", true, "Watch recursively")
+	watchCmd.Flags().StringArray("exclude", nil, "Skip files and directories matching a glob pattern (repeatable)")
+	addIgnoreProfileFlag(watchCmd)
+	watchCmd.Flags().String("pipeline", "", "devkit file operation to apply to every new file, e.g. 'convert --to json --out-dir ./processed'")
+	watchCmd.Flags().String("quarantine", "", "With --pipeline, directory for files the operation failed on (default: <path>/.failed)")
+	watchCmd.Flags().String("done-dir", "", "With --pipeline, directory to move processed files to (default: leave them)")
+	watchCmd.Flags().Duration("settle", time.Second, "With --pipeline, how long a new file must stay unchanged before it is processed")
+	watchCmd.Flags().Bool("existing", false, "With --pipeline, also process the files already in the directory")
+	watchCmd.Flags().String("log", "", "With --pipeline, file to append JSON log records to")
+	watchCmd.Flags().StringP("output", "o", "plain", "Output format for --pipeline: plain, json")
+}
+
+// watchRecord is the log record of one file handled by --pipeline
+type watchRecord struct {
+	Time       string `json:"time"`
+	File       string `json:"file"`
+	Status     string `json:"status"`
+	DurationMS int64  `json:"duration_ms"`
+	ExitCode   int    `json:"exit_code"`
+	Output     string `json:"output,omitempty"`
+	Error      string `json:"error,omitempty"`
+	MovedTo    string `json:"moved_to,omitempty"`
+}
+
+// watchPipeline is the state of a hot folder run by --pipeline
+type watchPipeline struct {
+	command    []string
+	op         *cobra.Command
+	self       string
+	root       string
+	matcher    *matcher.Matcher
+	quarantine string
+	doneDir    string
+	settle     time.Duration
+	log        *os.File
+	json       bool
+	// written are the output files and directories of the operation that
+	// are inside the watched directory
+	written []string
+}
+
+// watchOutputFlags are the flags through which file operations write
+// files, so that --pipeline can tell its own output from new files
+var watchOutputFlags = map[string]struct{ dirs, files []string }{
+	"convert": {dirs: []string{"out-dir"}, files: []string{"output"}},
 }
 
 func runWatch(cmd *cobra.Command, args []string) error {
-	watchPath := "."
+	opts, watchPath, err := fileMatcherOptions(cmd, ".")
+	if err != nil {
+		return err
+	}
 	if len(args) > 0 {
 		watchPath = args[0]
 	}
+	m, err := matcher.New(opts)
+	if err != nil {
+		return err
+	}
 
 	pattern, _ := cmd.Flags().GetString("pattern")
 	onChange, _ := cmd.Flags().GetString("on-change")
 	recursive, _ := cmd.Flags().GetBool("recursive")
+	shell, _ := cmd.Flags().GetString("shell")
+	pipeline, _ := cmd.Flags().GetString("pipeline")
+
+	if pipeline != "" {
+		if onChange != "" {
+			return fmt.Errorf("--on-change and --pipeline cannot be used together")
+		}
+		return runWatchPipeline(cmd, m, watchPath, pattern, recursive, pipeline)
+	}
+
+	// Check the shell up front rather than on the first change
+	if onChange != "" {
+		if _, err := platform.ShellCommand(shell, onChange); err != nil {
+			return err
+		}
+	}
 
 	watcher, err := fsnotify.NewWatcher()
 	if err != nil {
@@ -56,6 +160,9 @@ func runWatch(cmd *cobra.Command, args []string) error {
 				return nil
 			}
 			if info.IsDir() {
+				if path != watchPath && m.Excluded(watchRel(watchPath, path), true) {
+					return filepath.SkipDir
+				}
 				return watcher.Add(path)
 			}
 			return nil
@@ -72,7 +179,8 @@ func runWatch(cmd *cobra.Command, args []string) error {
 	if onChange != "" {
 		fmt.Printf("On change: %s\n", onChange)
 	}
-	fmt.Println("Press Ctrl+C to stop...\n")
+	fmt.Println("Press Ctrl+C to stop...")
+	fmt.Println()
 
 	done := make(chan bool)
 	go func() {
@@ -83,8 +191,7 @@ func runWatch(cmd *cobra.Command, args []string) error {
 					return
 				}
 
-				matched, _ := filepath.Match(pattern, filepath.Base(event.Name))
-				if !matched {
+				if !m.Match(watchRel(watchPath, event.Name)) {
 					continue
 				}
 
@@ -92,7 +199,7 @@ func runWatch(cmd *cobra.Command, args []string) error {
 					fmt.Printf("[%s] Modified: %s\n", time.Now().Format("15:04:05"), event.Name)
 
 					if onChange != "" {
-						cmd := exec.Command("sh", "-c", onChange)
+						cmd, _ := platform.ShellCommand(shell, onChange)
 						cmd.Stdout = os.Stdout
 						cmd.Stderr = os.Stderr
 						if err := cmd.Run(); err != nil {
@@ -113,3 +220,420 @@ func runWatch(cmd *cobra.Command, args []string) error {
 	<-done
 	return nil
 }
+
+func runWatchPipeline(cmd *cobra.Command, m *matcher.Matcher, watchPath, pattern string, recursive bool, pipeline string) error {
+	quarantine, _ := cmd.Flags().GetString("quarantine")
+	doneDir, _ := cmd.Flags().GetString("done-dir")
+	settle, _ := cmd.Flags().GetDuration("settle")
+	existing, _ := cmd.Flags().GetBool("existing")
+	logPath, _ := cmd.Flags().GetString("log")
+	outputFormat, _ := cmd.Flags().GetString("output")
+	format := output.OutputFormat(outputFormat)
+
+	command, err := utils.SplitArgs(pipeline)
+	if err != nil {
+		return fmt.Errorf("invalid --pipeline: %w", err)
+	}
+	// "devkit file convert ..." and "file convert ..." mean "convert ..."
+	if len(command) > 0 && command[0] == "devkit" {
+		command = command[1:]
+	}
+	if len(command) > 0 && command[0] == "file" {
+		command = command[1:]
+	}
+	if len(command) == 0 {
+		return fmt.Errorf("--pipeline cannot be empty")
+	}
+	op, _, err := fileCmd.Find(command)
+	if err != nil || op == fileCmd || op.Name() == "watch" {
+		return fmt.Errorf("invalid --pipeline: %s is not a file operation", command[0])
+	}
+	if _, err := filepath.Match(pattern, ""); err != nil {
+		return fmt.Errorf("invalid pattern: %w", err)
+	}
+	if settle < 0 {
+		return fmt.Errorf("--settle cannot be negative")
+	}
+
+	info, err := os.Stat(watchPath)
+	if err != nil {
+		return fmt.Errorf("failed to watch %s: %w", watchPath, err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory (--pipeline watches a directory)", watchPath)
+	}
+
+	self, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("cannot find the devkit executable: %w", err)
+	}
+	if quarantine == "" {
+		quarantine = filepath.Join(watchPath, ".failed")
+	}
+	p := &watchPipeline{
+		command: append([]string{"file"}, command...),
+		op:      op,
+		self:    self,
+		root:    watchPath,
+		matcher: m,
+		settle:  settle,
+		json:    format == output.FormatJSON,
+	}
+	if p.quarantine, err = filepath.Abs(quarantine); err != nil {
+		return err
+	}
+	if doneDir != "" {
+		if p.doneDir, err = filepath.Abs(doneDir); err != nil {
+			return err
+		}
+		if err := os.MkdirAll(p.doneDir, 0755); err != nil {
+			return fmt.Errorf("failed to create done directory: %w", err)
+		}
+	}
+	if err := p.checkOutputs(); err != nil {
+		return err
+	}
+	if logPath != "" {
+		if p.log, err = os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644); err != nil {
+			return fmt.Errorf("failed to open log file: %w", err)
+		}
+		defer p.log.Close()
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create watcher: %w", err)
+	}
+	defer watcher.Close()
+
+	// pending holds new files until they have stopped changing
+	pending := make(map[string]*watchPending)
+	if err := p.add(watcher, watchPath, recursive, existing, pending); err != nil {
+		return fmt.Errorf("failed to add path to watcher: %w", err)
+	}
+
+	if !p.json {
+		fmt.Printf("Watching: %s (pattern: %s)\n", watchPath, pattern)
+		fmt.Printf("Pipeline: devkit %s\n", strings.Join(p.command, " "))
+		fmt.Printf("Quarantine: %s\n", p.quarantine)
+		fmt.Println("Press Ctrl+C to stop...")
+		fmt.Println()
+	}
+
+	tick := time.NewTicker(min(max(settle/4, 50*time.Millisecond), time.Second))
+	defer tick.Stop()
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if event.Op&(fsnotify.Create|fsnotify.Write) == 0 || p.skipped(event.Name) {
+				continue
+			}
+			info, err := os.Stat(event.Name)
+			if err != nil {
+				continue
+			}
+			if info.IsDir() {
+				// Files in a new directory are new as well
+				if recursive && event.Op&fsnotify.Create != 0 {
+					p.add(watcher, event.Name, true, true, pending)
+				}
+				continue
+			}
+			p.queue(event.Name, info, pending)
+
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			fmt.Fprintf(os.Stderr, "Watcher error: %v\n", err)
+
+		case now := <-tick.C:
+			for path, f := range pending {
+				info, err := os.Stat(path)
+				if err != nil {
+					delete(pending, path)
+					continue
+				}
+				if info.Size() != f.size || !info.ModTime().Equal(f.modified) {
+					f.size, f.modified, f.changed = info.Size(), info.ModTime(), now
+					continue
+				}
+				if now.Sub(f.changed) >= p.settle {
+					delete(pending, path)
+					p.process(path)
+				}
+			}
+		}
+	}
+}
+
+// watchPending is a new file waiting to settle
+type watchPending struct {
+	size     int64
+	modified time.Time
+	changed  time.Time
+}
+
+// add watches dir (and its subdirectories when recursive). With existing,
+// the files already in it are queued as if they had just appeared.
+func (p *watchPipeline) add(watcher *fsnotify.Watcher, dir string, recursive, existing bool, pending map[string]*watchPending) error {
+	return filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info.IsDir() {
+			if path != dir && (!recursive || p.skipped(path) || strings.HasPrefix(info.Name(), ".") || p.matcher.Excluded(watchRel(p.root, path), true)) {
+				return filepath.SkipDir
+			}
+			return watcher.Add(path)
+		}
+		if existing && !p.skipped(path) {
+			p.queue(path, info, pending)
+		}
+		return nil
+	})
+}
+
+func (p *watchPipeline) queue(path string, info os.FileInfo, pending map[string]*watchPending) {
+	if !p.matcher.Match(watchRel(p.root, path)) || !info.Mode().IsRegular() {
+		return
+	}
+	if f, ok := pending[path]; ok {
+		f.size, f.modified, f.changed = info.Size(), info.ModTime(), time.Now()
+		return
+	}
+	pending[path] = &watchPending{size: info.Size(), modified: info.ModTime(), changed: time.Now()}
+}
+
+// watchRel returns path relative to the watched root, slash-separated, as
+// the matcher expects; a watched file is matched by its name
+func watchRel(root, path string) string {
+	rel, err := filepath.Rel(root, path)
+	if err != nil || rel == "." {
+		return filepath.Base(path)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// skipped reports whether path is in the quarantine or done directory or
+// was written by the operation, whose files must not be processed again
+func (p *watchPipeline) skipped(path string) bool {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+	for _, dir := range append([]string{p.quarantine, p.doneDir}, p.written...) {
+		if dir != "" && watchWithin(abs, dir) {
+			return true
+		}
+	}
+	return false
+}
+
+// watchWithin reports whether path is dir or inside it; both are absolute
+func watchWithin(path, dir string) bool {
+	return path == dir || strings.HasPrefix(path, strings.TrimSuffix(dir, string(filepath.Separator))+string(filepath.Separator))
+}
+
+// outputs returns the absolute paths of the directories and files argv
+// makes the operation write to
+func (p *watchPipeline) outputs(argv []string) (dirs, files []string) {
+	spec, ok := watchOutputFlags[p.op.Name()]
+	if !ok {
+		return nil, nil
+	}
+	// Only the output flags are parsed; the operation checks the rest
+	fs := pflag.NewFlagSet(p.op.Name(), pflag.ContinueOnError)
+	fs.ParseErrorsAllowlist.UnknownFlags = true
+	fs.SetOutput(io.Discard)
+	values := make(map[string]*string)
+	for _, name := range append(append([]string{}, spec.dirs...), spec.files...) {
+		if f := p.op.Flags().Lookup(name); f != nil {
+			values[name] = fs.StringP(name, f.Shorthand, "", "")
+		}
+	}
+	fs.Parse(argv[2:])
+
+	resolve := func(names []string) []string {
+		var paths []string
+		for _, name := range names {
+			if v, ok := values[name]; ok && *v != "" {
+				if abs, err := filepath.Abs(*v); err == nil {
+					paths = append(paths, abs)
+				}
+			}
+		}
+		return paths
+	}
+	return resolve(spec.dirs), resolve(spec.files)
+}
+
+// checkOutputs refuses an operation that writes into the directory of the
+// files it processes, and skips its fixed outputs inside the watched
+// directory from the start
+func (p *watchPipeline) checkOutputs() error {
+	root, err := filepath.Abs(p.root)
+	if err != nil {
+		return err
+	}
+	// A file in the watched directory stands in for the files to come
+	sample := filepath.Join(root, "file")
+	dirs, _ := p.outputs(expandFindCommand(p.command, sample))
+	for _, dir := range dirs {
+		if watchWithin(sample, dir) {
+			return fmt.Errorf("--pipeline writes its output to %s, where it would be processed again; use an --out-dir outside the watched directory or in a subdirectory of it", dir)
+		}
+	}
+
+	dirs, files := p.outputs(p.command)
+	for _, path := range append(dirs, files...) {
+		if !strings.ContainsAny(path, "{}") {
+			p.skip(path)
+		}
+	}
+	return nil
+}
+
+// skip adds an output path of the operation to the skipped paths when it
+// is inside the watched directory
+func (p *watchPipeline) skip(path string) {
+	root, err := filepath.Abs(p.root)
+	if err != nil || !watchWithin(path, root) || p.skipped(path) {
+		return
+	}
+	p.written = append(p.written, path)
+}
+
+// process runs the pipeline on one file, moves it to the quarantine or
+// done directory and logs the result
+func (p *watchPipeline) process(path string) {
+	argv := expandFindCommand(p.command, path)
+	var stdout, stderr bytes.Buffer
+	proc := exec.Command(p.self, argv...)
+	proc.Stdout = &stdout
+	proc.Stderr = &stderr
+
+	// The output is skipped before the operation writes it, as its events
+	// are only read once the operation has finished
+	dirs, files := p.outputs(argv)
+	var err error
+	if abs, absErr := filepath.Abs(path); absErr == nil {
+		for _, dir := range dirs {
+			if watchWithin(abs, dir) {
+				err = &errors.ExitError{Code: errors.ExitInvalid, Err: fmt.Errorf("output directory %s contains %s, whose output would be processed again", dir, path)}
+			}
+		}
+	}
+	for _, out := range append(dirs, files...) {
+		p.skip(out)
+	}
+
+	start := time.Now()
+	if err == nil {
+		err = proc.Run()
+	}
+	record := watchRecord{
+		Time:       start.Format(time.RFC3339),
+		File:       path,
+		Status:     "ok",
+		DurationMS: time.Since(start).Milliseconds(),
+		Output:     strings.TrimSpace(stdout.String()),
+	}
+
+	dest := p.doneDir
+	if err != nil {
+		record.Status = "failed"
+		record.ExitCode = errors.ExitFailure
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			record.ExitCode = exitErr.ExitCode()
+			record.Error = pipelineError(stderr.String())
+		} else if exitErr, ok := err.(*errors.ExitError); ok {
+			record.ExitCode = exitErr.Code
+			record.Error = exitErr.Err.Error()
+		} else {
+			record.Error = err.Error()
+		}
+		dest = p.quarantine
+	}
+
+	if dest != "" {
+		moved, err := moveIntoDir(path, dest)
+		if err != nil && record.Error != "" {
+			record.Error += "; " + err.Error()
+		} else if err != nil {
+			record.Error = err.Error()
+		} else {
+			record.MovedTo = moved
+		}
+	}
+	p.logRecord(record)
+}
+
+// pipelineError returns the error message from the stderr of a failed
+// operation without the usage that follows it, or all of stderr when there
+// is no error line
+func pipelineError(stderr string) string {
+	prefix := i18n.T("error.prefix")
+	for _, line := range strings.Split(stderr, "\n") {
+		if msg, ok := strings.CutPrefix(strings.TrimSpace(line), prefix); ok {
+			return strings.TrimSpace(msg)
+		}
+	}
+	return strings.TrimSpace(stderr)
+}
+
+func (p *watchPipeline) logRecord(record watchRecord) {
+	data, _ := json.Marshal(record)
+	if p.log != nil {
+		p.log.Write(append(data, '\n'))
+	}
+	if p.json {
+		fmt.Println(string(data))
+		return
+	}
+
+	stamp := fmt.Sprintf("[%s] ", time.Now().Format("15:04:05"))
+	if record.Status == "ok" {
+		line := fmt.Sprintf("%s (%dms)", record.File, record.DurationMS)
+		if record.MovedTo != "" {
+			line += " -> " + record.MovedTo
+		}
+		fmt.Println(stamp + output.OK(line))
+		if record.Output != "" {
+			fmt.Println(output.Muted(record.Output))
+		}
+		if record.Error != "" {
+			fmt.Println(output.Warn(record.Error))
+		}
+		return
+	}
+	line := fmt.Sprintf("%s (exit %d)", record.File, record.ExitCode)
+	if record.MovedTo != "" {
+		line += " quarantined in " + record.MovedTo
+	}
+	fmt.Println(stamp + output.Fail(line))
+	if record.Error != "" {
+		fmt.Println(output.Muted(record.Error))
+	}
+}
+
+// moveIntoDir moves path into dir, adding a timestamp to the name when a
+// file of that name is already there, and returns the new path
+func moveIntoDir(path, dir string) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+	name := filepath.Base(path)
+	dest := filepath.Join(dir, name)
+	if _, err := os.Stat(dest); err == nil {
+		ext := filepath.Ext(name)
+		dest = filepath.Join(dir, fmt.Sprintf("%s.%s%s", strings.TrimSuffix(name, ext), time.Now().Format("20060102-150405.000"), ext))
+	}
+	if err := os.Rename(path, dest); err != nil {
+		return "", fmt.Errorf("failed to move %s: %w", path, err)
+	}
+	return dest, nil
+}
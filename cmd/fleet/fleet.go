@@ -0,0 +1,40 @@
+package fleet
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// fleetCmd represents the fleet command group
+var fleetCmd = &cobra.Command{
+	Use:   "fleet",
+	Short: "Run devkit commands across many hosts",
+	Long: `Run devkit commands on a list of hosts over SSH and merge the results.
+
+Hosts are listed in a YAML file, with optional defaults and tags:
+  user: deploy              # defaults for every host
+  identity: ~/.ssh/fleet
+  hosts:
+    - web1.example.com
+    - web2.example.com
+    - name: db1
+      host: 10.0.0.5
+      user: admin
+      port: 2222
+      tags: [db]
+
+The binary is installed and reused as with "devkit remote".
+
+Examples:
+  devkit fleet run --hosts hosts.yaml -- net disk /
+  devkit fleet run --hosts hosts.yaml --tag db --sort -percent -- net disk /var/lib`,
+}
+
+// GetFleetCmd returns the fleet command
+func GetFleetCmd() *cobra.Command {
+	return fleetCmd
+}
+
+func init() {
+	// This will be called when the package is imported
+	// Commands will be added in their respective files
+}
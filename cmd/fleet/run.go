@@ -0,0 +1,411 @@
+package fleet
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+	"devkit/internal/errors"
+	"devkit/internal/flatten"
+	"devkit/internal/output"
+	"devkit/internal/sshexec"
+)
+
+// defaultFleetJobs bounds how many hosts run at the same time
+const defaultFleetJobs = 10
+
+// runCmd represents the fleet run subcommand
+var runCmd = &cobra.Command{
+	Use:   "run --hosts hosts.yaml [flags] -- <command> [args...]",
+	Short: "Run a devkit command on every host and merge the results",
+	Long: `Run a devkit command on every host of a hosts file at the same time and
+merge the per-host JSON results into one table or JSON document.
+
+Each host's data becomes one row, with nested fields flattened to dotted
+column names (e.g. tls.version). When the data holds a single list of
+objects, such as the ports of "net port list", each item becomes a row
+instead; --rows picks the list when there are several. Hosts that fail,
+whether unreachable or because the command failed, appear with their error
+and do not stop the others.
+
+--sort orders the rows by one or more columns (prefix with - for
+descending); values starting with a number, like "18.4%" or "79.0 GB",
+compare by that number. --columns selects and orders the columns shown.
+
+The exit status is the highest exit status of the remote commands, or 1 if
+a host could not be reached.
+
+Examples:
+  devkit fleet run --hosts hosts.yaml -- net disk /
+  devkit fleet run --hosts hosts.yaml --sort -percent -- net disk /
+  devkit fleet run --hosts hosts.yaml --tag web -- net ssl expiry example.com
+  devkit fleet run --hosts hosts.yaml --columns port,process --sort host,port -- net port list
+  devkit fleet run --hosts hosts.yaml --output json -- net sysinfo`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runFleetRun,
+}
+
+func init() {
+	fleetCmd.AddCommand(runCmd)
+
+	// Flags after the remote command belong to it
+	runCmd.Flags().SetInterspersed(false)
+
+	runCmd.Flags().String("hosts", "", "Hosts file (YAML) (required)")
+	runCmd.Flags().StringArray("tag", nil, "Only run on hosts with this tag (repeatable; any tag matches)")
+	runCmd.Flags().IntP("jobs", "j", defaultFleetJobs, "Number of hosts run in parallel")
+	runCmd.Flags().String("sort", "", "Sort rows by columns, e.g. -percent or host,port")
+	runCmd.Flags().String("columns", "", "Comma-separated columns to show, in order")
+	runCmd.Flags().String("rows", "", "List field whose items become rows, e.g. ports")
+	runCmd.Flags().StringP("output", "o", "table", "Output format: table, json")
+	sshexec.AddFlags(runCmd)
+	runCmd.MarkFlagRequired("hosts")
+}
+
+// fleetHost is a host of the hosts file. A plain string is a host name.
+type fleetHost struct {
+	Name     string   `yaml:"name"`
+	Host     string   `yaml:"host"`
+	User     string   `yaml:"user"`
+	Port     int      `yaml:"port"`
+	Identity string   `yaml:"identity"`
+	Tags     []string `yaml:"tags"`
+}
+
+func (h *fleetHost) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		h.Host = node.Value
+		return nil
+	}
+	type plain fleetHost
+	return node.Decode((*plain)(h))
+}
+
+// target is the ssh destination of the host
+func (h fleetHost) target() string {
+	if h.User == "" || strings.Contains(h.Host, "@") {
+		return h.Host
+	}
+	return h.User + "@" + h.Host
+}
+
+// fleetConfig is the structure of the hosts file
+type fleetConfig struct {
+	User     string      `yaml:"user"`
+	Port     int         `yaml:"port"`
+	Identity string      `yaml:"identity"`
+	Hosts    []fleetHost `yaml:"hosts"`
+}
+
+// fleetRow is a row of the merged report
+type fleetRow struct {
+	host   int
+	values map[string]string
+}
+
+func runFleetRun(cmd *cobra.Command, args []string) error {
+	hostsFile, _ := cmd.Flags().GetString("hosts")
+	tags, _ := cmd.Flags().GetStringArray("tag")
+	jobs, _ := cmd.Flags().GetInt("jobs")
+	sortFlag, _ := cmd.Flags().GetString("sort")
+	columnsFlag, _ := cmd.Flags().GetString("columns")
+	rowsField, _ := cmd.Flags().GetString("rows")
+	outputFormat, _ := cmd.Flags().GetString("output")
+	format := output.OutputFormat(outputFormat)
+
+	opts, err := sshexec.FromCommand(cmd)
+	if err != nil {
+		return err
+	}
+	cfg, err := loadFleetConfig(hostsFile)
+	if err != nil {
+		return err
+	}
+	hosts := filterFleetHosts(cfg.Hosts, tags)
+	if len(hosts) == 0 {
+		return fmt.Errorf("no hosts in %s have the tags %s", hostsFile, strings.Join(tags, ", "))
+	}
+	if args[0] == "remote" || args[0] == "fleet" {
+		return fmt.Errorf("refusing to run devkit %s on the remote hosts", args[0])
+	}
+	args = sshexec.JSONArgs(args)
+	cmd.SilenceUsage = true
+
+	results := make([]sshexec.Result, len(hosts))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < max(jobs, 1) && w < len(hosts); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				h := hosts[i]
+				hostOpts := opts
+				hostOpts.Port = cmp.Or(h.Port, cfg.Port, opts.Port)
+				hostOpts.Identity = cmp.Or(h.Identity, cfg.Identity, opts.Identity)
+				var stdout, stderr bytes.Buffer
+				r := hostOpts.Exec(h.target(), args, &stdout, &stderr)
+				r.Decode(stdout.Bytes(), stderr.String())
+				r.Host = cmp.Or(h.Name, h.Host)
+				results[i] = r
+			}
+		}()
+	}
+	for i := range hosts {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	rows, columns := fleetRows(results, rowsField)
+	if columnsFlag != "" {
+		columns = strings.Split(columnsFlag, ",")
+		for i := range columns {
+			columns[i] = strings.TrimSpace(columns[i])
+		}
+	}
+	if sortFlag != "" {
+		if err := sortFleetRows(results, rows, sortFlag); err != nil {
+			return err
+		}
+	}
+
+	failed, code := 0, 0
+	for _, r := range results {
+		if r.ExitCode != 0 {
+			failed++
+			code = max(code, r.ExitCode)
+		}
+	}
+
+	if format == output.FormatJSON {
+		merged := make([]map[string]string, len(rows))
+		for i, row := range rows {
+			merged[i] = map[string]string{"host": results[row.host].Host}
+			for _, c := range columns {
+				if v, ok := row.values[c]; ok {
+					merged[i][c] = v
+				}
+			}
+			if e := results[row.host].Error; e != "" {
+				merged[i]["error"] = e
+			}
+		}
+		output.PrintSuccess(format, map[string]interface{}{
+			"command": strings.Join(args, " "),
+			"count":   len(results),
+			"failed":  failed,
+			"columns": columns,
+			"rows":    merged,
+			"results": results,
+		})
+	} else {
+		printFleetTable(results, rows, columns)
+		fmt.Printf("\n%d hosts, %d failed\n", len(results), failed)
+	}
+
+	if failed == 0 {
+		return nil
+	}
+	// The table already shows the errors
+	cmd.SilenceErrors = true
+	return &errors.ExitError{Code: code, Err: fmt.Errorf("command failed on %d of %d hosts", failed, len(results))}
+}
+
+func loadFleetConfig(path string) (*fleetConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read hosts file: %w", err)
+	}
+
+	var cfg fleetConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("invalid hosts file: %w", err)
+	}
+
+	if len(cfg.Hosts) == 0 {
+		return nil, fmt.Errorf("no hosts defined in %s", path)
+	}
+
+	for i := range cfg.Hosts {
+		h := &cfg.Hosts[i]
+		if h.Host == "" {
+			h.Host = h.Name
+		}
+		if h.Host == "" {
+			return nil, fmt.Errorf("host %d in %s has no host or name", i+1, path)
+		}
+		if h.User == "" {
+			h.User = cfg.User
+		}
+	}
+	return &cfg, nil
+}
+
+// filterFleetHosts keeps the hosts with any of tags; no tags keeps all
+func filterFleetHosts(hosts []fleetHost, tags []string) []fleetHost {
+	if len(tags) == 0 {
+		return hosts
+	}
+	var kept []fleetHost
+	for _, h := range hosts {
+		for _, tag := range tags {
+			if slices.Contains(h.Tags, tag) {
+				kept = append(kept, h)
+				break
+			}
+		}
+	}
+	return kept
+}
+
+// fleetRows flattens the host results into rows and returns them with the
+// sorted union of their columns
+func fleetRows(results []sshexec.Result, rowsField string) ([]fleetRow, []string) {
+	var rows []fleetRow
+	for i, r := range results {
+		if r.Output != "" && r.Data == nil {
+			rows = append(rows, fleetRow{host: i, values: map[string]string{"output": r.Output}})
+			continue
+		}
+		data, ok := r.Data.(map[string]interface{})
+		if !ok {
+			values := map[string]string{}
+			if r.Data != nil {
+				values["value"] = flatten.Cell(r.Data)
+			}
+			rows = append(rows, fleetRow{host: i, values: values})
+			continue
+		}
+
+		if items := flatten.Items(data, rowsField); items != nil {
+			if len(items) == 0 {
+				rows = append(rows, fleetRow{host: i, values: map[string]string{}})
+			}
+			for _, item := range items {
+				rows = append(rows, fleetRow{host: i, values: flatten.Object(item)})
+			}
+			continue
+		}
+		rows = append(rows, fleetRow{host: i, values: flatten.Object(data)})
+	}
+
+	values := make([]map[string]string, len(rows))
+	for i, row := range rows {
+		values[i] = row.values
+	}
+	return rows, flatten.Keys(values...)
+}
+
+// leadingNumber matches the number a cell starts with
+var leadingNumber = regexp.MustCompile(`^-?[0-9]+(\.[0-9]+)?`)
+
+// sortFleetRows sorts rows by the comma-separated keys of spec, where host
+// is the host name. Empty cells sort last.
+func sortFleetRows(results []sshexec.Result, rows []fleetRow, spec string) error {
+	type sortKey struct {
+		column string
+		desc   bool
+	}
+	var keys []sortKey
+	for _, field := range strings.Split(spec, ",") {
+		field = strings.TrimSpace(field)
+		desc := strings.HasPrefix(field, "-")
+		field = strings.TrimPrefix(field, "-")
+		if field == "" {
+			return fmt.Errorf("invalid --sort value: %s", spec)
+		}
+		keys = append(keys, sortKey{column: field, desc: desc})
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		for _, k := range keys {
+			a, b := rows[i].values[k.column], rows[j].values[k.column]
+			if k.column == "host" {
+				a, b = results[rows[i].host].Host, results[rows[j].host].Host
+			}
+			if a == b {
+				continue
+			}
+			if a == "" || b == "" {
+				return b == ""
+			}
+			c := compareFleetCells(a, b)
+			if c == 0 {
+				continue
+			}
+			return (c < 0) != k.desc
+		}
+		return false
+	})
+	return nil
+}
+
+// compareFleetCells compares two cells by their leading number when both
+// have one, and as text otherwise
+func compareFleetCells(a, b string) int {
+	x, errX := strconv.ParseFloat(leadingNumber.FindString(a), 64)
+	y, errY := strconv.ParseFloat(leadingNumber.FindString(b), 64)
+	if errX == nil && errY == nil && x != y {
+		return cmp.Compare(x, y)
+	}
+	return strings.Compare(a, b)
+}
+
+// printFleetTable prints a row per result row, with the host first and the
+// error of failed hosts in place of their values
+func printFleetTable(results []sshexec.Result, rows []fleetRow, columns []string) {
+	headers := []string{"HOST"}
+	for _, c := range columns {
+		headers = append(headers, strings.ToUpper(c))
+	}
+	widths := make([]int, len(headers))
+	for i, h := range headers {
+		widths[i] = len(h)
+	}
+	for _, row := range rows {
+		widths[0] = max(widths[0], len(results[row.host].Host))
+		for j, c := range columns {
+			widths[j+1] = max(widths[j+1], len(row.values[c]))
+		}
+	}
+
+	var header strings.Builder
+	total := 0
+	for i, h := range headers {
+		fmt.Fprintf(&header, "%-*s  ", widths[i], h)
+		total += widths[i] + 2
+	}
+	fmt.Println(output.Accent(strings.TrimRight(header.String(), " ")))
+	fmt.Println(output.Rule(total - 2))
+	for _, row := range rows {
+		r := results[row.host]
+		if r.Error != "" && len(row.values) == 0 {
+			fmt.Printf("%-*s  %s\n", widths[0], r.Host, output.Failure(r.Error))
+			continue
+		}
+		var line strings.Builder
+		fmt.Fprintf(&line, "%-*s  ", widths[0], r.Host)
+		for j, c := range columns {
+			cell := row.values[c]
+			if cell == "" {
+				cell = "-"
+			}
+			fmt.Fprintf(&line, "%-*s  ", widths[j+1], cell)
+		}
+		text := strings.TrimRight(line.String(), " ")
+		if r.Error != "" {
+			text += "  " + output.Failure(r.Error)
+		}
+		fmt.Println(text)
+	}
+}
@@ -0,0 +1,29 @@
+package git
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// gitCmd represents the git command group
+var gitCmd = &cobra.Command{
+	Use:   "git",
+	Short: "Git hosting helpers (GitHub, GitLab)",
+	Long: `Helpers for the chores around Git hosting: listing pull requests,
+finding the latest release and fetching single files, with devkit's
+consistent JSON output instead of bespoke curl and jq.
+
+Examples:
+  devkit git hub pr list --mine
+  devkit git hub release latest cli/cli
+  devkit git hub raw golang/go VERSION --ref master`,
+}
+
+// GetGitCmd returns the git command
+func GetGitCmd() *cobra.Command {
+	return gitCmd
+}
+
+func init() {
+	// This will be called when the package is imported
+	// Commands will be added in their respective files
+}
@@ -0,0 +1,380 @@
+package git
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/spf13/cobra"
+	"devkit/internal/output"
+)
+
+// hubPRCmd represents the pr command group
+var hubPRCmd = &cobra.Command{
+	Use:   "pr",
+	Short: "Pull requests (merge requests on GitLab)",
+	Long: `Query pull requests, or merge requests on GitLab.
+
+Examples:
+  devkit git hub pr list
+  devkit git hub pr list --mine`,
+}
+
+// hubPRListCmd represents the pr list subcommand
+var hubPRListCmd = &cobra.Command{
+	Use:   "list [repo]",
+	Short: "List pull requests",
+	Long: `List the pull requests of a repository, newest first, or with --mine
+the ones you opened. Without a repository, the origin remote of the clone
+in the current directory is used; with --mine outside a clone, your pull
+requests in all repositories are listed. --mine needs a token.
+
+States: open (default), closed (closed without merging), merged, all.
+
+Examples:
+  devkit git hub pr list
+  devkit git hub pr list --mine
+  devkit git hub pr list golang/go --state merged --limit 10
+  devkit git hub pr list https://gitlab.com/gitlab-org/cli --state all
+  devkit git hub pr list --mine --output json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runHubPRList,
+}
+
+func init() {
+	hubCmd.AddCommand(hubPRCmd)
+	hubPRCmd.AddCommand(hubPRListCmd)
+
+	hubPRListCmd.Flags().Bool("mine", false, "Only pull requests you opened")
+	hubPRListCmd.Flags().StringP("state", "s", "open", "State: open, closed, merged, all")
+	hubPRListCmd.Flags().IntP("limit", "n", 30, "Maximum number of pull requests")
+	hubPRListCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
+}
+
+// hubPullRequest is a GitHub pull request or GitLab merge request
+type hubPullRequest struct {
+	Number  int    `json:"number"`
+	Repo    string `json:"repo"`
+	Title   string `json:"title"`
+	Author  string `json:"author"`
+	State   string `json:"state"`
+	Draft   bool   `json:"draft"`
+	Source  string `json:"source_branch,omitempty"`
+	Target  string `json:"target_branch,omitempty"`
+	URL     string `json:"url"`
+	Created string `json:"created"`
+	Updated string `json:"updated"`
+}
+
+func runHubPRList(cmd *cobra.Command, args []string) error {
+	mine, _ := cmd.Flags().GetBool("mine")
+	state, _ := cmd.Flags().GetString("state")
+	limit, _ := cmd.Flags().GetInt("limit")
+	outputFormat, _ := cmd.Flags().GetString("output")
+	format := output.OutputFormat(outputFormat)
+
+	switch state {
+	case "open", "closed", "merged", "all":
+	default:
+		return fmt.Errorf("invalid state: %s (supported: open, closed, merged, all)", state)
+	}
+	if limit < 1 {
+		return fmt.Errorf("limit must be at least 1")
+	}
+	if limit > 1000 {
+		return fmt.Errorf("limit cannot exceed 1000")
+	}
+
+	repo := ""
+	if len(args) > 0 {
+		repo = args[0]
+	}
+	c, repo, err := newHubClient(cmd, repo, !mine)
+	if err != nil {
+		return err
+	}
+	cmd.SilenceUsage = true
+	if mine {
+		if err := c.requireToken("--mine"); err != nil {
+			return err
+		}
+	}
+
+	var prs []hubPullRequest
+	if c.provider == "gitlab" {
+		prs, err = c.gitlabMergeRequests(repo, state, mine, limit)
+	} else if mine {
+		prs, err = c.githubSearchPRs(repo, state, limit)
+	} else {
+		prs, err = c.githubPulls(repo, state, limit)
+	}
+	if err != nil {
+		return err
+	}
+	if prs == nil {
+		prs = []hubPullRequest{}
+	}
+
+	if format == output.FormatJSON {
+		result := map[string]interface{}{
+			"provider":      c.provider,
+			"state":         state,
+			"mine":          mine,
+			"count":         len(prs),
+			"pull_requests": prs,
+		}
+		if repo != "" {
+			result["repo"] = repo
+		}
+		output.PrintSuccess(format, result)
+		return nil
+	}
+
+	if len(prs) == 0 {
+		fmt.Println(output.Muted("No pull requests"))
+		return nil
+	}
+	idWidth, authorWidth := 0, 0
+	ids := make([]string, len(prs))
+	for i, pr := range prs {
+		ids[i] = "#" + strconv.Itoa(pr.Number)
+		if repo == "" {
+			ids[i] = pr.Repo + ids[i]
+		}
+		idWidth = max(idWidth, len(ids[i]))
+		authorWidth = max(authorWidth, len(pr.Author))
+	}
+	for i, pr := range prs {
+		state := pr.State
+		if pr.Draft && state == "open" {
+			state = "draft"
+		}
+		title := pr.Title
+		if utf8.RuneCountInString(title) > 60 {
+			title = string([]rune(title)[:59]) + "…"
+		}
+		fmt.Printf("%s  %-60s  %-*s  %-6s  %s\n", output.Accent(fmt.Sprintf("%-*s", idWidth, ids[i])),
+			title, authorWidth, pr.Author, state, output.Muted(formatHubDate(pr.Updated)))
+	}
+	return nil
+}
+
+// githubPulls lists the pull requests of a repository
+func (c *hubClient) githubPulls(repo, state string, limit int) ([]hubPullRequest, error) {
+	apiState := state
+	if state == "merged" {
+		apiState = "closed"
+	}
+
+	var prs []hubPullRequest
+	for page := 1; len(prs) < limit; page++ {
+		var items []struct {
+			Number   int    `json:"number"`
+			Title    string `json:"title"`
+			State    string `json:"state"`
+			Draft    bool   `json:"draft"`
+			HTMLURL  string `json:"html_url"`
+			Created  string `json:"created_at"`
+			Updated  string `json:"updated_at"`
+			MergedAt string `json:"merged_at"`
+			User     struct {
+				Login string `json:"login"`
+			} `json:"user"`
+			Head struct {
+				Ref string `json:"ref"`
+			} `json:"head"`
+			Base struct {
+				Ref string `json:"ref"`
+			} `json:"base"`
+		}
+		query := url.Values{
+			"state":    {apiState},
+			"per_page": {"100"},
+			"page":     {strconv.Itoa(page)},
+		}
+		if err := c.get(c.projectPath(repo)+"/pulls", query, &items); err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			pr := hubPullRequest{
+				Number:  item.Number,
+				Repo:    repo,
+				Title:   item.Title,
+				Author:  item.User.Login,
+				State:   item.State,
+				Draft:   item.Draft,
+				Source:  item.Head.Ref,
+				Target:  item.Base.Ref,
+				URL:     item.HTMLURL,
+				Created: item.Created,
+				Updated: item.Updated,
+			}
+			if item.MergedAt != "" {
+				pr.State = "merged"
+			}
+			if state != "all" && pr.State != state {
+				continue
+			}
+			if len(prs) < limit {
+				prs = append(prs, pr)
+			}
+		}
+		if len(items) < 100 {
+			break
+		}
+	}
+	return prs, nil
+}
+
+// githubSearchPRs finds the pull requests of the authenticated user, in
+// one repository or in all of them
+func (c *hubClient) githubSearchPRs(repo, state string, limit int) ([]hubPullRequest, error) {
+	terms := []string{"is:pr", "author:@me"}
+	if repo != "" {
+		terms = append(terms, "repo:"+repo)
+	}
+	switch state {
+	case "open":
+		terms = append(terms, "is:open")
+	case "closed":
+		terms = append(terms, "is:closed", "is:unmerged")
+	case "merged":
+		terms = append(terms, "is:merged")
+	}
+
+	var prs []hubPullRequest
+	for page := 1; len(prs) < limit; page++ {
+		var result struct {
+			Items []struct {
+				Number        int    `json:"number"`
+				Title         string `json:"title"`
+				State         string `json:"state"`
+				Draft         bool   `json:"draft"`
+				HTMLURL       string `json:"html_url"`
+				RepositoryURL string `json:"repository_url"`
+				Created       string `json:"created_at"`
+				Updated       string `json:"updated_at"`
+				User          struct {
+					Login string `json:"login"`
+				} `json:"user"`
+				PullRequest struct {
+					MergedAt string `json:"merged_at"`
+				} `json:"pull_request"`
+			} `json:"items"`
+		}
+		query := url.Values{
+			"q":        {strings.Join(terms, " ")},
+			"sort":     {"updated"},
+			"per_page": {"100"},
+			"page":     {strconv.Itoa(page)},
+		}
+		if err := c.get("search/issues", query, &result); err != nil {
+			return nil, err
+		}
+		for _, item := range result.Items {
+			pr := hubPullRequest{
+				Number:  item.Number,
+				Repo:    strings.TrimPrefix(item.RepositoryURL, c.api+"/repos/"),
+				Title:   item.Title,
+				Author:  item.User.Login,
+				State:   item.State,
+				Draft:   item.Draft,
+				URL:     item.HTMLURL,
+				Created: item.Created,
+				Updated: item.Updated,
+			}
+			if item.PullRequest.MergedAt != "" {
+				pr.State = "merged"
+			}
+			if len(prs) < limit {
+				prs = append(prs, pr)
+			}
+		}
+		if len(result.Items) < 100 {
+			break
+		}
+	}
+	return prs, nil
+}
+
+// gitlabMergeRequests lists the merge requests of a project, or with mine
+// those of the authenticated user (in all projects when repo is empty)
+func (c *hubClient) gitlabMergeRequests(repo, state string, mine bool, limit int) ([]hubPullRequest, error) {
+	path := "merge_requests"
+	if repo != "" {
+		path = c.projectPath(repo) + "/merge_requests"
+	}
+	query := url.Values{
+		"order_by": {"updated_at"},
+		"per_page": {"100"},
+		"scope":    {"all"},
+	}
+	switch state {
+	case "open":
+		query.Set("state", "opened")
+	case "closed", "merged":
+		query.Set("state", state)
+	}
+	if mine {
+		query.Set("scope", "created_by_me")
+	}
+
+	var prs []hubPullRequest
+	for page := 1; len(prs) < limit; page++ {
+		var items []struct {
+			IID        int    `json:"iid"`
+			Title      string `json:"title"`
+			State      string `json:"state"`
+			Draft      bool   `json:"draft"`
+			WebURL     string `json:"web_url"`
+			Source     string `json:"source_branch"`
+			Target     string `json:"target_branch"`
+			Created    string `json:"created_at"`
+			Updated    string `json:"updated_at"`
+			References struct {
+				Full string `json:"full"`
+			} `json:"references"`
+			Author struct {
+				Username string `json:"username"`
+			} `json:"author"`
+		}
+		query.Set("page", strconv.Itoa(page))
+		if err := c.get(path, query, &items); err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			pr := hubPullRequest{
+				Number:  item.IID,
+				Repo:    repo,
+				Title:   item.Title,
+				Author:  item.Author.Username,
+				State:   item.State,
+				Draft:   item.Draft,
+				Source:  item.Source,
+				Target:  item.Target,
+				URL:     item.WebURL,
+				Created: item.Created,
+				Updated: item.Updated,
+			}
+			// group/project!12
+			if full, _, found := strings.Cut(item.References.Full, "!"); found && pr.Repo == "" {
+				pr.Repo = full
+			}
+			switch pr.State {
+			case "opened":
+				pr.State = "open"
+			case "locked":
+				pr.State = "closed"
+			}
+			if len(prs) < limit {
+				prs = append(prs, pr)
+			}
+		}
+		if len(items) < 100 {
+			break
+		}
+	}
+	return prs, nil
+}
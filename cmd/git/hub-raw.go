@@ -0,0 +1,140 @@
+package git
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/spf13/cobra"
+	"devkit/internal/output"
+)
+
+// hubRawMaxSize bounds the files raw reads, which covers the GitHub
+// contents API limit of 100 MB
+const hubRawMaxSize = 100 << 20
+
+// hubRawCmd represents the raw subcommand
+var hubRawCmd = &cobra.Command{
+	Use:   "raw <repo> <path>",
+	Short: "Print a file from a repository",
+	Long: `Print the contents of one file of a repository without cloning it, at
+the default branch or at --ref (a branch, tag or commit). Private
+repositories need a token.
+
+With --out the file is written to disk instead. With --output json, the
+content is returned as text, or base64 for binary files.
+
+Examples:
+  devkit git hub raw golang/go VERSION
+  devkit git hub raw kubernetes/kubernetes go.mod --ref v1.30.0
+  devkit git hub raw https://gitlab.com/gitlab-org/cli .goreleaser.yml
+  devkit git hub raw myorg/infra deploy/values.yaml --out values.yaml`,
+	Args: cobra.ExactArgs(2),
+	RunE: runHubRaw,
+}
+
+func init() {
+	hubCmd.AddCommand(hubRawCmd)
+
+	hubRawCmd.Flags().StringP("ref", "r", "", "Branch, tag or commit (default: the default branch)")
+	hubRawCmd.Flags().String("out", "", "Write the file to this path instead of stdout")
+	hubRawCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
+}
+
+func runHubRaw(cmd *cobra.Command, args []string) error {
+	ref, _ := cmd.Flags().GetString("ref")
+	outFile, _ := cmd.Flags().GetString("out")
+	outputFormat, _ := cmd.Flags().GetString("output")
+	format := output.OutputFormat(outputFormat)
+
+	filePath := strings.Trim(args[1], "/")
+	if filePath == "" {
+		return fmt.Errorf("file path cannot be empty")
+	}
+
+	c, repo, err := newHubClient(cmd, args[0], true)
+	if err != nil {
+		return err
+	}
+	cmd.SilenceUsage = true
+
+	var (
+		apiPath string
+		accept  string
+		query   = url.Values{}
+	)
+	if c.provider == "gitlab" {
+		apiPath = c.projectPath(repo) + "/repository/files/" + url.PathEscape(filePath) + "/raw"
+		if ref == "" {
+			ref = "HEAD"
+		}
+		query.Set("ref", ref)
+	} else {
+		segments := strings.Split(filePath, "/")
+		for i, s := range segments {
+			segments[i] = url.PathEscape(s)
+		}
+		apiPath = c.projectPath(repo) + "/contents/" + strings.Join(segments, "/")
+		accept = "application/vnd.github.raw"
+		if ref != "" {
+			query.Set("ref", ref)
+		}
+	}
+
+	resp, err := c.request(apiPath, query, accept)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "application/json") && c.provider == "github" {
+		// The contents API answers a directory with a JSON listing
+		return fmt.Errorf("%s is a directory in %s", filePath, repo)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, hubRawMaxSize+1))
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", filePath, err)
+	}
+	if len(data) > hubRawMaxSize {
+		return fmt.Errorf("%s is larger than %d MB", filePath, hubRawMaxSize>>20)
+	}
+
+	if outFile != "" {
+		if err := os.WriteFile(outFile, data, 0644); err != nil {
+			return fmt.Errorf("failed to write output file: %w", err)
+		}
+	}
+
+	if format == output.FormatJSON {
+		result := map[string]interface{}{
+			"provider": c.provider,
+			"repo":     repo,
+			"path":     filePath,
+			"size":     len(data),
+		}
+		if ref != "" {
+			result["ref"] = ref
+		}
+		if outFile != "" {
+			result["out"] = outFile
+		} else if utf8.Valid(data) {
+			result["encoding"] = "utf-8"
+			result["content"] = string(data)
+		} else {
+			result["encoding"] = "base64"
+			result["content"] = base64.StdEncoding.EncodeToString(data)
+		}
+		output.PrintSuccess(format, result)
+		return nil
+	}
+
+	if outFile != "" {
+		fmt.Printf("Saved: %s (%s)\n", outFile, formatBytes(int64(len(data))))
+		return nil
+	}
+	os.Stdout.Write(data)
+	return nil
+}
@@ -0,0 +1,223 @@
+package git
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/spf13/cobra"
+	"devkit/internal/output"
+)
+
+// hubReleaseCmd represents the release command group
+var hubReleaseCmd = &cobra.Command{
+	Use:   "release",
+	Short: "Releases",
+	Long: `Query the releases of a repository.
+
+Examples:
+  devkit git hub release latest cli/cli`,
+}
+
+// hubReleaseLatestCmd represents the release latest subcommand
+var hubReleaseLatestCmd = &cobra.Command{
+	Use:   "latest [repo]",
+	Short: "Show the latest release of a repository",
+	Long: `Show the latest release of a repository with its assets. On GitHub,
+drafts and prereleases are not considered; on GitLab, the release with the
+most recent release date is shown.
+
+--tag-only prints just the tag, e.g. for a download script.
+
+Examples:
+  devkit git hub release latest cli/cli
+  devkit git hub release latest https://gitlab.com/gitlab-org/cli
+  devkit git hub release latest golangci/golangci-lint --tag-only
+  devkit git hub release latest cli/cli --output json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runHubReleaseLatest,
+}
+
+func init() {
+	hubCmd.AddCommand(hubReleaseCmd)
+	hubReleaseCmd.AddCommand(hubReleaseLatestCmd)
+
+	hubReleaseLatestCmd.Flags().Bool("tag-only", false, "Print only the tag name")
+	hubReleaseLatestCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
+}
+
+// hubAsset is a file attached to a release
+type hubAsset struct {
+	Name      string `json:"name"`
+	URL       string `json:"url"`
+	Size      int64  `json:"size,omitempty"`
+	Downloads int    `json:"downloads,omitempty"`
+}
+
+// hubRelease is a GitHub or GitLab release
+type hubRelease struct {
+	Repo       string     `json:"repo"`
+	Tag        string     `json:"tag"`
+	Name       string     `json:"name"`
+	Published  string     `json:"published"`
+	Prerelease bool       `json:"prerelease"`
+	URL        string     `json:"url"`
+	Notes      string     `json:"notes"`
+	Assets     []hubAsset `json:"assets"`
+}
+
+func runHubReleaseLatest(cmd *cobra.Command, args []string) error {
+	tagOnly, _ := cmd.Flags().GetBool("tag-only")
+	outputFormat, _ := cmd.Flags().GetString("output")
+	format := output.OutputFormat(outputFormat)
+
+	repo := ""
+	if len(args) > 0 {
+		repo = args[0]
+	}
+	c, repo, err := newHubClient(cmd, repo, true)
+	if err != nil {
+		return err
+	}
+	cmd.SilenceUsage = true
+
+	var release *hubRelease
+	if c.provider == "gitlab" {
+		release, err = c.gitlabLatestRelease(repo)
+	} else {
+		release, err = c.githubLatestRelease(repo)
+	}
+	if err != nil {
+		return err
+	}
+
+	if format == output.FormatJSON {
+		output.PrintSuccess(format, release)
+		return nil
+	}
+	if tagOnly {
+		fmt.Println(release.Tag)
+		return nil
+	}
+
+	title := release.Tag
+	if release.Name != "" && release.Name != release.Tag {
+		title += "  " + release.Name
+	}
+	if release.Prerelease {
+		title += "  " + output.Warning("(prerelease)")
+	}
+	fmt.Println(output.Accent(title))
+	fmt.Printf("  Published: %s\n", formatHubDate(release.Published))
+	fmt.Printf("  URL:       %s\n", release.URL)
+	if len(release.Assets) > 0 {
+		fmt.Println("  Assets:")
+		for _, asset := range release.Assets {
+			size := ""
+			if asset.Size > 0 {
+				size = output.Muted(fmt.Sprintf("  (%s)", formatBytes(asset.Size)))
+			}
+			fmt.Printf("    %s%s\n", asset.Name, size)
+		}
+	}
+	return nil
+}
+
+func (c *hubClient) githubLatestRelease(repo string) (*hubRelease, error) {
+	var item struct {
+		TagName     string `json:"tag_name"`
+		Name        string `json:"name"`
+		PublishedAt string `json:"published_at"`
+		Prerelease  bool   `json:"prerelease"`
+		HTMLURL     string `json:"html_url"`
+		Body        string `json:"body"`
+		Assets      []struct {
+			Name               string `json:"name"`
+			Size               int64  `json:"size"`
+			DownloadCount      int    `json:"download_count"`
+			BrowserDownloadURL string `json:"browser_download_url"`
+		} `json:"assets"`
+	}
+	if err := c.get(c.projectPath(repo)+"/releases/latest", nil, &item); err != nil {
+		return nil, err
+	}
+
+	release := &hubRelease{
+		Repo:       repo,
+		Tag:        item.TagName,
+		Name:       item.Name,
+		Published:  item.PublishedAt,
+		Prerelease: item.Prerelease,
+		URL:        item.HTMLURL,
+		Notes:      item.Body,
+		Assets:     []hubAsset{},
+	}
+	for _, a := range item.Assets {
+		release.Assets = append(release.Assets, hubAsset{
+			Name:      a.Name,
+			URL:       a.BrowserDownloadURL,
+			Size:      a.Size,
+			Downloads: a.DownloadCount,
+		})
+	}
+	return release, nil
+}
+
+func (c *hubClient) gitlabLatestRelease(repo string) (*hubRelease, error) {
+	var items []struct {
+		TagName         string `json:"tag_name"`
+		Name            string `json:"name"`
+		ReleasedAt      string `json:"released_at"`
+		UpcomingRelease bool   `json:"upcoming_release"`
+		Description     string `json:"description"`
+		Links           struct {
+			Self string `json:"self"`
+		} `json:"_links"`
+		Assets struct {
+			Links []struct {
+				Name string `json:"name"`
+				URL  string `json:"url"`
+			} `json:"links"`
+		} `json:"assets"`
+	}
+	query := url.Values{
+		"order_by": {"released_at"},
+		"sort":     {"desc"},
+		"per_page": {"1"},
+	}
+	if err := c.get(c.projectPath(repo)+"/releases", query, &items); err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, fmt.Errorf("%s has no releases", repo)
+	}
+
+	item := items[0]
+	release := &hubRelease{
+		Repo:       repo,
+		Tag:        item.TagName,
+		Name:       item.Name,
+		Published:  item.ReleasedAt,
+		Prerelease: item.UpcomingRelease,
+		URL:        item.Links.Self,
+		Notes:      item.Description,
+		Assets:     []hubAsset{},
+	}
+	for _, a := range item.Assets.Links {
+		release.Assets = append(release.Assets, hubAsset{Name: a.Name, URL: a.URL})
+	}
+	return release, nil
+}
+
+// formatBytes formats a byte count with a binary unit
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,324 @@
+package git
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"devkit/internal/netutil"
+	"devkit/pkg/version"
+)
+
+// hubTokenEnv lists the environment variables holding a token, per
+// provider, in the order they are tried
+var hubTokenEnv = map[string][]string{
+	"github": {"GITHUB_TOKEN", "GH_TOKEN"},
+	"gitlab": {"GITLAB_TOKEN"},
+}
+
+// hubCmd represents the hub command group
+var hubCmd = &cobra.Command{
+	Use:   "hub",
+	Short: "Query GitHub and GitLab repositories (pull requests, releases, files)",
+	Long: `Query the GitHub or GitLab API for repository metadata.
+
+Repositories are given as owner/name (group/subgroup/name on GitLab), as a
+web URL or as a clone URL; the host of a URL selects the provider. Without
+a repository, the origin remote of the clone in the current directory is
+used. --provider and --host, or hub.provider and hub.host in the config
+file, select a provider and a self-hosted instance (GitHub Enterprise or
+GitLab) for owner/name repositories.
+
+The token is read from GITHUB_TOKEN or GH_TOKEN for GitHub, GITLAB_TOKEN
+for GitLab, or hub.token in the config file. Public repositories can be
+queried without one, within the lower anonymous rate limits.
+
+Examples:
+  devkit git hub pr list --mine
+  devkit git hub pr list golang/go --state merged --limit 10
+  devkit git hub release latest cli/cli --output json
+  devkit git hub raw https://gitlab.com/gitlab-org/cli README.md`,
+}
+
+func init() {
+	gitCmd.AddCommand(hubCmd)
+
+	hubCmd.PersistentFlags().String("provider", "", "API provider: github, gitlab (default: from the repository URL or hub.provider)")
+	hubCmd.PersistentFlags().String("host", "", "Host of a self-hosted GitHub Enterprise or GitLab instance, e.g. git.example.com (default: hub.host)")
+	netutil.AddFlags(hubCmd)
+}
+
+// hubClient talks to the REST API of GitHub or GitLab
+type hubClient struct {
+	provider string
+	host     string
+	api      string
+	token    string
+	ctx      context.Context
+	opts     netutil.Options
+	client   *http.Client
+}
+
+// newHubClient resolves the provider, host and token for repo (which may
+// be empty) and returns the client and the repository path, empty when no
+// repository was given and none could be found in the current directory
+func newHubClient(cmd *cobra.Command, repo string, needRepo bool) (*hubClient, string, error) {
+	if repo == "" {
+		repo = originRemote()
+		if repo == "" && needRepo {
+			return nil, "", fmt.Errorf("repository not specified (give owner/name or run inside a clone with an origin remote)")
+		}
+	}
+
+	host, repoPath, err := parseHubRepo(repo)
+	if err != nil {
+		return nil, "", err
+	}
+
+	provider, _ := cmd.Flags().GetString("provider")
+	flagHost, _ := cmd.Flags().GetString("host")
+	if provider == "" && host != "" {
+		switch {
+		case host == "github.com":
+			provider = "github"
+		case host == "gitlab.com" || strings.Contains(host, "gitlab"):
+			provider = "gitlab"
+		}
+	}
+	if provider == "" {
+		provider = viper.GetString("hub.provider")
+	}
+	if provider == "" {
+		provider = "github"
+	}
+	if provider != "github" && provider != "gitlab" {
+		return nil, "", fmt.Errorf("invalid provider: %s (supported: github, gitlab)", provider)
+	}
+
+	switch {
+	case flagHost != "":
+		host = flagHost
+	case host == "":
+		host = viper.GetString("hub.host")
+	}
+	if host == "" {
+		host = provider + ".com"
+	}
+
+	// A host may carry a scheme, e.g. http://gitlab.internal:8080
+	base := "https://" + host
+	if scheme, rest, found := strings.Cut(host, "://"); found {
+		base, host = scheme+"://"+strings.TrimRight(rest, "/"), strings.TrimRight(rest, "/")
+	}
+
+	c := &hubClient{provider: provider, host: host, ctx: cmd.Context()}
+	if c.ctx == nil {
+		c.ctx = context.Background()
+	}
+	switch {
+	case provider == "github" && host == "github.com":
+		c.api = "https://api.github.com"
+	case provider == "github":
+		c.api = base + "/api/v3"
+	default:
+		c.api = base + "/api/v4"
+	}
+
+	for _, name := range hubTokenEnv[provider] {
+		if c.token = os.Getenv(name); c.token != "" {
+			break
+		}
+	}
+	if c.token == "" {
+		c.token = viper.GetString("hub.token")
+	}
+
+	if c.opts, err = netutil.FromCommand(cmd, 30*time.Second); err != nil {
+		return nil, "", err
+	}
+	c.client = c.opts.HTTPClient()
+	return c, repoPath, nil
+}
+
+// parseHubRepo accepts owner/name, https://host/owner/name(.git) and
+// git@host:owner/name.git and returns the host (empty for owner/name) and
+// the repository path
+func parseHubRepo(repo string) (string, string, error) {
+	if repo == "" {
+		return "", "", nil
+	}
+	host := ""
+	path := repo
+	switch {
+	case strings.Contains(repo, "://"):
+		u, err := url.Parse(repo)
+		if err != nil {
+			return "", "", fmt.Errorf("invalid repository URL: %w", err)
+		}
+		host, path = u.Host, u.Path
+		if u.Scheme == "http" {
+			host = "http://" + host
+		}
+	case strings.Contains(repo, "@") && strings.Contains(repo, ":"):
+		// scp-like clone URL: git@host:owner/name.git
+		rest := repo[strings.Index(repo, "@")+1:]
+		host, path, _ = strings.Cut(rest, ":")
+	}
+
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	// Web URLs may point into the repository, e.g. /owner/name/tree/main
+	// or /group/name/-/merge_requests
+	if before, _, found := strings.Cut(path, "/-/"); found {
+		path = before
+	}
+	if host == "github.com" {
+		if parts := strings.Split(path, "/"); len(parts) > 2 {
+			path = parts[0] + "/" + parts[1]
+		}
+	}
+	if strings.Count(path, "/") < 1 || strings.Contains(path, "//") {
+		return "", "", fmt.Errorf("invalid repository: %s (use owner/name or a repository URL)", repo)
+	}
+	return host, path, nil
+}
+
+// originRemote returns the URL of the origin remote of the clone in the
+// current directory, or "" outside a clone
+func originRemote() string {
+	out, err := exec.Command("git", "remote", "get-url", "origin").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// projectPath returns the API path of a repository: repos/owner/name on
+// GitHub and projects/<url-encoded path> on GitLab
+func (c *hubClient) projectPath(repo string) string {
+	if c.provider == "gitlab" {
+		return "projects/" + url.PathEscape(repo)
+	}
+	return "repos/" + repo
+}
+
+// request sends a GET request for an API path and returns the response,
+// turning error statuses into errors
+func (c *hubClient) request(path string, query url.Values, accept string) (*http.Response, error) {
+	u := c.api + "/" + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(c.ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "devkit/"+version.Version)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	if c.provider == "github" {
+		req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+		if c.token != "" {
+			req.Header.Set("Authorization", "Bearer "+c.token)
+		}
+	} else if c.token != "" {
+		req.Header.Set("PRIVATE-TOKEN", c.token)
+	}
+
+	resp, err := c.opts.Do(c.client, req)
+	if err != nil {
+		return nil, fmt.Errorf("request to %s failed: %w", c.host, err)
+	}
+	if resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		return nil, c.statusError(resp)
+	}
+	return resp, nil
+}
+
+// get decodes the JSON response for an API path into v
+func (c *hubClient) get(path string, query url.Values, v interface{}) error {
+	accept := ""
+	if c.provider == "github" {
+		accept = "application/vnd.github+json"
+	}
+	resp, err := c.request(path, query, accept)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("invalid response from %s: %w", c.host, err)
+	}
+	return nil
+}
+
+// statusError explains an error response, with a hint for the common ones
+func (c *hubClient) statusError(resp *http.Response) error {
+	var body struct {
+		Message interface{} `json:"message"`
+		Error   string      `json:"error"`
+	}
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	json.Unmarshal(data, &body)
+	message := body.Error
+	if body.Message != nil {
+		message = fmt.Sprint(body.Message)
+	}
+	if message == "" {
+		message = resp.Status
+	}
+
+	tokenVar := hubTokenEnv[c.provider][0]
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized:
+		return fmt.Errorf("%s: authentication failed: %s (check %s)", c.host, message, tokenVar)
+	case resp.StatusCode == http.StatusForbidden && resp.Header.Get("X-RateLimit-Remaining") == "0",
+		resp.StatusCode == http.StatusTooManyRequests:
+		hint := ""
+		if c.token == "" {
+			hint = fmt.Sprintf("; set %s for a higher limit", tokenVar)
+		}
+		// The reset time is in Unix seconds on both providers
+		for _, name := range []string{"X-RateLimit-Reset", "RateLimit-Reset"} {
+			if unix, err := strconv.ParseInt(resp.Header.Get(name), 10, 64); err == nil {
+				hint = fmt.Sprintf(", resets at %s%s", time.Unix(unix, 0).Format("15:04"), hint)
+				break
+			}
+		}
+		return fmt.Errorf("%s: API rate limit exceeded%s", c.host, hint)
+	case resp.StatusCode == http.StatusNotFound && c.token == "":
+		return fmt.Errorf("%s: not found: %s (private repositories need %s)", c.host, message, tokenVar)
+	case resp.StatusCode == http.StatusNotFound:
+		return fmt.Errorf("%s: not found: %s", c.host, message)
+	}
+	return fmt.Errorf("%s: %s", c.host, message)
+}
+
+// requireToken fails when no token is configured for an endpoint that
+// needs one
+func (c *hubClient) requireToken(what string) error {
+	if c.token != "" {
+		return nil
+	}
+	return fmt.Errorf("%s needs a token (set %s)", what, hubTokenEnv[c.provider][0])
+}
+
+// formatHubDate shortens an RFC 3339 API timestamp to a local date and time
+func formatHubDate(value string) string {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return value
+	}
+	return t.Local().Format("2006-01-02 15:04")
+}
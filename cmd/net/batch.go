@@ -0,0 +1,209 @@
+package net
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cobra"
+	"devkit/internal/output"
+)
+
+// defaultBatchJobs bounds how many targets are checked at the same time
+const defaultBatchJobs = 10
+
+// batchResult is the outcome of a command for one target of a batch run
+type batchResult struct {
+	Target string                 `json:"target"`
+	Result map[string]interface{} `json:"result,omitempty"`
+	Error  string                 `json:"error,omitempty"`
+}
+
+// batchColumn is a column of the batch table, read from the result map
+type batchColumn struct {
+	Header string
+	Key    string
+}
+
+// addBatchFlags defines --targets and --jobs on a single-target command
+func addBatchFlags(cmd *cobra.Command) {
+	cmd.Flags().String("targets", "", "Check every target listed in a file, one per line (- for stdin)")
+	cmd.Flags().IntP("jobs", "j", defaultBatchJobs, "With --targets, number of targets checked in parallel")
+}
+
+// batchTargets returns the targets to check and whether the command runs in
+// batch mode, which is the case when --targets is given. Targets given as
+// arguments are checked too. Blank lines and lines starting with # are
+// skipped.
+func batchTargets(cmd *cobra.Command, args []string) ([]string, bool, error) {
+	path, _ := cmd.Flags().GetString("targets")
+	if path == "" {
+		return args, false, nil
+	}
+
+	var r io.Reader
+	if path == "-" {
+		stat, err := os.Stdin.Stat()
+		if err != nil {
+			return nil, false, fmt.Errorf("stdin error: %w", err)
+		}
+		if (stat.Mode() & os.ModeCharDevice) != 0 {
+			return nil, false, fmt.Errorf("no data available from stdin")
+		}
+		r = os.Stdin
+	} else {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to read targets: %w", err)
+		}
+		defer file.Close()
+		r = file
+	}
+
+	targets := append([]string(nil), args...)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		targets = append(targets, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, false, fmt.Errorf("failed to read targets: %w", err)
+	}
+	if len(targets) == 0 {
+		return nil, false, fmt.Errorf("no targets found in %s", path)
+	}
+	return targets, true, nil
+}
+
+// runBatch calls check for every target with up to --jobs running at the
+// same time and returns the results in the order of targets
+func runBatch(cmd *cobra.Command, targets []string, check func(target string) (map[string]interface{}, error)) []batchResult {
+	jobs, _ := cmd.Flags().GetInt("jobs")
+	if jobs < 1 {
+		jobs = 1
+	}
+
+	results := make([]batchResult, len(targets))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < jobs && w < len(targets); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				r := batchResult{Target: targets[i]}
+				result, err := check(targets[i])
+				if err != nil {
+					r.Error = err.Error()
+				} else {
+					r.Result = result
+				}
+				results[i] = r
+			}
+		}()
+	}
+	for i := range targets {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+	return results
+}
+
+// printBatch prints the aggregated report: one JSON document, or a table
+// with a row per target. It fails when any target could not be checked.
+func printBatch(cmd *cobra.Command, format output.OutputFormat, results []batchResult, columns []batchColumn) error {
+	failed := 0
+	for _, r := range results {
+		if r.Error != "" {
+			failed++
+		}
+	}
+
+	if format == output.FormatJSON {
+		output.PrintSuccess(format, map[string]interface{}{
+			"count":   len(results),
+			"failed":  failed,
+			"results": results,
+		})
+	} else {
+		headers := []string{"TARGET"}
+		for _, c := range columns {
+			headers = append(headers, c.Header)
+		}
+		rows := make([][]string, len(results))
+		widths := make([]int, len(headers))
+		for i, h := range headers {
+			widths[i] = len(h)
+		}
+		for i, r := range results {
+			row := []string{r.Target}
+			if r.Error == "" {
+				for _, c := range columns {
+					row = append(row, formatBatchCell(r.Result[c.Key]))
+				}
+			}
+			for j, cell := range row {
+				widths[j] = max(widths[j], len(cell))
+			}
+			rows[i] = row
+		}
+
+		var header strings.Builder
+		total := 0
+		for i, h := range headers {
+			fmt.Fprintf(&header, "%-*s  ", widths[i], h)
+			total += widths[i] + 2
+		}
+		fmt.Println(output.Accent(strings.TrimRight(header.String(), " ")))
+		fmt.Println(output.Rule(total - 2))
+		for i, row := range rows {
+			if results[i].Error != "" {
+				fmt.Printf("%-*s  %s\n", widths[0], row[0], output.Failure(results[i].Error))
+				continue
+			}
+			var line strings.Builder
+			for j, cell := range row {
+				fmt.Fprintf(&line, "%-*s  ", widths[j], cell)
+			}
+			fmt.Println(strings.TrimRight(line.String(), " "))
+		}
+	}
+
+	if failed > 0 {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("%d of %d targets failed", failed, len(results))
+	}
+	return nil
+}
+
+// formatBatchCell renders a result value for the table
+func formatBatchCell(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "-"
+	case string:
+		if v == "" {
+			return "-"
+		}
+		return v
+	case []string:
+		if len(v) == 0 {
+			return "-"
+		}
+		return strings.Join(v, ", ")
+	case bool:
+		if v {
+			return "yes"
+		}
+		return "no"
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -2,7 +2,6 @@ package net
 
 import (
 	"fmt"
-	"strings"
 
 	"github.com/shirou/gopsutil/v3/disk"
 	"github.com/spf13/cobra"
@@ -55,8 +54,8 @@ func runDisk(cmd *cobra.Command, args []string) error {
 	if format == output.FormatJSON {
 		output.PrintSuccess(format, result)
 	} else if format == output.FormatTable {
-		fmt.Printf("%-20s %15s %15s %15s %10s\n", "PATH", "TOTAL", "USED", "FREE", "USED%")
-		fmt.Println(strings.Repeat("-", 80))
+		fmt.Println(output.Accent(fmt.Sprintf("%-20s %15s %15s %15s %10s", "PATH", "TOTAL", "USED", "FREE", "USED%")))
+		fmt.Println(output.Rule(80))
 		fmt.Printf("%-20s %15s %15s %15s %10s\n",
 			path, result["total"], result["used"], result["free"], result["percent"])
 	} else {
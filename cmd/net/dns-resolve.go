@@ -0,0 +1,751 @@
+package net
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/net/dns/dnsmessage"
+	"devkit/internal/netutil"
+	"devkit/internal/output"
+)
+
+// dnsResolveCmd represents the resolve subcommand
+var dnsResolveCmd = &cobra.Command{
+	Use:   "resolve [name]",
+	Short: "Resolve a name the way the system does",
+	Long: `Resolve a host name with the system resolver, as other programs do,
+including /etc/hosts and the search domains.
+
+With --explain the resolution path is shown step by step: the source order
+from nsswitch.conf, the hosts file entry, the name servers, search domains
+and ndots from resolv.conf, every query name tried with its answer and
+remaining cache TTL, and the name the OS would settle on. The result is then
+compared with a direct, non-recursive query to the zone's authoritative
+server, which shows stale caches and split-horizon answers.
+
+--root reads etc/hosts, etc/resolv.conf and etc/nsswitch.conf below another
+root directory, e.g. a container's file system (/proc/<pid>/root), to
+explain why a name resolves differently there. --server replaces the name
+servers from resolv.conf.
+
+Examples:
+  devkit net dns resolve api
+  devkit net dns resolve api --explain
+  devkit net dns resolve db.internal --explain --root /proc/4242/root
+  devkit net dns resolve example.com --explain --server 1.1.1.1 --output json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runDNSResolve,
+}
+
+func init() {
+	dnsCmd.AddCommand(dnsResolveCmd)
+
+	dnsResolveCmd.Flags().Bool("explain", false, "Show the resolution path and compare it with the authoritative answer")
+	dnsResolveCmd.Flags().String("root", "/", "Read the resolver configuration below this directory")
+	dnsResolveCmd.Flags().String("server", "", "Name server to query instead of those in resolv.conf (host or host:port)")
+	dnsResolveCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
+	netutil.AddFamilyFlags(dnsResolveCmd)
+}
+
+// dnsRecord is one resource record of an answer
+type dnsRecord struct {
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	TTL   uint32 `json:"ttl"`
+	Value string `json:"value"`
+}
+
+// dnsQueryResult is the answer to one query of the resolution path
+type dnsQueryResult struct {
+	Name    string      `json:"name"`
+	Type    string      `json:"type"`
+	Rcode   string      `json:"rcode,omitempty"`
+	Records []dnsRecord `json:"records,omitempty"`
+	Error   string      `json:"error,omitempty"`
+}
+
+// resolvConf is the part of resolv.conf that decides the query names
+type resolvConf struct {
+	Path        string   `json:"path"`
+	Nameservers []string `json:"nameservers"`
+	Search      []string `json:"search,omitempty"`
+	Ndots       int      `json:"ndots"`
+	Options     []string `json:"options,omitempty"`
+	Error       string   `json:"error,omitempty"`
+}
+
+// dnsStubNotes explains well-known local name server addresses
+var dnsStubNotes = map[string]string{
+	"127.0.0.53": "systemd-resolved stub; upstream servers are in /run/systemd/resolve/resolv.conf",
+	"127.0.0.11": "Docker embedded DNS; it forwards to the host's name servers",
+}
+
+func runDNSResolve(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	explain, _ := cmd.Flags().GetBool("explain")
+	root, _ := cmd.Flags().GetString("root")
+	server, _ := cmd.Flags().GetString("server")
+	outputFormat, _ := cmd.Flags().GetString("output")
+	format := output.OutputFormat(outputFormat)
+	opts, err := netutil.FromCommand(cmd, 5*time.Second)
+	if err != nil {
+		return err
+	}
+
+	if !explain {
+		if cmd.Flags().Changed("root") || server != "" {
+			return fmt.Errorf("--root and --server require --explain")
+		}
+		addresses, err := systemLookup(cmd.Context(), opts, name)
+		if err != nil {
+			return err
+		}
+		if format == output.FormatJSON {
+			output.PrintSuccess(format, map[string]interface{}{
+				"name":      name,
+				"addresses": addresses,
+			})
+		} else {
+			for _, address := range addresses {
+				fmt.Println(address)
+			}
+		}
+		return nil
+	}
+
+	result := explainResolution(cmd.Context(), opts, name, root, server)
+	if format == output.FormatJSON {
+		output.PrintSuccess(format, result)
+	} else {
+		printResolution(result)
+	}
+	if _, ok := result["addresses"]; !ok {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("%s does not resolve", name)
+	}
+	return nil
+}
+
+// systemLookup resolves name with this machine's resolver, as other
+// programs do
+func systemLookup(ctx context.Context, opts netutil.Options, name string) ([]string, error) {
+	var addrs []net.IPAddr
+	err := opts.Retry(ctx, func(ctx context.Context) error {
+		var err error
+		addrs, err = net.DefaultResolver.LookupIPAddr(ctx, name)
+		return permanentDNSError(err)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("lookup failed: %w", err)
+	}
+	var addresses []string
+	for _, addr := range addrs {
+		if opts.Family == 4 && addr.IP.To4() == nil || opts.Family == 6 && addr.IP.To4() != nil {
+			continue
+		}
+		addresses = append(addresses, addr.IP.String())
+	}
+	if len(addresses) == 0 {
+		return nil, fmt.Errorf("no IPv%d addresses for %s", opts.Family, name)
+	}
+	return addresses, nil
+}
+
+// explainResolution follows the steps the OS resolver takes for name and
+// then asks the authoritative server directly
+func explainResolution(ctx context.Context, opts netutil.Options, name, root, server string) map[string]interface{} {
+	result := map[string]interface{}{"name": name, "root": root}
+
+	hostsPath := filepath.Join(root, "etc", "hosts")
+	if runtime.GOOS == "windows" && root == "/" {
+		hostsPath = filepath.Join(os.Getenv("SystemRoot"), "System32", "drivers", "etc", "hosts")
+	}
+	sources := nsswitchHosts(filepath.Join(root, "etc", "nsswitch.conf"))
+	conf := readResolvConf(filepath.Join(root, "etc", "resolv.conf"))
+	if server != "" {
+		if _, _, err := net.SplitHostPort(server); err != nil {
+			server = net.JoinHostPort(server, "53")
+		}
+		conf.Nameservers = []string{server}
+	}
+	result["sources"] = sources
+	result["resolv_conf"] = conf
+
+	hosts := map[string]interface{}{"path": hostsPath}
+	hostAddresses, err := hostsFileLookup(hostsPath, name)
+	if err != nil {
+		hosts["error"] = err.Error()
+	} else if len(hostAddresses) > 0 {
+		hosts["addresses"] = hostAddresses
+	}
+	result["hosts_file"] = hosts
+
+	var notes []string
+	for _, ns := range conf.Nameservers {
+		host, _, _ := net.SplitHostPort(ns)
+		if note, ok := dnsStubNotes[host]; ok {
+			notes = append(notes, fmt.Sprintf("%s is the %s", host, note))
+		}
+	}
+
+	// The name server the OS would ask first, in the forced family
+	nameserver := server
+	for _, ns := range conf.Nameservers {
+		if nameserver != "" {
+			break
+		}
+		host, _, _ := net.SplitHostPort(ns)
+		ip := net.ParseIP(host)
+		if ip == nil || opts.Family == 4 && ip.To4() == nil || opts.Family == 6 && ip.To4() != nil {
+			continue
+		}
+		nameserver = ns
+		break
+	}
+
+	var queries []dnsQueryResult
+	var answered *dnsQueryResult
+	var dnsResolved []string
+	if nameserver != "" {
+		result["nameserver"] = nameserver
+		for _, candidate := range dnsSearchNames(name, conf) {
+			found := false
+			for _, qtype := range []dnsmessage.Type{dnsmessage.TypeA, dnsmessage.TypeAAAA} {
+				q := queryRecords(ctx, opts, nameserver, candidate, qtype, true)
+				queries = append(queries, q)
+				if len(dnsAddresses(q.Records)) > 0 {
+					found = true
+				}
+			}
+			if found {
+				answered = &queries[len(queries)-2]
+				dnsResolved = append(dnsAddresses(answered.Records), dnsAddresses(queries[len(queries)-1].Records)...)
+				break
+			}
+		}
+		result["queries"] = queries
+	} else if len(conf.Nameservers) > 0 {
+		notes = append(notes, fmt.Sprintf("no IPv%d name server configured", opts.Family))
+	}
+
+	// Walk the sources in order like the OS does: the first one that knows
+	// the name wins
+	for _, source := range sources {
+		var addresses []string
+		switch source {
+		case "files":
+			addresses = hostAddresses
+		case "dns", "resolve":
+			if answered != nil {
+				addresses = dnsResolved
+				result["fqdn"] = answered.Name
+			}
+		default:
+			notes = append(notes, fmt.Sprintf("source %q is not simulated", source))
+			continue
+		}
+		if len(addresses) > 0 {
+			result["source"] = source
+			result["addresses"] = addresses
+			break
+		}
+	}
+
+	if root == "/" {
+		if addresses, err := systemLookup(ctx, opts, name); err != nil {
+			result["system_lookup_error"] = err.Error()
+		} else {
+			result["system_lookup"] = addresses
+		}
+	}
+
+	if result["source"] == "files" && answered != nil &&
+		strings.Join(sortedCopy(hostAddresses), ",") != strings.Join(sortedCopy(dnsResolved), ",") {
+		notes = append(notes, fmt.Sprintf("the hosts file entry shadows the DNS answer for %s (%s)", answered.Name, strings.Join(dnsResolved, ", ")))
+	}
+
+	if answered != nil {
+		auth, err := authoritativeAnswer(ctx, opts, nameserver, answered.Name)
+		if err != nil {
+			result["authoritative_error"] = err.Error()
+		} else {
+			result["authoritative"] = auth
+			notes = append(notes, compareAnswers(*answered, auth["queries"].([]dnsQueryResult))...)
+		}
+	}
+
+	if len(notes) > 0 {
+		result["notes"] = notes
+	}
+	return result
+}
+
+// nsswitchHosts returns the sources of the hosts line of nsswitch.conf, or
+// the usual files-then-dns order when there is none
+func nsswitchHosts(path string) []string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return []string{"files", "dns"}
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		line, _, _ = strings.Cut(line, "#")
+		fields := strings.Fields(line)
+		if len(fields) == 0 || fields[0] != "hosts:" {
+			continue
+		}
+		var sources []string
+		for _, f := range fields[1:] {
+			// Skip actions such as [NOTFOUND=return]
+			if !strings.HasPrefix(f, "[") {
+				sources = append(sources, f)
+			}
+		}
+		return sources
+	}
+	return []string{"files", "dns"}
+}
+
+// readResolvConf parses the name servers, search list and options
+func readResolvConf(path string) resolvConf {
+	conf := resolvConf{Path: path, Ndots: 1}
+	file, err := os.Open(path)
+	if err != nil {
+		conf.Error = err.Error()
+		return conf
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || strings.HasPrefix(fields[0], "#") || strings.HasPrefix(fields[0], ";") {
+			continue
+		}
+		switch fields[0] {
+		case "nameserver":
+			if net.ParseIP(fields[1]) != nil {
+				conf.Nameservers = append(conf.Nameservers, net.JoinHostPort(fields[1], "53"))
+			}
+		case "search", "domain":
+			// The last search or domain line wins
+			conf.Search = nil
+			for _, domain := range fields[1:] {
+				conf.Search = append(conf.Search, dnsFQDN(domain))
+			}
+		case "options":
+			for _, option := range fields[1:] {
+				conf.Options = append(conf.Options, option)
+				if v, ok := strings.CutPrefix(option, "ndots:"); ok {
+					if n, err := strconv.Atoi(v); err == nil {
+						conf.Ndots = min(n, 15)
+					}
+				}
+			}
+		}
+	}
+	return conf
+}
+
+// hostsFileLookup returns the addresses listed for name in a hosts file
+func hostsFileLookup(path, name string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	name = strings.TrimSuffix(name, ".")
+	var addresses []string
+	for _, line := range strings.Split(string(data), "\n") {
+		line, _, _ = strings.Cut(line, "#")
+		fields := strings.Fields(line)
+		if len(fields) < 2 || net.ParseIP(fields[0]) == nil {
+			continue
+		}
+		for _, host := range fields[1:] {
+			if strings.EqualFold(strings.TrimSuffix(host, "."), name) {
+				addresses = append(addresses, fields[0])
+				break
+			}
+		}
+	}
+	return addresses, nil
+}
+
+// dnsSearchNames lists the names queried for name in order: names with at
+// least ndots dots are tried as given first, others after the search
+// domains, and names ending in a dot are never expanded
+func dnsSearchNames(name string, conf resolvConf) []string {
+	if strings.HasSuffix(name, ".") {
+		return []string{name}
+	}
+	var names []string
+	for _, domain := range conf.Search {
+		names = append(names, name+"."+domain)
+	}
+	if strings.Count(name, ".") >= conf.Ndots {
+		return append([]string{name + "."}, names...)
+	}
+	return append(names, name+".")
+}
+
+// dnsFQDN adds the trailing dot of a fully qualified name
+func dnsFQDN(name string) string {
+	if strings.HasSuffix(name, ".") {
+		return name
+	}
+	return name + "."
+}
+
+// authoritativeAnswer finds the zone of fqdn and its name servers through
+// the resolver, then asks the first of them directly without recursion
+func authoritativeAnswer(ctx context.Context, opts netutil.Options, resolver, fqdn string) (map[string]interface{}, error) {
+	soa, err := dnsExchange(ctx, opts, resolver, fqdn, dnsmessage.TypeSOA, true)
+	if err != nil {
+		return nil, err
+	}
+	zone := ""
+	for _, rr := range append(soa.Answers, soa.Authorities...) {
+		if rr.Header.Type == dnsmessage.TypeSOA {
+			zone = rr.Header.Name.String()
+			break
+		}
+	}
+	if zone == "" {
+		return nil, fmt.Errorf("no SOA record found for %s", fqdn)
+	}
+
+	ns, err := dnsExchange(ctx, opts, resolver, zone, dnsmessage.TypeNS, true)
+	if err != nil {
+		return nil, err
+	}
+	var servers []string
+	for _, rr := range ns.Answers {
+		if body, ok := rr.Body.(*dnsmessage.NSResource); ok {
+			servers = append(servers, body.NS.String())
+		}
+	}
+	if len(servers) == 0 {
+		return nil, fmt.Errorf("no name servers found for zone %s", zone)
+	}
+
+	// Use the first name server that has an address in the forced family
+	var server, serverAddress string
+	for _, candidate := range servers {
+		qtype := dnsmessage.TypeA
+		if opts.Family == 6 {
+			qtype = dnsmessage.TypeAAAA
+		}
+		q := queryRecords(ctx, opts, resolver, candidate, qtype, true)
+		if addresses := dnsAddresses(q.Records); len(addresses) > 0 {
+			server, serverAddress = candidate, net.JoinHostPort(addresses[0], "53")
+			break
+		}
+	}
+	if server == "" {
+		return nil, fmt.Errorf("no address found for the name servers of %s", zone)
+	}
+
+	var queries []dnsQueryResult
+	for _, qtype := range []dnsmessage.Type{dnsmessage.TypeA, dnsmessage.TypeAAAA} {
+		queries = append(queries, queryRecords(ctx, opts, serverAddress, fqdn, qtype, false))
+	}
+	return map[string]interface{}{
+		"zone":        zone,
+		"nameservers": servers,
+		"server":      server,
+		"address":     serverAddress,
+		"queries":     queries,
+	}, nil
+}
+
+// compareAnswers notes where the resolver's answer differs from the
+// authoritative one and how long the resolver has cached it
+func compareAnswers(resolved dnsQueryResult, authoritative []dnsQueryResult) []string {
+	var auth dnsQueryResult
+	for _, q := range authoritative {
+		if q.Type == resolved.Type {
+			auth = q
+		}
+	}
+	if auth.Error != "" {
+		return []string{"authoritative query failed: " + auth.Error}
+	}
+
+	var notes []string
+	got, want := dnsAddresses(resolved.Records), dnsAddresses(auth.Records)
+	switch {
+	case len(want) == 0 && len(auth.Records) > 0:
+		notes = append(notes, fmt.Sprintf("the authoritative answer is a %s to %s, resolved in another zone", auth.Records[0].Type, auth.Records[0].Value))
+	case strings.Join(sortedCopy(got), ",") != strings.Join(sortedCopy(want), ","):
+		notes = append(notes, fmt.Sprintf("resolver answer (%s) differs from the authoritative answer (%s)", formatBatchCell(got), formatBatchCell(want)))
+	default:
+		notes = append(notes, "resolver and authoritative answers agree")
+	}
+
+	if len(resolved.Records) > 0 && len(auth.Records) > 0 {
+		last := resolved.Records[len(resolved.Records)-1]
+		for _, rr := range auth.Records {
+			if rr.Type == last.Type && rr.TTL > last.TTL {
+				notes = append(notes, fmt.Sprintf("resolver cache: %ds of %ds TTL left (cached about %ds ago)", last.TTL, rr.TTL, rr.TTL-last.TTL))
+				break
+			}
+		}
+	}
+	return notes
+}
+
+// sortedCopy returns values sorted without changing the original slice
+func sortedCopy(values []string) []string {
+	sorted := append([]string(nil), values...)
+	sort.Strings(sorted)
+	return sorted
+}
+
+// queryRecords sends one query and describes the answer
+func queryRecords(ctx context.Context, opts netutil.Options, server, name string, qtype dnsmessage.Type, recursive bool) dnsQueryResult {
+	q := dnsQueryResult{Name: dnsFQDN(name), Type: dnsTypeName(qtype)}
+	msg, err := dnsExchange(ctx, opts, server, name, qtype, recursive)
+	if err != nil {
+		q.Error = err.Error()
+		return q
+	}
+	q.Rcode = dnsRcodeName(msg.RCode)
+	for _, rr := range msg.Answers {
+		q.Records = append(q.Records, dnsRecord{
+			Name:  rr.Header.Name.String(),
+			Type:  dnsTypeName(rr.Header.Type),
+			TTL:   rr.Header.TTL,
+			Value: dnsRecordValue(rr.Body),
+		})
+	}
+	return q
+}
+
+// dnsAddresses returns the A and AAAA values of records
+func dnsAddresses(records []dnsRecord) []string {
+	var addresses []string
+	for _, rr := range records {
+		if rr.Type == "A" || rr.Type == "AAAA" {
+			addresses = append(addresses, rr.Value)
+		}
+	}
+	return addresses
+}
+
+// dnsExchange sends a query over UDP, and again over TCP when the answer is
+// truncated, retrying failed attempts
+func dnsExchange(ctx context.Context, opts netutil.Options, server, name string, qtype dnsmessage.Type, recursive bool) (*dnsmessage.Message, error) {
+	qname, err := dnsmessage.NewName(dnsFQDN(name))
+	if err != nil {
+		return nil, fmt.Errorf("invalid name %s: %w", name, err)
+	}
+	id := uint16(rand.Uint32())
+	query := dnsmessage.Message{
+		Header:    dnsmessage.Header{ID: id, RecursionDesired: recursive},
+		Questions: []dnsmessage.Question{{Name: qname, Type: qtype, Class: dnsmessage.ClassINET}},
+	}
+	packed, err := query.Pack()
+	if err != nil {
+		return nil, err
+	}
+
+	var msg *dnsmessage.Message
+	err = opts.Retry(ctx, func(ctx context.Context) error {
+		var err error
+		msg, err = dnsRoundTrip(ctx, opts, "udp", server, packed, id)
+		if err == nil && msg.Truncated {
+			msg, err = dnsRoundTrip(ctx, opts, "tcp", server, packed, id)
+		}
+		return err
+	})
+	return msg, err
+}
+
+// dnsRoundTrip sends a packed query to server and reads the response
+func dnsRoundTrip(ctx context.Context, opts netutil.Options, network, server string, query []byte, id uint16) (*dnsmessage.Message, error) {
+	conn, err := opts.Dialer().DialContext(ctx, opts.Network(network), server)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	buf := make([]byte, 65535)
+	var data []byte
+	if network == "tcp" {
+		framed := append([]byte{byte(len(query) >> 8), byte(len(query))}, query...)
+		if _, err := conn.Write(framed); err != nil {
+			return nil, err
+		}
+		if _, err := io.ReadFull(conn, buf[:2]); err != nil {
+			return nil, err
+		}
+		size := int(buf[0])<<8 | int(buf[1])
+		if _, err := io.ReadFull(conn, buf[:size]); err != nil {
+			return nil, err
+		}
+		data = buf[:size]
+	} else {
+		if _, err := conn.Write(query); err != nil {
+			return nil, err
+		}
+		n, err := conn.Read(buf)
+		if err != nil {
+			return nil, err
+		}
+		data = buf[:n]
+	}
+
+	var msg dnsmessage.Message
+	if err := msg.Unpack(data); err != nil {
+		return nil, fmt.Errorf("invalid response from %s: %w", server, err)
+	}
+	if msg.ID != id {
+		return nil, fmt.Errorf("response from %s does not match the query", server)
+	}
+	return &msg, nil
+}
+
+// dnsTypeName returns the mnemonic of a record type, e.g. AAAA
+func dnsTypeName(t dnsmessage.Type) string {
+	return strings.TrimPrefix(t.String(), "Type")
+}
+
+// dnsRcodeNames maps response codes to their usual mnemonics
+var dnsRcodeNames = map[dnsmessage.RCode]string{
+	dnsmessage.RCodeSuccess:        "NOERROR",
+	dnsmessage.RCodeFormatError:    "FORMERR",
+	dnsmessage.RCodeServerFailure:  "SERVFAIL",
+	dnsmessage.RCodeNameError:      "NXDOMAIN",
+	dnsmessage.RCodeNotImplemented: "NOTIMP",
+	dnsmessage.RCodeRefused:        "REFUSED",
+}
+
+// dnsRcodeName returns the mnemonic of a response code
+func dnsRcodeName(code dnsmessage.RCode) string {
+	if name, ok := dnsRcodeNames[code]; ok {
+		return name
+	}
+	return strings.TrimPrefix(code.String(), "RCode")
+}
+
+// dnsRecordValue renders the data of a record
+func dnsRecordValue(body dnsmessage.ResourceBody) string {
+	switch b := body.(type) {
+	case *dnsmessage.AResource:
+		return net.IP(b.A[:]).String()
+	case *dnsmessage.AAAAResource:
+		return net.IP(b.AAAA[:]).String()
+	case *dnsmessage.CNAMEResource:
+		return b.CNAME.String()
+	case *dnsmessage.NSResource:
+		return b.NS.String()
+	case *dnsmessage.SOAResource:
+		return fmt.Sprintf("%s %s %d", b.NS, b.MBox, b.Serial)
+	default:
+		return fmt.Sprint(body)
+	}
+}
+
+// printResolution prints the steps of an --explain run
+func printResolution(result map[string]interface{}) {
+	fmt.Printf("Resolution of %s", result["name"])
+	if root := result["root"]; root != "/" {
+		fmt.Printf(" under %s", root)
+	}
+	fmt.Println()
+
+	fmt.Printf("\n%s %s\n", output.Accent("Source order:"), strings.Join(result["sources"].([]string), " → "))
+
+	hosts := result["hosts_file"].(map[string]interface{})
+	fmt.Printf("%s %s: ", output.Accent("Hosts file:"), hosts["path"])
+	switch {
+	case hosts["error"] != nil:
+		fmt.Println(output.Failure(hosts["error"].(string)))
+	case hosts["addresses"] != nil:
+		fmt.Println(strings.Join(hosts["addresses"].([]string), ", "))
+	default:
+		fmt.Println(output.Muted("no entry"))
+	}
+
+	conf := result["resolv_conf"].(resolvConf)
+	fmt.Printf("%s %s\n", output.Accent("Resolver:"), conf.Path)
+	if conf.Error != "" {
+		fmt.Printf("  %s\n", output.Failure(conf.Error))
+	}
+	fmt.Printf("  nameservers: %s\n", formatBatchCell(conf.Nameservers))
+	fmt.Printf("  search:      %s\n", formatBatchCell(conf.Search))
+	fmt.Printf("  ndots:       %d\n", conf.Ndots)
+
+	if queries, ok := result["queries"].([]dnsQueryResult); ok {
+		fmt.Printf("\n%s\n", output.Accent(fmt.Sprintf("Queries via %s:", result["nameserver"])))
+		printDNSQueries(queries)
+	}
+
+	fmt.Println()
+	if addresses, ok := result["addresses"].([]string); ok {
+		from := "hosts file"
+		if result["source"] != "files" {
+			from = fmt.Sprintf("DNS, %s", result["fqdn"])
+		}
+		fmt.Println(output.OK(fmt.Sprintf("Result: %s (from %s)", strings.Join(addresses, ", "), from)))
+	} else {
+		fmt.Println(output.Fail("Result: not found"))
+	}
+	if addresses, ok := result["system_lookup"].([]string); ok {
+		fmt.Printf("System lookup: %s\n", strings.Join(addresses, ", "))
+	} else if err, ok := result["system_lookup_error"]; ok {
+		fmt.Printf("System lookup: %s\n", output.Failure(err.(string)))
+	}
+
+	if auth, ok := result["authoritative"].(map[string]interface{}); ok {
+		fmt.Printf("\n%s\n", output.Accent(fmt.Sprintf("Authoritative (%s %s, zone %s):", auth["server"], auth["address"], auth["zone"])))
+		printDNSQueries(auth["queries"].([]dnsQueryResult))
+	} else if err, ok := result["authoritative_error"]; ok {
+		fmt.Printf("\nAuthoritative: %s\n", output.Failure(err.(string)))
+	}
+
+	if notes, ok := result["notes"].([]string); ok {
+		fmt.Println()
+		for _, note := range notes {
+			fmt.Printf("• %s\n", note)
+		}
+	}
+}
+
+// printDNSQueries prints one line per query, or per record of its answer
+func printDNSQueries(queries []dnsQueryResult) {
+	width := 0
+	for _, q := range queries {
+		width = max(width, len(q.Name))
+	}
+	for _, q := range queries {
+		prefix := fmt.Sprintf("  %-*s  %-4s  ", width, q.Name, q.Type)
+		switch {
+		case q.Error != "":
+			fmt.Println(prefix + output.Failure(q.Error))
+		case len(q.Records) == 0:
+			fmt.Println(prefix + output.Muted(q.Rcode+", no records"))
+		default:
+			for i, rr := range q.Records {
+				if i > 0 {
+					prefix = strings.Repeat(" ", len(prefix))
+				}
+				fmt.Printf("%s%s %s (ttl %ds)\n", prefix, rr.Type, rr.Value, rr.TTL)
+			}
+		}
+	}
+}
@@ -1,11 +1,17 @@
 package net
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/spf13/cobra"
+	"devkit/internal/cache"
+	"devkit/internal/netutil"
 	"devkit/internal/output"
 )
 
@@ -18,7 +24,8 @@ var dnsCmd = &cobra.Command{
 Examples:
   devkit net dns lookup google.com
   devkit net dns lookup google.com --type MX
-  devkit net dns reverse 8.8.8.8`,
+  devkit net dns reverse 8.8.8.8
+  devkit net dns resolve api --explain`,
 }
 
 // dnsLookupCmd represents the lookup subcommand
@@ -29,11 +36,20 @@ var dnsLookupCmd = &cobra.Command{
 
 Record types: A, AAAA, MX, TXT, NS, CNAME
 
+-4 and -6 query the name server over IPv4 or IPv6 only and skip the cache;
+the server that answered and its family are part of the output.
+
+With --targets, every domain listed in the file (one per line, - for stdin)
+is looked up concurrently and one table or JSON report is printed.
+
 Examples:
   devkit net dns lookup google.com
   devkit net dns lookup google.com --type MX
-  devkit net dns lookup google.com --type TXT`,
-	Args: cobra.ExactArgs(1),
+  devkit net dns lookup google.com --type TXT
+  devkit net dns lookup google.com --type AAAA -6
+  devkit net dns lookup --targets domains.txt --type MX
+  cat domains.txt | devkit net dns lookup --targets - --output json`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: runDNSLookup,
 }
 
@@ -57,24 +73,143 @@ func init() {
 
 	dnsLookupCmd.Flags().StringP("type", "t", "A", "DNS record type (A, AAAA, MX, TXT, NS, CNAME)")
 	dnsLookupCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
+	netutil.AddFamilyFlags(dnsLookupCmd)
+	addBatchFlags(dnsLookupCmd)
 
 	dnsReverseCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
+	netutil.AddFamilyFlags(dnsReverseCmd)
 }
 
+// dnsCacheTTL bounds how long cached answers are reused. It is kept short
+// since real record TTLs are not available from the system resolver.
+const dnsCacheTTL = 5 * time.Minute
+
 func runDNSLookup(cmd *cobra.Command, args []string) error {
-	domain := args[0]
 	recordType, _ := cmd.Flags().GetString("type")
 	outputFormat, _ := cmd.Flags().GetString("output")
 	format := output.OutputFormat(outputFormat)
+	opts, err := netutil.FromCommand(cmd, 5*time.Second)
+	if err != nil {
+		return err
+	}
+
+	targets, batch, err := batchTargets(cmd, args)
+	if err != nil {
+		return err
+	}
+	if batch {
+		results := runBatch(cmd, targets, func(domain string) (map[string]interface{}, error) {
+			return dnsLookup(cmd.Context(), opts, domain, recordType)
+		})
+		return printBatch(cmd, format, results, []batchColumn{
+			{Header: "TYPE", Key: "type"},
+			{Header: "RECORDS", Key: "records"},
+		})
+	}
+	if len(targets) == 0 {
+		return fmt.Errorf("domain not specified (provide it as argument or use --targets)")
+	}
 
-	var result map[string]interface{}
+	result, err := dnsLookup(cmd.Context(), opts, targets[0], recordType)
+	if err != nil {
+		return err
+	}
+
+	if format == output.FormatJSON {
+		output.PrintSuccess(format, result)
+	} else {
+		fmt.Printf("DNS %s records for %s:\n", recordType, result["domain"])
+		for _, value := range result["records"].([]string) {
+			fmt.Printf("  %s\n", value)
+		}
+		printDNSServer(result)
+	}
+
+	return nil
+}
+
+// dnsLookup resolves the records of one type for domain, using the cache
+// unless -4 or -6 asks to test a particular path to the name server
+func dnsLookup(ctx context.Context, opts netutil.Options, domain, recordType string) (map[string]interface{}, error) {
+	key := cache.Key(strings.ToUpper(recordType), strings.ToLower(domain))
+	var values []string
+	var server net.Addr
+	if opts.Family != 0 || !cache.Get(cache.NamespaceDNS, key, dnsCacheTTL, &values) {
+		resolver, used := trackedResolver(opts)
+		err := opts.Retry(ctx, func(ctx context.Context) error {
+			var err error
+			values, err = lookupRecords(ctx, resolver, domain, recordType)
+			return permanentDNSError(err)
+		})
+		server = used()
+		if err != nil {
+			return nil, err
+		}
+		cache.Set(cache.NamespaceDNS, key, values)
+	}
+
+	result := map[string]interface{}{
+		"domain":  domain,
+		"type":    recordType,
+		"records": values,
+		"count":   len(values),
+	}
+	if server != nil {
+		result["server"] = server.String()
+		result["family"] = netutil.Family(server)
+	}
+	return result, nil
+}
+
+// printDNSServer shows which name server answered and over which family
+func printDNSServer(result map[string]interface{}) {
+	if server, ok := result["server"]; ok {
+		fmt.Println(output.Muted(fmt.Sprintf("Server: %s (%s)", server, result["family"])))
+	}
+}
+
+// trackedResolver returns the resolver for opts and a function reporting the
+// name server it last connected to, nil for none (e.g. /etc/hosts answers)
+func trackedResolver(opts netutil.Options) (*net.Resolver, func() net.Addr) {
+	var mu sync.Mutex
+	var server net.Addr
+	resolver := opts.Resolver()
+	dial := resolver.Dial
+	resolver.Dial = func(ctx context.Context, network, address string) (net.Conn, error) {
+		conn, err := dial(ctx, network, address)
+		if err == nil {
+			mu.Lock()
+			server = conn.RemoteAddr()
+			mu.Unlock()
+		}
+		return conn, err
+	}
+	return resolver, func() net.Addr {
+		mu.Lock()
+		defer mu.Unlock()
+		return server
+	}
+}
+
+// permanentDNSError marks answers that a retry cannot change, such as
+// NXDOMAIN, so that only timeouts and server failures are retried
+func permanentDNSError(err error) error {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+		return netutil.Permanent(err)
+	}
+	return err
+}
+
+// lookupRecords resolves the records of one type for domain
+func lookupRecords(ctx context.Context, resolver *net.Resolver, domain, recordType string) ([]string, error) {
 	var values []string
 
 	switch strings.ToUpper(recordType) {
 	case "A":
-		ips, err := net.LookupIP(domain)
+		ips, err := resolver.LookupIP(ctx, "ip", domain)
 		if err != nil {
-			return fmt.Errorf("DNS lookup failed: %w", err)
+			return nil, fmt.Errorf("DNS lookup failed: %w", err)
 		}
 		for _, ip := range ips {
 			if ip.To4() != nil {
@@ -82,9 +217,9 @@ func runDNSLookup(cmd *cobra.Command, args []string) error {
 			}
 		}
 	case "AAAA":
-		ips, err := net.LookupIP(domain)
+		ips, err := resolver.LookupIP(ctx, "ip", domain)
 		if err != nil {
-			return fmt.Errorf("DNS lookup failed: %w", err)
+			return nil, fmt.Errorf("DNS lookup failed: %w", err)
 		}
 		for _, ip := range ips {
 			if ip.To4() == nil {
@@ -92,62 +227,56 @@ func runDNSLookup(cmd *cobra.Command, args []string) error {
 			}
 		}
 	case "MX":
-		mxRecords, err := net.LookupMX(domain)
+		mxRecords, err := resolver.LookupMX(ctx, domain)
 		if err != nil {
-			return fmt.Errorf("MX lookup failed: %w", err)
+			return nil, fmt.Errorf("MX lookup failed: %w", err)
 		}
 		for _, mx := range mxRecords {
 			values = append(values, fmt.Sprintf("%s (priority: %d)", mx.Host, mx.Pref))
 		}
 	case "TXT":
-		txtRecords, err := net.LookupTXT(domain)
+		txtRecords, err := resolver.LookupTXT(ctx, domain)
 		if err != nil {
-			return fmt.Errorf("TXT lookup failed: %w", err)
+			return nil, fmt.Errorf("TXT lookup failed: %w", err)
 		}
 		values = txtRecords
 	case "NS":
-		nsRecords, err := net.LookupNS(domain)
+		nsRecords, err := resolver.LookupNS(ctx, domain)
 		if err != nil {
-			return fmt.Errorf("NS lookup failed: %w", err)
+			return nil, fmt.Errorf("NS lookup failed: %w", err)
 		}
 		for _, ns := range nsRecords {
 			values = append(values, ns.Host)
 		}
 	case "CNAME":
-		cname, err := net.LookupCNAME(domain)
+		cname, err := resolver.LookupCNAME(ctx, domain)
 		if err != nil {
-			return fmt.Errorf("CNAME lookup failed: %w", err)
+			return nil, fmt.Errorf("CNAME lookup failed: %w", err)
 		}
 		values = []string{cname}
 	default:
-		return fmt.Errorf("unsupported record type: %s (supported: A, AAAA, MX, TXT, NS, CNAME)", recordType)
-	}
-
-	result = map[string]interface{}{
-		"domain": domain,
-		"type":   recordType,
-		"records": values,
-		"count":  len(values),
+		return nil, netutil.Permanent(fmt.Errorf("unsupported record type: %s (supported: A, AAAA, MX, TXT, NS, CNAME)", recordType))
 	}
 
-	if format == output.FormatJSON {
-		output.PrintSuccess(format, result)
-	} else {
-		fmt.Printf("DNS %s records for %s:\n", recordType, domain)
-		for _, value := range values {
-			fmt.Printf("  %s\n", value)
-		}
-	}
-
-	return nil
+	return values, nil
 }
 
 func runDNSReverse(cmd *cobra.Command, args []string) error {
 	ipStr := args[0]
 	outputFormat, _ := cmd.Flags().GetString("output")
 	format := output.OutputFormat(outputFormat)
+	opts, err := netutil.FromCommand(cmd, 5*time.Second)
+	if err != nil {
+		return err
+	}
 
-	names, err := net.LookupAddr(ipStr)
+	var names []string
+	resolver, used := trackedResolver(opts)
+	err = opts.Retry(cmd.Context(), func(ctx context.Context) error {
+		var err error
+		names, err = resolver.LookupAddr(ctx, ipStr)
+		return permanentDNSError(err)
+	})
 	if err != nil {
 		return fmt.Errorf("reverse DNS lookup failed: %w", err)
 	}
@@ -157,6 +286,10 @@ func runDNSReverse(cmd *cobra.Command, args []string) error {
 		"names":  names,
 		"count":  len(names),
 	}
+	if server := used(); server != nil {
+		result["server"] = server.String()
+		result["family"] = netutil.Family(server)
+	}
 
 	if format == output.FormatJSON {
 		output.PrintSuccess(format, result)
@@ -165,6 +298,7 @@ func runDNSReverse(cmd *cobra.Command, args []string) error {
 		for _, name := range names {
 			fmt.Printf("  %s\n", name)
 		}
+		printDNSServer(result)
 	}
 
 	return nil
@@ -0,0 +1,359 @@
+package net
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// vcrRedacted replaces scrubbed values in cassettes
+const vcrRedacted = "REDACTED"
+
+// vcrSecretMarkers flag header, query parameter, form and JSON field names
+// ending in them as secrets; names are compared without case, - and _, so
+// access_token and X-Api-Key are scrubbed but token_type is not
+var vcrSecretMarkers = []string{
+	"token", "secret", "password", "passwd", "apikey", "accesskey", "secretkey", "privatekey",
+	"credential", "credentials", "authorization", "cookie", "session", "sessionid", "signature",
+}
+
+// vcrMatchers are the request properties --match can compare
+var vcrMatchers = []string{"method", "url", "host", "path", "query", "body", "header:<name>"}
+
+// vcrCassette is a file of recorded HTTP interactions
+type vcrCassette struct {
+	Version      int              `yaml:"version"`
+	Interactions []vcrInteraction `yaml:"interactions"`
+}
+
+// vcrInteraction is one recorded request and its response
+type vcrInteraction struct {
+	Request    vcrRequest  `yaml:"request"`
+	Response   vcrResponse `yaml:"response"`
+	RecordedAt time.Time   `yaml:"recorded_at"`
+}
+
+type vcrRequest struct {
+	Method  string              `yaml:"method"`
+	URL     string              `yaml:"url"`
+	Headers map[string][]string `yaml:"headers,omitempty"`
+	Body    string              `yaml:"body,omitempty"`
+}
+
+type vcrResponse struct {
+	Status       string              `yaml:"status"`
+	StatusCode   int                 `yaml:"status_code"`
+	Headers      map[string][]string `yaml:"headers,omitempty"`
+	Body         string              `yaml:"body,omitempty"`
+	BodyEncoding string              `yaml:"body_encoding,omitempty"`
+}
+
+// vcrOptions holds the --record, --replay, --match and --scrub flags
+type vcrOptions struct {
+	record string
+	replay string
+	match  []string
+	scrub  []string
+}
+
+// addVCRFlags defines the cassette flags of an http subcommand
+func addVCRFlags(cmd *cobra.Command) {
+	cmd.Flags().String("record", "", "Record the request and response to a cassette file (YAML)")
+	cmd.Flags().String("replay", "", "Answer from a cassette file instead of the network")
+	cmd.Flags().StringSlice("match", []string{"method", "url"}, "Request properties a recorded interaction must match: "+strings.Join(vcrMatchers, ", "))
+	cmd.Flags().StringSlice("scrub", nil, "Extra header, query parameter or JSON field names to redact when recording")
+}
+
+// vcrFromCommand reads the cassette flags; it returns nil when neither
+// --record nor --replay is given
+func vcrFromCommand(cmd *cobra.Command) (*vcrOptions, error) {
+	o := &vcrOptions{}
+	o.record, _ = cmd.Flags().GetString("record")
+	o.replay, _ = cmd.Flags().GetString("replay")
+	o.match, _ = cmd.Flags().GetStringSlice("match")
+	o.scrub, _ = cmd.Flags().GetStringSlice("scrub")
+
+	if o.record != "" && o.replay != "" {
+		return nil, fmt.Errorf("--record and --replay cannot be used together")
+	}
+	if o.record == "" && o.replay == "" {
+		return nil, nil
+	}
+	for i, m := range o.match {
+		m = strings.ToLower(strings.TrimSpace(m))
+		if name, ok := strings.CutPrefix(m, "header:"); ok && name != "" {
+			o.match[i] = "header:" + http.CanonicalHeaderKey(name)
+			continue
+		}
+		switch m {
+		case "method", "url", "host", "path", "query", "body":
+			o.match[i] = m
+		default:
+			return nil, fmt.Errorf("invalid --match value: %s (use %s)", m, strings.Join(vcrMatchers, ", "))
+		}
+	}
+	return o, nil
+}
+
+// find returns the first interaction of the replay cassette matching req
+func (o *vcrOptions) find(req *http.Request, body string) (*vcrInteraction, error) {
+	cassette, err := loadVCRCassette(o.replay)
+	if err != nil {
+		return nil, err
+	}
+	want := o.request(req, body)
+	for i := range cassette.Interactions {
+		if o.matches(want, cassette.Interactions[i].Request) {
+			return &cassette.Interactions[i], nil
+		}
+	}
+	return nil, fmt.Errorf("no interaction in %s matches %s %s (compared: %s)", o.replay, want.Method, want.URL, strings.Join(o.match, ", "))
+}
+
+// save adds an interaction to the record cassette, replacing a recorded one
+// that matches the same request
+func (o *vcrOptions) save(req *http.Request, body string, resp *http.Response, respBody []byte) error {
+	cassette, err := loadVCRCassette(o.record)
+	if errors.Is(err, fs.ErrNotExist) {
+		cassette, err = &vcrCassette{Version: 1}, nil
+	}
+	if err != nil {
+		return err
+	}
+
+	interaction := vcrInteraction{
+		Request: o.request(req, body),
+		Response: vcrResponse{
+			Status:     resp.Status,
+			StatusCode: resp.StatusCode,
+			Headers:    o.scrubHeaders(resp.Header),
+		},
+		RecordedAt: time.Now().UTC().Truncate(time.Second),
+	}
+	if utf8.Valid(respBody) {
+		interaction.Response.Body = o.scrubBody(string(respBody), resp.Header.Get("Content-Type"))
+	} else {
+		interaction.Response.Body = base64.StdEncoding.EncodeToString(respBody)
+		interaction.Response.BodyEncoding = "base64"
+	}
+
+	replaced := false
+	for i := range cassette.Interactions {
+		if o.matches(interaction.Request, cassette.Interactions[i].Request) {
+			cassette.Interactions[i] = interaction
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		cassette.Interactions = append(cassette.Interactions, interaction)
+	}
+
+	data, err := yaml.Marshal(cassette)
+	if err != nil {
+		return fmt.Errorf("failed to encode cassette: %w", err)
+	}
+	if err := os.WriteFile(o.record, data, 0644); err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+	return nil
+}
+
+// request converts req to its scrubbed cassette form
+func (o *vcrOptions) request(req *http.Request, body string) vcrRequest {
+	u := *req.URL
+	query := u.Query()
+	for name, values := range query {
+		if o.secret(name) {
+			for i := range values {
+				values[i] = vcrRedacted
+			}
+		}
+	}
+	u.RawQuery = query.Encode()
+	if u.User != nil {
+		u.User = url.User("redacted")
+	}
+	return vcrRequest{
+		Method:  req.Method,
+		URL:     u.String(),
+		Headers: o.scrubHeaders(req.Header),
+		Body:    o.scrubBody(body, req.Header.Get("Content-Type")),
+	}
+}
+
+// matches compares the --match properties of two requests
+func (o *vcrOptions) matches(a, b vcrRequest) bool {
+	ua, errA := url.Parse(a.URL)
+	ub, errB := url.Parse(b.URL)
+	if errA != nil || errB != nil {
+		return false
+	}
+	for _, m := range o.match {
+		var same bool
+		switch m {
+		case "method":
+			same = strings.EqualFold(a.Method, b.Method)
+		case "url":
+			same = ua.Scheme == ub.Scheme && ua.Host == ub.Host && ua.Path == ub.Path && ua.Query().Encode() == ub.Query().Encode()
+		case "host":
+			same = ua.Host == ub.Host
+		case "path":
+			same = ua.Path == ub.Path
+		case "query":
+			same = ua.Query().Encode() == ub.Query().Encode()
+		case "body":
+			same = vcrSameBody(a.Body, b.Body)
+		default:
+			name := strings.TrimPrefix(m, "header:")
+			same = reflect.DeepEqual(a.Headers[name], b.Headers[name])
+		}
+		if !same {
+			return false
+		}
+	}
+	return true
+}
+
+// response rebuilds the recorded response
+func (i *vcrInteraction) response() (*http.Response, []byte, error) {
+	body := []byte(i.Response.Body)
+	if i.Response.BodyEncoding == "base64" {
+		var err error
+		if body, err = base64.StdEncoding.DecodeString(i.Response.Body); err != nil {
+			return nil, nil, fmt.Errorf("invalid recorded body: %w", err)
+		}
+	}
+	resp := &http.Response{
+		Status:     i.Response.Status,
+		StatusCode: i.Response.StatusCode,
+		Header:     http.Header(i.Response.Headers),
+	}
+	if resp.Header == nil {
+		resp.Header = http.Header{}
+	}
+	return resp, body, nil
+}
+
+// secret reports whether a header, parameter or field name holds a secret
+func (o *vcrOptions) secret(name string) bool {
+	for _, s := range o.scrub {
+		if strings.EqualFold(name, s) {
+			return true
+		}
+	}
+	normalized := strings.NewReplacer("-", "", "_", "").Replace(strings.ToLower(name))
+	for _, marker := range vcrSecretMarkers {
+		if strings.HasSuffix(normalized, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+func (o *vcrOptions) scrubHeaders(header http.Header) map[string][]string {
+	if len(header) == 0 {
+		return nil
+	}
+	headers := make(map[string][]string, len(header))
+	for name, values := range header {
+		values = append([]string{}, values...)
+		if o.secret(name) {
+			for i := range values {
+				values[i] = vcrRedacted
+			}
+		}
+		headers[name] = values
+	}
+	return headers
+}
+
+// scrubBody redacts secret fields of a JSON or form body; other bodies are
+// kept as is
+func (o *vcrOptions) scrubBody(body, contentType string) string {
+	if strings.HasPrefix(contentType, "application/x-www-form-urlencoded") {
+		form, err := url.ParseQuery(body)
+		if err != nil {
+			return body
+		}
+		changed := false
+		for name, values := range form {
+			if o.secret(name) {
+				for i := range values {
+					values[i] = vcrRedacted
+				}
+				changed = true
+			}
+		}
+		if !changed {
+			return body
+		}
+		return form.Encode()
+	}
+
+	var v interface{}
+	if json.Unmarshal([]byte(body), &v) != nil || !o.scrubJSON(v) {
+		return body
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return body
+	}
+	return string(data)
+}
+
+// scrubJSON redacts secret fields in place and reports whether any were found
+func (o *vcrOptions) scrubJSON(v interface{}) bool {
+	changed := false
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if _, isString := value.(string); isString && o.secret(key) {
+				v[key] = vcrRedacted
+				changed = true
+			} else if o.scrubJSON(value) {
+				changed = true
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			if o.scrubJSON(item) {
+				changed = true
+			}
+		}
+	}
+	return changed
+}
+
+// vcrSameBody compares bodies, as JSON values when both are JSON
+func vcrSameBody(a, b string) bool {
+	var va, vb interface{}
+	if json.Unmarshal([]byte(a), &va) == nil && json.Unmarshal([]byte(b), &vb) == nil {
+		return reflect.DeepEqual(va, vb)
+	}
+	return a == b
+}
+
+func loadVCRCassette(path string) (*vcrCassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cassette: %w", err)
+	}
+	var cassette vcrCassette
+	if err := yaml.Unmarshal(data, &cassette); err != nil {
+		return nil, fmt.Errorf("failed to parse cassette %s: %w", path, err)
+	}
+	return &cassette, nil
+}
@@ -4,12 +4,17 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
+	"net/http/httptrace"
 	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
+	"devkit/internal/expand"
+	"devkit/internal/netutil"
 	"devkit/internal/output"
+	"devkit/internal/workspace"
 )
 
 // httpCmd represents the http command group
@@ -18,10 +23,29 @@ var httpCmd = &cobra.Command{
 	Short: "HTTP request operations",
 	Long: `Send HTTP requests (GET, POST, PUT, DELETE).
 
+Inside a workspace, URLs without a scheme are relative to workspace.http.base_url
+and workspace.http.headers are sent with every request (--header overrides them).
+
+-4 and -6 force IPv4 or IPv6; the address the response came from and its
+family are reported (behind a proxy, that is the proxy's address).
+
+--record saves the request and response to a YAML cassette, replacing an
+interaction recorded for the same request; --replay answers from the
+cassette without touching the network, so tests and demos are repeatable.
+Requests are matched on --match (default: method and url; also host, path,
+query, body, which compares JSON by value, and header:<name>). Headers,
+query parameters and JSON fields with secret-looking names (Authorization,
+Cookie, *token*, *password*, ...) or named with --scrub are redacted when
+recording, and in the live request before it is matched on replay.
+
 Examples:
   devkit net http get https://api.example.com/users
   devkit net http post https://api.example.com/users --data '{"name":"John"}'
-  devkit net http get https://api.example.com --header "Authorization: Bearer token"`,
+  devkit net http get https://api.example.com --header "Authorization: Bearer token"
+  devkit net http get /users
+  devkit net http get https://api.example.com/health -6
+  devkit net http get https://api.example.com/users --record users.yaml
+  devkit net http post https://api.example.com/login --data '{"user":"dev"}' --replay api.yaml --match method,url,body`,
 }
 
 // httpGetCmd represents the get subcommand
@@ -63,6 +87,8 @@ func init() {
 	for _, cmd := range []*cobra.Command{httpGetCmd, httpPostCmd, httpPutCmd, httpDeleteCmd} {
 		cmd.Flags().StringSliceP("header", "H", []string{}, "HTTP headers (key:value)")
 		cmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
+		netutil.AddFamilyFlags(cmd)
+		addVCRFlags(cmd)
 	}
 
 	httpPostCmd.Flags().StringP("data", "d", "", "Request body data")
@@ -94,19 +120,40 @@ func runHTTPRequest(cmd *cobra.Command, args []string, method, body string) erro
 
 	url := args[0]
 	headers, _ := cmd.Flags().GetStringSlice("header")
+	if ws := workspace.Current(); ws != nil {
+		baseURL, wsHeaders := ws.HTTP.BaseURL, ws.HTTP.Headers
+		// Workspace values may reference secrets as {{env.NAME}}
+		if expand.Enabled() {
+			var err error
+			if baseURL, err = expand.String(baseURL); err != nil {
+				return fmt.Errorf("workspace base_url: %w", err)
+			}
+			if wsHeaders, err = expand.Strings(wsHeaders); err != nil {
+				return fmt.Errorf("workspace headers: %w", err)
+			}
+		}
+		url = withBaseURL(baseURL, url)
+		headers = append(append([]string{}, wsHeaders...), headers...)
+	}
 	outputFormat, _ := cmd.Flags().GetString("output")
 	format := output.OutputFormat(outputFormat)
 
-	client := &http.Client{
-		Timeout: 10 * time.Second,
+	opts, err := netutil.FromCommand(cmd, 10*time.Second)
+	if err != nil {
+		return err
 	}
+	vcr, err := vcrFromCommand(cmd)
+	if err != nil {
+		return err
+	}
+	client := opts.HTTPClient()
 
 	var reqBody io.Reader
 	if body != "" {
 		reqBody = bytes.NewBufferString(body)
 	}
 
-	req, err := http.NewRequest(method, url, reqBody)
+	req, err := http.NewRequestWithContext(cmd.Context(), method, url, reqBody)
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
@@ -123,15 +170,42 @@ func runHTTPRequest(cmd *cobra.Command, args []string, method, body string) erro
 		req.Header.Set("Content-Type", "application/json")
 	}
 
-	resp, err := client.Do(req)
-	if err != nil {
-		return fmt.Errorf("request failed: %w", err)
-	}
-	defer resp.Body.Close()
+	var resp *http.Response
+	var respBody []byte
+	var remote net.Addr
+	var replayed *vcrInteraction
+	if vcr != nil && vcr.replay != "" {
+		if replayed, err = vcr.find(req, body); err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+		if resp, respBody, err = replayed.response(); err != nil {
+			return err
+		}
+	} else {
+		// Record the connection that served the last attempt
+		req = req.WithContext(httptrace.WithClientTrace(req.Context(), &httptrace.ClientTrace{
+			GotConn: func(info httptrace.GotConnInfo) {
+				remote = info.Conn.RemoteAddr()
+			},
+		}))
+
+		resp, err = opts.Do(client, req)
+		if err != nil {
+			return fmt.Errorf("request failed: %w", err)
+		}
+		defer resp.Body.Close()
 
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return fmt.Errorf("failed to read response: %w", err)
+		respBody, err = io.ReadAll(resp.Body)
+		if err != nil {
+			return fmt.Errorf("failed to read response: %w", err)
+		}
+
+		if vcr != nil {
+			if err := vcr.save(req, body, resp, respBody); err != nil {
+				return err
+			}
+		}
 	}
 
 	result := map[string]interface{}{
@@ -142,13 +216,36 @@ func runHTTPRequest(cmd *cobra.Command, args []string, method, body string) erro
 		"headers":     resp.Header,
 		"body":        string(respBody),
 	}
+	if remote != nil {
+		result["remote_address"] = remote.String()
+		result["family"] = netutil.Family(remote)
+	}
+	if replayed != nil {
+		result["replayed_from"] = vcr.replay
+		result["recorded_at"] = replayed.RecordedAt
+	}
 
 	if format == output.FormatJSON {
 		output.PrintSuccess(format, result)
 	} else {
 		fmt.Printf("Status: %s\n", resp.Status)
+		if remote != nil {
+			fmt.Printf("Remote: %s (%s)\n", remote, result["family"])
+		}
+		if replayed != nil {
+			fmt.Printf("Replayed: %s (recorded %s)\n", vcr.replay, replayed.RecordedAt.Local().Format(time.RFC3339))
+		}
 		fmt.Printf("Response:\n%s\n", string(respBody))
 	}
 
 	return nil
 }
+
+// withBaseURL prepends the workspace base URL to request URLs without a
+// scheme, so that "/users" becomes "https://api.example.com/users"
+func withBaseURL(baseURL, url string) string {
+	if baseURL == "" || strings.Contains(url, "://") {
+		return url
+	}
+	return strings.TrimRight(baseURL, "/") + "/" + strings.TrimLeft(url, "/")
+}
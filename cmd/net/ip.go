@@ -6,8 +6,10 @@ import (
 	"net"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
+	"devkit/internal/netutil"
 	"devkit/internal/output"
 )
 
@@ -17,10 +19,14 @@ var ipCmd = &cobra.Command{
 	Short: "IP address information",
 	Long: `Get IP address information (public/private) and geolocation.
 
+With --targets, information about every address listed in the file (one
+per line, - for stdin) is printed as one table or JSON report.
+
 Examples:
   devkit net ip                    # Public IP
   devkit net ip --local            # Local IP
-  devkit net ip info 8.8.8.8       # IP information`,
+  devkit net ip --info 8.8.8.8     # IP information
+  devkit net ip --targets ips.txt  # Information about many IPs`,
 	RunE: runIP,
 }
 
@@ -30,6 +36,7 @@ func init() {
 	ipCmd.Flags().BoolP("local", "l", false, "Show local IP address")
 	ipCmd.Flags().StringP("info", "i", "", "Get information about an IP address")
 	ipCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
+	addBatchFlags(ipCmd)
 }
 
 func runIP(cmd *cobra.Command, args []string) error {
@@ -38,19 +45,40 @@ func runIP(cmd *cobra.Command, args []string) error {
 	outputFormat, _ := cmd.Flags().GetString("output")
 	format := output.OutputFormat(outputFormat)
 
+	if targets, batch, err := batchTargets(cmd, nil); err != nil {
+		return err
+	} else if batch {
+		if infoIP != "" {
+			targets = append([]string{infoIP}, targets...)
+		}
+		results := runBatch(cmd, targets, ipInfo)
+		return printBatch(cmd, format, results, []batchColumn{
+			{Header: "VERSION", Key: "version"},
+			{Header: "PRIVATE", Key: "private"},
+		})
+	}
+
 	if infoIP != "" {
 		return showIPInfo(infoIP, format)
 	}
 
+	opts, err := netutil.FromCommand(cmd, 10*time.Second)
+	if err != nil {
+		return err
+	}
 	if local {
-		return showLocalIP(format)
+		return showLocalIP(cmd, opts, format)
 	}
 
-	return showPublicIP(format)
+	return showPublicIP(cmd, opts, format)
 }
 
-func showPublicIP(format output.OutputFormat) error {
-	resp, err := http.Get("https://api.ipify.org")
+func showPublicIP(cmd *cobra.Command, opts netutil.Options, format output.OutputFormat) error {
+	req, err := http.NewRequestWithContext(cmd.Context(), http.MethodGet, "https://api.ipify.org", nil)
+	if err != nil {
+		return fmt.Errorf("failed to get public IP: %w", err)
+	}
+	resp, err := opts.Do(opts.HTTPClient(), req)
 	if err != nil {
 		return fmt.Errorf("failed to get public IP: %w", err)
 	}
@@ -77,8 +105,10 @@ func showPublicIP(format output.OutputFormat) error {
 	return nil
 }
 
-func showLocalIP(format output.OutputFormat) error {
-	conn, err := net.Dial("udp", "8.8.8.8:80")
+func showLocalIP(cmd *cobra.Command, opts netutil.Options, format output.OutputFormat) error {
+	// Connecting a UDP socket sends nothing; it only selects the local
+	// address of the default route
+	conn, err := opts.Dialer().DialContext(cmd.Context(), "udp", "8.8.8.8:80")
 	if err != nil {
 		return fmt.Errorf("failed to get local IP: %w", err)
 	}
@@ -102,9 +132,27 @@ func showLocalIP(format output.OutputFormat) error {
 }
 
 func showIPInfo(ipStr string, format output.OutputFormat) error {
+	result, err := ipInfo(ipStr)
+	if err != nil {
+		return err
+	}
+
+	if format == output.FormatJSON {
+		output.PrintSuccess(format, result)
+	} else {
+		fmt.Printf("IP: %s\n", ipStr)
+		fmt.Printf("Version: %s\n", result["version"])
+		fmt.Printf("Private: %v\n", result["private"])
+	}
+
+	return nil
+}
+
+// ipInfo describes an IP address
+func ipInfo(ipStr string) (map[string]interface{}, error) {
 	ip := net.ParseIP(ipStr)
 	if ip == nil {
-		return fmt.Errorf("invalid IP address: %s", ipStr)
+		return nil, fmt.Errorf("invalid IP address: %s", ipStr)
 	}
 
 	result := map[string]interface{}{
@@ -116,16 +164,7 @@ func showIPInfo(ipStr string, format output.OutputFormat) error {
 	if ip.To4() == nil {
 		result["version"] = "IPv6"
 	}
-
-	if format == output.FormatJSON {
-		output.PrintSuccess(format, result)
-	} else {
-		fmt.Printf("IP: %s\n", ipStr)
-		fmt.Printf("Version: %s\n", result["version"])
-		fmt.Printf("Private: %v\n", result["private"])
-	}
-
-	return nil
+	return result, nil
 }
 
 func isPrivateIP(ip net.IP) bool {
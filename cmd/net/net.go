@@ -2,6 +2,7 @@ package net
 
 import (
 	"github.com/spf13/cobra"
+	"devkit/internal/netutil"
 )
 
 // netCmd represents the net command group
@@ -19,11 +20,18 @@ This command group includes utilities for:
 - SSL certificate information
 - Whois queries
 - Internet speed testing
+- Throughput tests between machines
 - System information
 - Process management
 - Disk usage analysis
 - Network interfaces
-- Open ports monitoring`,
+- Open ports monitoring
+- Local mock servers
+
+Every command that talks to the network accepts --timeout (per attempt, with
+a default suited to the command), --retries and --retry-delay. HTTP requests
+and TLS connections use the proxy from HTTPS_PROXY/HTTP_PROXY or --proxy and
+trust the extra CAs given with --ca-cert; --insecure skips verification.`,
 }
 
 // GetNetCmd returns the net command
@@ -32,6 +40,6 @@ func GetNetCmd() *cobra.Command {
 }
 
 func init() {
-	// This will be called when the package is imported
-	// Commands will be added in their respective files
+	// Subcommands are added in their respective files
+	netutil.AddFlags(netCmd)
 }
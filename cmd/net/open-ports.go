@@ -3,9 +3,11 @@ package net
 import (
 	"fmt"
 	"net"
-	"strings"
+	"strconv"
+	"time"
 
 	"github.com/spf13/cobra"
+	"devkit/internal/netutil"
 	"devkit/internal/output"
 )
 
@@ -38,6 +40,11 @@ func runOpenPorts(cmd *cobra.Command, args []string) error {
 	}
 
 	var ports []map[string]interface{}
+	opts, err := netutil.FromCommand(cmd, time.Second)
+	if err != nil {
+		return err
+	}
+	dialer := opts.Dialer()
 
 	for _, iface := range interfaces {
 		addrs, _ := iface.Addrs()
@@ -50,7 +57,7 @@ func runOpenPorts(cmd *cobra.Command, args []string) error {
 			// Check common ports
 			commonPorts := []int{22, 80, 443, 3306, 5432, 8080, 9000}
 			for _, port := range commonPorts {
-				conn, err := net.Dial("tcp", fmt.Sprintf("%s:%d", ip, port))
+				conn, err := dialer.DialContext(cmd.Context(), "tcp", net.JoinHostPort(ip.String(), strconv.Itoa(port)))
 				if err == nil {
 					conn.Close()
 					ports = append(ports, map[string]interface{}{
@@ -70,8 +77,8 @@ func runOpenPorts(cmd *cobra.Command, args []string) error {
 			"count": len(ports),
 		})
 	} else if format == output.FormatTable {
-		fmt.Printf("%-10s %-10s %-20s %-10s\n", "PORT", "PROTOCOL", "ADDRESS", "STATUS")
-		fmt.Println(strings.Repeat("-", 55))
+		fmt.Println(output.Accent(fmt.Sprintf("%-10s %-10s %-20s %-10s", "PORT", "PROTOCOL", "ADDRESS", "STATUS")))
+		fmt.Println(output.Rule(55))
 		for _, port := range ports {
 			fmt.Printf("%-10d %-10s %-20s %-10s\n",
 				port["port"], port["protocol"], port["address"], port["status"])
@@ -0,0 +1,735 @@
+package net
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/spf13/cobra"
+	"devkit/internal/output"
+)
+
+// perfCmd represents the perf command group
+var perfCmd = &cobra.Command{
+	Use:   "perf",
+	Short: "TCP throughput and latency test between two machines",
+	Long: `Measure TCP throughput, latency and retransmits between two machines
+running devkit, as a dependency-free stand-in for iperf on LAN and VPN links.
+
+Start "perf server" on one machine and "perf client <host>" on the other.
+The client uploads to the server by default; --reverse makes the server
+send. Throughput is measured at the receiver. Latency is measured on the
+control connection before the transfer (idle) and during it (under load,
+which shows buffering on the path). Retransmits are read from the kernel
+on Linux and are not reported elsewhere.
+
+Examples:
+  devkit net perf server
+  devkit net perf client 192.168.1.20
+  devkit net perf client vpn-host --duration 20s --streams 4 --reverse`,
+}
+
+// perfServerCmd represents the perf server subcommand
+var perfServerCmd = &cobra.Command{
+	Use:   "server",
+	Short: "Accept perf tests from clients",
+	Long: `Wait for "devkit net perf client" connections and serve their tests until
+interrupted with Ctrl+C, or after one test with --once.
+
+Examples:
+  devkit net perf server
+  devkit net perf server --port 5201 --bind 10.0.0.2
+  devkit net perf server --once`,
+	Args: cobra.NoArgs,
+	RunE: runPerfServer,
+}
+
+// perfClientCmd represents the perf client subcommand
+var perfClientCmd = &cobra.Command{
+	Use:   "client <host>",
+	Short: "Run a perf test against a server",
+	Long: `Connect to a "devkit net perf server", measure latency, then transfer
+data over --streams parallel TCP connections for --duration and report the
+throughput every --interval and in total.
+
+Examples:
+  devkit net perf client 192.168.1.20
+  devkit net perf client 192.168.1.20 --duration 10s --streams 4
+  devkit net perf client vpn-host --reverse --output json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPerfClient,
+}
+
+func init() {
+	netCmd.AddCommand(perfCmd)
+	perfCmd.AddCommand(perfServerCmd)
+	perfCmd.AddCommand(perfClientCmd)
+
+	perfServerCmd.Flags().Int("port", perfDefaultPort, "Port to listen on")
+	perfServerCmd.Flags().String("bind", "", "Address to bind, e.g. 10.0.0.2 (default: all interfaces)")
+	perfServerCmd.Flags().Bool("once", false, "Exit after one test")
+
+	perfClientCmd.Flags().Int("port", perfDefaultPort, "Server port")
+	perfClientCmd.Flags().Duration("duration", 10*time.Second, "Length of the transfer")
+	perfClientCmd.Flags().Int("streams", 1, "Parallel TCP connections")
+	perfClientCmd.Flags().Bool("reverse", false, "Let the server send (download) instead of the client")
+	perfClientCmd.Flags().Duration("interval", time.Second, "Reporting interval (0 to disable)")
+	perfClientCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
+}
+
+const (
+	perfDefaultPort = 5201
+	perfVersion     = 1
+	perfBufferSize  = 128 * 1024
+	perfIdlePings   = 10
+	perfMaxStreams  = 128
+)
+
+// perfMessage is a line of the JSON protocol spoken on the control
+// connection; data connections send one "data" message and then raw bytes
+type perfMessage struct {
+	Type        string  `json:"type"`
+	Version     int     `json:"version,omitempty"`
+	ID          string  `json:"id,omitempty"`
+	Streams     int     `json:"streams,omitempty"`
+	Stream      int     `json:"stream,omitempty"`
+	Reverse     bool    `json:"reverse,omitempty"`
+	DurationMS  int64   `json:"duration_ms,omitempty"`
+	Seq         int     `json:"seq,omitempty"`
+	Bytes       int64   `json:"bytes,omitempty"`
+	Seconds     float64 `json:"seconds,omitempty"`
+	Retransmits *int64  `json:"retransmits,omitempty"`
+	Error       string  `json:"error,omitempty"`
+}
+
+// perfConn reads and writes perfMessages
+type perfConn struct {
+	net.Conn
+	r *bufio.Reader
+}
+
+func newPerfConn(conn net.Conn) *perfConn {
+	return &perfConn{Conn: conn, r: bufio.NewReader(conn)}
+}
+
+func (c *perfConn) send(msg perfMessage) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	_, err = c.Write(append(data, '\n'))
+	return err
+}
+
+func (c *perfConn) receive() (perfMessage, error) {
+	var msg perfMessage
+	line, err := c.r.ReadBytes('\n')
+	if err != nil {
+		return msg, err
+	}
+	if err := json.Unmarshal(line, &msg); err != nil {
+		return msg, fmt.Errorf("invalid message from peer: %w", err)
+	}
+	if msg.Type == "error" {
+		return msg, errors.New(msg.Error)
+	}
+	return msg, nil
+}
+
+// perfSession is a test in progress on the server
+type perfSession struct {
+	id       string
+	streams  int
+	reverse  bool
+	duration time.Duration
+
+	mu      sync.Mutex
+	conns   []*perfConn
+	ready   chan struct{}
+	started time.Time
+	last    time.Time
+	bytes   atomic.Int64
+	wg      sync.WaitGroup
+}
+
+// perfServer tracks the sessions of a perf server
+type perfServer struct {
+	mu       sync.Mutex
+	sessions map[string]*perfSession
+	done     chan struct{}
+	once     bool
+}
+
+func runPerfServer(cmd *cobra.Command, args []string) error {
+	port, _ := cmd.Flags().GetInt("port")
+	bind, _ := cmd.Flags().GetString("bind")
+	once, _ := cmd.Flags().GetBool("once")
+
+	if port < 1 || port > 65535 {
+		return fmt.Errorf("invalid port: %d", port)
+	}
+	cmd.SilenceUsage = true
+
+	listener, err := net.Listen("tcp", net.JoinHostPort(bind, strconv.Itoa(port)))
+	if err != nil {
+		return fmt.Errorf("failed to listen: %w", err)
+	}
+	defer listener.Close()
+
+	server := &perfServer{sessions: map[string]*perfSession{}, done: make(chan struct{}), once: once}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go server.handle(conn)
+		}
+	}()
+
+	fmt.Printf("%-5s %s\n", "PERF", output.Accent(listener.Addr().String()))
+	fmt.Println(output.Muted("Run: devkit net perf client <this host> --port " + strconv.Itoa(port)))
+	if once {
+		<-server.done
+		return nil
+	}
+	fmt.Println(output.Muted("Press Ctrl+C to stop"))
+	waitForInterrupt()
+	return nil
+}
+
+// handle dispatches a new connection on its first message
+func (s *perfServer) handle(raw net.Conn) {
+	conn := newPerfConn(raw)
+	raw.SetReadDeadline(time.Now().Add(10 * time.Second))
+	msg, err := conn.receive()
+	raw.SetReadDeadline(time.Time{})
+	if err != nil {
+		raw.Close()
+		return
+	}
+
+	switch msg.Type {
+	case "hello":
+		s.control(conn, msg)
+	case "data":
+		s.mu.Lock()
+		session := s.sessions[msg.ID]
+		s.mu.Unlock()
+		if session == nil {
+			conn.send(perfMessage{Type: "error", Error: "unknown test"})
+			raw.Close()
+			return
+		}
+		session.mu.Lock()
+		if len(session.conns) >= session.streams {
+			session.mu.Unlock()
+			raw.Close()
+			return
+		}
+		session.conns = append(session.conns, conn)
+		if len(session.conns) == session.streams {
+			close(session.ready)
+		}
+		session.mu.Unlock()
+		if !session.reverse {
+			go session.receive(conn)
+		}
+	default:
+		raw.Close()
+	}
+}
+
+// control runs a test for the client on its control connection
+func (s *perfServer) control(conn *perfConn, hello perfMessage) {
+	defer conn.Close()
+
+	if hello.Version != perfVersion {
+		conn.send(perfMessage{Type: "error", Error: fmt.Sprintf("unsupported protocol version %d (server speaks %d)", hello.Version, perfVersion)})
+		return
+	}
+	if hello.Streams < 1 || hello.Streams > perfMaxStreams || hello.DurationMS <= 0 {
+		conn.send(perfMessage{Type: "error", Error: "invalid test parameters"})
+		return
+	}
+
+	id := make([]byte, 8)
+	rand.Read(id)
+	session := &perfSession{
+		id:       hex.EncodeToString(id),
+		streams:  hello.Streams,
+		reverse:  hello.Reverse,
+		duration: time.Duration(hello.DurationMS) * time.Millisecond,
+		ready:    make(chan struct{}),
+	}
+	session.wg.Add(session.streams)
+	s.mu.Lock()
+	s.sessions[session.id] = session
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.sessions, session.id)
+		s.mu.Unlock()
+		session.mu.Lock()
+		for _, c := range session.conns {
+			c.Close()
+		}
+		session.mu.Unlock()
+	}()
+
+	direction := "upload"
+	if session.reverse {
+		direction = "download"
+	}
+	serveLog("%s connected: %d streams, %s, %s", conn.RemoteAddr(), session.streams, session.duration, direction)
+	if err := conn.send(perfMessage{Type: "welcome", ID: session.id}); err != nil {
+		return
+	}
+
+	for {
+		msg, err := conn.receive()
+		if err != nil {
+			return
+		}
+		switch msg.Type {
+		case "ping":
+			conn.send(perfMessage{Type: "pong", Seq: msg.Seq})
+		case "start":
+			select {
+			case <-session.ready:
+			case <-time.After(10 * time.Second):
+				conn.send(perfMessage{Type: "error", Error: "not all data connections arrived"})
+				return
+			}
+			session.mu.Lock()
+			session.started = time.Now()
+			conns := append([]*perfConn{}, session.conns...)
+			session.mu.Unlock()
+			if session.reverse {
+				for _, c := range conns {
+					go session.send(c)
+				}
+			}
+			conn.send(perfMessage{Type: "started"})
+		case "result":
+			waited := make(chan struct{})
+			go func() {
+				session.wg.Wait()
+				close(waited)
+			}()
+			select {
+			case <-waited:
+			case <-time.After(session.duration + 30*time.Second):
+			}
+
+			result := perfMessage{Type: "result", Bytes: session.bytes.Load()}
+			session.mu.Lock()
+			if !session.last.IsZero() {
+				result.Seconds = session.last.Sub(session.started).Seconds()
+			}
+			if session.reverse {
+				result.Retransmits = perfRetransmits(session.conns)
+			}
+			session.mu.Unlock()
+			conn.send(result)
+
+			serveLog("%s done: %s in %.2f s, %s", conn.RemoteAddr(), formatBytes(uint64(result.Bytes)), result.Seconds, perfRate(result.Bytes, result.Seconds))
+			if s.once {
+				close(s.done)
+			}
+			return
+		}
+	}
+}
+
+// receive counts the bytes arriving on a data connection until the client
+// closes it
+func (p *perfSession) receive(conn *perfConn) {
+	defer p.wg.Done()
+	buf := make([]byte, perfBufferSize)
+	for {
+		n, err := conn.r.Read(buf)
+		if n > 0 {
+			p.bytes.Add(int64(n))
+			p.mu.Lock()
+			p.last = time.Now()
+			p.mu.Unlock()
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// send writes to a data connection for the duration of the test, then
+// closes its sending side
+func (p *perfSession) send(conn *perfConn) {
+	defer p.wg.Done()
+	buf := make([]byte, perfBufferSize)
+	rand.Read(buf)
+	deadline := time.Now().Add(p.duration)
+	conn.SetWriteDeadline(deadline)
+	for time.Now().Before(deadline) {
+		n, err := conn.Write(buf)
+		p.bytes.Add(int64(n))
+		if err != nil {
+			break
+		}
+	}
+	p.mu.Lock()
+	p.last = time.Now()
+	p.mu.Unlock()
+	if tcp, ok := conn.Conn.(*net.TCPConn); ok {
+		tcp.CloseWrite()
+	}
+}
+
+// perfInterval is the transfer during one reporting interval
+type perfInterval struct {
+	Start   float64 `json:"start"`
+	End     float64 `json:"end"`
+	Bytes   int64   `json:"bytes"`
+	Bitrate float64 `json:"bits_per_second"`
+}
+
+// perfLatency summarizes round-trip times in milliseconds
+type perfLatency struct {
+	Samples int     `json:"samples"`
+	Min     float64 `json:"min_ms"`
+	Avg     float64 `json:"avg_ms"`
+	Max     float64 `json:"max_ms"`
+}
+
+func runPerfClient(cmd *cobra.Command, args []string) error {
+	port, _ := cmd.Flags().GetInt("port")
+	duration, _ := cmd.Flags().GetDuration("duration")
+	streams, _ := cmd.Flags().GetInt("streams")
+	reverse, _ := cmd.Flags().GetBool("reverse")
+	interval, _ := cmd.Flags().GetDuration("interval")
+	outputFormat, _ := cmd.Flags().GetString("output")
+	format := output.OutputFormat(outputFormat)
+
+	if port < 1 || port > 65535 {
+		return fmt.Errorf("invalid port: %d", port)
+	}
+	if duration < time.Second {
+		return fmt.Errorf("duration must be at least 1s")
+	}
+	if streams < 1 || streams > perfMaxStreams {
+		return fmt.Errorf("streams must be between 1 and %d", perfMaxStreams)
+	}
+	cmd.SilenceUsage = true
+	plain := format != output.FormatJSON
+
+	addr := net.JoinHostPort(args[0], strconv.Itoa(port))
+	raw, err := net.DialTimeout("tcp", addr, 5*time.Second)
+	if err != nil {
+		return fmt.Errorf("failed to connect to %s: %w", addr, err)
+	}
+	control := newPerfConn(raw)
+	defer control.Close()
+
+	control.SetDeadline(time.Now().Add(10 * time.Second))
+	if err := control.send(perfMessage{Type: "hello", Version: perfVersion, Streams: streams, Reverse: reverse, DurationMS: duration.Milliseconds()}); err != nil {
+		return fmt.Errorf("failed to start test: %w", err)
+	}
+	welcome, err := control.receive()
+	if err != nil {
+		return fmt.Errorf("server refused the test: %w", err)
+	}
+
+	direction := "upload"
+	if reverse {
+		direction = "download"
+	}
+	if plain {
+		fmt.Printf("Connected to %s (%d streams, %s, %s)\n", raw.RemoteAddr(), streams, duration, direction)
+	}
+
+	// Idle latency
+	var idle []time.Duration
+	for seq := 1; seq <= perfIdlePings; seq++ {
+		rtt, err := perfPing(control, seq)
+		if err != nil {
+			return fmt.Errorf("latency test failed: %w", err)
+		}
+		idle = append(idle, rtt)
+		time.Sleep(20 * time.Millisecond)
+	}
+	idleLatency := summarizePerfLatency(idle)
+	if plain {
+		fmt.Printf("Latency (idle): min %.2f ms  avg %.2f ms  max %.2f ms\n", idleLatency.Min, idleLatency.Avg, idleLatency.Max)
+	}
+
+	// Data connections
+	conns := make([]*perfConn, streams)
+	for i := range conns {
+		c, err := net.DialTimeout("tcp", addr, 5*time.Second)
+		if err != nil {
+			return fmt.Errorf("failed to open stream %d: %w", i+1, err)
+		}
+		conns[i] = newPerfConn(c)
+		defer conns[i].Close()
+		if err := conns[i].send(perfMessage{Type: "data", ID: welcome.ID, Stream: i + 1}); err != nil {
+			return fmt.Errorf("failed to open stream %d: %w", i+1, err)
+		}
+	}
+
+	if err := control.send(perfMessage{Type: "start"}); err != nil {
+		return fmt.Errorf("failed to start transfer: %w", err)
+	}
+	if _, err := control.receive(); err != nil {
+		return fmt.Errorf("failed to start transfer: %w", err)
+	}
+	control.SetDeadline(time.Time{})
+
+	var transferred atomic.Int64
+	var wg sync.WaitGroup
+	start := time.Now()
+	deadline := start.Add(duration)
+	for _, c := range conns {
+		wg.Add(1)
+		go func(c *perfConn) {
+			defer wg.Done()
+			if reverse {
+				perfReceive(c, &transferred, deadline.Add(30*time.Second))
+			} else {
+				perfSend(c, &transferred, deadline)
+			}
+		}(c)
+	}
+	finished := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(finished)
+	}()
+
+	// Latency under load, measured until the transfer ends
+	var loaded []time.Duration
+	pingsDone := make(chan struct{})
+	go func() {
+		defer close(pingsDone)
+		for seq := perfIdlePings + 1; ; seq++ {
+			select {
+			case <-finished:
+				return
+			case <-time.After(250 * time.Millisecond):
+			}
+			control.SetDeadline(time.Now().Add(5 * time.Second))
+			rtt, err := perfPing(control, seq)
+			if err != nil {
+				return
+			}
+			loaded = append(loaded, rtt)
+		}
+	}()
+
+	intervals := []perfInterval{}
+	var ticker <-chan time.Time
+	if interval > 0 {
+		t := time.NewTicker(interval)
+		defer t.Stop()
+		ticker = t.C
+		if plain {
+			fmt.Printf("%-14s %12s %14s\n", "Interval", "Transfer", "Bitrate")
+		}
+	}
+	var lastBytes int64
+	lastTime := start
+	for waiting := true; waiting; {
+		select {
+		case <-finished:
+			waiting = false
+		case now := <-ticker:
+			total := transferred.Load()
+			iv := perfInterval{
+				Start: lastTime.Sub(start).Seconds(),
+				End:   now.Sub(start).Seconds(),
+				Bytes: total - lastBytes,
+			}
+			iv.Bitrate = float64(iv.Bytes*8) / (iv.End - iv.Start)
+			intervals = append(intervals, iv)
+			if plain {
+				fmt.Printf("%5.1f-%-5.1f s %12s %14s\n", iv.Start, iv.End, formatBytes(uint64(iv.Bytes)), perfRate(iv.Bytes, iv.End-iv.Start))
+			}
+			lastBytes, lastTime = total, now
+		}
+	}
+	elapsed := time.Since(start)
+	<-pingsDone
+
+	control.SetDeadline(time.Now().Add(duration + 40*time.Second))
+	if err := control.send(perfMessage{Type: "result"}); err != nil {
+		return fmt.Errorf("failed to get results: %w", err)
+	}
+	serverResult, err := control.receive()
+	if err != nil {
+		return fmt.Errorf("failed to get results: %w", err)
+	}
+
+	// Throughput is measured where the data arrives
+	bytes, seconds := serverResult.Bytes, serverResult.Seconds
+	retransmits := serverResult.Retransmits
+	if reverse {
+		bytes, seconds = transferred.Load(), elapsed.Seconds()
+	} else {
+		retransmits = perfRetransmits(conns)
+	}
+	if seconds <= 0 {
+		seconds = elapsed.Seconds()
+	}
+	loadedLatency := summarizePerfLatency(loaded)
+
+	if !plain {
+		result := map[string]interface{}{
+			"server":          raw.RemoteAddr().String(),
+			"direction":       direction,
+			"streams":         streams,
+			"duration":        duration.String(),
+			"bytes":           bytes,
+			"seconds":         seconds,
+			"bits_per_second": float64(bytes*8) / seconds,
+			"sender_bytes":    transferred.Load(),
+			"latency_idle":    idleLatency,
+			"latency_loaded":  loadedLatency,
+			"intervals":       intervals,
+		}
+		if reverse {
+			result["sender_bytes"] = serverResult.Bytes
+		}
+		if retransmits != nil {
+			result["retransmits"] = *retransmits
+		}
+		output.PrintSuccess(format, result)
+		return nil
+	}
+
+	fmt.Println()
+	label := "Upload"
+	if reverse {
+		label = "Download"
+	}
+	fmt.Printf("%s: %s in %.2f s = %s\n", label, formatBytes(uint64(bytes)), seconds, output.Accent(perfRate(bytes, seconds)))
+	if loadedLatency.Samples > 0 {
+		fmt.Printf("Latency (loaded): min %.2f ms  avg %.2f ms  max %.2f ms\n", loadedLatency.Min, loadedLatency.Avg, loadedLatency.Max)
+	}
+	if retransmits != nil {
+		fmt.Printf("Retransmits: %d\n", *retransmits)
+	}
+	return nil
+}
+
+// perfPing measures one round trip on the control connection
+func perfPing(conn *perfConn, seq int) (time.Duration, error) {
+	start := time.Now()
+	if err := conn.send(perfMessage{Type: "ping", Seq: seq}); err != nil {
+		return 0, err
+	}
+	msg, err := conn.receive()
+	if err != nil {
+		return 0, err
+	}
+	if msg.Type != "pong" || msg.Seq != seq {
+		return 0, fmt.Errorf("unexpected %s message", msg.Type)
+	}
+	return time.Since(start), nil
+}
+
+// perfSend writes to conn until deadline, then closes its sending side
+func perfSend(conn *perfConn, counter *atomic.Int64, deadline time.Time) {
+	buf := make([]byte, perfBufferSize)
+	rand.Read(buf)
+	conn.SetWriteDeadline(deadline)
+	for time.Now().Before(deadline) {
+		n, err := conn.Write(buf)
+		counter.Add(int64(n))
+		if err != nil {
+			break
+		}
+	}
+	if tcp, ok := conn.Conn.(*net.TCPConn); ok {
+		tcp.CloseWrite()
+	}
+}
+
+// perfReceive counts the bytes arriving on conn until the server closes it
+func perfReceive(conn *perfConn, counter *atomic.Int64, deadline time.Time) {
+	conn.SetReadDeadline(deadline)
+	io.Copy(io.Discard, perfCounter{conn.r, counter})
+}
+
+// perfCounter adds the bytes read from r to n as they arrive
+type perfCounter struct {
+	r io.Reader
+	n *atomic.Int64
+}
+
+func (c perfCounter) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n.Add(int64(n))
+	return n, err
+}
+
+func summarizePerfLatency(samples []time.Duration) perfLatency {
+	if len(samples) == 0 {
+		return perfLatency{}
+	}
+	sorted := append([]time.Duration{}, samples...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	var total time.Duration
+	for _, d := range sorted {
+		total += d
+	}
+	ms := func(d time.Duration) float64 { return float64(d) / float64(time.Millisecond) }
+	return perfLatency{
+		Samples: len(sorted),
+		Min:     ms(sorted[0]),
+		Avg:     ms(total / time.Duration(len(sorted))),
+		Max:     ms(sorted[len(sorted)-1]),
+	}
+}
+
+// perfRate formats a transfer as bits per second
+func perfRate(bytes int64, seconds float64) string {
+	if seconds <= 0 {
+		return "-"
+	}
+	bits := float64(bytes*8) / seconds
+	switch {
+	case bits >= 1e9:
+		return fmt.Sprintf("%.2f Gbps", bits/1e9)
+	case bits >= 1e6:
+		return fmt.Sprintf("%.1f Mbps", bits/1e6)
+	case bits >= 1e3:
+		return fmt.Sprintf("%.1f Kbps", bits/1e3)
+	}
+	return fmt.Sprintf("%.0f bps", bits)
+}
+
+// perfRetransmits sums the retransmitted segments of the connections, or
+// returns nil where the kernel does not report them
+func perfRetransmits(conns []*perfConn) *int64 {
+	var total int64
+	for _, c := range conns {
+		tcp, ok := c.Conn.(*net.TCPConn)
+		if !ok {
+			return nil
+		}
+		n, ok := tcpRetransmits(tcp)
+		if !ok {
+			return nil
+		}
+		total += n
+	}
+	return &total
+}
@@ -0,0 +1,26 @@
+//go:build linux
+
+package net
+
+import (
+	"net"
+
+	"golang.org/x/sys/unix"
+)
+
+// tcpRetransmits returns the segments the kernel retransmitted on conn
+func tcpRetransmits(conn *net.TCPConn) (int64, bool) {
+	raw, err := conn.SyscallConn()
+	if err != nil {
+		return 0, false
+	}
+	var info *unix.TCPInfo
+	var infoErr error
+	err = raw.Control(func(fd uintptr) {
+		info, infoErr = unix.GetsockoptTCPInfo(int(fd), unix.IPPROTO_TCP, unix.TCP_INFO)
+	})
+	if err != nil || infoErr != nil {
+		return 0, false
+	}
+	return int64(info.Total_retrans), true
+}
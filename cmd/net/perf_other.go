@@ -0,0 +1,10 @@
+//go:build !linux
+
+package net
+
+import "net"
+
+// tcpRetransmits is only implemented on Linux
+func tcpRetransmits(conn *net.TCPConn) (int64, bool) {
+	return 0, false
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+	"devkit/internal/netutil"
 	"devkit/internal/output"
 )
 
@@ -15,9 +16,13 @@ var pingCmd = &cobra.Command{
 	Short: "Ping a host with statistics",
 	Long: `Ping a host and display statistics.
 
+On dual-stack hosts -4 and -6 force IPv4 or IPv6; the address that answered
+and its family are reported either way.
+
 Examples:
   devkit net ping google.com
-  devkit net ping 8.8.8.8 --count 10`,
+  devkit net ping 8.8.8.8 --count 10
+  devkit net ping google.com -6`,
 	Args: cobra.ExactArgs(1),
 	RunE: runPing,
 }
@@ -26,26 +31,34 @@ func init() {
 	netCmd.AddCommand(pingCmd)
 
 	pingCmd.Flags().IntP("count", "c", 4, "Number of ping packets")
-	pingCmd.Flags().IntP("timeout", "t", 3, "Timeout in seconds")
+	netutil.AddTimeoutFlag(pingCmd, "t", 3*time.Second)
+	netutil.AddFamilyFlags(pingCmd)
 	pingCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
 }
 
 func runPing(cmd *cobra.Command, args []string) error {
 	host := args[0]
 	count, _ := cmd.Flags().GetInt("count")
-	timeout, _ := cmd.Flags().GetInt("timeout")
+	opts, err := netutil.FromCommand(cmd, 3*time.Second)
+	if err != nil {
+		return err
+	}
 	outputFormat, _ := cmd.Flags().GetString("output")
 	format := output.OutputFormat(outputFormat)
 
 	var times []time.Duration
 	var successCount int
+	var remote net.Addr
 
 	for i := 0; i < count; i++ {
 		start := time.Now()
-		conn, err := net.DialTimeout("tcp", fmt.Sprintf("%s:80", host), time.Duration(timeout)*time.Second)
+		conn, err := opts.Dialer().DialContext(cmd.Context(), opts.Network("tcp"), net.JoinHostPort(host, "80"))
 		duration := time.Since(start)
 
 		if err == nil {
+			if remote == nil {
+				remote = conn.RemoteAddr()
+			}
 			conn.Close()
 			times = append(times, duration)
 			successCount++
@@ -80,12 +93,14 @@ func runPing(cmd *cobra.Command, args []string) error {
 		"max":         max.String(),
 		"avg":         avg.String(),
 		"times":       times,
+		"address":     remote.(*net.TCPAddr).IP.String(),
+		"family":      netutil.Family(remote),
 	}
 
 	if format == output.FormatJSON {
 		output.PrintSuccess(format, result)
 	} else {
-		fmt.Printf("Ping statistics for %s:\n", host)
+		fmt.Printf("Ping statistics for %s (%s, %s):\n", host, result["address"], result["family"])
 		fmt.Printf("  Packets: Sent = %d, Received = %d, Lost = %d (%.1f%% loss)\n",
 			count, successCount, count-successCount, loss)
 		fmt.Printf("  Times: Min = %s, Max = %s, Avg = %s\n", min, max, avg)
@@ -3,11 +3,16 @@ package net
 import (
 	"fmt"
 	"net"
+	"sort"
 	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
+	psnet "github.com/shirou/gopsutil/v3/net"
+	"github.com/shirou/gopsutil/v3/process"
 	"github.com/spf13/cobra"
+	"devkit/internal/netutil"
 	"devkit/internal/output"
 )
 
@@ -29,10 +34,21 @@ var portCheckCmd = &cobra.Command{
 	Short: "Check if a port is open",
 	Long: `Check if a specific port is open on a host.
 
+With --targets, every endpoint listed in the file (one per line, - for
+stdin) is checked concurrently and one table or JSON report is printed.
+Lines are "host:port", or just a host, which is checked on the port given
+as argument.
+
+-4 and -6 force IPv4 or IPv6; the address that accepted the connection and
+its family are reported.
+
 Examples:
   devkit net port check 8080
-  devkit net port check 8080 --host localhost`,
-	Args: cobra.ExactArgs(1),
+  devkit net port check 8080 --host localhost
+  devkit net port check 443 --host example.com -6
+  devkit net port check 443 --targets hosts.txt
+  printf "db:5432\ncache:6379\n" | devkit net port check --targets -`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: runPortCheck,
 }
 
@@ -40,11 +56,12 @@ Examples:
 var portScanCmd = &cobra.Command{
 	Use:   "scan [host]",
 	Short: "Scan a range of ports",
-	Long: `Scan a range of ports on a host.
+	Long: `Scan a range of ports on a host. -4 and -6 force IPv4 or IPv6.
 
 Examples:
   devkit net port scan localhost --range 1-1000
-  devkit net port scan 192.168.1.1 --range 80-443`,
+  devkit net port scan 192.168.1.1 --range 80-443
+  devkit net port scan localhost --range 1-1000 -6`,
 	Args: cobra.ExactArgs(1),
 	RunE: runPortScan,
 }
@@ -53,10 +70,13 @@ Examples:
 var portListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List listening ports",
-	Long: `List all listening ports on the local system.
+	Long: `List all listening ports on the local system with the process that owns
+them. Works on Linux, macOS and Windows; processes owned by other users may
+only be shown when running as root or Administrator.
 
 Examples:
   devkit net port list
+  devkit net port list --udp
   devkit net port list --output json`,
 	RunE: runPortList,
 }
@@ -69,48 +89,71 @@ func init() {
 
 	portCheckCmd.Flags().String("host", "localhost", "Host to check")
 	portCheckCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
+	netutil.AddFamilyFlags(portCheckCmd)
+	addBatchFlags(portCheckCmd)
 
 	portScanCmd.Flags().StringP("range", "r", "1-1000", "Port range to scan (e.g., 1-1000)")
-	portScanCmd.Flags().IntP("timeout", "t", 1, "Timeout in seconds")
+	netutil.AddTimeoutFlag(portScanCmd, "t", time.Second)
+	netutil.AddFamilyFlags(portScanCmd)
 	portScanCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
 
-	portListCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
+	portListCmd.Flags().Bool("udp", false, "Include bound UDP sockets")
+	portListCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json, table")
 }
 
 func runPortCheck(cmd *cobra.Command, args []string) error {
-	portStr := args[0]
 	host, _ := cmd.Flags().GetString("host")
 	outputFormat, _ := cmd.Flags().GetString("output")
 	format := output.OutputFormat(outputFormat)
-
-	port, err := strconv.Atoi(portStr)
+	opts, err := netutil.FromCommand(cmd, 2*time.Second)
 	if err != nil {
-		return fmt.Errorf("invalid port: %s", portStr)
+		return err
 	}
 
-	address := fmt.Sprintf("%s:%d", host, port)
-	conn, err := net.DialTimeout("tcp", address, 2*time.Second)
-	
-	isOpen := err == nil
-	if conn != nil {
-		conn.Close()
+	port := 0
+	if len(args) > 0 {
+		var err error
+		port, err = strconv.Atoi(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid port: %s", args[0])
+		}
 	}
 
-	result := map[string]interface{}{
-		"host":   host,
-		"port":   port,
-		"status": "open",
+	// The argument is the port, so only the --targets list holds targets
+	targets, batch, err := batchTargets(cmd, nil)
+	if err != nil {
+		return err
 	}
-
-	if !isOpen {
-		result["status"] = "closed"
+	if batch {
+		results := runBatch(cmd, targets, func(target string) (map[string]interface{}, error) {
+			targetHost, targetPort := target, port
+			if h, p, err := net.SplitHostPort(target); err == nil {
+				targetHost = h
+				if targetPort, err = strconv.Atoi(p); err != nil {
+					return nil, fmt.Errorf("invalid port: %s", p)
+				}
+			} else if port == 0 {
+				return nil, fmt.Errorf("no port given (use host:port or pass the port as argument)")
+			}
+			return portCheck(cmd, opts, targetHost, targetPort), nil
+		})
+		return printBatch(cmd, format, results, []batchColumn{
+			{Header: "PORT", Key: "port"},
+			{Header: "STATUS", Key: "status"},
+			{Header: "ADDRESS", Key: "address"},
+		})
+	}
+	if len(args) == 0 {
+		return fmt.Errorf("port not specified")
 	}
 
+	result := portCheck(cmd, opts, host, port)
+
 	if format == output.FormatJSON {
 		output.PrintSuccess(format, result)
 	} else {
-		if isOpen {
-			fmt.Printf("Port %d on %s is OPEN\n", port, host)
+		if result["status"] == "open" {
+			fmt.Printf("Port %d on %s is OPEN (%s, %s)\n", port, host, result["address"], result["family"])
 		} else {
 			fmt.Printf("Port %d on %s is CLOSED\n", port, host)
 		}
@@ -119,10 +162,35 @@ func runPortCheck(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// portCheck reports whether a TCP connection to host:port succeeds
+func portCheck(cmd *cobra.Command, opts netutil.Options, host string, port int) map[string]interface{} {
+	address := net.JoinHostPort(host, strconv.Itoa(port))
+	conn, err := opts.DialContext(cmd.Context(), "tcp", address)
+
+	result := map[string]interface{}{
+		"host":   host,
+		"port":   port,
+		"status": "open",
+	}
+
+	if err != nil {
+		result["status"] = "closed"
+		result["error"] = err.Error()
+		return result
+	}
+	defer conn.Close()
+	result["address"] = conn.RemoteAddr().(*net.TCPAddr).IP.String()
+	result["family"] = netutil.Family(conn.RemoteAddr())
+	return result
+}
+
 func runPortScan(cmd *cobra.Command, args []string) error {
 	host := args[0]
 	rangeStr, _ := cmd.Flags().GetString("range")
-	timeout, _ := cmd.Flags().GetInt("timeout")
+	opts, err := netutil.FromCommand(cmd, time.Second)
+	if err != nil {
+		return err
+	}
 	outputFormat, _ := cmd.Flags().GetString("output")
 	format := output.OutputFormat(outputFormat)
 
@@ -142,14 +210,23 @@ func runPortScan(cmd *cobra.Command, args []string) error {
 	}
 
 	var openPorts []int
+	families := make(map[string]bool)
 	for port := start; port <= end; port++ {
-		address := fmt.Sprintf("%s:%d", host, port)
-		conn, err := net.DialTimeout("tcp", address, time.Duration(timeout)*time.Second)
+		address := net.JoinHostPort(host, strconv.Itoa(port))
+		conn, err := opts.Dialer().DialContext(cmd.Context(), opts.Network("tcp"), address)
 		if err == nil {
 			openPorts = append(openPorts, port)
+			families[netutil.Family(conn.RemoteAddr())] = true
 			conn.Close()
 		}
 	}
+	// A dual-stack host may answer some ports over IPv4 and others over IPv6
+	var familyNames []string
+	for _, family := range []string{"IPv4", "IPv6"} {
+		if families[family] {
+			familyNames = append(familyNames, family)
+		}
+	}
 
 	if format == output.FormatJSON {
 		output.PrintSuccess(format, map[string]interface{}{
@@ -157,12 +234,13 @@ func runPortScan(cmd *cobra.Command, args []string) error {
 			"range":     rangeStr,
 			"open_ports": openPorts,
 			"count":     len(openPorts),
+			"families":  familyNames,
 		})
 	} else {
 		if len(openPorts) == 0 {
 			fmt.Printf("No open ports found in range %s on %s\n", rangeStr, host)
 		} else {
-			fmt.Printf("Open ports on %s:\n", host)
+			fmt.Printf("Open ports on %s (%s):\n", host, strings.Join(familyNames, ", "))
 			for _, port := range openPorts {
 				fmt.Printf("  %d\n", port)
 			}
@@ -173,22 +251,90 @@ func runPortScan(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// listeningPort is one socket accepting connections
+type listeningPort struct {
+	Protocol string `json:"protocol"`
+	Address  string `json:"address"`
+	Port     uint32 `json:"port"`
+	PID      int32  `json:"pid,omitempty"`
+	Process  string `json:"process,omitempty"`
+}
+
 func runPortList(cmd *cobra.Command, args []string) error {
+	includeUDP, _ := cmd.Flags().GetBool("udp")
 	outputFormat, _ := cmd.Flags().GetString("output")
 	format := output.OutputFormat(outputFormat)
 
-	// List listening ports (simplified - would need platform-specific code for full implementation)
-	result := map[string]interface{}{
-		"message": "Port listing requires platform-specific implementation",
-		"note":    "Use 'netstat' or 'lsof' for detailed port information",
+	conns, err := psnet.Connections("inet")
+	if err != nil {
+		return fmt.Errorf("failed to list sockets: %w", err)
 	}
 
+	names := make(map[int32]string)
+	seen := make(map[listeningPort]bool)
+	var ports []listeningPort
+	for _, c := range conns {
+		var protocol string
+		switch {
+		case c.Type == syscall.SOCK_STREAM && c.Status == "LISTEN":
+			protocol = "tcp"
+		case c.Type == syscall.SOCK_DGRAM && includeUDP && c.Raddr.IP == "":
+			protocol = "udp"
+		default:
+			continue
+		}
+		if c.Family == syscall.AF_INET6 {
+			protocol += "6"
+		}
+
+		p := listeningPort{Protocol: protocol, Address: c.Laddr.IP, Port: c.Laddr.Port, PID: c.Pid}
+		if seen[p] {
+			continue
+		}
+		seen[p] = true
+
+		if c.Pid > 0 {
+			name, ok := names[c.Pid]
+			if !ok {
+				if proc, err := process.NewProcess(c.Pid); err == nil {
+					name, _ = proc.Name()
+				}
+				names[c.Pid] = name
+			}
+			p.Process = name
+		}
+		ports = append(ports, p)
+	}
+
+	sort.Slice(ports, func(i, j int) bool {
+		if ports[i].Port != ports[j].Port {
+			return ports[i].Port < ports[j].Port
+		}
+		return ports[i].Protocol < ports[j].Protocol
+	})
+
 	if format == output.FormatJSON {
-		output.PrintSuccess(format, result)
-	} else {
-		fmt.Println("Note: Full port listing requires platform-specific implementation.")
-		fmt.Println("Use system commands like 'netstat -an' or 'lsof -i' for detailed information.")
+		output.PrintSuccess(format, map[string]interface{}{
+			"ports": ports,
+			"count": len(ports),
+		})
+		return nil
+	}
+
+	if len(ports) == 0 {
+		fmt.Println("No listening ports found")
+		return nil
+	}
+	fmt.Println(output.Accent(fmt.Sprintf("%-8s %-30s %-8s %s", "PROTO", "ADDRESS", "PID", "PROCESS")))
+	fmt.Println(output.Rule(65))
+	for _, p := range ports {
+		pid := "-"
+		if p.PID > 0 {
+			pid = strconv.Itoa(int(p.PID))
+		}
+		fmt.Printf("%-8s %-30s %-8s %s\n", p.Protocol, net.JoinHostPort(p.Address, strconv.Itoa(int(p.Port))), pid, p.Process)
 	}
+	fmt.Printf("\nTotal: %d listening ports\n", len(ports))
 
 	return nil
 }
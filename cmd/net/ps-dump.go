@@ -0,0 +1,326 @@
+package net
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	psnet "github.com/shirou/gopsutil/v3/net"
+	"github.com/shirou/gopsutil/v3/process"
+	"github.com/spf13/cobra"
+	"devkit/internal/netutil"
+	"devkit/internal/output"
+	"devkit/internal/safety"
+	"devkit/internal/stacktrace"
+)
+
+// psDumpCmd represents the ps dump subcommand
+var psDumpCmd = &cobra.Command{
+	Use:   "dump <pid>",
+	Short: "Capture a goroutine or heap dump of a Go process",
+	Long: `Capture a goroutine dump (and with --heap a heap profile) of a running Go
+process and save it with a timestamp, e.g. api-4242-goroutines-20240301-150405.txt.
+
+The dump comes from the net/http/pprof endpoint of the process: --pprof
+gives its address, otherwise the ports the process listens on are probed
+for /debug/pprof/. Processes without pprof can be dumped with --signal,
+which sends SIGQUIT and captures what the Go runtime prints to standard
+error; this works on Linux when standard error is redirected to a file,
+and stops the process unless it handles SIGQUIT, so it asks first.
+
+--summary prints the dump condensed by "dev stacktrace parse", with the
+frames of the module of the process's working directory highlighted.
+
+Examples:
+  devkit net ps dump 4242 --goroutines --summary
+  devkit net ps dump 4242 --pprof localhost:6060 --heap --dir ./dumps
+  devkit net ps dump 4242 --signal --yes
+  devkit net ps dump 4242 --output json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPSDump,
+}
+
+func init() {
+	psCmd.AddCommand(psDumpCmd)
+
+	psDumpCmd.Flags().Bool("goroutines", false, "Capture a goroutine dump (default unless only --heap is given)")
+	psDumpCmd.Flags().Bool("heap", false, "Capture a heap profile (needs a pprof endpoint)")
+	psDumpCmd.Flags().String("pprof", "", "Address or URL of the pprof endpoint, e.g. localhost:6060 (default: probe the listening ports)")
+	psDumpCmd.Flags().Bool("signal", false, "Send SIGQUIT and capture the dump from the process's standard error file")
+	psDumpCmd.Flags().String("dir", ".", "Directory to save the dumps in")
+	psDumpCmd.Flags().Bool("summary", false, "Print a summary of the goroutine dump")
+	psDumpCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
+}
+
+// psDumpFile is a saved dump
+type psDumpFile struct {
+	Kind   string `json:"kind"`
+	Path   string `json:"path"`
+	Size   int    `json:"size"`
+	Source string `json:"source"`
+}
+
+func runPSDump(cmd *cobra.Command, args []string) error {
+	goroutines, _ := cmd.Flags().GetBool("goroutines")
+	heap, _ := cmd.Flags().GetBool("heap")
+	endpoint, _ := cmd.Flags().GetString("pprof")
+	signal, _ := cmd.Flags().GetBool("signal")
+	dir, _ := cmd.Flags().GetString("dir")
+	summary, _ := cmd.Flags().GetBool("summary")
+	outputFormat, _ := cmd.Flags().GetString("output")
+	format := output.OutputFormat(outputFormat)
+
+	pid, err := strconv.ParseInt(args[0], 10, 32)
+	if err != nil || pid <= 0 {
+		return fmt.Errorf("invalid pid: %s", args[0])
+	}
+	if !goroutines && !heap {
+		goroutines = true
+	}
+	if signal && endpoint != "" {
+		return fmt.Errorf("--signal and --pprof cannot be used together")
+	}
+	if signal && heap {
+		return fmt.Errorf("--heap needs a pprof endpoint and cannot be used with --signal")
+	}
+	opts, err := netutil.FromCommand(cmd, 10*time.Second)
+	if err != nil {
+		return err
+	}
+
+	proc, err := process.NewProcess(int32(pid))
+	if err != nil {
+		return fmt.Errorf("no process with pid %d", pid)
+	}
+	name, err := proc.Name()
+	if err != nil || name == "" {
+		name = "process"
+	}
+	cmd.SilenceUsage = true
+
+	client := opts.HTTPClient()
+	source := "SIGQUIT"
+	if !signal {
+		if endpoint != "" {
+			endpoint = pprofBaseURL(endpoint)
+		} else if endpoint = findPprofEndpoint(client, proc); endpoint == "" {
+			return fmt.Errorf("no pprof endpoint found on the ports of %s (%d): give --pprof, or --signal to dump through SIGQUIT", name, pid)
+		}
+		source = endpoint
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+	stamp := time.Now().Format("20060102-150405")
+	prefix := filepath.Join(dir, fmt.Sprintf("%s-%d", safeDumpName(name), pid))
+
+	var files []psDumpFile
+	var dump []byte
+	if goroutines {
+		if signal {
+			if err := safety.Confirm(fmt.Sprintf("Send SIGQUIT to %s (%d)? Go programs exit on SIGQUIT unless they handle it.", name, pid)); err != nil {
+				return err
+			}
+			dump, err = captureSigquitDump(proc, opts.Timeout)
+		} else {
+			dump, err = fetchPprof(client, endpoint+"/goroutine?debug=2")
+		}
+		if err != nil {
+			return err
+		}
+		path := prefix + "-goroutines-" + stamp + ".txt"
+		if err := os.WriteFile(path, dump, 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		files = append(files, psDumpFile{Kind: "goroutines", Path: path, Size: len(dump), Source: source})
+	}
+	if heap {
+		profile, err := fetchPprof(client, endpoint+"/heap?gc=1")
+		if err != nil {
+			return err
+		}
+		path := prefix + "-heap-" + stamp + ".pb.gz"
+		if err := os.WriteFile(path, profile, 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		files = append(files, psDumpFile{Kind: "heap", Path: path, Size: len(profile), Source: source})
+	}
+
+	var traces []*stacktrace.Trace
+	var groups []stacktrace.Group
+	if summary && dump != nil {
+		if traces, err = stacktrace.Parse(bytes.NewReader(dump)); err != nil {
+			return err
+		}
+		var modulePaths []string
+		if cwd, err := proc.Cwd(); err == nil {
+			if path := stacktrace.ModulePath(cwd); path != "" {
+				modulePaths = []string{path}
+			}
+		}
+		stacktrace.MarkOwn(traces, modulePaths)
+		groups = stacktrace.GroupTraces(traces)
+	}
+
+	if format == output.FormatJSON {
+		data := map[string]interface{}{
+			"pid":   pid,
+			"name":  name,
+			"files": files,
+		}
+		if summary && dump != nil {
+			if groups == nil {
+				groups = []stacktrace.Group{}
+			}
+			data["goroutines"] = len(traces)
+			data["groups"] = groups
+		}
+		output.PrintSuccess(format, data)
+		return nil
+	}
+
+	for _, f := range files {
+		fmt.Println(output.OK(fmt.Sprintf("Saved %s dump of %s (%d): %s (%s)", f.Kind, name, pid, f.Path, formatBytesPS(uint64(f.Size)))))
+	}
+	fmt.Println(output.Muted("Source: " + source))
+	if summary && dump != nil {
+		fmt.Printf("\n%d goroutines in %d groups\n", len(traces), len(groups))
+		for _, g := range groups {
+			fmt.Println()
+			stacktrace.PrintGroup(g)
+		}
+	}
+	return nil
+}
+
+// pprofBaseURL turns an address such as localhost:6060 or a URL into the
+// base URL of the pprof handlers, without a trailing slash
+func pprofBaseURL(endpoint string) string {
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "http://" + endpoint
+	}
+	endpoint = strings.TrimRight(endpoint, "/")
+	if !strings.HasSuffix(endpoint, "/debug/pprof") {
+		endpoint += "/debug/pprof"
+	}
+	return endpoint
+}
+
+// findPprofEndpoint probes the TCP ports the process listens on for the
+// net/http/pprof handlers and returns the first base URL that answers
+func findPprofEndpoint(client *http.Client, proc *process.Process) string {
+	conns, err := psnet.ConnectionsPid("tcp", proc.Pid)
+	if err != nil {
+		return ""
+	}
+	seen := make(map[uint32]bool)
+	for _, c := range conns {
+		if c.Status != "LISTEN" || seen[c.Laddr.Port] {
+			continue
+		}
+		seen[c.Laddr.Port] = true
+		host := c.Laddr.IP
+		if ip := net.ParseIP(host); ip == nil || ip.IsUnspecified() {
+			host = "127.0.0.1"
+			if ip != nil && ip.To4() == nil {
+				host = "::1"
+			}
+		}
+		base := pprofBaseURL(net.JoinHostPort(host, strconv.Itoa(int(c.Laddr.Port))))
+		resp, err := client.Get(base + "/")
+		if err != nil {
+			continue
+		}
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+		resp.Body.Close()
+		if resp.StatusCode == http.StatusOK && bytes.Contains(body, []byte("goroutine")) {
+			return base
+		}
+	}
+	return ""
+}
+
+// fetchPprof downloads one pprof handler
+func fetchPprof(client *http.Client, url string) ([]byte, error) {
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", url, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch %s: %s", url, resp.Status)
+	}
+	return body, nil
+}
+
+// captureSigquitDump sends SIGQUIT to the process and returns what it
+// appends to its standard error, which must be a regular file; it waits
+// until the file stops growing or timeout passes
+func captureSigquitDump(proc *process.Process, timeout time.Duration) ([]byte, error) {
+	stderr, err := os.Readlink(fmt.Sprintf("/proc/%d/fd/2", proc.Pid))
+	if err != nil {
+		return nil, fmt.Errorf("cannot find the standard error of process %d (SIGQUIT capture needs Linux): use --pprof", proc.Pid)
+	}
+	info, err := os.Stat(stderr)
+	if err != nil || !info.Mode().IsRegular() {
+		return nil, fmt.Errorf("standard error of process %d is %s, not a file: redirect it to a file (2>app.log) or use --pprof", proc.Pid, stderr)
+	}
+	start := info.Size()
+
+	if err := proc.SendSignal(syscall.SIGQUIT); err != nil {
+		return nil, fmt.Errorf("failed to send SIGQUIT to %d: %w", proc.Pid, err)
+	}
+
+	deadline := time.Now().Add(timeout)
+	size, stable := start, 0
+	for time.Now().Before(deadline) && stable < 5 {
+		time.Sleep(100 * time.Millisecond)
+		info, err := os.Stat(stderr)
+		if err != nil {
+			break
+		}
+		if info.Size() != size {
+			size, stable = info.Size(), 0
+		} else if size > start {
+			stable++
+		}
+	}
+	if size <= start {
+		return nil, fmt.Errorf("process %d wrote nothing to %s after SIGQUIT", proc.Pid, stderr)
+	}
+
+	f, err := os.Open(stderr)
+	if err != nil {
+		return nil, fmt.Errorf("read file error: %w", err)
+	}
+	defer f.Close()
+	dump := make([]byte, size-start)
+	if _, err := f.ReadAt(dump, start); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("read file error: %w", err)
+	}
+	return dump, nil
+}
+
+// safeDumpName replaces the characters of a process name that do not
+// belong in a file name
+func safeDumpName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r == ':' || r == ' ' || r < 32 {
+			return '_'
+		}
+		return r
+	}, name)
+}
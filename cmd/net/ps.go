@@ -97,8 +97,8 @@ func runPS(cmd *cobra.Command, args []string) error {
 			"count":    len(procList),
 		})
 	} else if format == output.FormatTable {
-		fmt.Printf("%-8s %-30s %10s %12s\n", "PID", "NAME", "CPU", "MEMORY")
-		fmt.Println(strings.Repeat("-", 65))
+		fmt.Println(output.Accent(fmt.Sprintf("%-8s %-30s %10s %12s", "PID", "NAME", "CPU", "MEMORY")))
+		fmt.Println(output.Rule(65))
 		for _, proc := range procList {
 			fmt.Printf("%-8d %-30s %10s %12s\n",
 				proc["pid"], truncate(proc["name"].(string), 30), proc["cpu"], proc["memory"])
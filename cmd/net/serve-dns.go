@@ -0,0 +1,500 @@
+package net
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/net/dns/dnsmessage"
+	"gopkg.in/yaml.v3"
+	"devkit/internal/output"
+)
+
+// serveDNSCmd represents the serve dns subcommand
+var serveDNSCmd = &cobra.Command{
+	Use:   "dns",
+	Short: "DNS server answering local development names",
+	Long: `Answer A, AAAA and CNAME queries for development names over UDP and TCP, so
+names like app.test resolve to a local address without editing /etc/hosts
+for every subdomain.
+
+Records are NAME=VALUE pairs: an IPv4 value gives an A record, an IPv6
+value an AAAA record and a host name a CNAME, which is followed when its
+target is also local. Repeat a name to give it several addresses.
+--wildcard '*.test=127.0.0.1' answers for every name below test; exact
+names and more specific wildcards take precedence.
+
+Records can also come from --config, a YAML file:
+
+  ttl: 60
+  records:
+    dev.local: 127.0.0.1
+    api.dev.local: [127.0.0.1, "::1"]
+    www.dev.local: dev.local
+    "*.test": 127.0.0.1
+
+Other names get NXDOMAIN, or are passed to --upstream when given. To send
+only *.test to this server, use /etc/resolver/test on macOS
+("nameserver 127.0.0.1" and "port 5353") or a dnsmasq/systemd-resolved
+forwarding rule on Linux.
+
+Examples:
+  devkit net serve dns --zone dev.local=127.0.0.1 --wildcard '*.test=127.0.0.1'
+  devkit net serve dns --config dns.yaml --upstream 1.1.1.1
+  dig @127.0.0.1 -p 5353 app.test`,
+	Args: cobra.NoArgs,
+	RunE: runServeDNS,
+}
+
+func init() {
+	serveCmd.AddCommand(serveDNSCmd)
+
+	serveDNSCmd.Flags().Int("port", 5353, "Port to listen on (UDP and TCP)")
+	serveDNSCmd.Flags().String("bind", "127.0.0.1", "Address to bind (empty for all interfaces)")
+	serveDNSCmd.Flags().StringArray("zone", nil, "Record as NAME=VALUE, e.g. dev.local=127.0.0.1")
+	serveDNSCmd.Flags().StringArray("wildcard", nil, "Wildcard record as *.DOMAIN=VALUE, e.g. '*.test=127.0.0.1'")
+	serveDNSCmd.Flags().String("config", "", "YAML file with ttl and records")
+	serveDNSCmd.Flags().String("upstream", "", "Resolver for other names, e.g. 1.1.1.1 (default: answer NXDOMAIN)")
+	serveDNSCmd.Flags().Int("ttl", 60, "TTL of answers in seconds")
+}
+
+// dnsServeRecord is one answer for a name: an address or a CNAME target
+type dnsServeRecord struct {
+	typ    dnsmessage.Type
+	addr   net.IP
+	target string
+}
+
+func (r dnsServeRecord) String() string {
+	if r.typ == dnsmessage.TypeCNAME {
+		return "CNAME " + strings.TrimSuffix(r.target, ".")
+	}
+	return dnsTypeName(r.typ) + " " + r.addr.String()
+}
+
+// dnsServeName matches the host names records can be given for and point to
+var dnsServeName = regexp.MustCompile(`^(?i)([a-z0-9_]([a-z0-9_-]{0,61}[a-z0-9])?\.)*[a-z0-9_]([a-z0-9_-]{0,61}[a-z0-9])?\.?$`)
+
+// dnsZone holds the records served, keyed by lowercase FQDN; wildcards are
+// keyed by the domain below which they apply
+type dnsZone struct {
+	names     map[string][]dnsServeRecord
+	wildcards map[string][]dnsServeRecord
+	ttl       uint32
+}
+
+// dnsServeConfig is the structure of the --config file
+type dnsServeConfig struct {
+	TTL     int                  `yaml:"ttl"`
+	Records map[string]yaml.Node `yaml:"records"`
+}
+
+func runServeDNS(cmd *cobra.Command, args []string) error {
+	port, _ := cmd.Flags().GetInt("port")
+	bind, _ := cmd.Flags().GetString("bind")
+	zones, _ := cmd.Flags().GetStringArray("zone")
+	wildcards, _ := cmd.Flags().GetStringArray("wildcard")
+	configFile, _ := cmd.Flags().GetString("config")
+	upstream, _ := cmd.Flags().GetString("upstream")
+	ttl, _ := cmd.Flags().GetInt("ttl")
+
+	if port < 1 || port > 65535 {
+		return fmt.Errorf("invalid port: %d", port)
+	}
+	zone := &dnsZone{names: map[string][]dnsServeRecord{}, wildcards: map[string][]dnsServeRecord{}}
+
+	if configFile != "" {
+		data, err := os.ReadFile(configFile)
+		if err != nil {
+			return fmt.Errorf("failed to read config: %w", err)
+		}
+		var config dnsServeConfig
+		if err := yaml.Unmarshal(data, &config); err != nil {
+			return fmt.Errorf("failed to parse config: %w", err)
+		}
+		if config.TTL > 0 && !cmd.Flags().Changed("ttl") {
+			ttl = config.TTL
+		}
+		for name, node := range config.Records {
+			var values []string
+			if node.Kind == yaml.SequenceNode {
+				err = node.Decode(&values)
+			} else {
+				var value string
+				err = node.Decode(&value)
+				values = []string{value}
+			}
+			if err != nil {
+				return fmt.Errorf("invalid record %s in %s: %w", name, configFile, err)
+			}
+			for _, value := range values {
+				if err := zone.add(name, value); err != nil {
+					return fmt.Errorf("invalid record %s in %s: %w", name, configFile, err)
+				}
+			}
+		}
+	}
+	for _, flag := range [][]string{zones, wildcards} {
+		for _, spec := range flag {
+			name, value, ok := strings.Cut(spec, "=")
+			if !ok {
+				return fmt.Errorf("invalid record %q (expected NAME=VALUE)", spec)
+			}
+			if err := zone.add(name, value); err != nil {
+				return fmt.Errorf("invalid record %q: %w", spec, err)
+			}
+		}
+	}
+	if len(zone.names) == 0 && len(zone.wildcards) == 0 && upstream == "" {
+		return fmt.Errorf("no records (use --zone, --wildcard or --config)")
+	}
+	if ttl < 0 {
+		return fmt.Errorf("invalid ttl: %d", ttl)
+	}
+	zone.ttl = uint32(ttl)
+	if upstream != "" {
+		if _, _, err := net.SplitHostPort(upstream); err != nil {
+			upstream = net.JoinHostPort(strings.Trim(upstream, "[]"), "53")
+		}
+	}
+	cmd.SilenceUsage = true
+
+	addr := net.JoinHostPort(bind, strconv.Itoa(port))
+	udp, err := net.ListenPacket("udp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on UDP: %w", err)
+	}
+	defer udp.Close()
+	tcp, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on TCP: %w", err)
+	}
+	defer tcp.Close()
+
+	go serveDNSUDP(udp, zone, upstream)
+	go serveDNSTCP(tcp, zone, upstream)
+
+	host, _, _ := net.SplitHostPort(udp.LocalAddr().String())
+	fmt.Printf("%-5s %s  %s\n", "DNS", output.Accent(udp.LocalAddr().String()), output.Muted("(UDP and TCP)"))
+	for _, line := range zone.describe() {
+		fmt.Printf("      %s\n", line)
+	}
+	if upstream != "" {
+		fmt.Printf("      Other names forwarded to %s\n", upstream)
+	}
+	if ip := net.ParseIP(host); ip == nil || ip.IsUnspecified() {
+		host = "127.0.0.1"
+	}
+	fmt.Println(output.Muted(fmt.Sprintf("Try: dig @%s -p %d %s", host, port, zone.example())))
+	fmt.Println(output.Muted("Press Ctrl+C to stop"))
+	waitForInterrupt()
+	return nil
+}
+
+// add parses a NAME=VALUE record; names starting with *. are wildcards
+func (z *dnsZone) add(name, value string) error {
+	name = strings.ToLower(strings.TrimSpace(name))
+	value = strings.TrimSpace(value)
+	records := z.names
+	if rest, ok := strings.CutPrefix(name, "*."); ok {
+		name, records = rest, z.wildcards
+	}
+	if !dnsServeName.MatchString(name) {
+		return fmt.Errorf("invalid name: %s", name)
+	}
+	name = dnsFQDN(name)
+
+	var record dnsServeRecord
+	if ip := net.ParseIP(value); ip != nil {
+		record = dnsServeRecord{typ: dnsmessage.TypeAAAA, addr: ip}
+		if ip4 := ip.To4(); ip4 != nil {
+			record = dnsServeRecord{typ: dnsmessage.TypeA, addr: ip4}
+		}
+	} else {
+		if value == "" {
+			return fmt.Errorf("value required (an IP address or a host name)")
+		}
+		if !dnsServeName.MatchString(value) {
+			return fmt.Errorf("invalid value: %s (expected an IP address or a host name)", value)
+		}
+		record = dnsServeRecord{typ: dnsmessage.TypeCNAME, target: strings.ToLower(dnsFQDN(value))}
+	}
+
+	for _, existing := range records[name] {
+		if (existing.typ == dnsmessage.TypeCNAME) != (record.typ == dnsmessage.TypeCNAME) {
+			return fmt.Errorf("a CNAME cannot be combined with other records for the same name")
+		}
+		if record.typ == dnsmessage.TypeCNAME {
+			return fmt.Errorf("a name can only have one CNAME")
+		}
+	}
+	records[name] = append(records[name], record)
+	return nil
+}
+
+// lookup returns the records for fqdn: the exact name first, then the most
+// specific wildcard
+func (z *dnsZone) lookup(fqdn string) ([]dnsServeRecord, bool) {
+	if records, ok := z.names[fqdn]; ok {
+		return records, true
+	}
+	for i := strings.Index(fqdn, "."); i >= 0 && i < len(fqdn)-1; i = strings.Index(fqdn, ".") {
+		fqdn = fqdn[i+1:]
+		if records, ok := z.wildcards[fqdn]; ok {
+			return records, true
+		}
+	}
+	return nil, false
+}
+
+// describe lists the records for the startup banner
+func (z *dnsZone) describe() []string {
+	type entry struct{ name, records string }
+	var entries []entry
+	width := 0
+	for prefix, set := range map[string]map[string][]dnsServeRecord{"": z.names, "*.": z.wildcards} {
+		for name, records := range set {
+			values := make([]string, len(records))
+			for i, r := range records {
+				values[i] = r.String()
+			}
+			e := entry{prefix + strings.TrimSuffix(name, "."), strings.Join(values, ", ")}
+			width = max(width, len(e.name))
+			entries = append(entries, e)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].name < entries[j].name })
+
+	lines := make([]string, len(entries))
+	for i, e := range entries {
+		lines[i] = fmt.Sprintf("%-*s  %s", width, e.name, e.records)
+	}
+	return lines
+}
+
+// example returns a name to suggest in the startup banner
+func (z *dnsZone) example() string {
+	var names []string
+	for name := range z.wildcards {
+		names = append(names, "app."+name)
+	}
+	if len(names) == 0 {
+		for name := range z.names {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return "example.com"
+	}
+	sort.Strings(names)
+	return strings.TrimSuffix(names[0], ".")
+}
+
+// answer builds the response to a query, or returns nil when it should be
+// forwarded upstream
+func (z *dnsZone) answer(query []byte, upstream string, maxSize int) []byte {
+	var p dnsmessage.Parser
+	header, err := p.Start(query)
+	if err != nil {
+		return nil
+	}
+	question, err := p.Question()
+	if err != nil {
+		return dnsReply(header, nil, nil, dnsmessage.RCodeFormatError, maxSize)
+	}
+	if question.Class != dnsmessage.ClassINET {
+		return dnsReply(header, &question, nil, dnsmessage.RCodeNotImplemented, maxSize)
+	}
+
+	name := strings.ToLower(question.Name.String())
+	records, ok := z.lookup(name)
+	if !ok {
+		if upstream != "" {
+			return nil
+		}
+		serveLog("%-5s %s %s", dnsTypeName(question.Type), strings.TrimSuffix(name, "."), output.Failure("NXDOMAIN"))
+		return dnsReply(header, &question, nil, dnsmessage.RCodeNameError, maxSize)
+	}
+
+	var answers []dnsmessage.Resource
+	var logged []string
+	owner := question.Name
+	// Follow local CNAME chains, stopping at loops
+	seen := map[string]bool{name: true}
+	for len(records) > 0 {
+		if records[0].typ == dnsmessage.TypeCNAME {
+			target := dnsmessage.MustNewName(records[0].target)
+			answers = append(answers, dnsmessage.Resource{
+				Header: dnsmessage.ResourceHeader{Name: owner, Type: dnsmessage.TypeCNAME, Class: dnsmessage.ClassINET, TTL: z.ttl},
+				Body:   &dnsmessage.CNAMEResource{CNAME: target},
+			})
+			logged = append(logged, records[0].String())
+			if question.Type == dnsmessage.TypeCNAME || seen[records[0].target] {
+				break
+			}
+			seen[records[0].target] = true
+			owner = target
+			records, _ = z.lookup(records[0].target)
+			continue
+		}
+		for _, r := range records {
+			if r.typ != question.Type && question.Type != dnsmessage.TypeALL {
+				continue
+			}
+			h := dnsmessage.ResourceHeader{Name: owner, Type: r.typ, Class: dnsmessage.ClassINET, TTL: z.ttl}
+			if r.typ == dnsmessage.TypeA {
+				var a dnsmessage.AResource
+				copy(a.A[:], r.addr.To4())
+				answers = append(answers, dnsmessage.Resource{Header: h, Body: &a})
+			} else {
+				var aaaa dnsmessage.AAAAResource
+				copy(aaaa.AAAA[:], r.addr.To16())
+				answers = append(answers, dnsmessage.Resource{Header: h, Body: &aaaa})
+			}
+			logged = append(logged, r.String())
+		}
+		break
+	}
+
+	if len(logged) == 0 {
+		logged = append(logged, output.Muted("no "+dnsTypeName(question.Type)+" records"))
+	}
+	serveLog("%-5s %s %s", dnsTypeName(question.Type), strings.TrimSuffix(name, "."), strings.Join(logged, ", "))
+	return dnsReply(header, &question, answers, dnsmessage.RCodeSuccess, maxSize)
+}
+
+// dnsReply packs an authoritative response; answers that do not fit in
+// maxSize are dropped and the response marked truncated
+func dnsReply(query dnsmessage.Header, question *dnsmessage.Question, answers []dnsmessage.Resource, rcode dnsmessage.RCode, maxSize int) []byte {
+	msg := dnsmessage.Message{
+		Header: dnsmessage.Header{
+			ID:                 query.ID,
+			Response:           true,
+			OpCode:             query.OpCode,
+			Authoritative:      rcode == dnsmessage.RCodeSuccess || rcode == dnsmessage.RCodeNameError,
+			RecursionDesired:   query.RecursionDesired,
+			RecursionAvailable: false,
+			RCode:              rcode,
+		},
+		Answers: answers,
+	}
+	if question != nil {
+		msg.Questions = []dnsmessage.Question{*question}
+	}
+	packed, err := msg.Pack()
+	if err != nil {
+		return nil
+	}
+	if len(packed) > maxSize {
+		msg.Answers = nil
+		msg.Truncated = true
+		packed, _ = msg.Pack()
+	}
+	return packed
+}
+
+// serveDNSUDP answers queries on a UDP socket
+func serveDNSUDP(conn net.PacketConn, zone *dnsZone, upstream string) {
+	buf := make([]byte, 65535)
+	for {
+		n, addr, err := conn.ReadFrom(buf)
+		if err != nil {
+			return
+		}
+		query := append([]byte(nil), buf[:n]...)
+		go func() {
+			reply := zone.answer(query, upstream, 512)
+			if reply == nil && upstream != "" {
+				reply = dnsForward("udp", upstream, query)
+			}
+			if reply != nil {
+				conn.WriteTo(reply, addr)
+			}
+		}()
+	}
+}
+
+// serveDNSTCP answers length-prefixed queries on TCP connections
+func serveDNSTCP(listener net.Listener, zone *dnsZone, upstream string) {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			defer conn.Close()
+			buf := make([]byte, 65535)
+			for {
+				conn.SetDeadline(time.Now().Add(10 * time.Second))
+				if _, err := io.ReadFull(conn, buf[:2]); err != nil {
+					return
+				}
+				size := int(buf[0])<<8 | int(buf[1])
+				if _, err := io.ReadFull(conn, buf[:size]); err != nil {
+					return
+				}
+				query := buf[:size]
+				reply := zone.answer(query, upstream, 65535)
+				if reply == nil && upstream != "" {
+					reply = dnsForward("tcp", upstream, query)
+				}
+				if reply == nil {
+					return
+				}
+				framed := append([]byte{byte(len(reply) >> 8), byte(len(reply))}, reply...)
+				if _, err := conn.Write(framed); err != nil {
+					return
+				}
+			}
+		}()
+	}
+}
+
+// dnsForward relays a query to the upstream resolver and returns its
+// response unchanged
+func dnsForward(network, upstream string, query []byte) []byte {
+	var p dnsmessage.Parser
+	if _, err := p.Start(query); err == nil {
+		if q, err := p.Question(); err == nil {
+			serveLog("%-5s %s %s", dnsTypeName(q.Type), strings.TrimSuffix(strings.ToLower(q.Name.String()), "."), output.Muted("→ "+upstream))
+		}
+	}
+
+	conn, err := net.DialTimeout(network, upstream, 3*time.Second)
+	if err != nil {
+		return nil
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	buf := make([]byte, 65535)
+	if network == "tcp" {
+		framed := append([]byte{byte(len(query) >> 8), byte(len(query))}, query...)
+		if _, err := conn.Write(framed); err != nil {
+			return nil
+		}
+		if _, err := io.ReadFull(conn, buf[:2]); err != nil {
+			return nil
+		}
+		size := int(buf[0])<<8 | int(buf[1])
+		if _, err := io.ReadFull(conn, buf[:size]); err != nil {
+			return nil
+		}
+		return buf[:size]
+	}
+	if _, err := conn.Write(query); err != nil {
+		return nil
+	}
+	n, err := conn.Read(buf)
+	if err != nil {
+		return nil
+	}
+	return buf[:n]
+}
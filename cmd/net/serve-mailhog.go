@@ -0,0 +1,704 @@
+package net
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/http"
+	"net/mail"
+	"net/textproto"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"devkit/internal/output"
+)
+
+// serveMailhogCmd represents the serve mailhog subcommand
+var serveMailhogCmd = &cobra.Command{
+	Use:   "mailhog",
+	Short: "Catch-all SMTP server with a web inbox and JSON API",
+	Long: `Accept every message sent over SMTP, keep it in memory and show it in a
+web inbox and a JSON API, so applications can send email during development
+without a real mail server. Nothing is delivered anywhere.
+
+Any sender, recipient and AUTH PLAIN/LOGIN credentials are accepted;
+STARTTLS is not offered, so configure the application for plain SMTP.
+With --pop3, mail clients can read the same inbox (any user and password).
+
+HTTP API:
+  GET    /api/messages            List messages, newest first (?to=addr filters)
+  GET    /api/messages/{id}       Message with headers, text and HTML bodies
+  GET    /api/messages/{id}/raw   The message as received
+  DELETE /api/messages/{id}       Delete one message
+  DELETE /api/messages            Delete all messages
+
+Examples:
+  devkit net serve mailhog
+  devkit net serve mailhog --smtp :2525 --http 127.0.0.1:8025
+  devkit net serve mailhog --pop3 :1110 --max-messages 100
+  curl -s localhost:8025/api/messages | jq '.messages[0].subject'`,
+	Args: cobra.NoArgs,
+	RunE: runServeMailhog,
+}
+
+func init() {
+	serveCmd.AddCommand(serveMailhogCmd)
+
+	serveMailhogCmd.Flags().String("smtp", ":1025", "SMTP listen address")
+	serveMailhogCmd.Flags().String("http", ":8025", "Web inbox and JSON API listen address (empty to disable)")
+	serveMailhogCmd.Flags().String("pop3", "", "POP3 listen address, e.g. :1110 (default: disabled)")
+	serveMailhogCmd.Flags().Int("max-messages", 1000, "Messages kept in memory; the oldest are dropped")
+	serveMailhogCmd.Flags().Int("max-size", 25, "Maximum message size in MB")
+}
+
+// mailAttachment is a non-body part of a message
+type mailAttachment struct {
+	Filename    string `json:"filename"`
+	ContentType string `json:"content_type"`
+	Size        int    `json:"size"`
+}
+
+// mailMessage is a received message; raw holds it as received
+type mailMessage struct {
+	ID          int                 `json:"id"`
+	From        string              `json:"from"`
+	To          []string            `json:"to"`
+	Subject     string              `json:"subject"`
+	Date        string              `json:"date,omitempty"`
+	Received    string              `json:"received"`
+	Size        int                 `json:"size"`
+	Headers     map[string][]string `json:"headers,omitempty"`
+	Text        string              `json:"text,omitempty"`
+	HTML        string              `json:"html,omitempty"`
+	Attachments []mailAttachment    `json:"attachments,omitempty"`
+	raw         []byte
+}
+
+// summary returns the message without headers and bodies, for listings
+func (m *mailMessage) summary() mailMessage {
+	return mailMessage{
+		ID:          m.ID,
+		From:        m.From,
+		To:          m.To,
+		Subject:     m.Subject,
+		Date:        m.Date,
+		Received:    m.Received,
+		Size:        m.Size,
+		Attachments: m.Attachments,
+	}
+}
+
+// mailStore is the in-memory inbox shared by the SMTP, POP3 and HTTP sides
+type mailStore struct {
+	mu       sync.Mutex
+	messages []*mailMessage
+	nextID   int
+	max      int
+}
+
+func (s *mailStore) add(from string, to []string, raw []byte) *mailMessage {
+	msg := parseMailMessage(raw)
+	msg.From, msg.To = from, to
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nextID++
+	msg.ID = s.nextID
+	s.messages = append(s.messages, msg)
+	if len(s.messages) > s.max {
+		s.messages = s.messages[len(s.messages)-s.max:]
+	}
+	return msg
+}
+
+// list returns the messages, oldest first
+func (s *mailStore) list() []*mailMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*mailMessage(nil), s.messages...)
+}
+
+func (s *mailStore) get(id int) *mailMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, m := range s.messages {
+		if m.ID == id {
+			return m
+		}
+	}
+	return nil
+}
+
+// remove deletes the given messages, or all of them when ids is nil
+func (s *mailStore) remove(ids map[int]bool) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if ids == nil {
+		n := len(s.messages)
+		s.messages = nil
+		return n
+	}
+	kept := s.messages[:0]
+	for _, m := range s.messages {
+		if !ids[m.ID] {
+			kept = append(kept, m)
+		}
+	}
+	n := len(s.messages) - len(kept)
+	s.messages = kept
+	return n
+}
+
+func runServeMailhog(cmd *cobra.Command, args []string) error {
+	smtpAddr, _ := cmd.Flags().GetString("smtp")
+	httpAddr, _ := cmd.Flags().GetString("http")
+	pop3Addr, _ := cmd.Flags().GetString("pop3")
+	maxMessages, _ := cmd.Flags().GetInt("max-messages")
+	maxSize, _ := cmd.Flags().GetInt("max-size")
+
+	if smtpAddr == "" {
+		return fmt.Errorf("--smtp cannot be empty")
+	}
+	if maxMessages < 1 {
+		return fmt.Errorf("max-messages must be at least 1")
+	}
+	if maxSize < 1 {
+		return fmt.Errorf("max-size must be at least 1")
+	}
+	cmd.SilenceUsage = true
+
+	store := &mailStore{max: maxMessages}
+	hostname, _ := os.Hostname()
+	if hostname == "" {
+		hostname = "localhost"
+	}
+
+	smtpListener, err := net.Listen("tcp", smtpAddr)
+	if err != nil {
+		return fmt.Errorf("failed to listen for SMTP: %w", err)
+	}
+	defer smtpListener.Close()
+	smtp := &smtpServer{store: store, hostname: hostname, maxSize: int64(maxSize) << 20}
+	go acceptLoop(smtpListener, smtp.handle)
+	fmt.Printf("%-5s %s\n", "SMTP", output.Accent(serveURL("smtp", smtpListener.Addr())))
+
+	if pop3Addr != "" {
+		pop3Listener, err := net.Listen("tcp", pop3Addr)
+		if err != nil {
+			return fmt.Errorf("failed to listen for POP3: %w", err)
+		}
+		defer pop3Listener.Close()
+		pop3 := &pop3Server{store: store}
+		go acceptLoop(pop3Listener, pop3.handle)
+		fmt.Printf("%-5s %s\n", "POP3", output.Accent(serveURL("pop3", pop3Listener.Addr())))
+	}
+
+	if httpAddr != "" {
+		httpListener, err := net.Listen("tcp", httpAddr)
+		if err != nil {
+			return fmt.Errorf("failed to listen for HTTP: %w", err)
+		}
+		server := &http.Server{Handler: mailHTTPHandler(store), ReadHeaderTimeout: 10 * time.Second}
+		defer server.Close()
+		go server.Serve(httpListener)
+		webURL := serveURL("http", httpListener.Addr())
+		fmt.Printf("%-5s %s  %s\n", "Web", output.Accent(webURL), output.Muted("(JSON API at "+webURL+"/api/messages)"))
+	}
+
+	fmt.Println(output.Muted("Press Ctrl+C to stop"))
+	waitForInterrupt()
+	return nil
+}
+
+// acceptLoop serves every connection of a listener until it is closed
+func acceptLoop(listener net.Listener, handle func(net.Conn)) {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		go handle(conn)
+	}
+}
+
+// smtpServer accepts every message and adds it to the store
+type smtpServer struct {
+	store    *mailStore
+	hostname string
+	maxSize  int64
+}
+
+func (s *smtpServer) handle(conn net.Conn) {
+	defer conn.Close()
+	tp := textproto.NewConn(conn)
+	tp.PrintfLine("220 %s ESMTP devkit mailhog ready", s.hostname)
+
+	var (
+		from   string
+		to     []string
+		inMail bool
+	)
+	for {
+		conn.SetDeadline(time.Now().Add(5 * time.Minute))
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		verb, arg, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(verb) {
+		case "HELO":
+			tp.PrintfLine("250 %s", s.hostname)
+		case "EHLO":
+			tp.PrintfLine("250-%s", s.hostname)
+			tp.PrintfLine("250-SIZE %d", s.maxSize)
+			tp.PrintfLine("250-8BITMIME")
+			tp.PrintfLine("250-PIPELINING")
+			tp.PrintfLine("250 AUTH PLAIN LOGIN")
+		case "AUTH":
+			s.auth(tp, arg)
+		case "MAIL":
+			addr, ok := smtpPath(arg, "FROM:")
+			if !ok {
+				tp.PrintfLine("501 Syntax: MAIL FROM:<address>")
+				continue
+			}
+			from, to, inMail = addr, nil, true
+			tp.PrintfLine("250 OK")
+		case "RCPT":
+			addr, ok := smtpPath(arg, "TO:")
+			switch {
+			case !inMail:
+				tp.PrintfLine("503 MAIL first")
+			case !ok || addr == "":
+				tp.PrintfLine("501 Syntax: RCPT TO:<address>")
+			default:
+				to = append(to, addr)
+				tp.PrintfLine("250 OK")
+			}
+		case "DATA":
+			if len(to) == 0 {
+				tp.PrintfLine("503 RCPT first")
+				continue
+			}
+			tp.PrintfLine("354 End data with <CR><LF>.<CR><LF>")
+			raw, err := io.ReadAll(io.LimitReader(tp.DotReader(), s.maxSize+1))
+			if err != nil {
+				return
+			}
+			if int64(len(raw)) > s.maxSize {
+				io.Copy(io.Discard, tp.DotReader())
+				tp.PrintfLine("552 Message exceeds the maximum size")
+				from, to, inMail = "", nil, false
+				continue
+			}
+			msg := s.store.add(from, to, raw)
+			tp.PrintfLine("250 OK: queued as %d", msg.ID)
+			sender := msg.From
+			if sender == "" {
+				sender = "<>"
+			}
+			serveLog("%s %s → %s  %s", output.Accent(fmt.Sprintf("#%d", msg.ID)), sender, strings.Join(msg.To, ", "), msg.Subject)
+			from, to, inMail = "", nil, false
+		case "RSET":
+			from, to, inMail = "", nil, false
+			tp.PrintfLine("250 OK")
+		case "NOOP":
+			tp.PrintfLine("250 OK")
+		case "VRFY":
+			tp.PrintfLine("252 Cannot verify, but will accept the message")
+		case "STARTTLS":
+			tp.PrintfLine("454 TLS not available")
+		case "QUIT":
+			tp.PrintfLine("221 Bye")
+			return
+		default:
+			tp.PrintfLine("502 Command not implemented")
+		}
+	}
+}
+
+// auth accepts any credentials for AUTH PLAIN and AUTH LOGIN
+func (s *smtpServer) auth(tp *textproto.Conn, arg string) {
+	mechanism, initial, _ := strings.Cut(arg, " ")
+	switch strings.ToUpper(mechanism) {
+	case "PLAIN":
+		if initial == "" {
+			tp.PrintfLine("334 ")
+			if _, err := tp.ReadLine(); err != nil {
+				return
+			}
+		}
+	case "LOGIN":
+		if initial == "" {
+			tp.PrintfLine("334 VXNlcm5hbWU6")
+			if _, err := tp.ReadLine(); err != nil {
+				return
+			}
+		}
+		tp.PrintfLine("334 UGFzc3dvcmQ6")
+		if _, err := tp.ReadLine(); err != nil {
+			return
+		}
+	default:
+		tp.PrintfLine("504 Unrecognized authentication type")
+		return
+	}
+	tp.PrintfLine("235 Authentication successful")
+}
+
+// smtpPath extracts the address of MAIL FROM:<a> and RCPT TO:<a>,
+// ignoring parameters such as SIZE=1234
+func smtpPath(arg, prefix string) (string, bool) {
+	if len(arg) < len(prefix) || !strings.EqualFold(arg[:len(prefix)], prefix) {
+		return "", false
+	}
+	path := strings.TrimSpace(arg[len(prefix):])
+	if strings.HasPrefix(path, "<") {
+		end := strings.Index(path, ">")
+		if end < 0 {
+			return "", false
+		}
+		return path[1:end], true
+	}
+	addr, _, _ := strings.Cut(path, " ")
+	return addr, addr != ""
+}
+
+// parseMailMessage extracts the subject, bodies and attachments of a raw
+// message; unparsable messages keep just their raw form
+func parseMailMessage(raw []byte) *mailMessage {
+	msg := &mailMessage{
+		Received: time.Now().Format(time.RFC3339),
+		Size:     len(raw),
+		raw:      raw,
+	}
+	parsed, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		msg.Text = string(raw)
+		return msg
+	}
+
+	decoder := new(mime.WordDecoder)
+	msg.Headers = map[string][]string(parsed.Header)
+	msg.Subject = parsed.Header.Get("Subject")
+	if decoded, err := decoder.DecodeHeader(msg.Subject); err == nil {
+		msg.Subject = decoded
+	}
+	msg.Date = parsed.Header.Get("Date")
+	parseMailPart(textproto.MIMEHeader(parsed.Header), parsed.Body, msg)
+	return msg
+}
+
+// parseMailPart walks a MIME part, collecting the first text/plain and
+// text/html bodies and listing everything else as attachments
+func parseMailPart(header textproto.MIMEHeader, body io.Reader, msg *mailMessage) {
+	mediaType, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		mediaType = "text/plain"
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		mr := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := mr.NextRawPart()
+			if err != nil {
+				return
+			}
+			parseMailPart(part.Header, part, msg)
+		}
+	}
+
+	switch strings.ToLower(header.Get("Content-Transfer-Encoding")) {
+	case "quoted-printable":
+		body = quotedprintable.NewReader(body)
+	case "base64":
+		body = base64.NewDecoder(base64.StdEncoding, body)
+	}
+	data, _ := io.ReadAll(io.LimitReader(body, 64<<20))
+
+	disposition, dispParams, _ := mime.ParseMediaType(header.Get("Content-Disposition"))
+	filename := dispParams["filename"]
+	if filename == "" {
+		filename = params["name"]
+	}
+	switch {
+	case disposition != "attachment" && filename == "" && mediaType == "text/plain" && msg.Text == "":
+		msg.Text = string(data)
+	case disposition != "attachment" && filename == "" && mediaType == "text/html" && msg.HTML == "":
+		msg.HTML = string(data)
+	default:
+		msg.Attachments = append(msg.Attachments, mailAttachment{
+			Filename:    filename,
+			ContentType: mediaType,
+			Size:        len(data),
+		})
+	}
+}
+
+// pop3Server serves the store as a single mailbox to any user
+type pop3Server struct {
+	store *mailStore
+}
+
+func (s *pop3Server) handle(conn net.Conn) {
+	defer conn.Close()
+	tp := textproto.NewConn(conn)
+	tp.PrintfLine("+OK devkit mailhog POP3 ready")
+
+	var (
+		mailbox []*mailMessage
+		deleted = map[int]bool{}
+		authed  bool
+	)
+	// message returns the message numbered by arg (1-based) in the mailbox
+	message := func(arg string) *mailMessage {
+		n, err := strconv.Atoi(strings.TrimSpace(arg))
+		if err != nil || n < 1 || n > len(mailbox) || deleted[mailbox[n-1].ID] {
+			tp.PrintfLine("-ERR no such message")
+			return nil
+		}
+		return mailbox[n-1]
+	}
+
+	for {
+		conn.SetDeadline(time.Now().Add(10 * time.Minute))
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		verb, arg, _ := strings.Cut(line, " ")
+		verb = strings.ToUpper(verb)
+
+		if !authed {
+			switch verb {
+			case "CAPA":
+				tp.PrintfLine("+OK")
+				tp.PrintfLine("USER\r\nUIDL\r\nTOP\r\n.")
+			case "USER":
+				tp.PrintfLine("+OK any user is accepted")
+			case "PASS":
+				authed, mailbox = true, s.store.list()
+				tp.PrintfLine("+OK %d messages", len(mailbox))
+			case "QUIT":
+				tp.PrintfLine("+OK bye")
+				return
+			default:
+				tp.PrintfLine("-ERR authenticate first")
+			}
+			continue
+		}
+
+		switch verb {
+		case "STAT":
+			count, size := 0, 0
+			for _, m := range mailbox {
+				if !deleted[m.ID] {
+					count++
+					size += m.Size
+				}
+			}
+			tp.PrintfLine("+OK %d %d", count, size)
+		case "LIST", "UIDL":
+			value := func(m *mailMessage) string {
+				if verb == "UIDL" {
+					return strconv.Itoa(m.ID)
+				}
+				return strconv.Itoa(m.Size)
+			}
+			if arg != "" {
+				if m := message(arg); m != nil {
+					tp.PrintfLine("+OK %s %s", arg, value(m))
+				}
+				continue
+			}
+			tp.PrintfLine("+OK")
+			for i, m := range mailbox {
+				if !deleted[m.ID] {
+					tp.PrintfLine("%d %s", i+1, value(m))
+				}
+			}
+			tp.PrintfLine(".")
+		case "RETR", "TOP":
+			num, lines, _ := strings.Cut(arg, " ")
+			m := message(num)
+			if m == nil {
+				continue
+			}
+			data := m.raw
+			if verb == "TOP" {
+				data = pop3Top(data, lines)
+			}
+			tp.PrintfLine("+OK %d octets", len(data))
+			w := tp.DotWriter()
+			w.Write(data)
+			w.Close()
+		case "DELE":
+			if m := message(arg); m != nil {
+				deleted[m.ID] = true
+				tp.PrintfLine("+OK deleted")
+			}
+		case "RSET":
+			deleted = map[int]bool{}
+			tp.PrintfLine("+OK")
+		case "NOOP":
+			tp.PrintfLine("+OK")
+		case "QUIT":
+			s.store.remove(deleted)
+			tp.PrintfLine("+OK bye")
+			return
+		default:
+			tp.PrintfLine("-ERR unknown command")
+		}
+	}
+}
+
+// pop3Top returns the headers and the first n body lines of a message
+func pop3Top(raw []byte, n string) []byte {
+	lines, err := strconv.Atoi(strings.TrimSpace(n))
+	if err != nil || lines < 0 {
+		lines = 0
+	}
+	sep := []byte("\r\n\r\n")
+	end := bytes.Index(raw, sep)
+	if end < 0 {
+		sep = []byte("\n\n")
+		if end = bytes.Index(raw, sep); end < 0 {
+			return raw
+		}
+	}
+	head, body := raw[:end+len(sep)], raw[end+len(sep):]
+	bodyLines := bytes.SplitAfter(body, []byte("\n"))
+	if lines < len(bodyLines) {
+		bodyLines = bodyLines[:lines]
+	}
+	return append(append([]byte(nil), head...), bytes.Join(bodyLines, nil)...)
+}
+
+// mailInboxPage is the web inbox; it refreshes itself every few seconds
+var mailInboxPage = template.Must(template.New("inbox").Parse(`<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>devkit mailhog ({{len .}})</title>
+<meta http-equiv="refresh" content="5">
+<style>body{font-family:sans-serif;margin:2em}table{border-collapse:collapse;width:100%}
+td,th{text-align:left;padding:.4em .8em;border-bottom:1px solid #ddd}a{color:#0366d6}</style></head>
+<body><h1>Inbox</h1>
+{{if not .}}<p>No messages yet.</p>{{else}}
+<table><tr><th>#</th><th>From</th><th>To</th><th>Subject</th><th>Received</th></tr>
+{{range .}}<tr><td>{{.ID}}</td><td>{{.From}}</td><td>{{range $i, $to := .To}}{{if $i}}, {{end}}{{$to}}{{end}}</td>
+<td><a href="/messages/{{.ID}}">{{if .Subject}}{{.Subject}}{{else}}(no subject){{end}}</a></td><td>{{.Received}}</td></tr>
+{{end}}</table>{{end}}
+</body></html>
+`))
+
+// mailMessagePage shows one message; the HTML body is shown in a sandboxed
+// frame so its scripts cannot run
+var mailMessagePage = template.Must(template.New("message").Parse(`<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>{{.Subject}}</title>
+<style>body{font-family:sans-serif;margin:2em}pre{white-space:pre-wrap;background:#f6f8fa;padding:1em}
+iframe{width:100%;height:60vh;border:1px solid #ddd}th{text-align:left;padding-right:1em}</style></head>
+<body><p><a href="/">&larr; Inbox</a> · <a href="/api/messages/{{.ID}}/raw">Raw</a> · <a href="/api/messages/{{.ID}}">JSON</a></p>
+<h1>{{if .Subject}}{{.Subject}}{{else}}(no subject){{end}}</h1>
+<table><tr><th>From</th><td>{{.From}}</td></tr><tr><th>To</th><td>{{range $i, $to := .To}}{{if $i}}, {{end}}{{$to}}{{end}}</td></tr>
+<tr><th>Date</th><td>{{.Date}}</td></tr>
+{{range .Attachments}}<tr><th>Attachment</th><td>{{.Filename}} ({{.ContentType}}, {{.Size}} bytes)</td></tr>{{end}}</table>
+{{if .HTML}}<h2>HTML</h2><iframe sandbox srcdoc="{{.HTML}}"></iframe>{{end}}
+{{if .Text}}<h2>Text</h2><pre>{{.Text}}</pre>{{end}}
+</body></html>
+`))
+
+// mailHTTPHandler serves the web inbox and the JSON API
+func mailHTTPHandler(store *mailStore) http.Handler {
+	mux := http.NewServeMux()
+
+	writeJSON := func(w http.ResponseWriter, status int, v interface{}) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		enc.Encode(v)
+	}
+	// lookup finds the message of the {id} path value or answers 404
+	lookup := func(w http.ResponseWriter, r *http.Request) *mailMessage {
+		id, _ := strconv.Atoi(r.PathValue("id"))
+		m := store.get(id)
+		if m == nil {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": "message not found"})
+		}
+		return m
+	}
+	newestFirst := func(to string) []*mailMessage {
+		messages := store.list()
+		var out []*mailMessage
+		for i := len(messages) - 1; i >= 0; i-- {
+			m := messages[i]
+			if to != "" && !containsFold(m.To, to) {
+				continue
+			}
+			out = append(out, m)
+		}
+		return out
+	}
+
+	mux.HandleFunc("GET /api/messages", func(w http.ResponseWriter, r *http.Request) {
+		messages := []mailMessage{}
+		for _, m := range newestFirst(r.URL.Query().Get("to")) {
+			messages = append(messages, m.summary())
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"count": len(messages), "messages": messages})
+	})
+	mux.HandleFunc("DELETE /api/messages", func(w http.ResponseWriter, r *http.Request) {
+		n := store.remove(nil)
+		serveLog("deleted %d messages", n)
+		writeJSON(w, http.StatusOK, map[string]int{"deleted": n})
+	})
+	mux.HandleFunc("GET /api/messages/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if m := lookup(w, r); m != nil {
+			writeJSON(w, http.StatusOK, m)
+		}
+	})
+	mux.HandleFunc("GET /api/messages/{id}/raw", func(w http.ResponseWriter, r *http.Request) {
+		if m := lookup(w, r); m != nil {
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			w.Write(m.raw)
+		}
+	})
+	mux.HandleFunc("DELETE /api/messages/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if m := lookup(w, r); m != nil {
+			store.remove(map[int]bool{m.ID: true})
+			writeJSON(w, http.StatusOK, map[string]int{"deleted": 1})
+		}
+	})
+	mux.HandleFunc("GET /messages/{id}", func(w http.ResponseWriter, r *http.Request) {
+		id, _ := strconv.Atoi(r.PathValue("id"))
+		m := store.get(id)
+		if m == nil {
+			http.NotFound(w, r)
+			return
+		}
+		mailMessagePage.Execute(w, m)
+	})
+	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, r *http.Request) {
+		mailInboxPage.Execute(w, newestFirst(r.URL.Query().Get("to")))
+	})
+	return mux
+}
+
+// containsFold reports whether list contains s, ignoring case
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
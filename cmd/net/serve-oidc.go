@@ -0,0 +1,681 @@
+package net
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"html/template"
+	"math/big"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+	"devkit/internal/output"
+)
+
+// serveOIDCCmd represents the serve oidc subcommand
+var serveOIDCCmd = &cobra.Command{
+	Use:   "oidc",
+	Short: "Mock OAuth2/OpenID Connect identity provider",
+	Long: `Run an OpenID Connect provider for testing login flows offline. It serves
+discovery, JWKS, authorize, token, userinfo and logout endpoints and issues
+RS256-signed ID and access tokens carrying the claims of the test users.
+
+Any client ID, client secret and redirect URI are accepted. The authorize
+endpoint shows a page to pick a user; login_hint=<username> signs in
+without it, which suits browser tests. Supported grants:
+authorization_code (with PKCE), refresh_token, password and
+client_credentials.
+
+The users file lists the test users and their claims:
+
+  users:
+    - username: alice
+      password: secret        # optional; any password when omitted
+      claims:
+        email: alice@example.com
+        name: Alice Example
+        groups: [admin, dev]
+  claims:                     # optional; added to every user's tokens
+    tenant: local
+
+Without --users a single user "dev" is available. The signing key is
+generated at startup unless --key names an RSA private key (PEM), so
+tokens stay valid across restarts only with --key.
+
+Examples:
+  devkit net serve oidc
+  devkit net serve oidc --port 9100 --users users.yaml
+  devkit net serve oidc --issuer http://oidc.local:9100 --token-ttl 5m --key oidc-key.pem
+  curl -s localhost:9100/token -d grant_type=password -d username=alice -d password=secret -d client_id=app`,
+	Args: cobra.NoArgs,
+	RunE: runServeOIDC,
+}
+
+func init() {
+	serveCmd.AddCommand(serveOIDCCmd)
+
+	serveOIDCCmd.Flags().Int("port", 9100, "Port to listen on")
+	serveOIDCCmd.Flags().String("bind", "", "Address to bind, e.g. 127.0.0.1 (default: all interfaces)")
+	serveOIDCCmd.Flags().String("users", "", "Users file (YAML) with usernames, passwords and claims")
+	serveOIDCCmd.Flags().String("issuer", "", "Issuer URL in tokens and discovery (default: http://localhost:<port>)")
+	serveOIDCCmd.Flags().Duration("token-ttl", time.Hour, "Lifetime of ID and access tokens")
+	serveOIDCCmd.Flags().String("key", "", "RSA private key (PEM) used to sign tokens (default: generated)")
+}
+
+// oidcUser is a test user from the users file
+type oidcUser struct {
+	Username string                 `yaml:"username"`
+	Password string                 `yaml:"password"`
+	Claims   map[string]interface{} `yaml:"claims"`
+}
+
+// oidcConfig is the structure of the users file
+type oidcConfig struct {
+	Users  []oidcUser             `yaml:"users"`
+	Claims map[string]interface{} `yaml:"claims"`
+}
+
+// oidcGrant is what an authorization code or refresh token stands for
+type oidcGrant struct {
+	user          *oidcUser
+	clientID      string
+	redirectURI   string
+	scope         string
+	nonce         string
+	challenge     string
+	method        string
+	authenticated time.Time
+	expires       time.Time
+}
+
+// oidcProvider holds the signing key, users and outstanding grants
+type oidcProvider struct {
+	issuer        string
+	ttl           time.Duration
+	key           *rsa.PrivateKey
+	keyID         string
+	config        oidcConfig
+	mu            sync.Mutex
+	codes         map[string]*oidcGrant
+	refreshTokens map[string]*oidcGrant
+}
+
+func runServeOIDC(cmd *cobra.Command, args []string) error {
+	port, _ := cmd.Flags().GetInt("port")
+	bind, _ := cmd.Flags().GetString("bind")
+	usersFile, _ := cmd.Flags().GetString("users")
+	issuer, _ := cmd.Flags().GetString("issuer")
+	ttl, _ := cmd.Flags().GetDuration("token-ttl")
+	keyFile, _ := cmd.Flags().GetString("key")
+
+	if port < 1 || port > 65535 {
+		return fmt.Errorf("invalid port: %d", port)
+	}
+	if ttl < time.Second {
+		return fmt.Errorf("token-ttl must be at least 1s")
+	}
+	if issuer == "" {
+		issuer = "http://localhost:" + strconv.Itoa(port)
+	}
+	issuer = strings.TrimSuffix(issuer, "/")
+	if u, err := url.Parse(issuer); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid issuer: %s (use an http or https URL)", issuer)
+	}
+	cmd.SilenceUsage = true
+
+	config := oidcConfig{Users: []oidcUser{{
+		Username: "dev",
+		Claims:   map[string]interface{}{"email": "dev@example.com", "email_verified": true, "name": "Dev User"},
+	}}}
+	if usersFile != "" {
+		data, err := os.ReadFile(usersFile)
+		if err != nil {
+			return fmt.Errorf("failed to read users file: %w", err)
+		}
+		config = oidcConfig{}
+		if err := yaml.Unmarshal(data, &config); err != nil {
+			return fmt.Errorf("failed to parse users file: %w", err)
+		}
+		if len(config.Users) == 0 {
+			return fmt.Errorf("no users in %s", usersFile)
+		}
+		for i, user := range config.Users {
+			if user.Username == "" {
+				return fmt.Errorf("user %d in %s has no username", i+1, usersFile)
+			}
+		}
+	}
+
+	key, err := loadOIDCKey(keyFile)
+	if err != nil {
+		return err
+	}
+	modulusSum := sha256.Sum256(key.N.Bytes())
+	provider := &oidcProvider{
+		issuer:        issuer,
+		ttl:           ttl,
+		key:           key,
+		keyID:         base64.RawURLEncoding.EncodeToString(modulusSum[:8]),
+		config:        config,
+		codes:         map[string]*oidcGrant{},
+		refreshTokens: map[string]*oidcGrant{},
+	}
+
+	listener, err := net.Listen("tcp", net.JoinHostPort(bind, strconv.Itoa(port)))
+	if err != nil {
+		return fmt.Errorf("failed to listen: %w", err)
+	}
+	server := &http.Server{Handler: provider.handler(), ReadHeaderTimeout: 10 * time.Second}
+	defer server.Close()
+	go server.Serve(listener)
+
+	names := make([]string, 0, len(config.Users))
+	for _, user := range config.Users {
+		names = append(names, user.Username)
+	}
+	fmt.Printf("%-5s %s", "OIDC", output.Accent(issuer))
+	if local := serveURL("http", listener.Addr()); local != issuer {
+		fmt.Printf("  %s", output.Muted("(listening on "+local+")"))
+	}
+	fmt.Println()
+	fmt.Printf("      Discovery: %s/.well-known/openid-configuration\n", issuer)
+	fmt.Printf("      Users: %s\n", strings.Join(names, ", "))
+	fmt.Println(output.Muted("Press Ctrl+C to stop"))
+	waitForInterrupt()
+	return nil
+}
+
+// loadOIDCKey reads an RSA private key in PKCS#1 or PKCS#8 PEM, or
+// generates one when path is empty
+func loadOIDCKey(path string) (*rsa.PrivateKey, error) {
+	if path == "" {
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate signing key: %w", err)
+		}
+		return key, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM block in %s", path)
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse key %s: %w", path, err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("%s is not an RSA private key", path)
+	}
+	return key, nil
+}
+
+func (p *oidcProvider) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/openid-configuration", p.discovery)
+	mux.HandleFunc("GET /jwks", p.jwks)
+	mux.HandleFunc("GET /authorize", p.authorize)
+	mux.HandleFunc("POST /authorize", p.authorize)
+	mux.HandleFunc("POST /token", p.token)
+	mux.HandleFunc("GET /userinfo", p.userinfo)
+	mux.HandleFunc("POST /userinfo", p.userinfo)
+	mux.HandleFunc("GET /logout", p.logout)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec := &serveStatusWriter{ResponseWriter: w, status: http.StatusOK}
+		// Single-page apps call the token and userinfo endpoints directly
+		rec.Header().Set("Access-Control-Allow-Origin", "*")
+		if r.Method == http.MethodOptions {
+			rec.Header().Set("Access-Control-Allow-Methods", "GET, POST")
+			rec.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type")
+			serveLogRequest(r, http.StatusOK)
+			return
+		}
+		mux.ServeHTTP(rec, r)
+		serveLogRequest(r, rec.status)
+	})
+}
+
+func oidcJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(v)
+}
+
+// oidcError answers with an OAuth2 error response
+func oidcError(w http.ResponseWriter, status int, code, description string) {
+	oidcJSON(w, status, map[string]string{"error": code, "error_description": description})
+}
+
+func (p *oidcProvider) discovery(w http.ResponseWriter, r *http.Request) {
+	oidcJSON(w, http.StatusOK, map[string]interface{}{
+		"issuer":                                p.issuer,
+		"authorization_endpoint":                p.issuer + "/authorize",
+		"token_endpoint":                        p.issuer + "/token",
+		"userinfo_endpoint":                     p.issuer + "/userinfo",
+		"jwks_uri":                              p.issuer + "/jwks",
+		"end_session_endpoint":                  p.issuer + "/logout",
+		"response_types_supported":              []string{"code"},
+		"response_modes_supported":              []string{"query"},
+		"grant_types_supported":                 []string{"authorization_code", "refresh_token", "password", "client_credentials"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+		"scopes_supported":                      []string{"openid", "profile", "email", "offline_access"},
+		"token_endpoint_auth_methods_supported": []string{"client_secret_basic", "client_secret_post", "none"},
+		"code_challenge_methods_supported":      []string{"S256", "plain"},
+		"claims_supported":                      []string{"sub", "iss", "aud", "exp", "iat", "auth_time", "nonce", "name", "email"},
+	})
+}
+
+func (p *oidcProvider) jwks(w http.ResponseWriter, r *http.Request) {
+	pub := p.key.PublicKey
+	oidcJSON(w, http.StatusOK, map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"use": "sig",
+			"alg": "RS256",
+			"kid": p.keyID,
+			"n":   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		}},
+	})
+}
+
+func (p *oidcProvider) findUser(username string) *oidcUser {
+	for i := range p.config.Users {
+		if p.config.Users[i].Username == username {
+			return &p.config.Users[i]
+		}
+	}
+	return nil
+}
+
+// checkPassword accepts any password for users without one
+func (u *oidcUser) checkPassword(password string) bool {
+	return u.Password == "" || subtle.ConstantTimeCompare([]byte(u.Password), []byte(password)) == 1
+}
+
+// oidcLoginPage lets the tester pick the user to sign in as
+var oidcLoginPage = template.Must(template.New("login").Parse(`<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>Sign in · devkit oidc</title>
+<style>body{font-family:sans-serif;max-width:28em;margin:3em auto}button{display:block;width:100%;margin:.4em 0;padding:.6em;font-size:1em;cursor:pointer}
+input{width:100%;padding:.5em;margin:.3em 0;box-sizing:border-box}.error{color:#c00}small{color:#666}</style></head>
+<body><h1>Sign in</h1><p><small>to <b>{{.ClientID}}</b> · mock identity provider</small></p>
+{{if .Error}}<p class="error">{{.Error}}</p>{{end}}
+<form method="post" action="/authorize">
+{{range $name, $value := .Params}}<input type="hidden" name="{{$name}}" value="{{$value}}">{{end}}
+{{if .Passwords}}<input name="password" type="password" placeholder="Password (if the user has one)">{{end}}
+{{range .Users}}<button name="username" value="{{.Username}}">{{.Username}}{{with index .Claims "email"}} <small>{{.}}</small>{{end}}</button>{{end}}
+</form></body></html>
+`))
+
+// authorize shows the login page (GET) and issues a code once a user is
+// picked (POST, or GET with login_hint)
+func (p *oidcProvider) authorize(w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+	params := map[string]string{}
+	for _, name := range []string{"response_type", "client_id", "redirect_uri", "scope", "state", "nonce", "code_challenge", "code_challenge_method"} {
+		params[name] = r.Form.Get(name)
+	}
+
+	redirectURI, err := url.Parse(params["redirect_uri"])
+	if err != nil || !redirectURI.IsAbs() {
+		http.Error(w, "invalid or missing redirect_uri", http.StatusBadRequest)
+		return
+	}
+	if params["client_id"] == "" {
+		http.Error(w, "missing client_id", http.StatusBadRequest)
+		return
+	}
+	// From here on errors go back to the client, as the spec asks
+	redirectError := func(code, description string) {
+		q := redirectURI.Query()
+		q.Set("error", code)
+		q.Set("error_description", description)
+		if params["state"] != "" {
+			q.Set("state", params["state"])
+		}
+		redirectURI.RawQuery = q.Encode()
+		http.Redirect(w, r, redirectURI.String(), http.StatusFound)
+	}
+	if params["response_type"] != "code" {
+		redirectError("unsupported_response_type", "only response_type=code is supported")
+		return
+	}
+	if method := params["code_challenge_method"]; method != "" && method != "S256" && method != "plain" {
+		redirectError("invalid_request", "code_challenge_method must be S256 or plain")
+		return
+	}
+
+	username := r.Form.Get("username")
+	if username == "" && r.Method == http.MethodGet {
+		username = r.Form.Get("login_hint")
+	}
+	if username == "" {
+		p.renderLogin(w, params, "")
+		return
+	}
+	user := p.findUser(username)
+	if user == nil {
+		p.renderLogin(w, params, "Unknown user "+username)
+		return
+	}
+	if r.Method == http.MethodPost && !user.checkPassword(r.Form.Get("password")) {
+		p.renderLogin(w, params, "Wrong password for "+username)
+		return
+	}
+
+	code := randomOIDCToken()
+	now := time.Now()
+	p.mu.Lock()
+	p.codes[code] = &oidcGrant{
+		user:          user,
+		clientID:      params["client_id"],
+		redirectURI:   params["redirect_uri"],
+		scope:         params["scope"],
+		nonce:         params["nonce"],
+		challenge:     params["code_challenge"],
+		method:        params["code_challenge_method"],
+		authenticated: now,
+		expires:       now.Add(5 * time.Minute),
+	}
+	p.mu.Unlock()
+
+	q := redirectURI.Query()
+	q.Set("code", code)
+	if params["state"] != "" {
+		q.Set("state", params["state"])
+	}
+	redirectURI.RawQuery = q.Encode()
+	serveLog("%s signed in to %s", output.Accent(user.Username), params["client_id"])
+	http.Redirect(w, r, redirectURI.String(), http.StatusFound)
+}
+
+func (p *oidcProvider) renderLogin(w http.ResponseWriter, params map[string]string, message string) {
+	passwords := false
+	for _, user := range p.config.Users {
+		passwords = passwords || user.Password != ""
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if message != "" {
+		w.WriteHeader(http.StatusUnauthorized)
+	}
+	oidcLoginPage.Execute(w, map[string]interface{}{
+		"ClientID":  params["client_id"],
+		"Params":    params,
+		"Users":     p.config.Users,
+		"Passwords": passwords,
+		"Error":     message,
+	})
+}
+
+func (p *oidcProvider) token(w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+	clientID := r.Form.Get("client_id")
+	if basicID, _, ok := r.BasicAuth(); ok {
+		clientID = basicID
+	}
+
+	var grant *oidcGrant
+	switch grantType := r.Form.Get("grant_type"); grantType {
+	case "authorization_code":
+		p.mu.Lock()
+		grant = p.codes[r.Form.Get("code")]
+		delete(p.codes, r.Form.Get("code"))
+		p.mu.Unlock()
+		if grant == nil || time.Now().After(grant.expires) {
+			oidcError(w, http.StatusBadRequest, "invalid_grant", "the authorization code is invalid, expired or already used")
+			return
+		}
+		if clientID != "" && clientID != grant.clientID {
+			oidcError(w, http.StatusBadRequest, "invalid_grant", "the code was issued to another client")
+			return
+		}
+		if redirectURI := r.Form.Get("redirect_uri"); redirectURI != "" && redirectURI != grant.redirectURI {
+			oidcError(w, http.StatusBadRequest, "invalid_grant", "redirect_uri does not match the authorization request")
+			return
+		}
+		if !verifyPKCE(grant.challenge, grant.method, r.Form.Get("code_verifier")) {
+			oidcError(w, http.StatusBadRequest, "invalid_grant", "code_verifier does not match the code_challenge")
+			return
+		}
+	case "refresh_token":
+		p.mu.Lock()
+		grant = p.refreshTokens[r.Form.Get("refresh_token")]
+		p.mu.Unlock()
+		if grant == nil {
+			oidcError(w, http.StatusBadRequest, "invalid_grant", "unknown refresh token")
+			return
+		}
+	case "password":
+		user := p.findUser(r.Form.Get("username"))
+		if user == nil || !user.checkPassword(r.Form.Get("password")) {
+			oidcError(w, http.StatusBadRequest, "invalid_grant", "invalid username or password")
+			return
+		}
+		grant = &oidcGrant{user: user, clientID: clientID, scope: r.Form.Get("scope"), authenticated: time.Now()}
+	case "client_credentials":
+		if clientID == "" {
+			oidcError(w, http.StatusUnauthorized, "invalid_client", "client_id is required")
+			return
+		}
+		grant = &oidcGrant{clientID: clientID, scope: r.Form.Get("scope"), authenticated: time.Now()}
+	case "":
+		oidcError(w, http.StatusBadRequest, "invalid_request", "grant_type is required")
+		return
+	default:
+		oidcError(w, http.StatusBadRequest, "unsupported_grant_type", grantType+" is not supported")
+		return
+	}
+	if grant.clientID == "" {
+		grant.clientID = "devkit"
+	}
+
+	response, err := p.issue(grant)
+	if err != nil {
+		oidcError(w, http.StatusInternalServerError, "server_error", err.Error())
+		return
+	}
+	oidcJSON(w, http.StatusOK, response)
+}
+
+// verifyPKCE checks a code_verifier against the challenge of the
+// authorization request; requests without a challenge need no verifier
+func verifyPKCE(challenge, method, verifier string) bool {
+	if challenge == "" {
+		return true
+	}
+	if method == "S256" {
+		sum := sha256.Sum256([]byte(verifier))
+		verifier = base64.RawURLEncoding.EncodeToString(sum[:])
+	}
+	return subtle.ConstantTimeCompare([]byte(challenge), []byte(verifier)) == 1
+}
+
+// issue signs the tokens for a grant: an access token always, an ID token
+// for users with the openid scope (or no scope) and a refresh token for users
+func (p *oidcProvider) issue(grant *oidcGrant) (map[string]interface{}, error) {
+	now := time.Now()
+	base := jwt.MapClaims{
+		"iss": p.issuer,
+		"iat": now.Unix(),
+		"exp": now.Add(p.ttl).Unix(),
+	}
+
+	access := jwt.MapClaims{}
+	for k, v := range base {
+		access[k] = v
+	}
+	access["aud"] = grant.clientID
+	access["client_id"] = grant.clientID
+	access["jti"] = randomOIDCToken()
+	if grant.scope != "" {
+		access["scope"] = grant.scope
+	}
+	if grant.user == nil {
+		access["sub"] = grant.clientID
+	} else {
+		for k, v := range p.userClaims(grant.user) {
+			access[k] = v
+		}
+	}
+	accessToken, err := p.sign(access)
+	if err != nil {
+		return nil, err
+	}
+
+	response := map[string]interface{}{
+		"access_token": accessToken,
+		"token_type":   "Bearer",
+		"expires_in":   int(p.ttl.Seconds()),
+	}
+	if grant.scope != "" {
+		response["scope"] = grant.scope
+	}
+	if grant.user == nil {
+		return response, nil
+	}
+
+	if grant.scope == "" || hasOIDCScope(grant.scope, "openid") {
+		id := jwt.MapClaims{}
+		for k, v := range base {
+			id[k] = v
+		}
+		for k, v := range p.userClaims(grant.user) {
+			id[k] = v
+		}
+		id["aud"] = grant.clientID
+		id["azp"] = grant.clientID
+		id["auth_time"] = grant.authenticated.Unix()
+		if grant.nonce != "" {
+			id["nonce"] = grant.nonce
+		}
+		idToken, err := p.sign(id)
+		if err != nil {
+			return nil, err
+		}
+		response["id_token"] = idToken
+	}
+
+	refresh := randomOIDCToken()
+	p.mu.Lock()
+	p.refreshTokens[refresh] = grant
+	p.mu.Unlock()
+	response["refresh_token"] = refresh
+	return response, nil
+}
+
+// userClaims returns the shared and per-user claims, with sub defaulting
+// to the username
+func (p *oidcProvider) userClaims(user *oidcUser) map[string]interface{} {
+	claims := map[string]interface{}{
+		"sub":                user.Username,
+		"preferred_username": user.Username,
+	}
+	for k, v := range p.config.Claims {
+		claims[k] = v
+	}
+	for k, v := range user.Claims {
+		claims[k] = v
+	}
+	return claims
+}
+
+func (p *oidcProvider) sign(claims jwt.MapClaims) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = p.keyID
+	return token.SignedString(p.key)
+}
+
+// userinfo returns the claims of the user an access token was issued to
+func (p *oidcProvider) userinfo(w http.ResponseWriter, r *http.Request) {
+	raw, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		r.ParseForm()
+		raw = r.Form.Get("access_token")
+	}
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(raw, claims, func(t *jwt.Token) (interface{}, error) {
+		return &p.key.PublicKey, nil
+	}, jwt.WithValidMethods([]string{"RS256"}), jwt.WithIssuer(p.issuer))
+	if err != nil {
+		w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+		oidcError(w, http.StatusUnauthorized, "invalid_token", err.Error())
+		return
+	}
+	sub, _ := claims["sub"].(string)
+	user := p.findUser(sub)
+	if user == nil {
+		// sub may be overridden in the users file
+		for i := range p.config.Users {
+			if s, _ := p.config.Users[i].Claims["sub"].(string); s != "" && s == sub {
+				user = &p.config.Users[i]
+			}
+		}
+	}
+	if user == nil {
+		oidcError(w, http.StatusUnauthorized, "invalid_token", "the token was not issued to a user")
+		return
+	}
+	oidcJSON(w, http.StatusOK, p.userClaims(user))
+}
+
+func (p *oidcProvider) logout(w http.ResponseWriter, r *http.Request) {
+	target := r.URL.Query().Get("post_logout_redirect_uri")
+	if target == "" {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		fmt.Fprintln(w, "Signed out.")
+		return
+	}
+	if u, err := url.Parse(target); err == nil && u.IsAbs() {
+		if state := r.URL.Query().Get("state"); state != "" {
+			q := u.Query()
+			q.Set("state", state)
+			u.RawQuery = q.Encode()
+		}
+		http.Redirect(w, r, u.String(), http.StatusFound)
+		return
+	}
+	http.Error(w, "invalid post_logout_redirect_uri", http.StatusBadRequest)
+}
+
+func hasOIDCScope(scope, want string) bool {
+	for _, s := range strings.Fields(scope) {
+		if s == want {
+			return true
+		}
+	}
+	return false
+}
+
+// randomOIDCToken returns an unguessable opaque code or token
+func randomOIDCToken() string {
+	b := make([]byte, 24)
+	rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
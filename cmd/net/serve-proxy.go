@@ -0,0 +1,487 @@
+package net
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"devkit/internal/output"
+)
+
+// serveProxyCmd represents the serve proxy subcommand
+var serveProxyCmd = &cobra.Command{
+	Use:   "proxy <target>",
+	Short: "Reverse proxy that injects latency, errors and slow connections",
+	Long: `Forward requests to a target URL, optionally injecting faults, to test how
+clients cope with slow or unreliable services.
+
+Faults:
+  --latency           delay before forwarding, with optional jitter: 200ms±100ms (or 200ms+-100ms)
+  --error-rate        share of requests answered with --error-status instead: 5% or 0.05
+  --bandwidth         throttle request and response bodies: 1Mbps, 512kbps, 100KB/s
+  --drop-connections  share of requests whose connection is closed without a response
+
+--route overrides the faults for matching requests. Its value is an
+optional method and a path followed by settings, e.g.
+"POST /upload bandwidth=64kbps" or "/api/ latency=2s error-rate=20%".
+A path ending in / matches everything below it, other paths match
+exactly; the first matching route applies and unset settings keep the
+global values. Setting a fault to 0 disables it for the route.
+
+Examples:
+  devkit net serve proxy http://localhost:3000
+  devkit net serve proxy http://localhost:3000 --latency 200ms±100ms --error-rate 5%
+  devkit net serve proxy http://localhost:3000 --bandwidth 1Mbps --drop-connections 2%
+  devkit net serve proxy http://localhost:3000 --route "/api/ latency=2s" --route "GET /health error-rate=0"`,
+	Args: cobra.ExactArgs(1),
+	RunE: runServeProxy,
+}
+
+func init() {
+	serveCmd.AddCommand(serveProxyCmd)
+
+	serveProxyCmd.Flags().Int("port", 8899, "Port to listen on")
+	serveProxyCmd.Flags().String("bind", "", "Address to bind, e.g. 127.0.0.1 (default: all interfaces)")
+	serveProxyCmd.Flags().String("latency", "", "Delay before forwarding, e.g. 200ms or 200ms±100ms")
+	serveProxyCmd.Flags().String("error-rate", "", "Share of requests to fail, e.g. 5%")
+	serveProxyCmd.Flags().Int("error-status", http.StatusInternalServerError, "Status code of injected errors")
+	serveProxyCmd.Flags().String("bandwidth", "", "Bandwidth limit per request, e.g. 1Mbps or 100KB/s")
+	serveProxyCmd.Flags().String("drop-connections", "", "Share of connections to close without a response, e.g. 2%")
+	serveProxyCmd.Flags().StringArray("route", nil, `Faults for matching requests, e.g. "GET /api/ latency=2s error-rate=20%"`)
+	serveProxyCmd.Flags().Int64("seed", 0, "Seed for reproducible fault injection")
+}
+
+// proxyChaos is the set of faults injected into a request
+type proxyChaos struct {
+	latency     time.Duration
+	jitter      time.Duration
+	errorRate   float64
+	errorStatus int
+	bandwidth   int64 // bytes per second, 0 for unlimited
+	dropRate    float64
+}
+
+// proxyRoute applies its faults to requests matching method and path
+type proxyRoute struct {
+	method string
+	path   string
+	chaos  proxyChaos
+}
+
+// matches reports whether r is handled by the route, using the subtree
+// rule of http.ServeMux for paths ending in /
+func (rt *proxyRoute) matches(r *http.Request) bool {
+	if rt.method != "" && rt.method != r.Method {
+		return false
+	}
+	if strings.HasSuffix(rt.path, "/") {
+		return strings.HasPrefix(r.URL.Path, rt.path)
+	}
+	return r.URL.Path == rt.path
+}
+
+// proxyServer forwards requests to the target and injects faults
+type proxyServer struct {
+	proxy  *httputil.ReverseProxy
+	chaos  proxyChaos
+	routes []proxyRoute
+
+	mu  sync.Mutex
+	rnd *rand.Rand
+}
+
+func runServeProxy(cmd *cobra.Command, args []string) error {
+	port, _ := cmd.Flags().GetInt("port")
+	bind, _ := cmd.Flags().GetString("bind")
+	routeFlags, _ := cmd.Flags().GetStringArray("route")
+
+	if port < 1 || port > 65535 {
+		return fmt.Errorf("invalid port: %d", port)
+	}
+	target, err := url.Parse(args[0])
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return fmt.Errorf("invalid target: %s (expected an http or https URL)", args[0])
+	}
+
+	chaos := proxyChaos{}
+	for _, name := range []string{"latency", "error-rate", "error-status", "bandwidth", "drop-connections"} {
+		value := cmd.Flag(name).Value.String()
+		if value == "" {
+			continue
+		}
+		if err := chaos.set(name, value); err != nil {
+			return fmt.Errorf("invalid --%s: %w", name, err)
+		}
+	}
+
+	var routes []proxyRoute
+	for _, spec := range routeFlags {
+		route, err := parseProxyRoute(spec, chaos)
+		if err != nil {
+			return fmt.Errorf("invalid --route %q: %w", spec, err)
+		}
+		routes = append(routes, route)
+	}
+
+	seed := time.Now().UnixNano()
+	if cmd.Flags().Changed("seed") {
+		seed, _ = cmd.Flags().GetInt64("seed")
+	}
+	cmd.SilenceUsage = true
+
+	server := &proxyServer{chaos: chaos, routes: routes, rnd: rand.New(rand.NewSource(seed))}
+	server.proxy = &httputil.ReverseProxy{
+		Rewrite: func(pr *httputil.ProxyRequest) {
+			pr.SetURL(target)
+			pr.SetXForwarded()
+		},
+		FlushInterval: 100 * time.Millisecond,
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, "devkit proxy: upstream error: "+err.Error(), http.StatusBadGateway)
+		},
+	}
+
+	listener, err := net.Listen("tcp", net.JoinHostPort(bind, strconv.Itoa(port)))
+	if err != nil {
+		return fmt.Errorf("failed to listen: %w", err)
+	}
+	httpServer := &http.Server{Handler: server, ReadHeaderTimeout: 10 * time.Second}
+	defer httpServer.Close()
+	go httpServer.Serve(listener)
+
+	fmt.Printf("%-5s %s  %s\n", "PROXY", output.Accent(serveURL("http", listener.Addr())), output.Muted("(to "+target.String()+")"))
+	fmt.Printf("      Faults: %s\n", chaos)
+	for _, route := range routes {
+		fmt.Printf("      %s: %s\n", strings.TrimSpace(route.method+" "+route.path), route.chaos)
+	}
+	fmt.Println(output.Muted("Press Ctrl+C to stop"))
+	waitForInterrupt()
+	return nil
+}
+
+// parseProxyRoute parses a --route value: an optional method, a path and
+// name=value settings applied on top of the global faults
+func parseProxyRoute(spec string, chaos proxyChaos) (proxyRoute, error) {
+	route := proxyRoute{chaos: chaos}
+	for _, field := range strings.FieldsFunc(spec, func(r rune) bool { return r == ' ' || r == '\t' || r == ',' }) {
+		name, value, isSetting := strings.Cut(field, "=")
+		switch {
+		case isSetting:
+			if err := route.chaos.set(name, value); err != nil {
+				return route, fmt.Errorf("%s: %w", name, err)
+			}
+		case route.path != "":
+			return route, fmt.Errorf("unexpected %q after the path", field)
+		case strings.HasPrefix(field, "/"):
+			route.path = field
+		case route.method == "":
+			route.method = strings.ToUpper(field)
+		default:
+			return route, fmt.Errorf("path must start with /")
+		}
+	}
+	if route.path == "" {
+		return route, fmt.Errorf("path required")
+	}
+	return route, nil
+}
+
+// set changes one fault from its flag or --route setting name
+func (c *proxyChaos) set(name, value string) error {
+	var err error
+	switch name {
+	case "latency":
+		c.latency, c.jitter, err = parseProxyLatency(value)
+	case "error-rate":
+		c.errorRate, err = parseProxyRate(value)
+	case "error-status":
+		c.errorStatus, err = strconv.Atoi(value)
+		if err == nil && (c.errorStatus < 100 || c.errorStatus > 599) {
+			err = fmt.Errorf("status must be between 100 and 599")
+		}
+	case "bandwidth":
+		c.bandwidth, err = parseProxyBandwidth(value)
+	case "drop-connections":
+		c.dropRate, err = parseProxyRate(value)
+	default:
+		return fmt.Errorf("unknown setting (use latency, error-rate, error-status, bandwidth or drop-connections)")
+	}
+	return err
+}
+
+func (c proxyChaos) String() string {
+	var parts []string
+	if c.latency > 0 || c.jitter > 0 {
+		latency := "latency " + c.latency.String()
+		if c.jitter > 0 {
+			latency += "±" + c.jitter.String()
+		}
+		parts = append(parts, latency)
+	}
+	if c.errorRate > 0 {
+		parts = append(parts, fmt.Sprintf("%s errors (%d)", formatProxyRate(c.errorRate), c.errorStatus))
+	}
+	if c.bandwidth > 0 {
+		parts = append(parts, "bandwidth "+formatProxyBandwidth(c.bandwidth))
+	}
+	if c.dropRate > 0 {
+		parts = append(parts, formatProxyRate(c.dropRate)+" dropped")
+	}
+	if len(parts) == 0 {
+		return "none"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// parseProxyLatency parses a duration with optional ±jitter
+func parseProxyLatency(s string) (time.Duration, time.Duration, error) {
+	base, jitterStr, hasJitter := strings.Cut(s, "±")
+	if !hasJitter {
+		base, jitterStr, hasJitter = strings.Cut(s, "+-")
+	}
+	latency, err := parseProxyDuration(base)
+	if err != nil {
+		return 0, 0, err
+	}
+	var jitter time.Duration
+	if hasJitter {
+		if jitter, err = parseProxyDuration(jitterStr); err != nil {
+			return 0, 0, err
+		}
+	}
+	return latency, jitter, nil
+}
+
+func parseProxyDuration(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "0" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration: %s", s)
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("duration must not be negative: %s", s)
+	}
+	return d, nil
+}
+
+// parseProxyRate parses a percentage such as 5% or a fraction such as 0.05
+func parseProxyRate(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	percent := strings.HasSuffix(s, "%")
+	v, err := strconv.ParseFloat(strings.TrimSuffix(s, "%"), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid rate: %s (use a percentage like 5%% or a fraction like 0.05)", s)
+	}
+	if percent {
+		v /= 100
+	}
+	if v < 0 || v > 1 {
+		return 0, fmt.Errorf("rate must be between 0%% and 100%%: %s", s)
+	}
+	return v, nil
+}
+
+func formatProxyRate(v float64) string {
+	return strconv.FormatFloat(v*100, 'f', -1, 64) + "%"
+}
+
+// proxyBandwidthUnits maps unit prefixes to multipliers; units ending in bps
+// or b/s count bits, Bps or B/s bytes
+var proxyBandwidthUnits = map[string]float64{"": 1, "k": 1e3, "m": 1e6, "g": 1e9}
+
+// parseProxyBandwidth parses a rate such as 1Mbps or 100KB/s into bytes per second
+func parseProxyBandwidth(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	if s == "0" {
+		return 0, nil
+	}
+	var unit string
+	var bits bool
+	for _, suffix := range []string{"bps", "b/s", "Bps", "B/s"} {
+		if strings.HasSuffix(s, suffix) {
+			unit, bits = suffix, suffix[0] == 'b'
+			break
+		}
+	}
+	if unit == "" {
+		return 0, fmt.Errorf("invalid bandwidth: %s (use a unit like 512kbps, 1Mbps or 100KB/s)", s)
+	}
+	number := strings.TrimSuffix(s, unit)
+	prefix := ""
+	if n := len(number); n > 0 && strings.ContainsAny(number[n-1:], "kKmMgG") {
+		number, prefix = number[:n-1], strings.ToLower(number[n-1:])
+	}
+	v, err := strconv.ParseFloat(number, 64)
+	if err != nil || v < 0 {
+		return 0, fmt.Errorf("invalid bandwidth: %s", s)
+	}
+	v *= proxyBandwidthUnits[prefix]
+	if bits {
+		v /= 8
+	}
+	if v > 0 && v < 1 {
+		return 0, fmt.Errorf("bandwidth too low: %s", s)
+	}
+	return int64(v), nil
+}
+
+func formatProxyBandwidth(bytesPerSecond int64) string {
+	bits := float64(bytesPerSecond) * 8
+	switch {
+	case bits >= 1e9:
+		return strconv.FormatFloat(bits/1e9, 'f', -1, 64) + "Gbps"
+	case bits >= 1e6:
+		return strconv.FormatFloat(bits/1e6, 'f', -1, 64) + "Mbps"
+	case bits >= 1e3:
+		return strconv.FormatFloat(bits/1e3, 'f', -1, 64) + "kbps"
+	}
+	return strconv.FormatFloat(bits, 'f', -1, 64) + "bps"
+}
+
+func (s *proxyServer) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	chaos := s.chaos
+	for i := range s.routes {
+		if s.routes[i].matches(r) {
+			chaos = s.routes[i].chaos
+			break
+		}
+	}
+
+	// Draw every decision up front so that --seed gives the same sequence
+	// regardless of how long requests take
+	s.mu.Lock()
+	delay := chaos.latency
+	if chaos.jitter > 0 {
+		delay += time.Duration(s.rnd.Int63n(int64(2*chaos.jitter)+1)) - chaos.jitter
+	}
+	drop := chaos.dropRate > 0 && s.rnd.Float64() < chaos.dropRate
+	fail := chaos.errorRate > 0 && s.rnd.Float64() < chaos.errorRate
+	s.mu.Unlock()
+
+	var notes []string
+	if delay > 0 {
+		notes = append(notes, "+"+delay.Round(time.Millisecond).String())
+		select {
+		case <-time.After(delay):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	if drop {
+		proxyLog(r, output.Failure("dropped"), notes)
+		// Aborting the handler closes the connection without a response
+		panic(http.ErrAbortHandler)
+	}
+
+	w := &serveStatusWriter{ResponseWriter: rw, status: http.StatusOK}
+	if fail {
+		notes = append(notes, "injected")
+		w.Header().Set("X-Devkit-Chaos", "error")
+		http.Error(w, fmt.Sprintf("devkit proxy: injected %d %s", chaos.errorStatus, http.StatusText(chaos.errorStatus)), chaos.errorStatus)
+		proxyLog(r, output.Failure(strconv.Itoa(w.status)), notes)
+		return
+	}
+
+	if chaos.bandwidth > 0 {
+		notes = append(notes, formatProxyBandwidth(chaos.bandwidth))
+		if r.Body != nil && r.Body != http.NoBody {
+			r.Body = newProxyThrottle(r.Body, chaos.bandwidth)
+		}
+		w.ResponseWriter = &proxyThrottleWriter{ResponseWriter: rw, rate: chaos.bandwidth, start: time.Now()}
+	}
+
+	start := time.Now()
+	s.proxy.ServeHTTP(w, r)
+	notes = append(notes, time.Since(start).Round(time.Millisecond).String())
+
+	status := strconv.Itoa(w.status)
+	if w.status >= 400 {
+		status = output.Failure(status)
+	}
+	proxyLog(r, status, notes)
+}
+
+// proxyLog logs a proxied request with the faults applied to it
+func proxyLog(r *http.Request, status string, notes []string) {
+	serveLog("%-6s %s %s  %s", r.Method, r.URL.RequestURI(), status, output.Muted(strings.Join(notes, " ")))
+}
+
+// proxyThrottle limits reads from a request body to rate bytes per second
+type proxyThrottle struct {
+	io.ReadCloser
+	rate  int64
+	start time.Time
+	done  int64
+}
+
+func newProxyThrottle(body io.ReadCloser, rate int64) *proxyThrottle {
+	return &proxyThrottle{ReadCloser: body, rate: rate, start: time.Now()}
+}
+
+func (t *proxyThrottle) Read(p []byte) (int, error) {
+	if chunk := proxyThrottleChunk(t.rate); len(p) > chunk {
+		p = p[:chunk]
+	}
+	n, err := t.ReadCloser.Read(p)
+	t.done += int64(n)
+	proxyThrottleWait(t.start, t.done, t.rate)
+	return n, err
+}
+
+// proxyThrottleWriter limits writes of a response body to rate bytes per second
+type proxyThrottleWriter struct {
+	http.ResponseWriter
+	rate  int64
+	start time.Time
+	done  int64
+}
+
+func (w *proxyThrottleWriter) Write(p []byte) (int, error) {
+	written := 0
+	chunk := proxyThrottleChunk(w.rate)
+	for len(p) > 0 {
+		n := min(len(p), chunk)
+		n, err := w.ResponseWriter.Write(p[:n])
+		written += n
+		w.done += int64(n)
+		if err != nil {
+			return written, err
+		}
+		// Flush each chunk so the client sees the body trickle in
+		http.NewResponseController(w.ResponseWriter).Flush()
+		proxyThrottleWait(w.start, w.done, w.rate)
+		p = p[n:]
+	}
+	return written, nil
+}
+
+func (w *proxyThrottleWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// proxyThrottleChunk is the amount transferred at a time: a tenth of a
+// second's worth, so the rate stays smooth
+func proxyThrottleChunk(rate int64) int {
+	return int(max(rate/10, 1))
+}
+
+// proxyThrottleWait sleeps until done bytes are due at rate since start
+func proxyThrottleWait(start time.Time, done, rate int64) {
+	due := start.Add(time.Duration(float64(done) / float64(rate) * float64(time.Second)))
+	if wait := time.Until(due); wait > 0 {
+		time.Sleep(wait)
+	}
+}
@@ -0,0 +1,1250 @@
+package net
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"devkit/internal/output"
+)
+
+// serveS3Cmd represents the serve s3 subcommand
+var serveS3Cmd = &cobra.Command{
+	Use:   "s3",
+	Short: "Fake S3 object storage backed by a local directory",
+	Long: `Serve enough of the S3 API for local integration tests, storing buckets as
+directories of --dir and objects as plain files, so test data can be seeded
+and inspected with ordinary tools.
+
+Supported: list, create, delete and head buckets; put, get (with ranges),
+head, copy and delete objects; DeleteObjects; ListObjects v1 and v2 with
+prefix, delimiter and paging; multipart uploads; presigned URLs and
+aws-chunked uploads. Other operations answer NotImplemented.
+
+Clients must use path-style addressing (http://localhost:9001/bucket/key),
+e.g. forcePathStyle in the JavaScript SDK, UsePathStyle in the Go SDK or
+addressing_style = path in boto3.
+
+Without --access-key any credentials are accepted; presigned URLs still
+expire. With --access-key and --secret-key, AWS Signature V4 is checked for
+signed requests and presigned URLs, and unsigned requests are refused.
+
+Examples:
+  devkit net serve s3
+  devkit net serve s3 --port 9001 --dir ./s3data --bucket uploads --bucket assets
+  devkit net serve s3 --access-key test --secret-key testsecret
+  AWS_ENDPOINT_URL=http://localhost:9001 aws s3 ls s3://uploads`,
+	Args: cobra.NoArgs,
+	RunE: runServeS3,
+}
+
+func init() {
+	serveCmd.AddCommand(serveS3Cmd)
+
+	serveS3Cmd.Flags().Int("port", 9001, "Port to listen on")
+	serveS3Cmd.Flags().String("bind", "", "Address to bind, e.g. 127.0.0.1 (default: all interfaces)")
+	serveS3Cmd.Flags().String("dir", "./s3data", "Data directory; each subdirectory is a bucket")
+	serveS3Cmd.Flags().StringSlice("bucket", nil, "Buckets to create on startup")
+	serveS3Cmd.Flags().String("region", "us-east-1", "Region reported to clients")
+	serveS3Cmd.Flags().String("access-key", "", "Access key to require (default: accept any credentials)")
+	serveS3Cmd.Flags().String("secret-key", "", "Secret key used to check signatures with --access-key")
+}
+
+// s3MetaDir holds object metadata and multipart uploads inside --dir; names
+// starting with a dot are never buckets
+const s3MetaDir = ".devkit-s3"
+
+const s3XMLNS = "http://s3.amazonaws.com/doc/2006-03-01/"
+
+var s3BucketName = regexp.MustCompile(`^[a-z0-9][a-z0-9.-]{1,61}[a-z0-9]$`)
+
+// s3Subresources are query parameters selecting operations this server
+// does not implement
+var s3Subresources = []string{
+	"acl", "analytics", "cors", "encryption", "intelligent-tiering", "inventory",
+	"legal-hold", "lifecycle", "logging", "metrics", "notification", "object-lock",
+	"ownershipControls", "policy", "policyStatus", "publicAccessBlock", "replication",
+	"requestPayment", "restore", "retention", "select", "tagging", "torrent",
+	"versioning", "versions", "website", "attributes",
+}
+
+// s3ResponseOverrides are the presigned GET parameters that set response headers
+var s3ResponseOverrides = map[string]string{
+	"response-content-type":        "Content-Type",
+	"response-content-language":    "Content-Language",
+	"response-expires":             "Expires",
+	"response-cache-control":       "Cache-Control",
+	"response-content-disposition": "Content-Disposition",
+	"response-content-encoding":    "Content-Encoding",
+}
+
+// s3StoredHeaders are the request headers kept with an object and returned on GET
+var s3StoredHeaders = []string{"Content-Type", "Cache-Control", "Content-Disposition", "Content-Encoding", "Content-Language", "Expires"}
+
+// s3Error is an S3 error response
+type s3Error struct {
+	XMLName  xml.Name `xml:"Error"`
+	Code     string   `xml:"Code"`
+	Message  string   `xml:"Message"`
+	Resource string   `xml:"Resource,omitempty"`
+	status   int
+}
+
+func (e *s3Error) Error() string { return e.Code + ": " + e.Message }
+
+func newS3Error(status int, code, message string) *s3Error {
+	return &s3Error{Code: code, Message: message, status: status}
+}
+
+// s3Object is the metadata kept for an object
+type s3Object struct {
+	ETag     string            `json:"etag"`
+	Headers  map[string]string `json:"headers,omitempty"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// s3Upload is an in-progress multipart upload
+type s3Upload struct {
+	Bucket string   `json:"bucket"`
+	Key    string   `json:"key"`
+	Object s3Object `json:"object"`
+}
+
+// s3Server serves the S3 API from a directory
+type s3Server struct {
+	dir       string
+	region    string
+	accessKey string
+	secretKey string
+	mu        sync.Mutex
+}
+
+func runServeS3(cmd *cobra.Command, args []string) error {
+	port, _ := cmd.Flags().GetInt("port")
+	bind, _ := cmd.Flags().GetString("bind")
+	dir, _ := cmd.Flags().GetString("dir")
+	buckets, _ := cmd.Flags().GetStringSlice("bucket")
+	region, _ := cmd.Flags().GetString("region")
+	accessKey, _ := cmd.Flags().GetString("access-key")
+	secretKey, _ := cmd.Flags().GetString("secret-key")
+
+	if port < 1 || port > 65535 {
+		return fmt.Errorf("invalid port: %d", port)
+	}
+	if (accessKey == "") != (secretKey == "") {
+		return fmt.Errorf("--access-key and --secret-key must be given together")
+	}
+	for _, bucket := range buckets {
+		if !s3BucketName.MatchString(bucket) {
+			return fmt.Errorf("invalid bucket name: %s (3-63 lowercase letters, digits, dots and hyphens)", bucket)
+		}
+	}
+	cmd.SilenceUsage = true
+
+	server := &s3Server{dir: dir, region: region, accessKey: accessKey, secretKey: secretKey}
+	for _, sub := range []string{"meta", "uploads", "tmp"} {
+		if err := os.MkdirAll(filepath.Join(dir, s3MetaDir, sub), 0755); err != nil {
+			return fmt.Errorf("failed to create data directory: %w", err)
+		}
+	}
+	for _, bucket := range buckets {
+		if err := os.MkdirAll(filepath.Join(dir, bucket), 0755); err != nil {
+			return fmt.Errorf("failed to create bucket %s: %w", bucket, err)
+		}
+	}
+
+	listener, err := net.Listen("tcp", net.JoinHostPort(bind, strconv.Itoa(port)))
+	if err != nil {
+		return fmt.Errorf("failed to listen: %w", err)
+	}
+	httpServer := &http.Server{Handler: server, ReadHeaderTimeout: 10 * time.Second}
+	defer httpServer.Close()
+	go httpServer.Serve(listener)
+
+	endpoint := serveURL("http", listener.Addr())
+	fmt.Printf("%-5s %s  %s\n", "S3", output.Accent(endpoint), output.Muted("(data in "+dir+")"))
+	if accessKey != "" {
+		fmt.Printf("      Signatures checked for access key %s, region %s\n", accessKey, region)
+	} else {
+		fmt.Printf("      Any credentials accepted, region %s\n", region)
+	}
+	fmt.Println(output.Muted("Use path-style addressing, e.g. AWS_ENDPOINT_URL=" + endpoint))
+	fmt.Println(output.Muted("Press Ctrl+C to stop"))
+	waitForInterrupt()
+	return nil
+}
+
+func (s *s3Server) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	w := &serveStatusWriter{ResponseWriter: rw, status: http.StatusOK}
+	defer serveLogRequest(r, w.status)
+
+	requestID := make([]byte, 8)
+	rand.Read(requestID)
+	w.Header().Set("x-amz-request-id", strings.ToUpper(hex.EncodeToString(requestID)))
+	w.Header().Set("Server", "devkit-s3")
+	// Browsers upload to presigned URLs, so allow any origin
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Expose-Headers", "ETag, x-amz-request-id, x-amz-version-id")
+	if r.Method == http.MethodOptions {
+		w.Header().Set("Access-Control-Allow-Methods", "GET, PUT, POST, DELETE, HEAD")
+		if headers := r.Header.Get("Access-Control-Request-Headers"); headers != "" {
+			w.Header().Set("Access-Control-Allow-Headers", headers)
+		}
+		w.Header().Set("Access-Control-Max-Age", "3600")
+		return
+	}
+
+	if err := s.serve(w, r); err != nil {
+		var s3err *s3Error
+		if !errors.As(err, &s3err) {
+			s3err = newS3Error(http.StatusInternalServerError, "InternalError", err.Error())
+		}
+		s3err.Resource = r.URL.Path
+		w.Header().Set("Content-Type", "application/xml")
+		w.WriteHeader(s3err.status)
+		if r.Method != http.MethodHead {
+			writeS3XML(w, s3err)
+		}
+	}
+}
+
+func (s *s3Server) serve(w http.ResponseWriter, r *http.Request) error {
+	if err := s.authenticate(r); err != nil {
+		return err
+	}
+	query := r.URL.Query()
+	for _, sub := range s3Subresources {
+		if query.Has(sub) {
+			return newS3Error(http.StatusNotImplemented, "NotImplemented", "?"+sub+" is not supported by this server")
+		}
+	}
+
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	if bucket == "" {
+		if r.Method != http.MethodGet {
+			return newS3Error(http.StatusMethodNotAllowed, "MethodNotAllowed", "the method is not allowed against this resource")
+		}
+		return s.listBuckets(w)
+	}
+	if strings.HasPrefix(bucket, ".") {
+		return newS3Error(http.StatusNotFound, "NoSuchBucket", "the specified bucket does not exist")
+	}
+
+	if key == "" {
+		switch {
+		case r.Method == http.MethodPut:
+			return s.createBucket(w, bucket)
+		case r.Method == http.MethodDelete:
+			return s.deleteBucket(w, bucket)
+		case r.Method == http.MethodHead:
+			return s.requireBucket(bucket)
+		case r.Method == http.MethodGet && query.Has("location"):
+			return s.bucketLocation(w, bucket)
+		case r.Method == http.MethodGet && query.Has("uploads"):
+			return newS3Error(http.StatusNotImplemented, "NotImplemented", "listing multipart uploads is not supported by this server")
+		case r.Method == http.MethodGet:
+			return s.listObjects(w, r, bucket)
+		case r.Method == http.MethodPost && query.Has("delete"):
+			return s.deleteObjects(w, r, bucket)
+		}
+		return newS3Error(http.StatusMethodNotAllowed, "MethodNotAllowed", "the method is not allowed against this resource")
+	}
+
+	if err := checkS3Key(key); err != nil {
+		return err
+	}
+	switch {
+	case r.Method == http.MethodPut && query.Has("uploadId"):
+		return s.uploadPart(w, r, bucket, key)
+	case r.Method == http.MethodPut && r.Header.Get("x-amz-copy-source") != "":
+		return s.copyObject(w, r, bucket, key)
+	case r.Method == http.MethodPut:
+		return s.putObject(w, r, bucket, key)
+	case (r.Method == http.MethodGet || r.Method == http.MethodHead) && query.Has("uploadId"):
+		return newS3Error(http.StatusNotImplemented, "NotImplemented", "listing parts is not supported by this server")
+	case r.Method == http.MethodGet || r.Method == http.MethodHead:
+		return s.getObject(w, r, bucket, key)
+	case r.Method == http.MethodDelete && query.Has("uploadId"):
+		return s.abortUpload(w, query.Get("uploadId"))
+	case r.Method == http.MethodDelete:
+		return s.deleteObject(w, bucket, key)
+	case r.Method == http.MethodPost && query.Has("uploads"):
+		return s.createUpload(w, r, bucket, key)
+	case r.Method == http.MethodPost && query.Has("uploadId"):
+		return s.completeUpload(w, r, bucket, key)
+	}
+	return newS3Error(http.StatusMethodNotAllowed, "MethodNotAllowed", "the method is not allowed against this resource")
+}
+
+// checkS3Key refuses keys that would not map onto a file inside the bucket
+func checkS3Key(key string) error {
+	if len(key) > 1024 {
+		return newS3Error(http.StatusBadRequest, "KeyTooLongError", "the key is longer than 1024 bytes")
+	}
+	trimmed := strings.TrimSuffix(key, "/")
+	if trimmed == "" || path.Clean(trimmed) != trimmed || strings.HasPrefix(trimmed, "../") || trimmed == ".." ||
+		strings.ContainsRune(key, 0) || strings.Contains(key, "\\") {
+		return newS3Error(http.StatusBadRequest, "InvalidArgument", "keys with empty, . or .. segments cannot be stored in a directory")
+	}
+	return nil
+}
+
+func writeS3XML(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/xml")
+	io.WriteString(w, xml.Header)
+	xml.NewEncoder(w).Encode(v)
+}
+
+func s3Time(t time.Time) string {
+	return t.UTC().Format("2006-01-02T15:04:05.000Z")
+}
+
+func (s *s3Server) bucketPath(bucket string) string {
+	return filepath.Join(s.dir, bucket)
+}
+
+func (s *s3Server) objectPath(bucket, key string) string {
+	return filepath.Join(s.dir, bucket, filepath.FromSlash(key))
+}
+
+// metaPath is where the metadata of an object is kept; keys are hashed so
+// that any key maps to one flat file
+func (s *s3Server) metaPath(bucket, key string) string {
+	sum := sha1.Sum([]byte(key))
+	return filepath.Join(s.dir, s3MetaDir, "meta", bucket, hex.EncodeToString(sum[:])+".json")
+}
+
+func (s *s3Server) requireBucket(bucket string) error {
+	info, err := os.Stat(s.bucketPath(bucket))
+	if err != nil || !info.IsDir() {
+		return newS3Error(http.StatusNotFound, "NoSuchBucket", "the specified bucket does not exist")
+	}
+	return nil
+}
+
+type s3ListAllMyBucketsResult struct {
+	XMLName xml.Name `xml:"ListAllMyBucketsResult"`
+	XMLNS   string   `xml:"xmlns,attr"`
+	Owner   struct {
+		ID          string `xml:"ID"`
+		DisplayName string `xml:"DisplayName"`
+	} `xml:"Owner"`
+	Buckets []s3BucketEntry `xml:"Buckets>Bucket"`
+}
+
+type s3BucketEntry struct {
+	Name         string `xml:"Name"`
+	CreationDate string `xml:"CreationDate"`
+}
+
+func (s *s3Server) listBuckets(w http.ResponseWriter) error {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return err
+	}
+	result := s3ListAllMyBucketsResult{XMLNS: s3XMLNS}
+	result.Owner.ID, result.Owner.DisplayName = "devkit", "devkit"
+	for _, entry := range entries {
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		result.Buckets = append(result.Buckets, s3BucketEntry{Name: entry.Name(), CreationDate: s3Time(info.ModTime())})
+	}
+	writeS3XML(w, result)
+	return nil
+}
+
+func (s *s3Server) createBucket(w http.ResponseWriter, bucket string) error {
+	if !s3BucketName.MatchString(bucket) {
+		return newS3Error(http.StatusBadRequest, "InvalidBucketName", "the specified bucket is not valid")
+	}
+	if err := os.MkdirAll(s.bucketPath(bucket), 0755); err != nil {
+		return err
+	}
+	w.Header().Set("Location", "/"+bucket)
+	return nil
+}
+
+func (s *s3Server) deleteBucket(w http.ResponseWriter, bucket string) error {
+	if err := s.requireBucket(bucket); err != nil {
+		return err
+	}
+	empty := true
+	filepath.WalkDir(s.bucketPath(bucket), func(p string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			empty = false
+			return filepath.SkipAll
+		}
+		return nil
+	})
+	if !empty {
+		return newS3Error(http.StatusConflict, "BucketNotEmpty", "the bucket you tried to delete is not empty")
+	}
+	if err := os.RemoveAll(s.bucketPath(bucket)); err != nil {
+		return err
+	}
+	os.RemoveAll(filepath.Join(s.dir, s3MetaDir, "meta", bucket))
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+func (s *s3Server) bucketLocation(w http.ResponseWriter, bucket string) error {
+	if err := s.requireBucket(bucket); err != nil {
+		return err
+	}
+	// us-east-1 is reported as an empty constraint, as S3 does
+	location := s.region
+	if location == "us-east-1" {
+		location = ""
+	}
+	writeS3XML(w, struct {
+		XMLName  xml.Name `xml:"LocationConstraint"`
+		XMLNS    string   `xml:"xmlns,attr"`
+		Location string   `xml:",chardata"`
+	}{XMLNS: s3XMLNS, Location: location})
+	return nil
+}
+
+// s3ObjectInfo describes a stored object
+type s3ObjectInfo struct {
+	key      string
+	size     int64
+	modified time.Time
+	meta     s3Object
+}
+
+// stat returns an object and its metadata, computing the ETag of files
+// placed in the directory by hand
+func (s *s3Server) stat(bucket, key string) (*s3ObjectInfo, error) {
+	info, err := os.Stat(s.objectPath(bucket, key))
+	if err != nil || info.IsDir() != strings.HasSuffix(key, "/") {
+		if err := s.requireBucket(bucket); err != nil {
+			return nil, err
+		}
+		return nil, newS3Error(http.StatusNotFound, "NoSuchKey", "the specified key does not exist")
+	}
+	obj := &s3ObjectInfo{key: key, modified: info.ModTime()}
+	if !info.IsDir() {
+		obj.size = info.Size()
+	}
+	if data, err := os.ReadFile(s.metaPath(bucket, key)); err == nil {
+		json.Unmarshal(data, &obj.meta)
+	}
+	if obj.meta.ETag == "" {
+		obj.meta.ETag = `"d41d8cd98f00b204e9800998ecf8427e"`
+		if !info.IsDir() {
+			if f, err := os.Open(s.objectPath(bucket, key)); err == nil {
+				h := md5.New()
+				io.Copy(h, f)
+				f.Close()
+				obj.meta.ETag = `"` + hex.EncodeToString(h.Sum(nil)) + `"`
+			}
+		}
+	}
+	return obj, nil
+}
+
+// store moves the temporary file tmp into place as the object and writes
+// its metadata. Keys ending in / are folder markers kept as directories.
+func (s *s3Server) store(bucket, key, tmp string, meta s3Object) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.requireBucket(bucket); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	target := s.objectPath(bucket, key)
+	conflict := newS3Error(http.StatusConflict, "InvalidArgument", "the key conflicts with an existing object or prefix in the data directory")
+	if strings.HasSuffix(key, "/") {
+		os.Remove(tmp)
+		if info, err := os.Stat(target); err == nil && !info.IsDir() {
+			return conflict
+		}
+		if err := os.MkdirAll(target, 0755); err != nil {
+			return conflict
+		}
+	} else {
+		if info, err := os.Stat(target); err == nil && info.IsDir() {
+			os.Remove(tmp)
+			return conflict
+		}
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			os.Remove(tmp)
+			return conflict
+		}
+		if err := os.Rename(tmp, target); err != nil {
+			os.Remove(tmp)
+			return err
+		}
+	}
+
+	metaPath := s.metaPath(bucket, key)
+	if err := os.MkdirAll(filepath.Dir(metaPath), 0755); err != nil {
+		return err
+	}
+	data, _ := json.Marshal(meta)
+	return os.WriteFile(metaPath, data, 0644)
+}
+
+// writeTemp copies body into a temporary file in the data directory and
+// returns its path and quoted MD5 ETag
+func (s *s3Server) writeTemp(body io.Reader) (string, string, error) {
+	f, err := os.CreateTemp(filepath.Join(s.dir, s3MetaDir, "tmp"), "object-")
+	if err != nil {
+		return "", "", err
+	}
+	defer f.Close()
+	h := md5.New()
+	if _, err := io.Copy(io.MultiWriter(f, h), body); err != nil {
+		os.Remove(f.Name())
+		return "", "", newS3Error(http.StatusBadRequest, "IncompleteBody", "failed to read the request body: "+err.Error())
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return "", "", err
+	}
+	return f.Name(), `"` + hex.EncodeToString(h.Sum(nil)) + `"`, nil
+}
+
+// requestBody returns the request body, decoding aws-chunked uploads
+func requestBody(r *http.Request) io.Reader {
+	if strings.HasPrefix(r.Header.Get("x-amz-content-sha256"), "STREAMING-") ||
+		strings.Contains(r.Header.Get("Content-Encoding"), "aws-chunked") {
+		return &awsChunkedReader{r: bufio.NewReader(r.Body)}
+	}
+	return r.Body
+}
+
+// objectMeta collects the stored headers and x-amz-meta-* metadata of a request
+func objectMeta(r *http.Request) s3Object {
+	meta := s3Object{Headers: map[string]string{}, Metadata: map[string]string{}}
+	for _, name := range s3StoredHeaders {
+		if value := r.Header.Get(name); value != "" {
+			meta.Headers[name] = value
+		}
+	}
+	// aws-chunked is the transfer encoding of the upload, not of the object
+	if encoding, ok := meta.Headers["Content-Encoding"]; ok {
+		var kept []string
+		for _, e := range strings.Split(encoding, ",") {
+			if e = strings.TrimSpace(e); e != "" && e != "aws-chunked" {
+				kept = append(kept, e)
+			}
+		}
+		if len(kept) == 0 {
+			delete(meta.Headers, "Content-Encoding")
+		} else {
+			meta.Headers["Content-Encoding"] = strings.Join(kept, ", ")
+		}
+	}
+	for name, values := range r.Header {
+		if lower := strings.ToLower(name); strings.HasPrefix(lower, "x-amz-meta-") {
+			meta.Metadata[strings.TrimPrefix(lower, "x-amz-meta-")] = strings.Join(values, ",")
+		}
+	}
+	return meta
+}
+
+func (s *s3Server) putObject(w http.ResponseWriter, r *http.Request, bucket, key string) error {
+	if err := s.requireBucket(bucket); err != nil {
+		return err
+	}
+	tmp, etag, err := s.writeTemp(requestBody(r))
+	if err != nil {
+		return err
+	}
+	meta := objectMeta(r)
+	meta.ETag = etag
+	if err := s.store(bucket, key, tmp, meta); err != nil {
+		return err
+	}
+	w.Header().Set("ETag", etag)
+	return nil
+}
+
+func (s *s3Server) getObject(w http.ResponseWriter, r *http.Request, bucket, key string) error {
+	obj, err := s.stat(bucket, key)
+	if err != nil {
+		return err
+	}
+	header := w.Header()
+	header.Set("Content-Type", "binary/octet-stream")
+	for name, value := range obj.meta.Headers {
+		header.Set(name, value)
+	}
+	for name, value := range obj.meta.Metadata {
+		header["x-amz-meta-"+name] = []string{value}
+	}
+	query := r.URL.Query()
+	for param, name := range s3ResponseOverrides {
+		if value := query.Get(param); value != "" {
+			header.Set(name, value)
+		}
+	}
+	header.Set("ETag", obj.meta.ETag)
+	header.Set("Accept-Ranges", "bytes")
+
+	if strings.HasSuffix(key, "/") {
+		http.ServeContent(w, r, "", obj.modified, bytes.NewReader(nil))
+		return nil
+	}
+	f, err := os.Open(s.objectPath(bucket, key))
+	if err != nil {
+		return newS3Error(http.StatusNotFound, "NoSuchKey", "the specified key does not exist")
+	}
+	defer f.Close()
+	http.ServeContent(w, r, "", obj.modified, f)
+	return nil
+}
+
+func (s *s3Server) deleteObject(w http.ResponseWriter, bucket, key string) error {
+	if err := s.requireBucket(bucket); err != nil {
+		return err
+	}
+	s.remove(bucket, key)
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// remove deletes an object and its metadata, then the directories it
+// leaves empty; missing objects are not an error, as in S3
+func (s *s3Server) remove(bucket, key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	target := s.objectPath(bucket, key)
+	if info, err := os.Stat(target); err == nil && info.IsDir() == strings.HasSuffix(key, "/") {
+		os.Remove(target)
+	}
+	os.Remove(s.metaPath(bucket, key))
+	root := s.bucketPath(bucket)
+	for dir := filepath.Dir(target); dir != root && strings.HasPrefix(dir, root); dir = filepath.Dir(dir) {
+		if os.Remove(dir) != nil {
+			break
+		}
+	}
+}
+
+type s3DeleteRequest struct {
+	Quiet   bool `xml:"Quiet"`
+	Objects []struct {
+		Key string `xml:"Key"`
+	} `xml:"Object"`
+}
+
+type s3DeleteResult struct {
+	XMLName xml.Name `xml:"DeleteResult"`
+	XMLNS   string   `xml:"xmlns,attr"`
+	Deleted []struct {
+		Key string `xml:"Key"`
+	} `xml:"Deleted"`
+	Errors []struct {
+		Key     string `xml:"Key"`
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	} `xml:"Error"`
+}
+
+func (s *s3Server) deleteObjects(w http.ResponseWriter, r *http.Request, bucket string) error {
+	if err := s.requireBucket(bucket); err != nil {
+		return err
+	}
+	var request s3DeleteRequest
+	if err := xml.NewDecoder(requestBody(r)).Decode(&request); err != nil {
+		return newS3Error(http.StatusBadRequest, "MalformedXML", "the XML you provided was not well-formed")
+	}
+	result := s3DeleteResult{XMLNS: s3XMLNS}
+	for _, object := range request.Objects {
+		if err := checkS3Key(object.Key); err != nil {
+			var s3err *s3Error
+			errors.As(err, &s3err)
+			result.Errors = append(result.Errors, struct {
+				Key     string `xml:"Key"`
+				Code    string `xml:"Code"`
+				Message string `xml:"Message"`
+			}{object.Key, s3err.Code, s3err.Message})
+			continue
+		}
+		s.remove(bucket, object.Key)
+		if !request.Quiet {
+			result.Deleted = append(result.Deleted, struct {
+				Key string `xml:"Key"`
+			}{object.Key})
+		}
+	}
+	writeS3XML(w, result)
+	return nil
+}
+
+type s3CopyObjectResult struct {
+	XMLName      xml.Name `xml:"CopyObjectResult"`
+	XMLNS        string   `xml:"xmlns,attr"`
+	LastModified string   `xml:"LastModified"`
+	ETag         string   `xml:"ETag"`
+}
+
+func (s *s3Server) copyObject(w http.ResponseWriter, r *http.Request, bucket, key string) error {
+	source, _, _ := strings.Cut(r.Header.Get("x-amz-copy-source"), "?")
+	if unescaped, err := url.PathUnescape(source); err == nil {
+		source = unescaped
+	}
+	srcBucket, srcKey, _ := strings.Cut(strings.TrimPrefix(source, "/"), "/")
+	if err := checkS3Key(srcKey); err != nil {
+		return err
+	}
+	src, err := s.stat(srcBucket, srcKey)
+	if err != nil {
+		return err
+	}
+	if err := s.requireBucket(bucket); err != nil {
+		return err
+	}
+
+	in, err := os.Open(s.objectPath(srcBucket, srcKey))
+	if err != nil {
+		return newS3Error(http.StatusNotFound, "NoSuchKey", "the specified key does not exist")
+	}
+	tmp, etag, err := s.writeTemp(in)
+	in.Close()
+	if err != nil {
+		return err
+	}
+	meta := src.meta
+	if strings.EqualFold(r.Header.Get("x-amz-metadata-directive"), "REPLACE") {
+		meta = objectMeta(r)
+	}
+	meta.ETag = etag
+	if err := s.store(bucket, key, tmp, meta); err != nil {
+		return err
+	}
+	writeS3XML(w, s3CopyObjectResult{XMLNS: s3XMLNS, LastModified: s3Time(time.Now()), ETag: etag})
+	return nil
+}
+
+type s3ListBucketResult struct {
+	XMLName               xml.Name        `xml:"ListBucketResult"`
+	XMLNS                 string          `xml:"xmlns,attr"`
+	Name                  string          `xml:"Name"`
+	Prefix                string          `xml:"Prefix"`
+	Delimiter             string          `xml:"Delimiter,omitempty"`
+	Marker                *string         `xml:"Marker"`
+	NextMarker            string          `xml:"NextMarker,omitempty"`
+	StartAfter            string          `xml:"StartAfter,omitempty"`
+	ContinuationToken     string          `xml:"ContinuationToken,omitempty"`
+	NextContinuationToken string          `xml:"NextContinuationToken,omitempty"`
+	KeyCount              *int            `xml:"KeyCount"`
+	MaxKeys               int             `xml:"MaxKeys"`
+	EncodingType          string          `xml:"EncodingType,omitempty"`
+	IsTruncated           bool            `xml:"IsTruncated"`
+	Contents              []s3ListEntry   `xml:"Contents"`
+	CommonPrefixes        []s3CommonEntry `xml:"CommonPrefixes"`
+}
+
+type s3ListEntry struct {
+	Key          string `xml:"Key"`
+	LastModified string `xml:"LastModified"`
+	ETag         string `xml:"ETag"`
+	Size         int64  `xml:"Size"`
+	StorageClass string `xml:"StorageClass"`
+}
+
+type s3CommonEntry struct {
+	Prefix string `xml:"Prefix"`
+}
+
+// listObjects implements ListObjects v1 and, with list-type=2, v2
+func (s *s3Server) listObjects(w http.ResponseWriter, r *http.Request, bucket string) error {
+	if err := s.requireBucket(bucket); err != nil {
+		return err
+	}
+	query := r.URL.Query()
+	v2 := query.Get("list-type") == "2"
+	prefix, delimiter := query.Get("prefix"), query.Get("delimiter")
+	maxKeys := 1000
+	if value := query.Get("max-keys"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return newS3Error(http.StatusBadRequest, "InvalidArgument", "max-keys must be a non-negative integer")
+		}
+		if n < maxKeys {
+			maxKeys = n
+		}
+	}
+
+	after := query.Get("marker")
+	if v2 {
+		after = query.Get("start-after")
+		if token := query.Get("continuation-token"); token != "" {
+			decoded, err := base64.RawURLEncoding.DecodeString(token)
+			if err != nil {
+				return newS3Error(http.StatusBadRequest, "InvalidArgument", "the continuation token provided is incorrect")
+			}
+			after = string(decoded)
+		}
+	}
+
+	var keys []string
+	root := s.bucketPath(bucket)
+	filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || p == root {
+			return nil
+		}
+		rel, _ := filepath.Rel(root, p)
+		key := filepath.ToSlash(rel)
+		if d.IsDir() {
+			// Only empty directories are listed, as folder markers
+			if entries, err := os.ReadDir(p); err == nil && len(entries) == 0 {
+				keys = append(keys, key+"/")
+			}
+			return nil
+		}
+		keys = append(keys, key)
+		return nil
+	})
+	sort.Strings(keys)
+
+	result := s3ListBucketResult{XMLNS: s3XMLNS, Name: bucket, Prefix: prefix, Delimiter: delimiter, MaxKeys: maxKeys}
+	encode := func(s string) string { return s }
+	if query.Get("encoding-type") == "url" {
+		result.EncodingType = "url"
+		encode = func(s string) string { return strings.ReplaceAll(url.QueryEscape(s), "%2F", "/") }
+		result.Prefix, result.Delimiter = encode(prefix), encode(delimiter)
+	}
+
+	seenPrefixes := map[string]bool{}
+	last, count := "", 0
+	for _, key := range keys {
+		if !strings.HasPrefix(key, prefix) || key <= after {
+			continue
+		}
+		entry := key
+		if delimiter != "" {
+			if i := strings.Index(key[len(prefix):], delimiter); i >= 0 {
+				entry = key[:len(prefix)+i+len(delimiter)]
+				// A prefix returned as the last entry of the previous page is
+				// the marker itself
+				if seenPrefixes[entry] || entry == after {
+					continue
+				}
+			}
+		}
+		if count == maxKeys {
+			result.IsTruncated = true
+			break
+		}
+		count++
+		if entry != key {
+			seenPrefixes[entry] = true
+			result.CommonPrefixes = append(result.CommonPrefixes, s3CommonEntry{Prefix: encode(entry)})
+			last = entry
+			continue
+		}
+		last = key
+		obj, err := s.stat(bucket, key)
+		if err != nil {
+			continue
+		}
+		result.Contents = append(result.Contents, s3ListEntry{
+			Key:          encode(key),
+			LastModified: s3Time(obj.modified),
+			ETag:         obj.meta.ETag,
+			Size:         obj.size,
+			StorageClass: "STANDARD",
+		})
+	}
+
+	if v2 {
+		result.KeyCount = &count
+		result.StartAfter = encode(query.Get("start-after"))
+		result.ContinuationToken = query.Get("continuation-token")
+		if result.IsTruncated {
+			result.NextContinuationToken = base64.RawURLEncoding.EncodeToString([]byte(last))
+		}
+	} else {
+		marker := encode(query.Get("marker"))
+		result.Marker = &marker
+		if result.IsTruncated && delimiter != "" {
+			result.NextMarker = encode(last)
+		}
+	}
+	writeS3XML(w, result)
+	return nil
+}
+
+type s3InitiateMultipartUploadResult struct {
+	XMLName  xml.Name `xml:"InitiateMultipartUploadResult"`
+	XMLNS    string   `xml:"xmlns,attr"`
+	Bucket   string   `xml:"Bucket"`
+	Key      string   `xml:"Key"`
+	UploadID string   `xml:"UploadId"`
+}
+
+func (s *s3Server) uploadDir(id string) (string, error) {
+	if id == "" || strings.ContainsAny(id, `/\.`) {
+		return "", newS3Error(http.StatusNotFound, "NoSuchUpload", "the specified upload does not exist")
+	}
+	dir := filepath.Join(s.dir, s3MetaDir, "uploads", id)
+	if _, err := os.Stat(dir); err != nil {
+		return "", newS3Error(http.StatusNotFound, "NoSuchUpload", "the specified upload does not exist")
+	}
+	return dir, nil
+}
+
+func (s *s3Server) createUpload(w http.ResponseWriter, r *http.Request, bucket, key string) error {
+	if err := s.requireBucket(bucket); err != nil {
+		return err
+	}
+	raw := make([]byte, 16)
+	rand.Read(raw)
+	id := hex.EncodeToString(raw)
+	dir := filepath.Join(s.dir, s3MetaDir, "uploads", id)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	data, _ := json.Marshal(s3Upload{Bucket: bucket, Key: key, Object: objectMeta(r)})
+	if err := os.WriteFile(filepath.Join(dir, "upload.json"), data, 0644); err != nil {
+		return err
+	}
+	writeS3XML(w, s3InitiateMultipartUploadResult{XMLNS: s3XMLNS, Bucket: bucket, Key: key, UploadID: id})
+	return nil
+}
+
+func (s *s3Server) uploadPart(w http.ResponseWriter, r *http.Request, bucket, key string) error {
+	query := r.URL.Query()
+	dir, err := s.uploadDir(query.Get("uploadId"))
+	if err != nil {
+		return err
+	}
+	part, err := strconv.Atoi(query.Get("partNumber"))
+	if err != nil || part < 1 || part > 10000 {
+		return newS3Error(http.StatusBadRequest, "InvalidArgument", "partNumber must be an integer between 1 and 10000")
+	}
+	tmp, etag, err := s.writeTemp(requestBody(r))
+	if err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, filepath.Join(dir, strconv.Itoa(part))); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	os.WriteFile(filepath.Join(dir, strconv.Itoa(part)+".etag"), []byte(etag), 0644)
+	w.Header().Set("ETag", etag)
+	return nil
+}
+
+type s3CompleteMultipartUpload struct {
+	Parts []struct {
+		PartNumber int    `xml:"PartNumber"`
+		ETag       string `xml:"ETag"`
+	} `xml:"Part"`
+}
+
+type s3CompleteMultipartUploadResult struct {
+	XMLName  xml.Name `xml:"CompleteMultipartUploadResult"`
+	XMLNS    string   `xml:"xmlns,attr"`
+	Location string   `xml:"Location"`
+	Bucket   string   `xml:"Bucket"`
+	Key      string   `xml:"Key"`
+	ETag     string   `xml:"ETag"`
+}
+
+func (s *s3Server) completeUpload(w http.ResponseWriter, r *http.Request, bucket, key string) error {
+	dir, err := s.uploadDir(r.URL.Query().Get("uploadId"))
+	if err != nil {
+		return err
+	}
+	var upload s3Upload
+	if data, err := os.ReadFile(filepath.Join(dir, "upload.json")); err != nil || json.Unmarshal(data, &upload) != nil ||
+		upload.Bucket != bucket || upload.Key != key {
+		return newS3Error(http.StatusNotFound, "NoSuchUpload", "the specified upload does not exist")
+	}
+	var request s3CompleteMultipartUpload
+	if err := xml.NewDecoder(requestBody(r)).Decode(&request); err != nil || len(request.Parts) == 0 {
+		return newS3Error(http.StatusBadRequest, "MalformedXML", "the XML you provided was not well-formed")
+	}
+
+	// The object ETag is the MD5 of the part MD5s followed by the part count
+	var files []io.Reader
+	var digests []byte
+	previous := 0
+	for _, part := range request.Parts {
+		if part.PartNumber <= previous {
+			return newS3Error(http.StatusBadRequest, "InvalidPartOrder", "the list of parts was not in ascending order")
+		}
+		previous = part.PartNumber
+		name := filepath.Join(dir, strconv.Itoa(part.PartNumber))
+		etag, err := os.ReadFile(name + ".etag")
+		if err != nil || strings.Trim(part.ETag, `"`) != strings.Trim(string(etag), `"`) {
+			return newS3Error(http.StatusBadRequest, "InvalidPart", fmt.Sprintf("part %d could not be found or its ETag does not match", part.PartNumber))
+		}
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		files = append(files, f)
+		digest, _ := hex.DecodeString(strings.Trim(string(etag), `"`))
+		digests = append(digests, digest...)
+	}
+
+	tmp, _, err := s.writeTemp(io.MultiReader(files...))
+	if err != nil {
+		return err
+	}
+	sum := md5.Sum(digests)
+	etag := fmt.Sprintf(`"%s-%d"`, hex.EncodeToString(sum[:]), len(request.Parts))
+	upload.Object.ETag = etag
+	if err := s.store(bucket, key, tmp, upload.Object); err != nil {
+		return err
+	}
+	os.RemoveAll(dir)
+
+	location := "http://" + r.Host + "/" + bucket + "/" + key
+	writeS3XML(w, s3CompleteMultipartUploadResult{XMLNS: s3XMLNS, Location: location, Bucket: bucket, Key: key, ETag: etag})
+	return nil
+}
+
+func (s *s3Server) abortUpload(w http.ResponseWriter, id string) error {
+	dir, err := s.uploadDir(id)
+	if err != nil {
+		return err
+	}
+	os.RemoveAll(dir)
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// awsChunkedReader decodes the aws-chunked body of streaming uploads:
+// hex-size[;chunk-signature=...]\r\n data \r\n ... 0\r\n [trailers] \r\n.
+// Chunk signatures and trailing checksums are not checked.
+type awsChunkedReader struct {
+	r         *bufio.Reader
+	remaining int64
+	done      bool
+}
+
+func (c *awsChunkedReader) Read(p []byte) (int, error) {
+	for c.remaining == 0 {
+		if c.done {
+			return 0, io.EOF
+		}
+		line, err := c.r.ReadString('\n')
+		if err != nil {
+			return 0, io.ErrUnexpectedEOF
+		}
+		sizeField, _, _ := strings.Cut(strings.TrimSpace(line), ";")
+		size, err := strconv.ParseInt(sizeField, 16, 64)
+		if err != nil || size < 0 {
+			return 0, fmt.Errorf("malformed aws-chunked body")
+		}
+		if size == 0 {
+			c.done = true
+			// Skip trailers up to the final empty line
+			for {
+				line, err := c.r.ReadString('\n')
+				if err != nil || strings.TrimSpace(line) == "" {
+					break
+				}
+			}
+			return 0, io.EOF
+		}
+		c.remaining = size
+	}
+
+	if int64(len(p)) > c.remaining {
+		p = p[:c.remaining]
+	}
+	n, err := c.r.Read(p)
+	c.remaining -= int64(n)
+	if c.remaining == 0 {
+		// Consume the CRLF after the chunk data
+		if _, err := c.r.Discard(2); err != nil {
+			return n, io.ErrUnexpectedEOF
+		}
+	}
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return n, err
+}
+
+// authenticate checks presigned URL expiry and, with --access-key, the
+// AWS Signature V4 of the request
+func (s *s3Server) authenticate(r *http.Request) error {
+	query := r.URL.Query()
+	presigned := query.Get("X-Amz-Algorithm") != ""
+
+	if presigned {
+		date, err := time.Parse("20060102T150405Z", query.Get("X-Amz-Date"))
+		if err != nil {
+			return newS3Error(http.StatusBadRequest, "AuthorizationQueryParametersError", "X-Amz-Date must be in the ISO8601 basic format")
+		}
+		expires, err := strconv.Atoi(query.Get("X-Amz-Expires"))
+		if err != nil || expires < 1 || expires > 604800 {
+			return newS3Error(http.StatusBadRequest, "AuthorizationQueryParametersError", "X-Amz-Expires must be between 1 and 604800 seconds")
+		}
+		if time.Now().After(date.Add(time.Duration(expires) * time.Second)) {
+			return newS3Error(http.StatusForbidden, "AccessDenied", "Request has expired")
+		}
+	}
+	if s.accessKey == "" {
+		return nil
+	}
+
+	var algorithm, credential, signedHeaders, signature, amzDate, payloadHash string
+	if presigned {
+		algorithm = query.Get("X-Amz-Algorithm")
+		credential = query.Get("X-Amz-Credential")
+		signedHeaders = query.Get("X-Amz-SignedHeaders")
+		signature = query.Get("X-Amz-Signature")
+		amzDate = query.Get("X-Amz-Date")
+		payloadHash = "UNSIGNED-PAYLOAD"
+	} else {
+		auth := r.Header.Get("Authorization")
+		if auth == "" {
+			return newS3Error(http.StatusForbidden, "AccessDenied", "Access Denied")
+		}
+		algorithm, auth, _ = strings.Cut(auth, " ")
+		for _, part := range strings.Split(auth, ",") {
+			name, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+			switch name {
+			case "Credential":
+				credential = value
+			case "SignedHeaders":
+				signedHeaders = value
+			case "Signature":
+				signature = value
+			}
+		}
+		amzDate = r.Header.Get("X-Amz-Date")
+		payloadHash = r.Header.Get("X-Amz-Content-Sha256")
+		if payloadHash == "" {
+			payloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+		}
+	}
+	if algorithm != "AWS4-HMAC-SHA256" {
+		return newS3Error(http.StatusBadRequest, "InvalidArgument", "only AWS4-HMAC-SHA256 signatures are supported")
+	}
+	scope := strings.SplitN(credential, "/", 2)
+	if len(scope) != 2 || strings.Count(scope[1], "/") != 3 {
+		return newS3Error(http.StatusBadRequest, "AuthorizationHeaderMalformed", "the credential is malformed")
+	}
+	if scope[0] != s.accessKey {
+		return newS3Error(http.StatusForbidden, "InvalidAccessKeyId", "the access key ID you provided does not exist in our records")
+	}
+
+	canonical := s3CanonicalRequest(r, signedHeaders, payloadHash)
+	expected := s3Signature(s.secretKey, amzDate, scope[1], canonical)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return newS3Error(http.StatusForbidden, "SignatureDoesNotMatch", "the request signature we calculated does not match the signature you provided")
+	}
+	return nil
+}
+
+// s3CanonicalRequest builds the canonical request of Signature V4
+func s3CanonicalRequest(r *http.Request, signedHeaders, payloadHash string) string {
+	var query []string
+	for name, values := range r.URL.Query() {
+		if name == "X-Amz-Signature" {
+			continue
+		}
+		for _, value := range values {
+			query = append(query, s3URIEncode(name, true)+"="+s3URIEncode(value, true))
+		}
+	}
+	sort.Strings(query)
+
+	var headers strings.Builder
+	for _, name := range strings.Split(signedHeaders, ";") {
+		var value string
+		switch name {
+		case "host":
+			value = r.Host
+		case "content-length":
+			value = r.Header.Get("Content-Length")
+			if value == "" {
+				value = strconv.FormatInt(r.ContentLength, 10)
+			}
+		default:
+			value = strings.Join(r.Header.Values(name), ",")
+		}
+		headers.WriteString(name + ":" + strings.Join(strings.Fields(value), " ") + "\n")
+	}
+
+	return strings.Join([]string{
+		r.Method,
+		s3URIEncode(r.URL.Path, false),
+		strings.Join(query, "&"),
+		headers.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+}
+
+// s3Signature signs a canonical request for the credential scope
+// date/region/service/aws4_request
+func s3Signature(secretKey, amzDate, scope, canonical string) string {
+	parts := strings.Split(scope, "/")
+	hash := sha256.Sum256([]byte(canonical))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(hash[:])
+
+	key := []byte("AWS4" + secretKey)
+	for _, part := range parts {
+		key = hmacSHA256(key, part)
+	}
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// s3URIEncode percent-encodes everything but unreserved characters, and
+// slashes in paths
+func s3URIEncode(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9', c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+	"devkit/internal/netutil"
 	"devkit/internal/output"
 )
 
@@ -33,12 +34,25 @@ func runSpeed(cmd *cobra.Command, args []string) error {
 	outputFormat, _ := cmd.Flags().GetString("output")
 	format := output.OutputFormat(outputFormat)
 
+	// The download has to fit in the timeout, so it is longer than usual
+	opts, err := netutil.FromCommand(cmd, time.Minute)
+	if err != nil {
+		return err
+	}
+	client := opts.HTTPClient()
+
 	// Simple speed test using HTTP download
 	testURL := "https://speed.cloudflare.com/__down?bytes=10000000" // 10MB
 
-	fmt.Println("Testing download speed...")
+	if format != output.FormatJSON {
+		fmt.Println("Testing download speed...")
+	}
 	start := time.Now()
-	resp, err := http.Get(testURL)
+	req, err := http.NewRequestWithContext(cmd.Context(), http.MethodGet, testURL, nil)
+	if err != nil {
+		return fmt.Errorf("speed test failed: %w", err)
+	}
+	resp, err := opts.Do(client, req)
 	if err != nil {
 		return fmt.Errorf("speed test failed: %w", err)
 	}
@@ -55,7 +69,9 @@ func runSpeed(cmd *cobra.Command, args []string) error {
 
 	// Ping test
 	pingStart := time.Now()
-	_, err = http.Get("https://www.google.com")
+	if pingResp, err := client.Get("https://www.google.com"); err == nil {
+		pingResp.Body.Close()
+	}
 	pingDuration := time.Since(pingStart)
 
 	result := map[string]interface{}{
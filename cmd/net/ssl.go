@@ -1,12 +1,18 @@
 package net
 
 import (
+	"context"
 	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/spf13/cobra"
+	"devkit/internal/check"
+	"devkit/internal/netutil"
 	"devkit/internal/output"
 )
 
@@ -27,10 +33,14 @@ var sslCheckCmd = &cobra.Command{
 	Short: "Check SSL certificate",
 	Long: `Check SSL certificate information for a host.
 
+With --targets, every host listed in the file (one per line, - for stdin)
+is checked concurrently and one table or JSON report is printed.
+
 Examples:
   devkit net ssl check google.com
-  devkit net ssl check example.com:443`,
-	Args: cobra.ExactArgs(1),
+  devkit net ssl check example.com:443
+  devkit net ssl check --targets hosts.txt --jobs 20`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: runSSLCheck,
 }
 
@@ -40,9 +50,21 @@ var sslExpiryCmd = &cobra.Command{
 	Short: "Check SSL certificate expiry",
 	Long: `Check when SSL certificate expires.
 
+Exit status: 3 when the certificate has expired, 2 when it is not trusted
+(unknown issuer, host name mismatch), 4 when it expires within --warn-days.
+--fail-on sets the lowest level that fails the command (default expired),
+so a CI job can fail ahead of time with --fail-on warning.
+
+With --targets, every host listed in the file (one per line, - for stdin)
+is checked concurrently and one report is printed; the exit status follows
+the worst certificate.
+
 Examples:
-  devkit net ssl expiry google.com`,
-	Args: cobra.ExactArgs(1),
+  devkit net ssl expiry google.com
+  devkit net ssl expiry example.com --warn-days 14 --fail-on warning
+  devkit net ssl expiry internal.example.com --fail-on none -o json
+  devkit net ssl expiry --targets hosts.txt --warn-days 14 --fail-on warning`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: runSSLExpiry,
 }
 
@@ -52,47 +74,48 @@ func init() {
 	sslCmd.AddCommand(sslExpiryCmd)
 
 	sslCheckCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
+	addBatchFlags(sslCheckCmd)
+	sslExpiryCmd.Flags().Int("warn-days", 30, "Warn when the certificate expires within this many days")
 	sslExpiryCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
+	check.AddFailOnFlag(sslExpiryCmd, check.Expired)
+	addBatchFlags(sslExpiryCmd)
 }
 
 func runSSLCheck(cmd *cobra.Command, args []string) error {
-	host := args[0]
 	outputFormat, _ := cmd.Flags().GetString("output")
 	format := output.OutputFormat(outputFormat)
-
-	if !strings.Contains(host, ":") {
-		host = host + ":443"
+	opts, err := netutil.FromCommand(cmd, 10*time.Second)
+	if err != nil {
+		return err
 	}
 
-	conn, err := tls.Dial("tcp", host, &tls.Config{
-		InsecureSkipVerify: false,
-	})
+	targets, batch, err := batchTargets(cmd, args)
 	if err != nil {
-		return fmt.Errorf("failed to connect: %w", err)
+		return err
 	}
-	defer conn.Close()
-
-	state := conn.ConnectionState()
-	if len(state.PeerCertificates) == 0 {
-		return fmt.Errorf("no certificate found")
+	if batch {
+		results := runBatch(cmd, targets, func(host string) (map[string]interface{}, error) {
+			return sslCheck(cmd.Context(), opts, host)
+		})
+		return printBatch(cmd, format, results, []batchColumn{
+			{Header: "VALID TO", Key: "valid_to"},
+			{Header: "DAYS", Key: "days_remaining"},
+			{Header: "ISSUER", Key: "issuer"},
+		})
+	}
+	if len(targets) == 0 {
+		return fmt.Errorf("host not specified (provide it as argument or use --targets)")
 	}
 
-	cert := state.PeerCertificates[0]
-
-	result := map[string]interface{}{
-		"host":            host,
-		"subject":         cert.Subject.String(),
-		"issuer":          cert.Issuer.String(),
-		"valid_from":      cert.NotBefore.Format(time.RFC3339),
-		"valid_to":        cert.NotAfter.Format(time.RFC3339),
-		"is_valid":        time.Now().Before(cert.NotAfter) && time.Now().After(cert.NotBefore),
-		"days_remaining":  int(time.Until(cert.NotAfter).Hours() / 24),
+	result, err := sslCheck(cmd.Context(), opts, targets[0])
+	if err != nil {
+		return err
 	}
 
 	if format == output.FormatJSON {
 		output.PrintSuccess(format, result)
 	} else {
-		fmt.Printf("SSL Certificate for %s:\n", host)
+		fmt.Printf("SSL Certificate for %s:\n", result["host"])
 		fmt.Printf("  Subject: %s\n", result["subject"])
 		fmt.Printf("  Issuer: %s\n", result["issuer"])
 		fmt.Printf("  Valid From: %s\n", result["valid_from"])
@@ -103,48 +126,177 @@ func runSSLCheck(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func runSSLExpiry(cmd *cobra.Command, args []string) error {
-	host := args[0]
-	outputFormat, _ := cmd.Flags().GetString("output")
-	format := output.OutputFormat(outputFormat)
+// sslCheck connects to host and describes its verified certificate
+func sslCheck(ctx context.Context, opts netutil.Options, host string) (map[string]interface{}, error) {
+	host = sslAddress(host)
 
-	if !strings.Contains(host, ":") {
-		host = host + ":443"
-	}
-
-	conn, err := tls.Dial("tcp", host, &tls.Config{
+	conn, err := opts.DialTLS(ctx, "tcp", host, &tls.Config{
 		InsecureSkipVerify: false,
 	})
 	if err != nil {
-		return fmt.Errorf("failed to connect: %w", err)
+		return nil, fmt.Errorf("failed to connect: %w", err)
 	}
 	defer conn.Close()
 
 	state := conn.ConnectionState()
 	if len(state.PeerCertificates) == 0 {
-		return fmt.Errorf("no certificate found")
+		return nil, fmt.Errorf("no certificate found")
 	}
 
 	cert := state.PeerCertificates[0]
+
+	return map[string]interface{}{
+		"host":           host,
+		"subject":        cert.Subject.String(),
+		"issuer":         cert.Issuer.String(),
+		"valid_from":     cert.NotBefore.Format(time.RFC3339),
+		"valid_to":       cert.NotAfter.Format(time.RFC3339),
+		"is_valid":       time.Now().Before(cert.NotAfter) && time.Now().After(cert.NotBefore),
+		"days_remaining": int(time.Until(cert.NotAfter).Hours() / 24),
+	}, nil
+}
+
+func runSSLExpiry(cmd *cobra.Command, args []string) error {
+	warnDays, _ := cmd.Flags().GetInt("warn-days")
+	outputFormat, _ := cmd.Flags().GetString("output")
+	format := output.OutputFormat(outputFormat)
+	opts, err := netutil.FromCommand(cmd, 10*time.Second)
+	if err != nil {
+		return err
+	}
+
+	targets, batch, err := batchTargets(cmd, args)
+	if err != nil {
+		return err
+	}
+	if batch {
+		// The exit code follows the worst finding across all hosts
+		var mu sync.Mutex
+		worst := check.None
+		results := runBatch(cmd, targets, func(host string) (map[string]interface{}, error) {
+			result, level, err := sslExpiry(cmd.Context(), opts, host, warnDays)
+			if err != nil {
+				return nil, err
+			}
+			result["status"] = "ok"
+			if level != check.None {
+				result["status"] = level.String()
+			}
+			mu.Lock()
+			worst = max(worst, level)
+			mu.Unlock()
+			return result, nil
+		})
+		if err := printBatch(cmd, format, results, []batchColumn{
+			{Header: "EXPIRES", Key: "expires"},
+			{Header: "DAYS", Key: "days_remaining"},
+			{Header: "STATUS", Key: "status"},
+		}); err != nil {
+			return err
+		}
+		return check.Result(cmd, worst, fmt.Sprintf("certificate check found %s certificates", worst))
+	}
+	if len(targets) == 0 {
+		return fmt.Errorf("host not specified (provide it as argument or use --targets)")
+	}
+
+	result, level, err := sslExpiry(cmd.Context(), opts, targets[0], warnDays)
+	if err != nil {
+		return err
+	}
+	host := result["host"]
+	daysRemaining := result["days_remaining"]
+
+	if format == output.FormatJSON {
+		output.PrintSuccess(format, result)
+	} else {
+		switch {
+		case level == check.Expired:
+			fmt.Println(output.Fail(fmt.Sprintf("Certificate for %s EXPIRED on %s", host, result["expires"])))
+		case level == check.Warning:
+			fmt.Println(output.Warn(fmt.Sprintf("Certificate for %s expires in %d days (%s)", host, daysRemaining, result["expires"])))
+		default:
+			fmt.Printf("Certificate for %s expires in %d days (%s)\n", host, daysRemaining, result["expires"])
+		}
+		if level == check.Invalid {
+			fmt.Println(output.Fail(fmt.Sprintf("Certificate is not trusted: %v", result["verify_error"])))
+		}
+	}
+
+	switch level {
+	case check.Expired:
+		return check.Result(cmd, level, "certificate has expired")
+	case check.Invalid:
+		return check.Result(cmd, level, "certificate is not trusted")
+	case check.Warning:
+		return check.Result(cmd, level, fmt.Sprintf("certificate expires in %d days", daysRemaining))
+	}
+	return nil
+}
+
+// sslExpiry reads the certificate of host, trusted or not, and rates it
+func sslExpiry(ctx context.Context, opts netutil.Options, host string, warnDays int) (map[string]interface{}, check.Level, error) {
+	host = sslAddress(host)
+
+	cert, verifyErr, err := fetchCertificate(ctx, opts, host)
+	if err != nil {
+		return nil, check.None, err
+	}
+
 	daysRemaining := int(time.Until(cert.NotAfter).Hours() / 24)
 	isExpired := time.Now().After(cert.NotAfter)
 
+	// An expired certificate also fails verification; report it as expired
+	// and keep "invalid" for untrusted chains and host name mismatches
+	level := check.None
+	var invalid *x509.CertificateInvalidError
+	switch {
+	case isExpired:
+		level = check.Expired
+	case verifyErr != nil && !(errors.As(verifyErr, &invalid) && invalid.Reason == x509.Expired):
+		level = check.Invalid
+	case daysRemaining < warnDays:
+		level = check.Warning
+	}
+
 	result := map[string]interface{}{
 		"host":           host,
 		"expires":        cert.NotAfter.Format(time.RFC3339),
 		"days_remaining": daysRemaining,
 		"is_expired":     isExpired,
+		"warning":        level == check.Warning,
 	}
+	if verifyErr != nil {
+		result["verify_error"] = verifyErr.Error()
+	}
+	return result, level, nil
+}
 
-	if format == output.FormatJSON {
-		output.PrintSuccess(format, result)
-	} else {
-		if isExpired {
-			fmt.Printf("Certificate for %s EXPIRED on %s\n", host, result["expires"])
-		} else {
-			fmt.Printf("Certificate for %s expires in %d days (%s)\n", host, daysRemaining, result["expires"])
+// sslAddress adds the default HTTPS port to host when it has none
+func sslAddress(host string) string {
+	if !strings.Contains(host, ":") {
+		return host + ":443"
+	}
+	return host
+}
+
+// fetchCertificate returns the leaf certificate of host. A certificate that
+// fails verification is still returned, together with the verification
+// error, so that its dates can be reported.
+func fetchCertificate(ctx context.Context, opts netutil.Options, host string) (cert *x509.Certificate, verifyErr error, err error) {
+	conn, err := opts.DialTLS(ctx, "tcp", host, &tls.Config{})
+	if err != nil {
+		var verr *tls.CertificateVerificationError
+		if errors.As(err, &verr) && len(verr.UnverifiedCertificates) > 0 {
+			return verr.UnverifiedCertificates[0], verr.Err, nil
 		}
+		return nil, nil, fmt.Errorf("failed to connect: %w", err)
 	}
+	defer conn.Close()
 
-	return nil
+	state := conn.ConnectionState()
+	if len(state.PeerCertificates) == 0 {
+		return nil, nil, fmt.Errorf("no certificate found")
+	}
+	return state.PeerCertificates[0], nil, nil
 }
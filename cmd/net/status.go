@@ -0,0 +1,414 @@
+package net
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+	"unicode/utf8"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+	"devkit/internal/netutil"
+	"devkit/internal/output"
+)
+
+// statusCmd represents the status command
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Live reachability dashboard for a list of targets",
+	Long: `Show a live terminal dashboard with the TCP/HTTP/TLS status of a list of
+targets, including latency sparklines and the last error seen.
+
+A tcp target is up when a TCP connection to host:port (port 80 by default)
+succeeds; no ICMP ping is sent. "ping" is accepted as an older name for it.
+
+Targets file format (YAML):
+  interval: 5s
+  targets:
+    - name: api
+      type: http          # http, tls or tcp
+      url: https://api.example.com/health
+    - name: database
+      type: tcp
+      host: db.internal
+      port: 5432
+    - name: website
+      type: tls
+      host: example.com
+
+Examples:
+  devkit net status --targets targets.yaml
+  devkit net status --targets targets.yaml --interval 10s
+  devkit net status --targets targets.yaml --once --output json`,
+	RunE: runStatus,
+}
+
+// statusTarget describes a single target in the targets file
+type statusTarget struct {
+	Name string `yaml:"name"`
+	Type string `yaml:"type"`
+	URL  string `yaml:"url"`
+	Host string `yaml:"host"`
+	Port int    `yaml:"port"`
+}
+
+// statusConfig is the structure of the targets file
+type statusConfig struct {
+	Interval string         `yaml:"interval"`
+	Targets  []statusTarget `yaml:"targets"`
+}
+
+// statusState holds the rolling check history of a target
+type statusState struct {
+	target    statusTarget
+	up        bool
+	checked   bool
+	latency   time.Duration
+	latencies []time.Duration
+	lastError string
+	detail    string
+}
+
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+func init() {
+	netCmd.AddCommand(statusCmd)
+
+	statusCmd.Flags().String("targets", "", "Targets file (YAML) (required)")
+	statusCmd.Flags().Duration("interval", 0, "Refresh interval (overrides the targets file, default 5s)")
+	statusCmd.Flags().Int("history", 30, "Number of samples kept for the latency sparkline")
+	statusCmd.Flags().Bool("once", false, "Check all targets once and exit")
+	statusCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json (json requires --once)")
+	statusCmd.MarkFlagRequired("targets")
+}
+
+func runStatus(cmd *cobra.Command, args []string) error {
+	targetsFile, _ := cmd.Flags().GetString("targets")
+	interval, _ := cmd.Flags().GetDuration("interval")
+	history, _ := cmd.Flags().GetInt("history")
+	once, _ := cmd.Flags().GetBool("once")
+	outputFormat, _ := cmd.Flags().GetString("output")
+	format := output.OutputFormat(outputFormat)
+
+	cfg, err := loadStatusConfig(targetsFile)
+	if err != nil {
+		return err
+	}
+
+	if interval == 0 {
+		interval = 5 * time.Second
+		if cfg.Interval != "" {
+			interval, err = time.ParseDuration(cfg.Interval)
+			if err != nil {
+				return fmt.Errorf("invalid interval in targets file: %w", err)
+			}
+		}
+	}
+	if interval < time.Second {
+		return fmt.Errorf("interval must be at least 1s")
+	}
+	if history < 1 {
+		history = 1
+	}
+
+	// A check may take as long as the refresh interval unless --timeout
+	// asks for less
+	opts, err := netutil.FromCommand(cmd, interval)
+	if err != nil {
+		return err
+	}
+
+	states := make([]*statusState, len(cfg.Targets))
+	for i, target := range cfg.Targets {
+		states[i] = &statusState{target: target}
+	}
+
+	if once {
+		checkAllTargets(cmd.Context(), states, opts, history)
+		if format == output.FormatJSON {
+			output.PrintSuccess(format, map[string]interface{}{
+				"targets": statusResults(states),
+				"count":   len(states),
+			})
+		} else {
+			renderStatus(states, interval, false)
+		}
+		return nil
+	}
+
+	if format == output.FormatJSON {
+		return fmt.Errorf("--output json requires --once")
+	}
+
+	// Restore the cursor when interrupted
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigs)
+	fmt.Print("\033[?25l")
+	defer fmt.Print("\033[?25h")
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		checkAllTargets(cmd.Context(), states, opts, history)
+		renderStatus(states, interval, true)
+
+		select {
+		case <-sigs:
+			fmt.Println()
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func loadStatusConfig(path string) (*statusConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read targets file: %w", err)
+	}
+
+	var cfg statusConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("invalid targets file: %w", err)
+	}
+
+	if len(cfg.Targets) == 0 {
+		return nil, fmt.Errorf("no targets defined in %s", path)
+	}
+
+	for i := range cfg.Targets {
+		t := &cfg.Targets[i]
+		t.Type = strings.ToLower(t.Type)
+		if t.Type == "ping" {
+			// The former name of tcp, which never sent an ICMP ping
+			t.Type = "tcp"
+		}
+		if t.Type == "" {
+			t.Type = "tcp"
+			if t.URL != "" {
+				t.Type = "http"
+			}
+		}
+		switch t.Type {
+		case "http":
+			if t.URL == "" {
+				return nil, fmt.Errorf("target %d: http targets require a url", i+1)
+			}
+		case "tls", "tcp":
+			if t.Host == "" {
+				return nil, fmt.Errorf("target %d: %s targets require a host", i+1, t.Type)
+			}
+		default:
+			return nil, fmt.Errorf("target %d: unsupported type %s (supported: http, tls, tcp)", i+1, t.Type)
+		}
+		if t.Name == "" {
+			t.Name = t.Host
+			if t.URL != "" {
+				t.Name = t.URL
+			}
+		}
+	}
+
+	return &cfg, nil
+}
+
+func checkAllTargets(ctx context.Context, states []*statusState, opts netutil.Options, history int) {
+	var wg sync.WaitGroup
+	for _, state := range states {
+		wg.Add(1)
+		go func(s *statusState) {
+			defer wg.Done()
+			latency, detail, err := checkTarget(ctx, s.target, opts)
+
+			s.checked = true
+			s.up = err == nil
+			s.latency = latency
+			s.detail = detail
+			if err != nil {
+				s.lastError = fmt.Sprintf("%s: %v", time.Now().Format("15:04:05"), err)
+			} else {
+				s.latencies = append(s.latencies, latency)
+				if len(s.latencies) > history {
+					s.latencies = s.latencies[len(s.latencies)-history:]
+				}
+			}
+		}(state)
+	}
+	wg.Wait()
+}
+
+// checkTarget checks t, retrying failed checks. The latency is that of the
+// last attempt.
+func checkTarget(ctx context.Context, t statusTarget, opts netutil.Options) (latency time.Duration, detail string, err error) {
+	err = opts.Retry(ctx, func(ctx context.Context) error {
+		var err error
+		latency, detail, err = checkTargetOnce(ctx, t, opts)
+		return err
+	})
+	return latency, detail, err
+}
+
+func checkTargetOnce(ctx context.Context, t statusTarget, opts netutil.Options) (time.Duration, string, error) {
+	start := time.Now()
+
+	switch t.Type {
+	case "http":
+		client := opts.HTTPClient()
+		defer client.CloseIdleConnections()
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, t.URL, nil)
+		if err != nil {
+			return 0, "", err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return 0, "", err
+		}
+		resp.Body.Close()
+		latency := time.Since(start)
+		if resp.StatusCode >= 400 {
+			return latency, resp.Status, fmt.Errorf("HTTP %s", resp.Status)
+		}
+		return latency, resp.Status, nil
+
+	case "tls":
+		port := t.Port
+		if port == 0 {
+			port = 443
+		}
+		// checkTarget already retries, so dial once
+		once := opts
+		once.Retries = 0
+		conn, err := once.DialTLS(ctx, "tcp", net.JoinHostPort(t.Host, fmt.Sprint(port)), nil)
+		if err != nil {
+			return 0, "", err
+		}
+		defer conn.Close()
+		latency := time.Since(start)
+		certs := conn.ConnectionState().PeerCertificates
+		if len(certs) == 0 {
+			return latency, "", fmt.Errorf("no certificate found")
+		}
+		days := int(time.Until(certs[0].NotAfter).Hours() / 24)
+		if days < 0 {
+			return latency, "expired", fmt.Errorf("certificate expired")
+		}
+		return latency, fmt.Sprintf("cert %dd left", days), nil
+
+	default:
+		port := t.Port
+		if port == 0 {
+			port = 80
+		}
+		conn, err := opts.Dialer().DialContext(ctx, "tcp", net.JoinHostPort(t.Host, fmt.Sprint(port)))
+		if err != nil {
+			return 0, "", err
+		}
+		conn.Close()
+		return time.Since(start), fmt.Sprintf("tcp/%d", port), nil
+	}
+}
+
+func renderStatus(states []*statusState, interval time.Duration, live bool) {
+	var b strings.Builder
+	if live {
+		// Clear the screen and move the cursor home
+		b.WriteString("\033[H\033[2J")
+		fmt.Fprintf(&b, "DevKit status - %s (refresh every %s, Ctrl+C to quit)\n\n",
+			time.Now().Format("2006-01-02 15:04:05"), interval)
+	}
+
+	b.WriteString(output.Accent(fmt.Sprintf("%s %-6s %-6s %10s  %s %s", padStatus("TARGET", 20), "TYPE", "STATUS", "LATENCY", padStatus("HISTORY", 30), "DETAIL")) + "\n")
+	b.WriteString(output.Rule(100) + "\n")
+
+	for _, s := range states {
+		status := output.Muted("...   ")
+		if s.checked {
+			if s.up {
+				status = output.Success("UP    ")
+			} else {
+				status = output.Failure("DOWN  ")
+			}
+		}
+
+		latency := "-"
+		if s.up {
+			latency = formatLatency(s.latency)
+		}
+
+		fmt.Fprintf(&b, "%s %-6s %s %10s  %s %s\n",
+			padStatus(truncate(s.target.Name, 20), 20), s.target.Type, status, latency, padStatus(sparkline(s.latencies), 30), s.detail)
+		if s.lastError != "" {
+			fmt.Fprintf(&b, "%-20s %s\n", "", output.Muted("last error "+s.lastError))
+		}
+	}
+
+	fmt.Print(b.String())
+}
+
+func statusResults(states []*statusState) []map[string]interface{} {
+	results := make([]map[string]interface{}, 0, len(states))
+	for _, s := range states {
+		result := map[string]interface{}{
+			"name":   s.target.Name,
+			"type":   s.target.Type,
+			"up":     s.up,
+			"detail": s.detail,
+		}
+		if s.up {
+			result["latency_ms"] = float64(s.latency.Microseconds()) / 1000
+		}
+		if s.lastError != "" {
+			result["error"] = s.lastError
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+func sparkline(samples []time.Duration) string {
+	if len(samples) == 0 {
+		return ""
+	}
+
+	min, max := samples[0], samples[0]
+	for _, s := range samples {
+		if s < min {
+			min = s
+		}
+		if s > max {
+			max = s
+		}
+	}
+
+	var b strings.Builder
+	for _, s := range samples {
+		idx := 0
+		if max > min {
+			idx = int(float64(s-min) / float64(max-min) * float64(len(sparkBlocks)-1))
+		}
+		b.WriteRune(sparkBlocks[idx])
+	}
+	return b.String()
+}
+
+// padStatus pads s with spaces to width characters; fmt pads by bytes,
+// which misaligns the multi-byte sparkline blocks
+func padStatus(s string, width int) string {
+	if n := utf8.RuneCountInString(s); n < width {
+		return s + strings.Repeat(" ", width-n)
+	}
+	return s
+}
+
+func formatLatency(d time.Duration) string {
+	return fmt.Sprintf("%.1fms", float64(d.Microseconds())/1000)
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/shirou/gopsutil/v3/mem"
 	"github.com/shirou/gopsutil/v3/host"
 	"github.com/spf13/cobra"
+	"devkit/internal/i18n"
 	"devkit/internal/output"
 )
 
@@ -90,16 +91,16 @@ func runSysinfo(cmd *cobra.Command, args []string) error {
 		output.PrintSuccess(format, result)
 	} else {
 		if cpu, ok := result["cpu"].(map[string]interface{}); ok {
-			fmt.Printf("CPU: %s (%d cores) - Usage: %s\n", cpu["model"], cpu["cores"], cpu["usage"])
+			fmt.Printf("%s: %s (%d %s) - %s: %s\n", i18n.T("label.cpu"), cpu["model"], cpu["cores"], i18n.T("label.cores"), i18n.T("label.usage"), cpu["usage"])
 		}
 		if mem, ok := result["memory"].(map[string]interface{}); ok {
-			fmt.Printf("Memory: %s / %s (%s used)\n", mem["used"], mem["total"], mem["percent"])
+			fmt.Printf("%s: %s / %s (%s %s)\n", i18n.T("label.memory"), mem["used"], mem["total"], mem["percent"], i18n.T("label.used"))
 		}
 		if disk, ok := result["disk"].(map[string]interface{}); ok {
-			fmt.Printf("Disk: %s / %s (%s used)\n", disk["used"], disk["total"], disk["percent"])
+			fmt.Printf("%s: %s / %s (%s %s)\n", i18n.T("label.disk"), disk["used"], disk["total"], disk["percent"], i18n.T("label.used"))
 		}
 		if os, ok := result["os"].(map[string]interface{}); ok {
-			fmt.Printf("OS: %s %s (%s)\n", os["platform"], os["version"], os["hostname"])
+			fmt.Printf("%s: %s %s (%s)\n", i18n.T("label.os"), os["platform"], os["version"], os["hostname"])
 		}
 	}
 
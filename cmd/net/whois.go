@@ -1,12 +1,15 @@
 package net
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
+	"devkit/internal/cache"
+	"devkit/internal/netutil"
 	"devkit/internal/output"
 )
 
@@ -16,10 +19,15 @@ var whoisCmd = &cobra.Command{
 	Short: "Domain whois lookup",
 	Long: `Perform whois lookup for a domain.
 
+With --targets, every domain listed in the file (one per line, - for stdin)
+is looked up concurrently. The table shows the referral whois server and
+the dates of each response; use --output json for the full responses.
+
 Examples:
   devkit net whois example.com
-  devkit net whois google.com`,
-	Args: cobra.ExactArgs(1),
+  devkit net whois google.com
+  devkit net whois --targets domains.txt --jobs 4`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: runWhois,
 }
 
@@ -27,22 +35,115 @@ func init() {
 	netCmd.AddCommand(whoisCmd)
 
 	whoisCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
+	addBatchFlags(whoisCmd)
 }
 
+// whoisCacheTTL bounds how long cached whois responses are reused
+const whoisCacheTTL = 24 * time.Hour
+
 func runWhois(cmd *cobra.Command, args []string) error {
-	domain := args[0]
 	outputFormat, _ := cmd.Flags().GetString("output")
 	format := output.OutputFormat(outputFormat)
+	opts, err := netutil.FromCommand(cmd, 5*time.Second)
+	if err != nil {
+		return err
+	}
+
+	targets, batch, err := batchTargets(cmd, args)
+	if err != nil {
+		return err
+	}
+	if batch {
+		results := runBatch(cmd, targets, func(domain string) (map[string]interface{}, error) {
+			result, err := whoisLookup(cmd.Context(), opts, domain)
+			if err != nil {
+				return nil, err
+			}
+			data := result["data"].(string)
+			result["refer"] = whoisField(data, "refer", "whois")
+			result["created"] = whoisField(data, "created", "creation date")
+			result["changed"] = whoisField(data, "changed", "updated date")
+			return result, nil
+		})
+		return printBatch(cmd, format, results, []batchColumn{
+			{Header: "REFER", Key: "refer"},
+			{Header: "CREATED", Key: "created"},
+			{Header: "CHANGED", Key: "changed"},
+		})
+	}
+	if len(targets) == 0 {
+		return fmt.Errorf("domain not specified (provide it as argument or use --targets)")
+	}
+
+	result, err := whoisLookup(cmd.Context(), opts, targets[0])
+	if err != nil {
+		return err
+	}
+
+	if format == output.FormatJSON {
+		output.PrintSuccess(format, result)
+	} else {
+		fmt.Print(result["data"])
+	}
+
+	return nil
+}
+
+// whoisLookup returns the whois response for domain, using the cache
+func whoisLookup(ctx context.Context, opts netutil.Options, domain string) (map[string]interface{}, error) {
+	key := cache.Key(strings.ToLower(domain))
+	var whoisData string
+	if !cache.Get(cache.NamespaceWhois, key, whoisCacheTTL, &whoisData) {
+		err := opts.Retry(ctx, func(ctx context.Context) error {
+			var err error
+			whoisData, err = queryWhois(ctx, opts, domain)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		if whoisData != "" {
+			cache.Set(cache.NamespaceWhois, key, whoisData)
+		}
+	}
+
+	return map[string]interface{}{
+		"domain": domain,
+		"data":   whoisData,
+	}, nil
+}
 
+// whoisField returns the value of the first "key: value" line in a whois
+// response whose key is one of keys, or "" if there is none
+func whoisField(data string, keys ...string) string {
+	for _, line := range strings.Split(data, "\n") {
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		name = strings.ToLower(strings.TrimSpace(name))
+		for _, key := range keys {
+			if name == key {
+				return strings.TrimSpace(value)
+			}
+		}
+	}
+	return ""
+}
+
+// queryWhois sends the whois query for domain and returns the raw response
+func queryWhois(ctx context.Context, opts netutil.Options, domain string) (string, error) {
 	// Use whois server (simplified implementation)
 	whoisServer := "whois.iana.org"
-	conn, err := net.DialTimeout("tcp", whoisServer+":43", 5*time.Second)
+	conn, err := opts.Dialer().DialContext(ctx, "tcp", net.JoinHostPort(whoisServer, "43"))
 	if err != nil {
-		return fmt.Errorf("failed to connect to whois server: %w", err)
+		return "", fmt.Errorf("failed to connect to whois server: %w", err)
 	}
 	defer conn.Close()
 
-	conn.SetDeadline(time.Now().Add(5 * time.Second))
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
 	fmt.Fprintf(conn, "%s\r\n", domain)
 
 	var response strings.Builder
@@ -55,18 +156,5 @@ func runWhois(cmd *cobra.Command, args []string) error {
 		response.Write(buffer[:n])
 	}
 
-	whoisData := response.String()
-
-	result := map[string]interface{}{
-		"domain": domain,
-		"data":   whoisData,
-	}
-
-	if format == output.FormatJSON {
-		output.PrintSuccess(format, result)
-	} else {
-		fmt.Print(whoisData)
-	}
-
-	return nil
+	return response.String(), nil
 }
@@ -0,0 +1,467 @@
+package release
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"devkit/internal/output"
+)
+
+// Operating systems and architectures looked for when --platforms is not
+// given, in the order they are listed
+var (
+	packageOSes   = []string{"linux", "darwin", "windows", "freebsd", "openbsd", "netbsd"}
+	packageArches = []string{"amd64", "arm64", "386", "arm", "riscv64", "ppc64le", "s390x"}
+)
+
+// packageCmd represents the release package subcommand
+var packageCmd = &cobra.Command{
+	Use:   "package",
+	Short: "Archive, name and checksum build outputs with a manifest",
+	Long: `Package the binaries of a cross-compiled build as release artifacts named
+<name>_<version>_<os>_<arch>.tar.gz (or .zip), with an optional checksums
+file and a manifest.json describing every artifact.
+
+--bin is the path of the binary without the platform. For each platform
+the binary is looked up by the usual conventions, e.g. for --bin bin/devkit
+and linux/amd64:
+  bin/devkit-linux-amd64       (go build -o, as in make build-all)
+  bin/devkit_linux_amd64
+  bin/linux_amd64/devkit
+  bin/linux-amd64/devkit
+  bin/devkit_linux_amd64/devkit
+Windows binaries end in .exe. Without --platforms, every platform found
+next to --bin is packaged.
+
+The version defaults to the output of git describe --tags; a leading v is
+dropped from file names. Archives contain the binary as <name> (or
+<name>.exe) and the files given with --include. --archive none copies the
+binaries under the release names instead.
+
+Artifacts, the checksums file (<name>_<version>_checksums.txt, sha256sum
+format) and manifest.json are written to --out-dir, by default the
+directory of --bin.
+
+Examples:
+  devkit release package --bin ./bin/devkit --checksums
+  devkit release package --bin ./dist/devkit --platforms linux/amd64,darwin/arm64 --checksums --archive tar.gz
+  devkit release package --bin ./dist/devkit --version 1.4.0 --include README.md,LICENSE
+  devkit release package --bin ./dist/devkit --archive zip --out-dir ./release --output json`,
+	Args: cobra.NoArgs,
+	RunE: runPackage,
+}
+
+func init() {
+	releaseCmd.AddCommand(packageCmd)
+
+	packageCmd.Flags().String("bin", "", "Path of the binary without the platform suffix, e.g. ./bin/devkit (required)")
+	packageCmd.Flags().StringSlice("platforms", nil, "Platforms as os/arch, comma-separated (default: every binary found)")
+	packageCmd.Flags().String("name", "", "Project name used in artifact names (default: the --bin file name)")
+	packageCmd.Flags().String("version", "", "Version used in artifact names (default: git describe --tags)")
+	packageCmd.Flags().String("archive", "tar.gz", "Archive format: tar.gz, zip, none")
+	packageCmd.Flags().StringSlice("include", nil, "Extra files added to every archive, e.g. README.md,LICENSE")
+	packageCmd.Flags().Bool("checksums", false, "Write a SHA-256 checksums file")
+	packageCmd.Flags().String("out-dir", "", "Directory for the artifacts (default: the directory of --bin)")
+	packageCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
+	packageCmd.MarkFlagRequired("bin")
+}
+
+// packageArtifact is one packaged platform in the manifest
+type packageArtifact struct {
+	Platform string `json:"platform"`
+	OS       string `json:"os"`
+	Arch     string `json:"arch"`
+	Binary   string `json:"binary"`
+	File     string `json:"file"`
+	Size     int64  `json:"size"`
+	SHA256   string `json:"sha256"`
+}
+
+// packageManifest is written to manifest.json and printed with -o json
+type packageManifest struct {
+	Name      string            `json:"name"`
+	Version   string            `json:"version,omitempty"`
+	Archive   string            `json:"archive"`
+	Created   string            `json:"created"`
+	OutDir    string            `json:"out_dir"`
+	Checksums string            `json:"checksums,omitempty"`
+	Artifacts []packageArtifact `json:"artifacts"`
+}
+
+func runPackage(cmd *cobra.Command, args []string) error {
+	bin, _ := cmd.Flags().GetString("bin")
+	platforms, _ := cmd.Flags().GetStringSlice("platforms")
+	name, _ := cmd.Flags().GetString("name")
+	releaseVersion, _ := cmd.Flags().GetString("version")
+	archive, _ := cmd.Flags().GetString("archive")
+	includes, _ := cmd.Flags().GetStringSlice("include")
+	checksums, _ := cmd.Flags().GetBool("checksums")
+	outDir, _ := cmd.Flags().GetString("out-dir")
+	outputFormat, _ := cmd.Flags().GetString("output")
+	format := output.OutputFormat(outputFormat)
+
+	switch archive {
+	case "tar.gz", "zip", "none":
+	default:
+		return fmt.Errorf("invalid archive format: %s (supported: tar.gz, zip, none)", archive)
+	}
+	bin = strings.TrimSuffix(filepath.Clean(bin), ".exe")
+	if name == "" {
+		name = filepath.Base(bin)
+	}
+	if outDir == "" {
+		outDir = filepath.Dir(bin)
+	}
+	if archive == "none" && len(includes) > 0 {
+		return fmt.Errorf("--include needs an archive format")
+	}
+	for _, include := range includes {
+		info, err := os.Stat(include)
+		if err != nil {
+			return fmt.Errorf("cannot include %s: %w", include, err)
+		}
+		if info.IsDir() {
+			return fmt.Errorf("cannot include %s: is a directory", include)
+		}
+	}
+	if releaseVersion == "" {
+		releaseVersion = gitVersion()
+	}
+
+	for _, platform := range platforms {
+		goos, goarch, ok := strings.Cut(platform, "/")
+		if !ok || goos == "" || goarch == "" || strings.Contains(goarch, "/") {
+			return fmt.Errorf("invalid platform: %s (use os/arch, e.g. linux/amd64)", platform)
+		}
+	}
+	cmd.SilenceUsage = true
+
+	binaries := map[string]string{}
+	if len(platforms) == 0 {
+		for _, goos := range packageOSes {
+			for _, goarch := range packageArches {
+				if path := findPlatformBinary(bin, goos, goarch); path != "" {
+					platform := goos + "/" + goarch
+					platforms = append(platforms, platform)
+					binaries[platform] = path
+				}
+			}
+		}
+		if len(platforms) == 0 {
+			return fmt.Errorf("no platform binaries found for %s (e.g. %s-linux-amd64); build them first or give --platforms", bin, bin)
+		}
+	} else {
+		for _, platform := range platforms {
+			goos, goarch, _ := strings.Cut(platform, "/")
+			path := findPlatformBinary(bin, goos, goarch)
+			if path == "" {
+				return fmt.Errorf("no binary for %s (looked for %s and %s)", platform,
+					platformCandidates(bin, goos, goarch)[0], platformCandidates(bin, goos, goarch)[2])
+			}
+			binaries[platform] = path
+		}
+	}
+
+	if err := os.MkdirAll(outDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	manifest := packageManifest{
+		Name:      name,
+		Version:   releaseVersion,
+		Archive:   archive,
+		Created:   time.Now().Format(time.RFC3339),
+		OutDir:    outDir,
+		Artifacts: []packageArtifact{},
+	}
+	fileVersion := strings.TrimPrefix(releaseVersion, "v")
+
+	for _, platform := range platforms {
+		goos, goarch, _ := strings.Cut(platform, "/")
+		exe := ""
+		if goos == "windows" {
+			exe = ".exe"
+		}
+		base := name
+		if fileVersion != "" {
+			base += "_" + fileVersion
+		}
+		base += "_" + goos + "_" + goarch
+
+		var file string
+		var err error
+		switch archive {
+		case "tar.gz":
+			file = base + ".tar.gz"
+			err = writeTarGz(filepath.Join(outDir, file), binaries[platform], name+exe, includes)
+		case "zip":
+			file = base + ".zip"
+			err = writeZip(filepath.Join(outDir, file), binaries[platform], name+exe, includes)
+		default:
+			file = base + exe
+			err = copyFile(filepath.Join(outDir, file), binaries[platform], 0755)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to package %s: %w", platform, err)
+		}
+
+		sum, size, err := sha256File(filepath.Join(outDir, file))
+		if err != nil {
+			return err
+		}
+		manifest.Artifacts = append(manifest.Artifacts, packageArtifact{
+			Platform: platform,
+			OS:       goos,
+			Arch:     goarch,
+			Binary:   binaries[platform],
+			File:     file,
+			Size:     size,
+			SHA256:   sum,
+		})
+	}
+
+	if checksums {
+		manifest.Checksums = name + "_checksums.txt"
+		if fileVersion != "" {
+			manifest.Checksums = name + "_" + fileVersion + "_checksums.txt"
+		}
+		sorted := append([]packageArtifact(nil), manifest.Artifacts...)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i].File < sorted[j].File })
+		var b strings.Builder
+		for _, a := range sorted {
+			fmt.Fprintf(&b, "%s  %s\n", a.SHA256, a.File)
+		}
+		if err := os.WriteFile(filepath.Join(outDir, manifest.Checksums), []byte(b.String()), 0644); err != nil {
+			return fmt.Errorf("failed to write checksums: %w", err)
+		}
+	}
+
+	data, _ := json.MarshalIndent(manifest, "", "  ")
+	if err := os.WriteFile(filepath.Join(outDir, "manifest.json"), append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+
+	if format == output.FormatJSON {
+		output.PrintSuccess(format, manifest)
+		return nil
+	}
+
+	title := name
+	if releaseVersion != "" {
+		title += " " + releaseVersion
+	}
+	fmt.Printf("Packaged %s into %s\n", output.Accent(title), outDir)
+	for _, a := range manifest.Artifacts {
+		fmt.Printf("  %s %s  %s\n", output.OK(fmt.Sprintf("%-16s", a.Platform)), a.File, output.Muted(formatBytes(a.Size)))
+	}
+	if manifest.Checksums != "" {
+		fmt.Printf("  Checksums: %s\n", manifest.Checksums)
+	}
+	fmt.Println("  Manifest:  manifest.json")
+	return nil
+}
+
+// platformCandidates lists the paths a platform binary is looked for at
+func platformCandidates(bin, goos, goarch string) []string {
+	exe := ""
+	if goos == "windows" {
+		exe = ".exe"
+	}
+	dir, name := filepath.Dir(bin), filepath.Base(bin)
+	return []string{
+		bin + "-" + goos + "-" + goarch + exe,
+		bin + "_" + goos + "_" + goarch + exe,
+		filepath.Join(dir, goos+"_"+goarch, name+exe),
+		filepath.Join(dir, goos+"-"+goarch, name+exe),
+		filepath.Join(dir, name+"_"+goos+"_"+goarch, name+exe),
+	}
+}
+
+// findPlatformBinary returns the first candidate that is a regular file
+func findPlatformBinary(bin, goos, goarch string) string {
+	for _, path := range platformCandidates(bin, goos, goarch) {
+		if info, err := os.Stat(path); err == nil && info.Mode().IsRegular() {
+			return path
+		}
+	}
+	return ""
+}
+
+// gitVersion returns git describe --tags for the current directory, or ""
+func gitVersion() string {
+	out, err := exec.Command("git", "describe", "--tags", "--always").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// archiveEntry is a file added to an archive under name
+type archiveEntry struct {
+	path string
+	name string
+	mode os.FileMode
+}
+
+func archiveEntries(binary, binaryName string, includes []string) []archiveEntry {
+	entries := []archiveEntry{{path: binary, name: binaryName, mode: 0755}}
+	for _, include := range includes {
+		entries = append(entries, archiveEntry{path: include, name: filepath.Base(include), mode: 0644})
+	}
+	return entries
+}
+
+func writeTarGz(path, binary, binaryName string, includes []string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	gz := gzip.NewWriter(file)
+	tw := tar.NewWriter(gz)
+	for _, entry := range archiveEntries(binary, binaryName, includes) {
+		if err := addTarFile(tw, entry); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+func addTarFile(tw *tar.Writer, entry archiveEntry) error {
+	f, err := os.Open(entry.path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	header := &tar.Header{
+		Name:    entry.name,
+		Mode:    int64(entry.mode),
+		Size:    info.Size(),
+		ModTime: info.ModTime(),
+	}
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+	_, err = io.Copy(tw, f)
+	return err
+}
+
+func writeZip(path, binary, binaryName string, includes []string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	zw := zip.NewWriter(file)
+	for _, entry := range archiveEntries(binary, binaryName, includes) {
+		if err := addZipFile(zw, entry); err != nil {
+			return err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+func addZipFile(zw *zip.Writer, entry archiveEntry) error {
+	f, err := os.Open(entry.path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	header, err := zip.FileInfoHeader(info)
+	if err != nil {
+		return err
+	}
+	header.Name = entry.name
+	header.Method = zip.Deflate
+	header.SetMode(entry.mode)
+	w, err := zw.CreateHeader(header)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, f)
+	return err
+}
+
+func copyFile(dst, src string, mode os.FileMode) error {
+	// The binary may already have its release name
+	if absDst, _ := filepath.Abs(dst); absDst != "" {
+		if absSrc, _ := filepath.Abs(src); absSrc == absDst {
+			return nil
+		}
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	if _, err := io.Copy(out, in); err != nil {
+		return err
+	}
+	return out.Close()
+}
+
+// sha256File returns the hex SHA-256 digest and size of a file
+func sha256File(path string) (string, int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", 0, err
+	}
+	defer f.Close()
+	h := sha256.New()
+	n, err := io.Copy(h, f)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to hash %s: %w", path, err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), n, nil
+}
+
+// formatBytes formats a byte count with a binary unit
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,27 @@
+package release
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// releaseCmd represents the release command group
+var releaseCmd = &cobra.Command{
+	Use:   "release",
+	Short: "Release helpers for build outputs",
+	Long: `Turn build outputs into release artifacts: consistently named archives,
+checksums and a manifest, for any Go project's release script.
+
+Examples:
+  devkit release package --bin ./bin/devkit --checksums
+  devkit release package --bin ./dist/devkit --platforms linux/amd64,darwin/arm64 --archive tar.gz`,
+}
+
+// GetReleaseCmd returns the release command
+func GetReleaseCmd() *cobra.Command {
+	return releaseCmd
+}
+
+func init() {
+	// This will be called when the package is imported
+	// Commands will be added in their respective files
+}
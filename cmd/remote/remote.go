@@ -0,0 +1,194 @@
+package remote
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cobra"
+	"devkit/internal/errors"
+	"devkit/internal/output"
+	"devkit/internal/sshexec"
+)
+
+// remoteCmd represents the remote command
+var remoteCmd = &cobra.Command{
+	Use:   "remote --host [user@]host [flags] -- <command> [args...]",
+	Short: "Run a devkit command on other hosts over SSH",
+	Long: `Run a devkit command on one or more hosts over SSH and bring its output
+back, so that checks such as certificate expiry, disk usage or open ports
+can be run across a fleet and aggregated locally.
+
+The system ssh client is used, so ~/.ssh/config, the SSH agent and
+known_hosts apply as usual. ssh runs in batch mode: hosts must accept key
+authentication without prompting.
+
+On first use this devkit binary is copied to ~/.cache/devkit/devkit on the
+remote host (see --remote-path). It is reused while its SHA-256 matches the
+local binary and replaced otherwise. The local binary must match the remote
+platform; for other platforms pass a matching build with --binary. With
+--no-install the remote path is run as it is, e.g. a devkit already in PATH.
+
+Everything after the first argument is passed to the remote devkit, flags
+included; -- may be used to make this explicit. With --output json the
+remote command also runs with --output json and the per-host documents are
+merged into one. Otherwise the output is streamed, prefixed with the host
+when there are several.
+
+The exit status is the highest exit status of the remote commands, or 1 if
+a host could not be reached.
+
+Examples:
+  devkit remote --host deploy@web1 -- net port list
+  devkit remote --host web1 --host web2 -- net disk / --output json
+  devkit remote -H web1 -H web2 --output json -- net ssl expiry example.com
+  devkit remote --host pi@raspberry --binary dist/devkit-linux-arm64 -- net sysinfo
+  devkit remote --host db1 --no-install --remote-path devkit -- net open-ports`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runRemote,
+}
+
+// GetRemoteCmd returns the remote command
+func GetRemoteCmd() *cobra.Command {
+	return remoteCmd
+}
+
+func init() {
+	// Flags after the remote command belong to it
+	remoteCmd.Flags().SetInterspersed(false)
+
+	remoteCmd.Flags().StringArrayP("host", "H", nil, "Host to run on, as [user@]host (repeatable)")
+	sshexec.AddFlags(remoteCmd)
+	remoteCmd.Flags().IntP("jobs", "j", 4, "Number of hosts run in parallel")
+	remoteCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
+	remoteCmd.MarkFlagRequired("host")
+}
+
+func runRemote(cmd *cobra.Command, args []string) error {
+	hosts, _ := cmd.Flags().GetStringArray("host")
+	jobs, _ := cmd.Flags().GetInt("jobs")
+	outputFormat, _ := cmd.Flags().GetString("output")
+	format := output.OutputFormat(outputFormat)
+
+	opts, err := sshexec.FromCommand(cmd)
+	if err != nil {
+		return err
+	}
+	if args[0] == "remote" {
+		return fmt.Errorf("refusing to run devkit remote on the remote host")
+	}
+	if format == output.FormatJSON {
+		args = sshexec.JSONArgs(args)
+	}
+	cmd.SilenceUsage = true
+
+	var mu sync.Mutex
+	results := make([]sshexec.Result, len(hosts))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < max(jobs, 1) && w < len(hosts); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				var stdout, stderr io.Writer
+				var stdoutBuf, stderrBuf bytes.Buffer
+				switch {
+				case format == output.FormatJSON:
+					stdout, stderr = &stdoutBuf, &stderrBuf
+				case len(hosts) == 1:
+					stdout, stderr = os.Stdout, os.Stderr
+				default:
+					label := output.Accent("[" + hosts[i] + "]")
+					stdout = &prefixWriter{mu: &mu, w: os.Stdout, prefix: label}
+					stderr = &prefixWriter{mu: &mu, w: os.Stderr, prefix: label}
+				}
+				results[i] = opts.Exec(hosts[i], args, stdout, stderr)
+				if format == output.FormatJSON {
+					results[i].Decode(stdoutBuf.Bytes(), stderrBuf.String())
+				}
+				if f, ok := stdout.(*prefixWriter); ok {
+					f.Flush()
+					stderr.(*prefixWriter).Flush()
+				}
+			}
+		}()
+	}
+	for i := range hosts {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	failed, code := 0, 0
+	for _, r := range results {
+		if r.ExitCode != 0 {
+			failed++
+			code = max(code, r.ExitCode)
+		}
+	}
+
+	if format == output.FormatJSON {
+		output.PrintSuccess(format, map[string]interface{}{
+			"command": strings.Join(args, " "),
+			"count":   len(results),
+			"failed":  failed,
+			"results": results,
+		})
+	} else {
+		for _, r := range results {
+			if r.Error != "" {
+				fmt.Fprintln(os.Stderr, output.Fail(fmt.Sprintf("%s: %s", r.Host, r.Error)))
+			}
+		}
+		if len(results) > 1 {
+			fmt.Fprintf(os.Stderr, "\n%d hosts, %d failed\n", len(results), failed)
+		}
+	}
+
+	if failed == 0 {
+		return nil
+	}
+	// The remote commands and the summary above already reported the errors
+	cmd.SilenceErrors = true
+	return &errors.ExitError{Code: code, Err: fmt.Errorf("command failed on %d of %d hosts", failed, len(results))}
+}
+
+// prefixWriter writes complete lines prefixed with the host, so that the
+// output of hosts running in parallel does not interleave within a line
+type prefixWriter struct {
+	mu      *sync.Mutex
+	w       io.Writer
+	prefix  string
+	pending []byte
+}
+
+func (p *prefixWriter) Write(b []byte) (int, error) {
+	p.pending = append(p.pending, b...)
+	for {
+		i := bytes.IndexByte(p.pending, '\n')
+		if i < 0 {
+			break
+		}
+		p.writeLine(p.pending[:i+1])
+		p.pending = p.pending[i+1:]
+	}
+	return len(b), nil
+}
+
+// Flush writes a final line without a trailing newline
+func (p *prefixWriter) Flush() {
+	if len(p.pending) > 0 {
+		p.writeLine(append(p.pending, '\n'))
+		p.pending = nil
+	}
+}
+
+func (p *prefixWriter) writeLine(line []byte) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	fmt.Fprintf(p.w, "%s %s", p.prefix, line)
+}
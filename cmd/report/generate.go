@@ -0,0 +1,530 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+	"devkit/internal/check"
+	"devkit/internal/errors"
+	"devkit/internal/flatten"
+	"devkit/internal/i18n"
+	"devkit/internal/output"
+	"devkit/internal/utils"
+	"devkit/pkg/version"
+)
+
+// Check statuses, from the exit status of the check command
+const (
+	statusOK      = "ok"
+	statusWarning = "warning"
+	statusFailed  = "failed"
+)
+
+// generateCmd represents the report generate subcommand
+var generateCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "Run the checks of a spec file and render a report",
+	Long: `Run the devkit commands declared in a spec file and render a timestamped
+Markdown or HTML report with a summary table and the result of each check.
+
+Spec file format (YAML):
+  title: Production checks
+  checks:
+    - name: API certificate
+      run: net ssl expiry api.example.com --warn-days 21
+    - name: Homepage
+      run: net http https://example.com
+    - name: Mail servers
+      run: net dns lookup example.com --type MX
+    - name: Root disk
+      run: [net, disk, /]
+
+Each run is a devkit command line, as a string or a list of arguments; it
+is run with --output json by this devkit binary. A check passes when the
+command exits with 0, warns when it exits with 4 (e.g. a certificate close
+to expiry, see Exit Codes) and fails otherwise.
+
+The format follows the extension of --out (.html for HTML) unless --format
+is given; without --out the report is printed. With --output json the
+results are printed as JSON instead, and --out still writes the report.
+Reports are generated even when checks fail; use --fail-on to make the
+exit status reflect them, e.g. in a scheduled job.
+
+Examples:
+  devkit report generate --spec checks.yaml
+  devkit report generate --spec checks.yaml --out report.md
+  devkit report generate --spec checks.yaml --out report.html --jobs 8
+  devkit report generate --spec checks.yaml --out report.md --fail-on warning`,
+	Args: cobra.NoArgs,
+	RunE: runReportGenerate,
+}
+
+func init() {
+	reportCmd.AddCommand(generateCmd)
+
+	generateCmd.Flags().String("spec", "", "Spec file (YAML) declaring the checks (required)")
+	generateCmd.Flags().String("out", "", "Write the report to this file (default: stdout)")
+	generateCmd.Flags().String("format", "", "Report format: markdown, html (default from --out)")
+	generateCmd.Flags().IntP("jobs", "j", 4, "Number of checks run in parallel")
+	generateCmd.Flags().Duration("timeout", 2*time.Minute, "Timeout for each check")
+	generateCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
+	check.AddFailOnFlag(generateCmd, check.None)
+	generateCmd.MarkFlagRequired("spec")
+}
+
+// reportSpec is the structure of the spec file
+type reportSpec struct {
+	Title  string          `yaml:"title"`
+	Checks []reportSpecRun `yaml:"checks"`
+}
+
+// reportSpecRun is a check declared in the spec file
+type reportSpecRun struct {
+	Name string      `yaml:"name"`
+	Run  commandLine `yaml:"run"`
+}
+
+// commandLine is a command given as a string or a list of arguments
+type commandLine []string
+
+func (c *commandLine) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		args, err := utils.SplitArgs(node.Value)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", node.Line, err)
+		}
+		*c = args
+		return nil
+	}
+	var args []string
+	if err := node.Decode(&args); err != nil {
+		return err
+	}
+	*c = args
+	return nil
+}
+
+// reportCheck is the result of a check
+type reportCheck struct {
+	Name       string      `json:"name"`
+	Command    string      `json:"command"`
+	Status     string      `json:"status"`
+	ExitCode   int         `json:"exit_code"`
+	DurationMs int64       `json:"duration_ms"`
+	Data       interface{} `json:"data,omitempty"`
+	Output     string      `json:"output,omitempty"`
+	Error      string      `json:"error,omitempty"`
+}
+
+// reportDocument is the whole report
+type reportDocument struct {
+	Title       string        `json:"title"`
+	GeneratedAt time.Time     `json:"generated_at"`
+	Version     string        `json:"devkit_version"`
+	Passed      int           `json:"passed"`
+	Warnings    int           `json:"warnings"`
+	Failed      int           `json:"failed"`
+	Checks      []reportCheck `json:"checks"`
+}
+
+func runReportGenerate(cmd *cobra.Command, args []string) error {
+	specFile, _ := cmd.Flags().GetString("spec")
+	out, _ := cmd.Flags().GetString("out")
+	reportFormat, _ := cmd.Flags().GetString("format")
+	jobs, _ := cmd.Flags().GetInt("jobs")
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	outputFormat, _ := cmd.Flags().GetString("output")
+	format := output.OutputFormat(outputFormat)
+
+	if reportFormat == "" {
+		reportFormat = "markdown"
+		if ext := strings.ToLower(filepath.Ext(out)); ext == ".html" || ext == ".htm" {
+			reportFormat = "html"
+		}
+	}
+	if reportFormat == "md" {
+		reportFormat = "markdown"
+	}
+	if reportFormat != "markdown" && reportFormat != "html" {
+		return fmt.Errorf("unsupported report format: %s (supported: markdown, html)", reportFormat)
+	}
+
+	spec, err := loadReportSpec(specFile)
+	if err != nil {
+		return err
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("cannot locate the devkit binary: %w", err)
+	}
+	cmd.SilenceUsage = true
+
+	doc := reportDocument{
+		Title:       spec.Title,
+		GeneratedAt: time.Now(),
+		Version:     version.Version,
+		Checks:      make([]reportCheck, len(spec.Checks)),
+	}
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < max(jobs, 1) && w < len(spec.Checks); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				doc.Checks[i] = runReportCheck(exe, spec.Checks[i], timeout)
+			}
+		}()
+	}
+	for i := range spec.Checks {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	worst := check.None
+	for _, c := range doc.Checks {
+		switch c.Status {
+		case statusOK:
+			doc.Passed++
+		case statusWarning:
+			doc.Warnings++
+		default:
+			doc.Failed++
+		}
+		worst = max(worst, checkLevel(c.ExitCode))
+	}
+
+	var rendered bytes.Buffer
+	if reportFormat == "html" {
+		err = renderHTML(&rendered, doc)
+	} else {
+		renderMarkdown(&rendered, doc)
+	}
+	if err != nil {
+		return fmt.Errorf("render report: %w", err)
+	}
+
+	if out != "" {
+		if err := os.WriteFile(out, rendered.Bytes(), 0644); err != nil {
+			return fmt.Errorf("write report: %w", err)
+		}
+	}
+
+	switch {
+	case format == output.FormatJSON:
+		result := map[string]interface{}{"report": doc}
+		if out != "" {
+			result["out"] = out
+		}
+		output.PrintSuccess(format, result)
+	case out == "":
+		os.Stdout.Write(rendered.Bytes())
+	default:
+		summary := fmt.Sprintf("Wrote %s: %d checks, %d passed, %d warnings, %d failed", out, len(doc.Checks), doc.Passed, doc.Warnings, doc.Failed)
+		if doc.Failed > 0 {
+			fmt.Println(output.Fail(summary))
+		} else if doc.Warnings > 0 {
+			fmt.Println(output.Warn(summary))
+		} else {
+			fmt.Println(output.OK(summary))
+		}
+	}
+
+	return check.Result(cmd, worst, fmt.Sprintf("%d checks failed, %d warned", doc.Failed, doc.Warnings))
+}
+
+func loadReportSpec(path string) (*reportSpec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read spec file: %w", err)
+	}
+
+	var spec reportSpec
+	if err := yaml.Unmarshal(data, &spec); err != nil {
+		return nil, fmt.Errorf("invalid spec file: %w", err)
+	}
+
+	if len(spec.Checks) == 0 {
+		return nil, fmt.Errorf("no checks defined in %s", path)
+	}
+	if spec.Title == "" {
+		spec.Title = "devkit report"
+	}
+
+	for i := range spec.Checks {
+		c := &spec.Checks[i]
+		if len(c.Run) == 0 {
+			return nil, fmt.Errorf("check %d in %s has no run command", i+1, path)
+		}
+		if c.Run[0] == "devkit" {
+			c.Run = c.Run[1:]
+		}
+		if len(c.Run) > 0 && c.Run[0] == "report" {
+			return nil, fmt.Errorf("check %d in %s runs devkit report", i+1, path)
+		}
+		if c.Name == "" {
+			c.Name = strings.Join(c.Run, " ")
+		}
+	}
+	return &spec, nil
+}
+
+// runReportCheck runs one check with this devkit binary and collects its
+// JSON result
+func runReportCheck(exe string, spec reportSpecRun, timeout time.Duration) reportCheck {
+	c := reportCheck{Name: spec.Name, Command: "devkit " + strings.Join(spec.Run, " ")}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	proc := exec.CommandContext(ctx, exe, withJSONOutput(spec.Run)...)
+	var stdout, stderr bytes.Buffer
+	proc.Stdout = &stdout
+	proc.Stderr = &stderr
+
+	start := time.Now()
+	err := proc.Run()
+	c.DurationMs = time.Since(start).Milliseconds()
+
+	var result output.Result
+	if json.Unmarshal(stdout.Bytes(), &result) == nil {
+		c.Data = result.Data
+		c.Error = result.Error
+	} else if s := strings.TrimSpace(stdout.String()); s != "" {
+		c.Output = s
+	}
+
+	var exitErr *exec.ExitError
+	switch {
+	case ctx.Err() == context.DeadlineExceeded:
+		c.ExitCode = errors.ExitFailure
+		c.Error = fmt.Sprintf("timed out after %s", timeout)
+	case err == nil:
+	case stderrors.As(err, &exitErr):
+		c.ExitCode = exitErr.ExitCode()
+	default:
+		c.ExitCode = errors.ExitFailure
+		c.Error = err.Error()
+	}
+	if c.ExitCode != 0 && c.Error == "" {
+		c.Error = errorLine(stderr.String())
+	}
+	if c.ExitCode != 0 && c.Error == "" {
+		c.Error = fmt.Sprintf("exit status %d", c.ExitCode)
+	}
+
+	switch c.ExitCode {
+	case 0:
+		c.Status = statusOK
+	case errors.ExitWarning:
+		c.Status = statusWarning
+	default:
+		c.Status = statusFailed
+	}
+	return c
+}
+
+// errorLine returns the error message a devkit command printed to stderr,
+// without the usage that may follow it
+func errorLine(stderr string) string {
+	prefix := i18n.T("error.prefix")
+	for _, line := range strings.Split(stderr, "\n") {
+		if msg, ok := strings.CutPrefix(strings.TrimSpace(line), prefix); ok {
+			return strings.TrimSpace(msg)
+		}
+	}
+	return ""
+}
+
+// withJSONOutput adds --output json unless args already choose a format
+func withJSONOutput(args []string) []string {
+	for _, arg := range args {
+		if arg == "-o" || arg == "--output" || strings.HasPrefix(arg, "--output=") || strings.HasPrefix(arg, "-o=") {
+			return args
+		}
+	}
+	return append(append([]string(nil), args...), "--output", "json")
+}
+
+// checkLevel maps the exit status of a check to a --fail-on level; checks
+// that could not run count as invalid
+func checkLevel(code int) check.Level {
+	switch code {
+	case errors.ExitOK:
+		return check.None
+	case errors.ExitWarning:
+		return check.Warning
+	case errors.ExitExpired:
+		return check.Expired
+	}
+	return check.Invalid
+}
+
+// reportTable is the tabular form of a check's data
+type reportTable struct {
+	Columns []string
+	Rows    [][]string
+}
+
+// checkTable lays out the data of a check: a row per item when the data
+// holds a list of objects, and field/value pairs otherwise
+func checkTable(c reportCheck) *reportTable {
+	data, ok := c.Data.(map[string]interface{})
+	if !ok {
+		if c.Data == nil {
+			return nil
+		}
+		return &reportTable{Columns: []string{"Value"}, Rows: [][]string{{flatten.Cell(c.Data)}}}
+	}
+
+	if items := flatten.Items(data, ""); items != nil {
+		values := make([]map[string]string, len(items))
+		for i, item := range items {
+			values[i] = flatten.Object(item)
+		}
+		t := &reportTable{Columns: flatten.Keys(values...)}
+		for _, v := range values {
+			row := make([]string, len(t.Columns))
+			for j, col := range t.Columns {
+				row[j] = v[col]
+			}
+			t.Rows = append(t.Rows, row)
+		}
+		return t
+	}
+
+	values := flatten.Object(data)
+	t := &reportTable{Columns: []string{"Field", "Value"}}
+	for _, k := range flatten.Keys(values) {
+		t.Rows = append(t.Rows, []string{k, values[k]})
+	}
+	return t
+}
+
+// statusLabels are the status cells of the report, with an emoji that
+// wikis and chat tools render
+var statusLabels = map[string]string{
+	statusOK:      "✅ OK",
+	statusWarning: "⚠️ Warning",
+	statusFailed:  "❌ Failed",
+}
+
+// renderMarkdown writes the report as GitHub-flavored Markdown
+func renderMarkdown(w io.Writer, doc reportDocument) {
+	fmt.Fprintf(w, "# %s\n\n", doc.Title)
+	fmt.Fprintf(w, "Generated %s by devkit %s. %d checks: %d passed, %d warnings, %d failed.\n\n",
+		doc.GeneratedAt.Format("2006-01-02 15:04:05 MST"), doc.Version, len(doc.Checks), doc.Passed, doc.Warnings, doc.Failed)
+
+	fmt.Fprintln(w, "| Check | Status | Duration | Details |")
+	fmt.Fprintln(w, "|---|---|---|---|")
+	for _, c := range doc.Checks {
+		fmt.Fprintf(w, "| %s | %s | %s | %s |\n", markdownCell(c.Name), statusLabels[c.Status],
+			formatDuration(c.DurationMs), markdownCell(c.Error))
+	}
+
+	for _, c := range doc.Checks {
+		fmt.Fprintf(w, "\n## %s\n\n", c.Name)
+		fmt.Fprintf(w, "%s · `%s` · %s\n", statusLabels[c.Status], c.Command, formatDuration(c.DurationMs))
+		if c.Error != "" {
+			fmt.Fprintf(w, "\n> %s\n", c.Error)
+		}
+		if t := checkTable(c); t != nil {
+			fmt.Fprintf(w, "\n| %s |\n", strings.Join(t.Columns, " | "))
+			fmt.Fprintf(w, "|%s\n", strings.Repeat("---|", len(t.Columns)))
+			for _, row := range t.Rows {
+				cells := make([]string, len(row))
+				for i, cell := range row {
+					cells[i] = markdownCell(cell)
+				}
+				fmt.Fprintf(w, "| %s |\n", strings.Join(cells, " | "))
+			}
+		}
+		if c.Output != "" {
+			fmt.Fprintf(w, "\n```\n%s\n```\n", c.Output)
+		}
+	}
+}
+
+// markdownCell escapes a value for a Markdown table cell
+func markdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// formatDuration renders a check duration
+func formatDuration(ms int64) string {
+	if ms < 1000 {
+		return fmt.Sprintf("%dms", ms)
+	}
+	return fmt.Sprintf("%.1fs", float64(ms)/1000)
+}
+
+// htmlReport is the HTML layout of the report
+var htmlReport = template.Must(template.New("report").Funcs(template.FuncMap{
+	"status":   func(s string) string { return statusLabels[s] },
+	"duration": formatDuration,
+	"table":    checkTable,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em; color: #1f2328; }
+table { border-collapse: collapse; margin: 0.5em 0 1.5em; }
+th, td { border: 1px solid #d0d7de; padding: 4px 10px; text-align: left; vertical-align: top; }
+th { background: #f6f8fa; }
+code, pre { font-family: ui-monospace, Menlo, Consolas, monospace; }
+.ok { color: #1a7f37; } .warning { color: #9a6700; } .failed { color: #cf222e; }
+.meta { color: #656d76; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p class="meta">Generated {{.GeneratedAt.Format "2006-01-02 15:04:05 MST"}} by devkit {{.Version}}.
+{{len .Checks}} checks: {{.Passed}} passed, {{.Warnings}} warnings, {{.Failed}} failed.</p>
+<table>
+<tr><th>Check</th><th>Status</th><th>Duration</th><th>Details</th></tr>
+{{- range .Checks}}
+<tr><td>{{.Name}}</td><td class="{{.Status}}">{{status .Status}}</td><td>{{duration .DurationMs}}</td><td>{{.Error}}</td></tr>
+{{- end}}
+</table>
+{{- range .Checks}}
+<h2>{{.Name}}</h2>
+<p><span class="{{.Status}}">{{status .Status}}</span> · <code>{{.Command}}</code> · {{duration .DurationMs}}</p>
+{{- if .Error}}
+<blockquote>{{.Error}}</blockquote>
+{{- end}}
+{{- with table .}}
+<table>
+<tr>{{range .Columns}}<th>{{.}}</th>{{end}}</tr>
+{{- range .Rows}}
+<tr>{{range .}}<td>{{.}}</td>{{end}}</tr>
+{{- end}}
+</table>
+{{- end}}
+{{- if .Output}}
+<pre>{{.Output}}</pre>
+{{- end}}
+{{- end}}
+</body>
+</html>
+`))
+
+// renderHTML writes the report as a standalone HTML page
+func renderHTML(w io.Writer, doc reportDocument) error {
+	return htmlReport.Execute(w, doc)
+}
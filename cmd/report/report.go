@@ -0,0 +1,28 @@
+package report
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// reportCmd represents the report command group
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Generate reports from devkit checks",
+	Long: `Run a declared set of devkit checks and render the results as a
+Markdown or HTML report, e.g. from a scheduled job, for a wiki page or a
+chat channel.
+
+Examples:
+  devkit report generate --spec checks.yaml --out report.md
+  devkit report generate --spec checks.yaml --out report.html`,
+}
+
+// GetReportCmd returns the report command
+func GetReportCmd() *cobra.Command {
+	return reportCmd
+}
+
+func init() {
+	// This will be called when the package is imported
+	// Commands will be added in their respective files
+}
@@ -3,19 +3,57 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
+	"devkit/cmd/cache"
 	"devkit/cmd/dev"
+	"devkit/cmd/doctor"
 	"devkit/cmd/file"
+	"devkit/cmd/fleet"
+	"devkit/cmd/git"
 	"devkit/cmd/net"
+	"devkit/cmd/release"
+	"devkit/cmd/remote"
+	"devkit/cmd/report"
+	"devkit/cmd/schema"
+	"devkit/cmd/stats"
+	"devkit/cmd/undo"
+	workspacecmd "devkit/cmd/workspace"
+	"devkit/internal/errors"
+	"devkit/internal/expand"
+	"devkit/internal/history"
+	"devkit/internal/i18n"
+	"devkit/internal/matcher"
+	"devkit/internal/output"
+	"devkit/internal/profile"
+	"devkit/internal/safety"
+	"devkit/internal/workspace"
 	"devkit/pkg/version"
 )
 
 var (
-	cfgFile string
-	verbose bool
-	quiet   bool
+	cfgFile  string
+	verbose  bool
+	quiet    bool
+	lang     string
+	yes      bool
+	noCache  bool
+	noExpand bool
+	theme    string
+	logFile  string
+	tee      bool
+	profiled bool
+)
+
+// Configuration keys for --log-file rotation
+const (
+	keyLogMaxSize  = "log.max_size"  // rotate the log before it grows past this size (default 10MB)
+	keyLogMaxFiles = "log.max_files" // rotated logs kept (default 5)
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -30,33 +68,213 @@ and time/date manipulation.
 Built with Go, DevKit is distributed as a single binary and works
 cross-platform.`,
 	Version: version.Version,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := applyTheme(); err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+		// --yes confirms destructive changes, so it is only taken from the
+		// flag or DEVKIT_YES; AutomaticEnv would also accept a stray YES
+		assumeYes, _ := strconv.ParseBool(os.Getenv("DEVKIT_YES"))
+		viper.Set(safety.KeyYes, yes || assumeYes)
+		// Substitute {{env.NAME}} and {{config.key}} in arguments and flags
+		if err := expand.Command(cmd, args); err != nil {
+			return err
+		}
+		if err := startLog(); err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+		if profiled {
+			profile.Start()
+		}
+		return nil
+	},
 }
 
+// usageTemplate is cobra's default usage template with localized headings
+const usageTemplate = `{{T "help.usage"}}{{if .Runnable}}
+  {{.UseLine}}{{end}}{{if .HasAvailableSubCommands}}
+  {{.CommandPath}} [command]{{end}}{{if gt (len .Aliases) 0}}
+
+{{T "help.aliases"}}
+  {{.NameAndAliases}}{{end}}{{if .HasExample}}
+
+{{T "help.examples"}}
+{{.Example}}{{end}}{{if .HasAvailableSubCommands}}{{$cmds := .Commands}}{{if eq (len .Groups) 0}}
+
+{{T "help.available_commands"}}{{range $cmds}}{{if (or .IsAvailableCommand (eq .Name "help"))}}
+  {{rpad .Name .NamePadding }} {{.Short}}{{end}}{{end}}{{else}}{{range $group := .Groups}}
+
+{{.Title}}{{range $cmds}}{{if (and (eq .GroupID $group.ID) (or .IsAvailableCommand (eq .Name "help")))}}
+  {{rpad .Name .NamePadding }} {{.Short}}{{end}}{{end}}{{end}}{{if not .AllChildCommandsHaveGroup}}
+
+{{T "help.additional_commands"}}{{range $cmds}}{{if (and (eq .GroupID "") (or .IsAvailableCommand (eq .Name "help")))}}
+  {{rpad .Name .NamePadding }} {{.Short}}{{end}}{{end}}{{end}}{{end}}{{end}}{{if .HasAvailableLocalFlags}}
+
+{{T "help.flags"}}
+{{.LocalFlags.FlagUsages | trimTrailingWhitespaces}}{{end}}{{if .HasAvailableInheritedFlags}}
+
+{{T "help.global_flags"}}
+{{.InheritedFlags.FlagUsages | trimTrailingWhitespaces}}{{end}}{{if .HasHelpSubCommands}}
+
+{{T "help.additional_topics"}}{{range .Commands}}{{if .IsAdditionalHelpTopicCommand}}
+  {{rpad .CommandPath .CommandPathPadding}} {{.Short}}{{end}}{{end}}{{end}}{{if .HasAvailableSubCommands}}
+
+{{T "help.more_info" .CommandPath}}{{end}}
+`
+
 // Execute adds all child commands to the root command and sets flags appropriately.
 func Execute() error {
-	return rootCmd.Execute()
+	// The language must be known before cobra renders help or errors,
+	// so --lang is read ahead of flag parsing
+	args := os.Args[1:]
+	i18n.SetLanguage(i18n.Detect(flagFromArgs(args, "lang")))
+	rootCmd.InitDefaultHelpCmd()
+	rootCmd.InitDefaultCompletionCmd()
+
+	// Aliases decide which command runs, so the config (and its --config
+	// override) is loaded before cobra resolves the command
+	cfgFile = flagFromArgs(args, "config")
+	loadConfig()
+	loadAliases()
+	rootCmd.SetArgs(expandAlias(args))
+
+	localizeCommands(rootCmd)
+	rootCmd.SetErrPrefix(i18n.T("error.prefix"))
+
+	start := time.Now()
+	c, err := rootCmd.ExecuteC()
+	recordHistory(c, start, err)
+	if c != nil && profile.Started() {
+		format := "text"
+		if f := c.Flags().Lookup("output"); f != nil && f.Value.String() == "json" {
+			format = "json"
+		}
+		profile.Finish(strings.TrimPrefix(c.CommandPath(), rootCmd.Name()+" ")).Print(os.Stderr, format)
+	}
+	if c != nil {
+		logErr := output.FinishLog(strings.TrimPrefix(c.CommandPath(), rootCmd.Name()+" "), usedFlags(c), errors.ExitCode(err), err)
+		if logErr != nil {
+			fmt.Fprintln(os.Stderr, "Warning:", logErr)
+		}
+	}
+	return err
+}
+
+// recordHistory appends the invocation to the local history used by
+// devkit stats. Help, completion and bare root invocations are not recorded.
+func recordHistory(c *cobra.Command, start time.Time, err error) {
+	if c == nil || c == rootCmd || !c.Runnable() {
+		return
+	}
+	for p := c; p != nil; p = p.Parent() {
+		if p.Name() == "help" || p.Name() == "completion" || strings.HasPrefix(p.Name(), "__complete") {
+			return
+		}
+	}
+	if help := c.Flags().Lookup("help"); help != nil && help.Changed {
+		return
+	}
+
+	history.Record(history.Entry{
+		Time:       start,
+		Command:    strings.TrimPrefix(c.CommandPath(), rootCmd.Name()+" "),
+		Flags:      usedFlags(c),
+		DurationMs: time.Since(start).Milliseconds(),
+		Success:    err == nil,
+	})
+}
+
+// usedFlags returns the names of the flags given to c
+func usedFlags(c *cobra.Command) []string {
+	var flags []string
+	c.Flags().Visit(func(f *pflag.Flag) {
+		flags = append(flags, f.Name)
+	})
+	return flags
+}
+
+// startLog begins the --log-file capture of the command about to run
+func startLog() error {
+	if logFile == "" {
+		if tee {
+			return fmt.Errorf("--tee requires --log-file")
+		}
+		return nil
+	}
+	maxSize := int64(10 << 20)
+	if viper.IsSet(keyLogMaxSize) {
+		size, err := matcher.ParseSize(viper.GetString(keyLogMaxSize))
+		if err != nil {
+			return fmt.Errorf("invalid %s: %w", keyLogMaxSize, err)
+		}
+		maxSize = size
+	}
+	maxFiles := 5
+	if viper.IsSet(keyLogMaxFiles) {
+		maxFiles = viper.GetInt(keyLogMaxFiles)
+	}
+	return output.StartLog(output.LogOptions{Path: logFile, Tee: tee, MaxSize: maxSize, MaxFiles: maxFiles})
 }
 
 func init() {
-	cobra.OnInitialize(initConfig)
+	cobra.OnInitialize(reportConfig)
 
 	// Global flags
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.devkit.yaml)")
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "verbose output")
 	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "quiet mode (suppress non-error output)")
+	rootCmd.PersistentFlags().BoolVarP(&yes, "yes", "y", false, "assume yes for confirmation prompts (or set DEVKIT_YES=1)")
+	rootCmd.PersistentFlags().BoolVar(&noCache, "no-cache", false, "bypass the results cache (see cache.enabled)")
+	rootCmd.PersistentFlags().BoolVar(&noExpand, "no-expand", false, "do not substitute {{env.NAME}} and {{config.key}} in arguments")
+	rootCmd.PersistentFlags().StringVar(&lang, "lang", "", "message language: en, tr (default from DEVKIT_LANG or LANG)")
+	rootCmd.PersistentFlags().StringVar(&logFile, "log-file", "", "append a JSON record of the result to this file (rotated at log.max_size)")
+	rootCmd.PersistentFlags().BoolVar(&tee, "tee", false, "with --log-file, also write the text printed to stdout to the log")
+	rootCmd.PersistentFlags().BoolVar(&profiled, "profile", false, "report wall and CPU time, peak memory, I/O and network requests on stderr (as JSON with -o json)")
+	rootCmd.PersistentFlags().StringVar(&theme, "theme", "", "output theme: "+strings.Join(output.ThemeNames(), ", ")+" (default from theme in config)")
 
 	// Bind flags to viper
 	viper.BindPFlag("verbose", rootCmd.PersistentFlags().Lookup("verbose"))
 	viper.BindPFlag("quiet", rootCmd.PersistentFlags().Lookup("quiet"))
+	viper.BindPFlag("lang", rootCmd.PersistentFlags().Lookup("lang"))
+	viper.BindPFlag("no-cache", rootCmd.PersistentFlags().Lookup("no-cache"))
+	viper.BindPFlag(expand.KeyDisabled, rootCmd.PersistentFlags().Lookup("no-expand"))
+
+	cobra.AddTemplateFunc("T", i18n.T)
+	rootCmd.SetUsageTemplate(usageTemplate)
+
+	// Show the banner above the root help
+	defaultHelp := rootCmd.HelpFunc()
+	rootCmd.SetHelpFunc(func(c *cobra.Command, args []string) {
+		if c == rootCmd && applyTheme() == nil {
+			fmt.Fprint(c.OutOrStdout(), output.Banner())
+		}
+		defaultHelp(c, args)
+	})
 
 	// Add subcommands
 	rootCmd.AddCommand(dev.GetDevCmd())
 	rootCmd.AddCommand(file.GetFileCmd())
 	rootCmd.AddCommand(net.GetNetCmd())
+	rootCmd.AddCommand(doctor.GetDoctorCmd())
+	rootCmd.AddCommand(schema.GetSchemaCmd())
+	rootCmd.AddCommand(undo.GetUndoCmd())
+	rootCmd.AddCommand(cache.GetCacheCmd())
+	rootCmd.AddCommand(stats.GetStatsCmd())
+	rootCmd.AddCommand(workspacecmd.GetWorkspaceCmd())
+	rootCmd.AddCommand(remote.GetRemoteCmd())
+	rootCmd.AddCommand(fleet.GetFleetCmd())
+	rootCmd.AddCommand(report.GetReportCmd())
+	rootCmd.AddCommand(release.GetReleaseCmd())
+	rootCmd.AddCommand(git.GetGitCmd())
 }
 
-// initConfig reads in config file and ENV variables if set.
-func initConfig() {
+// activeWorkspace is the workspace detected by loadConfig, if any
+var activeWorkspace *workspace.Workspace
+
+// loadConfig reads in config file and ENV variables if set.
+func loadConfig() {
 	if cfgFile != "" {
 		// Use config file from the flag.
 		viper.SetConfigFile(cfgFile)
@@ -75,11 +293,60 @@ func initConfig() {
 	viper.AutomaticEnv() // read in environment variables that match
 
 	// If a config file is found, read it in.
-	if err := viper.ReadInConfig(); err == nil {
-		if verbose {
-			fmt.Fprintln(os.Stderr, "Using config file:", viper.ConfigFileUsed())
+	viper.ReadInConfig()
+
+	// Settings in the workspace's .devkit.yaml override the global ones
+	ws, err := workspace.Detect()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Warning:", err)
+		return
+	}
+	if ws != nil && ws.File != viper.ConfigFileUsed() {
+		project := viper.New()
+		project.SetConfigFile(ws.File)
+		if err := project.ReadInConfig(); err == nil {
+			viper.MergeConfigMap(project.AllSettings())
 		}
 	}
+	activeWorkspace = ws
+}
+
+// reportConfig prints the config file and workspace in use with --verbose,
+// once the flags are parsed
+func reportConfig() {
+	if !verbose {
+		return
+	}
+	if file := viper.ConfigFileUsed(); file != "" {
+		fmt.Fprintln(os.Stderr, "Using config file:", file)
+	}
+	if ws := activeWorkspace; ws != nil {
+		fmt.Fprintf(os.Stderr, "Using workspace: %s (%s)\n", ws.Name, ws.Root)
+	}
+}
+
+// applyTheme activates the theme given with --theme, or else the one set in
+// the config file, either as a name or as a map with a name and color
+// overrides:
+//
+//	theme:
+//	  name: high-contrast
+//	  accent: magenta
+func applyTheme() error {
+	name := theme
+	var colors map[string]string
+	switch v := viper.Get("theme").(type) {
+	case string:
+		if name == "" {
+			name = v
+		}
+	case map[string]interface{}:
+		colors = viper.GetStringMapString("theme")
+		if name == "" {
+			name = colors["name"]
+		}
+	}
+	return output.SetTheme(name, colors)
 }
 
 // GetVerbose returns the verbose flag value
@@ -91,3 +358,38 @@ func GetVerbose() bool {
 func GetQuiet() bool {
 	return quiet
 }
+
+// flagFromArgs returns the value of the global flag --name from raw command
+// line arguments
+func flagFromArgs(args []string, name string) string {
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+		if strings.HasPrefix(arg, "--"+name+"=") {
+			return strings.TrimPrefix(arg, "--"+name+"=")
+		}
+		if arg == "--"+name && i+1 < len(args) {
+			return args[i+1]
+		}
+	}
+	return ""
+}
+
+// localizeCommands replaces short descriptions with their translation, if any,
+// and has the errors of the commands reported in the active language
+func localizeCommands(c *cobra.Command) {
+	path := strings.Fields(c.CommandPath())[1:]
+	key := strings.Join(append(append([]string{"cmd"}, path...), "short"), ".")
+	if i18n.Has(key) {
+		c.Short = i18n.T(key)
+	}
+	if run := c.RunE; run != nil {
+		c.RunE = func(cmd *cobra.Command, args []string) error {
+			return errors.Localize(run(cmd, args))
+		}
+	}
+	for _, sub := range c.Commands() {
+		localizeCommands(sub)
+	}
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"devkit/internal/schema"
+)
+
+// schemaExempt lists the commands with --output json that print something
+// other than the success/data envelope, and so have no schema
+var schemaExempt = map[string]string{
+	"file watch": "prints one JSON record per processed file",
+}
+
+// TestJSONOutputsHaveSchemas fails when a command accepts --output json but
+// has no published schema, so that outputs_test.go covers every document
+func TestJSONOutputsHaveSchemas(t *testing.T) {
+	commands := make(map[string]bool)
+	var walk func(c *cobra.Command)
+	walk = func(c *cobra.Command) {
+		for _, sub := range c.Commands() {
+			walk(sub)
+		}
+		path := strings.TrimPrefix(c.CommandPath(), rootCmd.Name()+" ")
+		commands[schema.Normalize(path)] = true
+
+		// file convert has -o for the output file
+		flag := c.Flag("output")
+		if !c.Runnable() || flag == nil || !strings.Contains(flag.Usage, "json") {
+			return
+		}
+		if _, ok := schemaExempt[path]; ok {
+			return
+		}
+		if _, err := schema.Get(path); err != nil {
+			t.Errorf("%s has --output json but no schema in internal/schema/v1", path)
+		}
+	}
+	walk(rootCmd)
+
+	for path := range schemaExempt {
+		if !commands[schema.Normalize(path)] {
+			t.Errorf("exempt command %s does not exist", path)
+		}
+	}
+	for _, name := range schema.Names() {
+		if !commands[name] {
+			t.Errorf("schema %s belongs to no command", name)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"devkit/internal/output"
+	"devkit/internal/schema"
+)
+
+// listCmd represents the list subcommand
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List commands with a published schema",
+	Long: `List all commands whose --output json structure has a published schema.
+
+Examples:
+  devkit schema list
+  devkit schema list --output json`,
+	RunE: runList,
+}
+
+func init() {
+	schemaCmd.AddCommand(listCmd)
+
+	listCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
+}
+
+func runList(cmd *cobra.Command, args []string) error {
+	outputFormat, _ := cmd.Flags().GetString("output")
+	format := output.OutputFormat(outputFormat)
+
+	names := schema.Names()
+	commands := make([]string, len(names))
+	for i, name := range names {
+		commands[i] = strings.ReplaceAll(name, ".", " ")
+	}
+
+	if format == output.FormatJSON {
+		output.PrintSuccess(format, map[string]interface{}{
+			"version":  schema.Version,
+			"count":    len(commands),
+			"commands": commands,
+		})
+	} else {
+		fmt.Printf("Published schemas (v%d):\n", schema.Version)
+		for _, command := range commands {
+			fmt.Printf("  devkit %s\n", command)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,44 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"devkit/internal/schema"
+)
+
+// printCmd represents the print subcommand
+var printCmd = &cobra.Command{
+	Use:   "print <command...>",
+	Short: "Print the JSON Schema of a command's output",
+	Long: `Print the JSON Schema describing the --output json document of a command.
+The command path can be given as separate words or joined with dots.
+
+Examples:
+  devkit schema print dev uuid
+  devkit schema print net.dns.lookup
+  devkit schema print file search > file-search.schema.json`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runPrint,
+}
+
+func init() {
+	schemaCmd.AddCommand(printCmd)
+}
+
+func runPrint(cmd *cobra.Command, args []string) error {
+	s, err := schema.Get(strings.Join(args, " "))
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode schema: %w", err)
+	}
+	fmt.Println(string(data))
+
+	return nil
+}
@@ -0,0 +1,28 @@
+package schema
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// schemaCmd represents the schema command group
+var schemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "JSON Schemas for --output json documents",
+	Long: `Versioned JSON Schemas describing the --output json structure of DevKit
+commands, so that scripts and CI pipelines can rely on stable field names.
+
+This command group includes utilities for:
+- Listing commands with a published schema
+- Printing the schema of a command
+- Validating a command's JSON output against its schema`,
+}
+
+// GetSchemaCmd returns the schema command
+func GetSchemaCmd() *cobra.Command {
+	return schemaCmd
+}
+
+func init() {
+	// This will be called when the package is imported
+	// Commands will be added in their respective files
+}
@@ -0,0 +1,155 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"devkit/internal/errors"
+	"devkit/internal/output"
+	"devkit/internal/schema"
+)
+
+// validateCmd represents the validate subcommand
+var validateCmd = &cobra.Command{
+	Use:   "validate <command...>",
+	Short: "Validate a command's JSON output against its schema",
+	Long: `Validate a --output json document against the published schema of a
+command. Exits with status 2 when the document does not match,
+which makes it usable as a compatibility check in CI.
+
+Use --all with a directory of captured outputs named after the command
+(e.g. dev.uuid.json) to check every published schema at once.
+
+Examples:
+  devkit dev uuid --output json | devkit schema validate dev uuid --stdin
+  devkit schema validate net dns lookup --file lookup.json
+  devkit schema validate --all testdata/outputs`,
+	RunE: runValidate,
+}
+
+func init() {
+	schemaCmd.AddCommand(validateCmd)
+
+	validateCmd.Flags().StringP("file", "f", "", "JSON document file")
+	validateCmd.Flags().Bool("stdin", false, "Read JSON document from stdin")
+	validateCmd.Flags().String("all", "", "Directory of captured outputs named <command>.json")
+	validateCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
+}
+
+func runValidate(cmd *cobra.Command, args []string) error {
+	fileFlag, _ := cmd.Flags().GetString("file")
+	stdinFlag, _ := cmd.Flags().GetBool("stdin")
+	allDir, _ := cmd.Flags().GetString("all")
+	outputFormat, _ := cmd.Flags().GetString("output")
+	format := output.OutputFormat(outputFormat)
+
+	type result struct {
+		Command string   `json:"command"`
+		Valid   bool     `json:"valid"`
+		Errors  []string `json:"errors,omitempty"`
+	}
+	var results []result
+
+	if allDir != "" {
+		for _, name := range schema.Names() {
+			data, err := os.ReadFile(allDir + string(os.PathSeparator) + name + ".json")
+			if os.IsNotExist(err) {
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("read file error: %w", err)
+			}
+			errs, err := validateDocument(name, data)
+			if err != nil {
+				return err
+			}
+			results = append(results, result{Command: strings.ReplaceAll(name, ".", " "), Valid: len(errs) == 0, Errors: errs})
+		}
+		if len(results) == 0 {
+			return fmt.Errorf("no captured outputs found in %s", allDir)
+		}
+	} else {
+		if len(args) == 0 {
+			return fmt.Errorf("specify a command or use --all")
+		}
+
+		var data []byte
+		var err error
+		if stdinFlag {
+			data, err = io.ReadAll(os.Stdin)
+			if err != nil {
+				return fmt.Errorf("read stdin error: %w", err)
+			}
+		} else if fileFlag != "" {
+			data, err = os.ReadFile(fileFlag)
+			if err != nil {
+				return fmt.Errorf("read file error: %w", err)
+			}
+		} else {
+			return fmt.Errorf("specify --file or --stdin")
+		}
+
+		command := strings.Join(args, " ")
+		errs, err := validateDocument(command, data)
+		if err != nil {
+			return err
+		}
+		results = append(results, result{Command: strings.ReplaceAll(schema.Normalize(command), ".", " "), Valid: len(errs) == 0, Errors: errs})
+	}
+
+	failed := 0
+	for _, r := range results {
+		if !r.Valid {
+			failed++
+		}
+	}
+
+	if format == output.FormatJSON {
+		output.PrintSuccess(format, map[string]interface{}{
+			"valid":   failed == 0,
+			"count":   len(results),
+			"failed":  failed,
+			"results": results,
+		})
+	} else {
+		for _, r := range results {
+			if r.Valid {
+				fmt.Println(output.OK(r.Command))
+				continue
+			}
+			fmt.Println(output.Fail(r.Command))
+			for _, e := range r.Errors {
+				fmt.Printf("    %s\n", e)
+			}
+		}
+	}
+
+	if failed > 0 {
+		cmd.SilenceUsage = true
+		return &errors.ExitError{
+			Code: errors.ExitInvalid,
+			Err:  fmt.Errorf("%d of %d documents do not match their schema", failed, len(results)),
+		}
+	}
+
+	return nil
+}
+
+// validateDocument decodes a JSON document and validates it against the schema of command
+func validateDocument(command string, data []byte) ([]string, error) {
+	s, err := schema.Get(command)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	return schema.Validate(s, doc), nil
+}
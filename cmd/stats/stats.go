@@ -0,0 +1,204 @@
+package stats
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+	"devkit/internal/history"
+	"devkit/internal/matcher"
+	"devkit/internal/output"
+)
+
+// statsCmd represents the stats command
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Local usage statistics from the command history",
+	Long: `Summarize the local command history: the most used commands, their
+average and slowest durations and how often they fail.
+
+Every invocation is recorded in ~/.devkit/history.jsonl with its command,
+the names of the flags used, the duration and whether it succeeded.
+Argument and flag values are never stored, and nothing is sent over the
+network. Set history.enabled to false in the config to stop recording;
+history.max_entries (default 5000) bounds the file.
+
+Examples:
+  devkit stats
+  devkit stats --since 7d --top 5
+  devkit stats --sort failures
+  devkit stats --output json
+  devkit stats --clear`,
+	Args: cobra.NoArgs,
+	RunE: runStats,
+}
+
+// GetStatsCmd returns the stats command
+func GetStatsCmd() *cobra.Command {
+	return statsCmd
+}
+
+func init() {
+	statsCmd.Flags().String("since", "", "Only include runs after a date or age (e.g. 2024-06-01, 30d)")
+	statsCmd.Flags().IntP("top", "n", 10, "Number of commands to show (0 for all)")
+	statsCmd.Flags().String("sort", "runs", "Sort by: runs, failures, duration")
+	statsCmd.Flags().Bool("clear", false, "Delete the recorded history")
+	statsCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
+}
+
+// commandStats aggregates the runs of one command
+type commandStats struct {
+	Command     string  `json:"command"`
+	Runs        int     `json:"runs"`
+	Failures    int     `json:"failures"`
+	FailureRate float64 `json:"failure_rate"`
+	AvgMs       int64   `json:"avg_ms"`
+	MaxMs       int64   `json:"max_ms"`
+	totalMs     int64
+}
+
+func runStats(cmd *cobra.Command, args []string) error {
+	since, _ := cmd.Flags().GetString("since")
+	top, _ := cmd.Flags().GetInt("top")
+	sortBy, _ := cmd.Flags().GetString("sort")
+	clear, _ := cmd.Flags().GetBool("clear")
+	outputFormat, _ := cmd.Flags().GetString("output")
+	format := output.OutputFormat(outputFormat)
+
+	if clear {
+		count, err := history.Clear()
+		if err != nil {
+			return err
+		}
+		if format == output.FormatJSON {
+			output.PrintSuccess(format, map[string]interface{}{"cleared": count})
+		} else {
+			fmt.Printf("Deleted %d history entries\n", count)
+		}
+		return nil
+	}
+
+	var sinceTime time.Time
+	if since != "" {
+		var err error
+		if sinceTime, err = matcher.ParseTime(since); err != nil {
+			return err
+		}
+	}
+
+	entries, err := history.Load(sinceTime)
+	if err != nil {
+		return err
+	}
+
+	byCommand := make(map[string]*commandStats)
+	failures := 0
+	for _, e := range entries {
+		s, ok := byCommand[e.Command]
+		if !ok {
+			s = &commandStats{Command: e.Command}
+			byCommand[e.Command] = s
+		}
+		s.Runs++
+		s.totalMs += e.DurationMs
+		if e.DurationMs > s.MaxMs {
+			s.MaxMs = e.DurationMs
+		}
+		if !e.Success {
+			s.Failures++
+			failures++
+		}
+	}
+
+	commands := make([]*commandStats, 0, len(byCommand))
+	for _, s := range byCommand {
+		s.AvgMs = s.totalMs / int64(s.Runs)
+		s.FailureRate = float64(s.Failures) / float64(s.Runs)
+		commands = append(commands, s)
+	}
+	if err := sortStats(commands, sortBy); err != nil {
+		return err
+	}
+	if top > 0 && len(commands) > top {
+		commands = commands[:top]
+	}
+
+	failureRate := 0.0
+	if len(entries) > 0 {
+		failureRate = float64(failures) / float64(len(entries))
+	}
+
+	if format == output.FormatJSON {
+		result := map[string]interface{}{
+			"runs":         len(entries),
+			"failures":     failures,
+			"failure_rate": failureRate,
+			"commands":     commands,
+		}
+		if len(entries) > 0 {
+			result["first"] = entries[0].Time
+			result["last"] = entries[len(entries)-1].Time
+		}
+		output.PrintSuccess(format, result)
+		return nil
+	}
+
+	if len(entries) == 0 {
+		if !history.Enabled() {
+			fmt.Println("No history recorded (history.enabled is false)")
+		} else {
+			fmt.Println("No history recorded yet")
+		}
+		return nil
+	}
+
+	fmt.Printf("%d runs from %s to %s, %d failed (%.1f%%)\n\n", len(entries),
+		entries[0].Time.Format("2006-01-02"), entries[len(entries)-1].Time.Format("2006-01-02"),
+		failures, failureRate*100)
+	fmt.Printf("%-32s %6s %8s %9s %9s\n", "COMMAND", "RUNS", "FAILED", "AVG", "MAX")
+	for _, s := range commands {
+		fmt.Printf("%-32s %6d %7.1f%% %9s %9s\n", s.Command, s.Runs, s.FailureRate*100,
+			formatDuration(s.AvgMs), formatDuration(s.MaxMs))
+	}
+
+	return nil
+}
+
+func sortStats(commands []*commandStats, sortBy string) error {
+	var less func(a, b *commandStats) bool
+	switch sortBy {
+	case "runs":
+		less = func(a, b *commandStats) bool { return a.Runs > b.Runs }
+	case "failures":
+		less = func(a, b *commandStats) bool {
+			if a.FailureRate != b.FailureRate {
+				return a.FailureRate > b.FailureRate
+			}
+			return a.Failures > b.Failures
+		}
+	case "duration":
+		less = func(a, b *commandStats) bool { return a.AvgMs > b.AvgMs }
+	default:
+		return fmt.Errorf("invalid sort: %s (supported: runs, failures, duration)", sortBy)
+	}
+
+	sort.Slice(commands, func(i, j int) bool {
+		if less(commands[i], commands[j]) {
+			return true
+		}
+		if less(commands[j], commands[i]) {
+			return false
+		}
+		return commands[i].Command < commands[j].Command
+	})
+	return nil
+}
+
+func formatDuration(ms int64) string {
+	d := time.Duration(ms) * time.Millisecond
+	if d < time.Second {
+		return fmt.Sprintf("%dms", ms)
+	}
+	return d.Round(100 * time.Millisecond).String()
+}
@@ -0,0 +1,63 @@
+package undo
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"devkit/internal/output"
+	"devkit/internal/undo"
+)
+
+// listCmd represents the list subcommand
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List operations in the undo journal",
+	Long: `List journaled operations that can be reverted, newest first.
+
+Examples:
+  devkit undo list
+  devkit undo list --output json`,
+	RunE: runList,
+}
+
+func init() {
+	undoCmd.AddCommand(listCmd)
+
+	listCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
+}
+
+func runList(cmd *cobra.Command, args []string) error {
+	outputFormat, _ := cmd.Flags().GetString("output")
+	format := output.OutputFormat(outputFormat)
+
+	ops, err := undo.List()
+	if err != nil {
+		return err
+	}
+
+	if format == output.FormatJSON {
+		operations := make([]map[string]interface{}, 0, len(ops))
+		for _, op := range ops {
+			operations = append(operations, map[string]interface{}{
+				"id":         op.ID,
+				"command":    op.Command,
+				"created_at": op.CreatedAt,
+				"entries":    op.Entries,
+			})
+		}
+		output.PrintSuccess(format, map[string]interface{}{
+			"operations": operations,
+			"count":      len(operations),
+		})
+	} else {
+		if len(ops) == 0 {
+			fmt.Println("Nothing to undo")
+			return nil
+		}
+		for _, op := range ops {
+			fmt.Printf("%s  %s  %s (%d changes)\n", op.ID, op.CreatedAt.Format("2006-01-02 15:04:05"), op.Command, len(op.Entries))
+		}
+	}
+
+	return nil
+}
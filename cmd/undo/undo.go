@@ -0,0 +1,85 @@
+package undo
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"devkit/internal/output"
+	"devkit/internal/undo"
+)
+
+// undoCmd represents the undo command group
+var undoCmd = &cobra.Command{
+	Use:   "undo [last|operation-id]",
+	Short: "Revert changes made by file-modifying commands",
+	Long: `Revert changes recorded in the undo journal.
+
+Commands that modify files (file find-replace, file rename, file dedupe,
+dev env set, dev env unset and dev toml set --in-place) store the original
+content and paths in ~/.devkit/undo. The most recent operations are kept (undo.keep, default 20).
+
+Files changed again after the operation are not overwritten unless --force
+is given.
+
+Examples:
+  devkit undo last
+  devkit undo 20240101-120000-a1b2
+  devkit undo list
+  devkit undo last --force`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runUndo,
+}
+
+// GetUndoCmd returns the undo command
+func GetUndoCmd() *cobra.Command {
+	return undoCmd
+}
+
+func init() {
+	undoCmd.Flags().Bool("force", false, "Revert even if files changed after the operation")
+	undoCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
+}
+
+func runUndo(cmd *cobra.Command, args []string) error {
+	force, _ := cmd.Flags().GetBool("force")
+	outputFormat, _ := cmd.Flags().GetString("output")
+	format := output.OutputFormat(outputFormat)
+	cmd.SilenceUsage = true
+
+	var op *undo.Operation
+	var err error
+	if len(args) == 0 || args[0] == "last" {
+		op, err = undo.Last()
+	} else {
+		op, err = undo.Load(args[0])
+	}
+	if err != nil {
+		return err
+	}
+
+	if problems := op.Check(); len(problems) > 0 && !force {
+		return fmt.Errorf("cannot undo %s (use --force to override):\n  %s", op.ID, strings.Join(problems, "\n  "))
+	}
+
+	restored, err := op.Revert()
+	if err != nil {
+		return fmt.Errorf("undo of %s incomplete: %w", op.ID, err)
+	}
+
+	if format == output.FormatJSON {
+		output.PrintSuccess(format, map[string]interface{}{
+			"id":       op.ID,
+			"command":  op.Command,
+			"restored": restored,
+			"count":    len(restored),
+		})
+	} else {
+		fmt.Printf("Reverted %s (%s)\n", op.ID, op.Command)
+		for _, path := range restored {
+			fmt.Printf("  Restored: %s\n", path)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,61 @@
+package workspace
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"devkit/internal/output"
+	"devkit/internal/workspace"
+)
+
+// listCmd represents the list subcommand
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List registered workspaces",
+	Long: `List the workspaces registered with "workspace use". The active one is
+marked with *.
+
+Examples:
+  devkit workspace list
+  devkit workspace list --output json`,
+	Args: cobra.NoArgs,
+	RunE: runList,
+}
+
+func init() {
+	workspaceCmd.AddCommand(listCmd)
+
+	listCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
+}
+
+func runList(cmd *cobra.Command, args []string) error {
+	outputFormat, _ := cmd.Flags().GetString("output")
+	format := output.OutputFormat(outputFormat)
+
+	list, err := workspace.List()
+	if err != nil {
+		return err
+	}
+
+	if format == output.FormatJSON {
+		output.PrintSuccess(format, map[string]interface{}{
+			"workspaces": list,
+			"count":      len(list),
+		})
+		return nil
+	}
+
+	if len(list) == 0 {
+		fmt.Println("No workspaces registered")
+		return nil
+	}
+	for _, ws := range list {
+		marker := " "
+		if ws.Active {
+			marker = "*"
+		}
+		fmt.Printf("%s %-20s %s\n", marker, ws.Name, ws.Root)
+	}
+
+	return nil
+}
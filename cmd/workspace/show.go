@@ -0,0 +1,71 @@
+package workspace
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"devkit/internal/output"
+	"devkit/internal/workspace"
+)
+
+// showCmd represents the show subcommand
+var showCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show the current workspace and its defaults",
+	Long: `Show the workspace that applies in the current directory and the
+defaults it provides.
+
+Examples:
+  devkit workspace show
+  devkit workspace show --output json`,
+	Args: cobra.NoArgs,
+	RunE: runShow,
+}
+
+func init() {
+	workspaceCmd.AddCommand(showCmd)
+
+	showCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
+}
+
+func runShow(cmd *cobra.Command, args []string) error {
+	outputFormat, _ := cmd.Flags().GetString("output")
+	format := output.OutputFormat(outputFormat)
+
+	ws := workspace.Current()
+
+	if format == output.FormatJSON {
+		output.PrintSuccess(format, map[string]interface{}{
+			"workspace": ws,
+		})
+		return nil
+	}
+
+	if ws == nil {
+		fmt.Println("No workspace (run 'devkit workspace use <name>' in a project)")
+		return nil
+	}
+
+	source := "detected"
+	if ws.Active {
+		source = "active"
+	}
+	fmt.Printf("Workspace: %s (%s)\n", ws.Name, source)
+	fmt.Printf("Root:      %s\n", ws.Root)
+	fmt.Printf("File:      %s\n", ws.File)
+	printSetting("Path", ws.Path)
+	printSetting("Env file", ws.EnvFile)
+	printSetting("Ignore", strings.Join(ws.Ignore, ", "))
+	printSetting("Profiles", strings.Join(ws.IgnoreProfiles, ", "))
+	printSetting("HTTP base", ws.HTTP.BaseURL)
+	printSetting("HTTP headers", strings.Join(ws.HTTP.Headers, "; "))
+
+	return nil
+}
+
+func printSetting(label, value string) {
+	if value != "" {
+		fmt.Printf("%-10s %s\n", label+":", value)
+	}
+}
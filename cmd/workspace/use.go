@@ -0,0 +1,84 @@
+package workspace
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"devkit/internal/output"
+	"devkit/internal/workspace"
+)
+
+// useCmd represents the use subcommand
+var useCmd = &cobra.Command{
+	Use:   "use [name]",
+	Short: "Name the current project or switch to a workspace",
+	Long: `Make a workspace the active one.
+
+Inside a project with a .devkit.yaml, the project is given the name.
+Otherwise a registered workspace with that name is selected, or a new
+workspace is created at the repository root (the nearest directory with
+.git, else the current directory). Use --root to pick the directory.
+
+The active workspace applies when the current directory is not inside
+any workspace; --clear deactivates it.
+
+Examples:
+  devkit workspace use myproject
+  devkit workspace use api --root ~/src/api
+  devkit workspace use --clear`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runUse,
+}
+
+func init() {
+	workspaceCmd.AddCommand(useCmd)
+
+	useCmd.Flags().String("root", "", "Project directory (default: detected from the current directory)")
+	useCmd.Flags().Bool("clear", false, "Deactivate the active workspace")
+	useCmd.Flags().StringP("output", "o", "plain", "Output format: plain, json")
+}
+
+func runUse(cmd *cobra.Command, args []string) error {
+	root, _ := cmd.Flags().GetString("root")
+	clear, _ := cmd.Flags().GetBool("clear")
+	outputFormat, _ := cmd.Flags().GetString("output")
+	format := output.OutputFormat(outputFormat)
+
+	if clear {
+		if len(args) > 0 {
+			return fmt.Errorf("--clear does not take a workspace name")
+		}
+		if err := workspace.Deactivate(); err != nil {
+			return err
+		}
+		if format == output.FormatJSON {
+			output.PrintSuccess(format, map[string]interface{}{"active": nil})
+		} else {
+			fmt.Println("No active workspace")
+		}
+		return nil
+	}
+
+	if len(args) == 0 {
+		return fmt.Errorf("workspace name required")
+	}
+
+	ws, created, err := workspace.Use(args[0], root)
+	if err != nil {
+		return err
+	}
+
+	if format == output.FormatJSON {
+		output.PrintSuccess(format, map[string]interface{}{
+			"workspace": ws,
+			"created":   created,
+		})
+	} else {
+		if created {
+			fmt.Printf("Created %s\n", ws.File)
+		}
+		fmt.Printf("Using workspace %s (%s)\n", ws.Name, ws.Root)
+	}
+
+	return nil
+}
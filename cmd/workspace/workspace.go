@@ -0,0 +1,41 @@
+package workspace
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// workspaceCmd represents the workspace command group
+var workspaceCmd = &cobra.Command{
+	Use:   "workspace",
+	Short: "Per-project defaults",
+	Long: `Manage workspaces: per-project defaults stored in a .devkit.yaml at the
+project root.
+
+The workspace is detected like .git, from the nearest parent directory
+that contains a .devkit.yaml. Outside any project, the workspace selected
+with "workspace use" applies. Its defaults are used whenever the matching
+flag is not given:
+
+  workspace.path       default root for file commands
+  workspace.env_file   default file for dev env commands
+  workspace.ignore     patterns excluded by every file command
+  workspace.http       base_url and headers for net http requests
+
+Paths are relative to the workspace root. Any other setting in the file
+(safety, cache, undo, ...) overrides the global config inside the project.
+
+Examples:
+  devkit workspace use myproject
+  devkit workspace show
+  devkit workspace list`,
+}
+
+// GetWorkspaceCmd returns the workspace command
+func GetWorkspaceCmd() *cobra.Command {
+	return workspaceCmd
+}
+
+func init() {
+	// This will be called when the package is imported
+	// Commands will be added in their respective files
+}
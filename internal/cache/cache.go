@@ -0,0 +1,166 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// KeyEnabled is the configuration key that turns the cache on
+const KeyEnabled = "cache.enabled"
+
+// Namespaces used by the commands that cache results
+const (
+	NamespaceFileHash = "file-hash"
+	NamespaceDNS      = "dns"
+	NamespaceWhois    = "whois"
+)
+
+type entry struct {
+	CreatedAt time.Time       `json:"created_at"`
+	Value     json.RawMessage `json:"value"`
+}
+
+// Dir returns the cache directory (~/.devkit/cache)
+func Dir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate home directory: %w", err)
+	}
+	return filepath.Join(home, ".devkit", "cache"), nil
+}
+
+// Enabled reports whether results should be cached. The cache is opt-in
+// (cache.enabled) and --no-cache bypasses it for a single run.
+func Enabled() bool {
+	return viper.GetBool(KeyEnabled) && !viper.GetBool("no-cache")
+}
+
+// Key derives a cache key from the parts identifying an input
+func Key(parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(sum[:])
+}
+
+// Get loads the value stored under namespace/key into v. It reports false
+// when the cache is disabled, the entry is missing or unreadable, or it is
+// older than ttl (0 = never expires).
+func Get(namespace, key string, ttl time.Duration, v interface{}) bool {
+	if !Enabled() {
+		return false
+	}
+	path, err := entryPath(namespace, key)
+	if err != nil {
+		return false
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	var e entry
+	if err := json.Unmarshal(data, &e); err != nil {
+		return false
+	}
+	if ttl > 0 && time.Since(e.CreatedAt) > ttl {
+		os.Remove(path)
+		return false
+	}
+	return json.Unmarshal(e.Value, v) == nil
+}
+
+// Set stores v under namespace/key. Failures are ignored: the cache only
+// speeds up repeated runs and never affects results.
+func Set(namespace, key string, v interface{}) {
+	if !Enabled() {
+		return
+	}
+	path, err := entryPath(namespace, key)
+	if err != nil {
+		return
+	}
+
+	value, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	data, err := json.Marshal(entry{CreatedAt: time.Now(), Value: value})
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return
+	}
+
+	// Write to a temporary file first so that concurrent runs never read a
+	// partial entry
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+	}
+}
+
+// Clear removes the given namespaces (all when none are given) and returns
+// the number of entries and bytes removed
+func Clear(namespaces ...string) (int, int64, error) {
+	root, err := Dir()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var dirs []string
+	if len(namespaces) == 0 {
+		dirs = []string{root}
+	} else {
+		for _, namespace := range namespaces {
+			if !validNamespace(namespace) {
+				return 0, 0, fmt.Errorf("invalid cache namespace: %s", namespace)
+			}
+			dirs = append(dirs, filepath.Join(root, namespace))
+		}
+	}
+
+	count := 0
+	var size int64
+	for _, dir := range dirs {
+		filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+			if err == nil && info.Mode().IsRegular() {
+				count++
+				size += info.Size()
+			}
+			return nil
+		})
+		if err := os.RemoveAll(dir); err != nil {
+			return count, size, fmt.Errorf("failed to clear cache: %w", err)
+		}
+	}
+	return count, size, nil
+}
+
+func entryPath(namespace, key string) (string, error) {
+	if !validNamespace(namespace) {
+		return "", fmt.Errorf("invalid cache namespace: %s", namespace)
+	}
+	root, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	// Spread entries over subdirectories so that large trees do not end up
+	// with a single huge directory
+	return filepath.Join(root, namespace, key[:2], key+".json"), nil
+}
+
+func validNamespace(namespace string) bool {
+	return namespace != "" && namespace != "." && namespace != ".." &&
+		!strings.ContainsAny(namespace, `/\`)
+}
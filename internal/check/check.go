@@ -0,0 +1,84 @@
+// Package check implements the --fail-on threshold shared by validation
+// commands, so that their findings map to documented exit codes.
+package check
+
+import (
+	stderrors "errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"devkit/internal/errors"
+)
+
+// Level is the severity of a finding
+type Level int
+
+// Levels from least to most severe
+const (
+	None Level = iota
+	Warning
+	Expired
+	Invalid
+)
+
+var levelNames = []string{"none", "warning", "expired", "invalid"}
+
+func (l Level) String() string {
+	return levelNames[l]
+}
+
+// exitCodes maps each level to the exit code it produces
+var exitCodes = map[Level]int{
+	Warning: errors.ExitWarning,
+	Expired: errors.ExitExpired,
+	Invalid: errors.ExitInvalid,
+}
+
+// levelValue is the --fail-on flag value; unknown levels are rejected when
+// the flags are parsed
+type levelValue Level
+
+func (v *levelValue) String() string {
+	return Level(*v).String()
+}
+
+func (v *levelValue) Set(s string) error {
+	for i, name := range levelNames {
+		if strings.EqualFold(s, name) {
+			*v = levelValue(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("must be one of %s", strings.Join(levelNames, ", "))
+}
+
+func (v *levelValue) Type() string {
+	return "level"
+}
+
+// AddFailOnFlag defines --fail-on with the given default threshold
+func AddFailOnFlag(cmd *cobra.Command, def Level) {
+	v := levelValue(def)
+	cmd.Flags().Var(&v, "fail-on", "Fail on findings of this level or worse: warning, expired, invalid, none")
+}
+
+// Result turns a finding into the command's error. It returns nil when
+// found is None or below the --fail-on threshold; otherwise an ExitError
+// with the level's exit code. The command has already reported the finding,
+// so cobra is told not to print the error or the usage again.
+func Result(cmd *cobra.Command, found Level, message string) error {
+	threshold := Invalid
+	if f := cmd.Flags().Lookup("fail-on"); f != nil {
+		if v, ok := f.Value.(*levelValue); ok {
+			threshold = Level(*v)
+		}
+	}
+	if found == None || threshold == None || found < threshold {
+		return nil
+	}
+
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+	return &errors.ExitError{Code: exitCodes[found], Err: stderrors.New(message)}
+}
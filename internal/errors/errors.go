@@ -1,6 +1,13 @@
 package errors
 
-import "fmt"
+import (
+	stderrors "errors"
+	"fmt"
+	"io/fs"
+	"net"
+
+	"devkit/internal/i18n"
+)
 
 // DevKitError represents a custom error type for DevKit
 type DevKitError struct {
@@ -12,7 +19,16 @@ type DevKitError struct {
 // Error implements the error interface
 func (e *DevKitError) Error() string {
 	if e.Err != nil {
-		return fmt.Sprintf("%s: %v", e.Message, e.Err)
+		return fmt.Sprintf("%s: %v", e.LocalizedMessage(), e.Err)
+	}
+	return e.LocalizedMessage()
+}
+
+// LocalizedMessage returns the message in the active language when the
+// catalog has a translation for the error code
+func (e *DevKitError) LocalizedMessage() string {
+	if key := "error." + e.Code; i18n.Has(key) {
+		return i18n.T(key)
 	}
 	return e.Message
 }
@@ -24,10 +40,10 @@ func (e *DevKitError) Unwrap() error {
 
 // Predefined errors
 var (
-	ErrFileNotFound     = &DevKitError{Code: "FILE_NOT_FOUND", Message: "Dosya bulunamadı"}
-	ErrInvalidInput     = &DevKitError{Code: "INVALID_INPUT", Message: "Geçersiz giriş"}
-	ErrNetworkTimeout   = &DevKitError{Code: "NETWORK_TIMEOUT", Message: "Ağ zaman aşımı"}
-	ErrPermissionDenied = &DevKitError{Code: "PERMISSION_DENIED", Message: "Erişim izni yok"}
+	ErrFileNotFound     = &DevKitError{Code: "FILE_NOT_FOUND", Message: "File not found"}
+	ErrInvalidInput     = &DevKitError{Code: "INVALID_INPUT", Message: "Invalid input"}
+	ErrNetworkTimeout   = &DevKitError{Code: "NETWORK_TIMEOUT", Message: "Network timeout"}
+	ErrPermissionDenied = &DevKitError{Code: "PERMISSION_DENIED", Message: "Permission denied"}
 )
 
 // New creates a new DevKitError
@@ -46,3 +62,72 @@ func Wrap(err error, code, message string) *DevKitError {
 		Err:     err,
 	}
 }
+
+// Localize prefixes err with the translated message of the predefined error
+// it falls under (file not found, permission denied, network timeout or
+// invalid input) when the active language is not English. The details of err
+// stay as they are, and so does its exit code.
+func Localize(err error) error {
+	if err == nil || i18n.Language() == i18n.DefaultLanguage {
+		return err
+	}
+	var devkitErr *DevKitError
+	if stderrors.As(err, &devkitErr) {
+		return err
+	}
+
+	var netErr net.Error
+	var known *DevKitError
+	switch {
+	case stderrors.Is(err, fs.ErrNotExist):
+		known = ErrFileNotFound
+	case stderrors.Is(err, fs.ErrPermission):
+		known = ErrPermissionDenied
+	case stderrors.As(err, &netErr) && netErr.Timeout():
+		known = ErrNetworkTimeout
+	case ExitCode(err) == ExitInvalid:
+		known = ErrInvalidInput
+	default:
+		return err
+	}
+	return Wrap(err, known.Code, known.Message)
+}
+
+// Exit codes. Validation commands exit with the code of the most severe
+// finding that reached their --fail-on threshold.
+const (
+	ExitOK      = 0
+	ExitFailure = 1 // the command could not run: bad input, I/O or network error
+	ExitInvalid = 2 // the input failed validation
+	ExitExpired = 3 // the token or certificate has expired
+	ExitWarning = 4 // a warning, e.g. a certificate close to expiry
+)
+
+// ExitError carries the exit code for a failed command
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+// Error implements the error interface
+func (e *ExitError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// ExitCode returns the process exit code for err: 0 for nil, the code of an
+// ExitError, and ExitFailure (1) otherwise
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+	var exitErr *ExitError
+	if stderrors.As(err, &exitErr) {
+		return exitErr.Code
+	}
+	return ExitFailure
+}
@@ -0,0 +1,117 @@
+package expand
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// KeyDisabled is the setting (and global flag) that turns expansion off
+const KeyDisabled = "no-expand"
+
+// placeholder matches {{env.NAME}} and {{config.key}}, optionally preceded
+// by a backslash that escapes it
+var placeholder = regexp.MustCompile(`\\?\{\{\s*(env|config)\.([A-Za-z0-9_.\-]+)\s*\}\}`)
+
+// String replaces {{env.NAME}} with the environment variable NAME and
+// {{config.key}} with the configuration value key. A placeholder preceded by
+// a backslash is kept literally, without the backslash. Unset variables and
+// keys are an error, so that a command never runs with an empty secret.
+func String(s string) (string, error) {
+	var missing error
+	result := placeholder.ReplaceAllStringFunc(s, func(match string) string {
+		if match[0] == '\\' {
+			return match[1:]
+		}
+
+		parts := placeholder.FindStringSubmatch(match)
+		source, name := parts[1], parts[2]
+		if source == "env" {
+			if value, ok := os.LookupEnv(name); ok {
+				return value
+			}
+			if missing == nil {
+				missing = fmt.Errorf("environment variable %s is not set (in %s)", name, match)
+			}
+			return match
+		}
+
+		if !viper.IsSet(name) {
+			if missing == nil {
+				missing = fmt.Errorf("config key %s is not set (in %s)", name, match)
+			}
+			return match
+		}
+		return viper.GetString(name)
+	})
+	return result, missing
+}
+
+// Strings expands every element of values
+func Strings(values []string) ([]string, error) {
+	expanded := make([]string, len(values))
+	for i, value := range values {
+		var err error
+		if expanded[i], err = String(value); err != nil {
+			return nil, err
+		}
+	}
+	return expanded, nil
+}
+
+// Enabled reports whether expansion is on (it is unless --no-expand is given)
+func Enabled() bool {
+	return !viper.GetBool(KeyDisabled)
+}
+
+// Command expands the positional arguments in place and the string, string
+// slice and string array flags that were set on the command line. It does
+// nothing when expansion is disabled with --no-expand.
+func Command(cmd *cobra.Command, args []string) error {
+	if !Enabled() {
+		return nil
+	}
+
+	for i, arg := range args {
+		value, err := String(arg)
+		if err != nil {
+			return err
+		}
+		args[i] = value
+	}
+
+	var err error
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		if err != nil {
+			return
+		}
+		err = expandFlag(f)
+	})
+	return err
+}
+
+func expandFlag(f *pflag.Flag) error {
+	switch f.Value.Type() {
+	case "string":
+		value, err := String(f.Value.String())
+		if err != nil {
+			return fmt.Errorf("--%s: %w", f.Name, err)
+		}
+		return f.Value.Set(value)
+	case "stringSlice", "stringArray":
+		slice, ok := f.Value.(pflag.SliceValue)
+		if !ok {
+			return nil
+		}
+		values, err := Strings(slice.GetSlice())
+		if err != nil {
+			return fmt.Errorf("--%s: %w", f.Name, err)
+		}
+		return slice.Replace(values)
+	}
+	return nil
+}
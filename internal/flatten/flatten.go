@@ -0,0 +1,102 @@
+// Package flatten turns decoded JSON results into flat rows of text cells,
+// for the commands that tabulate the output of other devkit commands.
+package flatten
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Into stores v in values under prefix, with the fields of nested objects
+// under dotted keys. A non-object v at the top level is stored as "value".
+func Into(values map[string]string, prefix string, v interface{}) {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		if prefix == "" {
+			prefix = "value"
+		}
+		values[prefix] = Cell(v)
+		return
+	}
+	for k, sub := range m {
+		if prefix != "" {
+			k = prefix + "." + k
+		}
+		Into(values, k, sub)
+	}
+}
+
+// Object flattens v into a new map
+func Object(v interface{}) map[string]string {
+	values := map[string]string{}
+	Into(values, "", v)
+	return values
+}
+
+// Cell renders a JSON value as text. Lists of scalars are joined with
+// commas; lists of objects are summarized by their length.
+func Cell(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case []interface{}:
+		parts := make([]string, len(v))
+		for i, item := range v {
+			if _, isObject := item.(map[string]interface{}); isObject {
+				return fmt.Sprintf("[%d items]", len(v))
+			}
+			parts[i] = Cell(item)
+		}
+		return strings.Join(parts, ", ")
+	}
+	return fmt.Sprint(v)
+}
+
+// Items returns the list of data whose items make rows: the field named
+// field, or when field is empty the only field holding a list of objects.
+// It returns nil when there is no such list.
+func Items(data map[string]interface{}, field string) []interface{} {
+	if field != "" {
+		items, _ := data[field].([]interface{})
+		return items
+	}
+	var found []interface{}
+	for _, v := range data {
+		items, ok := v.([]interface{})
+		if !ok || len(items) == 0 {
+			continue
+		}
+		if _, isObject := items[0].(map[string]interface{}); !isObject {
+			continue
+		}
+		if found != nil {
+			return nil
+		}
+		found = items
+	}
+	return found
+}
+
+// Keys returns the sorted union of the keys of rows
+func Keys(rows ...map[string]string) []string {
+	seen := make(map[string]bool)
+	var keys []string
+	for _, row := range rows {
+		for k := range row {
+			if !seen[k] {
+				seen[k] = true
+				keys = append(keys, k)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,166 @@
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// Configuration keys
+const (
+	KeyEnabled    = "history.enabled"     // record invocations (default true)
+	KeyMaxEntries = "history.max_entries" // entries kept (default 5000)
+)
+
+const defaultMaxEntries = 5000
+
+// Entry is one recorded invocation. Only the command path and the names of
+// the flags used are kept: argument and flag values may contain secrets and
+// are never written.
+type Entry struct {
+	Time       time.Time `json:"time"`
+	Command    string    `json:"command"`
+	Flags      []string  `json:"flags,omitempty"`
+	DurationMs int64     `json:"duration_ms"`
+	Success    bool      `json:"success"`
+}
+
+// Path returns the history file (~/.devkit/history.jsonl)
+func Path() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate home directory: %w", err)
+	}
+	return filepath.Join(home, ".devkit", "history.jsonl"), nil
+}
+
+// Enabled reports whether invocations are recorded
+func Enabled() bool {
+	if !viper.IsSet(KeyEnabled) {
+		return true
+	}
+	return viper.GetBool(KeyEnabled)
+}
+
+// Record appends an entry to the history file. Failures are ignored: the
+// history must never make a command fail.
+func Record(e Entry) {
+	if !Enabled() {
+		return
+	}
+	path, err := Path()
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return
+	}
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return
+	}
+	file.Write(append(data, '\n'))
+	info, statErr := file.Stat()
+	file.Close()
+
+	// Entries are about 100 bytes; trim only once the file is clearly over
+	// the limit so that most runs just append
+	max := maxEntries()
+	if statErr == nil && info.Size() > int64(max)*150 {
+		trim(path, max)
+	}
+}
+
+// Load returns the entries recorded at or after since (all when zero),
+// oldest first. Malformed lines are skipped.
+func Load(since time.Time) ([]Entry, error) {
+	path, err := Path()
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+	defer file.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var e Entry
+		if json.Unmarshal(scanner.Bytes(), &e) != nil {
+			continue
+		}
+		if !since.IsZero() && e.Time.Before(since) {
+			continue
+		}
+		entries = append(entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+	return entries, nil
+}
+
+// Clear removes the history file and returns the number of entries removed
+func Clear() (int, error) {
+	entries, err := Load(time.Time{})
+	if err != nil {
+		return 0, err
+	}
+	path, err := Path()
+	if err != nil {
+		return 0, err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return 0, fmt.Errorf("failed to clear history: %w", err)
+	}
+	return len(entries), nil
+}
+
+func maxEntries() int {
+	if n := viper.GetInt(KeyMaxEntries); n > 0 {
+		return n
+	}
+	return defaultMaxEntries
+}
+
+// trim keeps the newest max entries
+func trim(path string, max int) {
+	entries, err := Load(time.Time{})
+	if err != nil || len(entries) <= max {
+		return
+	}
+	entries = entries[len(entries)-max:]
+
+	tmp := path + ".tmp"
+	file, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return
+	}
+	w := bufio.NewWriter(file)
+	for _, e := range entries {
+		data, _ := json.Marshal(e)
+		w.Write(append(data, '\n'))
+	}
+	if w.Flush() != nil || file.Close() != nil {
+		os.Remove(tmp)
+		return
+	}
+	if os.Rename(tmp, path) != nil {
+		os.Remove(tmp)
+	}
+}
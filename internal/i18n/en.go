@@ -0,0 +1,59 @@
+package i18n
+
+// en is the English message catalog. Command help text lives in the command
+// definitions, so only shared strings are listed here.
+var en = map[string]string{
+	// Help template headings
+	"help.usage":               "Usage:",
+	"help.aliases":             "Aliases:",
+	"help.examples":            "Examples:",
+	"help.available_commands":  "Available Commands:",
+	"help.additional_commands": "Additional Commands:",
+	"help.flags":               "Flags:",
+	"help.global_flags":        "Global Flags:",
+	"help.additional_topics":   "Additional help topics:",
+	"help.more_info":           "Use \"%s [command] --help\" for more information about a command.",
+
+	// Errors
+	"error.prefix":            "Error:",
+	"error.FILE_NOT_FOUND":    "File not found",
+	"error.INVALID_INPUT":     "Invalid input",
+	"error.NETWORK_TIMEOUT":   "Network timeout",
+	"error.PERMISSION_DENIED": "Permission denied",
+
+	// Confirmation prompts
+	"safety.delete_files": "%d file(s) will be deleted — proceed?",
+	"safety.delete_paths": "%d file(s) and folder(s) will be deleted — proceed?",
+	"safety.modify_files": "%d file(s) will be modified — proceed?",
+	"safety.rename_files": "%d file(s) will be renamed — proceed?",
+	"safety.remove_keys":  "%d key(s) will be removed — proceed?",
+	"safety.choices":      "[y/N]",
+	"safety.yes_answers":  "y,yes",
+
+	// File picker
+	"picker.select_file": "Select a file",
+	"picker.first_file":  "Select the first file",
+	"picker.second_file": "Select the second file",
+	"picker.keys":        "(type to filter, ↑/↓ to move, enter to select, esc to cancel)",
+	"picker.list_keys":   "(↑/↓ to move, space to toggle, a to toggle all, enter to apply, esc to cancel)",
+	"picker.checked":     "%d of %d selected",
+	"picker.renames":     "Select the files to rename",
+	"picker.deletes":     "Select the duplicates to delete",
+	"picker.cleans":      "Select the files and folders to delete",
+
+	// Output labels
+	"label.name":         "Name",
+	"label.size":         "Size",
+	"label.bytes":        "bytes",
+	"label.mode":         "Mode",
+	"label.attributes":   "Attributes",
+	"label.modified":     "Modified",
+	"label.is_directory": "Is Directory",
+	"label.cpu":          "CPU",
+	"label.cores":        "cores",
+	"label.usage":        "Usage",
+	"label.memory":       "Memory",
+	"label.disk":         "Disk",
+	"label.used":         "used",
+	"label.os":           "OS",
+}
@@ -0,0 +1,113 @@
+package i18n
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DefaultLanguage is used when no supported language is detected
+const DefaultLanguage = "en"
+
+var (
+	mu       sync.RWMutex
+	current  = DefaultLanguage
+	catalogs = map[string]map[string]string{
+		"en": en,
+		"tr": tr,
+	}
+)
+
+// Languages returns the supported language codes
+func Languages() []string {
+	langs := make([]string, 0, len(catalogs))
+	for lang := range catalogs {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// Normalize converts a locale such as "tr_TR.UTF-8" to a supported language
+// code. It returns an empty string for unsupported locales.
+func Normalize(locale string) string {
+	locale = strings.ToLower(strings.TrimSpace(locale))
+	if i := strings.IndexAny(locale, "_-.@"); i >= 0 {
+		locale = locale[:i]
+	}
+	if _, ok := catalogs[locale]; ok {
+		return locale
+	}
+	return ""
+}
+
+// Detect picks the language from an explicit value (the --lang flag) or,
+// when it is empty, from DEVKIT_LANG, LC_ALL, LC_MESSAGES and LANG.
+func Detect(explicit string) string {
+	candidates := []string{explicit}
+	if explicit == "" {
+		candidates = []string{os.Getenv("DEVKIT_LANG"), os.Getenv("LC_ALL"), os.Getenv("LC_MESSAGES"), os.Getenv("LANG")}
+	}
+	for _, c := range candidates {
+		if c == "" {
+			continue
+		}
+		if lang := Normalize(c); lang != "" {
+			return lang
+		}
+		// The first locale that is set wins, even if unsupported
+		break
+	}
+	return DefaultLanguage
+}
+
+// SetLanguage sets the active language. Unsupported languages fall back to English.
+func SetLanguage(lang string) {
+	mu.Lock()
+	defer mu.Unlock()
+	if normalized := Normalize(lang); normalized != "" {
+		current = normalized
+	} else {
+		current = DefaultLanguage
+	}
+}
+
+// Language returns the active language code
+func Language() string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current
+}
+
+// Has reports whether a message key exists in the active or default catalog
+func Has(key string) bool {
+	_, ok := lookup(key)
+	return ok
+}
+
+// T returns the message for key in the active language, formatted with args.
+// Missing keys fall back to English and then to the key itself.
+func T(key string, args ...interface{}) string {
+	msg, ok := lookup(key)
+	if !ok {
+		msg = key
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(msg, args...)
+	}
+	return msg
+}
+
+func lookup(key string) (string, bool) {
+	mu.RLock()
+	lang := current
+	mu.RUnlock()
+
+	if msg, ok := catalogs[lang][key]; ok {
+		return msg, true
+	}
+	msg, ok := catalogs[DefaultLanguage][key]
+	return msg, ok
+}
@@ -0,0 +1,260 @@
+package i18n
+
+// tr is the Turkish message catalog. Keys of the form cmd.<path>.short
+// replace the short description of the command at that path.
+var tr = map[string]string{
+	// Help template headings
+	"help.usage":               "Kullanım:",
+	"help.aliases":             "Takma adlar:",
+	"help.examples":            "Örnekler:",
+	"help.available_commands":  "Kullanılabilir Komutlar:",
+	"help.additional_commands": "Ek Komutlar:",
+	"help.flags":               "Bayraklar:",
+	"help.global_flags":        "Genel Bayraklar:",
+	"help.additional_topics":   "Ek yardım konuları:",
+	"help.more_info":           "Bir komut hakkında daha fazla bilgi için \"%s [komut] --help\" kullanın.",
+
+	// Errors
+	"error.prefix":            "Hata:",
+	"error.FILE_NOT_FOUND":    "Dosya bulunamadı",
+	"error.INVALID_INPUT":     "Geçersiz giriş",
+	"error.NETWORK_TIMEOUT":   "Ağ zaman aşımı",
+	"error.PERMISSION_DENIED": "Erişim izni yok",
+
+	// Confirmation prompts
+	"safety.delete_files": "%d dosya silinecek — devam edilsin mi?",
+	"safety.delete_paths": "%d dosya ve klasör silinecek — devam edilsin mi?",
+	"safety.modify_files": "%d dosya değiştirilecek — devam edilsin mi?",
+	"safety.rename_files": "%d dosya yeniden adlandırılacak — devam edilsin mi?",
+	"safety.remove_keys":  "%d anahtar kaldırılacak — devam edilsin mi?",
+	"safety.choices":      "[e/H]",
+	"safety.yes_answers":  "e,evet,y,yes",
+
+	// File picker
+	"picker.select_file": "Bir dosya seçin",
+	"picker.first_file":  "Birinci dosyayı seçin",
+	"picker.second_file": "İkinci dosyayı seçin",
+	"picker.keys":        "(süzmek için yazın, ↑/↓ ile gezinin, enter ile seçin, esc ile vazgeçin)",
+	"picker.list_keys":   "(↑/↓ ile gezinin, boşluk ile işaretleyin, a ile tümünü değiştirin, enter ile uygulayın, esc ile vazgeçin)",
+	"picker.checked":     "%d / %d seçili",
+	"picker.renames":     "Yeniden adlandırılacak dosyaları seçin",
+	"picker.deletes":     "Silinecek kopyaları seçin",
+	"picker.cleans":      "Silinecek dosya ve klasörleri seçin",
+
+	// Output labels
+	"label.name":         "Ad",
+	"label.size":         "Boyut",
+	"label.bytes":        "bayt",
+	"label.mode":         "İzinler",
+	"label.attributes":   "Öznitelikler",
+	"label.modified":     "Değiştirilme",
+	"label.is_directory": "Dizin mi",
+	"label.cpu":          "İşlemci",
+	"label.cores":        "çekirdek",
+	"label.usage":        "Kullanım",
+	"label.memory":       "Bellek",
+	"label.disk":         "Disk",
+	"label.used":         "kullanımda",
+	"label.os":           "İşletim Sistemi",
+
+	"cmd.short":                         "Geliştiriciler için çok yönlü bir CLI araç seti",
+	"cmd.help.short":                    "Herhangi bir komut hakkında yardım",
+	"cmd.completion.short":              "Belirtilen kabuk için otomatik tamamlama betiği oluştur",
+	"cmd.dev.short":                     "Geliştirici araçları ve yardımcıları",
+	"cmd.dev.base64.short":              "Base64 kodlama/çözme işlemleri",
+	"cmd.dev.base64.decode.short":       "Base64 metnini çöz",
+	"cmd.dev.base64.encode.short":       "Girdiyi base64 olarak kodla",
+	"cmd.dev.base32.short":              "Base32 kodlama/çözme işlemleri",
+	"cmd.dev.base32.decode.short":       "Base32 metnini çöz",
+	"cmd.dev.base32.encode.short":       "Girdiyi base32 olarak kodla",
+	"cmd.dev.hex.short":                 "Hex kodlama/çözme işlemleri",
+	"cmd.dev.hex.decode.short":          "Hex metnini çöz",
+	"cmd.dev.hex.encode.short":          "Girdiyi hex olarak kodla",
+	"cmd.dev.cron.short":                "Cron ifadesi işlemleri",
+	"cmd.dev.cron.explain.short":        "Bir cron ifadesini açıkla",
+	"cmd.dev.cron.make.short":           "İngilizce bir ifadeden cron ifadesi oluştur",
+	"cmd.dev.cron.next.short":           "Sonraki çalışma zamanlarını göster",
+	"cmd.dev.cron.prev.short":           "Önceki çalışma zamanlarını göster",
+	"cmd.dev.ci.short":                  "CI ortamı yardımcıları",
+	"cmd.dev.ci.info.short":             "CI sağlayıcısını algıla ve ortak derleme bilgilerini göster",
+	"cmd.dev.env.short":                 ".env dosyası yönetimi",
+	"cmd.dev.env.get.short":             ".env dosyasından bir değişkenin değerini al",
+	"cmd.dev.env.list.short":            ".env dosyasındaki tüm değişkenleri listele",
+	"cmd.dev.env.set.short":             ".env dosyasında bir değişken ayarla",
+	"cmd.dev.env.unset.short":           ".env dosyasından bir değişkeni kaldır",
+	"cmd.dev.epoch.short":               "Unix zaman damgası ile tarih arasında dönüştür",
+	"cmd.dev.epoch.diff.short":          "İki zaman damgası veya tarih arasındaki süreyi yazdır",
+	"cmd.dev.gomod.short":               "Go modülü yardımcıları",
+	"cmd.dev.gomod.bump.short":          "Modülleri en son minor veya yama sürümüne yükselt",
+	"cmd.dev.gomod.graph.short":         "Modül gereksinim grafiğini veya bir modülün neden gerektiğini yazdır",
+	"cmd.dev.gomod.tidy-check.short":    "go mod tidy go.mod veya go.sum dosyasını değiştirecekse başarısız ol",
+	"cmd.dev.hash.short":                "Girdinin hash değerini hesapla",
+	"cmd.dev.hash.bcrypt.short":         "Parolayı bcrypt ile hashle veya doğrula",
+	"cmd.dev.hash.argon2.short":         "Parolayı Argon2id ile hashle veya doğrula",
+	"cmd.dev.hash.dir.short":            "Dizin ağacının deterministik hash değerini hesapla",
+	"cmd.dev.html.short":                "HTML varlık kodlama/çözme işlemleri",
+	"cmd.dev.html.decode.short":         "HTML varlıklarını çöz",
+	"cmd.dev.html.encode.short":         "Metni HTML varlıklarıyla kodla",
+	"cmd.dev.json.short":                "JSON işlemleri (biçimlendir, küçült, doğrula, sorgula)",
+	"cmd.dev.json.escape.short":         "Metni JSON dizesi olarak kaçışla",
+	"cmd.dev.json.minify.short":         "JSON metnini küçült",
+	"cmd.dev.json.mock.short":           "Şema veya şablondan sahte JSON belgeleri üret",
+	"cmd.dev.json.path.short":           "JSONPath ile JSON sorgula",
+	"cmd.dev.json.prettify.short":       "JSON metnini biçimlendir",
+	"cmd.dev.json.unescape.short":       "JSON dizesindeki kaçışları çöz",
+	"cmd.dev.json.validate.short":       "JSON metnini doğrula",
+	"cmd.dev.jwt.short":                 "JWT (JSON Web Token) işlemleri",
+	"cmd.dev.jwt.decode.short":          "JWT belirtecini doğrulamadan çöz",
+	"cmd.dev.jwt.verify.short":          "JWT imzasını doğrula",
+	"cmd.dev.lorem.short":               "Lorem Ipsum metni üret",
+	"cmd.dev.fake.short":                "Gerçekçi test verisi üret (ad, e-posta, adres, ...)",
+	"cmd.dev.pprof.short":               "Go pprof profillerini özetle",
+	"cmd.dev.pprof.top.short":           "En yüksek düz veya kümülatif değere sahip fonksiyonları listele",
+	"cmd.dev.pprof.diff.short":          "İki profili fonksiyon fonksiyon karşılaştır",
+	"cmd.dev.random.short":              "Rastgele veri üret (metin, sayı, parola, parola cümlesi, bayt, desen, seçim)",
+	"cmd.dev.random.bytes.short":        "Gizli anahtarlar için rastgele bayt üret",
+	"cmd.dev.random.passphrase.short":   "Diceware parola cümlesi üret",
+	"cmd.dev.random.number.short":       "Rastgele sayı üret",
+	"cmd.dev.random.choice.short":       "Bir listeden rastgele öğe seç",
+	"cmd.dev.random.shuffle.short":      "Satırları veya öğeleri rastgele sırala",
+	"cmd.dev.random.pattern.short":      "AAA-999-aaa gibi bir desenden değer üret",
+	"cmd.dev.random.password.short":     "Rastgele parola üret",
+	"cmd.dev.random.string.short":       "Rastgele metin üret",
+	"cmd.dev.semver.short":              "Anlamsal sürüm işlemleri",
+	"cmd.dev.semver.bump.short":         "Anlamsal sürümü artır",
+	"cmd.dev.semver.check.short":        "Sürümlerin bir kısıtı karşılayıp karşılamadığını kontrol et",
+	"cmd.dev.semver.compare.short":      "İki anlamsal sürümü karşılaştır",
+	"cmd.dev.semver.diff.short":         "İki sürüm arasındaki değişikliğin türünü belirle",
+	"cmd.dev.semver.sort.short":         "Sürüm listesini sırala ve süz",
+	"cmd.dev.slug.short":                "Metni URL'ye uygun bir kısa ada dönüştür",
+	"cmd.dev.stacktrace.short":          "Yığın izi ve panik günlüğü araçları",
+	"cmd.dev.stacktrace.parse.short":    "Yığın izlerini özetle, aynı yığınları grupla",
+	"cmd.dev.time.short":                "Saat dilimi dönüştürme, dünya saati ve tarih hesaplama",
+	"cmd.dev.time.add.short":            "Bir zamana süre ekle",
+	"cmd.dev.time.convert.short":        "Bir zamanı bir saat diliminden diğerlerine dönüştür",
+	"cmd.dev.time.now.short":            "Şu anki zamanı birkaç saat diliminde yazdır",
+	"cmd.dev.time.sub.short":            "Bir zamandan süre çıkar",
+	"cmd.dev.trace.short":               "Go çalışma izlerini özetle",
+	"cmd.dev.trace.summary.short":       "Bir izin goroutine, GC ve zamanlayıcı değerlerini yazdır",
+	"cmd.dev.ulid.short":                "ULID üret",
+	"cmd.dev.ulid.decode.short":         "ULID içindeki zaman damgasını göster",
+	"cmd.dev.nanoid.short":              "NanoID üret",
+	"cmd.dev.snowflake.short":           "Snowflake ID üret ve çözümle",
+	"cmd.dev.snowflake.decode.short":    "Snowflake ID'lerin zaman damgasını, veri merkezini, işçisini ve sırasını göster",
+	"cmd.dev.url.short":                 "URL kodlama/çözme ve ayrıştırma işlemleri",
+	"cmd.dev.url.build.short":           "Bileşenlerinden URL oluştur",
+	"cmd.dev.url.decode.short":          "URL kodlu metni çöz",
+	"cmd.dev.url.encode.short":          "Metni URL kodla",
+	"cmd.dev.url.parse.short":           "URL'yi ayrıştır ve bileşenlerini göster",
+	"cmd.dev.url.punycode.short":        "Uluslararası alan adlarını punycode'a ve geri dönüştür",
+	"cmd.dev.url.punycode.decode.short": "Punycode alan adlarını Unicode'a dönüştür",
+	"cmd.dev.url.punycode.encode.short": "Alan adlarını punycode'a dönüştür",
+	"cmd.dev.url.query.short":           "Sorgu parametrelerini ekle, kaldır, oku ve sırala",
+	"cmd.dev.url.query.add.short":       "URL'ye sorgu parametreleri ekle",
+	"cmd.dev.url.query.get.short":       "Sorgu parametresi değerlerini yazdır",
+	"cmd.dev.url.query.remove.short":    "URL'den sorgu parametrelerini kaldır",
+	"cmd.dev.url.query.sort.short":      "Sorgu parametrelerini anahtara göre sırala",
+	"cmd.dev.yaml.short":                "YAML işlemleri (doğrula, biçimlendir, sorgula, dönüştür)",
+	"cmd.dev.yaml.validate.short":       "YAML metnini doğrula",
+	"cmd.dev.yaml.prettify.short":       "YAML metnini biçimlendir",
+	"cmd.dev.yaml.path.short":           "YAML'ı yol ifadesiyle sorgula",
+	"cmd.dev.yaml.to-json.short":        "YAML'ı JSON'a dönüştür",
+	"cmd.dev.toml.short":                "TOML işlemleri (doğrula, oku, yaz)",
+	"cmd.dev.toml.validate.short":       "TOML metnini doğrula",
+	"cmd.dev.toml.get.short":            "Anahtar yoluyla değer oku",
+	"cmd.dev.toml.set.short":            "Anahtar yoluyla değer ata",
+	"cmd.dev.xml.short":                 "XML işlemleri (doğrula, biçimlendir, küçült, xpath)",
+	"cmd.dev.xml.validate.short":        "XML metnini doğrula",
+	"cmd.dev.xml.prettify.short":        "XML metnini biçimlendir",
+	"cmd.dev.xml.minify.short":          "XML metnini küçült",
+	"cmd.dev.xml.xpath.short":           "XML'i XPath ile sorgula",
+	"cmd.dev.csv.short":                 "CSV işlemleri (önizle, seç, JSON'a dönüştür, istatistik)",
+	"cmd.dev.csv.preview.short":         "İlk satırları tablo olarak göster",
+	"cmd.dev.csv.select.short":          "CSV dosyasından sütun seç",
+	"cmd.dev.csv.to-json.short":         "CSV'yi JSON'a dönüştür",
+	"cmd.dev.csv.stats.short":           "Satır, sütun ve boş hücre sayılarını göster",
+	"cmd.dev.uuid.short":                "UUID üret (v1, v3, v4, v5 veya v7)",
+	"cmd.dev.uuid.inspect.short":        "UUID'nin sürümünü, varyantını ve zaman damgasını göster",
+	"cmd.dev.uuid.validate.short":       "Değerlerin geçerli UUID olup olmadığını kontrol et",
+	"cmd.doctor.short":                  "Sorun giderme ve hata raporları için tanılama",
+	"cmd.doctor.bundle.short":           "Hata raporları için maskelenmiş bir tanılama arşivi topla",
+	"cmd.file.short":                    "Dosya ve dizin işlemleri",
+	"cmd.file.archive.short":            "Arşiv işlemleri (denetim)",
+	"cmd.file.archive.audit.short":      "Bir arşivi dizin aşımı, sembolik bağlantı kaçışı ve zip bombasına karşı denetle",
+	"cmd.file.clean.short":              "Derleme çıktıları ve önbellekler gibi üretilmiş dosyaları sil",
+	"cmd.file.convert.short":            "Dosya biçimleri arasında dönüştür (JSON, YAML, TOML, XML, CSV)",
+	"cmd.file.dedupe.short":             "Yinelenen dosyaları bul ve kaldır",
+	"cmd.file.diff.short":               "İki dosyayı veya dizini karşılaştır",
+	"cmd.file.find.short":               "Dosyaları ada, türe, boyuta ve yaşa göre bul ve her biri için komut çalıştır",
+	"cmd.file.find-replace.short":       "Birden çok dosyada metin bul ve değiştir",
+	"cmd.file.preview.short":            "Bir dosyanın içinde ne olduğunu göster",
+	"cmd.file.rename.short":             "Dosyaları kalıplarla toplu yeniden adlandır",
+	"cmd.file.search.short":             "Dosyalarda metin ara",
+	"cmd.file.stat.short":               "Ayrıntılı dosya bilgilerini göster",
+	"cmd.file.tree.short":               "Dizin yapısını ağaç olarak göster",
+	"cmd.file.watch.short":              "Dosyalardaki değişiklikleri izle",
+	"cmd.net.short":                     "Ağ ve sistem işlemleri",
+	"cmd.net.disk.short":                "Disk kullanım analizi",
+	"cmd.net.dns.short":                 "DNS sorgulama işlemleri",
+	"cmd.net.dns.lookup.short":          "DNS kayıtlarını sorgula",
+	"cmd.net.dns.reverse.short":         "Ters DNS sorgusu",
+	"cmd.net.dns.resolve.short":         "Adı sistemin yaptığı gibi çözümle",
+	"cmd.net.http.short":                "HTTP istek işlemleri",
+	"cmd.net.http.delete.short":         "DELETE isteği gönder",
+	"cmd.net.http.get.short":            "GET isteği gönder",
+	"cmd.net.http.post.short":           "POST isteği gönder",
+	"cmd.net.http.put.short":            "PUT isteği gönder",
+	"cmd.net.interfaces.short":          "Ağ arayüzleri ve bağlantıları",
+	"cmd.net.ip.short":                  "IP adresi bilgileri",
+	"cmd.net.open-ports.short":          "Açık portları ve uygulamaları göster",
+	"cmd.net.perf.short":                "İki makine arasında TCP verim ve gecikme testi",
+	"cmd.net.perf.client.short":         "Bir sunucuya karşı verim testi çalıştır",
+	"cmd.net.perf.server.short":         "İstemcilerden gelen verim testlerini kabul et",
+	"cmd.net.ping.short":                "Bir sunucuya ping at ve istatistikleri göster",
+	"cmd.net.port.short":                "Port tarama ve durum kontrolü",
+	"cmd.net.port.check.short":          "Bir portun açık olup olmadığını kontrol et",
+	"cmd.net.port.list.short":           "Dinlenen portları listele",
+	"cmd.net.port.scan.short":           "Bir port aralığını tara",
+	"cmd.net.ps.short":                  "Süreç listesi ve yönetimi",
+	"cmd.net.ps.dump.short":             "Bir Go sürecinin goroutine veya heap dökümünü al",
+	"cmd.net.serve.short":               "Geliştirme için yerel sahte sunucular",
+	"cmd.net.serve.dns.short":           "Yerel geliştirme adlarını yanıtlayan DNS sunucusu",
+	"cmd.net.serve.mailhog.short":       "Web gelen kutusu ve JSON API'li her şeyi kabul eden SMTP sunucusu",
+	"cmd.net.serve.oidc.short":          "Giriş akışlarını denemek için sahte OAuth2/OpenID Connect sağlayıcısı",
+	"cmd.net.serve.proxy.short":         "Gecikme, hata ve yavaş bağlantı enjekte eden ters vekil sunucu",
+	"cmd.net.serve.s3.short":            "Yerel dizinle çalışan sahte S3 nesne deposu",
+	"cmd.net.speed.short":               "İnternet hız testi",
+	"cmd.net.ssl.short":                 "SSL sertifika işlemleri",
+	"cmd.net.ssl.check.short":           "SSL sertifikasını kontrol et",
+	"cmd.net.ssl.expiry.short":          "SSL sertifikasının bitiş tarihini kontrol et",
+	"cmd.net.status.short":              "Hedef listesi için canlı erişilebilirlik panosu",
+	"cmd.net.sysinfo.short":             "Sistem bilgileri",
+	"cmd.net.whois.short":               "Alan adı whois sorgusu",
+	"cmd.schema.short":                  "--output json belgeleri için JSON şemaları",
+	"cmd.schema.list.short":             "Şeması yayımlanmış komutları listele",
+	"cmd.schema.print.short":            "Bir komutun çıktısının JSON şemasını yazdır",
+	"cmd.schema.validate.short":         "Bir komutun JSON çıktısını şemasına göre doğrula",
+	"cmd.undo.short":                    "Dosya değiştiren komutların yaptığı değişiklikleri geri al",
+	"cmd.cache.short":                   "Sonuç önbelleğini yönet",
+	"cmd.cache.clear.short":             "Önbelleğe alınmış sonuçları sil",
+	"cmd.workspace.short":               "Projeye özel varsayılanlar",
+	"cmd.workspace.use.short":           "Geçerli projeyi adlandır veya başka bir çalışma alanına geç",
+	"cmd.workspace.show.short":          "Geçerli çalışma alanını ve varsayılanlarını göster",
+	"cmd.workspace.list.short":          "Kayıtlı çalışma alanlarını listele",
+	"cmd.fleet.short":                   "devkit komutlarını birçok makinede çalıştır",
+	"cmd.fleet.run.short":               "Bir devkit komutunu her makinede çalıştır ve sonuçları birleştir",
+	"cmd.report.short":                  "devkit kontrollerinden raporlar üret",
+	"cmd.report.generate.short":         "Kontrolleri çalıştır ve Markdown/HTML raporu oluştur",
+	"cmd.release.short":                 "Derleme çıktıları için sürüm yardımcıları",
+	"cmd.release.package.short":         "Derleme çıktılarını arşivle, adlandır, sağlama toplamı ve manifest üret",
+	"cmd.stats.short":                   "Komut geçmişinden yerel kullanım istatistikleri",
+	"cmd.remote.short":                  "Bir devkit komutunu SSH üzerinden başka makinelerde çalıştır",
+	"cmd.undo.list.short":               "Geri alma günlüğündeki işlemleri listele",
+	"cmd.git.short":                     "Git barındırma yardımcıları (GitHub, GitLab)",
+	"cmd.git.hub.short":                 "GitHub ve GitLab depolarını sorgula (çekme istekleri, sürümler, dosyalar)",
+	"cmd.git.hub.pr.short":              "Çekme istekleri (GitLab'da birleştirme istekleri)",
+	"cmd.git.hub.pr.list.short":         "Çekme isteklerini listele",
+	"cmd.git.hub.release.short":         "Sürümler",
+	"cmd.git.hub.release.latest.short":  "Bir deponun en son sürümünü göster",
+	"cmd.git.hub.raw.short":             "Bir depodaki dosyayı yazdır",
+}
@@ -0,0 +1,491 @@
+package matcher
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"devkit/internal/platform"
+)
+
+// Options configures a Matcher
+type Options struct {
+	// Include patterns; a file must match one of them (all files when empty)
+	Include []string
+	// Exclude patterns; matching files are skipped and matching directories
+	// are not descended into. They follow .gitignore rules (see Matcher).
+	Exclude []string
+	// IgnoreProfiles adds the exclude patterns of named profiles (see
+	// Profiles), before Exclude
+	IgnoreProfiles []string
+	// Extensions restricts files to the given extensions (leading dot optional)
+	Extensions []string
+	// Recursive descends into subdirectories. Patterns containing ** always
+	// recurse.
+	Recursive bool
+	// MinSize and MaxSize bound the file size in bytes (0 = no bound)
+	MinSize int64
+	MaxSize int64
+	// ModifiedSince and ModifiedBefore bound the modification time (zero =
+	// no bound)
+	ModifiedSince  time.Time
+	ModifiedBefore time.Time
+	// FollowSymlinks descends into symlinked directories and visits
+	// symlinked files; otherwise symbolic links are skipped
+	FollowSymlinks bool
+	// Dirs also visits the directories below the roots that pass the
+	// patterns and modification time filters
+	Dirs bool
+}
+
+// Matcher decides which files under a set of roots are selected.
+//
+// Patterns use doublestar syntax (*, ?, [abc], {a,b} and ** for any number
+// of directories). Patterns without a slash match the base name at any
+// depth; patterns with a slash match the path relative to the walked root.
+//
+// Exclude patterns also follow .gitignore rules: blank lines and lines
+// starting with # are ignored, a leading / anchors a pattern to the root, a
+// trailing / matches directories only, and a leading ! re-includes what an
+// earlier pattern excluded. The last matching pattern wins. As in git, a
+// file cannot be re-included when one of its directories is excluded.
+type Matcher struct {
+	include        []string
+	exclude        []rule
+	extensions     []string
+	recursive      bool
+	minSize        int64
+	maxSize        int64
+	modifiedSince  time.Time
+	modifiedBefore time.Time
+	followSymlinks bool
+	dirs           bool
+}
+
+// New validates the patterns and returns a Matcher
+func New(opts Options) (*Matcher, error) {
+	m := &Matcher{
+		recursive:      opts.Recursive,
+		minSize:        opts.MinSize,
+		maxSize:        opts.MaxSize,
+		modifiedSince:  opts.ModifiedSince,
+		modifiedBefore: opts.ModifiedBefore,
+		followSymlinks: opts.FollowSymlinks,
+		dirs:           opts.Dirs,
+	}
+	if m.maxSize > 0 && m.minSize > m.maxSize {
+		return nil, fmt.Errorf("minimum size is larger than maximum size")
+	}
+	if !m.modifiedSince.IsZero() && !m.modifiedBefore.IsZero() && !m.modifiedSince.Before(m.modifiedBefore) {
+		return nil, fmt.Errorf("modified-since must be before modified-before")
+	}
+
+	for _, pattern := range opts.Include {
+		pattern = normalizePattern(pattern)
+		if pattern == "" {
+			continue
+		}
+		if !doublestar.ValidatePattern(pattern) {
+			return nil, fmt.Errorf("invalid include pattern: %s", pattern)
+		}
+		if strings.Contains(pattern, "**") {
+			m.recursive = true
+		}
+		m.include = append(m.include, pattern)
+	}
+
+	var exclude []string
+	for _, name := range opts.IgnoreProfiles {
+		patterns, ok := Profiles[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			return nil, fmt.Errorf("unknown ignore profile: %s (use %s)", name, strings.Join(ProfileNames(), ", "))
+		}
+		exclude = append(exclude, patterns...)
+	}
+	for _, pattern := range append(exclude, opts.Exclude...) {
+		r, ok := parseRule(pattern)
+		if !ok {
+			continue
+		}
+		if !doublestar.ValidatePattern(r.pattern) {
+			return nil, fmt.Errorf("invalid exclude pattern: %s", pattern)
+		}
+		m.exclude = append(m.exclude, r)
+	}
+
+	for _, ext := range opts.Extensions {
+		ext = strings.TrimPrefix(strings.TrimSpace(ext), ".")
+		if ext != "" {
+			m.extensions = append(m.extensions, strings.ToLower(ext))
+		}
+	}
+
+	return m, nil
+}
+
+// SplitList splits a comma-separated flag value, dropping empty entries
+func SplitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// Excluded reports whether the file or directory (dir) at rel
+// (slash-separated, relative to a root) is excluded. Only rel itself is
+// checked, not its parent directories: Walk does not enter excluded
+// directories.
+func (m *Matcher) Excluded(rel string, dir bool) bool {
+	excluded := false
+	for _, r := range m.exclude {
+		if r.negate == excluded && (!r.dirOnly || dir) && r.match(rel) {
+			excluded = !r.negate
+		}
+	}
+	return excluded
+}
+
+// Match reports whether the file at rel (slash-separated, relative to a
+// root) is selected
+func (m *Matcher) Match(rel string) bool {
+	if m.Excluded(rel, false) {
+		return false
+	}
+
+	if len(m.extensions) > 0 {
+		ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(rel), "."))
+		found := false
+		for _, e := range m.extensions {
+			if e == ext {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if len(m.include) == 0 {
+		return true
+	}
+	for _, pattern := range m.include {
+		if matchPattern(pattern, rel) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchInfo reports whether a file passes the size and modification time
+// filters; directories are only checked against the time filters
+func (m *Matcher) MatchInfo(info os.FileInfo) bool {
+	if m.minSize > 0 && (info.IsDir() || info.Size() < m.minSize) {
+		return false
+	}
+	if m.maxSize > 0 && !info.IsDir() && info.Size() > m.maxSize {
+		return false
+	}
+	if !m.modifiedSince.IsZero() && info.ModTime().Before(m.modifiedSince) {
+		return false
+	}
+	if !m.modifiedBefore.IsZero() && !info.ModTime().Before(m.modifiedBefore) {
+		return false
+	}
+	return true
+}
+
+// WalkFunc is called for every selected file (and directory with Dirs)
+type WalkFunc func(path string, info os.FileInfo) error
+
+// Walk visits the selected files under roots. Roots that are files are
+// always visited; a file reachable from several roots is visited once.
+// Unreadable entries are skipped.
+//
+// Symbolic links are skipped unless FollowSymlinks is set. When following,
+// a directory already visited (a symlink loop or a second link to the same
+// tree) is not descended into again.
+func (m *Matcher) Walk(roots []string, fn WalkFunc) error {
+	w := &walker{
+		matcher: m,
+		fn:      fn,
+		seen:    make(map[string]bool),
+		dirs:    make(map[string]bool),
+	}
+
+	for _, root := range roots {
+		info, err := os.Stat(root)
+		if err != nil {
+			return fmt.Errorf("cannot access %s: %w", root, err)
+		}
+		if !info.IsDir() {
+			if err := w.visit(root, info); err != nil {
+				return err
+			}
+			continue
+		}
+		if !w.enterDir(root) {
+			continue
+		}
+		if err := w.walkDir(root, ""); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type walker struct {
+	matcher *Matcher
+	fn      WalkFunc
+	seen    map[string]bool // files already visited, by resolved path
+	dirs    map[string]bool // directories already entered, by resolved path
+}
+
+// visit calls fn once per file; links to a file already visited are
+// skipped so that commands never see the same file under two names
+func (w *walker) visit(path string, info os.FileInfo) error {
+	key := path
+	if real, err := filepath.EvalSymlinks(path); err == nil {
+		key = real
+	}
+	if abs, err := filepath.Abs(key); err == nil {
+		if w.seen[abs] {
+			return nil
+		}
+		w.seen[abs] = true
+	}
+	return w.fn(path, info)
+}
+
+// enterDir reports whether a directory has not been entered yet
+func (w *walker) enterDir(dir string) bool {
+	real, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return false
+	}
+	if real, err = filepath.Abs(real); err != nil {
+		return false
+	}
+	if w.dirs[real] {
+		return false
+	}
+	w.dirs[real] = true
+	return true
+}
+
+func (w *walker) walkDir(dir, relDir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		rel := entry.Name()
+		if relDir != "" {
+			rel = relDir + "/" + rel
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			if !w.matcher.followSymlinks {
+				continue
+			}
+			// Broken links are skipped
+			if info, err = os.Stat(path); err != nil {
+				continue
+			}
+		}
+
+		if info.IsDir() {
+			if w.matcher.Excluded(rel, true) {
+				continue
+			}
+			if w.matcher.dirs && w.matcher.Match(rel) && w.matcher.MatchInfo(info) {
+				if err := w.fn(path, info); err != nil {
+					return err
+				}
+			}
+			if !w.matcher.recursive || !w.enterDir(path) {
+				continue
+			}
+			if err := w.walkDir(path, rel); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if !info.Mode().IsRegular() || !w.matcher.Match(rel) || !w.matcher.MatchInfo(info) {
+			continue
+		}
+		if err := w.visit(path, info); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ExpandPaths expands arguments containing glob patterns (including **)
+// into the matching paths. Plain arguments are returned unchanged.
+func ExpandPaths(args []string) ([]string, error) {
+	var paths []string
+	for _, arg := range args {
+		if !hasMeta(arg) {
+			paths = append(paths, arg)
+			continue
+		}
+
+		matches, err := doublestar.FilepathGlob(arg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %s: %w", arg, err)
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("no files match %s", arg)
+		}
+		sort.Strings(matches)
+		paths = append(paths, matches...)
+	}
+	return paths, nil
+}
+
+var sizeUnits = map[string]int64{
+	"":   1,
+	"b":  1,
+	"k":  1 << 10,
+	"kb": 1 << 10,
+	"m":  1 << 20,
+	"mb": 1 << 20,
+	"g":  1 << 30,
+	"gb": 1 << 30,
+	"t":  1 << 40,
+	"tb": 1 << 40,
+}
+
+// ParseSize parses a size such as 512, 10K, 1.5MB or 2GB (1024-based)
+func ParseSize(value string) (int64, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	value = strings.Replace(value, "ib", "b", 1)
+
+	i := 0
+	for i < len(value) && (value[i] >= '0' && value[i] <= '9' || value[i] == '.') {
+		i++
+	}
+	number, err := strconv.ParseFloat(value[:i], 64)
+	unit, ok := sizeUnits[strings.TrimSpace(value[i:])]
+	if err != nil || !ok || number < 0 {
+		return 0, fmt.Errorf("invalid size: %s (examples: 512, 10K, 1.5MB, 2GB)", value)
+	}
+	return int64(number * float64(unit)), nil
+}
+
+// ParseTime parses an absolute date (2006-01-02, 2006-01-02 15:04 or
+// RFC 3339) or an age relative to now (90m, 36h, 7d, 2w)
+func ParseTime(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+
+	for _, layout := range []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02 15:04", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+
+	if len(value) > 1 {
+		multiplier := time.Duration(0)
+		switch value[len(value)-1] {
+		case 'd':
+			multiplier = 24 * time.Hour
+		case 'w':
+			multiplier = 7 * 24 * time.Hour
+		}
+		if multiplier > 0 {
+			if n, err := strconv.Atoi(value[:len(value)-1]); err == nil && n >= 0 {
+				return time.Now().Add(-time.Duration(n) * multiplier), nil
+			}
+		}
+	}
+	if d, err := time.ParseDuration(value); err == nil && d >= 0 {
+		return time.Now().Add(-d), nil
+	}
+
+	return time.Time{}, fmt.Errorf("invalid time: %s (examples: 2024-06-01, 7d, 36h)", value)
+}
+
+func matchPattern(pattern, rel string) bool {
+	if strings.Contains(pattern, "/") {
+		return matchPath(pattern, rel)
+	}
+
+	// Patterns without a slash match the base name, so that "node_modules"
+	// or "*.go" apply at every depth
+	return matchPath(pattern, path.Base(rel))
+}
+
+func matchPath(pattern, name string) bool {
+	// NTFS ignores case, so "*.JPG" has to find photo.jpg on Windows
+	if platform.CaseInsensitivePaths {
+		pattern, name = strings.ToLower(pattern), strings.ToLower(name)
+	}
+	ok, _ := doublestar.Match(pattern, name)
+	return ok
+}
+
+// rule is an exclude pattern parsed with .gitignore rules
+type rule struct {
+	pattern  string
+	negate   bool // !pattern re-includes
+	dirOnly  bool // pattern/ only matches directories
+	anchored bool // the pattern had a slash, so it matches the whole path
+}
+
+// parseRule parses one exclude pattern; ok is false for blank lines and
+// comments
+func parseRule(pattern string) (rule, bool) {
+	pattern = filepath.ToSlash(strings.TrimSpace(pattern))
+	if pattern == "" || strings.HasPrefix(pattern, "#") {
+		return rule{}, false
+	}
+
+	var r rule
+	if strings.HasPrefix(pattern, "!") {
+		r.negate, pattern = true, pattern[1:]
+	} else if strings.HasPrefix(pattern, `\!`) || strings.HasPrefix(pattern, `\#`) {
+		pattern = pattern[1:]
+	}
+	if strings.HasSuffix(pattern, "/") {
+		r.dirOnly, pattern = true, strings.TrimRight(pattern, "/")
+	}
+	pattern = strings.TrimPrefix(pattern, "./")
+	r.anchored = strings.Contains(pattern, "/")
+	r.pattern = strings.TrimPrefix(pattern, "/")
+	return r, r.pattern != ""
+}
+
+func (r rule) match(rel string) bool {
+	if r.anchored {
+		return matchPath(r.pattern, rel)
+	}
+	return matchPath(r.pattern, path.Base(rel))
+}
+
+func normalizePattern(pattern string) string {
+	pattern = filepath.ToSlash(strings.TrimSpace(pattern))
+	return strings.TrimPrefix(pattern, "./")
+}
+
+func hasMeta(path string) bool {
+	return strings.ContainsAny(path, "*?[{")
+}
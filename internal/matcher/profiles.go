@@ -0,0 +1,76 @@
+package matcher
+
+import "sort"
+
+// Profiles are named sets of exclude patterns for the files a toolchain
+// generates, selected with --ignore-profile or the ignore_profiles
+// workspace setting
+var Profiles = map[string][]string{
+	"node": {
+		"node_modules/",
+		"bower_components/",
+		".npm/",
+		".pnpm-store/",
+		".yarn/cache/",
+		".next/",
+		".nuxt/",
+		".svelte-kit/",
+		".parcel-cache/",
+		".turbo/",
+		"coverage/",
+		"npm-debug.log*",
+		"yarn-debug.log*",
+		"yarn-error.log*",
+		"pnpm-debug.log*",
+	},
+	"go": {
+		"vendor/",
+		"*.test",
+		"*.prof",
+		"*.out",
+		"go.work.sum",
+	},
+	"python": {
+		"__pycache__/",
+		"*.py[cod]",
+		".venv/",
+		"venv/",
+		".tox/",
+		".nox/",
+		".mypy_cache/",
+		".pytest_cache/",
+		".ruff_cache/",
+		".ipynb_checkpoints/",
+		"*.egg-info/",
+		".eggs/",
+		"htmlcov/",
+		".coverage",
+	},
+	"build-artifacts": {
+		"build/",
+		"dist/",
+		"out/",
+		"target/",
+		"bin/",
+		"obj/",
+		"*.o",
+		"*.obj",
+		"*.a",
+		"*.lib",
+		"*.so",
+		"*.dylib",
+		"*.dll",
+		"*.exe",
+		"*.class",
+	},
+}
+
+// ProfileNames returns the names of the ignore profiles, sorted
+func ProfileNames() []string {
+	names := make([]string, 0, len(Profiles))
+	for name := range Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
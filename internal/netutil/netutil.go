@@ -0,0 +1,519 @@
+// Package netutil builds the dialers and HTTP clients used by the net
+// commands from the shared --timeout, --retries and --retry-delay flags and
+// the proxy and TLS settings (--proxy, --ca-cert, --insecure).
+package netutil
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"devkit/internal/profile"
+)
+
+// DefaultRetryDelay is the pause between attempts unless --retry-delay is given
+const DefaultRetryDelay = time.Second
+
+// Options are the timeout and retry settings of a network command
+type Options struct {
+	// Timeout bounds each attempt: a dial, a handshake or a whole HTTP request
+	Timeout time.Duration
+	// Retries is the number of extra attempts after a failure
+	Retries int
+	// RetryDelay is the pause between attempts
+	RetryDelay time.Duration
+	// Proxy is the proxy for HTTP requests and TLS connections. nil uses
+	// HTTP_PROXY, HTTPS_PROXY and NO_PROXY from the environment.
+	Proxy *url.URL
+	// NoProxy disables proxies, including those from the environment
+	NoProxy bool
+	// RootCAs are the trusted CAs: the system pool plus --ca-cert, or nil
+	// for the system pool alone
+	RootCAs *x509.CertPool
+	// Insecure skips certificate verification
+	Insecure bool
+	// Family restricts connections to IPv4 (4) or IPv6 (6); 0 allows both
+	Family int
+}
+
+// timeoutValue is a duration flag that also accepts a plain number of
+// seconds, so that "--timeout 5" keeps working next to "--timeout 500ms"
+type timeoutValue time.Duration
+
+func (v *timeoutValue) String() string {
+	if *v == 0 {
+		return "0"
+	}
+	return time.Duration(*v).String()
+}
+
+func (v *timeoutValue) Set(s string) error {
+	if secs, err := strconv.ParseFloat(s, 64); err == nil {
+		*v = timeoutValue(secs * float64(time.Second))
+	} else if d, err := time.ParseDuration(s); err == nil {
+		*v = timeoutValue(d)
+	} else {
+		return fmt.Errorf("invalid duration %q (use e.g. 5s, 500ms or 2)", s)
+	}
+	if *v < 0 {
+		return fmt.Errorf("must not be negative")
+	}
+	return nil
+}
+
+func (v *timeoutValue) Type() string {
+	return "duration"
+}
+
+// AddFlags defines the shared flags as persistent flags of cmd. Without
+// --timeout every command uses its own default. --proxy, --ca-cert and
+// --insecure default to net.proxy, net.ca_cert and net.insecure from the
+// config file.
+func AddFlags(cmd *cobra.Command) {
+	flags := cmd.PersistentFlags()
+	flags.Var(new(timeoutValue), "timeout", "Timeout for each attempt, e.g. 5s, 500ms or 2 (seconds); the default depends on the command")
+	flags.Int("retries", 0, "Retry failed requests this many times")
+	flags.Duration("retry-delay", DefaultRetryDelay, "Pause between retries")
+	flags.String("proxy", "", "Proxy URL, e.g. http://proxy:3128, or none (default from HTTPS_PROXY/HTTP_PROXY)")
+	flags.String("ca-cert", "", "PEM file with extra CA certificates to trust, e.g. a corporate proxy CA")
+	flags.Bool("insecure", false, "Skip TLS certificate verification")
+}
+
+// AddFamilyFlags defines -4/--ipv4 and -6/--ipv6, which force the address
+// family of the connections a command makes
+func AddFamilyFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolP("ipv4", "4", false, "Use IPv4 only")
+	cmd.Flags().BoolP("ipv6", "6", false, "Use IPv6 only")
+	cmd.MarkFlagsMutuallyExclusive("ipv4", "ipv6")
+}
+
+// AddTimeoutFlag defines a local --timeout with a shorthand and a default
+// for commands whose timeout is part of their own interface, such as ping.
+// It replaces the shared --timeout on that command.
+func AddTimeoutFlag(cmd *cobra.Command, shorthand string, def time.Duration) {
+	v := timeoutValue(def)
+	cmd.Flags().VarP(&v, "timeout", shorthand, "Timeout for each attempt, e.g. 5s, 500ms or 2 (seconds)")
+}
+
+// FromCommand reads the flags of cmd. defaultTimeout applies when neither
+// --timeout nor a local timeout default is set.
+func FromCommand(cmd *cobra.Command, defaultTimeout time.Duration) (Options, error) {
+	o := Options{Timeout: defaultTimeout, RetryDelay: DefaultRetryDelay}
+	if f := cmd.Flags().Lookup("timeout"); f != nil {
+		if v, ok := f.Value.(*timeoutValue); ok && *v > 0 {
+			o.Timeout = time.Duration(*v)
+		}
+	}
+	if retries, err := cmd.Flags().GetInt("retries"); err == nil && retries > 0 {
+		o.Retries = retries
+	}
+	if delay, err := cmd.Flags().GetDuration("retry-delay"); err == nil {
+		o.RetryDelay = delay
+	}
+
+	proxy := stringSetting(cmd, "proxy", "net.proxy")
+	switch strings.ToLower(proxy) {
+	case "":
+	case "none", "direct":
+		o.NoProxy = true
+	default:
+		u, err := parseProxy(proxy)
+		if err != nil {
+			return o, err
+		}
+		o.Proxy = u
+	}
+
+	if caFile := stringSetting(cmd, "ca-cert", "net.ca_cert"); caFile != "" {
+		pool, err := loadCAs(caFile)
+		if err != nil {
+			return o, err
+		}
+		o.RootCAs = pool
+	}
+
+	if f := cmd.Flags().Lookup("insecure"); f != nil && f.Changed {
+		o.Insecure, _ = cmd.Flags().GetBool("insecure")
+	} else {
+		o.Insecure = viper.GetBool("net.insecure")
+	}
+
+	if v4, _ := cmd.Flags().GetBool("ipv4"); v4 {
+		o.Family = 4
+	} else if v6, _ := cmd.Flags().GetBool("ipv6"); v6 {
+		o.Family = 6
+	}
+	return o, nil
+}
+
+// Network restricts network ("tcp", "udp" or "ip") to the forced family,
+// e.g. "tcp" becomes "tcp4" with -4
+func (o Options) Network(network string) string {
+	switch network {
+	case "tcp", "udp", "ip":
+		if o.Family != 0 {
+			return network + strconv.Itoa(o.Family)
+		}
+	}
+	return network
+}
+
+// Family names the address family of addr: IPv4, IPv6, or "" when addr is
+// not an IP address
+func Family(addr net.Addr) string {
+	var ip net.IP
+	switch a := addr.(type) {
+	case *net.TCPAddr:
+		ip = a.IP
+	case *net.UDPAddr:
+		ip = a.IP
+	case *net.IPAddr:
+		ip = a.IP
+	default:
+		return ""
+	}
+	if ip.To4() != nil {
+		return "IPv4"
+	}
+	return "IPv6"
+}
+
+// stringSetting returns the flag value when given, else the config key
+func stringSetting(cmd *cobra.Command, flag, key string) string {
+	if f := cmd.Flags().Lookup(flag); f != nil && f.Changed {
+		return f.Value.String()
+	}
+	return viper.GetString(key)
+}
+
+// parseProxy parses a proxy URL; a missing scheme means http
+func parseProxy(s string) (*url.URL, error) {
+	if !strings.Contains(s, "://") {
+		s = "http://" + s
+	}
+	u, err := url.Parse(s)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid proxy URL: %s", s)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("unsupported proxy scheme: %s (supported: http, https)", u.Scheme)
+	}
+	return u, nil
+}
+
+// loadCAs returns the system CAs plus the certificates in a PEM file
+func loadCAs(path string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA certificates: %w", err)
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no PEM certificates found in %s", path)
+	}
+	return pool, nil
+}
+
+// TLSConfig returns a copy of base (nil for defaults) with the trusted CAs
+// and --insecure applied
+func (o Options) TLSConfig(base *tls.Config) *tls.Config {
+	config := &tls.Config{}
+	if base != nil {
+		config = base.Clone()
+	}
+	if config.RootCAs == nil {
+		config.RootCAs = o.RootCAs
+	}
+	if o.Insecure {
+		config.InsecureSkipVerify = true
+	}
+	return config
+}
+
+// proxyFor returns the proxy to use for u, or nil to connect directly
+func (o Options) proxyFor(u *url.URL) (*url.URL, error) {
+	switch {
+	case o.NoProxy:
+		return nil, nil
+	case o.Proxy != nil:
+		return o.Proxy, nil
+	default:
+		return http.ProxyFromEnvironment(&http.Request{URL: u})
+	}
+}
+
+// permanentError marks an error that retrying cannot fix
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent wraps err so that Retry returns it without further attempts
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// Retry calls fn until it succeeds, returns a Permanent error, or the
+// retries are used up. Each attempt gets a context bounded by the timeout.
+func (o Options) Retry(ctx context.Context, fn func(ctx context.Context) error) error {
+	var err error
+	for attempt := 0; attempt <= o.Retries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return err
+			case <-time.After(o.RetryDelay):
+			}
+		}
+
+		attemptCtx, cancel := o.context(ctx)
+		err = fn(attemptCtx)
+		cancel()
+
+		var permanent *permanentError
+		if err == nil || errors.As(err, &permanent) || ctx.Err() != nil {
+			break
+		}
+	}
+	if o.Retries > 0 && err != nil {
+		var permanent *permanentError
+		if !errors.As(err, &permanent) {
+			return fmt.Errorf("%w (after %d attempts)", err, o.Retries+1)
+		}
+	}
+	return err
+}
+
+func (o Options) context(ctx context.Context) (context.Context, context.CancelFunc) {
+	if o.Timeout > 0 {
+		return context.WithTimeout(ctx, o.Timeout)
+	}
+	return context.WithCancel(ctx)
+}
+
+// Dialer returns a dialer bounded by the timeout. Its sockets are counted
+// for --profile.
+func (o Options) Dialer() *net.Dialer {
+	return &net.Dialer{
+		Timeout: o.Timeout,
+		Control: func(network, address string, c syscall.RawConn) error {
+			profile.CountConnection()
+			return nil
+		},
+	}
+}
+
+// Resolver returns a resolver that uses Go's built-in DNS client and reaches
+// the name servers over the forced family, like dig -4 and -6
+func (o Options) Resolver() *net.Resolver {
+	dialer := o.Dialer()
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+			return dialer.DialContext(ctx, o.Network(network), address)
+		},
+	}
+}
+
+// DialContext connects to address, retrying failed attempts
+func (o Options) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	var conn net.Conn
+	err := o.Retry(ctx, func(ctx context.Context) error {
+		var err error
+		conn, err = o.Dialer().DialContext(ctx, o.Network(network), address)
+		return err
+	})
+	return conn, err
+}
+
+// DialTLS connects to address and completes a TLS handshake, retrying
+// failed attempts. Certificate verification errors are not retried. Behind
+// an HTTP proxy the connection is tunneled with CONNECT, as HTTPS requests
+// are.
+func (o Options) DialTLS(ctx context.Context, network, address string, config *tls.Config) (*tls.Conn, error) {
+	config = o.TLSConfig(config)
+	if config.ServerName == "" {
+		host, _, err := net.SplitHostPort(address)
+		if err != nil {
+			return nil, err
+		}
+		config.ServerName = host
+	}
+
+	var conn *tls.Conn
+	err := o.Retry(ctx, func(ctx context.Context) error {
+		raw, err := o.dialVia(ctx, network, address)
+		if err != nil {
+			return err
+		}
+		conn = tls.Client(raw, config)
+		if err := conn.HandshakeContext(ctx); err != nil {
+			raw.Close()
+			var verr *tls.CertificateVerificationError
+			if errors.As(err, &verr) {
+				return Permanent(err)
+			}
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return conn, nil
+}
+
+// dialVia connects to address directly or through the proxy for it
+func (o Options) dialVia(ctx context.Context, network, address string) (net.Conn, error) {
+	proxy, err := o.proxyFor(&url.URL{Scheme: "https", Host: address})
+	if err != nil {
+		return nil, Permanent(err)
+	}
+	if proxy == nil {
+		return o.Dialer().DialContext(ctx, o.Network(network), address)
+	}
+
+	proxyAddress := proxy.Host
+	if proxy.Port() == "" {
+		port := "80"
+		if proxy.Scheme == "https" {
+			port = "443"
+		}
+		proxyAddress = net.JoinHostPort(proxy.Hostname(), port)
+	}
+	conn, err := o.Dialer().DialContext(ctx, o.Network("tcp"), proxyAddress)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to proxy %s: %w", proxy.Host, err)
+	}
+	if proxy.Scheme == "https" {
+		tlsConn := tls.Client(conn, o.TLSConfig(&tls.Config{ServerName: proxy.Hostname()}))
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("failed to connect to proxy %s: %w", proxy.Host, err)
+		}
+		conn = tlsConn
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+		defer conn.SetDeadline(time.Time{})
+	}
+	req := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: address},
+		Host:   address,
+		Header: make(http.Header),
+	}
+	if proxy.User != nil {
+		password, _ := proxy.User.Password()
+		credentials := base64.StdEncoding.EncodeToString([]byte(proxy.User.Username() + ":" + password))
+		req.Header.Set("Proxy-Authorization", "Basic "+credentials)
+	}
+	if err := req.Write(conn); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("proxy CONNECT failed: %w", err)
+	}
+	// The server sends nothing after the response until the client speaks,
+	// so the reader cannot hold back tunneled bytes
+	resp, err := http.ReadResponse(bufio.NewReader(conn), req)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("proxy CONNECT failed: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		conn.Close()
+		return nil, fmt.Errorf("proxy CONNECT failed: %s", resp.Status)
+	}
+	return conn, nil
+}
+
+// HTTPClient returns a client whose requests are bounded by the timeout and
+// use the proxy and TLS settings
+func (o Options) HTTPClient() *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	dialer := o.Dialer()
+	transport.DialContext = func(ctx context.Context, network, address string) (net.Conn, error) {
+		return dialer.DialContext(ctx, o.Network(network), address)
+	}
+	transport.Proxy = func(req *http.Request) (*url.URL, error) {
+		return o.proxyFor(req.URL)
+	}
+	transport.TLSClientConfig = o.TLSConfig(nil)
+	return &http.Client{Timeout: o.Timeout, Transport: countingTransport{transport}}
+}
+
+// countingTransport counts the requests sent for --profile
+type countingTransport struct {
+	http.RoundTripper
+}
+
+func (t countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	profile.CountRequest()
+	return t.RoundTripper.RoundTrip(req)
+}
+
+// Do sends req with client, retrying network errors and 502, 503 and 504
+// responses. The request body is replayed on retries when it can be, which
+// is the case for bodies created from a string or a byte slice.
+func (o Options) Do(client *http.Client, req *http.Request) (*http.Response, error) {
+	var resp *http.Response
+	attempt := 0
+	err := o.Retry(req.Context(), func(ctx context.Context) error {
+		attempt++
+		r := req
+		if attempt > 1 {
+			if req.Body != nil && req.GetBody == nil {
+				return Permanent(fmt.Errorf("request body cannot be sent again"))
+			}
+			r = req.Clone(req.Context())
+			if req.GetBody != nil {
+				body, err := req.GetBody()
+				if err != nil {
+					return Permanent(err)
+				}
+				r.Body = body
+			}
+		}
+
+		var err error
+		resp, err = client.Do(r)
+		if err != nil {
+			return err
+		}
+		switch resp.StatusCode {
+		case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			if attempt <= o.Retries {
+				resp.Body.Close()
+				return fmt.Errorf("server returned %s", resp.Status)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
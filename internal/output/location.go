@@ -0,0 +1,34 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Location is a finding at a position in a file. --vimgrep prints it as
+// path:line:column: message, which Vim's quickfix list (%f:%l:%c:%m), VS
+// Code problem matchers and Emacs compilation buffers read as is.
+type Location struct {
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+	Message string `json:"message"`
+}
+
+// String formats the location on one line; lines and columns start at 1,
+// and a missing one is printed as 1
+func (l Location) String() string {
+	file := l.File
+	if file == "" {
+		file = "-"
+	}
+	message := strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ").Replace(l.Message)
+	return fmt.Sprintf("%s:%d:%d: %s", file, max(l.Line, 1), max(l.Column, 1), message)
+}
+
+// PrintLocations prints one location per line, without colors
+func PrintLocations(locations []Location) {
+	for _, l := range locations {
+		fmt.Println(l.String())
+	}
+}
@@ -0,0 +1,175 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// LogOptions configures the --log-file capture of a command
+type LogOptions struct {
+	Path     string
+	Tee      bool  // also capture the text written to stdout
+	MaxSize  int64 // rotate before the file grows past this size; 0 never rotates
+	MaxFiles int   // rotated files kept next to the log (file.1 is the newest)
+}
+
+// LogRecord is the line appended to the log file for each command run. As
+// in the history, only the names of the flags used are kept; results and
+// captured output are written as printed.
+type LogRecord struct {
+	Time       time.Time     `json:"time"`
+	Command    string        `json:"command"`
+	Flags      []string      `json:"flags,omitempty"`
+	DurationMs int64         `json:"duration_ms"`
+	Success    bool          `json:"success"`
+	ExitCode   int           `json:"exit_code"`
+	Error      string        `json:"error,omitempty"`
+	Results    []interface{} `json:"results,omitempty"`
+	Output     string        `json:"output,omitempty"`
+}
+
+// logCapture collects what a command prints for its log record
+type logCapture struct {
+	opts    LogOptions
+	start   time.Time
+	results []interface{}
+
+	stdout *os.File // the real stdout while --tee redirects it
+	pipe   *os.File
+	text   bytes.Buffer
+	copied chan struct{}
+}
+
+// commandLog is the capture in progress, if any
+var commandLog *logCapture
+
+// ansiEscape matches color and cursor sequences, which are stripped from
+// the captured text
+var ansiEscape = regexp.MustCompile(`\x1b\[[0-9;?]*[A-Za-z]`)
+
+// StartLog begins capturing the results of the running command for
+// FinishLog. With Tee, stdout is replaced by a pipe that copies everything
+// to the terminal and to the log, so commands see a non-terminal stdout.
+func StartLog(opts LogOptions) error {
+	if err := os.MkdirAll(filepath.Dir(opts.Path), 0755); err != nil {
+		return fmt.Errorf("failed to create log directory: %w", err)
+	}
+	// Fail now rather than after the command has run
+	file, err := os.OpenFile(opts.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	file.Close()
+
+	commandLog = &logCapture{opts: opts, start: time.Now()}
+
+	if opts.Tee {
+		r, w, err := os.Pipe()
+		if err != nil {
+			commandLog = nil
+			return fmt.Errorf("failed to capture output: %w", err)
+		}
+		l := commandLog
+		l.stdout, l.pipe, l.copied = os.Stdout, w, make(chan struct{})
+		os.Stdout = w
+		go func() {
+			io.Copy(io.MultiWriter(l.stdout, &l.text), r)
+			r.Close()
+			close(l.copied)
+		}()
+	}
+	return nil
+}
+
+// logResult keeps a printed result for the log
+func logResult(result Result) {
+	if commandLog == nil {
+		return
+	}
+	if result.Success {
+		commandLog.results = append(commandLog.results, result.Data)
+	} else {
+		commandLog.results = append(commandLog.results, map[string]interface{}{"error": result.Error})
+	}
+}
+
+// FinishLog appends the record of the command to the log file, rotating it
+// first when it would grow past MaxSize. It does nothing unless StartLog
+// was called.
+func FinishLog(command string, flags []string, exitCode int, cmdErr error) error {
+	l := commandLog
+	if l == nil {
+		return nil
+	}
+	commandLog = nil
+
+	record := LogRecord{
+		Time:       l.start,
+		Command:    command,
+		Flags:      flags,
+		DurationMs: time.Since(l.start).Milliseconds(),
+		Success:    cmdErr == nil,
+		ExitCode:   exitCode,
+		Results:    l.results,
+	}
+	if cmdErr != nil {
+		record.Error = cmdErr.Error()
+	}
+	if l.pipe != nil {
+		os.Stdout = l.stdout
+		l.pipe.Close()
+		<-l.copied
+		record.Output = ansiEscape.ReplaceAllString(l.text.String(), "")
+	}
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode log record: %w", err)
+	}
+	data = append(data, '\n')
+
+	if info, err := os.Stat(l.opts.Path); err == nil && l.opts.MaxSize > 0 && info.Size() > 0 && info.Size()+int64(len(data)) > l.opts.MaxSize {
+		if err := rotateLog(l.opts.Path, l.opts.MaxFiles); err != nil {
+			return err
+		}
+	}
+
+	file, err := os.OpenFile(l.opts.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	defer file.Close()
+	if _, err := file.Write(data); err != nil {
+		return fmt.Errorf("failed to write log file: %w", err)
+	}
+	return nil
+}
+
+// rotateLog shifts path.1 ... path.<keep-1> up by one and moves path to
+// path.1; the oldest file is removed
+func rotateLog(path string, keep int) error {
+	if keep < 1 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to rotate log file: %w", err)
+		}
+		return nil
+	}
+	os.Remove(path + "." + strconv.Itoa(keep))
+	for i := keep - 1; i >= 1; i-- {
+		from := path + "." + strconv.Itoa(i)
+		if err := os.Rename(from, path+"."+strconv.Itoa(i+1)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to rotate log file: %w", err)
+		}
+	}
+	if err := os.Rename(path, path+".1"); err != nil {
+		return fmt.Errorf("failed to rotate log file: %w", err)
+	}
+	return nil
+}
@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+
+	"devkit/internal/i18n"
 )
 
 // OutputFormat represents the output format type
@@ -24,6 +26,7 @@ type Result struct {
 
 // Print prints the result in the specified format
 func Print(format OutputFormat, result Result) {
+	logResult(result)
 	switch format {
 	case FormatJSON:
 		printJSON(result)
@@ -63,7 +66,7 @@ func printJSON(result Result) {
 // printPlain prints the result as plain text
 func printPlain(result Result) {
 	if !result.Success {
-		fmt.Fprintf(os.Stderr, "Error: %s\n", result.Error)
+		fmt.Fprintf(os.Stderr, "%s %s\n", i18n.T("error.prefix"), result.Error)
 		return
 	}
 
@@ -86,7 +89,7 @@ func printPlain(result Result) {
 // printTable prints the result as a table (basic implementation)
 func printTable(result Result) {
 	if !result.Success {
-		fmt.Fprintf(os.Stderr, "Error: %s\n", result.Error)
+		fmt.Fprintf(os.Stderr, "%s %s\n", i18n.T("error.prefix"), result.Error)
 		return
 	}
 
@@ -0,0 +1,79 @@
+package output
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// progressDelay keeps short operations from flashing a progress line
+const progressDelay = time.Second
+
+// Progress is an io.Writer that counts the bytes passing through it and
+// shows "label  45% (1.2 GB / 2.6 GB)" on stderr. Nothing is shown during the
+// first second, when stderr is not a terminal, or with --quiet.
+type Progress struct {
+	label   string
+	total   int64 // 0 when unknown
+	written int64
+	start   time.Time
+	last    time.Time
+	enabled bool
+	width   int // length of the last line shown, 0 if none
+}
+
+// NewProgress returns a progress indicator for total bytes (0 if unknown)
+func NewProgress(label string, total int64) *Progress {
+	enabled := !viper.GetBool("quiet")
+	if info, err := os.Stderr.Stat(); err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		enabled = false
+	}
+	return &Progress{label: label, total: total, start: time.Now(), enabled: enabled}
+}
+
+// Write records len(p) bytes of progress
+func (p *Progress) Write(b []byte) (int, error) {
+	p.written += int64(len(b))
+	if !p.enabled {
+		return len(b), nil
+	}
+
+	now := time.Now()
+	if now.Sub(p.start) < progressDelay || now.Sub(p.last) < 100*time.Millisecond {
+		return len(b), nil
+	}
+	p.last = now
+
+	line := fmt.Sprintf("%s  %s", p.label, formatBytes(p.written))
+	if p.total > 0 {
+		line = fmt.Sprintf("%s  %3d%% (%s / %s)", p.label, p.written*100/p.total, formatBytes(p.written), formatBytes(p.total))
+	}
+	elapsed := now.Sub(p.start).Seconds()
+	line += fmt.Sprintf("  %s/s", formatBytes(int64(float64(p.written)/elapsed)))
+	fmt.Fprintf(os.Stderr, "\r%s%s", Muted(line), strings.Repeat(" ", max(p.width-len(line), 0)))
+	p.width = max(p.width, len(line))
+	return len(b), nil
+}
+
+// Done clears the progress line, if one was shown
+func (p *Progress) Done() {
+	if p.width > 0 {
+		fmt.Fprintf(os.Stderr, "\r%s\r", strings.Repeat(" ", p.width))
+	}
+}
+
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTPE"[exp])
+}
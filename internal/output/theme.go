@@ -0,0 +1,254 @@
+package output
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// Theme holds the colors and symbols used for plain and table output. A nil
+// color prints the text unstyled.
+type Theme struct {
+	Name      string
+	Accent    *color.Color // headings, file names, table headers
+	Success   *color.Color
+	Failure   *color.Color
+	Warning   *color.Color
+	Muted     *color.Color // secondary details
+	Highlight *color.Color // matches inside text
+	OK        string       // symbol before a success message
+	Fail      string       // symbol before a failure message
+	Warn      string       // symbol before a warning
+	Rule      string       // repeated to draw table borders
+	Separator string       // gutter between columns, e.g. line numbers and text
+	Banner    bool         // show the ANSI-art banner in the root help
+}
+
+// Theme names
+const (
+	ThemeDefault      = "default"
+	ThemeHighContrast = "high-contrast"
+	ThemeMinimal      = "minimal"
+)
+
+var themes = map[string]func() *Theme{
+	ThemeDefault: func() *Theme {
+		return &Theme{
+			Name:      ThemeDefault,
+			Accent:    color.New(color.FgBlue),
+			Success:   color.New(color.FgGreen),
+			Failure:   color.New(color.FgRed),
+			Warning:   color.New(color.FgYellow),
+			Muted:     color.New(color.FgHiBlack),
+			Highlight: color.New(color.FgRed, color.Bold),
+			OK:        "✓",
+			Fail:      "✗",
+			Warn:      "⚠",
+			Rule:      "-",
+			Separator: "│",
+			Banner:    true,
+		}
+	},
+	// Bright, bold colors and text markers that stay readable on any
+	// background and without color
+	ThemeHighContrast: func() *Theme {
+		return &Theme{
+			Name:      ThemeHighContrast,
+			Accent:    color.New(color.FgHiCyan, color.Bold),
+			Success:   color.New(color.FgHiGreen, color.Bold),
+			Failure:   color.New(color.FgHiRed, color.Bold),
+			Warning:   color.New(color.FgHiYellow, color.Bold),
+			Muted:     color.New(color.FgHiWhite),
+			Highlight: color.New(color.FgBlack, color.BgHiYellow, color.Bold),
+			OK:        "[OK]",
+			Fail:      "[FAIL]",
+			Warn:      "[WARN]",
+			Rule:      "=",
+			Separator: "┃",
+			Banner:    true,
+		}
+	},
+	// No colors, ASCII only
+	ThemeMinimal: func() *Theme {
+		return &Theme{
+			Name:      ThemeMinimal,
+			OK:        "OK",
+			Fail:      "FAIL",
+			Warn:      "WARN",
+			Rule:      "-",
+			Separator: "|",
+		}
+	},
+}
+
+// ThemeNames lists the built-in themes
+func ThemeNames() []string {
+	names := make([]string, 0, len(themes))
+	for name := range themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+var current = themes[ThemeDefault]()
+
+// CurrentTheme returns the active theme
+func CurrentTheme() *Theme {
+	return current
+}
+
+// SetTheme activates the named theme (default when empty) and applies the
+// color overrides, keyed by role: accent, success, failure, warning, muted,
+// highlight. Colors are names such as "cyan", "hi-red" or "bold magenta";
+// "none" removes the color.
+func SetTheme(name string, colors map[string]string) error {
+	if name == "" {
+		name = ThemeDefault
+	}
+	newTheme, ok := themes[name]
+	if !ok {
+		return fmt.Errorf("unknown theme: %s (available: %s)", name, strings.Join(ThemeNames(), ", "))
+	}
+	theme := newTheme()
+
+	roles := map[string]**color.Color{
+		"accent":    &theme.Accent,
+		"success":   &theme.Success,
+		"failure":   &theme.Failure,
+		"warning":   &theme.Warning,
+		"muted":     &theme.Muted,
+		"highlight": &theme.Highlight,
+	}
+	for role, spec := range colors {
+		target, ok := roles[role]
+		if !ok {
+			continue
+		}
+		c, err := parseColor(spec)
+		if err != nil {
+			return fmt.Errorf("theme color %s: %w", role, err)
+		}
+		*target = c
+	}
+
+	current = theme
+	return nil
+}
+
+var colorNames = map[string]color.Attribute{
+	"black":   color.FgBlack,
+	"red":     color.FgRed,
+	"green":   color.FgGreen,
+	"yellow":  color.FgYellow,
+	"blue":    color.FgBlue,
+	"magenta": color.FgMagenta,
+	"cyan":    color.FgCyan,
+	"white":   color.FgWhite,
+}
+
+// parseColor parses "cyan", "hi-cyan", "bold cyan", "underline red" or "none"
+func parseColor(spec string) (*color.Color, error) {
+	spec = strings.ToLower(strings.TrimSpace(spec))
+	if spec == "" || spec == "none" {
+		return nil, nil
+	}
+
+	var attrs []color.Attribute
+	for _, word := range strings.Fields(spec) {
+		switch {
+		case word == "bold":
+			attrs = append(attrs, color.Bold)
+		case word == "underline":
+			attrs = append(attrs, color.Underline)
+		case strings.HasPrefix(word, "hi-") && colorNames[word[3:]] != 0:
+			attrs = append(attrs, colorNames[word[3:]]+60)
+		case colorNames[word] != 0:
+			attrs = append(attrs, colorNames[word])
+		default:
+			return nil, fmt.Errorf("unknown color %q", word)
+		}
+	}
+	return color.New(attrs...), nil
+}
+
+func paint(c *color.Color, s string) string {
+	if c == nil {
+		return s
+	}
+	return c.Sprint(s)
+}
+
+// Accent styles headings, file names and table headers
+func Accent(s string) string {
+	return paint(current.Accent, s)
+}
+
+// Success styles text reporting success
+func Success(s string) string {
+	return paint(current.Success, s)
+}
+
+// Failure styles text reporting a failure
+func Failure(s string) string {
+	return paint(current.Failure, s)
+}
+
+// Warning styles text reporting a warning
+func Warning(s string) string {
+	return paint(current.Warning, s)
+}
+
+// Muted styles secondary details
+func Muted(s string) string {
+	return paint(current.Muted, s)
+}
+
+// Highlight styles matches inside text
+func Highlight(s string) string {
+	return paint(current.Highlight, s)
+}
+
+// OK prefixes msg with the theme's success symbol
+func OK(msg string) string {
+	return Success(current.OK) + " " + msg
+}
+
+// Fail prefixes msg with the theme's failure symbol
+func Fail(msg string) string {
+	return Failure(current.Fail) + " " + msg
+}
+
+// Warn prefixes msg with the theme's warning symbol
+func Warn(msg string) string {
+	return Warning(current.Warn) + " " + msg
+}
+
+// Rule returns a table border width characters wide
+func Rule(width int) string {
+	return Muted(strings.Repeat(current.Rule, width))
+}
+
+// Separator returns the column gutter
+func Separator() string {
+	return Muted(current.Separator)
+}
+
+const banner = `
+ ____             _  ___ _
+|  _ \  _____   _| |/ (_) |_
+| | | |/ _ \ \ / / ' /| | __|
+| |_| |  __/\ V /| . \| | |_
+|____/ \___| \_/ |_|\_\_|\__|
+`
+
+// Banner returns the ANSI-art logo, or "" when the theme has none or colors
+// are off (output piped or NO_COLOR set)
+func Banner() string {
+	if !current.Banner || color.NoColor {
+		return ""
+	}
+	return Accent(strings.Trim(banner, "\n")) + "\n\n"
+}
@@ -0,0 +1,155 @@
+package picker
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+	"devkit/internal/i18n"
+	"devkit/internal/output"
+	"devkit/internal/safety"
+)
+
+// Checklist shows items as a list of checkboxes, all of them checked, so
+// that the user can leave some out before a bulk operation is applied. It
+// returns the indexes of the checked items in order, and safety.ErrAborted
+// when the user cancels.
+func Checklist(prompt string, items []string) ([]int, error) {
+	if !Available() {
+		return nil, fmt.Errorf("--interactive needs a terminal")
+	}
+	if len(items) == 0 {
+		return nil, nil
+	}
+
+	fd := int(os.Stdin.Fd())
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read from the terminal: %w", err)
+	}
+	defer term.Restore(fd, state)
+
+	c := &checklist{prompt: prompt, items: items, checked: make([]bool, len(items)), frame: frame{out: os.Stderr}}
+	for i := range c.checked {
+		c.checked[i] = true
+	}
+	return c.run(os.Stdin)
+}
+
+// checklist is the state of one checklist on the terminal
+type checklist struct {
+	prompt  string
+	items   []string
+	checked []bool
+	cursor  int // index of the highlighted item
+	offset  int // index of the first visible item
+	frame
+}
+
+func (c *checklist) run(in io.Reader) ([]int, error) {
+	buf := make([]byte, 64)
+	for {
+		c.render()
+		n, err := in.Read(buf)
+		if err != nil {
+			c.clear()
+			return nil, safety.ErrAborted
+		}
+
+		for key := buf[:n]; len(key) > 0; {
+			switch {
+			case key[0] == '\r' || key[0] == '\n':
+				c.clear()
+				return c.selected(), nil
+			case key[0] == 3 || key[0] == 4 || (key[0] == 27 && len(key) == 1):
+				// Ctrl-C, Ctrl-D or a lone Esc
+				c.clear()
+				return nil, safety.ErrAborted
+			case key[0] == 27 && len(key) >= 3 && (key[1] == '[' || key[1] == 'O'):
+				switch key[2] {
+				case 'A':
+					c.move(-1)
+				case 'B':
+					c.move(1)
+				}
+				key = key[3:]
+				continue
+			case key[0] == 27:
+				// Other escape sequences (function keys, Alt-…) are ignored
+				key = nil
+				continue
+			case key[0] == 16 || key[0] == 11 || key[0] == 'k':
+				c.move(-1) // Ctrl-P, Ctrl-K, k
+			case key[0] == 14 || key[0] == 9 || key[0] == 'j':
+				c.move(1) // Ctrl-N, Tab, j
+			case key[0] == ' ' || key[0] == 'x':
+				c.checked[c.cursor] = !c.checked[c.cursor]
+			case key[0] == 'a':
+				c.toggleAll()
+			}
+			key = key[1:]
+		}
+	}
+}
+
+func (c *checklist) move(delta int) {
+	c.cursor = (c.cursor + delta + len(c.items)) % len(c.items)
+	if c.cursor < c.offset {
+		c.offset = c.cursor
+	} else if c.cursor >= c.offset+visibleRows {
+		c.offset = c.cursor - visibleRows + 1
+	}
+}
+
+// toggleAll unchecks every item when all are checked, and checks them all
+// otherwise
+func (c *checklist) toggleAll() {
+	all := len(c.selected()) == len(c.items)
+	for i := range c.checked {
+		c.checked[i] = !all
+	}
+}
+
+func (c *checklist) selected() []int {
+	indexes := []int{}
+	for i, checked := range c.checked {
+		if checked {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
+
+// render redraws the prompt, the number of checked items and the visible
+// items, then leaves the cursor on the prompt line
+func (c *checklist) render() {
+	width := c.width()
+
+	var b strings.Builder
+	c.rewind(&b)
+	fmt.Fprintf(&b, "%s %s\x1b[K\r\n", output.Accent(c.prompt), output.Muted(i18n.T("picker.list_keys")))
+	fmt.Fprintf(&b, "  %s\x1b[K", output.Muted(i18n.T("picker.checked", len(c.selected()), len(c.items))))
+
+	lines := 1
+	for i := c.offset; i < len(c.items) && i < c.offset+visibleRows; i++ {
+		box := "[ ] "
+		if c.checked[i] {
+			box = "[x] "
+		}
+		line := box + truncate(c.items[i], width-7)
+		if i == c.cursor {
+			line = output.Accent("> " + line)
+		} else {
+			line = "  " + line
+		}
+		fmt.Fprintf(&b, "\r\n%s\x1b[K", line)
+		lines++
+	}
+	b.WriteString("\x1b[J")
+
+	fmt.Fprintf(&b, "\x1b[%dA\r", lines)
+	c.drawn = 0
+	io.WriteString(c.out, b.String())
+}
@@ -0,0 +1,363 @@
+// Package picker lets the user choose a file with a fuzzy finder when a
+// command that needs a path is run in a terminal without one, and review
+// the items of a bulk operation in a checklist before it is applied.
+package picker
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+	"devkit/internal/i18n"
+	"devkit/internal/matcher"
+	"devkit/internal/output"
+	"devkit/internal/workspace"
+)
+
+// ErrCancelled is returned when the user leaves the picker without
+// choosing a file
+var ErrCancelled = fmt.Errorf("no file selected")
+
+const (
+	// maxFiles bounds the candidates so that the picker opens quickly in
+	// very large trees
+	maxFiles = 50000
+	// visibleRows is the number of matches shown below the query
+	visibleRows = 10
+)
+
+var errEnough = errors.New("enough files")
+
+// Available reports whether a picker can be shown, i.e. both stdin and
+// stderr are terminals
+func Available() bool {
+	return term.IsTerminal(int(os.Stdin.Fd())) && term.IsTerminal(int(os.Stderr.Fd()))
+}
+
+// ExactArgs is cobra.ExactArgs(n), except that fewer arguments are accepted
+// when a picker can be shown for the missing paths
+func ExactArgs(n int) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, args []string) error {
+		if len(args) < n && Available() {
+			return nil
+		}
+		return cobra.ExactArgs(n)(cmd, args)
+	}
+}
+
+// MinimumNArgs is cobra.MinimumNArgs(n), except that fewer arguments are
+// accepted when a picker can be shown for the missing paths
+func MinimumNArgs(n int) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, args []string) error {
+		if len(args) < n && Available() {
+			return nil
+		}
+		return cobra.MinimumNArgs(n)(cmd, args)
+	}
+}
+
+// Fill asks for the paths missing from args, one picker per missing
+// argument, with the matching prompt (the generic prompt when prompts is
+// shorter). Only files with one of the given extensions are offered when
+// extensions is not empty. Picker errors, such as a cancelled picker, are
+// not usage errors, so the usage of cmd is not printed for them.
+func Fill(cmd *cobra.Command, args []string, n int, prompts []string, extensions ...string) ([]string, error) {
+	for i := len(args); i < n; i++ {
+		cmd.SilenceUsage = true
+		prompt := i18n.T("picker.select_file")
+		if i < len(prompts) {
+			prompt = prompts[i]
+		}
+		path, err := File(prompt, extensions...)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, path)
+	}
+	return args, nil
+}
+
+// File lists the files under the working directory, skipping .git and the
+// workspace ignore patterns, and lets the user narrow them down by typing.
+// It returns the chosen path relative to the working directory.
+func File(prompt string, extensions ...string) (string, error) {
+	if !Available() {
+		return "", fmt.Errorf("a file is required (no terminal to pick one from)")
+	}
+
+	files, err := listFiles(extensions)
+	if err != nil {
+		return "", err
+	}
+	if len(files) == 0 {
+		return "", fmt.Errorf("no files to pick from in the current directory")
+	}
+
+	fd := int(os.Stdin.Fd())
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return "", fmt.Errorf("cannot read from the terminal: %w", err)
+	}
+	defer term.Restore(fd, state)
+
+	p := &session{prompt: prompt, files: files, frame: frame{out: os.Stderr}}
+	p.filter()
+	return p.run(os.Stdin)
+}
+
+func listFiles(extensions []string) ([]string, error) {
+	opts := matcher.Options{
+		Recursive:  true,
+		Exclude:    []string{".git"},
+		Extensions: extensions,
+	}
+	if ws := workspace.Current(); ws != nil {
+		opts.Exclude = append(opts.Exclude, ws.Ignore...)
+		opts.IgnoreProfiles = ws.IgnoreProfiles
+	}
+	m, err := matcher.New(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	err = m.Walk([]string{"."}, func(path string, info os.FileInfo) error {
+		files = append(files, filepath.ToSlash(path))
+		if len(files) >= maxFiles {
+			return errEnough
+		}
+		return nil
+	})
+	if err != nil && err != errEnough {
+		return nil, err
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// session is the state of one picker on the terminal
+type session struct {
+	prompt  string
+	files   []string
+	matches []string
+	query   []rune
+	cursor  int // index of the highlighted match
+	offset  int // index of the first visible match
+	frame
+}
+
+// frame is the part of the terminal a picker draws on, below the cursor
+type frame struct {
+	drawn int // lines drawn below the prompt line by the last render
+	out   io.Writer
+}
+
+func (p *session) run(in io.Reader) (string, error) {
+	buf := make([]byte, 64)
+	for {
+		p.render()
+		n, err := in.Read(buf)
+		if err != nil {
+			p.clear()
+			return "", ErrCancelled
+		}
+
+		for key := buf[:n]; len(key) > 0; {
+			switch {
+			case key[0] == '\r' || key[0] == '\n':
+				p.clear()
+				if len(p.matches) == 0 {
+					return "", ErrCancelled
+				}
+				return p.matches[p.cursor], nil
+			case key[0] == 3 || key[0] == 4 || (key[0] == 27 && len(key) == 1):
+				// Ctrl-C, Ctrl-D or a lone Esc
+				p.clear()
+				return "", ErrCancelled
+			case key[0] == 27 && len(key) >= 3 && (key[1] == '[' || key[1] == 'O'):
+				switch key[2] {
+				case 'A':
+					p.move(-1)
+				case 'B':
+					p.move(1)
+				}
+				key = key[3:]
+				continue
+			case key[0] == 27:
+				// Other escape sequences (function keys, Alt-…) are ignored
+				key = nil
+				continue
+			case key[0] == 16 || key[0] == 11:
+				p.move(-1) // Ctrl-P, Ctrl-K
+			case key[0] == 14 || key[0] == 9:
+				p.move(1) // Ctrl-N, Tab
+			case key[0] == 127 || key[0] == 8:
+				if len(p.query) > 0 {
+					p.query = p.query[:len(p.query)-1]
+					p.filter()
+				}
+			case key[0] == 21:
+				p.query = nil // Ctrl-U
+				p.filter()
+			case key[0] < 32:
+			default:
+				r, size := utf8.DecodeRune(key)
+				if r != utf8.RuneError && unicode.IsPrint(r) {
+					p.query = append(p.query, r)
+					p.filter()
+				}
+				key = key[size:]
+				continue
+			}
+			key = key[1:]
+		}
+	}
+}
+
+func (p *session) move(delta int) {
+	if len(p.matches) == 0 {
+		return
+	}
+	p.cursor = (p.cursor + delta + len(p.matches)) % len(p.matches)
+	if p.cursor < p.offset {
+		p.offset = p.cursor
+	} else if p.cursor >= p.offset+visibleRows {
+		p.offset = p.cursor - visibleRows + 1
+	}
+}
+
+// filter ranks the files against the query, best matches first
+func (p *session) filter() {
+	p.cursor, p.offset = 0, 0
+	if len(p.query) == 0 {
+		p.matches = p.files
+		return
+	}
+
+	type match struct {
+		path  string
+		score int
+	}
+	query := strings.ToLower(string(p.query))
+	var ranked []match
+	for _, path := range p.files {
+		if score, ok := Score(query, path); ok {
+			ranked = append(ranked, match{path, score})
+		}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].score > ranked[j].score
+	})
+
+	p.matches = make([]string, len(ranked))
+	for i, m := range ranked {
+		p.matches[i] = m.path
+	}
+}
+
+// Score reports whether the characters of query (in lower case) appear in
+// path in order, and how well they match: consecutive characters, matches
+// at the start of a path segment or word and matches in the file name
+// rank higher, long paths lower.
+func Score(query, path string) (int, bool) {
+	lower := []rune(strings.ToLower(path))
+	orig := []rune(path)
+	base := len(lower) - len([]rune(filepath.Base(path)))
+
+	score, qi, prev := 0, 0, -2
+	q := []rune(query)
+	for i := 0; i < len(lower) && qi < len(q); i++ {
+		if lower[i] != q[qi] {
+			continue
+		}
+		score++
+		if i == prev+1 {
+			score += 4
+		}
+		if i == 0 || strings.ContainsRune("/._- ", orig[i-1]) ||
+			(unicode.IsUpper(orig[i]) && unicode.IsLower(orig[i-1])) {
+			score += 3
+		}
+		if i >= base {
+			score += 2
+		}
+		prev = i
+		qi++
+	}
+	if qi < len(q) {
+		return 0, false
+	}
+	return score*100 - len(lower), true
+}
+
+// render redraws the prompt, the query and the visible matches. In raw
+// mode the terminal does not translate "\n", so lines end in "\r\n".
+func (p *session) render() {
+	width := p.width()
+
+	var b strings.Builder
+	p.rewind(&b)
+	fmt.Fprintf(&b, "%s %s\x1b[K\r\n", output.Accent(p.prompt), output.Muted(i18n.T("picker.keys")))
+	fmt.Fprintf(&b, "> %s\x1b[K\r\n", string(p.query))
+	fmt.Fprintf(&b, "  %s\x1b[K", output.Muted(fmt.Sprintf("%d/%d", len(p.matches), len(p.files))))
+
+	lines := 2
+	for i := p.offset; i < len(p.matches) && i < p.offset+visibleRows; i++ {
+		line := truncate(p.matches[i], width-3)
+		if i == p.cursor {
+			line = output.Accent("> " + line)
+		} else {
+			line = "  " + line
+		}
+		fmt.Fprintf(&b, "\r\n%s\x1b[K", line)
+		lines++
+	}
+	b.WriteString("\x1b[J")
+
+	// Leave the cursor at the end of the query
+	fmt.Fprintf(&b, "\x1b[%dA\r\x1b[%dC", lines-1, 2+len(p.query))
+	p.drawn = 1
+	io.WriteString(p.out, b.String())
+}
+
+// rewind moves the cursor back to the prompt line
+func (f *frame) rewind(b *strings.Builder) {
+	if f.drawn > 0 {
+		fmt.Fprintf(b, "\x1b[%dA", f.drawn)
+	}
+	b.WriteString("\r")
+}
+
+// clear erases the picker from the terminal
+func (f *frame) clear() {
+	var b strings.Builder
+	f.rewind(&b)
+	b.WriteString("\x1b[J")
+	f.drawn = 0
+	io.WriteString(f.out, b.String())
+}
+
+// width returns the width of the terminal, 80 when it is unknown
+func (f *frame) width() int {
+	if w, _, err := term.GetSize(int(os.Stderr.Fd())); err == nil && w > 0 {
+		return w
+	}
+	return 80
+}
+
+// truncate shortens a path to width characters, keeping its end
+func truncate(path string, width int) string {
+	runes := []rune(path)
+	if width < 4 || len(runes) <= width {
+		return path
+	}
+	return "…" + string(runes[len(runes)-width+1:])
+}
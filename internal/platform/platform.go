@@ -0,0 +1,60 @@
+// Package platform holds the operating system specific bits of devkit. The
+// portable API lives in this file; the _unix.go and _windows.go files fill
+// in the per-platform parts behind build tags.
+package platform
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Shells lists the values accepted by ShellCommand
+var Shells = []string{"sh", "bash", "zsh", "cmd", "powershell", "pwsh", "none"}
+
+// ShellCommand builds the command that runs cmdline through shell. An empty
+// shell selects the platform default (sh, or cmd.exe on Windows); "none"
+// runs the first word as a program with the rest as arguments, without any
+// shell.
+func ShellCommand(shell, cmdline string) (*exec.Cmd, error) {
+	if shell == "" {
+		shell = DefaultShell()
+	}
+
+	switch shell {
+	case "sh", "bash", "zsh":
+		return exec.Command(shell, "-c", cmdline), nil
+	case "cmd":
+		return cmdShell(cmdline), nil
+	case "powershell", "pwsh":
+		return exec.Command(shell, "-NoProfile", "-NonInteractive", "-Command", cmdline), nil
+	case "none":
+		fields := strings.Fields(cmdline)
+		if len(fields) == 0 {
+			return nil, fmt.Errorf("empty command")
+		}
+		return exec.Command(fields[0], fields[1:]...), nil
+	}
+	return nil, fmt.Errorf("unsupported shell: %s (supported: %s)", shell, strings.Join(Shells, ", "))
+}
+
+// ValidateFileName reports whether name can be used as a single path
+// element on this platform. Separators are always rejected so that a
+// rename can never move a file into another directory.
+func ValidateFileName(name string) error {
+	if name == "" || name == "." || name == ".." {
+		return fmt.Errorf("invalid file name: %q", name)
+	}
+	if strings.ContainsRune(name, '/') || strings.ContainsRune(name, os.PathSeparator) {
+		return fmt.Errorf("invalid file name %q: contains a path separator", name)
+	}
+	return validateFileName(name)
+}
+
+// Attributes returns the platform file attributes worth showing next to the
+// mode, such as "readonly" and "hidden" on Windows. It returns nil when the
+// platform has none beyond the mode bits.
+func Attributes(info os.FileInfo) []string {
+	return attributes(info)
+}
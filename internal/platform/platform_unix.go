@@ -0,0 +1,29 @@
+//go:build !windows
+
+package platform
+
+import (
+	"os"
+	"os/exec"
+)
+
+// CaseInsensitivePaths reports whether file names differ only by case on
+// this platform's usual file systems
+const CaseInsensitivePaths = false
+
+// DefaultShell returns the shell used when none is configured
+func DefaultShell() string {
+	return "sh"
+}
+
+func cmdShell(cmdline string) *exec.Cmd {
+	return exec.Command("cmd", "/C", cmdline)
+}
+
+func validateFileName(name string) error {
+	return nil
+}
+
+func attributes(info os.FileInfo) []string {
+	return nil
+}
@@ -0,0 +1,83 @@
+//go:build windows
+
+package platform
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+)
+
+// CaseInsensitivePaths reports whether file names differ only by case on
+// this platform's usual file systems
+const CaseInsensitivePaths = true
+
+// DefaultShell returns the shell used when none is configured
+func DefaultShell() string {
+	return "cmd"
+}
+
+// cmdShell passes the command line to cmd.exe verbatim: Go's usual argument
+// quoting would escape the quotes that cmd /C expects to see unchanged
+func cmdShell(cmdline string) *exec.Cmd {
+	comspec := os.Getenv("COMSPEC")
+	if comspec == "" {
+		comspec = "cmd.exe"
+	}
+	c := exec.Command(comspec)
+	c.SysProcAttr = &syscall.SysProcAttr{CmdLine: fmt.Sprintf(`%s /S /C "%s"`, syscall.EscapeArg(comspec), cmdline)}
+	return c
+}
+
+// reservedNames are device names that cannot be used as file names, with
+// or without an extension
+var reservedNames = map[string]bool{
+	"CON": true, "PRN": true, "AUX": true, "NUL": true,
+	"COM1": true, "COM2": true, "COM3": true, "COM4": true, "COM5": true,
+	"COM6": true, "COM7": true, "COM8": true, "COM9": true,
+	"LPT1": true, "LPT2": true, "LPT3": true, "LPT4": true, "LPT5": true,
+	"LPT6": true, "LPT7": true, "LPT8": true, "LPT9": true,
+}
+
+func validateFileName(name string) error {
+	for _, r := range name {
+		if r < 32 || strings.ContainsRune(`<>:"|?*`, r) {
+			return fmt.Errorf("invalid file name %q: %q is not allowed on Windows", name, r)
+		}
+	}
+	if strings.HasSuffix(name, " ") || strings.HasSuffix(name, ".") {
+		return fmt.Errorf("invalid file name %q: names cannot end with a space or dot on Windows", name)
+	}
+	base := strings.ToUpper(strings.SplitN(name, ".", 2)[0])
+	if reservedNames[strings.TrimRight(base, " ")] {
+		return fmt.Errorf("invalid file name %q: %s is a reserved device name on Windows", name, base)
+	}
+	return nil
+}
+
+func attributes(info os.FileInfo) []string {
+	data, ok := info.Sys().(*syscall.Win32FileAttributeData)
+	if !ok {
+		return nil
+	}
+
+	var attrs []string
+	flags := []struct {
+		bit  uint32
+		name string
+	}{
+		{syscall.FILE_ATTRIBUTE_READONLY, "readonly"},
+		{syscall.FILE_ATTRIBUTE_HIDDEN, "hidden"},
+		{syscall.FILE_ATTRIBUTE_SYSTEM, "system"},
+		{syscall.FILE_ATTRIBUTE_ARCHIVE, "archive"},
+		{syscall.FILE_ATTRIBUTE_REPARSE_POINT, "reparse-point"},
+	}
+	for _, f := range flags {
+		if data.FileAttributes&f.bit != 0 {
+			attrs = append(attrs, f.name)
+		}
+	}
+	return attrs
+}
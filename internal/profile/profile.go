@@ -0,0 +1,144 @@
+// Package profile measures the resources a command uses for --profile. The
+// process counters come from the operating system (profile_unix.go and
+// profile_windows.go); network activity is counted by internal/netutil,
+// which every network command dials and sends HTTP requests through.
+package profile
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	requests    atomic.Int64
+	connections atomic.Int64
+)
+
+// CountRequest records an HTTP request, including each retry and redirect
+func CountRequest() {
+	requests.Add(1)
+}
+
+// CountConnection records an outgoing TCP connection or UDP socket
+func CountConnection() {
+	connections.Add(1)
+}
+
+// usage holds the process counters; fields the platform cannot provide are nil
+type usage struct {
+	cpu     *time.Duration
+	peakRSS *uint64
+	read    *uint64
+	written *uint64
+}
+
+// Report is the resource usage of a command. Values the platform does not
+// provide are left out.
+type Report struct {
+	Command      string   `json:"command"`
+	WallMs       float64  `json:"wall_ms"`
+	CPUMs        *float64 `json:"cpu_ms,omitempty"`
+	PeakMemory   *uint64  `json:"peak_memory_bytes,omitempty"`
+	BytesRead    *uint64  `json:"bytes_read,omitempty"`
+	BytesWritten *uint64  `json:"bytes_written,omitempty"`
+	HTTPRequests int64    `json:"http_requests"`
+	Connections  int64    `json:"connections"`
+}
+
+var (
+	started time.Time
+	before  usage
+)
+
+// Start takes the counters the report is measured from
+func Start() {
+	before = readUsage()
+	requests.Store(0)
+	connections.Store(0)
+	started = time.Now()
+}
+
+// Started reports whether Start was called
+func Started() bool {
+	return !started.IsZero()
+}
+
+// Finish returns the usage since Start for command
+func Finish(command string) Report {
+	wall := time.Since(started)
+	after := readUsage()
+
+	report := Report{
+		Command:      command,
+		WallMs:       milliseconds(wall),
+		HTTPRequests: requests.Load(),
+		Connections:  connections.Load(),
+	}
+	if after.cpu != nil && before.cpu != nil {
+		cpu := milliseconds(*after.cpu - *before.cpu)
+		report.CPUMs = &cpu
+	}
+	// The peak is that of the whole process, which is the command
+	report.PeakMemory = after.peakRSS
+	if after.read != nil && before.read != nil {
+		read := *after.read - *before.read
+		report.BytesRead = &read
+	}
+	if after.written != nil && before.written != nil {
+		written := *after.written - *before.written
+		report.BytesWritten = &written
+	}
+	return report
+}
+
+// Print writes the report as a footer, or as a JSON block when format is
+// "json"
+func (r Report) Print(w io.Writer, format string) {
+	if format == "json" {
+		data, _ := json.MarshalIndent(map[string]interface{}{"profile": r}, "", "  ")
+		fmt.Fprintln(w, string(data))
+		return
+	}
+
+	parts := []string{fmt.Sprintf("wall %s", formatMs(r.WallMs))}
+	if r.CPUMs != nil {
+		parts = append(parts, fmt.Sprintf("cpu %s", formatMs(*r.CPUMs)))
+	}
+	if r.PeakMemory != nil {
+		parts = append(parts, fmt.Sprintf("peak mem %s", formatBytes(*r.PeakMemory)))
+	}
+	if r.BytesRead != nil && r.BytesWritten != nil {
+		parts = append(parts, fmt.Sprintf("read %s", formatBytes(*r.BytesRead)), fmt.Sprintf("written %s", formatBytes(*r.BytesWritten)))
+	}
+	parts = append(parts, fmt.Sprintf("http requests %d", r.HTTPRequests), fmt.Sprintf("connections %d", r.Connections))
+	fmt.Fprintf(w, "\nProfile (%s, %s/%s): %s\n", r.Command, runtime.GOOS, runtime.GOARCH, strings.Join(parts, ", "))
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+func formatMs(ms float64) string {
+	if ms >= 1000 {
+		return fmt.Sprintf("%.2fs", ms/1000)
+	}
+	return fmt.Sprintf("%.1fms", ms)
+}
+
+func formatBytes(b uint64) string {
+	const unit = 1024
+	if b < unit {
+		return fmt.Sprintf("%d B", b)
+	}
+	div, exp := uint64(unit), 0
+	for n := b / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(b)/float64(div), "KMGTPE"[exp])
+}
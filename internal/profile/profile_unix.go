@@ -0,0 +1,56 @@
+//go:build !windows
+
+package profile
+
+import (
+	"bufio"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// readUsage reads CPU time and peak memory from getrusage and, on Linux,
+// the bytes read and written from /proc/self/io
+func readUsage() usage {
+	var u usage
+	var ru syscall.Rusage
+	if syscall.Getrusage(syscall.RUSAGE_SELF, &ru) == nil {
+		cpu := time.Duration(ru.Utime.Nano() + ru.Stime.Nano())
+		u.cpu = &cpu
+		// ru_maxrss is in bytes on macOS and in kilobytes elsewhere
+		peak := uint64(ru.Maxrss)
+		if runtime.GOOS != "darwin" {
+			peak *= 1024
+		}
+		u.peakRSS = &peak
+	}
+
+	file, err := os.Open("/proc/self/io")
+	if err != nil {
+		return u
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		name, value, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+		n, err := strconv.ParseUint(strings.TrimSpace(value), 10, 64)
+		if err != nil {
+			continue
+		}
+		// rchar and wchar count every read and write call: files, pipes,
+		// the terminal and sockets
+		switch name {
+		case "rchar":
+			u.read = &n
+		case "wchar":
+			u.written = &n
+		}
+	}
+	return u
+}
@@ -0,0 +1,30 @@
+//go:build windows
+
+package profile
+
+import (
+	"syscall"
+	"time"
+)
+
+// readUsage reads the CPU time of the process; peak memory and I/O are not
+// reported on Windows
+func readUsage() usage {
+	var u usage
+	process, err := syscall.GetCurrentProcess()
+	if err != nil {
+		return u
+	}
+	var creation, exit, kernel, user syscall.Filetime
+	if syscall.GetProcessTimes(process, &creation, &exit, &kernel, &user) == nil {
+		cpu := filetimeDuration(kernel) + filetimeDuration(user)
+		u.cpu = &cpu
+	}
+	return u
+}
+
+// filetimeDuration converts a FILETIME holding a duration, in 100-nanosecond
+// intervals
+func filetimeDuration(ft syscall.Filetime) time.Duration {
+	return time.Duration(int64(ft.HighDateTime)<<32|int64(ft.LowDateTime)) * 100
+}
@@ -0,0 +1,185 @@
+package safety
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"devkit/internal/config"
+	"devkit/internal/i18n"
+)
+
+// Configuration keys read from .devkit.yaml
+const (
+	// KeyYes skips every confirmation prompt (set from the global --yes
+	// flag or DEVKIT_YES)
+	KeyYes = "yes"
+	// KeyDryRunFirst requires a matching --dry-run before a destructive run
+	KeyDryRunFirst = "safety.dry_run_first"
+	// KeyDryRunTTL is how long a recorded dry run stays valid (default 1h)
+	KeyDryRunTTL = "safety.dry_run_ttl"
+)
+
+// ignoredFlags do not change what a destructive command does, so they are
+// left out of the invocation fingerprint
+var ignoredFlags = map[string]bool{
+	"dry-run": true,
+	"yes":     true,
+	"output":  true,
+	"verbose": true,
+	"quiet":   true,
+	"lang":    true,
+}
+
+// ErrAborted is returned when the user declines a confirmation prompt
+var ErrAborted = fmt.Errorf("operation aborted")
+
+// CheckDryRun enforces the dry-run-first policy. A dry run is recorded for
+// the invocation; a real run fails unless the same invocation was dry-run
+// recently. It is a no-op when safety.dry_run_first is disabled.
+func CheckDryRun(cmd *cobra.Command, args []string, dryRun bool) error {
+	if !config.GetBool(KeyDryRunFirst) {
+		return nil
+	}
+
+	records := loadRecords()
+	id := fingerprint(cmd, args)
+
+	if dryRun {
+		records[id] = time.Now()
+		return saveRecords(records)
+	}
+
+	ttl := time.Hour
+	if v := config.GetString(KeyDryRunTTL); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			ttl = d
+		}
+	}
+
+	recorded, ok := records[id]
+	if !ok || time.Since(recorded) > ttl {
+		return fmt.Errorf("%s is configured to require a dry run first: re-run with --dry-run, review the changes, then run again", KeyDryRunFirst)
+	}
+	return nil
+}
+
+// Change kinds accepted by ConfirmChanges
+const (
+	DeleteFiles = "safety.delete_files"
+	DeletePaths = "safety.delete_paths"
+	ModifyFiles = "safety.modify_files"
+	RenameFiles = "safety.rename_files"
+	RemoveKeys  = "safety.remove_keys"
+)
+
+// ConfirmChanges asks "N files will be deleted — proceed?" (or the message
+// for another change kind) and returns ErrAborted if the user declines.
+// Nothing is asked when count is zero.
+func ConfirmChanges(kind string, count int) error {
+	if count == 0 {
+		return nil
+	}
+	return Confirm(i18n.T(kind, count))
+}
+
+// Confirm prompts the user on stderr and returns ErrAborted unless the
+// answer is yes. It succeeds immediately when --yes is set and fails when
+// stdin is not a terminal, so scripts never hang on a prompt.
+func Confirm(prompt string) error {
+	if config.GetBool(KeyYes) {
+		return nil
+	}
+
+	stat, err := os.Stdin.Stat()
+	if err != nil || (stat.Mode()&os.ModeCharDevice) == 0 {
+		return fmt.Errorf("confirmation required: re-run with --yes to proceed non-interactively")
+	}
+
+	fmt.Fprintf(os.Stderr, "%s %s ", prompt, i18n.T("safety.choices"))
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+
+	for _, yes := range strings.Split(i18n.T("safety.yes_answers"), ",") {
+		if answer == yes {
+			return nil
+		}
+	}
+	return ErrAborted
+}
+
+// fingerprint identifies an invocation by command path, arguments and the
+// flags that were explicitly set
+func fingerprint(cmd *cobra.Command, args []string) string {
+	parts := []string{cmd.CommandPath()}
+	if wd, err := os.Getwd(); err == nil {
+		parts = append(parts, wd)
+	}
+	parts = append(parts, args...)
+
+	var flags []string
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		if !ignoredFlags[f.Name] {
+			flags = append(flags, "--"+f.Name+"="+f.Value.String())
+		}
+	})
+	sort.Strings(flags)
+	parts = append(parts, flags...)
+
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(sum[:])
+}
+
+func recordsPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".devkit", "dry-runs.json"), nil
+}
+
+func loadRecords() map[string]time.Time {
+	records := make(map[string]time.Time)
+	path, err := recordsPath()
+	if err != nil {
+		return records
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return records
+	}
+	json.Unmarshal(data, &records)
+	return records
+}
+
+func saveRecords(records map[string]time.Time) error {
+	path, err := recordsPath()
+	if err != nil {
+		return fmt.Errorf("failed to locate home directory: %w", err)
+	}
+
+	// Drop stale entries so the file does not grow forever
+	for id, recorded := range records {
+		if time.Since(recorded) > 24*time.Hour {
+			delete(records, id)
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to record dry run: %w", err)
+	}
+	data, _ := json.MarshalIndent(records, "", "  ")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to record dry run: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,215 @@
+package schema
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"math"
+	"path"
+	"sort"
+	"strings"
+)
+
+// Version is the version of the published output schemas. It is bumped
+// whenever a field is renamed or removed from a command's JSON output.
+const Version = 1
+
+//go:embed v1/*.json
+var files embed.FS
+
+// Names returns the names of all commands with a published schema
+func Names() []string {
+	entries, _ := files.ReadDir(fmt.Sprintf("v%d", Version))
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, strings.TrimSuffix(entry.Name(), ".json"))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Normalize converts a command path ("dev uuid" or "dev.uuid") to a schema name
+func Normalize(command string) string {
+	command = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(command), "devkit "))
+	return strings.Join(strings.Fields(strings.ReplaceAll(command, ".", " ")), ".")
+}
+
+// Get returns the full schema of a command's --output json document,
+// including the common success/data/error envelope
+func Get(command string) (map[string]interface{}, error) {
+	name := Normalize(command)
+	data, err := files.ReadFile(path.Join(fmt.Sprintf("v%d", Version), name+".json"))
+	if err != nil {
+		return nil, fmt.Errorf("no schema published for command: %s", strings.ReplaceAll(name, ".", " "))
+	}
+
+	var dataSchema map[string]interface{}
+	if err := json.Unmarshal(data, &dataSchema); err != nil {
+		return nil, fmt.Errorf("invalid schema %s: %w", name, err)
+	}
+
+	title, _ := dataSchema["title"].(string)
+	delete(dataSchema, "title")
+
+	return map[string]interface{}{
+		"$schema":  "https://json-schema.org/draft/2020-12/schema",
+		"$id":      fmt.Sprintf("devkit:schema:v%d:%s", Version, name),
+		"title":    title + " --output json",
+		"version":  Version,
+		"type":     "object",
+		"required": []interface{}{"success"},
+		"properties": map[string]interface{}{
+			"success": map[string]interface{}{"type": "boolean"},
+			"error":   map[string]interface{}{"type": "string"},
+			"data":    dataSchema,
+		},
+	}, nil
+}
+
+// Validate checks a decoded JSON document against a schema and returns a
+// list of violations. It supports the keywords used by the published schemas:
+// type, required, properties, additionalProperties, items, enum, minimum,
+// maximum, exclusiveMinimum, exclusiveMaximum, minLength, maxLength,
+// minItems and maxItems.
+func Validate(schema map[string]interface{}, doc interface{}) []string {
+	var errs []string
+	validate(schema, doc, "$", &errs)
+	return errs
+}
+
+func validate(schema map[string]interface{}, v interface{}, at string, errs *[]string) {
+	if types, ok := schemaTypes(schema["type"]); ok {
+		matched := false
+		for _, t := range types {
+			if typeMatches(t, v) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			*errs = append(*errs, fmt.Sprintf("%s: expected %s, got %s", at, strings.Join(types, " or "), typeName(v)))
+			return
+		}
+	}
+
+	if enum, ok := schema["enum"].([]interface{}); ok {
+		found := false
+		for _, e := range enum {
+			if fmt.Sprint(e) == fmt.Sprint(v) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			*errs = append(*errs, fmt.Sprintf("%s: value %v is not one of %v", at, v, enum))
+		}
+	}
+
+	switch t := v.(type) {
+	case map[string]interface{}:
+		if required, ok := schema["required"].([]interface{}); ok {
+			for _, r := range required {
+				key, _ := r.(string)
+				if _, exists := t[key]; !exists {
+					*errs = append(*errs, fmt.Sprintf("%s: missing required field %q", at, key))
+				}
+			}
+		}
+		props, _ := schema["properties"].(map[string]interface{})
+		additional, _ := schema["additionalProperties"].(map[string]interface{})
+		keys := make([]string, 0, len(t))
+		for key := range t {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if sub, ok := props[key].(map[string]interface{}); ok {
+				validate(sub, t[key], at+"."+key, errs)
+			} else if additional != nil {
+				validate(additional, t[key], at+"."+key, errs)
+			}
+		}
+	case []interface{}:
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range t {
+				validate(items, item, fmt.Sprintf("%s[%d]", at, i), errs)
+			}
+		}
+		if min, ok := schema["minItems"].(float64); ok && float64(len(t)) < min {
+			*errs = append(*errs, fmt.Sprintf("%s: %d items is less than minItems %v", at, len(t), min))
+		}
+		if max, ok := schema["maxItems"].(float64); ok && float64(len(t)) > max {
+			*errs = append(*errs, fmt.Sprintf("%s: %d items is more than maxItems %v", at, len(t), max))
+		}
+	case float64:
+		if min, ok := schema["minimum"].(float64); ok && t < min {
+			*errs = append(*errs, fmt.Sprintf("%s: %v is less than minimum %v", at, t, min))
+		}
+		if max, ok := schema["maximum"].(float64); ok && t > max {
+			*errs = append(*errs, fmt.Sprintf("%s: %v is greater than maximum %v", at, t, max))
+		}
+		if min, ok := schema["exclusiveMinimum"].(float64); ok && t <= min {
+			*errs = append(*errs, fmt.Sprintf("%s: %v is not greater than exclusiveMinimum %v", at, t, min))
+		}
+		if max, ok := schema["exclusiveMaximum"].(float64); ok && t >= max {
+			*errs = append(*errs, fmt.Sprintf("%s: %v is not less than exclusiveMaximum %v", at, t, max))
+		}
+	case string:
+		length := float64(len([]rune(t)))
+		if min, ok := schema["minLength"].(float64); ok && length < min {
+			*errs = append(*errs, fmt.Sprintf("%s: length %v is less than minLength %v", at, length, min))
+		}
+		if max, ok := schema["maxLength"].(float64); ok && length > max {
+			*errs = append(*errs, fmt.Sprintf("%s: length %v is greater than maxLength %v", at, length, max))
+		}
+	}
+}
+
+func schemaTypes(v interface{}) ([]string, bool) {
+	switch t := v.(type) {
+	case string:
+		return []string{t}, true
+	case []interface{}:
+		types := make([]string, 0, len(t))
+		for _, item := range t {
+			if s, ok := item.(string); ok {
+				types = append(types, s)
+			}
+		}
+		return types, len(types) > 0
+	case []string:
+		return t, len(t) > 0
+	}
+	return nil, false
+}
+
+func typeMatches(t string, v interface{}) bool {
+	switch t {
+	case "integer":
+		f, ok := v.(float64)
+		return ok && f == math.Trunc(f)
+	case "number":
+		_, ok := v.(float64)
+		return ok
+	default:
+		return typeName(v) == t
+	}
+}
+
+func typeName(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", v)
+}
@@ -0,0 +1,401 @@
+// Package sshexec runs devkit on other hosts through the system ssh client,
+// so that ~/.ssh/config, the agent and known_hosts apply as usual. The
+// devkit binary is installed on the remote host on first use and reused for
+// as long as it matches the local one.
+package sshexec
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// DefaultRemotePath is where the binary is installed, relative to the
+// remote user's home directory
+const DefaultRemotePath = ".cache/devkit/devkit"
+
+// Options are the connection and install settings shared by every host
+type Options struct {
+	// Port overrides the SSH port; 0 leaves it to ssh and ~/.ssh/config
+	Port int
+	// Identity is a private key file passed to ssh -i
+	Identity string
+	// SSHOptions are extra ssh -o options, such as StrictHostKeyChecking=no
+	SSHOptions []string
+	// ConnectTimeout bounds the SSH connection setup
+	ConnectTimeout time.Duration
+	// Timeout bounds the whole run on a host, install included; 0 for none
+	Timeout time.Duration
+	// Binary is the local binary to install. Empty uses the running
+	// executable, which must match the remote platform.
+	Binary string
+	// RemotePath is where the binary is installed on the remote host
+	RemotePath string
+	// NoInstall runs RemotePath as it is, without checking or copying it
+	NoInstall bool
+}
+
+// AddFlags defines the connection and install flags of the commands that
+// run devkit on other hosts
+func AddFlags(cmd *cobra.Command) {
+	flags := cmd.Flags()
+	flags.IntP("port", "p", 0, "SSH port (default from ssh and ~/.ssh/config)")
+	flags.StringP("identity", "i", "", "Private key file for SSH authentication")
+	flags.StringArray("ssh-option", nil, "Extra ssh -o option, e.g. StrictHostKeyChecking=accept-new (repeatable)")
+	flags.Duration("connect-timeout", 10*time.Second, "Timeout for establishing each SSH connection")
+	flags.Duration("timeout", 0, "Timeout for the whole run on each host (0 for none)")
+	flags.String("binary", "", "Local devkit binary to install (default: this binary)")
+	flags.String("remote-path", DefaultRemotePath, "Remote binary path, relative to the remote home directory")
+	flags.Bool("no-install", false, "Run --remote-path as it is without checking or copying the binary")
+}
+
+// FromCommand reads the flags defined by AddFlags
+func FromCommand(cmd *cobra.Command) (Options, error) {
+	port, _ := cmd.Flags().GetInt("port")
+	identity, _ := cmd.Flags().GetString("identity")
+	sshOpts, _ := cmd.Flags().GetStringArray("ssh-option")
+	connectTimeout, _ := cmd.Flags().GetDuration("connect-timeout")
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	binary, _ := cmd.Flags().GetString("binary")
+	remotePath, _ := cmd.Flags().GetString("remote-path")
+	noInstall, _ := cmd.Flags().GetBool("no-install")
+
+	if port < 0 || port > 65535 {
+		return Options{}, fmt.Errorf("invalid port: %d", port)
+	}
+	if remotePath == "" {
+		return Options{}, fmt.Errorf("remote path cannot be empty")
+	}
+	if binary != "" {
+		if _, err := os.Stat(binary); err != nil {
+			return Options{}, fmt.Errorf("binary not found: %s", binary)
+		}
+	}
+	return Options{
+		Port:           port,
+		Identity:       identity,
+		SSHOptions:     sshOpts,
+		ConnectTimeout: connectTimeout,
+		Timeout:        timeout,
+		Binary:         binary,
+		RemotePath:     remotePath,
+		NoInstall:      noInstall,
+	}, nil
+}
+
+// Result is the outcome of a devkit command on one host
+type Result struct {
+	Host     string      `json:"host"`
+	ExitCode int         `json:"exit_code"`
+	Install  *Install    `json:"install,omitempty"`
+	Data     interface{} `json:"data,omitempty"`
+	Output   string      `json:"output,omitempty"`
+	Error    string      `json:"error,omitempty"`
+}
+
+// Exec installs the binary on host if needed and runs devkit with args.
+// Failures to connect, install or finish within Timeout are reported in
+// the result with exit code 1.
+func (o Options) Exec(host string, args []string, stdout, stderr io.Writer) Result {
+	ctx := context.Background()
+	if o.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, o.Timeout)
+		defer cancel()
+	}
+
+	r := Result{Host: host}
+	install, err := o.Ensure(ctx, host)
+	if err == nil {
+		if !o.NoInstall {
+			r.Install = &install
+		}
+		r.ExitCode, err = o.Run(ctx, host, install, args, stdout, stderr)
+	}
+	switch {
+	case ctx.Err() == context.DeadlineExceeded:
+		r.ExitCode = 1
+		r.Error = fmt.Sprintf("timed out after %s", o.Timeout)
+	case err != nil:
+		r.ExitCode = 1
+		r.Error = err.Error()
+	}
+	return r
+}
+
+// Decode stores the data of the JSON document the remote command printed,
+// or its raw output when it is not one, and on failure the remote error
+// message
+func (r *Result) Decode(stdout []byte, stderr string) {
+	var doc struct {
+		Data  interface{} `json:"data"`
+		Error string      `json:"error"`
+	}
+	if err := json.Unmarshal(stdout, &doc); err == nil {
+		r.Data = doc.Data
+		if r.Error == "" {
+			r.Error = doc.Error
+		}
+	} else if s := strings.TrimSpace(string(stdout)); s != "" {
+		r.Output = s
+	}
+	if r.ExitCode != 0 && r.Error == "" {
+		lines := strings.Split(strings.TrimSpace(stderr), "\n")
+		r.Error = strings.TrimSpace(lines[len(lines)-1])
+	}
+}
+
+// JSONArgs returns args with --output json added, unless they already
+// choose an output format
+func JSONArgs(args []string) []string {
+	for _, arg := range args {
+		if arg == "-o" || arg == "--output" || strings.HasPrefix(arg, "--output=") || strings.HasPrefix(arg, "-o=") {
+			return args
+		}
+	}
+	return append(append([]string(nil), args...), "--output", "json")
+}
+
+// Install describes the remote binary used for a host
+type Install struct {
+	// Path is the remote binary
+	Path string `json:"path"`
+	// Platform is the remote os/arch, when it was probed
+	Platform string `json:"platform,omitempty"`
+	// Copied reports whether the binary was copied during this run
+	Copied bool `json:"copied"`
+}
+
+// ConnectionError is returned when ssh itself fails (exit status 255), as
+// opposed to the remote command failing
+type ConnectionError struct {
+	Host    string
+	Message string
+}
+
+func (e *ConnectionError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("ssh to %s failed", e.Host)
+	}
+	return fmt.Sprintf("ssh to %s failed: %s", e.Host, e.Message)
+}
+
+// Ensure makes sure host has a devkit binary identical to the local one,
+// copying it when it is missing or differs, and returns where it is
+func (o Options) Ensure(ctx context.Context, host string) (Install, error) {
+	remote := o.RemotePath
+	if remote == "" {
+		remote = DefaultRemotePath
+	}
+	install := Install{Path: remote}
+	if o.NoInstall {
+		return install, nil
+	}
+
+	local := o.Binary
+	if local == "" {
+		exe, err := os.Executable()
+		if err != nil {
+			return install, fmt.Errorf("cannot locate the devkit binary: %w", err)
+		}
+		local = exe
+	}
+	sum, err := fileSHA256(local)
+	if err != nil {
+		return install, fmt.Errorf("read binary error: %w", err)
+	}
+
+	// One round trip reports the platform and the checksum of the current
+	// remote binary, if there is one
+	probe := fmt.Sprintf("uname -sm; (sha256sum %[1]s || shasum -a 256 %[1]s) 2>/dev/null || true", QuotePath(remote))
+	var stdout bytes.Buffer
+	if err := o.run(ctx, host, probe, nil, &stdout); err != nil {
+		return install, err
+	}
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	install.Platform = platformName(lines[0])
+	if len(lines) > 1 {
+		if fields := strings.Fields(lines[1]); len(fields) > 0 && fields[0] == sum {
+			return install, nil
+		}
+	}
+
+	if o.Binary == "" && install.Platform != runtime.GOOS+"/"+runtime.GOARCH {
+		return install, fmt.Errorf("%s is %s but this devkit is built for %s/%s; pass a matching build with --binary",
+			host, install.Platform, runtime.GOOS, runtime.GOARCH)
+	}
+
+	file, err := os.Open(local)
+	if err != nil {
+		return install, fmt.Errorf("read binary error: %w", err)
+	}
+	defer file.Close()
+
+	// Write to a temporary name first so that a concurrent run never
+	// executes a partly copied binary
+	tmp := remote + ".tmp"
+	copyCmd := fmt.Sprintf("mkdir -p %s && cat > %s && chmod 755 %s && mv -f %s %s",
+		QuotePath(path.Dir(remote)), QuotePath(tmp), QuotePath(tmp), QuotePath(tmp), QuotePath(remote))
+	if err := o.run(ctx, host, copyCmd, file, io.Discard); err != nil {
+		return install, fmt.Errorf("copy binary to %s: %w", host, err)
+	}
+	install.Copied = true
+	return install, nil
+}
+
+// Run runs the remote binary with args on host and returns its exit code.
+// The error is non-nil only when the command could not be run at all.
+func (o Options) Run(ctx context.Context, host string, install Install, args []string, stdout, stderr io.Writer) (int, error) {
+	words := []string{QuotePath(install.Path)}
+	for _, arg := range args {
+		words = append(words, Quote(arg))
+	}
+
+	cmd := o.command(ctx, host, strings.Join(words, " "))
+	cmd.Stdout = stdout
+	var tail tailBuffer
+	cmd.Stderr = io.MultiWriter(stderr, &tail)
+	err := cmd.Run()
+
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		return 0, nil
+	case errors.As(err, &exitErr) && exitErr.ExitCode() != 255:
+		return exitErr.ExitCode(), nil
+	case errors.As(err, &exitErr):
+		return 255, &ConnectionError{Host: host, Message: tail.lastLine()}
+	}
+	return -1, fmt.Errorf("ssh: %w", err)
+}
+
+// run runs a shell command on host, failing on any non-zero exit
+func (o Options) run(ctx context.Context, host, script string, stdin io.Reader, stdout io.Writer) error {
+	cmd := o.command(ctx, host, script)
+	cmd.Stdin = stdin
+	cmd.Stdout = stdout
+	var stderr tailBuffer
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		return nil
+	case errors.As(err, &exitErr) && exitErr.ExitCode() == 255:
+		return &ConnectionError{Host: host, Message: stderr.lastLine()}
+	case errors.As(err, &exitErr):
+		if msg := stderr.lastLine(); msg != "" {
+			return fmt.Errorf("remote command failed: %s", msg)
+		}
+		return fmt.Errorf("remote command failed with exit status %d", exitErr.ExitCode())
+	}
+	return fmt.Errorf("ssh: %w", err)
+}
+
+// command builds the ssh invocation. BatchMode keeps ssh from prompting for
+// passwords, which would hang when several hosts run at once.
+func (o Options) command(ctx context.Context, host, script string) *exec.Cmd {
+	args := []string{"-o", "BatchMode=yes"}
+	if o.ConnectTimeout > 0 {
+		secs := int(o.ConnectTimeout.Round(time.Second) / time.Second)
+		args = append(args, "-o", "ConnectTimeout="+strconv.Itoa(max(secs, 1)))
+	}
+	if o.Port > 0 {
+		args = append(args, "-p", strconv.Itoa(o.Port))
+	}
+	if o.Identity != "" {
+		args = append(args, "-i", o.Identity)
+	}
+	for _, opt := range o.SSHOptions {
+		args = append(args, "-o", opt)
+	}
+	args = append(args, "--", host, script)
+	return exec.CommandContext(ctx, "ssh", args...)
+}
+
+// Quote quotes s for a POSIX shell. Words made only of safe characters are
+// left as they are.
+func Quote(s string) string {
+	if s != "" && strings.Trim(s, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_./:=@,+%") == "" {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// QuotePath quotes a remote path, keeping a leading ~/ expandable
+func QuotePath(p string) string {
+	if rest, ok := strings.CutPrefix(p, "~/"); ok {
+		return `"$HOME"/` + Quote(rest)
+	}
+	return Quote(p)
+}
+
+// platformName turns `uname -sm` output such as "Linux x86_64" into the
+// GOOS/GOARCH form
+func platformName(uname string) string {
+	fields := strings.Fields(uname)
+	if len(fields) != 2 {
+		return strings.TrimSpace(uname)
+	}
+	arch := fields[1]
+	switch arch {
+	case "x86_64":
+		arch = "amd64"
+	case "aarch64":
+		arch = "arm64"
+	case "i386", "i686":
+		arch = "386"
+	case "armv6l", "armv7l":
+		arch = "arm"
+	}
+	return strings.ToLower(fields[0]) + "/" + arch
+}
+
+// fileSHA256 returns the hex SHA-256 of a file
+func fileSHA256(name string) (string, error) {
+	file, err := os.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// tailBuffer keeps the last few KB written to it, enough to report the
+// final error line of ssh or the remote command
+type tailBuffer struct {
+	buf []byte
+}
+
+func (t *tailBuffer) Write(p []byte) (int, error) {
+	t.buf = append(t.buf, p...)
+	if len(t.buf) > 4096 {
+		t.buf = t.buf[len(t.buf)-4096:]
+	}
+	return len(p), nil
+}
+
+func (t *tailBuffer) lastLine() string {
+	lines := strings.Split(strings.TrimSpace(string(t.buf)), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
@@ -0,0 +1,423 @@
+// Package stacktrace finds Go panics and goroutine dumps, Java exceptions
+// and thread dumps, and Python tracebacks in logs, and groups the traces
+// with identical stacks.
+package stacktrace
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"devkit/internal/output"
+)
+
+// Frame is one call of a stack
+type Frame struct {
+	Function string `json:"function"`
+	File     string `json:"file,omitempty"`
+	Line     int    `json:"line,omitempty"`
+	Own      bool   `json:"own"`
+}
+
+// Trace is the stack of one goroutine, thread or exception, innermost
+// frame first
+type Trace struct {
+	language  string
+	id        string
+	state     string
+	wait      string
+	message   string
+	frames    []Frame
+	createdBy *Frame
+}
+
+// Group is a set of traces with the same stack, state and message
+type Group struct {
+	Language  string   `json:"language"`
+	Count     int      `json:"count"`
+	IDs       []string `json:"ids"`
+	State     string   `json:"state,omitempty"`
+	Wait      string   `json:"wait,omitempty"`
+	Message   string   `json:"message,omitempty"`
+	Frames    []Frame  `json:"frames"`
+	CreatedBy *Frame   `json:"created_by,omitempty"`
+}
+
+var (
+	goroutineHeader = regexp.MustCompile(`^goroutine (\d+)(?: gp=\S+ m=\S+(?: mp=\S+)?)? \[([^\]]*)\]:$`)
+	goPanicLine     = regexp.MustCompile(`^(panic|fatal error): `)
+	goFileLine      = regexp.MustCompile(`^\s+(\S.*?):(\d+)(?: \+0x[0-9a-f]+)?(?: fp=\S+ sp=\S+ pc=\S+)?$`)
+	goFuncLine      = regexp.MustCompile(`^\S.*\(.*\)$`)
+	goWaitPart      = regexp.MustCompile(`^\d+ minutes?$`)
+
+	javaException  = regexp.MustCompile(`^(?:Exception in thread "[^"]*" |Caused by: )?((?:[a-zA-Z_$][\w$]*\.)+[\w$]*(?:Exception|Error|Throwable)[\w$]*)(?::\s*(.*))?$`)
+	javaThread     = regexp.MustCompile(`^"([^"]+)"`)
+	javaState      = regexp.MustCompile(`^\s+java\.lang\.Thread\.State: (\S+)`)
+	javaFrame      = regexp.MustCompile(`^\s+at ([^(\s]+)\((.*)\)$`)
+	pythonStart    = regexp.MustCompile(`^Traceback \(most recent call last\):$`)
+	pythonFrame    = regexp.MustCompile(`^\s+File "(.+)", line (\d+), in (.+)$`)
+	goModuleHeader = regexp.MustCompile(`(?m)^module\s+"?([^"\s]+)"?`)
+)
+
+// ModulePath returns the module path declared in the go.mod of dir, or ""
+func ModulePath(dir string) string {
+	data, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		return ""
+	}
+	if m := goModuleHeader.FindSubmatch(data); m != nil {
+		return string(m[1])
+	}
+	return ""
+}
+
+// Parse reads the traces of every supported language from a log; lines
+// around the traces are ignored
+func Parse(r io.Reader) ([]*Trace, error) {
+	var traces []*Trace
+	var current *Trace
+	goMessage := ""
+
+	finish := func() {
+		if current == nil {
+			return
+		}
+		if current.language == "python" {
+			for i, j := 0, len(current.frames)-1; i < j; i, j = i+1, j-1 {
+				current.frames[i], current.frames[j] = current.frames[j], current.frames[i]
+			}
+		}
+		if len(current.frames) > 0 || current.message != "" {
+			traces = append(traces, current)
+		}
+		current = nil
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+
+		// Go
+		if m := goroutineHeader.FindStringSubmatch(line); m != nil {
+			finish()
+			state, wait := splitGoroutineState(m[2])
+			current = &Trace{language: "go", id: m[1], state: state, wait: wait, message: goMessage}
+			goMessage = ""
+			continue
+		}
+		if goPanicLine.MatchString(line) {
+			finish()
+			goMessage = strings.TrimSuffix(line, " [recovered]")
+			continue
+		}
+		if current != nil && current.language == "go" {
+			switch {
+			case strings.TrimSpace(line) == "":
+				finish()
+			case strings.HasPrefix(line, "created by "):
+				name, _, _ := strings.Cut(strings.TrimPrefix(line, "created by "), " in goroutine ")
+				current.createdBy = &Frame{Function: name}
+			case goFileLine.MatchString(line) && (line[0] == '\t' || line[0] == ' '):
+				m := goFileLine.FindStringSubmatch(line)
+				frame := current.createdBy
+				if frame == nil && len(current.frames) > 0 {
+					frame = &current.frames[len(current.frames)-1]
+				}
+				if frame != nil {
+					frame.File = m[1]
+					frame.Line, _ = strconv.Atoi(m[2])
+				}
+			case line == "...additional frames elided...":
+				current.frames = append(current.frames, Frame{Function: "..."})
+			case goFuncLine.MatchString(line):
+				// The arguments follow the last parenthesis: (*T).Method(0x1, ...)
+				current.frames = append(current.frames, Frame{Function: line[:strings.LastIndex(line, "(")]})
+			default:
+				finish()
+			}
+			continue
+		}
+
+		// Python
+		if pythonStart.MatchString(line) {
+			finish()
+			current = &Trace{language: "python"}
+			continue
+		}
+		if current != nil && current.language == "python" {
+			if m := pythonFrame.FindStringSubmatch(line); m != nil {
+				n, _ := strconv.Atoi(m[2])
+				current.frames = append(current.frames, Frame{Function: m[3], File: m[1], Line: n})
+			} else if line != "" && line[0] != ' ' && line[0] != '\t' {
+				current.message = line
+				finish()
+			}
+			continue
+		}
+
+		// Java
+		if m := javaFrame.FindStringSubmatch(line); m != nil && current != nil && current.language == "java" {
+			frame := Frame{Function: m[1]}
+			if file, lineNo, ok := strings.Cut(m[2], ":"); ok {
+				frame.File = file
+				frame.Line, _ = strconv.Atoi(lineNo)
+			} else {
+				frame.File = m[2]
+			}
+			current.frames = append(current.frames, frame)
+			continue
+		}
+		if m := javaException.FindStringSubmatch(line); m != nil {
+			finish()
+			message := m[1]
+			if strings.HasPrefix(line, "Caused by: ") {
+				message = "Caused by: " + message
+			}
+			if m[2] != "" {
+				message += ": " + m[2]
+			}
+			current = &Trace{language: "java", message: message}
+			if name, _, ok := strings.Cut(strings.TrimPrefix(line, `Exception in thread "`), `"`); ok && strings.HasPrefix(line, "Exception in thread") {
+				current.id = name
+			}
+			continue
+		}
+		if m := javaThread.FindStringSubmatch(line); m != nil {
+			finish()
+			current = &Trace{language: "java", id: m[1]}
+			continue
+		}
+		if current != nil && current.language == "java" {
+			if m := javaState.FindStringSubmatch(line); m != nil {
+				current.state = m[1]
+			} else if !javaContinuation(line) {
+				finish()
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read error: %w", err)
+	}
+	finish()
+	return traces, nil
+}
+
+// javaContinuation reports whether a line that is not a frame still
+// belongs to a Java trace: "... 5 more", and the indented monitor lines of
+// thread dumps such as "- locked <0x...>"
+func javaContinuation(line string) bool {
+	return strings.TrimSpace(line) != "" && (line[0] == ' ' || line[0] == '\t')
+}
+
+// splitGoroutineState separates the wait time from a goroutine state such
+// as "chan receive, 15 minutes, locked to thread"
+func splitGoroutineState(state string) (string, string) {
+	var parts []string
+	wait := ""
+	for _, part := range strings.Split(state, ", ") {
+		if goWaitPart.MatchString(part) {
+			wait = part
+			continue
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, ", "), wait
+}
+
+// MarkOwn flags the frames that belong to one of the module paths
+func MarkOwn(traces []*Trace, modulePaths []string) {
+	for _, t := range traces {
+		for i := range t.frames {
+			t.frames[i].Own = ownFrame(t.language, t.frames[i], modulePaths)
+		}
+		if t.createdBy != nil {
+			t.createdBy.Own = ownFrame(t.language, *t.createdBy, modulePaths)
+		}
+	}
+}
+
+// ownFrame reports whether a frame belongs to one of the module paths; the
+// functions of a Go main package are named main.* whatever the module
+func ownFrame(language string, frame Frame, modulePaths []string) bool {
+	if language == "go" && len(modulePaths) > 0 && strings.HasPrefix(frame.Function, "main.") {
+		return true
+	}
+	for _, prefix := range modulePaths {
+		if prefix == "" {
+			continue
+		}
+		if strings.HasPrefix(frame.Function, prefix) || strings.Contains(filepath.ToSlash(frame.File), prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// GroupTraces groups traces with the same language, state, message and
+// frames; groups with a message (panics, exceptions) come first, then the
+// largest
+func GroupTraces(traces []*Trace) []Group {
+	var groups []Group
+	index := make(map[string]int)
+	for _, t := range traces {
+		var key strings.Builder
+		fmt.Fprintf(&key, "%s\x00%s\x00%s", t.language, t.state, t.message)
+		for _, f := range t.frames {
+			fmt.Fprintf(&key, "\x00%s:%s:%d", f.Function, f.File, f.Line)
+		}
+		if t.createdBy != nil {
+			fmt.Fprintf(&key, "\x00created by %s:%s:%d", t.createdBy.Function, t.createdBy.File, t.createdBy.Line)
+		}
+
+		i, ok := index[key.String()]
+		if !ok {
+			i = len(groups)
+			index[key.String()] = i
+			frames := t.frames
+			if frames == nil {
+				frames = []Frame{}
+			}
+			groups = append(groups, Group{
+				Language:  t.language,
+				IDs:       []string{},
+				State:     t.state,
+				Message:   t.message,
+				Frames:    frames,
+				CreatedBy: t.createdBy,
+			})
+		}
+		g := &groups[i]
+		g.Count++
+		if t.id != "" {
+			g.IDs = append(g.IDs, t.id)
+		}
+		if longerGoWait(t.wait, g.Wait) {
+			g.Wait = t.wait
+		}
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		a, b := groups[i], groups[j]
+		if (a.Message != "") != (b.Message != "") {
+			return a.Message != ""
+		}
+		return a.Count > b.Count
+	})
+	return groups
+}
+
+// longerGoWait reports whether wait a ("15 minutes") is longer than b
+func longerGoWait(a, b string) bool {
+	minutes := func(s string) int {
+		n, _ := strconv.Atoi(strings.Fields(s + " 0")[0])
+		return n
+	}
+	return a != "" && (b == "" || minutes(a) > minutes(b))
+}
+
+// OwnFrames keeps the frames of your own code and the innermost frame
+func OwnFrames(frames []Frame) []Frame {
+	kept := []Frame{}
+	hidden := 0
+	for i, f := range frames {
+		if i == 0 || f.Own {
+			if hidden > 0 {
+				kept = append(kept, Frame{Function: fmt.Sprintf("... %d frames", hidden)})
+				hidden = 0
+			}
+			kept = append(kept, f)
+			continue
+		}
+		hidden++
+	}
+	if hidden > 0 {
+		kept = append(kept, Frame{Function: fmt.Sprintf("... %d frames", hidden)})
+	}
+	return kept
+}
+
+// shortFile trims the module cache and GOROOT prefixes from a path
+func shortFile(file string) string {
+	slashed := filepath.ToSlash(file)
+	for _, marker := range []string{"/pkg/mod/", "/go/src/", "/site-packages/"} {
+		if i := strings.LastIndex(slashed, marker); i >= 0 {
+			return slashed[i+len(marker):]
+		}
+	}
+	return file
+}
+
+// PrintGroup prints one group: its count, state and ids, the message and
+// the frames with their files
+func PrintGroup(g Group) {
+	noun := map[string]string{"go": "goroutine", "java": "thread", "python": "traceback"}[g.Language]
+	if g.Language == "java" && g.Message != "" {
+		noun = "exception"
+	}
+	if g.Count != 1 {
+		noun += "s"
+	}
+	header := fmt.Sprintf("%d %s", g.Count, noun)
+	if g.State != "" {
+		header += " [" + g.State
+		if g.Wait != "" {
+			header += ", up to " + g.Wait
+		}
+		header += "]"
+	}
+	fmt.Print(output.Accent(header))
+	if len(g.IDs) > 0 {
+		ids := g.IDs
+		more := ""
+		if len(ids) > 8 {
+			more = fmt.Sprintf(" and %d more", len(ids)-8)
+			ids = ids[:8]
+		}
+		fmt.Print(output.Muted(": " + strings.Join(ids, ", ") + more))
+	}
+	fmt.Println()
+	if g.Message != "" {
+		fmt.Println("  " + output.Failure(g.Message))
+	}
+
+	frames := g.Frames
+	if g.CreatedBy != nil {
+		created := *g.CreatedBy
+		created.Function = "created by " + created.Function
+		frames = append(append([]Frame{}, frames...), created)
+	}
+	width := 0
+	for _, f := range frames {
+		width = max(width, len(f.Function))
+	}
+	width = min(width, 60)
+	for _, f := range frames {
+		location := ""
+		if f.File != "" {
+			location = shortFile(f.File)
+			if f.Line > 0 {
+				location += ":" + strconv.Itoa(f.Line)
+			}
+		}
+		name := fmt.Sprintf("%-*s", width, f.Function)
+		switch {
+		case strings.HasPrefix(f.Function, "..."):
+			fmt.Println("  " + output.Muted(f.Function))
+		case location == "" && f.Own:
+			fmt.Println("  " + output.Accent(f.Function))
+		case location == "":
+			fmt.Println("  " + f.Function)
+		case f.Own:
+			fmt.Printf("  %s  %s\n", output.Accent(name), location)
+		default:
+			fmt.Printf("  %s  %s\n", name, output.Muted(location))
+		}
+	}
+}
@@ -0,0 +1,366 @@
+package undo
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"devkit/internal/config"
+)
+
+// Entry kinds
+const (
+	KindModify = "modify" // file content was changed; Backup holds the original
+	KindCreate = "create" // file did not exist before; undo removes it
+	KindRename = "rename" // Path was renamed to NewPath
+	KindDelete = "delete" // file was deleted; Backup holds the original
+)
+
+// KeyKeep is the configuration key for the number of operations kept
+const KeyKeep = "undo.keep"
+
+// Entry is a single recorded mutation
+type Entry struct {
+	Kind      string      `json:"kind"`
+	Path      string      `json:"path"`
+	NewPath   string      `json:"new_path,omitempty"`
+	Backup    string      `json:"backup,omitempty"`
+	Mode      os.FileMode `json:"mode,omitempty"`
+	AfterHash string      `json:"after_hash,omitempty"`
+}
+
+// Operation is a journaled command invocation that can be reverted
+type Operation struct {
+	ID        string    `json:"id"`
+	Command   string    `json:"command"`
+	CreatedAt time.Time `json:"created_at"`
+	Entries   []Entry   `json:"entries"`
+
+	dir string
+}
+
+// Dir returns the journal directory (~/.devkit/undo)
+func Dir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate home directory: %w", err)
+	}
+	return filepath.Join(home, ".devkit", "undo"), nil
+}
+
+// Begin starts journaling a new operation for command
+func Begin(command string) (*Operation, error) {
+	root, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+
+	suffix := make([]byte, 2)
+	rand.Read(suffix)
+	now := time.Now()
+	op := &Operation{
+		ID:        now.Format("20060102-150405") + "-" + hex.EncodeToString(suffix),
+		Command:   command,
+		CreatedAt: now,
+	}
+	op.dir = filepath.Join(root, op.ID)
+
+	if err := os.MkdirAll(filepath.Join(op.dir, "files"), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create undo journal: %w", err)
+	}
+	return op, nil
+}
+
+// BackupFile saves the current content of path before it is modified.
+// A path that does not exist yet is recorded as created.
+func (op *Operation) BackupFile(path string) error {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+
+	info, err := os.Stat(abs)
+	if os.IsNotExist(err) {
+		op.Entries = append(op.Entries, Entry{Kind: KindCreate, Path: abs})
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to back up %s: %w", path, err)
+	}
+
+	backup, err := op.copyToJournal(abs)
+	if err != nil {
+		return err
+	}
+	op.Entries = append(op.Entries, Entry{Kind: KindModify, Path: abs, Backup: backup, Mode: info.Mode().Perm()})
+	return nil
+}
+
+// RecordRename records that oldPath was renamed to newPath
+func (op *Operation) RecordRename(oldPath, newPath string) error {
+	oldAbs, err := filepath.Abs(oldPath)
+	if err != nil {
+		return err
+	}
+	newAbs, err := filepath.Abs(newPath)
+	if err != nil {
+		return err
+	}
+	op.Entries = append(op.Entries, Entry{Kind: KindRename, Path: oldAbs, NewPath: newAbs})
+	return nil
+}
+
+// BackupDelete saves the content of path before it is deleted
+func (op *Operation) BackupDelete(path string) error {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	info, err := os.Stat(abs)
+	if err != nil {
+		return fmt.Errorf("failed to back up %s: %w", path, err)
+	}
+
+	backup, err := op.copyToJournal(abs)
+	if err != nil {
+		return err
+	}
+	op.Entries = append(op.Entries, Entry{Kind: KindDelete, Path: abs, Backup: backup, Mode: info.Mode().Perm()})
+	return nil
+}
+
+// Commit writes the journal. Operations without entries are discarded.
+func (op *Operation) Commit() error {
+	if len(op.Entries) == 0 {
+		return op.Discard()
+	}
+
+	// Remember the content written by the command so that undo can detect
+	// files that were changed again afterwards
+	for i, entry := range op.Entries {
+		if entry.Kind == KindModify || entry.Kind == KindCreate {
+			op.Entries[i].AfterHash, _ = hashFile(entry.Path)
+		}
+	}
+
+	data, err := json.MarshalIndent(op, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode undo journal: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(op.dir, "journal.json"), data, 0600); err != nil {
+		return fmt.Errorf("failed to write undo journal: %w", err)
+	}
+
+	prune()
+	return nil
+}
+
+// Discard removes the journal of an operation that did not happen
+func (op *Operation) Discard() error {
+	return os.RemoveAll(op.dir)
+}
+
+// List returns all journaled operations, newest first
+func List() ([]*Operation, error) {
+	root, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+
+	dirs, err := os.ReadDir(root)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read undo journal: %w", err)
+	}
+
+	var ops []*Operation
+	for _, d := range dirs {
+		if !d.IsDir() {
+			continue
+		}
+		op, err := Load(d.Name())
+		if err != nil {
+			continue
+		}
+		ops = append(ops, op)
+	}
+
+	sort.Slice(ops, func(i, j int) bool {
+		return ops[i].CreatedAt.After(ops[j].CreatedAt)
+	})
+	return ops, nil
+}
+
+// Load reads the journal of an operation
+func Load(id string) (*Operation, error) {
+	root, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+	if id == "" || strings.ContainsAny(id, `/\`) || id == "." || id == ".." {
+		return nil, fmt.Errorf("invalid operation id: %s", id)
+	}
+
+	dir := filepath.Join(root, id)
+	data, err := os.ReadFile(filepath.Join(dir, "journal.json"))
+	if err != nil {
+		return nil, fmt.Errorf("operation not found: %s", id)
+	}
+
+	var op Operation
+	if err := json.Unmarshal(data, &op); err != nil {
+		return nil, fmt.Errorf("corrupt undo journal %s: %w", id, err)
+	}
+	op.dir = dir
+	return &op, nil
+}
+
+// Last returns the most recent operation
+func Last() (*Operation, error) {
+	ops, err := List()
+	if err != nil {
+		return nil, err
+	}
+	if len(ops) == 0 {
+		return nil, fmt.Errorf("nothing to undo")
+	}
+	return ops[0], nil
+}
+
+// Check returns the problems that would prevent a clean revert: files
+// changed since the operation, or paths that are now taken
+func (op *Operation) Check() []string {
+	var problems []string
+	for _, entry := range op.Entries {
+		switch entry.Kind {
+		case KindModify, KindCreate:
+			if entry.AfterHash == "" {
+				continue
+			}
+			hash, err := hashFile(entry.Path)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("%s: no longer exists", entry.Path))
+			} else if hash != entry.AfterHash {
+				problems = append(problems, fmt.Sprintf("%s: changed since the operation", entry.Path))
+			}
+		case KindRename:
+			if _, err := os.Stat(entry.NewPath); err != nil {
+				problems = append(problems, fmt.Sprintf("%s: no longer exists", entry.NewPath))
+			}
+			if _, err := os.Stat(entry.Path); err == nil {
+				problems = append(problems, fmt.Sprintf("%s: already exists", entry.Path))
+			}
+		case KindDelete:
+			if _, err := os.Stat(entry.Path); err == nil {
+				problems = append(problems, fmt.Sprintf("%s: already exists", entry.Path))
+			}
+		}
+	}
+	return problems
+}
+
+// Revert undoes the recorded entries in reverse order and removes the
+// journal. It returns the paths that were restored.
+func (op *Operation) Revert() ([]string, error) {
+	var restored []string
+	for i := len(op.Entries) - 1; i >= 0; i-- {
+		entry := op.Entries[i]
+		switch entry.Kind {
+		case KindModify, KindDelete:
+			if err := op.restoreFromJournal(entry); err != nil {
+				return restored, err
+			}
+		case KindCreate:
+			if err := os.Remove(entry.Path); err != nil && !os.IsNotExist(err) {
+				return restored, fmt.Errorf("failed to remove %s: %w", entry.Path, err)
+			}
+		case KindRename:
+			if err := os.Rename(entry.NewPath, entry.Path); err != nil {
+				return restored, fmt.Errorf("failed to rename %s: %w", entry.NewPath, err)
+			}
+		}
+		restored = append(restored, entry.Path)
+	}
+
+	if err := op.Discard(); err != nil {
+		return restored, fmt.Errorf("failed to remove undo journal: %w", err)
+	}
+	return restored, nil
+}
+
+func (op *Operation) copyToJournal(path string) (string, error) {
+	name := fmt.Sprintf("%d", len(op.Entries))
+	src, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to back up %s: %w", path, err)
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(filepath.Join(op.dir, "files", name), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return "", fmt.Errorf("failed to back up %s: %w", path, err)
+	}
+	defer dst.Close()
+
+	if _, err := io.Copy(dst, src); err != nil {
+		return "", fmt.Errorf("failed to back up %s: %w", path, err)
+	}
+	return name, nil
+}
+
+func (op *Operation) restoreFromJournal(entry Entry) error {
+	data, err := os.ReadFile(filepath.Join(op.dir, "files", entry.Backup))
+	if err != nil {
+		return fmt.Errorf("backup of %s is missing: %w", entry.Path, err)
+	}
+	mode := entry.Mode
+	if mode == 0 {
+		mode = 0644
+	}
+	if err := os.MkdirAll(filepath.Dir(entry.Path), 0755); err != nil {
+		return fmt.Errorf("failed to restore %s: %w", entry.Path, err)
+	}
+	if err := os.WriteFile(entry.Path, data, mode); err != nil {
+		return fmt.Errorf("failed to restore %s: %w", entry.Path, err)
+	}
+	return nil
+}
+
+// prune keeps only the newest operations (undo.keep, default 20)
+func prune() {
+	keep := config.GetInt(KeyKeep)
+	if keep <= 0 {
+		keep = 20
+	}
+	ops, err := List()
+	if err != nil {
+		return
+	}
+	for i := keep; i < len(ops); i++ {
+		ops[i].Discard()
+	}
+}
+
+func hashFile(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,392 @@
+package workspace
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FileName is the project configuration file marking a workspace root
+const FileName = ".devkit.yaml"
+
+// Settings are the per-project defaults stored under the workspace key of
+// the project's .devkit.yaml
+type Settings struct {
+	Name string `yaml:"name" json:"name"`
+	// Path is the default root for file commands
+	Path string `yaml:"path,omitempty" json:"path,omitempty"`
+	// EnvFile is the default file for dev env commands
+	EnvFile string `yaml:"env_file,omitempty" json:"env_file,omitempty"`
+	// Ignore patterns are excluded by every file command
+	Ignore []string `yaml:"ignore,omitempty" json:"ignore,omitempty"`
+	// IgnoreProfiles name the matcher profiles excluded by every file command
+	IgnoreProfiles []string `yaml:"ignore_profiles,omitempty" json:"ignore_profiles,omitempty"`
+	HTTP           HTTP     `yaml:"http,omitempty" json:"http,omitempty"`
+}
+
+// HTTP holds the defaults for net http requests
+type HTTP struct {
+	// BaseURL is prepended to request URLs that have no scheme
+	BaseURL string `yaml:"base_url,omitempty" json:"base_url,omitempty"`
+	// Headers are sent with every request, before any --header flags
+	Headers []string `yaml:"headers,omitempty" json:"headers,omitempty"`
+}
+
+// Workspace is a detected or selected project
+type Workspace struct {
+	Settings
+	Root string `json:"root"`
+	File string `json:"file"`
+	// Active is set when the workspace was selected with workspace use
+	// rather than detected from the current directory
+	Active bool `json:"active"`
+}
+
+// registry records the known workspaces and the active one
+type registry struct {
+	Active     string            `json:"active,omitempty"`
+	Workspaces map[string]string `json:"workspaces"`
+}
+
+var current *Workspace
+
+// Detect finds the workspace for the current directory: the nearest parent
+// directory with a .devkit.yaml (the one in the home directory is the
+// global config and is not a workspace), otherwise the workspace selected
+// with workspace use. It returns nil when there is none.
+func Detect() (*Workspace, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+
+	if root, ok := FindRoot(cwd); ok {
+		ws, err := Load(root)
+		if err != nil {
+			return nil, err
+		}
+		current = ws
+		return ws, nil
+	}
+
+	reg, err := loadRegistry()
+	if err != nil {
+		return nil, err
+	}
+	if root, ok := reg.Workspaces[reg.Active]; ok && reg.Active != "" {
+		if ws, err := Load(root); err == nil {
+			ws.Active = true
+			current = ws
+			return ws, nil
+		}
+	}
+	return nil, nil
+}
+
+// Current returns the workspace found by Detect, or nil
+func Current() *Workspace {
+	return current
+}
+
+// FindRoot returns the nearest directory from dir upwards that contains a
+// .devkit.yaml, skipping the home directory
+func FindRoot(dir string) (string, bool) {
+	home, _ := os.UserHomeDir()
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", false
+	}
+	for {
+		if dir != home {
+			if info, err := os.Stat(filepath.Join(dir, FileName)); err == nil && !info.IsDir() {
+				return dir, true
+			}
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+// RepoRoot returns the nearest directory from dir upwards that contains a
+// .git entry, or dir itself when it is not inside a repository
+func RepoRoot(dir string) string {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return dir
+	}
+	for d := dir; ; {
+		if _, err := os.Stat(filepath.Join(d, ".git")); err == nil {
+			return d
+		}
+		parent := filepath.Dir(d)
+		if parent == d {
+			return dir
+		}
+		d = parent
+	}
+}
+
+// Load reads the workspace settings from root/.devkit.yaml
+func Load(root string) (*Workspace, error) {
+	file := filepath.Join(root, FileName)
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read workspace file: %w", err)
+	}
+
+	var doc struct {
+		Workspace Settings `yaml:"workspace"`
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid workspace file %s: %w", file, err)
+	}
+	ws := &Workspace{Settings: doc.Workspace, Root: root, File: file}
+	if ws.Name == "" {
+		ws.Name = filepath.Base(root)
+	}
+	return ws, nil
+}
+
+// Resolve returns p relative to the workspace root, shortened to a path
+// relative to the current directory when possible
+func (w *Workspace) Resolve(p string) string {
+	if p == "" || filepath.IsAbs(p) {
+		return p
+	}
+	abs := filepath.Join(w.Root, p)
+	if cwd, err := os.Getwd(); err == nil {
+		if rel, err := filepath.Rel(cwd, abs); err == nil {
+			return rel
+		}
+	}
+	return abs
+}
+
+// Use makes name the active workspace and reports whether its file was
+// created. Without root, the workspace containing the current directory is
+// named, else a registered workspace with that name is selected, else a new
+// workspace is created at the enclosing repository root.
+func Use(name, root string) (*Workspace, bool, error) {
+	if name == "" || strings.ContainsAny(name, `/\`) {
+		return nil, false, fmt.Errorf("invalid workspace name: %q", name)
+	}
+	reg, err := loadRegistry()
+	if err != nil {
+		return nil, false, err
+	}
+
+	if root == "" {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return nil, false, err
+		}
+		if found, ok := FindRoot(cwd); ok {
+			root = found
+		} else if registered, ok := reg.Workspaces[name]; ok {
+			root = registered
+		} else {
+			root = RepoRoot(cwd)
+		}
+	}
+	if root, err = filepath.Abs(root); err != nil {
+		return nil, false, err
+	}
+
+	created, err := writeName(filepath.Join(root, FileName), name)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// A name refers to one directory; renaming a workspace drops its old
+	// name
+	for other, dir := range reg.Workspaces {
+		if dir == root && other != name {
+			delete(reg.Workspaces, other)
+		}
+	}
+	reg.Workspaces[name] = root
+	reg.Active = name
+	if err := saveRegistry(reg); err != nil {
+		return nil, false, err
+	}
+
+	ws, err := Load(root)
+	if err != nil {
+		return nil, false, err
+	}
+	ws.Active = true
+	return ws, created, nil
+}
+
+// Deactivate clears the active workspace; workspaces are still detected
+// from the current directory
+func Deactivate() error {
+	reg, err := loadRegistry()
+	if err != nil {
+		return err
+	}
+	reg.Active = ""
+	return saveRegistry(reg)
+}
+
+// List returns the registered workspaces sorted by name
+func List() ([]*Workspace, error) {
+	reg, err := loadRegistry()
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(reg.Workspaces))
+	for name := range reg.Workspaces {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var list []*Workspace
+	for _, name := range names {
+		root := reg.Workspaces[name]
+		ws, err := Load(root)
+		if err != nil {
+			// Keep entries whose directory is gone so that they show up
+			ws = &Workspace{Root: root, File: filepath.Join(root, FileName)}
+		}
+		ws.Name = name
+		ws.Active = name == reg.Active
+		list = append(list, ws)
+	}
+	return list, nil
+}
+
+// template is written when a workspace file is created
+const template = `# devkit workspace settings. Any other devkit setting (safety, cache,
+# undo, ...) placed in this file applies to commands run inside the project.
+workspace:
+  name: %s
+  # Default root for file commands
+  # path: src
+  # Default file for dev env commands
+  # env_file: .env
+  # Patterns excluded by every file command
+  # ignore: [node_modules, dist]
+  # http:
+  #   base_url: https://api.example.com
+  #   headers: ["Authorization: Bearer <token>"]
+`
+
+// writeName sets workspace.name in file, creating the file from the
+// template if it does not exist. Comments and other keys are preserved.
+func writeName(file, name string) (bool, error) {
+	data, err := os.ReadFile(file)
+	if os.IsNotExist(err) {
+		if err := os.WriteFile(file, []byte(fmt.Sprintf(template, name)), 0644); err != nil {
+			return false, fmt.Errorf("failed to create workspace file: %w", err)
+		}
+		return true, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read workspace file: %w", err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return false, fmt.Errorf("invalid workspace file %s: %w", file, err)
+	}
+	if len(doc.Content) == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+	top := doc.Content[0]
+	if top.Kind != yaml.MappingNode {
+		return false, fmt.Errorf("invalid workspace file %s: expected a mapping", file)
+	}
+
+	section := mappingValue(top, "workspace")
+	if section == nil || section.Kind != yaml.MappingNode {
+		section = &yaml.Node{Kind: yaml.MappingNode}
+		setMappingValue(top, "workspace", section)
+	}
+	setMappingValue(section, "name", &yaml.Node{Kind: yaml.ScalarNode, Value: name})
+
+	var out bytes.Buffer
+	encoder := yaml.NewEncoder(&out)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&doc); err != nil {
+		return false, err
+	}
+	if err := os.WriteFile(file, out.Bytes(), 0644); err != nil {
+		return false, fmt.Errorf("failed to write workspace file: %w", err)
+	}
+	return false, nil
+}
+
+func mappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func setMappingValue(mapping *yaml.Node, key string, value *yaml.Node) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			mapping.Content[i+1] = value
+			return
+		}
+	}
+	mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, value)
+}
+
+func registryPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate home directory: %w", err)
+	}
+	return filepath.Join(home, ".devkit", "workspaces.json"), nil
+}
+
+func loadRegistry() (*registry, error) {
+	reg := &registry{Workspaces: make(map[string]string)}
+	path, err := registryPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return reg, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, reg); err != nil {
+		return nil, fmt.Errorf("invalid workspace registry %s: %w", path, err)
+	}
+	if reg.Workspaces == nil {
+		reg.Workspaces = make(map[string]string)
+	}
+	return reg, nil
+}
+
+func saveRegistry(reg *registry) error {
+	path, err := registryPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(reg, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
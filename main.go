@@ -2,11 +2,12 @@ package main
 
 import (
 	"devkit/cmd"
+	"devkit/internal/errors"
 	"os"
 )
 
 func main() {
 	if err := cmd.Execute(); err != nil {
-		os.Exit(1)
+		os.Exit(errors.ExitCode(err))
 	}
 }